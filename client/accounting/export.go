@@ -0,0 +1,94 @@
+package accounting
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/indexer"
+)
+
+var (
+	reportCSVHeader = []string{
+		"wallet", "subaccount_id", "market_id", "method", "fills", "volume", "realized_pnl", "fees_paid", "fee_rebates",
+		"funding_paid", "funding_received", "net_pnl", "position", "average_entry_price",
+	}
+	closedLotCSVHeader = []string{
+		"subaccount_id", "market_id", "open_height", "open_time", "close_height", "close_time", "quantity",
+		"open_price", "close_price", "realized_pnl",
+	}
+)
+
+// Load computes the reports from the fills of the store matching the filter. The fills before the From time of the
+// filter are not matched, so the lots they opened are missing from the reports. The limit and order of the filter
+// are ignored
+func Load(ctx context.Context, store indexer.Store, filter indexer.FillFilter, method LotMatching) ([]Report, error) {
+	filter.Limit = 0
+	filter.NewestFirst = false
+	fills, err := store.Fills(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the fills")
+	}
+	return Compute(fills, method)
+}
+
+// WriteJSON writes the reports as an indented JSON array, with the decimals as strings
+func WriteJSON(w io.Writer, reports []Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reports)
+}
+
+// WritePeriodJSON writes the period report as indented JSON, with the decimals as strings
+func WritePeriodJSON(w io.Writer, period *PeriodReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(period)
+}
+
+// WriteCSV writes one line per report, without the lots
+func WriteCSV(w io.Writer, reports []Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reportCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		err := writer.Write([]string{
+			r.Wallet, r.SubaccountId, r.MarketId, string(r.Method), strconv.Itoa(r.Fills), r.Volume.String(),
+			r.RealizedPnL.String(), r.FeesPaid.String(), r.FeeRebates.String(), r.FundingPaid.String(),
+			r.FundingReceived.String(), r.NetPnL.String(), r.Position.String(), r.AverageEntryPrice().String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteClosedLotsCSV writes one line per closed lot of the reports, with the subaccount of the closing fill, with the times in RFC 3339
+func WriteClosedLotsCSV(w io.Writer, reports []Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(closedLotCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		for _, lot := range r.Closed {
+			err := writer.Write([]string{
+				lot.SubaccountId, lot.MarketId, strconv.FormatInt(lot.OpenHeight, 10), lot.OpenTime.Format(time.RFC3339),
+				strconv.FormatInt(lot.CloseHeight, 10), lot.CloseTime.Format(time.RFC3339), lot.Quantity.String(),
+				lot.OpenPrice.String(), lot.ClosePrice.String(), lot.RealizedPnL.String(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,211 @@
+package accounting
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/InjectiveLabs/sdk-go/client/indexer"
+)
+
+// TransferTotal is the total amount moved by the transfers of a kind in a denom
+type TransferTotal struct {
+	Kind   TransferKind `json:"kind"`
+	Denom  string       `json:"denom"`
+	Amount sdk.Int      `json:"amount"`
+	Fee    sdk.Int      `json:"fee"`
+	Count  int          `json:"count"`
+}
+
+// PeriodReport is the activity of a wallet from From, inclusive, to To, exclusive: the lots closed by its trades, the
+// fees and funding of its fills, and its classified transfers. The trading amounts are in the quote denom of the
+// markets
+type PeriodReport struct {
+	Wallet    string           `json:"wallet"`
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Method    LotMatching      `json:"method"`
+	Disposals []ClosedLot      `json:"disposals"`
+	Fills     []indexer.Fill   `json:"fills"`
+	Funding   []FundingPayment `json:"funding_payments"`
+	Transfers []Transfer       `json:"transfers"`
+
+	RealizedPnL     sdk.Dec `json:"realized_pnl"`
+	FeesPaid        sdk.Dec `json:"fees_paid"`
+	FeeRebates      sdk.Dec `json:"fee_rebates"`
+	FundingPaid     sdk.Dec `json:"funding_paid"`
+	FundingReceived sdk.Dec `json:"funding_received"`
+	NetPnL          sdk.Dec `json:"net_pnl"`
+	// TransferTotals are sorted by kind and denom
+	TransferTotals []TransferTotal `json:"transfer_totals"`
+}
+
+// NewPeriodReport builds the period report of a wallet. The fills must be the whole history of the wallet in
+// execution order, so the lots closed in the period are matched with the lots opened before it. The fills and
+// transfers of the other wallets are ignored
+func NewPeriodReport(wallet string, from, to time.Time, fills []indexer.Fill, transfers []Transfer, method LotMatching) (*PeriodReport, error) {
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	var walletFills []indexer.Fill
+	for _, fill := range fills {
+		owner, err := WalletOf(fill.SubaccountId)
+		if err != nil {
+			return nil, err
+		}
+		if owner == wallet {
+			walletFills = append(walletFills, fill)
+		}
+	}
+	reports, err := ComputeByWallet(walletFills, method)
+	if err != nil {
+		return nil, err
+	}
+
+	period := &PeriodReport{
+		Wallet:          wallet,
+		From:            from,
+		To:              to,
+		Method:          method,
+		RealizedPnL:     sdk.ZeroDec(),
+		FeesPaid:        sdk.ZeroDec(),
+		FeeRebates:      sdk.ZeroDec(),
+		FundingPaid:     sdk.ZeroDec(),
+		FundingReceived: sdk.ZeroDec(),
+	}
+	for _, report := range reports {
+		for _, lot := range report.Closed {
+			if inPeriod(lot.CloseTime) {
+				period.Disposals = append(period.Disposals, lot)
+				period.RealizedPnL = period.RealizedPnL.Add(lot.RealizedPnL)
+			}
+		}
+		for _, payment := range report.Funding {
+			if !inPeriod(payment.Time) {
+				continue
+			}
+			period.Funding = append(period.Funding, payment)
+			if payment.Amount.IsPositive() {
+				period.FundingPaid = period.FundingPaid.Add(payment.Amount)
+			} else {
+				period.FundingReceived = period.FundingReceived.Sub(payment.Amount)
+			}
+		}
+	}
+	for _, fill := range walletFills {
+		if !inPeriod(fill.Time) {
+			continue
+		}
+		period.Fills = append(period.Fills, fill)
+		if fill.Fee.IsNegative() {
+			period.FeeRebates = period.FeeRebates.Sub(fill.Fee)
+		} else {
+			period.FeesPaid = period.FeesPaid.Add(fill.Fee)
+		}
+	}
+	period.NetPnL = period.RealizedPnL.Sub(period.FeesPaid).Add(period.FeeRebates).Sub(period.FundingPaid).Add(period.FundingReceived)
+
+	type totalKey struct {
+		kind  TransferKind
+		denom string
+	}
+	totals := make(map[totalKey]*TransferTotal)
+	for _, transfer := range transfers {
+		if transfer.Wallet != wallet || !inPeriod(transfer.Time) {
+			continue
+		}
+		period.Transfers = append(period.Transfers, transfer)
+		key := totalKey{kind: transfer.Kind, denom: transfer.Denom}
+		total, found := totals[key]
+		if !found {
+			total = &TransferTotal{Kind: transfer.Kind, Denom: transfer.Denom, Amount: sdk.ZeroInt(), Fee: sdk.ZeroInt()}
+			totals[key] = total
+		}
+		total.Amount = total.Amount.Add(transfer.Amount)
+		if !transfer.Fee.IsNil() {
+			total.Fee = total.Fee.Add(transfer.Fee)
+		}
+		total.Count++
+	}
+	for _, total := range totals {
+		period.TransferTotals = append(period.TransferTotals, *total)
+	}
+	sort.Slice(period.TransferTotals, func(i, j int) bool {
+		if period.TransferTotals[i].Kind != period.TransferTotals[j].Kind {
+			return period.TransferTotals[i].Kind < period.TransferTotals[j].Kind
+		}
+		return period.TransferTotals[i].Denom < period.TransferTotals[j].Denom
+	})
+	return period, nil
+}
+
+var ledgerCSVHeader = []string{
+	"time", "height", "tx_hash", "category", "kind", "market_id", "subaccount_id", "denom", "quantity", "cost_basis",
+	"proceeds", "realized_pnl", "fee", "counterparty",
+}
+
+type ledgerRow struct {
+	time   time.Time
+	height int64
+	values []string
+}
+
+// WriteLedgerCSV writes the period report as one line per disposal, fill fee, funding payment and transfer, in time
+// order. The trading lines have the market id and the amounts in its quote denom, the transfer lines have the denom
+// and the amount in its base units
+func WriteLedgerCSV(w io.Writer, period *PeriodReport) error {
+	var rows []ledgerRow
+	add := func(t time.Time, height int64, txHash, category, kind, marketId, subaccountId, denom, quantity, costBasis, proceeds, realizedPnL, fee, counterparty string) {
+		rows = append(rows, ledgerRow{time: t, height: height, values: []string{
+			t.Format(time.RFC3339), strconv.FormatInt(height, 10), txHash, category, kind, marketId, subaccountId, denom,
+			quantity, costBasis, proceeds, realizedPnL, fee, counterparty,
+		}})
+	}
+
+	for _, lot := range period.Disposals {
+		add(lot.CloseTime, lot.CloseHeight, "", CategoryTrading, "disposal", lot.MarketId, lot.SubaccountId, "",
+			lot.Quantity.String(), lot.CostBasis().String(), lot.Proceeds().String(), lot.RealizedPnL.String(), "", "")
+	}
+	for _, fill := range period.Fills {
+		if fill.Fee.IsZero() {
+			continue
+		}
+		add(fill.Time, fill.Height, "", CategoryTrading, "fee", fill.MarketId, fill.SubaccountId, "", "", "", "",
+			fill.Fee.Neg().String(), fill.Fee.String(), "")
+	}
+	for _, payment := range period.Funding {
+		add(payment.Time, payment.Height, "", CategoryTrading, "funding", payment.MarketId, payment.SubaccountId, "", "", "", "",
+			payment.Amount.Neg().String(), "", "")
+	}
+	for _, transfer := range period.Transfers {
+		fee := ""
+		if !transfer.Fee.IsNil() && !transfer.Fee.IsZero() {
+			fee = transfer.Fee.String()
+		}
+		add(transfer.Time, transfer.Height, transfer.TxHash, transfer.Kind.Category(), string(transfer.Kind), "",
+			transfer.SubaccountId, transfer.Denom, transfer.Amount.String(), "", "", "", fee, transfer.Counterparty)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].time.Equal(rows[j].time) {
+			return rows[i].time.Before(rows[j].time)
+		}
+		return rows[i].height < rows[j].height
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(ledgerCSVHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(row.values); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
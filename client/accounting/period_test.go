@@ -0,0 +1,150 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/indexer"
+)
+
+const secondSubaccountId = "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000001"
+
+func spotFill(height int64, subaccountId string, isBuy bool, price, quantity, fee string) indexer.Fill {
+	fill := perpFill(height, isBuy, price, quantity, fee, "0")
+	fill.IsDerivative = false
+	fill.MarketId = spotMarketId
+	fill.SubaccountId = subaccountId
+	fill.CumulativeFunding = sdk.Dec{}
+	return fill
+}
+
+func hour(height int64) time.Time {
+	return testTime.Add(time.Duration(height) * time.Hour)
+}
+
+func TestComputeByWalletMatchesTheSubaccountsTogether(t *testing.T) {
+	fills := []indexer.Fill{
+		spotFill(1, testSubaccountId, true, "10", "2", "0.1"),
+		spotFill(2, secondSubaccountId, true, "12", "1", "0.1"),
+		spotFill(3, secondSubaccountId, false, "15", "2", "0.1"),
+		spotFill(4, otherSubaccountId, true, "1", "1", "0"),
+	}
+
+	reports, err := ComputeByWallet(fills, FIFO)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 2)
+	assert.Equal(t, otherWallet, reports[0].Wallet)
+	report := reports[1]
+	assert.Equal(t, testWallet, report.Wallet)
+	assert.Empty(t, report.SubaccountId)
+	assert.Equal(t, 3, report.Fills)
+	assert.Equal(t, dec("10"), report.RealizedPnL)
+	assert.Equal(t, dec("1"), report.Position)
+	assert.Equal(t, dec("12"), report.AverageEntryPrice())
+	assert.Equal(t, secondSubaccountId, report.Closed[0].SubaccountId)
+	assert.Equal(t, spotMarketId, report.Closed[0].MarketId)
+
+	// the subaccount reports match the lots of each subaccount
+	reports, err = Compute(fills, FIFO)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 3)
+	assert.Equal(t, testWallet, reports[2].Wallet)
+	assert.Equal(t, secondSubaccountId, reports[2].SubaccountId)
+	assert.Equal(t, dec("3"), reports[2].RealizedPnL)
+	assert.Equal(t, dec("-1"), reports[2].Position)
+
+	_, err = ComputeByWallet([]indexer.Fill{spotFill(1, "0x01", true, "1", "1", "0")}, FIFO)
+	assert.EqualError(t, err, `invalid subaccount id "0x01"`)
+}
+
+func TestClosedLotCostBasisAndProceeds(t *testing.T) {
+	long := ClosedLot{Quantity: dec("2"), OpenPrice: dec("10"), ClosePrice: dec("12")}
+	assert.Equal(t, dec("20"), long.CostBasis())
+	assert.Equal(t, dec("24"), long.Proceeds())
+
+	short := ClosedLot{Quantity: dec("-1"), OpenPrice: dec("8"), ClosePrice: dec("6")}
+	assert.Equal(t, dec("6"), short.CostBasis())
+	assert.Equal(t, dec("8"), short.Proceeds())
+}
+
+func TestPeriodReportFundingAndFees(t *testing.T) {
+	period, err := NewPeriodReport(testWallet, hour(3), hour(5), testFills(), nil, FIFO)
+	assert.NoError(t, err)
+	assert.Len(t, period.Disposals, 2)
+	assert.Len(t, period.Fills, 2)
+	assert.Equal(t, dec("-1"), period.RealizedPnL)
+	assert.Equal(t, dec("0.1"), period.FeesPaid)
+	assert.Equal(t, dec("0.1"), period.FeeRebates)
+	assert.Equal(t, []FundingPayment{{Height: 4, Time: hour(4), MarketId: perpMarketId, SubaccountId: testSubaccountId, Amount: dec("-0.25")}}, period.Funding)
+	assert.True(t, period.FundingPaid.IsZero())
+	assert.Equal(t, dec("0.25"), period.FundingReceived)
+	assert.Equal(t, dec("-0.75"), period.NetPnL)
+
+	_, err = NewPeriodReport(testWallet, hour(3), hour(5), testFills(), nil, "hifo")
+	assert.EqualError(t, err, `unknown lot matching method "hifo"`)
+}
+
+func TestPeriodReportLedger(t *testing.T) {
+	fills := []indexer.Fill{
+		spotFill(1, testSubaccountId, true, "10", "2", "0.1"),
+		spotFill(2, secondSubaccountId, true, "12", "1", "0.1"),
+		spotFill(3, secondSubaccountId, false, "15", "2", "0.1"),
+	}
+	deposit := func(height int64, wallet string, amount int64) Transfer {
+		return Transfer{
+			Height:       height,
+			Time:         hour(height),
+			TxHash:       "AB12",
+			Kind:         TransferDeposit,
+			Wallet:       wallet,
+			SubaccountId: testSubaccountId,
+			Denom:        "inj",
+			Amount:       sdk.NewInt(amount),
+			Fee:          sdk.ZeroInt(),
+		}
+	}
+	bridge := deposit(3, testWallet, 7)
+	bridge.Kind = TransferBridgeOut
+	bridge.SubaccountId = ""
+	bridge.Counterparty = ethereumAddress
+	bridge.Fee = sdk.NewInt(1)
+	transfers := []Transfer{deposit(1, testWallet, 100), deposit(3, testWallet, 500), deposit(3, otherWallet, 5), bridge, deposit(3, testWallet, 20)}
+
+	period, err := NewPeriodReport(testWallet, hour(3), hour(4), fills, transfers, FIFO)
+	assert.NoError(t, err)
+	assert.Len(t, period.Disposals, 1)
+	assert.Equal(t, dec("10"), period.RealizedPnL)
+	assert.Equal(t, dec("9.9"), period.NetPnL)
+	assert.Len(t, period.Transfers, 3)
+	assert.Equal(t, []TransferTotal{
+		{Kind: TransferBridgeOut, Denom: "inj", Amount: sdk.NewInt(7), Fee: sdk.NewInt(1), Count: 1},
+		{Kind: TransferDeposit, Denom: "inj", Amount: sdk.NewInt(520), Fee: sdk.ZeroInt(), Count: 2},
+	}, period.TransferTotals)
+
+	var ledger bytes.Buffer
+	assert.NoError(t, WriteLedgerCSV(&ledger, period))
+	assert.Equal(t, strings.Join([]string{
+		"time,height,tx_hash,category,kind,market_id,subaccount_id,denom,quantity,cost_basis,proceeds,realized_pnl,fee,counterparty",
+		"2024-01-01T03:00:00Z,3,,trading,disposal," + spotMarketId + "," + secondSubaccountId + ",,2.000000000000000000,20.000000000000000000,30.000000000000000000,10.000000000000000000,,",
+		"2024-01-01T03:00:00Z,3,,trading,fee," + spotMarketId + "," + secondSubaccountId + ",,,,,-0.100000000000000000,0.100000000000000000,",
+		"2024-01-01T03:00:00Z,3,AB12,exchange,deposit,," + testSubaccountId + ",inj,500,,,,,",
+		"2024-01-01T03:00:00Z,3,AB12,bridge,bridge_out,,,inj,7,,,,1," + ethereumAddress,
+		"2024-01-01T03:00:00Z,3,AB12,exchange,deposit,," + testSubaccountId + ",inj,20,,,,,",
+		"",
+	}, "\n"), ledger.String())
+
+	var jsonReport bytes.Buffer
+	assert.NoError(t, WritePeriodJSON(&jsonReport, period))
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(jsonReport.Bytes(), &decoded))
+	assert.Equal(t, testWallet, decoded["wallet"])
+	assert.Equal(t, "10.000000000000000000", decoded["realized_pnl"])
+	assert.Len(t, decoded["transfers"], 3)
+	assert.Len(t, decoded["disposals"], 1)
+}
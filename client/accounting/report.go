@@ -0,0 +1,311 @@
+// Package accounting computes the realized PnL, fee and funding reports of subaccounts and wallets from their indexed
+// fills, and classifies the transfers of the wallets for the period reports.
+package accounting
+
+import (
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/indexer"
+)
+
+// LotMatching is the method matching the fills reducing a position with the lots that opened it
+type LotMatching string
+
+const (
+	// FIFO closes the oldest lots first
+	FIFO LotMatching = "fifo"
+	// LIFO closes the newest lots first
+	LIFO LotMatching = "lifo"
+	// AverageCost merges the lots of a position into one at their average price
+	AverageCost LotMatching = "average"
+)
+
+// Lot is an open part of a position. Quantity is negative for the short lots
+type Lot struct {
+	Height   int64     `json:"height"`
+	Time     time.Time `json:"time"`
+	Quantity sdk.Dec   `json:"quantity"`
+	Price    sdk.Dec   `json:"price"`
+}
+
+// ClosedLot is the part of a lot closed by a fill, and the PnL it realized
+type ClosedLot struct {
+	MarketId string `json:"market_id"`
+	// SubaccountId is the subaccount of the closing fill
+	SubaccountId string    `json:"subaccount_id"`
+	OpenHeight   int64     `json:"open_height"`
+	OpenTime     time.Time `json:"open_time"`
+	CloseHeight  int64     `json:"close_height"`
+	CloseTime    time.Time `json:"close_time"`
+	// Quantity is negative when a short lot was closed
+	Quantity    sdk.Dec `json:"quantity"`
+	OpenPrice   sdk.Dec `json:"open_price"`
+	ClosePrice  sdk.Dec `json:"close_price"`
+	RealizedPnL sdk.Dec `json:"realized_pnl"`
+}
+
+// CostBasis returns the cost of the closed quantity: the open value of a long lot, the close value of a short lot
+func (l ClosedLot) CostBasis() sdk.Dec {
+	if l.Quantity.IsNegative() {
+		return l.Quantity.Neg().Mul(l.ClosePrice)
+	}
+	return l.Quantity.Mul(l.OpenPrice)
+}
+
+// Proceeds returns the proceeds of the closed quantity: the close value of a long lot, the open value of a short lot
+func (l ClosedLot) Proceeds() sdk.Dec {
+	if l.Quantity.IsNegative() {
+		return l.Quantity.Neg().Mul(l.OpenPrice)
+	}
+	return l.Quantity.Mul(l.ClosePrice)
+}
+
+// FundingPayment is the funding settled by a fill. Amount is negative when the funding was received
+type FundingPayment struct {
+	Height       int64     `json:"height"`
+	Time         time.Time `json:"time"`
+	MarketId     string    `json:"market_id"`
+	SubaccountId string    `json:"subaccount_id"`
+	Amount       sdk.Dec   `json:"amount"`
+}
+
+// Report is the accounting of a subaccount, or of a wallet, in a market, in the quote denom of the market. The funding is the funding
+// settled by the fills of the subaccount: the chain settles the funding of a position when the position changes, so
+// the funding accrued since the last fill is not included
+type Report struct {
+	Wallet string `json:"wallet"`
+	// SubaccountId is empty in the wallet reports
+	SubaccountId string      `json:"subaccount_id"`
+	MarketId     string      `json:"market_id"`
+	Method       LotMatching `json:"method"`
+	Fills        int         `json:"fills"`
+	Volume       sdk.Dec     `json:"volume"`
+	// RealizedPnL excludes the fees and the funding
+	RealizedPnL sdk.Dec `json:"realized_pnl"`
+	FeesPaid    sdk.Dec `json:"fees_paid"`
+	// FeeRebates are the negative fees of the maker fills
+	FeeRebates      sdk.Dec `json:"fee_rebates"`
+	FundingPaid     sdk.Dec `json:"funding_paid"`
+	FundingReceived sdk.Dec `json:"funding_received"`
+	// NetPnL is RealizedPnL - FeesPaid + FeeRebates - FundingPaid + FundingReceived
+	NetPnL sdk.Dec `json:"net_pnl"`
+	// Position is the open quantity, negative when short
+	Position sdk.Dec          `json:"position"`
+	OpenLots []Lot            `json:"open_lots"`
+	Closed   []ClosedLot      `json:"closed_lots"`
+	Funding  []FundingPayment `json:"funding_payments"`
+}
+
+// AverageEntryPrice returns the average price of the open lots, zero without position
+func (r Report) AverageEntryPrice() sdk.Dec {
+	if r.Position.IsZero() {
+		return sdk.ZeroDec()
+	}
+	cost := sdk.ZeroDec()
+	for _, lot := range r.OpenLots {
+		cost = cost.Add(lot.Quantity.Mul(lot.Price))
+	}
+	return cost.Quo(r.Position)
+}
+
+func newReport(wallet, subaccountId, marketId string, method LotMatching) *Report {
+	return &Report{
+		Wallet:          wallet,
+		SubaccountId:    subaccountId,
+		MarketId:        marketId,
+		Method:          method,
+		Volume:          sdk.ZeroDec(),
+		RealizedPnL:     sdk.ZeroDec(),
+		FeesPaid:        sdk.ZeroDec(),
+		FeeRebates:      sdk.ZeroDec(),
+		FundingPaid:     sdk.ZeroDec(),
+		FundingReceived: sdk.ZeroDec(),
+		NetPnL:          sdk.ZeroDec(),
+		Position:        sdk.ZeroDec(),
+	}
+}
+
+// Compute returns the report of every subaccount and market of the fills, sorted by subaccount and market. The fills
+// must be in execution order, as returned by the indexer store
+func Compute(fills []indexer.Fill, method LotMatching) ([]Report, error) {
+	reports, err := compute(fills, method, false)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].SubaccountId != reports[j].SubaccountId {
+			return reports[i].SubaccountId < reports[j].SubaccountId
+		}
+		return reports[i].MarketId < reports[j].MarketId
+	})
+	return reports, nil
+}
+
+// ComputeByWallet returns the report of every wallet and market of the fills, sorted by wallet and market. The lots
+// of all the subaccounts of a wallet are matched together, as a tax authority sees one position per wallet
+func ComputeByWallet(fills []indexer.Fill, method LotMatching) ([]Report, error) {
+	reports, err := compute(fills, method, true)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Wallet != reports[j].Wallet {
+			return reports[i].Wallet < reports[j].Wallet
+		}
+		return reports[i].MarketId < reports[j].MarketId
+	})
+	return reports, nil
+}
+
+func compute(fills []indexer.Fill, method LotMatching, byWallet bool) ([]Report, error) {
+	if method != FIFO && method != LIFO && method != AverageCost {
+		return nil, errors.Errorf("unknown lot matching method %q", method)
+	}
+
+	type reportKey struct {
+		owner    string
+		marketId string
+	}
+	books := make(map[reportKey]*book)
+	wallets := make(map[string]string)
+	for _, fill := range fills {
+		wallet, found := wallets[fill.SubaccountId]
+		if !found {
+			var err error
+			if wallet, err = WalletOf(fill.SubaccountId); err != nil {
+				return nil, err
+			}
+			wallets[fill.SubaccountId] = wallet
+		}
+
+		key := reportKey{owner: fill.SubaccountId, marketId: fill.MarketId}
+		subaccountId := fill.SubaccountId
+		if byWallet {
+			key.owner = wallet
+			subaccountId = ""
+		}
+		b, found := books[key]
+		if !found {
+			b = &book{report: newReport(wallet, subaccountId, fill.MarketId, method)}
+			books[key] = b
+		}
+		b.apply(fill)
+	}
+
+	reports := make([]Report, 0, len(books))
+	for _, b := range books {
+		reports = append(reports, b.finish())
+	}
+	return reports, nil
+}
+
+// book applies the fills of a subaccount, or of a wallet, in a market to its lots
+type book struct {
+	report *Report
+	lots   []Lot
+	// fundingEntry is the cumulative funding at the last fill, when the funding of the position was settled
+	fundingEntry sdk.Dec
+}
+
+func (b *book) apply(fill indexer.Fill) {
+	r := b.report
+	r.Fills++
+	r.Volume = r.Volume.Add(fill.Quantity)
+	if fill.Fee.IsNegative() {
+		r.FeeRebates = r.FeeRebates.Sub(fill.Fee)
+	} else {
+		r.FeesPaid = r.FeesPaid.Add(fill.Fee)
+	}
+	if fill.IsDerivative {
+		b.settleFunding(fill)
+	}
+	if fill.Quantity.IsZero() {
+		return
+	}
+
+	quantity := fill.Quantity
+	if !fill.IsBuy {
+		quantity = quantity.Neg()
+	}
+
+	// the fill reduces the position until it is closed, the rest opens a position on the other side
+	for !quantity.IsZero() && !r.Position.IsZero() && r.Position.IsPositive() != quantity.IsPositive() {
+		i := 0
+		if r.Method == LIFO {
+			i = len(b.lots) - 1
+		}
+		lot := &b.lots[i]
+
+		closed := lot.Quantity
+		if quantity.Abs().LT(lot.Quantity.Abs()) {
+			closed = quantity.Neg()
+		}
+		realized := closed.Mul(fill.Price.Sub(lot.Price))
+		r.RealizedPnL = r.RealizedPnL.Add(realized)
+		r.Closed = append(r.Closed, ClosedLot{
+			MarketId:     fill.MarketId,
+			SubaccountId: fill.SubaccountId,
+			OpenHeight:   lot.Height,
+			OpenTime:     lot.Time,
+			CloseHeight:  fill.Height,
+			CloseTime:    fill.Time,
+			Quantity:     closed,
+			OpenPrice:    lot.Price,
+			ClosePrice:   fill.Price,
+			RealizedPnL:  realized,
+		})
+
+		lot.Quantity = lot.Quantity.Sub(closed)
+		r.Position = r.Position.Sub(closed)
+		quantity = quantity.Add(closed)
+		if lot.Quantity.IsZero() {
+			b.lots = append(b.lots[:i], b.lots[i+1:]...)
+		}
+	}
+	if quantity.IsZero() {
+		return
+	}
+
+	r.Position = r.Position.Add(quantity)
+	if r.Method == AverageCost && len(b.lots) > 0 {
+		merged := &b.lots[0]
+		total := merged.Quantity.Add(quantity)
+		merged.Price = merged.Quantity.Mul(merged.Price).Add(quantity.Mul(fill.Price)).Quo(total)
+		merged.Quantity = total
+		return
+	}
+	b.lots = append(b.lots, Lot{Height: fill.Height, Time: fill.Time, Quantity: quantity, Price: fill.Price})
+}
+
+// settleFunding pays the funding of the position since the previous fill: the longs pay the increase of the
+// cumulative funding and the shorts receive it
+func (b *book) settleFunding(fill indexer.Fill) {
+	cumulativeFunding := fill.CumulativeFunding
+	if cumulativeFunding.IsNil() {
+		return
+	}
+	r := b.report
+	if !r.Position.IsZero() && !b.fundingEntry.IsNil() {
+		payment := r.Position.Mul(cumulativeFunding.Sub(b.fundingEntry))
+		if !payment.IsZero() {
+			r.Funding = append(r.Funding, FundingPayment{Height: fill.Height, Time: fill.Time, MarketId: fill.MarketId, SubaccountId: fill.SubaccountId, Amount: payment})
+		}
+		if payment.IsPositive() {
+			r.FundingPaid = r.FundingPaid.Add(payment)
+		} else {
+			r.FundingReceived = r.FundingReceived.Sub(payment)
+		}
+	}
+	b.fundingEntry = cumulativeFunding
+}
+
+func (b *book) finish() Report {
+	r := *b.report
+	r.OpenLots = append([]Lot{}, b.lots...)
+	r.NetPnL = r.RealizedPnL.Sub(r.FeesPaid).Add(r.FeeRebates).Sub(r.FundingPaid).Add(r.FundingReceived)
+	return r
+}
@@ -0,0 +1,188 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/indexer"
+)
+
+const (
+	testWallet        = "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r"
+	testSubaccountId  = "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000"
+	otherSubaccountId = "0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000"
+	spotMarketId      = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	perpMarketId      = "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+)
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func perpFill(height int64, isBuy bool, price, quantity, fee, cumulativeFunding string) indexer.Fill {
+	return indexer.Fill{
+		Height:            height,
+		Time:              testTime.Add(time.Duration(height) * time.Hour),
+		MarketId:          perpMarketId,
+		SubaccountId:      testSubaccountId,
+		IsBuy:             isBuy,
+		IsDerivative:      true,
+		Price:             sdk.MustNewDecFromStr(price),
+		Quantity:          sdk.MustNewDecFromStr(quantity),
+		Fee:               sdk.MustNewDecFromStr(fee),
+		Margin:            sdk.ZeroDec(),
+		Payout:            sdk.ZeroDec(),
+		CumulativeFunding: sdk.MustNewDecFromStr(cumulativeFunding),
+	}
+}
+
+func testFills() []indexer.Fill {
+	return []indexer.Fill{
+		perpFill(1, true, "10", "2", "0.1", "0"),
+		perpFill(2, true, "13", "1", "0.1", "0.5"),
+		perpFill(3, false, "12", "2", "0.1", "0.5"),
+		perpFill(4, false, "8", "3", "-0.1", "0.25"),
+		perpFill(5, true, "6", "1", "0", "1.25"),
+	}
+}
+
+func dec(value string) sdk.Dec {
+	return sdk.MustNewDecFromStr(value)
+}
+
+func TestLotMatchingMethods(t *testing.T) {
+	tests := []struct {
+		method   LotMatching
+		realized string
+		openLot  Lot
+	}{
+		{method: FIFO, realized: "4", openLot: Lot{Height: 2, Quantity: dec("1"), Price: dec("13")}},
+		{method: LIFO, realized: "1", openLot: Lot{Height: 1, Quantity: dec("1"), Price: dec("10")}},
+		{method: AverageCost, realized: "2", openLot: Lot{Height: 1, Quantity: dec("1"), Price: dec("11")}},
+	}
+
+	for _, test := range tests {
+		reports, err := Compute(testFills()[:3], test.method)
+		assert.NoError(t, err)
+		assert.Len(t, reports, 1)
+
+		report := reports[0]
+		assert.Equal(t, test.method, report.Method)
+		assert.Equal(t, dec(test.realized), report.RealizedPnL, test.method)
+		assert.Equal(t, dec("1"), report.Position, test.method)
+		assert.Len(t, report.OpenLots, 1, test.method)
+		test.openLot.Time = testTime.Add(time.Duration(test.openLot.Height) * time.Hour)
+		assert.Equal(t, test.openLot, report.OpenLots[0], test.method)
+		assert.Equal(t, test.openLot.Price, report.AverageEntryPrice(), test.method)
+	}
+}
+
+func TestReportFeesFundingAndFlips(t *testing.T) {
+	fills := testFills()
+	for _, method := range []LotMatching{FIFO, LIFO, AverageCost} {
+		reports, err := Compute(fills, method)
+		assert.NoError(t, err)
+		report := reports[0]
+
+		assert.Equal(t, 5, report.Fills)
+		assert.Equal(t, dec("9"), report.Volume)
+		assert.Equal(t, dec("1"), report.RealizedPnL, method)
+		assert.Equal(t, dec("0.3"), report.FeesPaid)
+		assert.Equal(t, dec("0.1"), report.FeeRebates)
+		// the long of 2 pays 0.5 per unit, the long of 1 receives 0.25, the short of 2 receives 1 per unit
+		assert.Equal(t, dec("1"), report.FundingPaid)
+		assert.Equal(t, dec("2.25"), report.FundingReceived)
+		assert.Equal(t, dec("2.05"), report.NetPnL)
+		assert.Equal(t, dec("-1"), report.Position)
+		assert.Equal(t, dec("8"), report.AverageEntryPrice())
+	}
+
+	// the average cost matching agrees with the indexer realized PnL
+	reports, err := Compute(fills, AverageCost)
+	assert.NoError(t, err)
+	assert.Equal(t, indexer.ComputeRealizedPnL(fills).Realized, reports[0].RealizedPnL)
+
+	reports, err = Compute(fills, FIFO)
+	assert.NoError(t, err)
+	var closed []string
+	for _, lot := range reports[0].Closed {
+		closed = append(closed, lot.Quantity.String()+"@"+lot.OpenPrice.String()+">"+lot.ClosePrice.String()+"="+lot.RealizedPnL.String())
+	}
+	assert.Equal(t, []string{
+		"2.000000000000000000@10.000000000000000000>12.000000000000000000=4.000000000000000000",
+		"1.000000000000000000@13.000000000000000000>8.000000000000000000=-5.000000000000000000",
+		"-1.000000000000000000@8.000000000000000000>6.000000000000000000=2.000000000000000000",
+	}, closed)
+	assert.Equal(t, int64(2), reports[0].Closed[1].OpenHeight)
+	assert.Equal(t, int64(4), reports[0].Closed[1].CloseHeight)
+}
+
+func TestComputeGroupsBySubaccountAndMarket(t *testing.T) {
+	spotBuy := perpFill(1, true, "2", "5", "0.01", "0")
+	spotBuy.IsDerivative = false
+	spotBuy.MarketId = spotMarketId
+	spotBuy.SubaccountId = otherSubaccountId
+	spotBuy.CumulativeFunding = sdk.Dec{}
+	spotSell := spotBuy
+	spotSell.Height = 2
+	spotSell.IsBuy = false
+	spotSell.Price = dec("3")
+
+	fills := append(testFills(), spotBuy, spotSell)
+	reports, err := Compute(fills, FIFO)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 2)
+	assert.Equal(t, otherSubaccountId, reports[0].SubaccountId)
+	assert.Equal(t, spotMarketId, reports[0].MarketId)
+	assert.Equal(t, dec("5"), reports[0].RealizedPnL)
+	assert.True(t, reports[0].FundingPaid.IsZero())
+	assert.Empty(t, reports[0].OpenLots)
+	assert.True(t, reports[0].AverageEntryPrice().IsZero())
+	assert.Equal(t, testSubaccountId, reports[1].SubaccountId)
+
+	_, err = Compute(fills, "hifo")
+	assert.EqualError(t, err, `unknown lot matching method "hifo"`)
+}
+
+func TestLoadAndExport(t *testing.T) {
+	ctx := context.Background()
+	store := indexer.NewMemoryStore()
+	for i, fill := range testFills()[:3] {
+		assert.NoError(t, store.SaveBlock(ctx, int64(i+1), []indexer.Fill{fill}))
+	}
+
+	reports, err := Load(ctx, store, indexer.FillFilter{SubaccountId: testSubaccountId, Limit: 1, NewestFirst: true}, FIFO)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, 3, reports[0].Fills)
+
+	var csvReport bytes.Buffer
+	assert.NoError(t, WriteCSV(&csvReport, reports))
+	assert.Equal(t, strings.Join([]string{
+		"wallet,subaccount_id,market_id,method,fills,volume,realized_pnl,fees_paid,fee_rebates,funding_paid,funding_received,net_pnl,position,average_entry_price",
+		testWallet + "," + testSubaccountId + "," + perpMarketId + ",fifo,3,5.000000000000000000,4.000000000000000000,0.300000000000000000,0.000000000000000000,1.000000000000000000,0.000000000000000000,2.700000000000000000,1.000000000000000000,13.000000000000000000",
+		"",
+	}, "\n"), csvReport.String())
+
+	var closedLots bytes.Buffer
+	assert.NoError(t, WriteClosedLotsCSV(&closedLots, reports))
+	assert.Equal(t, strings.Join([]string{
+		"subaccount_id,market_id,open_height,open_time,close_height,close_time,quantity,open_price,close_price,realized_pnl",
+		testSubaccountId + "," + perpMarketId + ",1,2024-01-01T01:00:00Z,3,2024-01-01T03:00:00Z,2.000000000000000000,10.000000000000000000,12.000000000000000000,4.000000000000000000",
+		"",
+	}, "\n"), closedLots.String())
+
+	var jsonReport bytes.Buffer
+	assert.NoError(t, WriteJSON(&jsonReport, reports))
+	var decoded []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(jsonReport.Bytes(), &decoded))
+	assert.Equal(t, "4.000000000000000000", decoded[0]["realized_pnl"])
+	assert.Equal(t, "fifo", decoded[0]["method"])
+	assert.Len(t, decoded[0]["open_lots"], 1)
+	assert.Len(t, decoded[0]["closed_lots"], 1)
+}
@@ -0,0 +1,354 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/gogoproto/proto"
+	transfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	channeltypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+	chaintypes "github.com/InjectiveLabs/sdk-go/chain/types"
+	"github.com/InjectiveLabs/sdk-go/client/indexer"
+)
+
+// TransferKind classifies a transfer from the point of view of a wallet
+type TransferKind string
+
+const (
+	// TransferDeposit moves funds from the bank balance of the wallet to one of its subaccounts
+	TransferDeposit TransferKind = "deposit"
+	// TransferWithdrawal moves funds from a subaccount of the wallet to its bank balance
+	TransferWithdrawal TransferKind = "withdrawal"
+	// TransferInternal moves funds between two subaccounts of the wallet
+	TransferInternal TransferKind = "internal"
+	TransferIn       TransferKind = "transfer_in"
+	TransferOut      TransferKind = "transfer_out"
+	// TransferBridgeIn and TransferBridgeOut move funds from and to Ethereum (Peggy) or another IBC chain
+	TransferBridgeIn  TransferKind = "bridge_in"
+	TransferBridgeOut TransferKind = "bridge_out"
+)
+
+const (
+	CategoryExchange = "exchange"
+	CategoryTransfer = "transfer"
+	CategoryBridge   = "bridge"
+	CategoryTrading  = "trading"
+)
+
+// Category groups the kinds: the movements between a wallet and its own subaccounts (exchange), the transfers between
+// wallets (transfer), and the transfers to and from other chains (bridge)
+func (k TransferKind) Category() string {
+	switch k {
+	case TransferDeposit, TransferWithdrawal, TransferInternal:
+		return CategoryExchange
+	case TransferBridgeIn, TransferBridgeOut:
+		return CategoryBridge
+	default:
+		return CategoryTransfer
+	}
+}
+
+// Transfer is a movement of funds of a wallet. A transfer between two wallets is reported once for each of them
+type Transfer struct {
+	Height int64     `json:"height"`
+	Time   time.Time `json:"time"`
+	// TxHash is empty for the transfers of the begin and end block events
+	TxHash       string       `json:"tx_hash,omitempty"`
+	Kind         TransferKind `json:"kind"`
+	Wallet       string       `json:"wallet"`
+	SubaccountId string       `json:"subaccount_id,omitempty"`
+	// Counterparty is the other wallet or subaccount, or the address on the other chain
+	Counterparty string  `json:"counterparty,omitempty"`
+	Denom        string  `json:"denom"`
+	Amount       sdk.Int `json:"amount"`
+	// Fee is the bridge fee of the Peggy withdrawals, in the denom of the amount
+	Fee sdk.Int `json:"fee"`
+}
+
+var (
+	subaccountDepositEventType  = proto.MessageName(&exchangetypes.EventSubaccountDeposit{})
+	subaccountWithdrawEventType = proto.MessageName(&exchangetypes.EventSubaccountWithdraw{})
+	subaccountTransferEventType = proto.MessageName(&exchangetypes.EventSubaccountBalanceTransfer{})
+	sendToEthEventType          = proto.MessageName(&peggytypes.EventSendToEth{})
+	depositClaimEventType       = proto.MessageName(&peggytypes.EventDepositClaim{})
+	attestationObservedType     = proto.MessageName(&peggytypes.EventAttestationObserved{})
+
+	bankSendActions = map[string]bool{
+		sdk.MsgTypeURL(&banktypes.MsgSend{}):      true,
+		sdk.MsgTypeURL(&banktypes.MsgMultiSend{}): true,
+	}
+)
+
+// TransferDecoder classifies the transfers of the block events:
+//   - the exchange deposits, withdrawals and subaccount transfers
+//   - the bank transfers of the MsgSend and MsgMultiSend messages. The other bank transfers (fees, trading settlement,
+//     module accounts) are not transfers between wallets
+//   - the Peggy withdrawals, and the Peggy deposits whose attestation is observed in the block
+//   - the outgoing IBC transfers and the successfully received IBC packets
+//
+// The cancelled Peggy withdrawals and the refunds of the failed IBC transfers are not reported
+type TransferDecoder struct {
+	// PeggyDenoms maps the ERC20 contracts of the Cosmos native tokens (e.g. INJ) to their denom. The other tokens
+	// get the peggy denom of their contract
+	PeggyDenoms map[ethcommon.Address]string
+}
+
+// blockEvent is an event of a block with the hash of the transaction that emitted it
+type blockEvent struct {
+	txHash string
+	event  abci.Event
+}
+
+// DecodeTransfers returns the transfers of a block in execution order
+func (d TransferDecoder) DecodeTransfers(block *ctypes.ResultBlock, results *ctypes.ResultBlockResults) ([]Transfer, error) {
+	var events []blockEvent
+	for _, event := range results.BeginBlockEvents {
+		events = append(events, blockEvent{event: event})
+	}
+	for i, txResult := range results.TxsResults {
+		if txResult.Code != 0 {
+			continue
+		}
+		var txHash string
+		if i < len(block.Block.Data.Txs) {
+			txHash = fmt.Sprintf("%X", block.Block.Data.Txs[i].Hash())
+		}
+		for _, event := range txResult.Events {
+			events = append(events, blockEvent{txHash: txHash, event: event})
+		}
+	}
+	for _, event := range results.EndBlockEvents {
+		events = append(events, blockEvent{event: event})
+	}
+
+	state := &decodeState{decoder: d, height: results.Height, time: block.Block.Time.UTC(), claims: make(map[string]*peggytypes.EventDepositClaim)}
+	for _, e := range events {
+		if e.txHash != state.txHash {
+			state.txHash = e.txHash
+			state.action = ""
+		}
+		if err := state.decode(e.event); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the %s event of block %d", e.event.Type, results.Height)
+		}
+	}
+	return state.transfers, nil
+}
+
+type decodeState struct {
+	decoder   TransferDecoder
+	height    int64
+	time      time.Time
+	txHash    string
+	transfers []Transfer
+
+	// action is the type of the message whose events are decoded, and sender its sender
+	action string
+	sender string
+	// recvPacket is the last received IBC packet, for the denom of the transfer packet that follows
+	recvPacket map[string]string
+	// claims are the Peggy deposit claims of the block by attestation id
+	claims map[string]*peggytypes.EventDepositClaim
+}
+
+func (s *decodeState) add(kind TransferKind, wallet, subaccountId, counterparty string, coin sdk.Coin) {
+	s.transfers = append(s.transfers, Transfer{
+		Height:       s.height,
+		Time:         s.time,
+		TxHash:       s.txHash,
+		Kind:         kind,
+		Wallet:       wallet,
+		SubaccountId: subaccountId,
+		Counterparty: counterparty,
+		Denom:        coin.Denom,
+		Amount:       coin.Amount,
+		Fee:          sdk.ZeroInt(),
+	})
+}
+
+func (s *decodeState) decode(event abci.Event) error {
+	switch event.Type {
+	case sdk.EventTypeMessage:
+		attributes := eventAttributes(event)
+		if action, found := attributes[sdk.AttributeKeyAction]; found {
+			s.action = action
+			s.sender = ""
+		}
+		if sender, found := attributes[sdk.AttributeKeySender]; found {
+			s.sender = sender
+		}
+		return nil
+	case banktypes.EventTypeTransfer:
+		if !bankSendActions[s.action] {
+			return nil
+		}
+		attributes := eventAttributes(event)
+		sender := attributes[banktypes.AttributeKeySender]
+		if sender == "" {
+			// the outputs of a MsgMultiSend have the sender in the message event
+			sender = s.sender
+		}
+		recipient := attributes[banktypes.AttributeKeyRecipient]
+		coins, err := sdk.ParseCoinsNormalized(attributes[sdk.AttributeKeyAmount])
+		if err != nil {
+			return err
+		}
+		for _, coin := range coins {
+			s.add(TransferOut, sender, "", recipient, coin)
+			s.add(TransferIn, recipient, "", sender, coin)
+		}
+		return nil
+	case transfertypes.EventTypeTransfer:
+		attributes := eventAttributes(event)
+		coin, err := ibcCoin(attributes)
+		if err != nil {
+			return err
+		}
+		s.add(TransferBridgeOut, attributes[sdk.AttributeKeySender], "", attributes[transfertypes.AttributeKeyReceiver], coin)
+		return nil
+	case channeltypes.EventTypeRecvPacket:
+		s.recvPacket = eventAttributes(event)
+		return nil
+	case transfertypes.EventTypePacket:
+		return s.decodeReceivedPacket(eventAttributes(event))
+	case subaccountDepositEventType, subaccountWithdrawEventType, subaccountTransferEventType,
+		sendToEthEventType, depositClaimEventType, attestationObservedType:
+		typedEvent, err := sdk.ParseTypedEvent(event)
+		if err != nil {
+			return err
+		}
+		return s.decodeTypedEvent(typedEvent)
+	}
+	return nil
+}
+
+// decodeReceivedPacket reports the received transfer packets, with the local denom of the received tokens. The
+// acknowledgement events of the sent packets have the same type and are skipped
+func (s *decodeState) decodeReceivedPacket(attributes map[string]string) error {
+	if _, isAck := attributes[transfertypes.AttributeKeyAck]; isAck || attributes[transfertypes.AttributeKeyAckSuccess] != "true" || s.recvPacket == nil {
+		return nil
+	}
+	coin, err := ibcCoin(attributes)
+	if err != nil {
+		return err
+	}
+
+	srcPort, srcChannel := s.recvPacket[channeltypes.AttributeKeySrcPort], s.recvPacket[channeltypes.AttributeKeySrcChannel]
+	dstPort, dstChannel := s.recvPacket[channeltypes.AttributeKeyDstPort], s.recvPacket[channeltypes.AttributeKeyDstChannel]
+	if transfertypes.ReceiverChainIsSource(srcPort, srcChannel, coin.Denom) {
+		// the tokens come back to this chain
+		unprefixed := coin.Denom[len(transfertypes.GetDenomPrefix(srcPort, srcChannel)):]
+		coin.Denom = transfertypes.ParseDenomTrace(unprefixed).IBCDenom()
+	} else {
+		coin.Denom = transfertypes.ParseDenomTrace(transfertypes.GetPrefixedDenom(dstPort, dstChannel, coin.Denom)).IBCDenom()
+	}
+	s.add(TransferBridgeIn, attributes[transfertypes.AttributeKeyReceiver], "", attributes[sdk.AttributeKeySender], coin)
+	s.recvPacket = nil
+	return nil
+}
+
+func (s *decodeState) decodeTypedEvent(typedEvent proto.Message) error {
+	switch e := typedEvent.(type) {
+	case *exchangetypes.EventSubaccountDeposit:
+		subaccountId := ethcommon.BytesToHash(e.SubaccountId).Hex()
+		s.add(TransferDeposit, e.SrcAddress, subaccountId, "", e.Amount)
+	case *exchangetypes.EventSubaccountWithdraw:
+		subaccountId := ethcommon.BytesToHash(e.SubaccountId).Hex()
+		s.add(TransferWithdrawal, e.DstAddress, subaccountId, "", e.Amount)
+	case *exchangetypes.EventSubaccountBalanceTransfer:
+		srcWallet, err := WalletOf(e.SrcSubaccountId)
+		if err != nil {
+			return err
+		}
+		dstWallet, err := WalletOf(e.DstSubaccountId)
+		if err != nil {
+			return err
+		}
+		if srcWallet == dstWallet {
+			s.add(TransferInternal, srcWallet, e.SrcSubaccountId, e.DstSubaccountId, e.Amount)
+			return nil
+		}
+		s.add(TransferOut, srcWallet, e.SrcSubaccountId, e.DstSubaccountId, e.Amount)
+		s.add(TransferIn, dstWallet, e.DstSubaccountId, e.SrcSubaccountId, e.Amount)
+	case *peggytypes.EventSendToEth:
+		s.add(TransferBridgeOut, e.Sender, "", e.Receiver, e.Amount)
+		if e.BridgeFee.Denom == e.Amount.Denom {
+			s.transfers[len(s.transfers)-1].Fee = e.BridgeFee.Amount
+		}
+	case *peggytypes.EventDepositClaim:
+		// every orchestrator claims the deposit
+		s.claims[string(e.AttestationId)] = e
+	case *peggytypes.EventAttestationObserved:
+		claim, found := s.claims[string(e.AttestationId)]
+		if e.AttestationType != peggytypes.CLAIM_TYPE_DEPOSIT || !found {
+			return nil
+		}
+		delete(s.claims, string(e.AttestationId))
+		s.add(TransferBridgeIn, claim.CosmosReceiver, "", claim.EthereumSender, sdk.NewCoin(s.decoder.peggyDenom(claim.TokenContract), claim.Amount))
+	}
+	return nil
+}
+
+func (d TransferDecoder) peggyDenom(tokenContract string) string {
+	contract := ethcommon.HexToAddress(tokenContract)
+	if denom, found := d.PeggyDenoms[contract]; found {
+		return denom
+	}
+	return peggytypes.PeggyDenomString(contract)
+}
+
+// CollectTransfers decodes the transfers of the blocks from fromHeight to toHeight, both inclusive
+func (d TransferDecoder) CollectTransfers(ctx context.Context, source indexer.BlockSource, fromHeight, toHeight int64) ([]Transfer, error) {
+	var transfers []Transfer
+	for height := fromHeight; height <= toHeight; height++ {
+		block, err := source.GetBlock(ctx, height)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get block %d", height)
+		}
+		results, err := source.GetBlockResults(ctx, height)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the results of block %d", height)
+		}
+		blockTransfers, err := d.DecodeTransfers(block, results)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, blockTransfers...)
+	}
+	return transfers, nil
+}
+
+// WalletOf returns the bech32 address of the wallet owning the subaccount
+func WalletOf(subaccountId string) (string, error) {
+	if !strings.HasPrefix(subaccountId, "0x") || len(subaccountId) != 66 {
+		return "", errors.Errorf("invalid subaccount id %q", subaccountId)
+	}
+	address := exchangetypes.SubaccountIDToSdkAddress(ethcommon.HexToHash(subaccountId))
+	return sdk.Bech32ifyAddressBytes(chaintypes.Bech32PrefixAccAddr, address)
+}
+
+func eventAttributes(event abci.Event) map[string]string {
+	attributes := make(map[string]string, len(event.Attributes))
+	for _, attribute := range event.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	return attributes
+}
+
+func ibcCoin(attributes map[string]string) (sdk.Coin, error) {
+	amount, ok := sdk.NewIntFromString(attributes[transfertypes.AttributeKeyAmount])
+	if !ok {
+		return sdk.Coin{}, errors.Errorf("invalid IBC transfer amount %q", attributes[transfertypes.AttributeKeyAmount])
+	}
+	return sdk.Coin{Denom: attributes[transfertypes.AttributeKeyDenom], Amount: amount}, nil
+}
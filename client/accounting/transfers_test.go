@@ -0,0 +1,200 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	transfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+const (
+	otherWallet     = "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	ethereumAddress = "0x6b175474e89094c44da98b954eedeac495271d0f"
+	tokenContract   = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+)
+
+func typedEvent(t *testing.T, msg proto.Message) abci.Event {
+	event, err := sdk.TypedEventToEvent(msg)
+	assert.NoError(t, err)
+	return abci.Event(event)
+}
+
+func attributeEvent(eventType string, keyValues ...string) abci.Event {
+	event := abci.Event{Type: eventType}
+	for i := 0; i < len(keyValues); i += 2 {
+		event.Attributes = append(event.Attributes, abci.EventAttribute{Key: keyValues[i], Value: keyValues[i+1]})
+	}
+	return event
+}
+
+func testBlock(txsEvents [][]abci.Event, failed map[int]bool, endBlockEvents []abci.Event) (*ctypes.ResultBlock, *ctypes.ResultBlockResults) {
+	block := &ctypes.ResultBlock{Block: &tmtypes.Block{}}
+	block.Block.Time = testTime
+	results := &ctypes.ResultBlockResults{Height: 10, EndBlockEvents: endBlockEvents}
+	for i, events := range txsEvents {
+		block.Block.Data.Txs = append(block.Block.Data.Txs, tmtypes.Tx(fmt.Sprintf("tx-%d", i)))
+		txResult := &abci.ResponseDeliverTx{Events: events}
+		if failed[i] {
+			txResult.Code = 5
+		}
+		results.TxsResults = append(results.TxsResults, txResult)
+	}
+	return block, results
+}
+
+func txHash(i int) string {
+	return fmt.Sprintf("%X", tmtypes.Tx(fmt.Sprintf("tx-%d", i)).Hash())
+}
+
+func TestDecodeTransfers(t *testing.T) {
+	inj := sdk.NewCoin("inj", sdk.NewInt(500))
+	attestationId := []byte{1, 2, 3}
+	depositClaim := &peggytypes.EventDepositClaim{
+		AttestationId:  attestationId,
+		EthereumSender: ethereumAddress,
+		CosmosReceiver: testWallet,
+		TokenContract:  tokenContract,
+		Amount:         sdk.NewInt(70),
+	}
+
+	block, results := testBlock([][]abci.Event{
+		{
+			// the fee is transferred before the message
+			attributeEvent("transfer", "recipient", otherWallet, "sender", testWallet, "amount", "10inj"),
+			attributeEvent("message", "action", "/cosmos.bank.v1beta1.MsgSend", "sender", testWallet, "module", "bank"),
+			attributeEvent("transfer", "recipient", otherWallet, "sender", testWallet, "amount", "5inj,3peggy"+tokenContract),
+		},
+		{
+			attributeEvent("message", "action", "/injective.exchange.v1beta1.MsgDeposit"),
+			typedEvent(t, &exchangetypes.EventSubaccountDeposit{SrcAddress: testWallet, SubaccountId: ethcommon.HexToHash(testSubaccountId).Bytes(), Amount: inj}),
+		},
+		{
+			attributeEvent("message", "action", "/injective.exchange.v1beta1.MsgExternalTransfer"),
+			// the settlement of the exchange messages is not a wallet transfer
+			attributeEvent("transfer", "recipient", otherWallet, "sender", testWallet, "amount", "1inj"),
+			typedEvent(t, &exchangetypes.EventSubaccountBalanceTransfer{SrcSubaccountId: testSubaccountId, DstSubaccountId: secondSubaccountId, Amount: inj}),
+			typedEvent(t, &exchangetypes.EventSubaccountBalanceTransfer{SrcSubaccountId: testSubaccountId, DstSubaccountId: otherSubaccountId, Amount: inj}),
+			typedEvent(t, &exchangetypes.EventSubaccountWithdraw{SubaccountId: ethcommon.HexToHash(testSubaccountId).Bytes(), DstAddress: testWallet, Amount: inj}),
+		},
+		{
+			typedEvent(t, &exchangetypes.EventSubaccountDeposit{SrcAddress: otherWallet, SubaccountId: ethcommon.HexToHash(otherSubaccountId).Bytes(), Amount: inj}),
+		},
+		{
+			typedEvent(t, &peggytypes.EventSendToEth{Sender: testWallet, Receiver: ethereumAddress, Amount: inj, BridgeFee: sdk.NewCoin("inj", sdk.NewInt(2))}),
+			attributeEvent("ibc_transfer", "sender", testWallet, "receiver", "cosmos1receiver", "amount", "40", "denom", "inj"),
+			// the acknowledgement of a sent packet
+			attributeEvent("fungible_token_packet", "sender", testWallet, "receiver", "cosmos1receiver", "amount", "40", "denom", "inj", "acknowledgement", "result:AQ==", "success", "\x01"),
+		},
+		{
+			attributeEvent("recv_packet", "packet_src_port", "transfer", "packet_src_channel", "channel-9", "packet_dst_port", "transfer", "packet_dst_channel", "channel-1"),
+			attributeEvent("fungible_token_packet", "module", "transfer", "sender", "cosmos1sender", "receiver", testWallet, "denom", "uatom", "amount", "8", "success", "true"),
+			attributeEvent("recv_packet", "packet_src_port", "transfer", "packet_src_channel", "channel-9", "packet_dst_port", "transfer", "packet_dst_channel", "channel-1"),
+			attributeEvent("fungible_token_packet", "module", "transfer", "sender", "cosmos1sender", "receiver", testWallet, "denom", "transfer/channel-9/inj", "amount", "9", "success", "true"),
+			attributeEvent("recv_packet", "packet_src_port", "transfer", "packet_src_channel", "channel-9", "packet_dst_port", "transfer", "packet_dst_channel", "channel-1"),
+			attributeEvent("fungible_token_packet", "module", "transfer", "sender", "cosmos1sender", "receiver", testWallet, "denom", "uatom", "amount", "1", "success", "false"),
+		},
+		{
+			typedEvent(t, depositClaim),
+		},
+	}, map[int]bool{3: true}, []abci.Event{
+		typedEvent(t, depositClaim),
+		typedEvent(t, &peggytypes.EventAttestationObserved{AttestationType: peggytypes.CLAIM_TYPE_DEPOSIT, AttestationId: attestationId}),
+		typedEvent(t, &peggytypes.EventAttestationObserved{AttestationType: peggytypes.CLAIM_TYPE_DEPOSIT, AttestationId: []byte{9}}),
+	})
+
+	transfers, err := TransferDecoder{}.DecodeTransfers(block, results)
+	assert.NoError(t, err)
+
+	var decoded []string
+	for _, transfer := range transfers {
+		assert.Equal(t, int64(10), transfer.Height)
+		assert.Equal(t, testTime, transfer.Time)
+		decoded = append(decoded, fmt.Sprintf("%s %s %s %s %s%s %s %s", transfer.TxHash, transfer.Kind, transfer.Wallet,
+			transfer.SubaccountId, transfer.Amount, transfer.Denom, transfer.Fee, transfer.Counterparty))
+	}
+	uatom := transfertypes.ParseDenomTrace("transfer/channel-1/uatom").IBCDenom()
+	assert.Equal(t, []string{
+		txHash(0) + " transfer_out " + testWallet + "  5inj 0 " + otherWallet,
+		txHash(0) + " transfer_in " + otherWallet + "  5inj 0 " + testWallet,
+		txHash(0) + " transfer_out " + testWallet + "  3peggy" + tokenContract + " 0 " + otherWallet,
+		txHash(0) + " transfer_in " + otherWallet + "  3peggy" + tokenContract + " 0 " + testWallet,
+		txHash(1) + " deposit " + testWallet + " " + testSubaccountId + " 500inj 0 ",
+		txHash(2) + " internal " + testWallet + " " + testSubaccountId + " 500inj 0 " + secondSubaccountId,
+		txHash(2) + " transfer_out " + testWallet + " " + testSubaccountId + " 500inj 0 " + otherSubaccountId,
+		txHash(2) + " transfer_in " + otherWallet + " " + otherSubaccountId + " 500inj 0 " + testSubaccountId,
+		txHash(2) + " withdrawal " + testWallet + " " + testSubaccountId + " 500inj 0 ",
+		txHash(4) + " bridge_out " + testWallet + "  500inj 2 " + ethereumAddress,
+		txHash(4) + " bridge_out " + testWallet + "  40inj 0 cosmos1receiver",
+		txHash(5) + " bridge_in " + testWallet + "  8" + uatom + " 0 cosmos1sender",
+		txHash(5) + " bridge_in " + testWallet + "  9inj 0 cosmos1sender",
+		" bridge_in " + testWallet + "  70peggy" + tokenContract + " 0 " + ethereumAddress,
+	}, decoded)
+
+	decoder := TransferDecoder{PeggyDenoms: map[ethcommon.Address]string{ethcommon.HexToAddress(tokenContract): "usdt"}}
+	transfers, err = decoder.DecodeTransfers(block, results)
+	assert.NoError(t, err)
+	assert.Equal(t, "usdt", transfers[len(transfers)-1].Denom)
+	assert.Equal(t, TransferBridgeIn, transfers[len(transfers)-1].Kind)
+	assert.Equal(t, sdk.NewInt(70), transfers[len(transfers)-1].Amount)
+}
+
+func TestDecodeTransfersErrors(t *testing.T) {
+	block, results := testBlock([][]abci.Event{{
+		attributeEvent("ibc_transfer", "sender", testWallet, "receiver", "cosmos1receiver", "amount", "many", "denom", "inj"),
+	}}, nil, nil)
+	_, err := TransferDecoder{}.DecodeTransfers(block, results)
+	assert.EqualError(t, err, `failed to decode the ibc_transfer event of block 10: invalid IBC transfer amount "many"`)
+
+	block, results = testBlock([][]abci.Event{{
+		typedEvent(t, &exchangetypes.EventSubaccountBalanceTransfer{SrcSubaccountId: "0x01", DstSubaccountId: otherSubaccountId}),
+	}}, nil, nil)
+	_, err = TransferDecoder{}.DecodeTransfers(block, results)
+	assert.EqualError(t, err, `failed to decode the injective.exchange.v1beta1.EventSubaccountBalanceTransfer event of block 10: invalid subaccount id "0x01"`)
+}
+
+type testBlockSource struct {
+	blocks  map[int64]*ctypes.ResultBlock
+	results map[int64]*ctypes.ResultBlockResults
+}
+
+func (s testBlockSource) GetBlock(_ context.Context, height int64) (*ctypes.ResultBlock, error) {
+	return s.blocks[height], nil
+}
+
+func (s testBlockSource) GetBlockResults(_ context.Context, height int64) (*ctypes.ResultBlockResults, error) {
+	return s.results[height], nil
+}
+
+func (s testBlockSource) GetLatestBlockHeight(context.Context) (int64, error) {
+	return int64(len(s.blocks)), nil
+}
+
+func TestCollectTransfers(t *testing.T) {
+	source := testBlockSource{blocks: map[int64]*ctypes.ResultBlock{}, results: map[int64]*ctypes.ResultBlockResults{}}
+	for height := int64(1); height <= 3; height++ {
+		block, results := testBlock([][]abci.Event{{
+			typedEvent(t, &peggytypes.EventSendToEth{Sender: testWallet, Receiver: ethereumAddress, Amount: sdk.NewCoin("inj", sdk.NewInt(height))}),
+		}}, nil, nil)
+		results.Height = height
+		source.blocks[height] = block
+		source.results[height] = results
+	}
+
+	transfers, err := TransferDecoder{}.CollectTransfers(context.Background(), source, 2, 3)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, int64(2), transfers[0].Height)
+	assert.Equal(t, sdk.NewInt(3), transfers[1].Amount)
+	assert.Equal(t, CategoryBridge, transfers[1].Kind.Category())
+}
@@ -0,0 +1,160 @@
+package backtest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+const testMarketId = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+
+func orderbookEvent(timestamp int64, bestBid string, bestAsk string) Event {
+	return Event{
+		Type:      SpotOrderbookEvent,
+		MarketId:  testMarketId,
+		Timestamp: timestamp,
+		Orderbook: &spotExchangePB.StreamOrderbookV2Response{
+			MarketId:  testMarketId,
+			Timestamp: timestamp,
+			Orderbook: &spotExchangePB.SpotLimitOrderbookV2{
+				Buys:  []*spotExchangePB.PriceLevel{{Price: bestBid, Quantity: "10"}},
+				Sells: []*spotExchangePB.PriceLevel{{Price: bestAsk, Quantity: "10"}},
+			},
+		},
+	}
+}
+
+func tradeEvent(timestamp int64, price string, quantity string) Event {
+	return Event{
+		Type:      SpotTradeEvent,
+		MarketId:  testMarketId,
+		Timestamp: timestamp,
+		Trade: &spotExchangePB.StreamTradesResponse{
+			Timestamp: timestamp,
+			Trade: &spotExchangePB.SpotTrade{
+				MarketId:   testMarketId,
+				Price:      &spotExchangePB.PriceLevel{Price: price, Quantity: quantity},
+				ExecutedAt: timestamp,
+			},
+		},
+	}
+}
+
+func TestEventsRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, WriteEvent(&buffer, tradeEvent(2000, "11", "1")))
+	assert.NoError(t, WriteEvent(&buffer, orderbookEvent(1000, "9", "11")))
+	assert.Error(t, WriteEvent(&buffer, Event{Type: SpotTradeEvent}))
+
+	events, err := ReadEvents(&buffer)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, SpotOrderbookEvent, events[0].Type)
+	assert.Equal(t, "11", events[0].Orderbook.Orderbook.Sells[0].Price)
+	assert.Equal(t, "11", events[1].Trade.Trade.Price.Price)
+
+	_, err = ReadEvents(bytes.NewBufferString(`{"type":"unknown"}`))
+	assert.Error(t, err)
+}
+
+func TestReplayExchangeClientStreamsRecordedEvents(t *testing.T) {
+	events := []Event{orderbookEvent(1000, "9", "11"), tradeEvent(2000, "11", "1"), tradeEvent(3000, "10", "2")}
+	client := NewReplayExchangeClient(events)
+
+	stream, err := client.StreamSpotTrades(context.Background(), &spotExchangePB.StreamTradesRequest{MarketId: testMarketId})
+	assert.NoError(t, err)
+	first, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2000), first.Timestamp)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	orderbookStream, err := client.StreamSpotOrderbookV2(context.Background(), []string{"0x01"})
+	assert.NoError(t, err)
+	_, err = orderbookStream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+type testStrategy struct {
+	placed bool
+	fills  []Fill
+}
+
+func (s *testStrategy) OnSpotOrderbook(sim *Simulator, update *spotExchangePB.StreamOrderbookV2Response) {
+	if !s.placed {
+		s.placed = true
+		_, _ = sim.PlaceLimitOrder(update.MarketId, true, decimal.RequireFromString("10"), decimal.RequireFromString("3"))
+	}
+}
+
+func (s *testStrategy) OnSpotTrade(sim *Simulator, update *spotExchangePB.StreamTradesResponse) {}
+
+func (s *testStrategy) OnFill(sim *Simulator, fill Fill) {
+	s.fills = append(s.fills, fill)
+	if fill.IsBuy {
+		// take profit for each buy fill
+		_, _ = sim.PlaceLimitOrder(fill.MarketId, false, fill.Price.Add(decimal.NewFromInt(2)), fill.Quantity)
+	}
+}
+
+func TestSimulatorFillsRestingOrdersWithTrades(t *testing.T) {
+	events := []Event{
+		orderbookEvent(1000, "9", "11"),
+		tradeEvent(2000, "11", "5"),
+		tradeEvent(3000, "10", "2"),
+		tradeEvent(4000, "9.5", "5"),
+		tradeEvent(5000, "12", "1"),
+	}
+	strategy := &testStrategy{}
+	sim := NewSimulator()
+
+	assert.NoError(t, sim.Run(events, strategy))
+
+	fills := sim.Fills()
+	assert.Len(t, fills, 3)
+	assert.Equal(t, "2", fills[0].Quantity.String())
+	assert.True(t, fills[0].IsBuy)
+	assert.True(t, fills[0].IsMaker)
+	assert.Equal(t, int64(3000), fills[0].ExecutedAt.UnixMilli())
+	assert.Equal(t, "1", fills[1].Quantity.String())
+	assert.Equal(t, "10", fills[1].Price.String())
+	assert.False(t, fills[2].IsBuy)
+	assert.Equal(t, "12", fills[2].Price.String())
+	assert.Equal(t, "1", fills[2].Quantity.String())
+	assert.Len(t, strategy.fills, 3)
+
+	openOrders := sim.OpenOrders(testMarketId)
+	assert.Len(t, openOrders, 2)
+	assert.Equal(t, "12", openOrders[0].Price.String())
+	assert.Equal(t, "1", openOrders[0].remainingQuantity().String())
+	assert.Equal(t, "1", openOrders[1].remainingQuantity().String())
+	assert.True(t, sim.CancelOrder(openOrders[0].Id))
+	assert.False(t, sim.CancelOrder(openOrders[0].Id))
+	assert.Len(t, sim.OpenOrders(""), 1)
+}
+
+func TestSimulatorFillsCrossingOrdersAgainstOrderbook(t *testing.T) {
+	sim := NewSimulator()
+	assert.NoError(t, sim.Run([]Event{orderbookEvent(1000, "9", "11")}, &testStrategy{placed: true}))
+
+	_, err := sim.PlaceLimitOrder(testMarketId, true, decimal.RequireFromString("12"), decimal.RequireFromString("15"))
+	assert.NoError(t, err)
+
+	fills := sim.Fills()
+	assert.Len(t, fills, 1)
+	assert.False(t, fills[0].IsMaker)
+	assert.Equal(t, "11", fills[0].Price.String())
+	assert.Equal(t, "10", fills[0].Quantity.String())
+	assert.Equal(t, "5", sim.OpenOrders(testMarketId)[0].remainingQuantity().String())
+
+	_, err = sim.PlaceLimitOrder(testMarketId, true, decimal.Zero, decimal.RequireFromString("1"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+// Package backtest records exchange market data streams into compressed files, replays recorded data through the
+// exchange client streaming interfaces, and simulates the fills of the orders placed by a strategy against the
+// recorded trades and orderbooks.
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+type EventType string
+
+const (
+	SpotOrderbookEvent EventType = "spot_orderbook"
+	SpotTradeEvent     EventType = "spot_trade"
+)
+
+// maxEventLineLength is the longest JSON line accepted when reading recorded events (full orderbook snapshots)
+const maxEventLineLength = 16 * 1024 * 1024
+
+// Event is a recorded market data update. Recordings are stored as JSON lines, one event per line.
+// Only the field corresponding to the event type is set
+type Event struct {
+	Type     EventType `json:"type"`
+	MarketId string    `json:"market_id"`
+	// Timestamp is the time of the update in milliseconds
+	Timestamp int64                                     `json:"timestamp"`
+	Orderbook *spotExchangePB.StreamOrderbookV2Response `json:"orderbook,omitempty"`
+	Trade     *spotExchangePB.StreamTradesResponse      `json:"trade,omitempty"`
+}
+
+func (e Event) validate() error {
+	switch e.Type {
+	case SpotOrderbookEvent:
+		if e.Orderbook == nil || e.Orderbook.Orderbook == nil {
+			return errors.Errorf("%s event at %d has no orderbook", e.Type, e.Timestamp)
+		}
+	case SpotTradeEvent:
+		if e.Trade == nil || e.Trade.Trade == nil || e.Trade.Trade.Price == nil {
+			return errors.Errorf("%s event at %d has no trade", e.Type, e.Timestamp)
+		}
+	default:
+		return errors.Errorf("unknown event type %q", e.Type)
+	}
+	return nil
+}
+
+// ReadEvents parses a recording, returning the events sorted by timestamp (events with the same timestamp keep
+// the recorded order)
+func ReadEvents(r io.Reader) ([]Event, error) {
+	var events []Event
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLineLength)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the event in line %d", line)
+		}
+		if err := event.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid event in line %d", line)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the events")
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+
+	return events, nil
+}
+
+// WriteEvent appends the event to a recording
+func WriteEvent(w io.Writer, event Event) error {
+	if err := event.validate(); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the event")
+	}
+	_, err = w.Write(append(encoded, '\n'))
+	return err
+}
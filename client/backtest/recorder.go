@@ -0,0 +1,278 @@
+package backtest
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+const (
+	defaultPartitionDuration = time.Hour
+	defaultMaxFileSize       = 256 * 1024 * 1024
+
+	recordingFileExtension = ".jsonl.gz"
+	checksumFileExtension  = ".sha256"
+)
+
+type RecorderConfig struct {
+	// Dir is the directory where the recordings are created
+	Dir        string
+	FilePrefix string
+	// PartitionDuration is the time range of the events stored in each file (one hour by default)
+	PartitionDuration time.Duration
+	// MaxFileSize is the maximum uncompressed size of a file before rotating it (256MB by default)
+	MaxFileSize int64
+}
+
+// Recorder stores market data events in gzip compressed JSON lines files that can be loaded with ReadRecording.
+// A new file is created when the events move to a new time partition or the file reaches the max size.
+// The SHA-256 checksum of each file is written next to it when the file is closed
+type Recorder struct {
+	config RecorderConfig
+
+	mux            sync.Mutex
+	file           *os.File
+	gzipWriter     *gzip.Writer
+	hasher         hash.Hash
+	partitionStart time.Time
+	written        int64
+	sequence       int
+}
+
+func NewRecorder(config RecorderConfig) (*Recorder, error) {
+	if config.PartitionDuration <= 0 {
+		config.PartitionDuration = defaultPartitionDuration
+	}
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = defaultMaxFileSize
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create the recordings directory %s", config.Dir)
+	}
+
+	return &Recorder{config: config}, nil
+}
+
+// Record appends the event to the current file, rotating it if needed
+func (r *Recorder) Record(event Event) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	partitionStart := time.UnixMilli(event.Timestamp).UTC().Truncate(r.config.PartitionDuration)
+	// events from different streams can arrive slightly out of order, late events are kept in the current file
+	// (ReadEvents sorts them by timestamp)
+	if partitionStart.Before(r.partitionStart) {
+		partitionStart = r.partitionStart
+	}
+	if r.file == nil || partitionStart.After(r.partitionStart) || r.written >= r.config.MaxFileSize {
+		if err := r.rotate(partitionStart); err != nil {
+			return err
+		}
+	}
+
+	counter := &countingWriter{writer: r.gzipWriter}
+	if err := WriteEvent(counter, event); err != nil {
+		return errors.Wrap(err, "failed to record the event")
+	}
+	r.written += counter.count
+
+	return nil
+}
+
+// RecordSpotTrades records the trades received from the stream until the stream ends or the context is done
+func (r *Recorder) RecordSpotTrades(ctx context.Context, stream spotExchangePB.InjectiveSpotExchangeRPC_StreamTradesClient) error {
+	for ctx.Err() == nil {
+		res, err := stream.Recv()
+		if err != nil {
+			return streamEndError(err)
+		}
+		if res.GetTrade() == nil {
+			continue
+		}
+		if err := r.Record(Event{
+			Type:      SpotTradeEvent,
+			MarketId:  res.Trade.MarketId,
+			Timestamp: res.Timestamp,
+			Trade:     res,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordSpotOrderbooks records the orderbooks received from the stream until the stream ends or the context is done
+func (r *Recorder) RecordSpotOrderbooks(ctx context.Context, stream spotExchangePB.InjectiveSpotExchangeRPC_StreamOrderbookV2Client) error {
+	for ctx.Err() == nil {
+		res, err := stream.Recv()
+		if err != nil {
+			return streamEndError(err)
+		}
+		if res.GetOrderbook() == nil {
+			continue
+		}
+		if err := r.Record(Event{
+			Type:      SpotOrderbookEvent,
+			MarketId:  res.MarketId,
+			Timestamp: res.Timestamp,
+			Orderbook: res,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes and closes the current file
+func (r *Recorder) Close() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return r.closeFile()
+}
+
+func streamEndError(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+	return errors.Wrap(err, "failed to receive from the stream")
+}
+
+func (r *Recorder) rotate(partitionStart time.Time) error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+
+	if !partitionStart.Equal(r.partitionStart) {
+		r.sequence = 0
+	}
+
+	// existing files of the partition (from a previous rotation or recorder) are never overwritten
+	var file *os.File
+	for ; ; r.sequence++ {
+		fileName := fmt.Sprintf("%s-%s-%04d%s", r.config.FilePrefix, partitionStart.Format("20060102T150405Z"), r.sequence, recordingFileExtension)
+		var err error
+		file, err = os.OpenFile(filepath.Join(r.config.Dir, fileName), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return errors.Wrap(err, "failed to create the recording file")
+		}
+	}
+
+	r.file = file
+	r.hasher = sha256.New()
+	r.gzipWriter = gzip.NewWriter(io.MultiWriter(file, r.hasher))
+	r.partitionStart = partitionStart
+	r.written = 0
+
+	return nil
+}
+
+func (r *Recorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+
+	path := r.file.Name()
+	if err := r.gzipWriter.Close(); err != nil {
+		_ = r.file.Close()
+		return errors.Wrapf(err, "failed to flush the recording %s", path)
+	}
+	if err := r.file.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close the recording %s", path)
+	}
+	r.file = nil
+
+	// same format as sha256sum, so the files can also be checked with the standard tools
+	checksum := fmt.Sprintf("%s  %s\n", hex.EncodeToString(r.hasher.Sum(nil)), filepath.Base(path))
+	if err := os.WriteFile(path+checksumFileExtension, []byte(checksum), 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write the checksum of the recording %s", path)
+	}
+
+	return nil
+}
+
+// VerifyRecording checks the recording file matches its checksum file
+func VerifyRecording(path string) error {
+	checksumFile, err := os.ReadFile(path + checksumFileExtension)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the checksum of the recording %s", path)
+	}
+	fields := strings.Fields(string(checksumFile))
+	if len(fields) == 0 {
+		return errors.Errorf("the checksum file of the recording %s is empty", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open the recording %s", path)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return errors.Wrapf(err, "failed to read the recording %s", path)
+	}
+	if checksum := hex.EncodeToString(hasher.Sum(nil)); checksum != fields[0] {
+		return errors.Errorf("the recording %s is corrupted: checksum %s, expected %s", path, checksum, fields[0])
+	}
+
+	return nil
+}
+
+// ReadRecording verifies the recording integrity and returns its events sorted by timestamp
+func ReadRecording(path string) ([]Event, error) {
+	if err := VerifyRecording(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the recording %s", path)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decompress the recording %s", path)
+	}
+	defer gzipReader.Close()
+
+	return ReadEvents(gzipReader)
+}
+
+// RecordingFiles returns the recordings with the prefix in the directory, in chronological order
+func RecordingFiles(dir string, filePrefix string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, filePrefix+"-*"+recordingFileExtension))
+	if err != nil {
+		return nil, err
+	}
+	// the partition time and sequence in the name are zero padded, so the lexical order is the chronological order
+	return paths, nil
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
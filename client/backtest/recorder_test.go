@@ -0,0 +1,120 @@
+package backtest
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderRotatesByPartition(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir, FilePrefix: "spot", PartitionDuration: time.Minute})
+	assert.NoError(t, err)
+
+	assert.NoError(t, recorder.Record(tradeEvent(1000, "10", "1")))
+	assert.NoError(t, recorder.Record(orderbookEvent(2000, "9", "11")))
+	assert.NoError(t, recorder.Record(tradeEvent(61000, "11", "1")))
+	// late event from a previous partition is kept in the current file
+	assert.NoError(t, recorder.Record(tradeEvent(59000, "12", "1")))
+	assert.NoError(t, recorder.Close())
+
+	files, err := RecordingFiles(dir, "spot")
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Contains(t, files[0], "spot-19700101T000000Z-0000.jsonl.gz")
+	assert.Contains(t, files[1], "spot-19700101T000100Z-0000.jsonl.gz")
+
+	events, err := ReadRecording(files[0])
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	events, err = ReadRecording(files[1])
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(59000), events[0].Timestamp)
+	assert.Equal(t, int64(61000), events[1].Timestamp)
+}
+
+func TestRecorderRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir, FilePrefix: "spot", MaxFileSize: 1})
+	assert.NoError(t, err)
+
+	assert.NoError(t, recorder.Record(tradeEvent(1000, "10", "1")))
+	assert.NoError(t, recorder.Record(orderbookEvent(2000, "9", "11")))
+	assert.NoError(t, recorder.Close())
+
+	files, err := RecordingFiles(dir, "spot")
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Contains(t, files[0], "spot-19700101T000000Z-0000.jsonl.gz")
+	assert.Contains(t, files[1], "spot-19700101T000000Z-0001.jsonl.gz")
+}
+
+func TestRecorderDoesNotOverwriteExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		recorder, err := NewRecorder(RecorderConfig{Dir: dir, FilePrefix: "spot"})
+		assert.NoError(t, err)
+		assert.NoError(t, recorder.Record(tradeEvent(1000, "10", "1")))
+		assert.NoError(t, recorder.Close())
+	}
+
+	files, err := RecordingFiles(dir, "spot")
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
+func TestReadRecordingDetectsCorruption(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir, FilePrefix: "spot"})
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.Record(tradeEvent(1000, "10", "1")))
+	assert.NoError(t, recorder.Close())
+
+	files, err := RecordingFiles(dir, "spot")
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.NoError(t, VerifyRecording(files[0]))
+
+	content, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	content[len(content)-1] ^= 0xff
+	assert.NoError(t, os.WriteFile(files[0], content, 0o644))
+
+	_, err = ReadRecording(files[0])
+	assert.ErrorContains(t, err, "corrupted")
+}
+
+func TestRecorderRecordsStreams(t *testing.T) {
+	ctx := context.Background()
+	client := NewReplayExchangeClient([]Event{
+		orderbookEvent(1000, "9", "11"),
+		tradeEvent(2000, "10", "1"),
+		orderbookEvent(3000, "9", "10"),
+	})
+	dir := t.TempDir()
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir, FilePrefix: "spot"})
+	assert.NoError(t, err)
+
+	orderbookStream, err := client.StreamSpotOrderbookV2(ctx, []string{testMarketId})
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.RecordSpotOrderbooks(ctx, orderbookStream))
+	tradesStream, err := client.StreamSpotTrades(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.RecordSpotTrades(ctx, tradesStream))
+	assert.NoError(t, recorder.Close())
+
+	files, err := RecordingFiles(dir, "spot")
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	events, err := ReadRecording(files[0])
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, SpotOrderbookEvent, events[0].Type)
+	assert.Equal(t, SpotTradeEvent, events[1].Type)
+	assert.Equal(t, SpotOrderbookEvent, events[2].Type)
+}
@@ -0,0 +1,115 @@
+package backtest
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/InjectiveLabs/sdk-go/client/exchange"
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+// ReplayExchangeClient implements exchange.ExchangeClient serving the spot orderbook and trades streams from
+// recorded events, so code written against the live client can consume recorded data. The streams end with io.EOF
+// once all the events were delivered. The rest of the methods return empty responses
+type ReplayExchangeClient struct {
+	exchange.MockExchangeClient
+	events []Event
+}
+
+func NewReplayExchangeClient(events []Event) *ReplayExchangeClient {
+	return &ReplayExchangeClient{events: events}
+}
+
+func marketFilter(marketIds []string) func(marketId string) bool {
+	if len(marketIds) == 0 {
+		return func(string) bool { return true }
+	}
+
+	included := make(map[string]struct{}, len(marketIds))
+	for _, marketId := range marketIds {
+		included[marketId] = struct{}{}
+	}
+	return func(marketId string) bool {
+		_, found := included[marketId]
+		return found
+	}
+}
+
+func (c *ReplayExchangeClient) StreamSpotOrderbookV2(ctx context.Context, marketIds []string) (spotExchangePB.InjectiveSpotExchangeRPC_StreamOrderbookV2Client, error) {
+	isIncluded := marketFilter(marketIds)
+	var responses []*spotExchangePB.StreamOrderbookV2Response
+	for _, event := range c.events {
+		if event.Type == SpotOrderbookEvent && isIncluded(event.MarketId) {
+			responses = append(responses, event.Orderbook)
+		}
+	}
+
+	return &orderbookReplayStream{replayClientStream: replayClientStream{ctx: ctx}, responses: responses}, nil
+}
+
+func (c *ReplayExchangeClient) StreamSpotTrades(ctx context.Context, req *spotExchangePB.StreamTradesRequest) (spotExchangePB.InjectiveSpotExchangeRPC_StreamTradesClient, error) {
+	marketIds := req.GetMarketIds()
+	if req.GetMarketId() != "" {
+		marketIds = append(marketIds, req.GetMarketId())
+	}
+	isIncluded := marketFilter(marketIds)
+
+	var responses []*spotExchangePB.StreamTradesResponse
+	for _, event := range c.events {
+		if event.Type == SpotTradeEvent && isIncluded(event.MarketId) {
+			responses = append(responses, event.Trade)
+		}
+	}
+
+	return &tradesReplayStream{replayClientStream: replayClientStream{ctx: ctx}, responses: responses}, nil
+}
+
+// replayClientStream implements grpc.ClientStream for the replayed streams
+type replayClientStream struct {
+	ctx context.Context
+}
+
+func (s *replayClientStream) Header() (metadata.MD, error) { return metadata.MD{}, nil }
+func (s *replayClientStream) Trailer() metadata.MD         { return metadata.MD{} }
+func (s *replayClientStream) CloseSend() error             { return nil }
+func (s *replayClientStream) Context() context.Context     { return s.ctx }
+func (s *replayClientStream) SendMsg(m interface{}) error  { return nil }
+func (s *replayClientStream) RecvMsg(m interface{}) error  { return io.EOF }
+
+type orderbookReplayStream struct {
+	replayClientStream
+	responses []*spotExchangePB.StreamOrderbookV2Response
+}
+
+func (s *orderbookReplayStream) Recv() (*spotExchangePB.StreamOrderbookV2Response, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(s.responses) == 0 {
+		return nil, io.EOF
+	}
+
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return response, nil
+}
+
+type tradesReplayStream struct {
+	replayClientStream
+	responses []*spotExchangePB.StreamTradesResponse
+}
+
+func (s *tradesReplayStream) Recv() (*spotExchangePB.StreamTradesResponse, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(s.responses) == 0 {
+		return nil, io.EOF
+	}
+
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return response, nil
+}
@@ -0,0 +1,236 @@
+package backtest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+// Strategy receives the replayed events in timestamp order. The callbacks can place and cancel orders in the
+// simulator, and the fills of those orders are reported through OnFill
+type Strategy interface {
+	OnSpotOrderbook(sim *Simulator, update *spotExchangePB.StreamOrderbookV2Response)
+	OnSpotTrade(sim *Simulator, update *spotExchangePB.StreamTradesResponse)
+	OnFill(sim *Simulator, fill Fill)
+}
+
+// SimulatedOrder is a limit order placed in the simulator. Prices and quantities use the same (chain) format as
+// the recorded market data
+type SimulatedOrder struct {
+	Id             string
+	MarketId       string
+	IsBuy          bool
+	Price          decimal.Decimal
+	Quantity       decimal.Decimal
+	FilledQuantity decimal.Decimal
+	PlacedAt       time.Time
+}
+
+func (o *SimulatedOrder) remainingQuantity() decimal.Decimal {
+	return o.Quantity.Sub(o.FilledQuantity)
+}
+
+type Fill struct {
+	OrderId  string
+	MarketId string
+	IsBuy    bool
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+	// IsMaker is false for fills against the orderbook when the order is placed
+	IsMaker    bool
+	ExecutedAt time.Time
+}
+
+// Simulator keeps the strategy orders and fills them against the replayed data. Resting orders are filled by the
+// recorded trades executed at their price or better (the simulated orders are considered to be first in the queue),
+// and orders crossing the last recorded orderbook are filled immediately against its levels
+type Simulator struct {
+	now         time.Time
+	nextOrderId int
+	// openOrders keeps the placement order, which is the priority to fill orders at the same price
+	openOrders []*SimulatedOrder
+	orderbooks map[string]*spotExchangePB.SpotLimitOrderbookV2
+	fills      []Fill
+	strategy   Strategy
+	// pendingFills are notified to the strategy once the orders state is updated, so the callback can place
+	// and cancel orders
+	pendingFills []Fill
+}
+
+func NewSimulator() *Simulator {
+	return &Simulator{
+		orderbooks: make(map[string]*spotExchangePB.SpotLimitOrderbookV2),
+	}
+}
+
+// Now returns the timestamp of the event being replayed
+func (s *Simulator) Now() time.Time {
+	return s.now
+}
+
+// Fills returns all the fills since the replay started
+func (s *Simulator) Fills() []Fill {
+	return append([]Fill(nil), s.fills...)
+}
+
+// OpenOrders returns the orders of the market that are not completely filled or cancelled.
+// An empty marketId returns the orders of all the markets
+func (s *Simulator) OpenOrders(marketId string) []SimulatedOrder {
+	var orders []SimulatedOrder
+	for _, order := range s.openOrders {
+		if marketId == "" || order.MarketId == marketId {
+			orders = append(orders, *order)
+		}
+	}
+	return orders
+}
+
+// PlaceLimitOrder adds a limit order, filling immediately the part that crosses the last recorded orderbook
+func (s *Simulator) PlaceLimitOrder(marketId string, isBuy bool, price decimal.Decimal, quantity decimal.Decimal) (string, error) {
+	if !price.IsPositive() || !quantity.IsPositive() {
+		return "", errors.Errorf("order price and quantity must be positive (price %s, quantity %s)", price, quantity)
+	}
+
+	s.nextOrderId++
+	order := &SimulatedOrder{
+		Id:             fmt.Sprintf("sim-%d", s.nextOrderId),
+		MarketId:       marketId,
+		IsBuy:          isBuy,
+		Price:          price,
+		Quantity:       quantity,
+		FilledQuantity: decimal.Zero,
+		PlacedAt:       s.now,
+	}
+
+	if orderbook, found := s.orderbooks[marketId]; found {
+		levels := orderbook.Sells
+		if !isBuy {
+			levels = orderbook.Buys
+		}
+		s.fillAgainstLevels(order, levels)
+	}
+
+	if order.remainingQuantity().IsPositive() {
+		s.openOrders = append(s.openOrders, order)
+	}
+	s.notifyFills()
+
+	return order.Id, nil
+}
+
+// CancelOrder removes an open order, returning false if the order is not open
+func (s *Simulator) CancelOrder(orderId string) bool {
+	for i, order := range s.openOrders {
+		if order.Id == orderId {
+			s.openOrders = append(s.openOrders[:i], s.openOrders[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Run replays the events through the strategy callbacks and the fill simulation
+func (s *Simulator) Run(events []Event, strategy Strategy) error {
+	s.strategy = strategy
+	defer func() { s.strategy = nil }()
+
+	for _, event := range events {
+		if err := event.validate(); err != nil {
+			return err
+		}
+		s.now = time.UnixMilli(event.Timestamp)
+
+		switch event.Type {
+		case SpotOrderbookEvent:
+			s.orderbooks[event.MarketId] = event.Orderbook.Orderbook
+			strategy.OnSpotOrderbook(s, event.Orderbook)
+		case SpotTradeEvent:
+			if err := s.fillWithTrade(event.MarketId, event.Trade.Trade); err != nil {
+				return err
+			}
+			strategy.OnSpotTrade(s, event.Trade)
+		}
+	}
+
+	return nil
+}
+
+func (s *Simulator) fillAgainstLevels(order *SimulatedOrder, levels []*spotExchangePB.PriceLevel) {
+	for _, level := range levels {
+		if !order.remainingQuantity().IsPositive() {
+			return
+		}
+		levelPrice, err := decimal.NewFromString(level.GetPrice())
+		if err != nil {
+			continue
+		}
+		levelQuantity, err := decimal.NewFromString(level.GetQuantity())
+		if err != nil {
+			continue
+		}
+		if (order.IsBuy && levelPrice.GreaterThan(order.Price)) || (!order.IsBuy && levelPrice.LessThan(order.Price)) {
+			return
+		}
+
+		s.fill(order, levelPrice, decimal.Min(levelQuantity, order.remainingQuantity()), false)
+	}
+}
+
+func (s *Simulator) fillWithTrade(marketId string, trade *spotExchangePB.SpotTrade) error {
+	tradePrice, err := decimal.NewFromString(trade.Price.Price)
+	if err != nil {
+		return errors.Wrapf(err, "invalid price in trade %s", trade.TradeId)
+	}
+	availableQuantity, err := decimal.NewFromString(trade.Price.Quantity)
+	if err != nil {
+		return errors.Wrapf(err, "invalid quantity in trade %s", trade.TradeId)
+	}
+
+	var stillOpen []*SimulatedOrder
+	for _, order := range s.openOrders {
+		if order.MarketId == marketId && availableQuantity.IsPositive() {
+			crosses := (order.IsBuy && !tradePrice.GreaterThan(order.Price)) || (!order.IsBuy && !tradePrice.LessThan(order.Price))
+			if crosses {
+				quantity := decimal.Min(availableQuantity, order.remainingQuantity())
+				availableQuantity = availableQuantity.Sub(quantity)
+				s.fill(order, order.Price, quantity, true)
+			}
+		}
+		if order.remainingQuantity().IsPositive() {
+			stillOpen = append(stillOpen, order)
+		}
+	}
+	s.openOrders = stillOpen
+	s.notifyFills()
+
+	return nil
+}
+
+func (s *Simulator) fill(order *SimulatedOrder, price decimal.Decimal, quantity decimal.Decimal, isMaker bool) {
+	order.FilledQuantity = order.FilledQuantity.Add(quantity)
+	fill := Fill{
+		OrderId:    order.Id,
+		MarketId:   order.MarketId,
+		IsBuy:      order.IsBuy,
+		Price:      price,
+		Quantity:   quantity,
+		IsMaker:    isMaker,
+		ExecutedAt: s.now,
+	}
+	s.fills = append(s.fills, fill)
+	s.pendingFills = append(s.pendingFills, fill)
+}
+
+func (s *Simulator) notifyFills() {
+	for len(s.pendingFills) > 0 {
+		fill := s.pendingFills[0]
+		s.pendingFills = s.pendingFills[1:]
+		if s.strategy != nil {
+			s.strategy.OnFill(s, fill)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const defaultAccountPollInterval = 2 * time.Second
+
+type AccountDeltaType string
+
+const (
+	DepositCredited           AccountDeltaType = "deposit_credited"
+	DepositDebited            AccountDeltaType = "deposit_debited"
+	AvailableBalanceIncreased AccountDeltaType = "available_balance_increased"
+	AvailableBalanceDecreased AccountDeltaType = "available_balance_decreased"
+	PositionOpened            AccountDeltaType = "position_opened"
+	PositionIncreased         AccountDeltaType = "position_increased"
+	PositionDecreased         AccountDeltaType = "position_decreased"
+	PositionClosed            AccountDeltaType = "position_closed"
+	PositionLiquidated        AccountDeltaType = "position_liquidated"
+	MarginCredited            AccountDeltaType = "margin_credited"
+	MarginDebited             AccountDeltaType = "margin_debited"
+)
+
+// AccountDeltaEvent is a change in a subaccount deposit or position, between two observations of the account state.
+// Deposit events have Denom set and position events have MarketId set. Deltas are the current value minus the
+// previous one; QuantityDelta is the change of the position size (positive when the position grows)
+type AccountDeltaEvent struct {
+	Type         AccountDeltaType
+	SubaccountId string
+
+	Denom                 string
+	TotalBalanceDelta     sdk.Dec
+	AvailableBalanceDelta sdk.Dec
+	PreviousDeposit       exchangetypes.Deposit
+	CurrentDeposit        exchangetypes.Deposit
+
+	MarketId         string
+	QuantityDelta    sdk.Dec
+	MarginDelta      sdk.Dec
+	PreviousPosition *exchangetypes.Position
+	CurrentPosition  *exchangetypes.Position
+
+	// Height is the block height of the stream update, zero for polled changes
+	Height     uint64
+	ObservedAt time.Time
+}
+
+type accountState struct {
+	deposits  map[string]exchangetypes.Deposit
+	positions map[string]exchangetypes.Position
+}
+
+// AccountWatcher tracks the deposits and positions of a set of subaccounts and emits the differences between
+// consecutive observations as typed delta events. The state can be observed by polling the chain (Watch and Poll) or
+// by applying chain stream responses (ApplyStreamResponse), or both.
+// The first observation of each subaccount only sets the baseline and emits no events
+type AccountWatcher struct {
+	chainClient   ChainClient
+	subaccountIds []string
+	pollInterval  time.Duration
+	clock         clock.Clock
+	logger        log.Logger
+
+	mux                 sync.Mutex
+	states              map[string]*accountState
+	pendingLiquidations map[string]bool
+}
+
+// NewAccountWatcher creates a watcher for the given subaccounts. A zero pollInterval uses the default interval
+func NewAccountWatcher(chainClient ChainClient, subaccountIds []string, pollInterval time.Duration) *AccountWatcher {
+	if pollInterval <= 0 {
+		pollInterval = defaultAccountPollInterval
+	}
+
+	return &AccountWatcher{
+		chainClient:         chainClient,
+		subaccountIds:       subaccountIds,
+		pollInterval:        pollInterval,
+		clock:               clock.Real(),
+		logger:              log.WithField("module", "account-watcher"),
+		states:              make(map[string]*accountState),
+		pendingLiquidations: make(map[string]bool),
+	}
+}
+
+// SetClock sets the clock of the polls and of the observation times, before calling Watch
+func (w *AccountWatcher) SetClock(c clock.Clock) {
+	w.clock = clock.OrReal(c)
+}
+
+// Watch sends the account delta events to eventCh until the context is done.
+// Query errors are logged and do not stop the watcher
+func (w *AccountWatcher) Watch(ctx context.Context, eventCh chan<- AccountDeltaEvent) {
+	ticker := w.clock.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		events, err := w.Poll(ctx)
+		if err != nil {
+			w.logger.WithError(err).Warningln("failed to poll the account state")
+		}
+
+		for _, event := range events {
+			select {
+			case eventCh <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Poll fetches the deposits and positions of all the subaccounts and returns the changes since the previous
+// observation. The subaccounts that could not be queried keep their previous state
+func (w *AccountWatcher) Poll(ctx context.Context) ([]AccountDeltaEvent, error) {
+	var events []AccountDeltaEvent
+	var firstErr error
+
+	for _, subaccountId := range w.subaccountIds {
+		current, err := w.fetchAccountState(ctx, subaccountId)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		w.mux.Lock()
+		events = append(events, w.replaceState(subaccountId, current, 0, w.clock.Now())...)
+		w.mux.Unlock()
+	}
+
+	return events, firstErr
+}
+
+// ApplyStreamResponse updates the tracked state with the deposits and positions of a chain stream response and returns
+// the resulting events. Derivative trades with a liquidation execution type mark the next change of the position as
+// a liquidation. Updates are only applied to subaccounts with a baseline set by a previous Poll
+func (w *AccountWatcher) ApplyStreamResponse(response *chainstreamtypes.StreamResponse) []AccountDeltaEvent {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	observedAt := w.clock.Now()
+	if response.BlockTime > 0 {
+		observedAt = time.UnixMilli(response.BlockTime)
+	}
+
+	for _, trade := range response.DerivativeTrades {
+		if trade.ExecutionType == exchangetypes.ExecutionType_MarketLiquidation.String() && w.isWatched(trade.SubaccountId) {
+			w.pendingLiquidations[positionKey(trade.SubaccountId, trade.MarketId)] = true
+		}
+	}
+
+	var events []AccountDeltaEvent
+	for _, subaccountDeposits := range response.SubaccountDeposits {
+		state, found := w.states[subaccountDeposits.SubaccountId]
+		if !found {
+			continue
+		}
+		for _, deposit := range subaccountDeposits.Deposits {
+			previous := state.deposits[deposit.Denom]
+			state.deposits[deposit.Denom] = deposit.Deposit
+			events = append(events, depositDeltaEvents(subaccountDeposits.SubaccountId, deposit.Denom, previous, deposit.Deposit, response.BlockHeight, observedAt)...)
+		}
+	}
+
+	for _, position := range response.Positions {
+		state, found := w.states[position.SubaccountId]
+		if !found {
+			continue
+		}
+		current := exchangetypes.Position{
+			IsLong:                 position.IsLong,
+			Quantity:               position.Quantity,
+			EntryPrice:             position.EntryPrice,
+			Margin:                 position.Margin,
+			CumulativeFundingEntry: position.CumulativeFundingEntry,
+		}
+		events = append(events, w.updatePosition(state, position.SubaccountId, position.MarketId, current, response.BlockHeight, observedAt)...)
+	}
+
+	return events
+}
+
+// MarkLiquidation flags the position as liquidated, to report its next decrease or closure as a liquidation.
+// It can be used with liquidations detected from sources other than the chain stream (e.g. the indexer)
+func (w *AccountWatcher) MarkLiquidation(subaccountId string, marketId string) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	w.pendingLiquidations[positionKey(subaccountId, marketId)] = true
+}
+
+// SubaccountIds returns the watched subaccounts
+func (w *AccountWatcher) SubaccountIds() []string {
+	return append([]string{}, w.subaccountIds...)
+}
+
+// Deposits returns the last observed deposits of the subaccount, by denom
+func (w *AccountWatcher) Deposits(subaccountId string) map[string]exchangetypes.Deposit {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	deposits := make(map[string]exchangetypes.Deposit)
+	if state, found := w.states[subaccountId]; found {
+		for denom, deposit := range state.deposits {
+			deposits[denom] = deposit
+		}
+	}
+	return deposits
+}
+
+// Positions returns the last observed open positions of the subaccount, by market id
+func (w *AccountWatcher) Positions(subaccountId string) map[string]exchangetypes.Position {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	positions := make(map[string]exchangetypes.Position)
+	if state, found := w.states[subaccountId]; found {
+		for marketId, position := range state.positions {
+			positions[marketId] = position
+		}
+	}
+	return positions
+}
+
+func (w *AccountWatcher) fetchAccountState(ctx context.Context, subaccountId string) (*accountState, error) {
+	depositsRes, err := w.chainClient.FetchSubaccountDeposits(ctx, subaccountId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch the deposits of subaccount %s", subaccountId)
+	}
+	positionsRes, err := w.chainClient.FetchChainSubaccountPositions(ctx, subaccountId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch the positions of subaccount %s", subaccountId)
+	}
+
+	state := &accountState{
+		deposits:  make(map[string]exchangetypes.Deposit, len(depositsRes.Deposits)),
+		positions: make(map[string]exchangetypes.Position, len(positionsRes.State)),
+	}
+	for denom, deposit := range depositsRes.Deposits {
+		if deposit != nil {
+			state.deposits[denom] = *deposit
+		}
+	}
+	for _, position := range positionsRes.State {
+		if position.Position != nil && isOpenPosition(*position.Position) {
+			state.positions[position.MarketId] = *position.Position
+		}
+	}
+	return state, nil
+}
+
+// replaceState must be called holding the mutex
+func (w *AccountWatcher) replaceState(subaccountId string, current *accountState, height uint64, observedAt time.Time) []AccountDeltaEvent {
+	previous, found := w.states[subaccountId]
+	if !found {
+		w.states[subaccountId] = current
+		return nil
+	}
+
+	var events []AccountDeltaEvent
+	for denom, deposit := range current.deposits {
+		events = append(events, depositDeltaEvents(subaccountId, denom, previous.deposits[denom], deposit, height, observedAt)...)
+	}
+	for denom, deposit := range previous.deposits {
+		if _, found := current.deposits[denom]; !found {
+			events = append(events, depositDeltaEvents(subaccountId, denom, deposit, exchangetypes.Deposit{}, height, observedAt)...)
+		}
+	}
+
+	previousPositions := previous.positions
+	previous.deposits = current.deposits
+	for marketId, position := range current.positions {
+		events = append(events, w.updatePosition(previous, subaccountId, marketId, position, height, observedAt)...)
+	}
+	for marketId := range previousPositions {
+		if _, found := current.positions[marketId]; !found {
+			events = append(events, w.updatePosition(previous, subaccountId, marketId, exchangetypes.Position{}, height, observedAt)...)
+		}
+	}
+
+	return events
+}
+
+// updatePosition must be called holding the mutex
+func (w *AccountWatcher) updatePosition(state *accountState, subaccountId string, marketId string, current exchangetypes.Position, height uint64, observedAt time.Time) []AccountDeltaEvent {
+	previous, hadPosition := state.positions[marketId]
+	hasPosition := isOpenPosition(current)
+	if hasPosition {
+		state.positions[marketId] = current
+	} else {
+		delete(state.positions, marketId)
+	}
+
+	key := positionKey(subaccountId, marketId)
+	liquidated := w.pendingLiquidations[key]
+
+	newEvent := func(deltaType AccountDeltaType, previousPosition *exchangetypes.Position, currentPosition *exchangetypes.Position) AccountDeltaEvent {
+		previousQuantity, previousMargin := positionQuantityAndMargin(previousPosition)
+		currentQuantity, currentMargin := positionQuantityAndMargin(currentPosition)
+		return AccountDeltaEvent{
+			Type:             deltaType,
+			SubaccountId:     subaccountId,
+			MarketId:         marketId,
+			QuantityDelta:    currentQuantity.Sub(previousQuantity),
+			MarginDelta:      currentMargin.Sub(previousMargin),
+			PreviousPosition: previousPosition,
+			CurrentPosition:  currentPosition,
+			Height:           height,
+			ObservedAt:       observedAt,
+		}
+	}
+
+	var events []AccountDeltaEvent
+	switch {
+	case !hadPosition && !hasPosition:
+		return nil
+	case !hadPosition:
+		events = append(events, newEvent(PositionOpened, nil, &current))
+	case !hasPosition:
+		closeType := PositionClosed
+		if liquidated {
+			closeType = PositionLiquidated
+		}
+		events = append(events, newEvent(closeType, &previous, nil))
+	case previous.IsLong != current.IsLong:
+		// the position was closed and a new one in the opposite direction opened with the same trade
+		closeType := PositionClosed
+		if liquidated {
+			closeType = PositionLiquidated
+		}
+		events = append(events, newEvent(closeType, &previous, nil), newEvent(PositionOpened, nil, &current))
+	case current.Quantity.GT(previous.Quantity):
+		events = append(events, newEvent(PositionIncreased, &previous, &current))
+	case current.Quantity.LT(previous.Quantity):
+		decreaseType := PositionDecreased
+		if liquidated {
+			decreaseType = PositionLiquidated
+		}
+		events = append(events, newEvent(decreaseType, &previous, &current))
+	case current.Margin.GT(previous.Margin):
+		events = append(events, newEvent(MarginCredited, &previous, &current))
+	case current.Margin.LT(previous.Margin):
+		events = append(events, newEvent(MarginDebited, &previous, &current))
+	default:
+		return nil
+	}
+
+	delete(w.pendingLiquidations, key)
+	return events
+}
+
+func (w *AccountWatcher) isWatched(subaccountId string) bool {
+	for _, watchedSubaccountId := range w.subaccountIds {
+		if watchedSubaccountId == subaccountId {
+			return true
+		}
+	}
+	return false
+}
+
+func depositDeltaEvents(subaccountId string, denom string, previous exchangetypes.Deposit, current exchangetypes.Deposit, height uint64, observedAt time.Time) []AccountDeltaEvent {
+	totalDelta := decOrZero(current.TotalBalance).Sub(decOrZero(previous.TotalBalance))
+	availableDelta := decOrZero(current.AvailableBalance).Sub(decOrZero(previous.AvailableBalance))
+
+	var deltaType AccountDeltaType
+	switch {
+	case totalDelta.IsPositive():
+		deltaType = DepositCredited
+	case totalDelta.IsNegative():
+		deltaType = DepositDebited
+	case availableDelta.IsPositive():
+		deltaType = AvailableBalanceIncreased
+	case availableDelta.IsNegative():
+		deltaType = AvailableBalanceDecreased
+	default:
+		return nil
+	}
+
+	return []AccountDeltaEvent{{
+		Type:                  deltaType,
+		SubaccountId:          subaccountId,
+		Denom:                 denom,
+		TotalBalanceDelta:     totalDelta,
+		AvailableBalanceDelta: availableDelta,
+		PreviousDeposit:       previous,
+		CurrentDeposit:        current,
+		Height:                height,
+		ObservedAt:            observedAt,
+	}}
+}
+
+func positionQuantityAndMargin(position *exchangetypes.Position) (sdk.Dec, sdk.Dec) {
+	if position == nil {
+		return sdk.ZeroDec(), sdk.ZeroDec()
+	}
+	return decOrZero(position.Quantity), decOrZero(position.Margin)
+}
+
+func isOpenPosition(position exchangetypes.Position) bool {
+	return !position.Quantity.IsNil() && position.Quantity.IsPositive()
+}
+
+func positionKey(subaccountId string, marketId string) string {
+	return subaccountId + "/" + marketId
+}
+
+func decOrZero(value sdk.Dec) sdk.Dec {
+	if value.IsNil() {
+		return sdk.ZeroDec()
+	}
+	return value
+}
@@ -0,0 +1,142 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
+)
+
+type accountWatcherTestChainClient struct {
+	MockChainClient
+	deposits  map[string]*exchangetypes.Deposit
+	positions []exchangetypes.DerivativePosition
+}
+
+func (c *accountWatcherTestChainClient) FetchSubaccountDeposits(ctx context.Context, subaccountId string) (*exchangetypes.QuerySubaccountDepositsResponse, error) {
+	return &exchangetypes.QuerySubaccountDepositsResponse{Deposits: c.deposits}, nil
+}
+
+func (c *accountWatcherTestChainClient) FetchChainSubaccountPositions(ctx context.Context, subaccountId string) (*exchangetypes.QuerySubaccountPositionsResponse, error) {
+	return &exchangetypes.QuerySubaccountPositionsResponse{State: c.positions}, nil
+}
+
+func accountWatcherDeposit(available string, total string) *exchangetypes.Deposit {
+	return &exchangetypes.Deposit{AvailableBalance: sdk.MustNewDecFromStr(available), TotalBalance: sdk.MustNewDecFromStr(total)}
+}
+
+func accountWatcherPosition(isLong bool, quantity string, margin string) exchangetypes.DerivativePosition {
+	return exchangetypes.DerivativePosition{
+		SubaccountId: riskSubaccountId,
+		MarketId:     riskDerivativeMarketId,
+		Position: &exchangetypes.Position{
+			IsLong:   isLong,
+			Quantity: sdk.MustNewDecFromStr(quantity),
+			Margin:   sdk.MustNewDecFromStr(margin),
+		},
+	}
+}
+
+func accountWatcherEventTypes(events []AccountDeltaEvent) []AccountDeltaType {
+	types := make([]AccountDeltaType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestAccountWatcherPollEmitsDeltas(t *testing.T) {
+	chainClient := &accountWatcherTestChainClient{
+		deposits: map[string]*exchangetypes.Deposit{"peggy0xdAC17F958D2ee523a2206206994597C13D831ec7": accountWatcherDeposit("100", "100")},
+	}
+	watcher := NewAccountWatcher(chainClient, []string{riskSubaccountId}, 0)
+
+	events, err := watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events, "the first poll only sets the baseline")
+
+	chainClient.deposits = map[string]*exchangetypes.Deposit{"peggy0xdAC17F958D2ee523a2206206994597C13D831ec7": accountWatcherDeposit("150", "150")}
+	events, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []AccountDeltaType{DepositCredited}, accountWatcherEventTypes(events))
+	assert.Equal(t, "50.000000000000000000", events[0].TotalBalanceDelta.String())
+
+	chainClient.deposits = map[string]*exchangetypes.Deposit{"peggy0xdAC17F958D2ee523a2206206994597C13D831ec7": accountWatcherDeposit("90", "150")}
+	chainClient.positions = []exchangetypes.DerivativePosition{accountWatcherPosition(true, "2", "60")}
+	events, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []AccountDeltaType{AvailableBalanceDecreased, PositionOpened}, accountWatcherEventTypes(events))
+	assert.Equal(t, "-60.000000000000000000", events[0].AvailableBalanceDelta.String())
+	assert.Equal(t, "2.000000000000000000", events[1].QuantityDelta.String())
+
+	chainClient.positions = []exchangetypes.DerivativePosition{accountWatcherPosition(true, "2", "40")}
+	events, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []AccountDeltaType{MarginDebited}, accountWatcherEventTypes(events))
+	assert.Equal(t, "-20.000000000000000000", events[0].MarginDelta.String())
+
+	chainClient.positions = []exchangetypes.DerivativePosition{accountWatcherPosition(false, "1", "25")}
+	events, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []AccountDeltaType{PositionClosed, PositionOpened}, accountWatcherEventTypes(events))
+
+	chainClient.positions = nil
+	events, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []AccountDeltaType{PositionClosed}, accountWatcherEventTypes(events))
+	assert.Empty(t, watcher.Positions(riskSubaccountId))
+}
+
+func TestAccountWatcherStreamLiquidation(t *testing.T) {
+	chainClient := &accountWatcherTestChainClient{
+		positions: []exchangetypes.DerivativePosition{accountWatcherPosition(true, "5", "100")},
+	}
+	watcher := NewAccountWatcher(chainClient, []string{riskSubaccountId}, 0)
+	_, err := watcher.Poll(context.Background())
+	assert.NoError(t, err)
+
+	events := watcher.ApplyStreamResponse(&chainstreamtypes.StreamResponse{
+		BlockHeight: 10,
+		Positions: []*chainstreamtypes.Position{{
+			MarketId:     riskDerivativeMarketId,
+			SubaccountId: riskSubaccountId,
+			IsLong:       true,
+			Quantity:     sdk.MustNewDecFromStr("3"),
+			Margin:       sdk.MustNewDecFromStr("60"),
+		}},
+	})
+	assert.Equal(t, []AccountDeltaType{PositionDecreased}, accountWatcherEventTypes(events))
+	assert.Equal(t, uint64(10), events[0].Height)
+	assert.Equal(t, "-2.000000000000000000", events[0].QuantityDelta.String())
+
+	events = watcher.ApplyStreamResponse(&chainstreamtypes.StreamResponse{
+		BlockHeight: 11,
+		DerivativeTrades: []*chainstreamtypes.DerivativeTrade{{
+			MarketId:      riskDerivativeMarketId,
+			SubaccountId:  riskSubaccountId,
+			ExecutionType: exchangetypes.ExecutionType_MarketLiquidation.String(),
+		}},
+		Positions: []*chainstreamtypes.Position{{
+			MarketId:     riskDerivativeMarketId,
+			SubaccountId: riskSubaccountId,
+			IsLong:       true,
+			Quantity:     sdk.ZeroDec(),
+			Margin:       sdk.ZeroDec(),
+		}},
+	})
+	assert.Equal(t, []AccountDeltaType{PositionLiquidated}, accountWatcherEventTypes(events))
+	assert.Nil(t, events[0].CurrentPosition)
+
+	// updates of subaccounts without a baseline are ignored
+	events = watcher.ApplyStreamResponse(&chainstreamtypes.StreamResponse{
+		SubaccountDeposits: []*chainstreamtypes.SubaccountDeposits{{
+			SubaccountId: "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000",
+			Deposits:     []chainstreamtypes.SubaccountDeposit{{Denom: "inj", Deposit: *accountWatcherDeposit("1", "1")}},
+		}},
+	})
+	assert.Empty(t, events)
+}
@@ -0,0 +1,153 @@
+package chain
+
+import (
+	"bytes"
+	"sync"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	clienttx "github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/std"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	crisistypes "github.com/cosmos/cosmos-sdk/x/crisis/types"
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	evidencetypes "github.com/cosmos/cosmos-sdk/x/evidence/types"
+	feegranttypes "github.com/cosmos/cosmos-sdk/x/feegrant"
+	govv1types "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	paramproposaltypes "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	ibcfeetypes "github.com/cosmos/ibc-go/v7/modules/apps/29-fee/types"
+	ibcapplicationtypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/pkg/errors"
+
+	auction "github.com/InjectiveLabs/sdk-go/chain/auction/types"
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	exchange "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	insurance "github.com/InjectiveLabs/sdk-go/chain/insurance/types"
+	ocr "github.com/InjectiveLabs/sdk-go/chain/ocr/types"
+	oracle "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	peggy "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+	tokenfactory "github.com/InjectiveLabs/sdk-go/chain/tokenfactory/types"
+	wasmx "github.com/InjectiveLabs/sdk-go/chain/wasmx/types"
+)
+
+var (
+	legacyAmino     *codec.LegacyAmino
+	legacyAminoOnce sync.Once
+)
+
+// RegisterLegacyAminoCodec registers the amino names of the msgs of all the modules known by the SDK (see
+// RegisterInterfaces), the legacy StdTx and the Injective and Cosmos public and private key types
+func RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
+	std.RegisterLegacyAminoCodec(cdc)
+	// the keys are registered here because keyscodec.RegisterCrypto also replaces the global legacy.Cdc
+	cdc.RegisterConcrete(&ethsecp256k1.PubKey{}, ethsecp256k1.PubKeyName, nil)
+	cdc.RegisterConcrete(&ethsecp256k1.PrivKey{}, ethsecp256k1.PrivKeyName, nil)
+
+	exchange.RegisterLegacyAminoCodec(cdc)
+	oracle.RegisterLegacyAminoCodec(cdc)
+	insurance.RegisterLegacyAminoCodec(cdc)
+	auction.RegisterLegacyAminoCodec(cdc)
+	peggy.RegisterLegacyAminoCodec(cdc)
+	ocr.RegisterLegacyAminoCodec(cdc)
+	wasmx.RegisterLegacyAminoCodec(cdc)
+	tokenfactory.RegisterCodec(cdc)
+
+	// more cosmos types
+	authtypes.RegisterLegacyAminoCodec(cdc)
+	authztypes.RegisterLegacyAminoCodec(cdc)
+	vestingtypes.RegisterLegacyAminoCodec(cdc)
+	banktypes.RegisterLegacyAminoCodec(cdc)
+	crisistypes.RegisterLegacyAminoCodec(cdc)
+	distributiontypes.RegisterLegacyAminoCodec(cdc)
+	evidencetypes.RegisterLegacyAminoCodec(cdc)
+	govtypes.RegisterLegacyAminoCodec(cdc)
+	govv1types.RegisterLegacyAminoCodec(cdc)
+	paramproposaltypes.RegisterLegacyAminoCodec(cdc)
+	ibcapplicationtypes.RegisterLegacyAminoCodec(cdc)
+	ibcfeetypes.RegisterLegacyAminoCodec(cdc)
+	slashingtypes.RegisterLegacyAminoCodec(cdc)
+	stakingtypes.RegisterLegacyAminoCodec(cdc)
+	upgradetypes.RegisterLegacyAminoCodec(cdc)
+	feegranttypes.RegisterLegacyAminoCodec(cdc)
+	wasmtypes.RegisterLegacyAminoCodec(cdc)
+}
+
+// LegacyAmino returns a shared sealed amino codec with all the SDK types registered
+func LegacyAmino() *codec.LegacyAmino {
+	legacyAminoOnce.Do(func() {
+		legacyAmino = codec.NewLegacyAmino()
+		RegisterLegacyAminoCodec(legacyAmino)
+		legacyAmino.Seal()
+	})
+	return legacyAmino
+}
+
+// DecodeLegacyAminoMsg decodes a msg in amino JSON ({"type": "exchange/MsgCreateSpotLimitOrder", "value": {...}}), as
+// signed with SIGN_MODE_LEGACY_AMINO_JSON (e.g. by Ledger or Keplr) or stored by legacy services
+func DecodeLegacyAminoMsg(msgJSON []byte) (sdk.Msg, error) {
+	var msg sdk.Msg
+	if err := LegacyAmino().UnmarshalJSON(msgJSON, &msg); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the amino JSON msg")
+	}
+	return msg, nil
+}
+
+// DecodeLegacyAminoTx decodes a legacy StdTx, encoded in amino binary or in amino JSON (with or without the
+// {"type": "cosmos-sdk/StdTx"} envelope)
+func DecodeLegacyAminoTx(txBytes []byte) (legacytx.StdTx, error) {
+	var stdTx legacytx.StdTx
+	trimmed := bytes.TrimSpace(txBytes)
+	if len(trimmed) == 0 {
+		return stdTx, errors.New("the legacy tx is empty")
+	}
+
+	if trimmed[0] != '{' {
+		if err := LegacyAmino().Unmarshal(txBytes, &stdTx); err != nil {
+			return stdTx, errors.Wrap(err, "failed to decode the amino binary tx")
+		}
+		return stdTx, nil
+	}
+
+	var tx sdk.Tx
+	if err := LegacyAmino().UnmarshalJSON(trimmed, &tx); err == nil {
+		decoded, ok := tx.(legacytx.StdTx)
+		if !ok {
+			return stdTx, errors.Errorf("the amino JSON tx is a %T, not a StdTx", tx)
+		}
+		return decoded, nil
+	}
+	if err := LegacyAmino().UnmarshalJSON(trimmed, &stdTx); err != nil {
+		return stdTx, errors.Wrap(err, "failed to decode the amino JSON tx")
+	}
+	return stdTx, nil
+}
+
+// MigrateLegacyAminoTx converts a legacy StdTx (see DecodeLegacyAminoTx) to a proto encoded tx with the same msgs,
+// fee, memo, timeout height and signatures. The signatures keep the SIGN_MODE_LEGACY_AMINO_JSON mode they were made
+// with. StdTx signatures do not include the signer sequence, so the migrated signatures have sequence 0
+func MigrateLegacyAminoTx(txConfig client.TxConfig, txBytes []byte) ([]byte, error) {
+	stdTx, err := DecodeLegacyAminoTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := txConfig.NewTxBuilder()
+	if err := clienttx.CopyTx(stdTx, builder, false); err != nil {
+		return nil, errors.Wrap(err, "failed to copy the legacy tx")
+	}
+	protoTxBytes, err := txConfig.TxEncoder()(builder.GetTx())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode the migrated tx")
+	}
+	return protoTxBytes, nil
+}
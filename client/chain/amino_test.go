@@ -0,0 +1,89 @@
+package chain
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func TestDecodeLegacyAminoMsg(t *testing.T) {
+	// amino JSON of a spot limit order, as signed with SIGN_MODE_LEGACY_AMINO_JSON
+	msgJSON := `{"type":"exchange/MsgCreateSpotLimitOrder","value":{"sender":"inj1rle8yrynltrum4mn7efn0m52f5qsj3hm38xwrw",` +
+		`"order":{"market_id":"` + riskSpotMarketId + `","order_info":{"subaccount_id":"` + riskSubaccountId + `",` +
+		`"fee_recipient":"inj1rle8yrynltrum4mn7efn0m52f5qsj3hm38xwrw","price":"10.500000000000000000",` +
+		`"quantity":"2.000000000000000000","cid":"legacy-1"},"order_type":1,"trigger_price":null}}}`
+
+	msg, err := DecodeLegacyAminoMsg([]byte(msgJSON))
+
+	assert.NoError(t, err)
+	order, ok := msg.(*exchangetypes.MsgCreateSpotLimitOrder)
+	assert.True(t, ok)
+	assert.Equal(t, "inj1rle8yrynltrum4mn7efn0m52f5qsj3hm38xwrw", order.Sender)
+	assert.Equal(t, riskSpotMarketId, order.Order.MarketId)
+	assert.Equal(t, exchangetypes.OrderType_BUY, order.Order.OrderType)
+	assert.Equal(t, "10.500000000000000000", order.Order.OrderInfo.Price.String())
+	assert.Equal(t, "legacy-1", order.Order.OrderInfo.Cid)
+
+	_, err = DecodeLegacyAminoMsg([]byte(`{"type":"exchange/MsgUnknown","value":{}}`))
+	assert.Error(t, err)
+}
+
+func TestMigrateLegacyAminoTx(t *testing.T) {
+	privKey, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	sender := sdk.AccAddress(privKey.PubKey().Address()).String()
+	msg := &banktypes.MsgSend{
+		FromAddress: sender,
+		ToAddress:   "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r",
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin("inj", 1000)),
+	}
+	fee := legacytx.NewStdFee(150000, sdk.NewCoins(sdk.NewInt64Coin("inj", 500)))
+	stdTx := legacytx.NewStdTx([]sdk.Msg{msg}, fee, []legacytx.StdSignature{
+		legacytx.NewStdSignature(privKey.PubKey(), []byte{1, 2, 3}),
+	}, "legacy memo")
+	stdTx.TimeoutHeight = 1000
+
+	binaryTx, err := LegacyAmino().Marshal(stdTx)
+	assert.NoError(t, err)
+	jsonTx, err := LegacyAmino().MarshalJSON(stdTx)
+	assert.NoError(t, err)
+
+	for name, txBytes := range map[string][]byte{"binary": binaryTx, "json": jsonTx} {
+		decodedStdTx, err := DecodeLegacyAminoTx(txBytes)
+		assert.NoError(t, err, name)
+		assert.Equal(t, "legacy memo", decodedStdTx.GetMemo(), name)
+
+		protoTxBytes, err := MigrateLegacyAminoTx(txDecoderConfig(), txBytes)
+		assert.NoError(t, err, name)
+
+		decodedTx, err := DecodeTx(protoTxBytes)
+		assert.NoError(t, err, name)
+		assert.Equal(t, []sdk.Msg{msg}, decodedTx.Msgs, name)
+		assert.Equal(t, "legacy memo", decodedTx.Memo, name)
+		assert.Equal(t, uint64(150000), decodedTx.GasLimit, name)
+		assert.Equal(t, "500inj", decodedTx.Fee.String(), name)
+		assert.Equal(t, uint64(1000), decodedTx.TimeoutHeight, name)
+		assert.Len(t, decodedTx.Signers, 1, name)
+		assert.Equal(t, sender, decodedTx.Signers[0].Address.String(), name)
+		assert.Equal(t, signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, decodedTx.Signers[0].SignMode, name)
+		assert.Equal(t, []byte{1, 2, 3}, decodedTx.Signers[0].Signature, name)
+	}
+}
+
+func TestDecodeLegacyAminoTxRejectsInvalidBytes(t *testing.T) {
+	_, err := DecodeLegacyAminoTx(nil)
+	assert.Error(t, err)
+
+	_, err = DecodeLegacyAminoTx([]byte{0x0a, 0x01})
+	assert.Error(t, err)
+
+	_, err = DecodeLegacyAminoTx([]byte(`{"type":"cosmos-sdk/StdTx","value":`))
+	assert.Error(t, err)
+}
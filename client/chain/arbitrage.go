@@ -0,0 +1,164 @@
+package chain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/core"
+)
+
+var basisPointsMultiplier = decimal.NewFromInt(10000)
+
+// SpotPerpQuote is the top of book of a spot market and its corresponding perpetual market, read at the same block
+// height. Prices are human readable
+type SpotPerpQuote struct {
+	Height          int64
+	SpotMarket      core.SpotMarket
+	PerpetualMarket core.DerivativeMarket
+
+	SpotBestBid decimal.Decimal
+	SpotBestAsk decimal.Decimal
+	PerpBestBid decimal.Decimal
+	PerpBestAsk decimal.Decimal
+
+	// EstimatedHourlyFundingRate is the funding rate of the current interval, estimated with the premium accumulated
+	// so far. A positive rate means longs pay shorts
+	EstimatedHourlyFundingRate decimal.Decimal
+	NextFundingTimestamp       int64
+}
+
+func (q SpotPerpQuote) SpotMidPrice() decimal.Decimal {
+	return q.SpotBestBid.Add(q.SpotBestAsk).Div(decimal.NewFromInt(2))
+}
+
+func (q SpotPerpQuote) PerpMidPrice() decimal.Decimal {
+	return q.PerpBestBid.Add(q.PerpBestAsk).Div(decimal.NewFromInt(2))
+}
+
+// BasisBps is the difference between the perpetual and spot mid prices, in basis points of the spot mid price
+func (q SpotPerpQuote) BasisBps() decimal.Decimal {
+	spotMid := q.SpotMidPrice()
+	return q.PerpMidPrice().Sub(spotMid).Div(spotMid).Mul(basisPointsMultiplier)
+}
+
+// EntryBasisBps is the basis captured by buying spot at the best ask and selling the perpetual at the best bid
+func (q SpotPerpQuote) EntryBasisBps() decimal.Decimal {
+	return q.PerpBestBid.Sub(q.SpotBestAsk).Div(q.SpotBestAsk).Mul(basisPointsMultiplier)
+}
+
+// CarryBps is the return in basis points of holding a long spot and short perpetual position during the horizon: the
+// current basis plus the funding received, assuming the estimated funding rate stays constant
+func (q SpotPerpQuote) CarryBps(horizon time.Duration) decimal.Decimal {
+	fundingBps := q.EstimatedHourlyFundingRate.Mul(decimal.NewFromFloat(horizon.Hours())).Mul(basisPointsMultiplier)
+	return q.BasisBps().Add(fundingBps)
+}
+
+// FindPerpetualMarketForSpot returns the perpetual market with the same base and quote as the spot market (the
+// perpetual ticker is the spot ticker followed by PERP)
+func FindPerpetualMarketForSpot(assistant MarketsAssistant, spotMarketId string) (core.DerivativeMarket, bool) {
+	spotMarket, found := assistant.AllSpotMarkets()[spotMarketId]
+	if !found {
+		return core.DerivativeMarket{}, false
+	}
+
+	expectedTicker := strings.ToUpper(spotMarket.Ticker + " PERP")
+	for _, derivativeMarket := range assistant.AllDerivativeMarkets() {
+		if derivativeMarket.ExpirationTimestamp == 0 && strings.ToUpper(derivativeMarket.Ticker) == expectedTicker && derivativeMarket.QuoteToken.Denom == spotMarket.QuoteToken.Denom {
+			return derivativeMarket, true
+		}
+	}
+	return core.DerivativeMarket{}, false
+}
+
+// FetchSpotPerpQuote queries the top of book of both markets and the perpetual market funding. All the queries are
+// pinned to the height of the first one, so the quotes are consistent with each other
+func FetchSpotPerpQuote(ctx context.Context, chainClient MarketQuerier, assistant MarketsAssistant, spotMarketId string, perpMarketId string) (SpotPerpQuote, error) {
+	spotMarket, found := assistant.AllSpotMarkets()[spotMarketId]
+	if !found {
+		return SpotPerpQuote{}, errors.Errorf("spot market %s not found", spotMarketId)
+	}
+	perpMarket, found := assistant.AllDerivativeMarkets()[perpMarketId]
+	if !found {
+		return SpotPerpQuote{}, errors.Errorf("derivative market %s not found", perpMarketId)
+	}
+	if perpMarket.ExpirationTimestamp != 0 {
+		return SpotPerpQuote{}, errors.Errorf("derivative market %s is not a perpetual market", perpMarketId)
+	}
+
+	spotTOB, height, err := QueryAtHeight(ctx, 0, func(ctx context.Context) (*exchangetypes.QuerySpotMidPriceAndTOBResponse, error) {
+		return chainClient.FetchSpotMidPriceAndTOB(ctx, spotMarketId)
+	})
+	if err != nil {
+		return SpotPerpQuote{}, errors.Wrapf(err, "failed to fetch the top of book of spot market %s", spotMarketId)
+	}
+
+	var perpTOB *exchangetypes.QueryDerivativeMidPriceAndTOBResponse
+	var marketInfo *exchangetypes.QueryPerpetualMarketInfoResponse
+	var funding *exchangetypes.QueryPerpetualMarketFundingResponse
+	_, _, err = QueryAtHeight(ctx, height, func(ctx context.Context) (struct{}, error) {
+		if perpTOB, err = chainClient.FetchDerivativeMidPriceAndTOB(ctx, perpMarketId); err != nil {
+			return struct{}{}, errors.Wrapf(err, "failed to fetch the top of book of derivative market %s", perpMarketId)
+		}
+		if marketInfo, err = chainClient.FetchChainPerpetualMarketInfo(ctx, perpMarketId); err != nil {
+			return struct{}{}, errors.Wrapf(err, "failed to fetch the perpetual market info of %s", perpMarketId)
+		}
+		if funding, err = chainClient.FetchChainPerpetualMarketFunding(ctx, perpMarketId); err != nil {
+			return struct{}{}, errors.Wrapf(err, "failed to fetch the perpetual market funding of %s", perpMarketId)
+		}
+		return struct{}{}, nil
+	})
+	if err != nil {
+		return SpotPerpQuote{}, err
+	}
+
+	if !validTOBPrice(spotTOB.BestBuyPrice) || !validTOBPrice(spotTOB.BestSellPrice) {
+		return SpotPerpQuote{}, errors.Errorf("spot market %s has no bids or asks", spotMarketId)
+	}
+	if !validTOBPrice(perpTOB.BestBuyPrice) || !validTOBPrice(perpTOB.BestSellPrice) {
+		return SpotPerpQuote{}, errors.Errorf("derivative market %s has no bids or asks", perpMarketId)
+	}
+
+	return SpotPerpQuote{
+		Height:                     height,
+		SpotMarket:                 spotMarket,
+		PerpetualMarket:            perpMarket,
+		SpotBestBid:                spotMarket.PriceFromChainFormat(*spotTOB.BestBuyPrice),
+		SpotBestAsk:                spotMarket.PriceFromChainFormat(*spotTOB.BestSellPrice),
+		PerpBestBid:                perpMarket.PriceFromChainFormat(*perpTOB.BestBuyPrice),
+		PerpBestAsk:                perpMarket.PriceFromChainFormat(*perpTOB.BestSellPrice),
+		EstimatedHourlyFundingRate: EstimateHourlyFundingRate(marketInfo.Info, funding.State),
+		NextFundingTimestamp:       marketInfo.Info.NextFundingTimestamp,
+	}, nil
+}
+
+// EstimateHourlyFundingRate applies the chain funding formula (the cumulative premium averaged over a day plus the
+// hourly interest rate, capped to the market funding rate cap) to the premium accumulated in the current interval
+func EstimateHourlyFundingRate(marketInfo exchangetypes.PerpetualMarketInfo, funding exchangetypes.PerpetualMarketFunding) decimal.Decimal {
+	rate := marketInfo.HourlyInterestRate
+	if rate.IsNil() {
+		rate = sdk.ZeroDec()
+	}
+	if marketInfo.FundingInterval > 0 && !funding.CumulativePrice.IsNil() {
+		rate = funding.CumulativePrice.Quo(sdk.NewDec(marketInfo.FundingInterval).MulInt64(24)).Add(rate)
+	}
+
+	if rateCap := marketInfo.HourlyFundingRateCap; !rateCap.IsNil() && rateCap.IsPositive() {
+		if rate.GT(rateCap) {
+			rate = rateCap
+		} else if rate.LT(rateCap.Neg()) {
+			rate = rateCap.Neg()
+		}
+	}
+
+	return decimal.RequireFromString(rate.String())
+}
+
+func validTOBPrice(price *sdk.Dec) bool {
+	return price != nil && !price.IsNil() && price.IsPositive()
+}
@@ -0,0 +1,113 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/core"
+)
+
+const (
+	arbitrageSpotMarketId = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	arbitragePerpMarketId = "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+)
+
+type arbitrageTestChainClient struct {
+	MockChainClient
+}
+
+func (c *arbitrageTestChainClient) FetchSpotMidPriceAndTOB(ctx context.Context, marketId string) (*exchangetypes.QuerySpotMidPriceAndTOBResponse, error) {
+	return &exchangetypes.QuerySpotMidPriceAndTOBResponse{
+		BestBuyPrice:  riskDec("0.000000000024990000"),
+		BestSellPrice: riskDec("0.000000000025010000"),
+	}, nil
+}
+
+func (c *arbitrageTestChainClient) FetchDerivativeMidPriceAndTOB(ctx context.Context, marketId string) (*exchangetypes.QueryDerivativeMidPriceAndTOBResponse, error) {
+	return &exchangetypes.QueryDerivativeMidPriceAndTOBResponse{
+		BestBuyPrice:  riskDec("25040000"),
+		BestSellPrice: riskDec("25060000"),
+	}, nil
+}
+
+func (c *arbitrageTestChainClient) FetchChainPerpetualMarketInfo(ctx context.Context, marketId string) (*exchangetypes.QueryPerpetualMarketInfoResponse, error) {
+	return &exchangetypes.QueryPerpetualMarketInfoResponse{Info: exchangetypes.PerpetualMarketInfo{
+		MarketId:             marketId,
+		HourlyFundingRateCap: sdk.MustNewDecFromStr("0.000625"),
+		HourlyInterestRate:   sdk.MustNewDecFromStr("0.00000416666"),
+		NextFundingTimestamp: 1700000000,
+		FundingInterval:      3600,
+	}}, nil
+}
+
+func (c *arbitrageTestChainClient) FetchChainPerpetualMarketFunding(ctx context.Context, marketId string) (*exchangetypes.QueryPerpetualMarketFundingResponse, error) {
+	return &exchangetypes.QueryPerpetualMarketFundingResponse{State: exchangetypes.PerpetualMarketFunding{
+		CumulativeFunding: sdk.ZeroDec(),
+		CumulativePrice:   sdk.MustNewDecFromStr("8.64"),
+	}}, nil
+}
+
+func arbitrageTestAssistant() MarketsAssistant {
+	assistant := newMarketsAssistant()
+	usdt := core.Token{Symbol: "USDT", Denom: "peggy0xdAC17F958D2ee523a2206206994597C13D831ec7", Decimals: 6}
+	assistant.spotMarkets[arbitrageSpotMarketId] = core.SpotMarket{
+		Id:         arbitrageSpotMarketId,
+		Ticker:     "INJ/USDT",
+		BaseToken:  core.Token{Symbol: "INJ", Denom: "inj", Decimals: 18},
+		QuoteToken: usdt,
+	}
+	assistant.derivativeMarkets[arbitragePerpMarketId] = core.DerivativeMarket{
+		Id:         arbitragePerpMarketId,
+		Ticker:     "INJ/USDT PERP",
+		QuoteToken: usdt,
+	}
+	return assistant
+}
+
+func TestFindPerpetualMarketForSpot(t *testing.T) {
+	assistant := arbitrageTestAssistant()
+
+	market, found := FindPerpetualMarketForSpot(assistant, arbitrageSpotMarketId)
+	assert.True(t, found)
+	assert.Equal(t, arbitragePerpMarketId, market.Id)
+
+	_, found = FindPerpetualMarketForSpot(assistant, "0x1")
+	assert.False(t, found)
+}
+
+func TestFetchSpotPerpQuote(t *testing.T) {
+	quote, err := FetchSpotPerpQuote(context.Background(), &arbitrageTestChainClient{}, arbitrageTestAssistant(), arbitrageSpotMarketId, arbitragePerpMarketId)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "24.99", quote.SpotBestBid.String())
+	assert.Equal(t, "25.01", quote.SpotBestAsk.String())
+	assert.Equal(t, "25.04", quote.PerpBestBid.String())
+	assert.Equal(t, "25.06", quote.PerpBestAsk.String())
+	// (25.05 - 25) / 25 * 10000
+	assert.Equal(t, "20", quote.BasisBps().String())
+	// (25.04 - 25.01) / 25.01 * 10000
+	assert.Equal(t, "11.995", quote.EntryBasisBps().Round(3).String())
+
+	// 8.64 / (3600 * 24) + 0.00000416666
+	assert.Equal(t, "0.00010416666", quote.EstimatedHourlyFundingRate.String())
+	assert.Equal(t, int64(1700000000), quote.NextFundingTimestamp)
+	// 20 bps of basis plus 8 hours of funding
+	assert.True(t, quote.CarryBps(8*time.Hour).Sub(decimal.RequireFromString("28.3333328")).Abs().LessThan(decimal.RequireFromString("0.0000001")))
+}
+
+func TestEstimateHourlyFundingRateIsCapped(t *testing.T) {
+	marketInfo := exchangetypes.PerpetualMarketInfo{
+		HourlyFundingRateCap: sdk.MustNewDecFromStr("0.000625"),
+		HourlyInterestRate:   sdk.ZeroDec(),
+		FundingInterval:      3600,
+	}
+
+	rate := EstimateHourlyFundingRate(marketInfo, exchangetypes.PerpetualMarketFunding{CumulativePrice: sdk.MustNewDecFromStr("-500")})
+	assert.Equal(t, "-0.000625", rate.String())
+}
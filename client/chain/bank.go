@@ -0,0 +1,131 @@
+package chain
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	tokenfactorytypes "github.com/InjectiveLabs/sdk-go/chain/tokenfactory/types"
+	"github.com/InjectiveLabs/sdk-go/client"
+	"github.com/InjectiveLabs/sdk-go/client/core"
+)
+
+const (
+	peggyDenomPrefix        = "peggy"
+	tokenfactoryDenomPrefix = "factory/"
+)
+
+// INJToken is the token definition of the chain native denom
+var INJToken = core.Token{
+	Name:     "Injective Protocol",
+	Symbol:   "INJ",
+	Denom:    client.InjDenom,
+	Decimals: 18,
+}
+
+// ValidateDenom checks the denom is valid for the bank module and, for bridged and tokenfactory denoms, that it
+// follows the expected format (peggy0x{erc20 address}, ibc/{hash} or factory/{creator}/{subdenom})
+func ValidateDenom(denom string) error {
+	if err := sdk.ValidateDenom(denom); err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasPrefix(denom, peggyDenomPrefix):
+		address := strings.TrimPrefix(denom, peggyDenomPrefix)
+		if !ethcommon.IsHexAddress(address) || !strings.HasPrefix(address, "0x") {
+			return errors.Errorf("invalid peggy denom %s: expected peggy0x followed by the ERC20 contract address", denom)
+		}
+	case strings.HasPrefix(denom, ibctransfertypes.DenomPrefix+"/"):
+		if _, err := IBCDenomHash(denom); err != nil {
+			return errors.Wrapf(err, "invalid IBC denom %s", denom)
+		}
+	case strings.HasPrefix(denom, tokenfactoryDenomPrefix):
+		if _, _, err := tokenfactorytypes.DeconstructDenom(denom); err != nil {
+			return errors.Wrapf(err, "invalid tokenfactory denom %s", denom)
+		}
+	}
+
+	return nil
+}
+
+// ChainFormattedCoin converts a human readable amount of the token into a coin in the chain base unit.
+// Amounts with more decimals than the token supports are rejected instead of being truncated
+func ChainFormattedCoin(token core.Token, humanReadableAmount decimal.Decimal) (sdk.Coin, error) {
+	if err := ValidateDenom(token.Denom); err != nil {
+		return sdk.Coin{}, err
+	}
+	if !humanReadableAmount.IsPositive() {
+		return sdk.Coin{}, errors.Errorf("amount must be positive, got %s", humanReadableAmount.String())
+	}
+
+	chainAmount := token.ChainFormattedValue(humanReadableAmount)
+	if !chainAmount.Equal(chainAmount.Truncate(0)) {
+		return sdk.Coin{}, errors.Errorf("amount %s has more than %d decimals supported by %s", humanReadableAmount.String(), token.Decimals, token.Symbol)
+	}
+
+	amount, ok := sdk.NewIntFromString(chainAmount.StringFixed(0))
+	if !ok {
+		return sdk.Coin{}, errors.Errorf("failed to convert amount %s to integer", chainAmount.String())
+	}
+
+	return sdk.NewCoin(token.Denom, amount), nil
+}
+
+func validateCoinsDenoms(coins sdk.Coins) error {
+	for _, coin := range coins {
+		if err := ValidateDenom(coin.Denom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewMsgSend builds a bank MsgSend, validating the addresses, amounts and denoms
+func NewMsgSend(fromAddress string, toAddress string, amount sdk.Coins) (*banktypes.MsgSend, error) {
+	if err := validateCoinsDenoms(amount); err != nil {
+		return nil, err
+	}
+
+	msg := &banktypes.MsgSend{
+		FromAddress: fromAddress,
+		ToAddress:   toAddress,
+		Amount:      amount,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// NewMsgMultiSend builds a bank MsgMultiSend from a single sender to several recipients.
+// The input is calculated as the sum of all the outputs
+func NewMsgMultiSend(fromAddress string, outputs []banktypes.Output) (*banktypes.MsgMultiSend, error) {
+	if len(outputs) == 0 {
+		return nil, errors.New("multi send requires at least one output")
+	}
+
+	total := sdk.NewCoins()
+	for _, output := range outputs {
+		if err := validateCoinsDenoms(output.Coins); err != nil {
+			return nil, err
+		}
+		total = total.Add(output.Coins...)
+	}
+
+	msg := &banktypes.MsgMultiSend{
+		Inputs:  []banktypes.Input{{Address: fromAddress, Coins: total}},
+		Outputs: outputs,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
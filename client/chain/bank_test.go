@@ -0,0 +1,72 @@
+package chain
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/core"
+)
+
+func TestValidateDenomFormats(t *testing.T) {
+	validDenoms := []string{
+		"inj",
+		"peggy0xdAC17F958D2ee523a2206206994597C13D831ec7",
+		"ibc/C4CFF46FD6DE35CA4CF4CE031E643C8FDC9BA4B99AE598E9B0ED98FE3A2319F9",
+		"factory/inj17vytdwqczqz72j65saukplrktd4gyfme5agf6c/atom",
+	}
+	for _, denom := range validDenoms {
+		assert.NoError(t, ValidateDenom(denom), denom)
+	}
+
+	invalidDenoms := []string{
+		"",
+		"peggy0xdAC17F958D2ee523a22062",
+		"peggydAC17F958D2ee523a2206206994597C13D831ec7",
+		"ibc/notahash",
+		"factory/atom",
+	}
+	for _, denom := range invalidDenoms {
+		assert.Error(t, ValidateDenom(denom), denom)
+	}
+}
+
+func TestChainFormattedCoinIsDecimalAware(t *testing.T) {
+	usdt := core.Token{Symbol: "USDT", Denom: "peggy0xdAC17F958D2ee523a2206206994597C13D831ec7", Decimals: 6}
+
+	coin, err := ChainFormattedCoin(usdt, decimal.RequireFromString("12.345678"))
+	assert.NoError(t, err)
+	assert.Equal(t, sdk.NewInt64Coin(usdt.Denom, 12345678), coin)
+
+	coin, err = ChainFormattedCoin(INJToken, decimal.RequireFromString("1.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000000000000000inj", coin.String())
+
+	_, err = ChainFormattedCoin(usdt, decimal.RequireFromString("0.0000001"))
+	assert.Error(t, err)
+
+	_, err = ChainFormattedCoin(usdt, decimal.Zero)
+	assert.Error(t, err)
+}
+
+func TestNewMsgMultiSendSumsOutputs(t *testing.T) {
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	outputs := []banktypes.Output{
+		{Address: "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r", Coins: sdk.NewCoins(sdk.NewInt64Coin("inj", 100))},
+		{Address: "inj1ady3s7whq30l4fx8sj3x6muv5mx4dfdlcpv8n7", Coins: sdk.NewCoins(sdk.NewInt64Coin("inj", 50))},
+	}
+
+	msg, err := NewMsgMultiSend(sender, outputs)
+	assert.NoError(t, err)
+	assert.Len(t, msg.Inputs, 1)
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("inj", 150)), msg.Inputs[0].Coins)
+
+	_, err = NewMsgMultiSend(sender, nil)
+	assert.Error(t, err)
+
+	_, err = NewMsgSend(sender, outputs[0].Address, sdk.Coins{sdk.NewInt64Coin("peggy0x1234", 1)})
+	assert.Error(t, err)
+}
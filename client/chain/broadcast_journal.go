@@ -0,0 +1,322 @@
+package chain
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+type JournalEntryStatus string
+
+const (
+	// JournalPending is a tx signed and sent to the node, without a known result
+	JournalPending JournalEntryStatus = "pending"
+	// JournalCommitted is a tx included in a block and executed successfully
+	JournalCommitted JournalEntryStatus = "committed"
+	// JournalFailed is a tx rejected by the node or included in a block with an execution error
+	JournalFailed JournalEntryStatus = "failed"
+	// JournalDropped is a pending tx not found in the chain after the recovery timeout
+	JournalDropped JournalEntryStatus = "dropped"
+)
+
+type JournalEntry struct {
+	TxHash    string             `json:"tx_hash"`
+	TxBytes   []byte             `json:"tx_bytes,omitempty"`
+	Sequence  uint64             `json:"sequence"`
+	Status    JournalEntryStatus `json:"status"`
+	Height    int64              `json:"height,omitempty"`
+	Codespace string             `json:"codespace,omitempty"`
+	Code      uint32             `json:"code,omitempty"`
+	Log       string             `json:"log,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+func (e JournalEntry) IsFinal() bool {
+	return e.Status != JournalPending
+}
+
+// FileBroadcastJournal is a write-ahead journal of the broadcasted txs stored in an append only file, with one JSON
+// entry per line. Every update is synced to disk before returning, and the latest line of each tx wins when the file
+// is loaded again
+type FileBroadcastJournal struct {
+	mux     sync.Mutex
+	path    string
+	file    *os.File
+	entries map[string]*JournalEntry
+	order   []string
+	clock   clock.Clock
+}
+
+// OpenFileBroadcastJournal loads the journal stored in path, creating the file if it does not exist
+func OpenFileBroadcastJournal(path string) (*FileBroadcastJournal, error) {
+	journal := &FileBroadcastJournal{
+		path:    path,
+		entries: make(map[string]*JournalEntry),
+		clock:   clock.Real(),
+	}
+
+	if err := journal.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open broadcast journal %s", path)
+	}
+	journal.file = file
+
+	return journal, nil
+}
+
+// SetClock sets the clock of the entry times and of the recovery drop delay
+func (j *FileBroadcastJournal) SetClock(c clock.Clock) {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	j.clock = clock.OrReal(c)
+}
+
+// RecordBroadcast records a tx as pending. It is called before sending the tx to the node
+func (j *FileBroadcastJournal) RecordBroadcast(txHash string, txBytes []byte, sequence uint64) error {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	now := j.clock.Now()
+	entry := &JournalEntry{
+		TxHash:    txHash,
+		TxBytes:   txBytes,
+		Sequence:  sequence,
+		Status:    JournalPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return j.write(entry)
+}
+
+// RecordResult records the result of a tx. A response without height is the CheckTx result: the tx is only marked
+// as failed if the node rejected it
+func (j *FileBroadcastJournal) RecordResult(txHash string, res *sdk.TxResponse) error {
+	if res == nil {
+		return nil
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	entry := j.entryCopy(txHash)
+	entry.Height = res.Height
+	entry.Codespace = res.Codespace
+	entry.Code = res.Code
+	entry.Log = res.RawLog
+
+	switch {
+	case res.Code != 0:
+		entry.Status = JournalFailed
+	case res.Height > 0:
+		entry.Status = JournalCommitted
+	default:
+		return nil
+	}
+
+	entry.UpdatedAt = j.clock.Now()
+	return j.write(entry)
+}
+
+// Entry returns the latest state of the tx
+func (j *FileBroadcastJournal) Entry(txHash string) (JournalEntry, bool) {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	entry, found := j.entries[txHash]
+	if !found {
+		return JournalEntry{}, false
+	}
+	return *entry, true
+}
+
+// PendingEntries returns the txs without a known result, in broadcast order
+func (j *FileBroadcastJournal) PendingEntries() []JournalEntry {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	var pending []JournalEntry
+	for _, txHash := range j.order {
+		if entry := j.entries[txHash]; !entry.IsFinal() {
+			pending = append(pending, *entry)
+		}
+	}
+	return pending
+}
+
+// Recover queries the chain for every pending tx and records its result. Pending txs not found in the chain and
+// broadcasted more than dropAfter ago are marked as dropped (a zero dropAfter keeps them pending).
+// It returns the entries resolved by the recovery
+func (j *FileBroadcastJournal) Recover(ctx context.Context, chainClient ChainClient, dropAfter time.Duration) ([]JournalEntry, error) {
+	var resolved []JournalEntry
+
+	for _, entry := range j.PendingEntries() {
+		res, err := chainClient.GetTx(ctx, entry.TxHash)
+		if err != nil {
+			if status.Code(err) != codes.NotFound {
+				return resolved, errors.Wrapf(err, "failed to get tx %s", entry.TxHash)
+			}
+			if dropAfter <= 0 || j.clock.Now().Sub(entry.CreatedAt) < dropAfter {
+				continue
+			}
+
+			if err := j.markDropped(entry.TxHash); err != nil {
+				return resolved, err
+			}
+		} else if err := j.RecordResult(entry.TxHash, res.TxResponse); err != nil {
+			return resolved, err
+		}
+
+		if updated, found := j.Entry(entry.TxHash); found && updated.IsFinal() {
+			resolved = append(resolved, updated)
+		}
+	}
+
+	return resolved, nil
+}
+
+// Compact rewrites the journal file keeping only the pending entries
+func (j *FileBroadcastJournal) Compact() error {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	tmpPath := j.path + ".tmp"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", tmpPath)
+	}
+
+	var order []string
+	entries := make(map[string]*JournalEntry)
+	encoder := json.NewEncoder(tmpFile)
+	for _, txHash := range j.order {
+		entry := j.entries[txHash]
+		if entry.IsFinal() {
+			continue
+		}
+		if err := encoder.Encode(entry); err != nil {
+			tmpFile.Close()
+			return errors.Wrap(err, "failed to write the compacted broadcast journal")
+		}
+		order = append(order, txHash)
+		entries[txHash] = entry
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "failed to sync the compacted broadcast journal")
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "failed to close the compacted broadcast journal")
+	}
+
+	j.file.Close()
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return errors.Wrapf(err, "failed to replace broadcast journal %s", j.path)
+	}
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open broadcast journal %s", j.path)
+	}
+
+	j.file = file
+	j.order = order
+	j.entries = entries
+	return nil
+}
+
+func (j *FileBroadcastJournal) Close() error {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	return j.file.Close()
+}
+
+func (j *FileBroadcastJournal) markDropped(txHash string) error {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	entry := j.entryCopy(txHash)
+	entry.Status = JournalDropped
+	entry.UpdatedAt = j.clock.Now()
+	return j.write(entry)
+}
+
+// entryCopy must be called holding the mutex
+func (j *FileBroadcastJournal) entryCopy(txHash string) *JournalEntry {
+	if entry, found := j.entries[txHash]; found {
+		entryCopy := *entry
+		return &entryCopy
+	}
+
+	now := j.clock.Now()
+	return &JournalEntry{TxHash: txHash, CreatedAt: now}
+}
+
+// write must be called holding the mutex
+func (j *FileBroadcastJournal) write(entry *JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode broadcast journal entry")
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return errors.Wrapf(err, "failed to write broadcast journal entry of tx %s", entry.TxHash)
+	}
+	if err := j.file.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync broadcast journal")
+	}
+
+	j.apply(entry)
+	return nil
+}
+
+func (j *FileBroadcastJournal) apply(entry *JournalEntry) {
+	if _, found := j.entries[entry.TxHash]; !found {
+		j.order = append(j.order, entry.TxHash)
+	}
+	j.entries[entry.TxHash] = entry
+}
+
+func (j *FileBroadcastJournal) load() error {
+	file, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to open broadcast journal %s", j.path)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// entries include the tx bytes
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// the last line can be incomplete if the process stopped while writing it
+			continue
+		}
+		j.apply(&entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "failed to read broadcast journal %s", j.path)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package chain
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+type journalTestChainClient struct {
+	MockChainClient
+	txs map[string]*sdk.TxResponse
+}
+
+func (c *journalTestChainClient) GetTx(ctx context.Context, txHash string) (*txtypes.GetTxResponse, error) {
+	res, found := c.txs[txHash]
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "tx not found: %s", txHash)
+	}
+	return &txtypes.GetTxResponse{TxResponse: res}, nil
+}
+
+func TestFileBroadcastJournalPersistsEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := OpenFileBroadcastJournal(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, journal.RecordBroadcast("AAAA", []byte{1, 2, 3}, 7))
+	assert.NoError(t, journal.RecordBroadcast("BBBB", []byte{4, 5, 6}, 8))
+	assert.NoError(t, journal.RecordBroadcast("CCCC", []byte{7, 8, 9}, 9))
+	// the CheckTx result of an accepted tx keeps it pending
+	assert.NoError(t, journal.RecordResult("AAAA", &sdk.TxResponse{TxHash: "AAAA"}))
+	assert.NoError(t, journal.RecordResult("BBBB", &sdk.TxResponse{TxHash: "BBBB", Height: 10}))
+	assert.NoError(t, journal.RecordResult("CCCC", &sdk.TxResponse{TxHash: "CCCC", Codespace: "sdk", Code: 32}))
+	assert.NoError(t, journal.Close())
+
+	// an incomplete last line is ignored
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, err = file.WriteString(`{"tx_hash":"DDDD","sta`)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	journal, err = OpenFileBroadcastJournal(path)
+	assert.NoError(t, err)
+	defer journal.Close()
+
+	pending := journal.PendingEntries()
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "AAAA", pending[0].TxHash)
+	assert.Equal(t, []byte{1, 2, 3}, pending[0].TxBytes)
+	assert.Equal(t, uint64(7), pending[0].Sequence)
+
+	entry, found := journal.Entry("BBBB")
+	assert.True(t, found)
+	assert.Equal(t, JournalCommitted, entry.Status)
+	entry, _ = journal.Entry("CCCC")
+	assert.Equal(t, JournalFailed, entry.Status)
+	assert.Equal(t, uint32(32), entry.Code)
+
+	assert.NoError(t, journal.Compact())
+	_, found = journal.Entry("BBBB")
+	assert.False(t, found)
+	assert.NoError(t, journal.RecordResult("AAAA", &sdk.TxResponse{TxHash: "AAAA", Height: 11}))
+	assert.Empty(t, journal.PendingEntries())
+}
+
+func TestFileBroadcastJournalRecover(t *testing.T) {
+	journal, err := OpenFileBroadcastJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	assert.NoError(t, err)
+	defer journal.Close()
+
+	fakeClock := clock.NewFake(time.Date(2023, 11, 14, 12, 0, 0, 0, time.UTC))
+	journal.SetClock(fakeClock)
+	assert.NoError(t, journal.RecordBroadcast("AAAA", nil, 1))
+	assert.NoError(t, journal.RecordBroadcast("BBBB", nil, 2))
+	assert.NoError(t, journal.RecordBroadcast("CCCC", nil, 3))
+
+	chainClient := &journalTestChainClient{txs: map[string]*sdk.TxResponse{
+		"AAAA": {TxHash: "AAAA", Height: 5},
+		"BBBB": {TxHash: "BBBB", Height: 5, Codespace: "exchange", Code: 59},
+	}}
+
+	resolved, err := journal.Recover(context.Background(), chainClient, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, resolved, 2)
+	assert.Equal(t, JournalCommitted, resolved[0].Status)
+	assert.Equal(t, JournalFailed, resolved[1].Status)
+	assert.Len(t, journal.PendingEntries(), 1)
+
+	fakeClock.Advance(2 * time.Minute)
+	resolved, err = journal.Recover(context.Background(), chainClient, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, resolved, 1)
+	assert.Equal(t, "CCCC", resolved[0].TxHash)
+	assert.Equal(t, JournalDropped, resolved[0].Status)
+}
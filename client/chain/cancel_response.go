@@ -0,0 +1,241 @@
+package chain
+
+import (
+	"encoding/hex"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// CancelledOrder is an order removed from the orderbook by a cancel msg, as reported by the cancel events
+type CancelledOrder struct {
+	MarketId     string
+	SubaccountId string
+	OrderHash    string
+	Cid          string
+	IsBuy        bool
+	IsDerivative bool
+	// Price and UnfilledQuantity are in chain format. UnfilledQuantity is the quantity that was still open when the
+	// order was cancelled; for spot orders it is the amount released (in base asset for sells and, multiplied by the
+	// price, in quote asset for buys)
+	Price            sdk.Dec
+	UnfilledQuantity sdk.Dec
+	// FreedMargin is the margin released by a derivative order cancel, proportional to the unfilled quantity. It is
+	// zero for spot and reduce only orders
+	FreedMargin sdk.Dec
+}
+
+// CancelResponse is the typed result of a tx with cancel msgs
+type CancelResponse struct {
+	TxHash string
+	Height int64
+	// Success has the per order result of each cancel msg, in msg order. Single order cancels have one entry
+	Success [][]bool
+	// CancelledOrders has the orders actually cancelled. It is only available when the TxResponse includes the
+	// events, i.e. for committed txs and not for sync broadcast responses
+	CancelledOrders []CancelledOrder
+}
+
+// AllSucceeded returns true if every cancel in the tx removed its order
+func (r *CancelResponse) AllSucceeded() bool {
+	for _, results := range r.Success {
+		for _, success := range results {
+			if !success {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type cancelResponseDecoder func(data []byte) ([]bool, error)
+
+// cancelResponseDecoders has the decoders of the msg responses with cancel results, by type URL
+var cancelResponseDecoders = map[string]cancelResponseDecoder{
+	msgResponseTypeURL(&exchangetypes.MsgCancelSpotOrderResponse{}): func(data []byte) ([]bool, error) {
+		return []bool{true}, (&exchangetypes.MsgCancelSpotOrderResponse{}).Unmarshal(data)
+	},
+	msgResponseTypeURL(&exchangetypes.MsgCancelDerivativeOrderResponse{}): func(data []byte) ([]bool, error) {
+		return []bool{true}, (&exchangetypes.MsgCancelDerivativeOrderResponse{}).Unmarshal(data)
+	},
+	msgResponseTypeURL(&exchangetypes.MsgCancelBinaryOptionsOrderResponse{}): func(data []byte) ([]bool, error) {
+		return []bool{true}, (&exchangetypes.MsgCancelBinaryOptionsOrderResponse{}).Unmarshal(data)
+	},
+	msgResponseTypeURL(&exchangetypes.MsgBatchCancelSpotOrdersResponse{}): func(data []byte) ([]bool, error) {
+		response := exchangetypes.MsgBatchCancelSpotOrdersResponse{}
+		err := response.Unmarshal(data)
+		return response.Success, err
+	},
+	msgResponseTypeURL(&exchangetypes.MsgBatchCancelDerivativeOrdersResponse{}): func(data []byte) ([]bool, error) {
+		response := exchangetypes.MsgBatchCancelDerivativeOrdersResponse{}
+		err := response.Unmarshal(data)
+		return response.Success, err
+	},
+	msgResponseTypeURL(&exchangetypes.MsgBatchCancelBinaryOptionsOrdersResponse{}): func(data []byte) ([]bool, error) {
+		response := exchangetypes.MsgBatchCancelBinaryOptionsOrdersResponse{}
+		err := response.Unmarshal(data)
+		return response.Success, err
+	},
+	msgResponseTypeURL(&exchangetypes.MsgBatchUpdateOrdersResponse{}): func(data []byte) ([]bool, error) {
+		response := exchangetypes.MsgBatchUpdateOrdersResponse{}
+		err := response.Unmarshal(data)
+		success := make([]bool, 0, len(response.SpotCancelSuccess)+len(response.DerivativeCancelSuccess)+len(response.BinaryOptionsCancelSuccess))
+		success = append(success, response.SpotCancelSuccess...)
+		success = append(success, response.DerivativeCancelSuccess...)
+		success = append(success, response.BinaryOptionsCancelSuccess...)
+		return success, err
+	},
+}
+
+// hasCancelMsgs returns true if any of the msgs can cancel orders
+func hasCancelMsgs(msgs []sdk.Msg) bool {
+	for _, msg := range msgs {
+		switch msg.(type) {
+		case *exchangetypes.MsgCancelSpotOrder,
+			*exchangetypes.MsgCancelDerivativeOrder,
+			*exchangetypes.MsgCancelBinaryOptionsOrder,
+			*exchangetypes.MsgBatchCancelSpotOrders,
+			*exchangetypes.MsgBatchCancelDerivativeOrders,
+			*exchangetypes.MsgBatchCancelBinaryOptionsOrders,
+			*exchangetypes.MsgBatchUpdateOrders:
+			return true
+		}
+	}
+	return false
+}
+
+func msgResponseTypeURL(msgResponse proto.Message) string {
+	return "/" + proto.MessageName(msgResponse)
+}
+
+// DecodeCancelResponse decodes the msg responses and cancel events of a successful tx with cancel msgs. Responses of
+// msgs that do not cancel orders are ignored
+func DecodeCancelResponse(txResponse *sdk.TxResponse) (*CancelResponse, error) {
+	if txResponse == nil {
+		return nil, errors.New("the tx response is empty")
+	}
+	if err := NewTxError(txResponse); err != nil {
+		return nil, err
+	}
+
+	response := &CancelResponse{
+		TxHash: txResponse.TxHash,
+		Height: txResponse.Height,
+	}
+
+	msgData, err := decodeTxMsgData(txResponse.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the msg responses of tx %s", txResponse.TxHash)
+	}
+	for _, msgResponse := range msgData.MsgResponses {
+		decoder, found := cancelResponseDecoders[msgResponse.TypeUrl]
+		if !found {
+			continue
+		}
+		success, err := decoder(msgResponse.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s in tx %s", msgResponse.TypeUrl, txResponse.TxHash)
+		}
+		response.Success = append(response.Success, success)
+	}
+
+	for _, event := range txResponse.Events {
+		cancelledOrder, found, err := cancelledOrderFromEvent(event)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the %s event in tx %s", event.Type, txResponse.TxHash)
+		}
+		if found {
+			response.CancelledOrders = append(response.CancelledOrders, cancelledOrder)
+		}
+	}
+
+	return response, nil
+}
+
+func decodeTxMsgData(data string) (*sdk.TxMsgData, error) {
+	msgData := &sdk.TxMsgData{}
+	if data == "" {
+		return msgData, nil
+	}
+	dataBytes, err := hex.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := msgData.Unmarshal(dataBytes); err != nil {
+		return nil, err
+	}
+	return msgData, nil
+}
+
+var (
+	cancelSpotOrderEventType       = proto.MessageName(&exchangetypes.EventCancelSpotOrder{})
+	cancelDerivativeOrderEventType = proto.MessageName(&exchangetypes.EventCancelDerivativeOrder{})
+)
+
+func cancelledOrderFromEvent(event abci.Event) (CancelledOrder, bool, error) {
+	if event.Type != cancelSpotOrderEventType && event.Type != cancelDerivativeOrderEventType {
+		return CancelledOrder{}, false, nil
+	}
+
+	typedEvent, err := sdk.ParseTypedEvent(event)
+	if err != nil {
+		return CancelledOrder{}, false, err
+	}
+
+	switch e := typedEvent.(type) {
+	case *exchangetypes.EventCancelSpotOrder:
+		return CancelledOrder{
+			MarketId:         e.MarketId,
+			SubaccountId:     e.Order.OrderInfo.SubaccountId,
+			OrderHash:        eth.BytesToHash(e.Order.OrderHash).Hex(),
+			Cid:              e.Order.OrderInfo.Cid,
+			IsBuy:            e.Order.OrderType.IsBuy(),
+			Price:            e.Order.OrderInfo.Price,
+			UnfilledQuantity: e.Order.Fillable,
+			FreedMargin:      sdk.ZeroDec(),
+		}, true, nil
+	case *exchangetypes.EventCancelDerivativeOrder:
+		if e.IsLimitCancel && e.LimitOrder != nil {
+			order := e.LimitOrder
+			return CancelledOrder{
+				MarketId:         e.MarketId,
+				SubaccountId:     order.OrderInfo.SubaccountId,
+				OrderHash:        eth.BytesToHash(order.OrderHash).Hex(),
+				Cid:              order.OrderInfo.Cid,
+				IsBuy:            order.OrderType.IsBuy(),
+				IsDerivative:     true,
+				Price:            order.OrderInfo.Price,
+				UnfilledQuantity: order.Fillable,
+				FreedMargin:      proportionalMargin(order.Margin, order.Fillable, order.OrderInfo.Quantity),
+			}, true, nil
+		}
+		if e.MarketOrderCancel != nil && e.MarketOrderCancel.MarketOrder != nil {
+			order := e.MarketOrderCancel.MarketOrder
+			return CancelledOrder{
+				MarketId:         e.MarketId,
+				SubaccountId:     order.OrderInfo.SubaccountId,
+				OrderHash:        eth.BytesToHash(order.OrderHash).Hex(),
+				Cid:              order.OrderInfo.Cid,
+				IsBuy:            order.OrderType.IsBuy(),
+				IsDerivative:     true,
+				Price:            order.OrderInfo.Price,
+				UnfilledQuantity: e.MarketOrderCancel.CancelQuantity,
+				FreedMargin:      proportionalMargin(order.Margin, e.MarketOrderCancel.CancelQuantity, order.OrderInfo.Quantity),
+			}, true, nil
+		}
+	}
+
+	return CancelledOrder{}, false, nil
+}
+
+func proportionalMargin(margin sdk.Dec, unfilledQuantity sdk.Dec, quantity sdk.Dec) sdk.Dec {
+	if margin.IsNil() || unfilledQuantity.IsNil() || quantity.IsNil() || !quantity.IsPositive() {
+		return sdk.ZeroDec()
+	}
+	return margin.Mul(unfilledQuantity).Quo(quantity)
+}
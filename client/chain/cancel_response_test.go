@@ -0,0 +1,130 @@
+package chain
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	abci "github.com/cometbft/cometbft/abci/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/gogoproto/proto"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func cancelTestTxResponse(t *testing.T, msgResponses []*codectypes.Any, typedEvents ...proto.Message) *sdk.TxResponse {
+	msgData := sdk.TxMsgData{MsgResponses: msgResponses}
+	data, err := msgData.Marshal()
+	assert.NoError(t, err)
+
+	var events []abci.Event
+	for _, typedEvent := range typedEvents {
+		event, err := sdk.TypedEventToEvent(typedEvent)
+		assert.NoError(t, err)
+		events = append(events, abci.Event(event))
+	}
+
+	return &sdk.TxResponse{
+		TxHash: "ABCD",
+		Height: 100,
+		Data:   strings.ToUpper(hex.EncodeToString(data)),
+		Events: events,
+	}
+}
+
+func TestDecodeCancelResponse(t *testing.T) {
+	batchResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgBatchUpdateOrdersResponse{
+		SpotCancelSuccess:       []bool{true},
+		DerivativeCancelSuccess: []bool{true, false},
+	})
+	assert.NoError(t, err)
+	cancelResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgCancelDerivativeOrderResponse{})
+	assert.NoError(t, err)
+	otherResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgDepositResponse{})
+	assert.NoError(t, err)
+
+	spotOrderHash := eth.HexToHash("0x01")
+	derivativeOrderHash := eth.HexToHash("0x02")
+	txResponse := cancelTestTxResponse(t,
+		[]*codectypes.Any{batchResponse, otherResponse, cancelResponse},
+		&exchangetypes.EventCancelSpotOrder{
+			MarketId: riskSpotMarketId,
+			Order: exchangetypes.SpotLimitOrder{
+				OrderInfo: exchangetypes.OrderInfo{SubaccountId: riskSubaccountId, Price: sdk.MustNewDecFromStr("2"), Quantity: sdk.MustNewDecFromStr("10"), Cid: "spot"},
+				OrderType: exchangetypes.OrderType_BUY,
+				Fillable:  sdk.MustNewDecFromStr("4"),
+				OrderHash: spotOrderHash.Bytes(),
+			},
+		},
+		&exchangetypes.EventCancelDerivativeOrder{
+			MarketId:      riskDerivativeMarketId,
+			IsLimitCancel: true,
+			LimitOrder: &exchangetypes.DerivativeLimitOrder{
+				OrderInfo: exchangetypes.OrderInfo{SubaccountId: riskSubaccountId, Price: sdk.MustNewDecFromStr("100"), Quantity: sdk.MustNewDecFromStr("2")},
+				OrderType: exchangetypes.OrderType_SELL,
+				Margin:    sdk.MustNewDecFromStr("50"),
+				Fillable:  sdk.MustNewDecFromStr("0.5"),
+				OrderHash: derivativeOrderHash.Bytes(),
+			},
+		},
+	)
+
+	response, err := DecodeCancelResponse(txResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, "ABCD", response.TxHash)
+	assert.Equal(t, int64(100), response.Height)
+	assert.Equal(t, [][]bool{{true, true, false}, {true}}, response.Success)
+	assert.False(t, response.AllSucceeded())
+
+	assert.Len(t, response.CancelledOrders, 2)
+	spotOrder := response.CancelledOrders[0]
+	assert.Equal(t, spotOrderHash.Hex(), spotOrder.OrderHash)
+	assert.Equal(t, "spot", spotOrder.Cid)
+	assert.True(t, spotOrder.IsBuy)
+	assert.False(t, spotOrder.IsDerivative)
+	assert.Equal(t, sdk.MustNewDecFromStr("4"), spotOrder.UnfilledQuantity)
+	assert.True(t, spotOrder.FreedMargin.IsZero())
+
+	derivativeOrder := response.CancelledOrders[1]
+	assert.Equal(t, riskDerivativeMarketId, derivativeOrder.MarketId)
+	assert.Equal(t, derivativeOrderHash.Hex(), derivativeOrder.OrderHash)
+	assert.True(t, derivativeOrder.IsDerivative)
+	assert.False(t, derivativeOrder.IsBuy)
+	assert.Equal(t, sdk.MustNewDecFromStr("12.5"), derivativeOrder.FreedMargin)
+}
+
+func TestDecodeCancelResponseFailedTx(t *testing.T) {
+	_, err := DecodeCancelResponse(&sdk.TxResponse{TxHash: "ABCD", Codespace: "exchange", Code: 5, RawLog: "failed"})
+	assert.Error(t, err)
+
+	_, err = DecodeCancelResponse(nil)
+	assert.Error(t, err)
+
+	response, err := DecodeCancelResponse(&sdk.TxResponse{TxHash: "ABCD"})
+	assert.NoError(t, err)
+	assert.Empty(t, response.CancelledOrders)
+	assert.True(t, response.AllSucceeded())
+}
+
+func TestBroadcastCancelResultsAreDecoded(t *testing.T) {
+	c := &chainClient{logger: log.WithField("module", "test")}
+	batchResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgBatchUpdateOrdersResponse{SpotCancelSuccess: []bool{true, false}})
+	assert.NoError(t, err)
+	res := &txtypes.BroadcastTxResponse{TxResponse: cancelTestTxResponse(t, []*codectypes.Any{batchResponse})}
+
+	cancelMsgs := []sdk.Msg{&exchangetypes.MsgBatchUpdateOrders{}}
+	cancelResponse := c.decodeCancelResponse(res, cancelMsgs)
+	assert.Equal(t, "ABCD", cancelResponse.TxHash)
+	assert.Equal(t, [][]bool{{true, false}}, cancelResponse.Success)
+
+	// txs without cancel msgs and failed txs have no cancel results
+	assert.Nil(t, c.decodeCancelResponse(res, []sdk.Msg{&exchangetypes.MsgDeposit{}}))
+	res.TxResponse.Code = 5
+	res.TxResponse.Codespace = "exchange"
+	assert.Nil(t, c.decodeCancelResponse(res, cancelMsgs))
+}
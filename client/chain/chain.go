@@ -17,21 +17,27 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/types/query"
 
-	"google.golang.org/grpc/credentials/insecure"
-
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	log "github.com/InjectiveLabs/suplog"
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	tmtypes "github.com/cometbft/cometbft/types"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/tx"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/cosmos/gogoproto/proto"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	ibcchanneltypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
 	eth "github.com/ethereum/go-ethereum/common"
+	gethsigner "github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 	"google.golang.org/grpc"
@@ -41,6 +47,7 @@ import (
 	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
 	tokenfactorytypes "github.com/InjectiveLabs/sdk-go/chain/tokenfactory/types"
 	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/core"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 )
@@ -56,7 +63,6 @@ const (
 	msgCommitBatchSizeLimit          = 1024
 	msgCommitBatchTimeLimit          = 500 * time.Millisecond
 	defaultBroadcastStatusPoll       = 100 * time.Millisecond
-	defaultBroadcastTimeout          = 40 * time.Second
 	defaultTimeoutHeight             = 20
 	defaultTimeoutHeightSyncInterval = 10 * time.Second
 )
@@ -69,35 +75,41 @@ var (
 )
 
 type ChainClient interface {
+	OrderSubmitter
+	MarketQuerier
+	Streamer
+	BalanceQuerier
+
 	CanSignTransactions() bool
 	FromAddress() sdk.AccAddress
 	QueryClient() *grpc.ClientConn
 	ClientContext() client.Context
 	// return account number and sequence without increasing sequence
 	GetAccNonce() (accNum uint64, accSeq uint64)
+	// replaces the key signing the txs of the client without restarting it
+	RotateSigningKey(from string, options KeyRotationOptions) (*KeyRotation, error)
+	// waits until the sequence of the account on chain reaches sequence
+	AwaitAccountSequence(ctx context.Context, address sdk.AccAddress, sequence uint64) error
 
 	SimulateMsg(clientCtx client.Context, msgs ...sdk.Msg) (*txtypes.SimulateResponse, error)
-	AsyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error)
-	SyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error)
 
 	// Build signed tx with given accNum and accSeq, useful for offline siging
 	// If simulate is set to false, initialGas will be used
 	BuildSignedTx(clientCtx client.Context, accNum, accSeq, initialGas uint64, msg ...sdk.Msg) ([]byte, error)
 	SyncBroadcastSignedTx(tyBytes []byte) (*txtypes.BroadcastTxResponse, error)
 	AsyncBroadcastSignedTx(txBytes []byte) (*txtypes.BroadcastTxResponse, error)
-	QueueBroadcastMsg(msgs ...sdk.Msg) error
 
 	// Bank Module
-	GetBankBalances(ctx context.Context, address string) (*banktypes.QueryAllBalancesResponse, error)
-	GetBankBalance(ctx context.Context, address string, denom string) (*banktypes.QueryBalanceResponse, error)
-	GetBankSpendableBalances(ctx context.Context, address string, pagination *query.PageRequest) (*banktypes.QuerySpendableBalancesResponse, error)
-	GetBankSpendableBalancesByDenom(ctx context.Context, address string, denom string) (*banktypes.QuerySpendableBalanceByDenomResponse, error)
 	GetBankTotalSupply(ctx context.Context, pagination *query.PageRequest) (*banktypes.QueryTotalSupplyResponse, error)
 	GetBankSupplyOf(ctx context.Context, denom string) (*banktypes.QuerySupplyOfResponse, error)
 	GetDenomMetadata(ctx context.Context, denom string) (*banktypes.QueryDenomMetadataResponse, error)
 	GetDenomsMetadata(ctx context.Context, pagination *query.PageRequest) (*banktypes.QueryDenomsMetadataResponse, error)
 	GetDenomOwners(ctx context.Context, denom string, pagination *query.PageRequest) (*banktypes.QueryDenomOwnersResponse, error)
 	GetBankSendEnabled(ctx context.Context, denoms []string, pagination *query.PageRequest) (*banktypes.QuerySendEnabledResponse, error)
+	// send a human readable amount of INJ or of any other token from the token registry (MarketsAssistant.AllTokens),
+	// waiting until the tx is included in a block
+	SendINJ(recipient string, amount decimal.Decimal) (*txtypes.BroadcastTxResponse, error)
+	Send(recipient string, token core.Token, amount decimal.Decimal) (*txtypes.BroadcastTxResponse, error)
 
 	GetAuthzGrants(ctx context.Context, req authztypes.QueryGrantsRequest) (*authztypes.QueryGrantsResponse, error)
 	GetAccount(ctx context.Context, address string) (*authtypes.QueryAccountResponse, error)
@@ -120,8 +132,12 @@ type ChainClient interface {
 	GetFeeDiscountInfo(ctx context.Context, account string) (*exchangetypes.QueryFeeDiscountAccountInfoResponse, error)
 
 	UpdateSubaccountNonceFromChain() error
+	UpdateSubaccountNonceFromChainWithContext(ctx context.Context) error
 	SynchronizeSubaccountNonce(subaccountId eth.Hash) error
+	SynchronizeSubaccountNonceWithContext(ctx context.Context, subaccountId eth.Hash) error
 	ComputeOrderHashes(spotOrders []exchangetypes.SpotOrder, derivativeOrders []exchangetypes.DerivativeOrder, subaccountId eth.Hash) (OrderHashes, error)
+	ComputeOrderHashesWithContext(ctx context.Context, spotOrders []exchangetypes.SpotOrder, derivativeOrders []exchangetypes.DerivativeOrder, subaccountId eth.Hash) (OrderHashes, error)
+	OrderHashDomain() gethsigner.TypedDataDomain
 
 	SpotOrder(defaultSubaccountID eth.Hash, network common.Network, d *SpotOrderData) *exchangetypes.SpotOrder
 	CreateSpotOrder(defaultSubaccountID eth.Hash, d *SpotOrderData, marketsAssistant MarketsAssistant) *exchangetypes.SpotOrder
@@ -131,13 +147,6 @@ type ChainClient interface {
 
 	GetGasFee() (string, error)
 
-	StreamEventOrderFail(sender string, failEventCh chan map[string]uint)
-	StreamEventOrderFailWithWebsocket(sender string, websocket *rpchttp.HTTP, failEventCh chan map[string]uint)
-	StreamOrderbookUpdateEvents(orderbookType OrderbookType, marketIds []string, orderbookCh chan exchangetypes.Orderbook)
-	StreamOrderbookUpdateEventsWithWebsocket(orderbookType OrderbookType, marketIds []string, websocket *rpchttp.HTTP, orderbookCh chan exchangetypes.Orderbook)
-
-	ChainStream(ctx context.Context, req chainstreamtypes.StreamRequest) (chainstreamtypes.Stream_StreamClient, error)
-
 	// get tx from chain node
 	GetTx(ctx context.Context, txHash string) (*txtypes.GetTxResponse, error)
 
@@ -177,9 +186,37 @@ type ChainClient interface {
 	FetchDelegatorWithdrawAddress(ctx context.Context, delegatorAddress string) (*distributiontypes.QueryDelegatorWithdrawAddressResponse, error)
 	FetchCommunityPool(ctx context.Context) (*distributiontypes.QueryCommunityPoolResponse, error)
 
+	// staking module
+	FetchValidators(ctx context.Context, status string, pagination *query.PageRequest) (*stakingtypes.QueryValidatorsResponse, error)
+	FetchValidator(ctx context.Context, validatorAddress string) (*stakingtypes.QueryValidatorResponse, error)
+	FetchDelegation(ctx context.Context, delegatorAddress string, validatorAddress string) (*stakingtypes.QueryDelegationResponse, error)
+	FetchDelegatorDelegations(ctx context.Context, delegatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryDelegatorDelegationsResponse, error)
+	FetchUnbondingDelegation(ctx context.Context, delegatorAddress string, validatorAddress string) (*stakingtypes.QueryUnbondingDelegationResponse, error)
+	FetchDelegatorUnbondingDelegations(ctx context.Context, delegatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryDelegatorUnbondingDelegationsResponse, error)
+	FetchRedelegations(ctx context.Context, delegatorAddress string, srcValidatorAddress string, dstValidatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryRedelegationsResponse, error)
+	FetchStakingPool(ctx context.Context) (*stakingtypes.QueryPoolResponse, error)
+	FetchStakingParams(ctx context.Context) (*stakingtypes.QueryParamsResponse, error)
+
+	// IBC Transfer module
+	FetchDenomTrace(ctx context.Context, hash string) (*ibctransfertypes.QueryDenomTraceResponse, error)
+	FetchDenomTraces(ctx context.Context, pagination *query.PageRequest) (*ibctransfertypes.QueryDenomTracesResponse, error)
+	FetchDenomHash(ctx context.Context, trace string) (*ibctransfertypes.QueryDenomHashResponse, error)
+	FetchEscrowAddress(ctx context.Context, portId string, channelId string) (*ibctransfertypes.QueryEscrowAddressResponse, error)
+	FetchTotalEscrowForDenom(ctx context.Context, denom string) (*ibctransfertypes.QueryTotalEscrowForDenomResponse, error)
+
+	// IBC Core Channel module
+	FetchIBCChannel(ctx context.Context, portId string, channelId string) (*ibcchanneltypes.QueryChannelResponse, error)
+	FetchIBCChannelClientState(ctx context.Context, portId string, channelId string) (*ibcchanneltypes.QueryChannelClientStateResponse, error)
+	FetchIBCPacketCommitments(ctx context.Context, portId string, channelId string, pagination *query.PageRequest) (*ibcchanneltypes.QueryPacketCommitmentsResponse, error)
+	FetchIBCUnreceivedAcks(ctx context.Context, portId string, channelId string, packetAckSequences []uint64) (*ibcchanneltypes.QueryUnreceivedAcksResponse, error)
+	// compute the timeout height and timestamp for a transfer through the channel, based on the counterparty chain
+	// latest height known by the channel client
+	ComputeIBCTransferTimeout(ctx context.Context, portId string, channelId string, blockOffset uint64, timeoutDuration time.Duration) (clienttypes.Height, uint64, error)
+
 	// chain exchange module
-	FetchSubaccountDeposits(ctx context.Context, subaccountID string) (*exchangetypes.QuerySubaccountDepositsResponse, error)
-	FetchSubaccountDeposit(ctx context.Context, subaccountId string, denom string) (*exchangetypes.QuerySubaccountDepositResponse, error)
+	FetchExchangeParams(ctx context.Context) (*exchangetypes.QueryExchangeParamsResponse, error)
+	// returns the exchange params from a snapshot refreshed every 10 minutes
+	CachedExchangeParams(ctx context.Context) (exchangetypes.Params, error)
 	FetchExchangeBalances(ctx context.Context) (*exchangetypes.QueryExchangeBalancesResponse, error)
 	FetchAggregateVolume(ctx context.Context, account string) (*exchangetypes.QueryAggregateVolumeResponse, error)
 	FetchAggregateVolumes(ctx context.Context, accounts []string, marketIds []string) (*exchangetypes.QueryAggregateVolumesResponse, error)
@@ -187,40 +224,37 @@ type ChainClient interface {
 	FetchAggregateMarketVolumes(ctx context.Context, marketIds []string) (*exchangetypes.QueryAggregateMarketVolumesResponse, error)
 	FetchDenomDecimal(ctx context.Context, denom string) (*exchangetypes.QueryDenomDecimalResponse, error)
 	FetchDenomDecimals(ctx context.Context, denoms []string) (*exchangetypes.QueryDenomDecimalsResponse, error)
-	FetchChainSpotMarkets(ctx context.Context, status string, marketIds []string) (*exchangetypes.QuerySpotMarketsResponse, error)
-	FetchChainSpotMarket(ctx context.Context, marketId string) (*exchangetypes.QuerySpotMarketResponse, error)
 	FetchChainFullSpotMarkets(ctx context.Context, status string, marketIds []string, withMidPriceAndTob bool) (*exchangetypes.QueryFullSpotMarketsResponse, error)
 	FetchChainFullSpotMarket(ctx context.Context, marketId string, withMidPriceAndTob bool) (*exchangetypes.QueryFullSpotMarketResponse, error)
-	FetchChainSpotOrderbook(ctx context.Context, marketId string, limit uint64, orderSide exchangetypes.OrderSide, limitCumulativeNotional sdk.Dec, limitCumulativeQuantity sdk.Dec) (*exchangetypes.QuerySpotOrderbookResponse, error)
 	FetchChainTraderSpotOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderSpotOrdersResponse, error)
 	FetchChainAccountAddressSpotOrders(ctx context.Context, marketId string, address string) (*exchangetypes.QueryAccountAddressSpotOrdersResponse, error)
 	FetchChainSpotOrdersByHashes(ctx context.Context, marketId string, subaccountId string, orderHashes []string) (*exchangetypes.QuerySpotOrdersByHashesResponse, error)
 	FetchChainSubaccountOrders(ctx context.Context, subaccountId string, marketId string) (*exchangetypes.QuerySubaccountOrdersResponse, error)
 	FetchChainTraderSpotTransientOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderSpotOrdersResponse, error)
-	FetchSpotMidPriceAndTOB(ctx context.Context, marketId string) (*exchangetypes.QuerySpotMidPriceAndTOBResponse, error)
-	FetchDerivativeMidPriceAndTOB(ctx context.Context, marketId string) (*exchangetypes.QueryDerivativeMidPriceAndTOBResponse, error)
-	FetchChainDerivativeOrderbook(ctx context.Context, marketId string, limit uint64, limitCumulativeNotional sdk.Dec) (*exchangetypes.QueryDerivativeOrderbookResponse, error)
 	FetchChainTraderDerivativeOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderDerivativeOrdersResponse, error)
 	FetchChainAccountAddressDerivativeOrders(ctx context.Context, marketId string, address string) (*exchangetypes.QueryAccountAddressDerivativeOrdersResponse, error)
 	FetchChainDerivativeOrdersByHashes(ctx context.Context, marketId string, subaccountId string, orderHashes []string) (*exchangetypes.QueryDerivativeOrdersByHashesResponse, error)
 	FetchChainTraderDerivativeTransientOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderDerivativeOrdersResponse, error)
-	FetchChainDerivativeMarkets(ctx context.Context, status string, marketIds []string, withMidPriceAndTob bool) (*exchangetypes.QueryDerivativeMarketsResponse, error)
-	FetchChainDerivativeMarket(ctx context.Context, marketId string) (*exchangetypes.QueryDerivativeMarketResponse, error)
 	FetchDerivativeMarketAddress(ctx context.Context, marketId string) (*exchangetypes.QueryDerivativeMarketAddressResponse, error)
 	FetchSubaccountTradeNonce(ctx context.Context, subaccountId string) (*exchangetypes.QuerySubaccountTradeNonceResponse, error)
 	FetchChainPositions(ctx context.Context) (*exchangetypes.QueryPositionsResponse, error)
 	FetchChainSubaccountPositions(ctx context.Context, subaccountId string) (*exchangetypes.QuerySubaccountPositionsResponse, error)
 	FetchChainSubaccountPositionInMarket(ctx context.Context, subaccountId string, marketId string) (*exchangetypes.QuerySubaccountPositionInMarketResponse, error)
 	FetchChainSubaccountEffectivePositionInMarket(ctx context.Context, subaccountId string, marketId string) (*exchangetypes.QuerySubaccountEffectivePositionInMarketResponse, error)
-	FetchChainPerpetualMarketInfo(ctx context.Context, marketId string) (*exchangetypes.QueryPerpetualMarketInfoResponse, error)
 	FetchChainExpiryFuturesMarketInfo(ctx context.Context, marketId string) (*exchangetypes.QueryExpiryFuturesMarketInfoResponse, error)
-	FetchChainPerpetualMarketFunding(ctx context.Context, marketId string) (*exchangetypes.QueryPerpetualMarketFundingResponse, error)
+	// returns the expiry futures market settlement price, and false if the market has not been settled yet
+	FetchExpiryFuturesSettlementPrice(ctx context.Context, marketId string) (sdk.Dec, bool, error)
 	FetchSubaccountOrderMetadata(ctx context.Context, subaccountId string) (*exchangetypes.QuerySubaccountOrderMetadataResponse, error)
 	FetchTradeRewardPoints(ctx context.Context, accounts []string) (*exchangetypes.QueryTradeRewardPointsResponse, error)
 	FetchPendingTradeRewardPoints(ctx context.Context, accounts []string) (*exchangetypes.QueryTradeRewardPointsResponse, error)
+	FetchPendingTradeRewardPointsForPool(ctx context.Context, accounts []string, pendingPoolTimestamp int64) (*exchangetypes.QueryTradeRewardPointsResponse, error)
+	// estimates the account rewards for the current campaign and the campaigns pending to be paid out
+	FetchTradingRewardsEstimate(ctx context.Context, account string) (*TradingRewardsEstimate, error)
 	FetchFeeDiscountAccountInfo(ctx context.Context, account string) (*exchangetypes.QueryFeeDiscountAccountInfoResponse, error)
 	FetchTradeRewardCampaign(ctx context.Context) (*exchangetypes.QueryTradeRewardCampaignResponse, error)
 	FetchFeeDiscountSchedule(ctx context.Context) (*exchangetypes.QueryFeeDiscountScheduleResponse, error)
+	// applies the account fee discount tier to the market fee rates
+	FetchEffectiveFeeRates(ctx context.Context, account string, makerFeeRate decimal.Decimal, takerFeeRate decimal.Decimal) (FeeRates, error)
 	FetchBalanceMismatches(ctx context.Context, dustFactor int64) (*exchangetypes.QueryBalanceMismatchesResponse, error)
 	FetchBalanceWithBalanceHolds(ctx context.Context) (*exchangetypes.QueryBalanceWithBalanceHoldsResponse, error)
 	FetchFeeDiscountTierStatistics(ctx context.Context) (*exchangetypes.QueryFeeDiscountTierStatisticsResponse, error)
@@ -230,7 +264,6 @@ type ChainClient interface {
 	FetchIsOptedOutOfRewards(ctx context.Context, account string) (*exchangetypes.QueryIsOptedOutOfRewardsResponse, error)
 	FetchOptedOutOfRewardsAccounts(ctx context.Context) (*exchangetypes.QueryOptedOutOfRewardsAccountsResponse, error)
 	FetchMarketVolatility(ctx context.Context, marketId string, tradeHistoryOptions *exchangetypes.TradeHistoryOptions) (*exchangetypes.QueryMarketVolatilityResponse, error)
-	FetchChainBinaryOptionsMarkets(ctx context.Context, status string) (*exchangetypes.QueryBinaryMarketsResponse, error)
 	FetchTraderDerivativeConditionalOrders(ctx context.Context, subaccountId string, marketId string) (*exchangetypes.QueryTraderDerivativeConditionalOrdersResponse, error)
 	FetchMarketAtomicExecutionFeeMultiplier(ctx context.Context, marketId string) (*exchangetypes.QueryMarketAtomicExecutionFeeMultiplierResponse, error)
 
@@ -269,7 +302,11 @@ type chainClient struct {
 	chainStreamClient       chainstreamtypes.StreamClient
 	tokenfactoryQueryClient tokenfactorytypes.QueryClient
 	distributionQueryClient distributiontypes.QueryClient
+	stakingQueryClient      stakingtypes.QueryClient
+	ibcTransferQueryClient  ibctransfertypes.QueryClient
+	ibcChannelQueryClient   ibcchanneltypes.QueryClient
 	subaccountToNonce       map[ethcommon.Hash]uint32
+	exchangeParamsCache     *exchangeParamsCache
 
 	closed  int64
 	canSign bool
@@ -293,6 +330,20 @@ func NewChainClient(
 			return nil, err
 		}
 	}
+	if !opts.SkipChainIDValidation {
+		if err := ValidateChainID(network, ctx.ChainID); err != nil {
+			return nil, err
+		}
+	}
+	if opts.NodeVersionCheck != nil {
+		if err := checkNodeVersion(ctx, *opts.NodeVersionCheck); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.TxSigner != nil && ctx.FromAddress.Empty() {
+		return nil, errors.New("the client context must have the FromAddress of the tx signer account")
+	}
 
 	// init tx factory
 	var txFactory tx.Factory
@@ -304,28 +355,22 @@ func NewChainClient(
 	} else {
 		txFactory = *opts.TxFactory
 	}
+	if opts.TxMemo != "" {
+		txFactory = txFactory.WithMemo(opts.TxMemo)
+	}
 
 	// init grpc connection
 	var conn *grpc.ClientConn
 	var err error
-	stickySessionEnabled := true
-	if opts.TLSCert != nil {
-		conn, err = grpc.Dial(network.ChainGrpcEndpoint, grpc.WithTransportCredentials(opts.TLSCert), grpc.WithContextDialer(common.DialerFunc))
-	} else {
-		conn, err = grpc.Dial(network.ChainGrpcEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(common.DialerFunc))
-		stickySessionEnabled = false
-	}
+	stickySessionEnabled := opts.TLSCert != nil
+	dialOptions := append(common.GrpcDialOptions(opts), grpc.WithChainUnaryInterceptor(heightInterceptor))
+	conn, err = grpc.Dial(network.ChainGrpcEndpoint, dialOptions...)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to connect to the gRPC: %s", network.ChainGrpcEndpoint)
 		return nil, err
 	}
 
-	var chainStreamConn *grpc.ClientConn
-	if opts.TLSCert != nil {
-		chainStreamConn, err = grpc.Dial(network.ChainStreamGrpcEndpoint, grpc.WithTransportCredentials(opts.TLSCert), grpc.WithContextDialer(common.DialerFunc))
-	} else {
-		chainStreamConn, err = grpc.Dial(network.ChainStreamGrpcEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(common.DialerFunc))
-	}
+	chainStreamConn, err := grpc.Dial(network.ChainStreamGrpcEndpoint, common.GrpcDialOptions(opts)...)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to connect to the chain stream gRPC: %s", network.ChainStreamGrpcEndpoint)
 		return nil, err
@@ -346,7 +391,7 @@ func NewChainClient(
 		conn:            conn,
 		chainStreamConn: chainStreamConn,
 		txFactory:       txFactory,
-		canSign:         ctx.Keyring != nil,
+		canSign:         ctx.Keyring != nil || opts.TxSigner != nil,
 		syncMux:         new(sync.Mutex),
 		msgC:            make(chan sdk.Msg, msgCommitBatchSizeLimit),
 		doneC:           make(chan bool, 1),
@@ -364,7 +409,11 @@ func NewChainClient(
 		chainStreamClient:       chainstreamtypes.NewStreamClient(chainStreamConn),
 		tokenfactoryQueryClient: tokenfactorytypes.NewQueryClient(conn),
 		distributionQueryClient: distributiontypes.NewQueryClient(conn),
+		stakingQueryClient:      stakingtypes.NewQueryClient(conn),
+		ibcTransferQueryClient:  ibctransfertypes.NewQueryClient(conn),
+		ibcChannelQueryClient:   ibcchanneltypes.NewQueryClient(conn),
 		subaccountToNonce:       make(map[ethcommon.Hash]uint32),
+		exchangeParamsCache:     newExchangeParamsCache(defaultExchangeParamsCacheTTL, opts.Clock),
 	}
 
 	if cc.canSign {
@@ -449,7 +498,18 @@ func (c *chainClient) prepareFactory(clientCtx client.Context, txf tx.Factory) (
 	return txf, nil
 }
 
+func (c *chainClient) broadcastTimeout() time.Duration {
+	if c.opts.Timeouts.BroadcastTimeout > 0 {
+		return c.opts.Timeouts.BroadcastTimeout
+	}
+	return common.DefaultBroadcastTimeout
+}
+
 func (c *chainClient) getAccSeq() uint64 {
+	if c.opts.DryRun != nil {
+		// the dry run txs are not broadcasted, so they don't use the sequence
+		return c.accSeq
+	}
 	defer func() {
 		c.accSeq += 1
 	}()
@@ -496,18 +556,18 @@ func (c *chainClient) FromAddress() sdk.AccAddress {
 	return c.ctx.FromAddress
 }
 
+// Close flushes the queued msgs and stops the client goroutines. The connections are closed also for clients that
+// can't sign txs
 func (c *chainClient) Close() {
-	if !c.canSign {
-		return
-	}
-	if atomic.CompareAndSwapInt64(&c.closed, 0, 1) {
-		close(c.msgC)
-	}
-
 	if c.cancelFn != nil {
 		c.cancelFn()
 	}
-	<-c.doneC
+	if c.canSign {
+		if atomic.CompareAndSwapInt64(&c.closed, 0, 1) {
+			close(c.msgC)
+		}
+		<-c.doneC
+	}
 	if c.conn != nil {
 		c.conn.Close()
 	}
@@ -518,6 +578,24 @@ func (c *chainClient) Close() {
 
 //Bank Module
 
+func (c *chainClient) SendINJ(recipient string, amount decimal.Decimal) (*txtypes.BroadcastTxResponse, error) {
+	return c.Send(recipient, INJToken, amount)
+}
+
+func (c *chainClient) Send(recipient string, token core.Token, amount decimal.Decimal) (*txtypes.BroadcastTxResponse, error) {
+	coin, err := ChainFormattedCoin(token, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := NewMsgSend(c.ctx.FromAddress.String(), recipient, sdk.NewCoins(coin))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SyncBroadcastMsg(msg)
+}
+
 func (c *chainClient) GetBankBalances(ctx context.Context, address string) (*banktypes.QueryAllBalancesResponse, error) {
 	req := &banktypes.QueryAllBalancesRequest{
 		Address: address,
@@ -594,33 +672,62 @@ func (c *chainClient) GetAccount(ctx context.Context, address string) (*authtype
 	return c.authQueryClient.Account(ctx, req)
 }
 
-// SyncBroadcastMsg sends Tx to chain and waits until Tx is included in block.
+// SyncBroadcastMsg sends Tx to chain and waits until Tx is included in block. If it is not included before the
+// broadcast timeout, ErrTimedOut is returned with the response of the broadcast, which has the tx hash.
+// Errors returned before the tx was sent are TxNotSentError
 func (c *chainClient) SyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
 	c.syncMux.Lock()
 	defer c.syncMux.Unlock()
 
-	sequence := c.getAccSeq()
-	c.txFactory = c.txFactory.WithSequence(sequence)
-	c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-	res, err := c.broadcastTx(c.ctx, c.txFactory, true, msgs...)
+	res, _, err := c.syncBroadcastMsg(c.txFactory.Memo(), msgs...)
+	return res, err
+}
+
+// SyncBroadcastMsgWithMemo sends Tx with the memo to chain and waits until Tx is included in block.
+func (c *chainClient) SyncBroadcastMsgWithMemo(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	c.syncMux.Lock()
+	defer c.syncMux.Unlock()
+
+	res, _, err := c.syncBroadcastMsg(memo, msgs...)
+	return res, err
+}
 
+// SyncBroadcastCancelMsg is SyncBroadcastMsg returning also the decoded results of the cancel msgs. The cancel
+// response is nil if the msgs have no cancels or the tx failed
+func (c *chainClient) SyncBroadcastCancelMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error) {
+	c.syncMux.Lock()
+	defer c.syncMux.Unlock()
+
+	return c.syncBroadcastMsg(c.txFactory.Memo(), msgs...)
+}
+
+// SyncBroadcastCreateMsg is SyncBroadcastMsg returning also the orders created by the msgs, with the order hashes
+// echoed by the chain (see CreateResponse.CheckOrderHashes to compare them with the locally computed hashes). The
+// create response is nil if the msgs create no orders or the tx failed
+func (c *chainClient) SyncBroadcastCreateMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CreateResponse, error) {
+	c.syncMux.Lock()
+	defer c.syncMux.Unlock()
+
+	res, _, err := c.syncBroadcastMsg(c.txFactory.Memo(), msgs...)
 	if err != nil {
-		if strings.Contains(err.Error(), "account sequence mismatch") {
-			c.syncNonce()
-			sequence := c.getAccSeq()
-			c.txFactory = c.txFactory.WithSequence(sequence)
-			c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-			log.Debugln("retrying broadcastTx with nonce", sequence)
-			res, err = c.broadcastTx(c.ctx, c.txFactory, true, msgs...)
-		}
-		if err != nil {
-			resJSON, _ := json.MarshalIndent(res, "", "\t")
-			c.logger.WithField("size", len(msgs)).WithError(err).Errorln("failed synchronously broadcast messages:", string(resJSON))
-			return nil, err
-		}
+		return res, nil, err
 	}
+	return res, c.decodeCreateResponse(res, msgs), nil
+}
 
-	return res, nil
+func (c *chainClient) syncBroadcastMsg(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error) {
+	if err := c.preBroadcastCheck(msgs...); err != nil {
+		return nil, nil, &TxNotSentError{Err: err}
+	}
+
+	res, cancelResponse, err := c.broadcastMsgWithRetry(memo, true, msgs...)
+	if err != nil {
+		resJSON, _ := json.MarshalIndent(res, "", "\t")
+		c.logger.WithField("size", len(msgs)).WithError(err).Errorln("failed synchronously broadcast messages:", string(resJSON))
+		return res, nil, err
+	}
+
+	return res, cancelResponse, nil
 }
 
 func (c *chainClient) GetFeeDiscountInfo(ctx context.Context, account string) (*exchangetypes.QueryFeeDiscountAccountInfoResponse, error) {
@@ -663,30 +770,165 @@ func (c *chainClient) AsyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxRe
 	c.syncMux.Lock()
 	defer c.syncMux.Unlock()
 
+	res, _, err := c.asyncBroadcastMsg(c.txFactory.Memo(), msgs...)
+	return res, err
+}
+
+// AsyncBroadcastMsgWithMemo sends Tx with the memo to chain and doesn't wait until Tx is included in block.
+func (c *chainClient) AsyncBroadcastMsgWithMemo(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	c.syncMux.Lock()
+	defer c.syncMux.Unlock()
+
+	res, _, err := c.asyncBroadcastMsg(memo, msgs...)
+	return res, err
+}
+
+// AsyncBroadcastCancelMsg is AsyncBroadcastMsg returning also the cancel response of the msgs. The tx is not executed
+// yet, so the response only has the tx hash; the per order results are known once the tx is committed
+func (c *chainClient) AsyncBroadcastCancelMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error) {
+	c.syncMux.Lock()
+	defer c.syncMux.Unlock()
+
+	return c.asyncBroadcastMsg(c.txFactory.Memo(), msgs...)
+}
+
+func (c *chainClient) asyncBroadcastMsg(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error) {
+	if err := c.preBroadcastCheck(msgs...); err != nil {
+		return nil, nil, &TxNotSentError{Err: err}
+	}
+
+	res, cancelResponse, err := c.broadcastMsgWithRetry(memo, false, msgs...)
+	if err != nil {
+		resJSON, _ := json.MarshalIndent(res, "", "\t")
+		c.logger.WithField("size", len(msgs)).WithError(err).Errorln("failed to asynchronously broadcast messagess:", string(resJSON))
+		return nil, nil, err
+	}
+
+	return res, cancelResponse, nil
+}
+
+func (c *chainClient) preBroadcastCheck(msgs ...sdk.Msg) error {
+	if c.opts.PreBroadcastCheck == nil {
+		return nil
+	}
+	if err := c.opts.PreBroadcastCheck(msgs...); err != nil {
+		c.logger.WithField("size", len(msgs)).WithError(err).Warningln("msgs rejected by the pre broadcast check")
+		return err
+	}
+	return nil
+}
+
+// journalBroadcast records the tx in the broadcast journal before sending it. The tx is not sent if it can't be recorded
+func (c *chainClient) journalBroadcast(txBytes []byte, sequence uint64) (string, error) {
+	txHash := fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash())
+	if c.opts.BroadcastJournal == nil {
+		return txHash, nil
+	}
+	if err := c.opts.BroadcastJournal.RecordBroadcast(txHash, txBytes, sequence); err != nil {
+		return txHash, errors.Wrap(err, "failed to record the tx in the broadcast journal")
+	}
+	return txHash, nil
+}
+
+func (c *chainClient) journalResult(txHash string, res *txtypes.BroadcastTxResponse) {
+	if c.opts.BroadcastJournal == nil || res == nil {
+		return
+	}
+	if err := c.opts.BroadcastJournal.RecordResult(txHash, res.TxResponse); err != nil {
+		c.logger.WithField("txHash", txHash).WithError(err).Warningln("failed to record the tx result in the broadcast journal")
+	}
+}
+
+// applyGasPriceStrategy sets the gas prices provided by the gas price strategy. The tx factory gas prices are kept
+// if the strategy fails
+func (c *chainClient) applyGasPriceStrategy(ctx context.Context, txf tx.Factory) tx.Factory {
+	if c.opts.GasPriceStrategy == nil {
+		return txf
+	}
+	gasPrices, err := c.opts.GasPriceStrategy.GasPrices(ctx)
+	if err != nil {
+		c.logger.WithError(err).Warningln("failed to get the gas prices from the gas price strategy")
+		return txf
+	}
+	if gasPrices.Empty() {
+		return txf
+	}
+	return txf.WithGasPrices(gasPrices.String())
+}
+
+// gasAdjustment is the adjustment of the simulated gas tuned by the gas telemetry, or the tx factory one
+func (c *chainClient) gasAdjustment(txf tx.Factory, msgs []sdk.Msg) float64 {
+	if c.opts.GasTelemetry == nil {
+		return txf.GasAdjustment()
+	}
+	return c.opts.GasTelemetry.GasAdjustment(msgs, txf.GasAdjustment())
+}
+
+// recordGas reports the gas of a tx included in a block to the gas telemetry
+func (c *chainClient) recordGas(msgs []sdk.Msg, simulatedGas uint64, res *sdk.TxResponse) {
+	if c.opts.GasTelemetry == nil || simulatedGas == 0 || res == nil || res.GasUsed < 0 || res.GasWanted < 0 {
+		return
+	}
+	outOfGas := res.Codespace == sdkerrors.ErrOutOfGas.Codespace() && res.Code == sdkerrors.ErrOutOfGas.ABCICode()
+	c.opts.GasTelemetry.RecordGas(msgs, simulatedGas, uint64(res.GasWanted), uint64(res.GasUsed), outOfGas)
+}
+
+// broadcastMsgWithRetry broadcasts the msgs with the next account sequence, and broadcasts them again after syncing the
+// sequence if it was wrong. Other retryable errors (e.g. a full mempool) are returned, because sending the msgs again
+// right away would fail the same way. The results of the cancel msgs are decoded from the final response. It has to be
+// called holding syncMux
+func (c *chainClient) broadcastMsgWithRetry(memo string, await bool, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error) {
 	sequence := c.getAccSeq()
 	c.txFactory = c.txFactory.WithSequence(sequence)
 	c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-	res, err := c.broadcastTx(c.ctx, c.txFactory, false, msgs...)
+	res, err := c.broadcastTx(c.ctx, c.txFactory.WithMemo(memo), await, msgs...)
+	if IsSequenceMismatch(broadcastError(res, err)) {
+		c.syncNonce()
+		sequence := c.getAccSeq()
+		c.txFactory = c.txFactory.WithSequence(sequence)
+		c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
+		log.Debugln("retrying broadcastTx with nonce", sequence)
+		res, err = c.broadcastTx(c.ctx, c.txFactory.WithMemo(memo), await, msgs...)
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "account sequence mismatch") {
-			c.syncNonce()
-			sequence := c.getAccSeq()
-			c.txFactory = c.txFactory.WithSequence(sequence)
-			c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-			log.Debugln("retrying broadcastTx with nonce", sequence)
-			res, err = c.broadcastTx(c.ctx, c.txFactory, false, msgs...)
-		}
-		if err != nil {
-			resJSON, _ := json.MarshalIndent(res, "", "\t")
-			c.logger.WithField("size", len(msgs)).WithError(err).Errorln("failed to asynchronously broadcast messagess:", string(resJSON))
-			return nil, err
-		}
+		return res, nil, err
 	}
 
-	return res, nil
+	return res, c.decodeCancelResponse(res, msgs), nil
+}
+
+// decodeCancelResponse returns the cancel results of a successful tx with cancel msgs. Decoding failures are logged
+// and not returned, because the tx has already been broadcasted
+func (c *chainClient) decodeCancelResponse(res *txtypes.BroadcastTxResponse, msgs []sdk.Msg) *CancelResponse {
+	if res == nil || res.TxResponse == nil || !hasCancelMsgs(msgs) || NewTxError(res.TxResponse) != nil {
+		return nil
+	}
+	cancelResponse, err := DecodeCancelResponse(res.TxResponse)
+	if err != nil {
+		c.logger.WithField("txHash", res.TxResponse.TxHash).WithError(err).Warningln("failed to decode the cancel results")
+		return nil
+	}
+	return cancelResponse
+}
+
+// decodeCreateResponse returns the created orders of a successful tx with create msgs. Decoding failures are logged
+// and not returned, because the tx has already been broadcasted
+func (c *chainClient) decodeCreateResponse(res *txtypes.BroadcastTxResponse, msgs []sdk.Msg) *CreateResponse {
+	if res == nil || res.TxResponse == nil || !hasCreateMsgs(msgs) || NewTxError(res.TxResponse) != nil {
+		return nil
+	}
+	createResponse, err := DecodeCreateResponse(res.TxResponse, msgs)
+	if err != nil {
+		c.logger.WithField("txHash", res.TxResponse.TxHash).WithError(err).Warningln("failed to decode the created orders")
+		return nil
+	}
+	return createResponse
 }
 
 func (c *chainClient) BuildSignedTx(clientCtx client.Context, accNum, accSeq, initialGas uint64, msgs ...sdk.Msg) ([]byte, error) {
+	if err := c.validateChainID(clientCtx.ChainID); err != nil {
+		return nil, err
+	}
 	txf := NewTxFactory(clientCtx).WithSequence(accSeq).WithAccountNumber(accNum).WithGas(initialGas)
 
 	if clientCtx.Simulate {
@@ -703,7 +945,7 @@ func (c *chainClient) BuildSignedTx(clientCtx client.Context, accNum, accSeq, in
 			return nil, err
 		}
 
-		adjustedGas := uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed))
+		adjustedGas := uint64(c.gasAdjustment(txf, msgs) * float64(simRes.GasInfo.GasUsed))
 		txf = txf.WithGas(adjustedGas)
 
 		c.gasWanted = adjustedGas
@@ -713,6 +955,7 @@ func (c *chainClient) BuildSignedTx(clientCtx client.Context, accNum, accSeq, in
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to prepareFactory")
 	}
+	txf = c.applyGasPriceStrategy(context.Background(), txf)
 
 	txn, err := txf.BuildUnsignedTx(msgs...)
 	if err != nil {
@@ -721,7 +964,7 @@ func (c *chainClient) BuildSignedTx(clientCtx client.Context, accNum, accSeq, in
 	}
 
 	txn.SetFeeGranter(clientCtx.GetFeeGranterAddress())
-	err = tx.Sign(txf, clientCtx.GetFromName(), txn, true)
+	err = c.sign(clientCtx, txf, txn)
 	if err != nil {
 		err = errors.Wrap(err, "failed to Sign Tx")
 		return nil, err
@@ -731,6 +974,9 @@ func (c *chainClient) BuildSignedTx(clientCtx client.Context, accNum, accSeq, in
 }
 
 func (c *chainClient) SyncBroadcastSignedTx(txBytes []byte) (*txtypes.BroadcastTxResponse, error) {
+	if c.opts.DryRun != nil {
+		return c.dryRunSignedTx(txBytes)
+	}
 	req := txtypes.BroadcastTxRequest{
 		TxBytes: txBytes,
 		Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
@@ -743,7 +989,7 @@ func (c *chainClient) SyncBroadcastSignedTx(txBytes []byte) (*txtypes.BroadcastT
 		return res, err
 	}
 
-	awaitCtx, cancelFn := context.WithTimeout(context.Background(), defaultBroadcastTimeout)
+	awaitCtx, cancelFn := context.WithTimeout(context.Background(), c.broadcastTimeout())
 	defer cancelFn()
 
 	txHash, _ := hex.DecodeString(res.TxResponse.TxHash)
@@ -778,6 +1024,9 @@ func (c *chainClient) SyncBroadcastSignedTx(txBytes []byte) (*txtypes.BroadcastT
 }
 
 func (c *chainClient) AsyncBroadcastSignedTx(txBytes []byte) (*txtypes.BroadcastTxResponse, error) {
+	if c.opts.DryRun != nil {
+		return c.dryRunSignedTx(txBytes)
+	}
 	req := txtypes.BroadcastTxRequest{
 		TxBytes: txBytes,
 		Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
@@ -800,49 +1049,48 @@ func (c *chainClient) broadcastTx(
 	await bool,
 	msgs ...sdk.Msg,
 ) (*txtypes.BroadcastTxResponse, error) {
+	if err := c.validateChainID(txf.ChainID()); err != nil {
+		return nil, &TxNotSentError{Err: err}
+	}
 	txf, err := c.prepareFactory(clientCtx, txf)
 	if err != nil {
 		err = errors.Wrap(err, "failed to prepareFactory")
-		return nil, err
+		return nil, &TxNotSentError{Err: err}
 	}
 	ctx := context.Background()
+	txf = c.applyGasPriceStrategy(ctx, txf)
+	if c.opts.DryRun != nil {
+		return c.dryRunTx(clientCtx, txf, msgs)
+	}
+	var simulatedGas uint64
 	if clientCtx.Simulate {
 		simTxBytes, err := txf.BuildSimTx(msgs...)
 		if err != nil {
 			err = errors.Wrap(err, "failed to build sim tx bytes")
-			return nil, err
+			return nil, &TxNotSentError{Err: err}
 		}
 		ctx := c.getCookie(ctx)
 		simRes, err := c.txClient.Simulate(ctx, &txtypes.SimulateRequest{TxBytes: simTxBytes})
 		if err != nil {
 			err = errors.Wrap(err, "failed to CalculateGas")
-			return nil, err
+			return nil, &TxNotSentError{Err: err}
 		}
 
-		adjustedGas := uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed))
+		simulatedGas = simRes.GasInfo.GasUsed
+		adjustedGas := uint64(c.gasAdjustment(txf, msgs) * float64(simulatedGas))
 		txf = txf.WithGas(adjustedGas)
 
 		c.gasWanted = adjustedGas
 	}
 
-	txn, err := txf.BuildUnsignedTx(msgs...)
-
+	txBytes, err := c.signTx(clientCtx, txf, msgs)
 	if err != nil {
-		err = errors.Wrap(err, "failed to BuildUnsignedTx")
-		return nil, err
+		return nil, &TxNotSentError{Err: err}
 	}
 
-	txn.SetFeeGranter(clientCtx.GetFeeGranterAddress())
-	err = tx.Sign(txf, clientCtx.GetFromName(), txn, true)
+	journalTxHash, err := c.journalBroadcast(txBytes, txf.Sequence())
 	if err != nil {
-		err = errors.Wrap(err, "failed to Sign Tx")
-		return nil, err
-	}
-
-	txBytes, err := clientCtx.TxConfig.TxEncoder()(txn.GetTx())
-	if err != nil {
-		err = errors.Wrap(err, "failed TxEncoder to encode Tx")
-		return nil, err
+		return nil, &TxNotSentError{Err: err}
 	}
 
 	req := txtypes.BroadcastTxRequest{
@@ -852,11 +1100,18 @@ func (c *chainClient) broadcastTx(
 	// use our own client to broadcast tx
 	ctx = c.getCookie(ctx)
 	res, err := c.txClient.BroadcastTx(ctx, &req)
+	if err == nil {
+		c.journalResult(journalTxHash, res)
+	}
 	if !await || err != nil {
 		return res, err
 	}
+	if res.TxResponse.Code != 0 {
+		// the tx was rejected by CheckTx, so it is not in the mempool and will never be included in a block
+		return res, nil
+	}
 
-	awaitCtx, cancelFn := context.WithTimeout(context.Background(), defaultBroadcastTimeout)
+	awaitCtx, cancelFn := context.WithTimeout(context.Background(), c.broadcastTimeout())
 	defer cancelFn()
 
 	txHash, _ := hex.DecodeString(res.TxResponse.TxHash)
@@ -867,11 +1122,12 @@ func (c *chainClient) broadcastTx(
 		case <-awaitCtx.Done():
 			err := errors.Wrapf(ErrTimedOut, "%s", res.TxResponse.TxHash)
 			t.Stop()
-			return nil, err
+			return res, err
 		case <-t.C:
 			resultTx, err := clientCtx.Client.Tx(awaitCtx, txHash, false)
 			if err != nil {
 				if errRes := client.CheckTendermintError(err, txBytes); errRes != nil {
+					c.journalResult(journalTxHash, &txtypes.BroadcastTxResponse{TxResponse: errRes})
 					return &txtypes.BroadcastTxResponse{TxResponse: errRes}, err
 				}
 
@@ -881,6 +1137,8 @@ func (c *chainClient) broadcastTx(
 			} else if resultTx.Height > 0 {
 				resResultTx := sdk.NewResponseResultTx(resultTx, res.TxResponse.Tx, res.TxResponse.Timestamp)
 				res = &txtypes.BroadcastTxResponse{TxResponse: resResultTx}
+				c.journalResult(journalTxHash, res)
+				c.recordGas(msgs, simulatedGas, res.TxResponse)
 				t.Stop()
 				return res, err
 			}
@@ -890,6 +1148,30 @@ func (c *chainClient) broadcastTx(
 	}
 }
 
+// signTx builds and signs the tx of the msgs with the factory gas and fees
+func (c *chainClient) signTx(clientCtx client.Context, txf tx.Factory, msgs []sdk.Msg) ([]byte, error) {
+	txn, err := txf.BuildUnsignedTx(msgs...)
+
+	if err != nil {
+		err = errors.Wrap(err, "failed to BuildUnsignedTx")
+		return nil, err
+	}
+
+	txn.SetFeeGranter(clientCtx.GetFeeGranterAddress())
+	err = c.sign(clientCtx, txf, txn)
+	if err != nil {
+		err = errors.Wrap(err, "failed to Sign Tx")
+		return nil, err
+	}
+
+	txBytes, err := clientCtx.TxConfig.TxEncoder()(txn.GetTx())
+	if err != nil {
+		err = errors.Wrap(err, "failed TxEncoder to encode Tx")
+		return nil, err
+	}
+	return txBytes, nil
+}
+
 // QueueBroadcastMsg enqueues a list of messages. Messages will added to the queue
 // and grouped into Txns in chunks. Use this method to mass broadcast Txns with efficiency.
 func (c *chainClient) QueueBroadcastMsg(msgs ...sdk.Msg) error {
@@ -898,6 +1180,9 @@ func (c *chainClient) QueueBroadcastMsg(msgs ...sdk.Msg) error {
 	} else if atomic.LoadInt64(&c.closed) == 1 {
 		return ErrQueueClosed
 	}
+	if err := c.preBroadcastCheck(msgs...); err != nil {
+		return err
+	}
 
 	t := time.NewTimer(10 * time.Second)
 	for _, msg := range msgs {
@@ -924,25 +1209,25 @@ func (c *chainClient) runBatchBroadcast() {
 		c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
 		log.Debugln("broadcastTx with nonce", sequence)
 		res, err := c.broadcastTx(c.ctx, c.txFactory, true, toSubmit...)
+		if IsSequenceMismatch(broadcastError(res, err)) {
+			c.syncNonce()
+			sequence := c.getAccSeq()
+			c.txFactory = c.txFactory.WithSequence(sequence)
+			c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
+			log.Debugln("retrying broadcastTx with nonce", sequence)
+			res, err = c.broadcastTx(c.ctx, c.txFactory, true, toSubmit...)
+		}
 		if err != nil {
-			if strings.Contains(err.Error(), "account sequence mismatch") {
-				c.syncNonce()
-				sequence := c.getAccSeq()
-				c.txFactory = c.txFactory.WithSequence(sequence)
-				c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-				log.Debugln("retrying broadcastTx with nonce", sequence)
-				res, err = c.broadcastTx(c.ctx, c.txFactory, true, toSubmit...)
-			}
-			if err != nil {
-				resJSON, _ := json.MarshalIndent(res, "", "\t")
-				c.logger.WithField("size", len(toSubmit)).WithError(err).Errorln("failed to broadcast messages batch:", string(resJSON))
-				return
-			}
+			resJSON, _ := json.MarshalIndent(res, "", "\t")
+			c.logger.WithField("size", len(toSubmit)).WithError(err).Errorln("failed to broadcast messages batch:", string(resJSON))
+			return
 		}
 
-		if res.TxResponse.Code != 0 {
-			err = errors.Errorf("error %d (%s): %s", res.TxResponse.Code, res.TxResponse.Codespace, res.TxResponse.RawLog)
-			log.WithField("txHash", res.TxResponse.TxHash).WithError(err).Errorln("failed to broadcast messages batch")
+		if txErr := NewTxError(res.TxResponse); txErr != nil {
+			log.WithFields(log.Fields{
+				"txHash":    res.TxResponse.TxHash,
+				"retryable": IsRetryable(txErr),
+			}).WithError(txErr).Errorln("failed to broadcast messages batch")
 		} else {
 			log.WithField("txHash", res.TxResponse.TxHash).Debugln("msg batch broadcasted successfully at height", res.TxResponse.Height)
 		}
@@ -1035,6 +1320,11 @@ func (c *chainClient) CreateSpotOrder(defaultSubaccountID eth.Hash, d *SpotOrder
 		panic(errors.Errorf("Invalid spot market id for %s network (%s)", c.network.Name, d.MarketId))
 	}
 
+	feeRecipient, err := c.orderFeeRecipient(defaultSubaccountID, d.FeeRecipient, d.Referral)
+	if err != nil {
+		panic(err)
+	}
+
 	orderSize := market.QuantityToChainFormat(d.Quantity)
 	orderPrice := market.PriceToChainFormat(d.Price)
 
@@ -1043,7 +1333,7 @@ func (c *chainClient) CreateSpotOrder(defaultSubaccountID eth.Hash, d *SpotOrder
 		OrderType: d.OrderType,
 		OrderInfo: exchangetypes.OrderInfo{
 			SubaccountId: defaultSubaccountID.Hex(),
-			FeeRecipient: d.FeeRecipient,
+			FeeRecipient: feeRecipient,
 			Price:        orderPrice,
 			Quantity:     orderSize,
 			Cid:          d.Cid,
@@ -1067,6 +1357,10 @@ func (c *chainClient) CreateDerivativeOrder(defaultSubaccountID eth.Hash, d *Der
 	if !isPresent {
 		panic(errors.Errorf("Invalid derivative market id for %s network (%s)", c.network.Name, d.MarketId))
 	}
+	feeRecipient, err := c.orderFeeRecipient(defaultSubaccountID, d.FeeRecipient, d.Referral)
+	if err != nil {
+		panic(err)
+	}
 
 	orderSize := market.QuantityToChainFormat(d.Quantity)
 	orderPrice := market.PriceToChainFormat(d.Price)
@@ -1082,7 +1376,7 @@ func (c *chainClient) CreateDerivativeOrder(defaultSubaccountID eth.Hash, d *Der
 		Margin:    orderMargin,
 		OrderInfo: exchangetypes.OrderInfo{
 			SubaccountId: defaultSubaccountID.Hex(),
-			FeeRecipient: d.FeeRecipient,
+			FeeRecipient: feeRecipient,
 			Price:        orderPrice,
 			Quantity:     orderSize,
 			Cid:          d.Cid,
@@ -1526,6 +1820,9 @@ type DerivativeOrderData struct {
 	Quantity     decimal.Decimal
 	Leverage     decimal.Decimal
 	FeeRecipient string
+	// Referral is the name of a referral of the client (see common.OptionReferral) whose address is the fee
+	// recipient of the order. It can not be set with FeeRecipient
+	Referral     string
 	MarketId     string
 	IsReduceOnly bool
 	Cid          string
@@ -1536,8 +1833,11 @@ type SpotOrderData struct {
 	Price        decimal.Decimal
 	Quantity     decimal.Decimal
 	FeeRecipient string
-	MarketId     string
-	Cid          string
+	// Referral is the name of a referral of the client whose address is the fee recipient of the order, see
+	// DerivativeOrderData
+	Referral string
+	MarketId string
+	Cid      string
 }
 
 type OrderCancelData struct {
@@ -1612,6 +1912,168 @@ func (c *chainClient) FetchCommunityPool(ctx context.Context) (*distributiontype
 	return c.distributionQueryClient.CommunityPool(ctx, req)
 }
 
+// Staking module
+func (c *chainClient) FetchValidators(ctx context.Context, status string, pagination *query.PageRequest) (*stakingtypes.QueryValidatorsResponse, error) {
+	req := &stakingtypes.QueryValidatorsRequest{
+		Status:     status,
+		Pagination: pagination,
+	}
+	return c.stakingQueryClient.Validators(ctx, req)
+}
+
+func (c *chainClient) FetchValidator(ctx context.Context, validatorAddress string) (*stakingtypes.QueryValidatorResponse, error) {
+	req := &stakingtypes.QueryValidatorRequest{
+		ValidatorAddr: validatorAddress,
+	}
+	return c.stakingQueryClient.Validator(ctx, req)
+}
+
+func (c *chainClient) FetchDelegation(ctx context.Context, delegatorAddress string, validatorAddress string) (*stakingtypes.QueryDelegationResponse, error) {
+	req := &stakingtypes.QueryDelegationRequest{
+		DelegatorAddr: delegatorAddress,
+		ValidatorAddr: validatorAddress,
+	}
+	return c.stakingQueryClient.Delegation(ctx, req)
+}
+
+func (c *chainClient) FetchDelegatorDelegations(ctx context.Context, delegatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryDelegatorDelegationsResponse, error) {
+	req := &stakingtypes.QueryDelegatorDelegationsRequest{
+		DelegatorAddr: delegatorAddress,
+		Pagination:    pagination,
+	}
+	return c.stakingQueryClient.DelegatorDelegations(ctx, req)
+}
+
+func (c *chainClient) FetchUnbondingDelegation(ctx context.Context, delegatorAddress string, validatorAddress string) (*stakingtypes.QueryUnbondingDelegationResponse, error) {
+	req := &stakingtypes.QueryUnbondingDelegationRequest{
+		DelegatorAddr: delegatorAddress,
+		ValidatorAddr: validatorAddress,
+	}
+	return c.stakingQueryClient.UnbondingDelegation(ctx, req)
+}
+
+func (c *chainClient) FetchDelegatorUnbondingDelegations(ctx context.Context, delegatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryDelegatorUnbondingDelegationsResponse, error) {
+	req := &stakingtypes.QueryDelegatorUnbondingDelegationsRequest{
+		DelegatorAddr: delegatorAddress,
+		Pagination:    pagination,
+	}
+	return c.stakingQueryClient.DelegatorUnbondingDelegations(ctx, req)
+}
+
+func (c *chainClient) FetchRedelegations(ctx context.Context, delegatorAddress string, srcValidatorAddress string, dstValidatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryRedelegationsResponse, error) {
+	req := &stakingtypes.QueryRedelegationsRequest{
+		DelegatorAddr:    delegatorAddress,
+		SrcValidatorAddr: srcValidatorAddress,
+		DstValidatorAddr: dstValidatorAddress,
+		Pagination:       pagination,
+	}
+	return c.stakingQueryClient.Redelegations(ctx, req)
+}
+
+func (c *chainClient) FetchStakingPool(ctx context.Context) (*stakingtypes.QueryPoolResponse, error) {
+	req := &stakingtypes.QueryPoolRequest{}
+	return c.stakingQueryClient.Pool(ctx, req)
+}
+
+func (c *chainClient) FetchStakingParams(ctx context.Context) (*stakingtypes.QueryParamsResponse, error) {
+	req := &stakingtypes.QueryParamsRequest{}
+	return c.stakingQueryClient.Params(ctx, req)
+}
+
+// IBC Transfer module
+func (c *chainClient) FetchDenomTrace(ctx context.Context, hash string) (*ibctransfertypes.QueryDenomTraceResponse, error) {
+	req := &ibctransfertypes.QueryDenomTraceRequest{
+		Hash: hash,
+	}
+	return c.ibcTransferQueryClient.DenomTrace(ctx, req)
+}
+
+func (c *chainClient) FetchDenomTraces(ctx context.Context, pagination *query.PageRequest) (*ibctransfertypes.QueryDenomTracesResponse, error) {
+	req := &ibctransfertypes.QueryDenomTracesRequest{
+		Pagination: pagination,
+	}
+	return c.ibcTransferQueryClient.DenomTraces(ctx, req)
+}
+
+func (c *chainClient) FetchDenomHash(ctx context.Context, trace string) (*ibctransfertypes.QueryDenomHashResponse, error) {
+	req := &ibctransfertypes.QueryDenomHashRequest{
+		Trace: trace,
+	}
+	return c.ibcTransferQueryClient.DenomHash(ctx, req)
+}
+
+func (c *chainClient) FetchEscrowAddress(ctx context.Context, portId string, channelId string) (*ibctransfertypes.QueryEscrowAddressResponse, error) {
+	req := &ibctransfertypes.QueryEscrowAddressRequest{
+		PortId:    portId,
+		ChannelId: channelId,
+	}
+	return c.ibcTransferQueryClient.EscrowAddress(ctx, req)
+}
+
+func (c *chainClient) FetchTotalEscrowForDenom(ctx context.Context, denom string) (*ibctransfertypes.QueryTotalEscrowForDenomResponse, error) {
+	req := &ibctransfertypes.QueryTotalEscrowForDenomRequest{
+		Denom: denom,
+	}
+	return c.ibcTransferQueryClient.TotalEscrowForDenom(ctx, req)
+}
+
+// IBC Core Channel module
+func (c *chainClient) FetchIBCChannel(ctx context.Context, portId string, channelId string) (*ibcchanneltypes.QueryChannelResponse, error) {
+	req := &ibcchanneltypes.QueryChannelRequest{
+		PortId:    portId,
+		ChannelId: channelId,
+	}
+	return c.ibcChannelQueryClient.Channel(ctx, req)
+}
+
+func (c *chainClient) FetchIBCChannelClientState(ctx context.Context, portId string, channelId string) (*ibcchanneltypes.QueryChannelClientStateResponse, error) {
+	req := &ibcchanneltypes.QueryChannelClientStateRequest{
+		PortId:    portId,
+		ChannelId: channelId,
+	}
+	return c.ibcChannelQueryClient.ChannelClientState(ctx, req)
+}
+
+// FetchIBCPacketCommitments returns the commitments of the packets sent through the channel that have not been
+// acknowledged or timed out yet (i.e. the pending outgoing transfers)
+func (c *chainClient) FetchIBCPacketCommitments(ctx context.Context, portId string, channelId string, pagination *query.PageRequest) (*ibcchanneltypes.QueryPacketCommitmentsResponse, error) {
+	req := &ibcchanneltypes.QueryPacketCommitmentsRequest{
+		PortId:     portId,
+		ChannelId:  channelId,
+		Pagination: pagination,
+	}
+	return c.ibcChannelQueryClient.PacketCommitments(ctx, req)
+}
+
+func (c *chainClient) FetchIBCUnreceivedAcks(ctx context.Context, portId string, channelId string, packetAckSequences []uint64) (*ibcchanneltypes.QueryUnreceivedAcksResponse, error) {
+	req := &ibcchanneltypes.QueryUnreceivedAcksRequest{
+		PortId:             portId,
+		ChannelId:          channelId,
+		PacketAckSequences: packetAckSequences,
+	}
+	return c.ibcChannelQueryClient.UnreceivedAcks(ctx, req)
+}
+
+func (c *chainClient) ComputeIBCTransferTimeout(ctx context.Context, portId string, channelId string, blockOffset uint64, timeoutDuration time.Duration) (clienttypes.Height, uint64, error) {
+	res, err := c.FetchIBCChannelClientState(ctx, portId, channelId)
+	if err != nil {
+		return clienttypes.ZeroHeight(), 0, errors.Wrapf(err, "failed to query client state for channel %s/%s", portId, channelId)
+	}
+	if res.IdentifiedClientState == nil || res.IdentifiedClientState.ClientState == nil {
+		return clienttypes.ZeroHeight(), 0, errors.Errorf("channel %s/%s has no client state", portId, channelId)
+	}
+
+	var clientState ibcexported.ClientState
+	if err := c.ctx.InterfaceRegistry.UnpackAny(res.IdentifiedClientState.ClientState, &clientState); err != nil {
+		return clienttypes.ZeroHeight(), 0, errors.Wrap(err, "failed to unpack channel client state")
+	}
+
+	timeoutHeight := IBCTimeoutHeight(clientState.GetLatestHeight(), blockOffset)
+	timeoutTimestamp := IBCTimeoutTimestamp(c.opts.Clock.Now(), timeoutDuration)
+
+	return timeoutHeight, timeoutTimestamp, nil
+}
+
 // Chain exchange module
 func (c *chainClient) FetchSubaccountDeposits(ctx context.Context, subaccountId string) (*exchangetypes.QuerySubaccountDepositsResponse, error) {
 	req := &exchangetypes.QuerySubaccountDepositsRequest{
@@ -1620,6 +2082,21 @@ func (c *chainClient) FetchSubaccountDeposits(ctx context.Context, subaccountId
 	return c.exchangeQueryClient.SubaccountDeposits(ctx, req)
 }
 
+func (c *chainClient) FetchExchangeParams(ctx context.Context) (*exchangetypes.QueryExchangeParamsResponse, error) {
+	req := &exchangetypes.QueryExchangeParamsRequest{}
+	return c.exchangeQueryClient.QueryExchangeParams(ctx, req)
+}
+
+func (c *chainClient) CachedExchangeParams(ctx context.Context) (exchangetypes.Params, error) {
+	return c.exchangeParamsCache.get(ctx, func(ctx context.Context) (*exchangetypes.Params, error) {
+		res, err := c.FetchExchangeParams(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &res.Params, nil
+	})
+}
+
 func (c *chainClient) FetchSubaccountDeposit(ctx context.Context, subaccountId string, denom string) (*exchangetypes.QuerySubaccountDepositResponse, error) {
 	req := &exchangetypes.QuerySubaccountDepositRequest{
 		SubaccountId: subaccountId,
@@ -1894,6 +2371,16 @@ func (c *chainClient) FetchChainExpiryFuturesMarketInfo(ctx context.Context, mar
 	return c.exchangeQueryClient.ExpiryFuturesMarketInfo(ctx, req)
 }
 
+func (c *chainClient) FetchExpiryFuturesSettlementPrice(ctx context.Context, marketId string) (sdk.Dec, bool, error) {
+	res, err := c.FetchChainExpiryFuturesMarketInfo(ctx, marketId)
+	if err != nil {
+		return sdk.Dec{}, false, err
+	}
+
+	settlementPrice, settled := ExpiryFuturesSettlementPrice(res.Info)
+	return settlementPrice, settled, nil
+}
+
 func (c *chainClient) FetchChainPerpetualMarketFunding(ctx context.Context, marketId string) (*exchangetypes.QueryPerpetualMarketFundingResponse, error) {
 	req := &exchangetypes.QueryPerpetualMarketFundingRequest{
 		MarketId: marketId,
@@ -1922,6 +2409,59 @@ func (c *chainClient) FetchPendingTradeRewardPoints(ctx context.Context, account
 	return c.exchangeQueryClient.PendingTradeRewardPoints(ctx, req)
 }
 
+func (c *chainClient) FetchPendingTradeRewardPointsForPool(ctx context.Context, accounts []string, pendingPoolTimestamp int64) (*exchangetypes.QueryTradeRewardPointsResponse, error) {
+	req := &exchangetypes.QueryTradeRewardPointsRequest{
+		Accounts:             accounts,
+		PendingPoolTimestamp: pendingPoolTimestamp,
+	}
+	return c.exchangeQueryClient.PendingTradeRewardPoints(ctx, req)
+}
+
+func (c *chainClient) FetchTradingRewardsEstimate(ctx context.Context, account string) (*TradingRewardsEstimate, error) {
+	campaign, err := c.FetchTradeRewardCampaign(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the trade reward campaign")
+	}
+
+	estimate := &TradingRewardsEstimate{}
+
+	// the first pool of the schedule is the one of the running campaign
+	if len(campaign.TradingRewardPoolCampaignSchedule) > 0 {
+		pool := campaign.TradingRewardPoolCampaignSchedule[0]
+		res, err := c.FetchTradeRewardPoints(ctx, []string{account})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the trade reward points")
+		}
+		accountPoints := pointsForFirstAccount(res)
+		estimate.Current = &TradingRewardsPoolEstimate{
+			StartTimestamp:   pool.StartTimestamp,
+			AccountPoints:    accountPoints,
+			TotalPoints:      campaign.TotalTradeRewardPoints,
+			EstimatedRewards: EstimateTradingRewards(accountPoints, campaign.TotalTradeRewardPoints, pool.MaxCampaignRewards),
+		}
+	}
+
+	for i, pool := range campaign.PendingTradingRewardPoolCampaignSchedule {
+		if pool == nil || i >= len(campaign.PendingTotalTradeRewardPoints) {
+			continue
+		}
+		res, err := c.FetchPendingTradeRewardPointsForPool(ctx, []string{account}, pool.StartTimestamp)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the pending trade reward points for the pool starting at %d", pool.StartTimestamp)
+		}
+		accountPoints := pointsForFirstAccount(res)
+		totalPoints := campaign.PendingTotalTradeRewardPoints[i]
+		estimate.Pending = append(estimate.Pending, TradingRewardsPoolEstimate{
+			StartTimestamp:   pool.StartTimestamp,
+			AccountPoints:    accountPoints,
+			TotalPoints:      totalPoints,
+			EstimatedRewards: EstimateTradingRewards(accountPoints, totalPoints, pool.MaxCampaignRewards),
+		})
+	}
+
+	return estimate, nil
+}
+
 func (c *chainClient) FetchTradeRewardCampaign(ctx context.Context) (*exchangetypes.QueryTradeRewardCampaignResponse, error) {
 	req := &exchangetypes.QueryTradeRewardCampaignRequest{}
 	return c.exchangeQueryClient.TradeRewardCampaign(ctx, req)
@@ -1939,6 +2479,15 @@ func (c *chainClient) FetchFeeDiscountSchedule(ctx context.Context) (*exchangety
 	return c.exchangeQueryClient.FeeDiscountSchedule(ctx, req)
 }
 
+func (c *chainClient) FetchEffectiveFeeRates(ctx context.Context, account string, makerFeeRate decimal.Decimal, takerFeeRate decimal.Decimal) (FeeRates, error) {
+	res, err := c.FetchFeeDiscountAccountInfo(ctx, account)
+	if err != nil {
+		return FeeRates{}, err
+	}
+
+	return ComputeEffectiveFeeRates(makerFeeRate, takerFeeRate, res.AccountInfo), nil
+}
+
 func (c *chainClient) FetchBalanceMismatches(ctx context.Context, dustFactor int64) (*exchangetypes.QueryBalanceMismatchesResponse, error) {
 	req := &exchangetypes.QueryBalanceMismatchesRequest{
 		DustFactor: dustFactor,
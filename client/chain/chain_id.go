@@ -0,0 +1,70 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	gethsigner "github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/version"
+)
+
+const nodeVersionCheckTimeout = 10 * time.Second
+
+var (
+	// ErrWrongChainID is returned when the chain ID used to sign the txs or hash the orders is not the one of the
+	// network. The chain rejects txs signed for another chain ID, and assigns other hashes to the orders
+	ErrWrongChainID = errors.New("wrong chain ID")
+	// ErrWrongExchangeAddress is returned when the order hash domain verifying contract is not the network exchange
+	ErrWrongExchangeAddress = errors.New("wrong exchange address")
+)
+
+// ValidateChainID checks that chainId (the chain ID of the client context signing the txs) is the chain ID of the
+// network preset. Networks without a chain ID (e.g. custom local networks) accept any chain ID
+func ValidateChainID(network common.Network, chainId string) error {
+	if network.ChainId == "" || chainId == network.ChainId {
+		return nil
+	}
+	return errors.Wrapf(ErrWrongChainID, "the client context chain ID is %q but the %s network chain ID is %q", chainId, network.Name, network.ChainId)
+}
+
+// ValidateOrderHashDomain checks that the EIP712 domain used to compute the order hashes has the chain ID and the
+// verifying contract (exchange address) of the network preset. Values not set in the network are not checked
+func ValidateOrderHashDomain(network common.Network, orderDomain gethsigner.TypedDataDomain) error {
+	if network.OrderHashChainId != 0 {
+		expected := big.NewInt(network.OrderHashChainId)
+		if orderDomain.ChainId == nil || (*big.Int)(orderDomain.ChainId).Cmp(expected) != 0 {
+			return errors.Wrapf(ErrWrongChainID, "the order hash domain chain ID is %v but the %s network one is %d", orderDomain.ChainId, network.Name, network.OrderHashChainId)
+		}
+	}
+	if network.ExchangeAddress != "" && !strings.EqualFold(orderDomain.VerifyingContract, network.ExchangeAddress) {
+		return errors.Wrapf(ErrWrongExchangeAddress, "the order hash domain verifying contract is %s but the %s network exchange address is %s", orderDomain.VerifyingContract, network.Name, network.ExchangeAddress)
+	}
+	return nil
+}
+
+// checkNodeVersion runs the version handshake with the RPC node of the client context (see common.OptionNodeVersionCheck)
+func checkNodeVersion(clientCtx client.Context, mode version.CheckMode) error {
+	if clientCtx.Client == nil {
+		return errors.New("the node version check needs the RPC client of the client context")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), nodeVersionCheckTimeout)
+	defer cancel()
+	_, err := version.Handshake(ctx, clientCtx.Client, mode)
+	return err
+}
+
+// validateChainID checks the chain ID of the client context signing a tx, unless the validation was disabled with
+// common.OptionSkipChainIDValidation
+func (c *chainClient) validateChainID(chainId string) error {
+	if c.opts.SkipChainIDValidation {
+		return nil
+	}
+	return ValidateChainID(c.network, chainId)
+}
@@ -0,0 +1,52 @@
+package chain
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/version"
+)
+
+func TestValidateChainID(t *testing.T) {
+	network := common.LoadNetwork("mainnet", "lb")
+
+	assert.NoError(t, ValidateChainID(network, "injective-1"))
+	assert.True(t, errors.Is(ValidateChainID(network, "injective-888"), ErrWrongChainID))
+	assert.True(t, errors.Is(ValidateChainID(network, ""), ErrWrongChainID))
+	assert.NoError(t, ValidateChainID(common.Network{Name: "custom"}, "injective-777"))
+}
+
+func TestValidateOrderHashDomain(t *testing.T) {
+	network := common.LoadNetwork("testnet", "lb")
+	assert.NoError(t, ValidateOrderHashDomain(network, NetworkOrderHashDomain(network)))
+
+	wrongChainId := NetworkOrderHashDomain(network)
+	wrongChainId.ChainId = ethmath.NewHexOrDecimal256(1)
+	assert.True(t, errors.Is(ValidateOrderHashDomain(network, wrongChainId), ErrWrongChainID))
+
+	wrongContract := NetworkOrderHashDomain(network)
+	wrongContract.VerifyingContract = "0x0000000000000000000000000000000000000001"
+	assert.True(t, errors.Is(ValidateOrderHashDomain(network, wrongContract), ErrWrongExchangeAddress))
+	assert.NoError(t, ValidateOrderHashDomain(common.Network{Name: "custom"}, wrongContract))
+}
+
+func TestNewChainClientRejectsWrongChainID(t *testing.T) {
+	network := common.LoadNetwork("mainnet", "lb")
+	clientCtx := client.Context{ChainID: "injective-888"}
+
+	_, err := NewChainClient(clientCtx, network)
+	assert.True(t, errors.Is(err, ErrWrongChainID))
+}
+
+func TestNewChainClientNodeVersionCheckNeedsTheRPCClient(t *testing.T) {
+	network := common.LoadNetwork("mainnet", "lb")
+	clientCtx := client.Context{ChainID: network.ChainId}
+
+	_, err := NewChainClient(clientCtx, network, common.OptionNodeVersionCheck(version.CheckFail))
+	assert.ErrorContains(t, err, "node version check")
+}
@@ -14,6 +14,7 @@ import (
 	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
 	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
 	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/core"
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
 	"github.com/cosmos/cosmos-sdk/client"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -22,7 +23,13 @@ import (
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	ibcchanneltypes "github.com/cosmos/ibc-go/v7/modules/core/04-channel/types"
 	eth "github.com/ethereum/go-ethereum/common"
+	gethsigner "github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/shopspring/decimal"
 	"google.golang.org/grpc"
 )
 
@@ -50,6 +57,14 @@ func (c *MockChainClient) GetAccNonce() (accNum uint64, accSeq uint64) {
 	return 1, 2
 }
 
+func (c *MockChainClient) RotateSigningKey(from string, options KeyRotationOptions) (*KeyRotation, error) {
+	return &KeyRotation{}, nil
+}
+
+func (c *MockChainClient) AwaitAccountSequence(ctx context.Context, address sdk.AccAddress, sequence uint64) error {
+	return nil
+}
+
 func (c *MockChainClient) SimulateMsg(clientCtx client.Context, msgs ...sdk.Msg) (*txtypes.SimulateResponse, error) {
 	return &txtypes.SimulateResponse{}, nil
 }
@@ -62,6 +77,26 @@ func (c *MockChainClient) SyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastT
 	return &txtypes.BroadcastTxResponse{}, nil
 }
 
+func (c *MockChainClient) AsyncBroadcastMsgWithMemo(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	return &txtypes.BroadcastTxResponse{}, nil
+}
+
+func (c *MockChainClient) SyncBroadcastMsgWithMemo(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	return &txtypes.BroadcastTxResponse{}, nil
+}
+
+func (c *MockChainClient) AsyncBroadcastCancelMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error) {
+	return &txtypes.BroadcastTxResponse{}, nil, nil
+}
+
+func (c *MockChainClient) SyncBroadcastCancelMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error) {
+	return &txtypes.BroadcastTxResponse{}, nil, nil
+}
+
+func (c *MockChainClient) SyncBroadcastCreateMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CreateResponse, error) {
+	return &txtypes.BroadcastTxResponse{}, nil, nil
+}
+
 func (c *MockChainClient) BuildSignedTx(clientCtx client.Context, accNum, accSeq, initialGas uint64, msg ...sdk.Msg) ([]byte, error) {
 	return *new([]byte), nil
 }
@@ -129,6 +164,14 @@ func (c *MockChainClient) GetBankSendEnabled(ctx context.Context, denoms []strin
 	return &banktypes.QuerySendEnabledResponse{}, nil
 }
 
+func (c *MockChainClient) SendINJ(recipient string, amount decimal.Decimal) (*txtypes.BroadcastTxResponse, error) {
+	return &txtypes.BroadcastTxResponse{}, nil
+}
+
+func (c *MockChainClient) Send(recipient string, token core.Token, amount decimal.Decimal) (*txtypes.BroadcastTxResponse, error) {
+	return &txtypes.BroadcastTxResponse{}, nil
+}
+
 func (c *MockChainClient) GetAuthzGrants(ctx context.Context, req authztypes.QueryGrantsRequest) (*authztypes.QueryGrantsResponse, error) {
 	return &authztypes.QueryGrantsResponse{}, nil
 }
@@ -176,14 +219,30 @@ func (c *MockChainClient) UpdateSubaccountNonceFromChain() error {
 	return nil
 }
 
+func (c *MockChainClient) UpdateSubaccountNonceFromChainWithContext(ctx context.Context) error {
+	return nil
+}
+
 func (c *MockChainClient) SynchronizeSubaccountNonce(subaccountId eth.Hash) error {
 	return nil
 }
 
+func (c *MockChainClient) SynchronizeSubaccountNonceWithContext(ctx context.Context, subaccountId eth.Hash) error {
+	return nil
+}
+
 func (c *MockChainClient) ComputeOrderHashes(spotOrders []exchangetypes.SpotOrder, derivativeOrders []exchangetypes.DerivativeOrder, subaccountId eth.Hash) (OrderHashes, error) {
 	return OrderHashes{}, nil
 }
 
+func (c *MockChainClient) ComputeOrderHashesWithContext(ctx context.Context, spotOrders []exchangetypes.SpotOrder, derivativeOrders []exchangetypes.DerivativeOrder, subaccountId eth.Hash) (OrderHashes, error) {
+	return OrderHashes{}, nil
+}
+
+func (c *MockChainClient) OrderHashDomain() gethsigner.TypedDataDomain {
+	return DefaultOrderHashDomain()
+}
+
 func (c *MockChainClient) SpotOrder(defaultSubaccountID eth.Hash, network common.Network, d *SpotOrderData) *exchangetypes.SpotOrder {
 	return c.CreateSpotOrder(defaultSubaccountID, d, MarketsAssistant{})
 }
@@ -326,7 +385,94 @@ func (c *MockChainClient) FetchCommunityPool(ctx context.Context) (*distribution
 	return &distributiontypes.QueryCommunityPoolResponse{}, nil
 }
 
+// Staking module
+func (c *MockChainClient) FetchValidators(ctx context.Context, status string, pagination *query.PageRequest) (*stakingtypes.QueryValidatorsResponse, error) {
+	return &stakingtypes.QueryValidatorsResponse{}, nil
+}
+
+func (c *MockChainClient) FetchValidator(ctx context.Context, validatorAddress string) (*stakingtypes.QueryValidatorResponse, error) {
+	return &stakingtypes.QueryValidatorResponse{}, nil
+}
+
+func (c *MockChainClient) FetchDelegation(ctx context.Context, delegatorAddress string, validatorAddress string) (*stakingtypes.QueryDelegationResponse, error) {
+	return &stakingtypes.QueryDelegationResponse{}, nil
+}
+
+func (c *MockChainClient) FetchDelegatorDelegations(ctx context.Context, delegatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryDelegatorDelegationsResponse, error) {
+	return &stakingtypes.QueryDelegatorDelegationsResponse{}, nil
+}
+
+func (c *MockChainClient) FetchUnbondingDelegation(ctx context.Context, delegatorAddress string, validatorAddress string) (*stakingtypes.QueryUnbondingDelegationResponse, error) {
+	return &stakingtypes.QueryUnbondingDelegationResponse{}, nil
+}
+
+func (c *MockChainClient) FetchDelegatorUnbondingDelegations(ctx context.Context, delegatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryDelegatorUnbondingDelegationsResponse, error) {
+	return &stakingtypes.QueryDelegatorUnbondingDelegationsResponse{}, nil
+}
+
+func (c *MockChainClient) FetchRedelegations(ctx context.Context, delegatorAddress string, srcValidatorAddress string, dstValidatorAddress string, pagination *query.PageRequest) (*stakingtypes.QueryRedelegationsResponse, error) {
+	return &stakingtypes.QueryRedelegationsResponse{}, nil
+}
+
+func (c *MockChainClient) FetchStakingPool(ctx context.Context) (*stakingtypes.QueryPoolResponse, error) {
+	return &stakingtypes.QueryPoolResponse{}, nil
+}
+
+func (c *MockChainClient) FetchStakingParams(ctx context.Context) (*stakingtypes.QueryParamsResponse, error) {
+	return &stakingtypes.QueryParamsResponse{}, nil
+}
+
+// IBC Transfer module
+func (c *MockChainClient) FetchDenomTrace(ctx context.Context, hash string) (*ibctransfertypes.QueryDenomTraceResponse, error) {
+	return &ibctransfertypes.QueryDenomTraceResponse{}, nil
+}
+
+func (c *MockChainClient) FetchDenomTraces(ctx context.Context, pagination *query.PageRequest) (*ibctransfertypes.QueryDenomTracesResponse, error) {
+	return &ibctransfertypes.QueryDenomTracesResponse{}, nil
+}
+
+func (c *MockChainClient) FetchDenomHash(ctx context.Context, trace string) (*ibctransfertypes.QueryDenomHashResponse, error) {
+	return &ibctransfertypes.QueryDenomHashResponse{}, nil
+}
+
+func (c *MockChainClient) FetchEscrowAddress(ctx context.Context, portId string, channelId string) (*ibctransfertypes.QueryEscrowAddressResponse, error) {
+	return &ibctransfertypes.QueryEscrowAddressResponse{}, nil
+}
+
+func (c *MockChainClient) FetchTotalEscrowForDenom(ctx context.Context, denom string) (*ibctransfertypes.QueryTotalEscrowForDenomResponse, error) {
+	return &ibctransfertypes.QueryTotalEscrowForDenomResponse{}, nil
+}
+
+// IBC Core Channel module
+func (c *MockChainClient) FetchIBCChannel(ctx context.Context, portId string, channelId string) (*ibcchanneltypes.QueryChannelResponse, error) {
+	return &ibcchanneltypes.QueryChannelResponse{}, nil
+}
+
+func (c *MockChainClient) FetchIBCChannelClientState(ctx context.Context, portId string, channelId string) (*ibcchanneltypes.QueryChannelClientStateResponse, error) {
+	return &ibcchanneltypes.QueryChannelClientStateResponse{}, nil
+}
+
+func (c *MockChainClient) FetchIBCPacketCommitments(ctx context.Context, portId string, channelId string, pagination *query.PageRequest) (*ibcchanneltypes.QueryPacketCommitmentsResponse, error) {
+	return &ibcchanneltypes.QueryPacketCommitmentsResponse{}, nil
+}
+
+func (c *MockChainClient) FetchIBCUnreceivedAcks(ctx context.Context, portId string, channelId string, packetAckSequences []uint64) (*ibcchanneltypes.QueryUnreceivedAcksResponse, error) {
+	return &ibcchanneltypes.QueryUnreceivedAcksResponse{}, nil
+}
+
+func (c *MockChainClient) ComputeIBCTransferTimeout(ctx context.Context, portId string, channelId string, blockOffset uint64, timeoutDuration time.Duration) (clienttypes.Height, uint64, error) {
+	return clienttypes.ZeroHeight(), 0, nil
+}
+
 // Chain exchange module
+func (c *MockChainClient) FetchExchangeParams(ctx context.Context) (*exchangetypes.QueryExchangeParamsResponse, error) {
+	return &exchangetypes.QueryExchangeParamsResponse{}, nil
+}
+
+func (c *MockChainClient) CachedExchangeParams(ctx context.Context) (exchangetypes.Params, error) {
+	return exchangetypes.Params{}, nil
+}
+
 func (c *MockChainClient) FetchSubaccountDeposits(ctx context.Context, subaccountId string) (*exchangetypes.QuerySubaccountDepositsResponse, error) {
 	return &exchangetypes.QuerySubaccountDepositsResponse{}, nil
 }
@@ -471,6 +617,10 @@ func (c *MockChainClient) FetchChainExpiryFuturesMarketInfo(ctx context.Context,
 	return &exchangetypes.QueryExpiryFuturesMarketInfoResponse{}, nil
 }
 
+func (c *MockChainClient) FetchExpiryFuturesSettlementPrice(ctx context.Context, marketId string) (sdk.Dec, bool, error) {
+	return sdk.Dec{}, false, nil
+}
+
 func (c *MockChainClient) FetchChainPerpetualMarketFunding(ctx context.Context, marketId string) (*exchangetypes.QueryPerpetualMarketFundingResponse, error) {
 	return &exchangetypes.QueryPerpetualMarketFundingResponse{}, nil
 }
@@ -491,6 +641,14 @@ func (c *MockChainClient) FetchTradeRewardCampaign(ctx context.Context) (*exchan
 	return &exchangetypes.QueryTradeRewardCampaignResponse{}, nil
 }
 
+func (c *MockChainClient) FetchPendingTradeRewardPointsForPool(ctx context.Context, accounts []string, pendingPoolTimestamp int64) (*exchangetypes.QueryTradeRewardPointsResponse, error) {
+	return &exchangetypes.QueryTradeRewardPointsResponse{}, nil
+}
+
+func (c *MockChainClient) FetchTradingRewardsEstimate(ctx context.Context, account string) (*TradingRewardsEstimate, error) {
+	return &TradingRewardsEstimate{}, nil
+}
+
 func (c *MockChainClient) FetchFeeDiscountAccountInfo(ctx context.Context, account string) (*exchangetypes.QueryFeeDiscountAccountInfoResponse, error) {
 	return &exchangetypes.QueryFeeDiscountAccountInfoResponse{}, nil
 }
@@ -499,6 +657,10 @@ func (c *MockChainClient) FetchFeeDiscountSchedule(ctx context.Context) (*exchan
 	return &exchangetypes.QueryFeeDiscountScheduleResponse{}, nil
 }
 
+func (c *MockChainClient) FetchEffectiveFeeRates(ctx context.Context, account string, makerFeeRate decimal.Decimal, takerFeeRate decimal.Decimal) (FeeRates, error) {
+	return FeeRates{MakerFeeRate: makerFeeRate, TakerFeeRate: takerFeeRate}, nil
+}
+
 func (c *MockChainClient) FetchBalanceMismatches(ctx context.Context, dustFactor int64) (*exchangetypes.QueryBalanceMismatchesResponse, error) {
 	return &exchangetypes.QueryBalanceMismatchesResponse{}, nil
 }
@@ -0,0 +1,256 @@
+package chain
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/exchange"
+)
+
+var (
+	ErrNetworkNotFound = errors.New("network not found in the client set")
+	ErrNetworkExists   = errors.New("network already in the client set")
+)
+
+// NetworkClients are the clients of one of the networks of a ClientSet
+type NetworkClients struct {
+	Network common.Network
+	// Keyring has the keys used to sign the network transactions. It is nil for read only clients
+	Keyring keyring.Keyring
+	Chain   ChainClient
+	// Exchange is the indexer client, nil when it was not requested
+	Exchange exchange.ExchangeClient
+}
+
+// NetworkConfig has the parameters used by ClientSet.Connect to create the clients of a network
+type NetworkConfig struct {
+	Network common.Network
+	// Keyring and From (name or address of the key) set the signer of the network. Each network should have its own
+	// keyring, so testnet keys are never used on mainnet and the other way around
+	Keyring keyring.Keyring
+	From    string
+	// WithExchange creates the exchange (indexer) client too
+	WithExchange bool
+	// Options are the options of the chain and exchange clients
+	Options []common.ClientOption
+}
+
+type networkTagKey struct{}
+
+// WithNetworkTag returns a context routing the ClientSet calls made with it to the network with the tag
+func WithNetworkTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, networkTagKey{}, tag)
+}
+
+// NetworkTag returns the network tag of the context, if it has one
+func NetworkTag(ctx context.Context) (string, bool) {
+	tag, found := ctx.Value(networkTagKey{}).(string)
+	return tag, found
+}
+
+// ClientSet holds the clients of several networks at the same time (e.g. mainnet and a testnet used for shadow
+// trading), each one with its own keyring, identified by a tag. Requests are routed to a network either with its tag
+// or with a context created by WithNetworkTag. The first network added is the default one, used for contexts without
+// tag
+type ClientSet struct {
+	mux        sync.RWMutex
+	networks   map[string]*NetworkClients
+	defaultTag string
+}
+
+func NewClientSet() *ClientSet {
+	return &ClientSet{
+		networks: make(map[string]*NetworkClients),
+	}
+}
+
+// Connect creates the clients of the network described by the config and adds them with the tag
+func (s *ClientSet) Connect(tag string, config NetworkConfig) (*NetworkClients, error) {
+	if s.has(tag) {
+		return nil, errors.Wrapf(ErrNetworkExists, "network %s", tag)
+	}
+
+	tmClient, err := rpchttp.New(config.Network.TmEndpoint, "/websocket")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the tendermint client of network %s", tag)
+	}
+	clientCtx, err := NewClientContext(config.Network.ChainId, config.From, config.Keyring)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the client context of network %s", tag)
+	}
+	clientCtx = clientCtx.WithNodeURI(config.Network.TmEndpoint).WithClient(tmClient)
+
+	chainClient, err := NewChainClient(clientCtx, config.Network, config.Options...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the chain client of network %s", tag)
+	}
+	clients := NetworkClients{
+		Network: config.Network,
+		Keyring: config.Keyring,
+		Chain:   chainClient,
+	}
+	if config.WithExchange {
+		exchangeClient, err := exchange.NewExchangeClient(config.Network, config.Options...)
+		if err != nil {
+			chainClient.Close()
+			return nil, errors.Wrapf(err, "failed to create the exchange client of network %s", tag)
+		}
+		clients.Exchange = exchangeClient
+	}
+
+	if err := s.Add(tag, clients); err != nil {
+		clients.close()
+		return nil, err
+	}
+	return s.Get(tag)
+}
+
+// Add adds clients created by the caller with the tag. The chain client must be connected to the chain of the network
+func (s *ClientSet) Add(tag string, clients NetworkClients) error {
+	if tag == "" {
+		return errors.New("the network tag can not be empty")
+	}
+	if clients.Chain == nil {
+		return errors.Errorf("network %s has no chain client", tag)
+	}
+	if chainId := clients.Chain.ClientContext().ChainID; chainId != "" && chainId != clients.Network.ChainId {
+		return errors.Errorf("the chain client of network %s uses chain %s instead of %s", tag, chainId, clients.Network.ChainId)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, found := s.networks[tag]; found {
+		return errors.Wrapf(ErrNetworkExists, "network %s", tag)
+	}
+	s.networks[tag] = &clients
+	if s.defaultTag == "" {
+		s.defaultTag = tag
+	}
+	return nil
+}
+
+// SetDefault sets the network used for the contexts without network tag
+func (s *ClientSet) SetDefault(tag string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, found := s.networks[tag]; !found {
+		return errors.Wrapf(ErrNetworkNotFound, "network %s", tag)
+	}
+	s.defaultTag = tag
+	return nil
+}
+
+// Get returns the clients of the network with the tag
+func (s *ClientSet) Get(tag string) (*NetworkClients, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	clients, found := s.networks[tag]
+	if !found {
+		return nil, errors.Wrapf(ErrNetworkNotFound, "network %s", tag)
+	}
+	return clients, nil
+}
+
+// Route returns the clients of the network tagged in the context, or of the default network if the context has no tag
+func (s *ClientSet) Route(ctx context.Context) (*NetworkClients, error) {
+	_, clients, err := s.route(ctx)
+	return clients, err
+}
+
+// Chain returns the chain client of the network the context is routed to
+func (s *ClientSet) Chain(ctx context.Context) (ChainClient, error) {
+	_, clients, err := s.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return clients.Chain, nil
+}
+
+// Exchange returns the exchange client of the network the context is routed to
+func (s *ClientSet) Exchange(ctx context.Context) (exchange.ExchangeClient, error) {
+	tag, clients, err := s.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if clients.Exchange == nil {
+		return nil, errors.Errorf("network %s has no exchange client", tag)
+	}
+	return clients.Exchange, nil
+}
+
+// Tags returns the tags of the networks, sorted
+func (s *ClientSet) Tags() []string {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	tags := make([]string, 0, len(s.networks))
+	for tag := range s.networks {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Remove closes the clients of the network and removes them from the set
+func (s *ClientSet) Remove(tag string) error {
+	s.mux.Lock()
+	clients, found := s.networks[tag]
+	if found {
+		delete(s.networks, tag)
+		if s.defaultTag == tag {
+			s.defaultTag = ""
+		}
+	}
+	s.mux.Unlock()
+
+	if !found {
+		return errors.Wrapf(ErrNetworkNotFound, "network %s", tag)
+	}
+	clients.close()
+	return nil
+}
+
+// Close closes the clients of all the networks
+func (s *ClientSet) Close() {
+	for _, tag := range s.Tags() {
+		_ = s.Remove(tag)
+	}
+}
+
+func (s *ClientSet) route(ctx context.Context) (string, *NetworkClients, error) {
+	tag, found := NetworkTag(ctx)
+	if !found {
+		s.mux.RLock()
+		tag = s.defaultTag
+		s.mux.RUnlock()
+		if tag == "" {
+			return "", nil, errors.Wrap(ErrNetworkNotFound, "the client set has no default network")
+		}
+	}
+	clients, err := s.Get(tag)
+	return tag, clients, err
+}
+
+func (s *ClientSet) has(tag string) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	_, found := s.networks[tag]
+	return found
+}
+
+func (c *NetworkClients) close() {
+	c.Chain.Close()
+	if c.Exchange != nil {
+		c.Exchange.Close()
+	}
+}
@@ -0,0 +1,72 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+type clientSetTestChainClient struct {
+	MockChainClient
+	chainId string
+	closed  int
+}
+
+func (c *clientSetTestChainClient) ClientContext() client.Context {
+	return client.Context{ChainID: c.chainId}
+}
+
+func (c *clientSetTestChainClient) Close() {
+	c.closed++
+}
+
+func TestClientSetRoutesByNetworkTag(t *testing.T) {
+	mainnet := &clientSetTestChainClient{chainId: "injective-1"}
+	testnet := &clientSetTestChainClient{chainId: "injective-888"}
+	set := NewClientSet()
+	assert.NoError(t, set.Add("mainnet", NetworkClients{Network: common.Network{ChainId: "injective-1"}, Chain: mainnet}))
+	assert.NoError(t, set.Add("testnet", NetworkClients{Network: common.Network{ChainId: "injective-888"}, Chain: testnet}))
+	assert.Equal(t, []string{"mainnet", "testnet"}, set.Tags())
+
+	// the first network is the default one
+	chainClient, err := set.Chain(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, mainnet, chainClient)
+	ctx := WithNetworkTag(context.Background(), "testnet")
+	chainClient, err = set.Chain(ctx)
+	assert.NoError(t, err)
+	assert.Same(t, testnet, chainClient)
+	tag, found := NetworkTag(ctx)
+	assert.True(t, found)
+	assert.Equal(t, "testnet", tag)
+
+	assert.NoError(t, set.SetDefault("testnet"))
+	chainClient, err = set.Chain(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, testnet, chainClient)
+
+	_, err = set.Chain(WithNetworkTag(context.Background(), "devnet"))
+	assert.True(t, errors.Is(err, ErrNetworkNotFound))
+	_, err = set.Exchange(ctx)
+	assert.EqualError(t, err, "network testnet has no exchange client")
+
+	err = set.Add("testnet", NetworkClients{Network: common.Network{ChainId: "injective-888"}, Chain: testnet})
+	assert.True(t, errors.Is(err, ErrNetworkExists))
+	// a client of another chain can not be added to the network
+	err = set.Add("devnet", NetworkClients{Network: common.Network{ChainId: "injective-777"}, Chain: mainnet})
+	assert.EqualError(t, err, "the chain client of network devnet uses chain injective-1 instead of injective-777")
+
+	assert.NoError(t, set.Remove("testnet"))
+	assert.Equal(t, 1, testnet.closed)
+	_, err = set.Route(context.Background())
+	assert.True(t, errors.Is(err, ErrNetworkNotFound))
+
+	set.Close()
+	assert.Equal(t, 1, mainnet.closed)
+	assert.Empty(t, set.Tags())
+}
@@ -0,0 +1,103 @@
+package chain
+
+import (
+	"sync"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/std"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	crisistypes "github.com/cosmos/cosmos-sdk/x/crisis/types"
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	evidencetypes "github.com/cosmos/cosmos-sdk/x/evidence/types"
+	feegranttypes "github.com/cosmos/cosmos-sdk/x/feegrant"
+	govv1types "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	paramproposaltypes "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	icatypes "github.com/cosmos/ibc-go/v7/modules/apps/27-interchain-accounts/types"
+	ibcfeetypes "github.com/cosmos/ibc-go/v7/modules/apps/29-fee/types"
+	ibcapplicationtypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	ibccoretypes "github.com/cosmos/ibc-go/v7/modules/core/types"
+	ibclightclienttypes "github.com/cosmos/ibc-go/v7/modules/light-clients/06-solomachine"
+	ibctenderminttypes "github.com/cosmos/ibc-go/v7/modules/light-clients/07-tendermint"
+
+	auction "github.com/InjectiveLabs/sdk-go/chain/auction/types"
+	keyscodec "github.com/InjectiveLabs/sdk-go/chain/crypto/codec"
+	exchange "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	insurance "github.com/InjectiveLabs/sdk-go/chain/insurance/types"
+	ocr "github.com/InjectiveLabs/sdk-go/chain/ocr/types"
+	oracle "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	peggy "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+	tokenfactory "github.com/InjectiveLabs/sdk-go/chain/tokenfactory/types"
+	chaintypes "github.com/InjectiveLabs/sdk-go/chain/types"
+	wasmx "github.com/InjectiveLabs/sdk-go/chain/wasmx/types"
+)
+
+var (
+	protoCodec     *codec.ProtoCodec
+	protoCodecOnce sync.Once
+)
+
+// RegisterInterfaces registers the msgs and interface implementations of all the modules known by the SDK: the
+// Injective modules (exchange, oracle, insurance, auction, peggy, ocr, wasmx, tokenfactory), the Injective account
+// and key types, and the Cosmos SDK, IBC and CosmWasm modules. Apps embedding the SDK types in their own Any values
+// can use it to decode them like the SDK does
+func RegisterInterfaces(registry types.InterfaceRegistry) {
+	keyscodec.RegisterInterfaces(registry)
+	std.RegisterInterfaces(registry)
+	exchange.RegisterInterfaces(registry)
+	oracle.RegisterInterfaces(registry)
+	insurance.RegisterInterfaces(registry)
+	auction.RegisterInterfaces(registry)
+	peggy.RegisterInterfaces(registry)
+	ocr.RegisterInterfaces(registry)
+	wasmx.RegisterInterfaces(registry)
+	chaintypes.RegisterInterfaces(registry)
+	tokenfactory.RegisterInterfaces(registry)
+
+	// more cosmos types
+	authtypes.RegisterInterfaces(registry)
+	authztypes.RegisterInterfaces(registry)
+	vestingtypes.RegisterInterfaces(registry)
+	banktypes.RegisterInterfaces(registry)
+	crisistypes.RegisterInterfaces(registry)
+	distributiontypes.RegisterInterfaces(registry)
+	evidencetypes.RegisterInterfaces(registry)
+	govtypes.RegisterInterfaces(registry)
+	govv1types.RegisterInterfaces(registry)
+	paramproposaltypes.RegisterInterfaces(registry)
+	ibcapplicationtypes.RegisterInterfaces(registry)
+	ibccoretypes.RegisterInterfaces(registry)
+	ibclightclienttypes.RegisterInterfaces(registry)
+	ibctenderminttypes.RegisterInterfaces(registry)
+	slashingtypes.RegisterInterfaces(registry)
+	stakingtypes.RegisterInterfaces(registry)
+	upgradetypes.RegisterInterfaces(registry)
+	feegranttypes.RegisterInterfaces(registry)
+	wasmtypes.RegisterInterfaces(registry)
+	icatypes.RegisterInterfaces(registry)
+	ibcfeetypes.RegisterInterfaces(registry)
+}
+
+// NewInterfaceRegistry creates an interface registry with all the SDK types (see RegisterInterfaces)
+func NewInterfaceRegistry() types.InterfaceRegistry {
+	registry := types.NewInterfaceRegistry()
+	RegisterInterfaces(registry)
+	return registry
+}
+
+// ProtoCodec returns a shared proto codec with all the SDK types registered. It must not be used to register more
+// types: apps registering their own types should call RegisterInterfaces on their registry instead
+func ProtoCodec() *codec.ProtoCodec {
+	protoCodecOnce.Do(func() {
+		protoCodec = codec.NewProtoCodec(NewInterfaceRegistry())
+	})
+	return protoCodec
+}
@@ -0,0 +1,55 @@
+package chain
+
+import (
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ibcfeetypes "github.com/cosmos/ibc-go/v7/modules/apps/29-fee/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	insurancetypes "github.com/InjectiveLabs/sdk-go/chain/insurance/types"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+func TestProtoCodecRoundTripsTheModuleMsgsInAny(t *testing.T) {
+	msgs := []sdk.Msg{
+		&exchangetypes.MsgCreateSpotLimitOrder{Sender: "inj1sender", Order: exchangetypes.SpotOrder{MarketId: riskSpotMarketId}},
+		&oracletypes.MsgRelayPriceFeedPrice{Sender: "inj1sender", Base: []string{"INJ"}, Quote: []string{"USDT"}},
+		&insurancetypes.MsgUnderwrite{Sender: "inj1sender", MarketId: riskDerivativeMarketId},
+		&peggytypes.MsgSendToEth{Sender: "inj1sender", EthDest: "0x0000000000000000000000000000000000000001"},
+		&ibcfeetypes.MsgRegisterPayee{PortId: "transfer", ChannelId: "channel-0"},
+	}
+
+	for _, msg := range msgs {
+		anyMsg, err := codectypes.NewAnyWithValue(msg)
+		assert.NoError(t, err)
+		data, err := ProtoCodec().Marshal(anyMsg)
+		assert.NoError(t, err)
+
+		var decodedAny codectypes.Any
+		assert.NoError(t, ProtoCodec().Unmarshal(data, &decodedAny))
+		var decoded sdk.Msg
+		assert.NoError(t, ProtoCodec().UnpackAny(&decodedAny, &decoded), sdk.MsgTypeURL(msg))
+		assert.Equal(t, msg, decoded)
+	}
+}
+
+func TestNewInterfaceRegistryHasTheSameTypesAsTheTxConfig(t *testing.T) {
+	registry := NewInterfaceRegistry()
+	msg := &ibcfeetypes.MsgRegisterPayee{PortId: "transfer", ChannelId: "channel-0"}
+
+	resolved, err := registry.Resolve(sdk.MsgTypeURL(msg))
+	assert.NoError(t, err)
+	assert.IsType(t, msg, resolved)
+
+	txBuilder := txDecoderConfig().NewTxBuilder()
+	assert.NoError(t, txBuilder.SetMsgs(msg))
+	txBytes, err := txDecoderConfig().TxEncoder()(txBuilder.GetTx())
+	assert.NoError(t, err)
+	decodedTx, err := txDecoderConfig().TxDecoder()(txBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, decodedTx.GetMsgs()[0])
+}
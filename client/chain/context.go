@@ -7,85 +7,16 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/codec/types"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
-	"github.com/cosmos/cosmos-sdk/std"
-	"github.com/cosmos/cosmos-sdk/x/auth/tx"
-	"github.com/pkg/errors"
-
-	keyscodec "github.com/InjectiveLabs/sdk-go/chain/crypto/codec"
-
-	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
-
-	auction "github.com/InjectiveLabs/sdk-go/chain/auction/types"
-	exchange "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
-	insurance "github.com/InjectiveLabs/sdk-go/chain/insurance/types"
-	ocr "github.com/InjectiveLabs/sdk-go/chain/ocr/types"
-	oracle "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
-	peggy "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
-	tokenfactory "github.com/InjectiveLabs/sdk-go/chain/tokenfactory/types"
-	chaintypes "github.com/InjectiveLabs/sdk-go/chain/types"
-	wasmx "github.com/InjectiveLabs/sdk-go/chain/wasmx/types"
-
 	cosmostypes "github.com/cosmos/cosmos-sdk/types"
 	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
-	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
-	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
-	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
-	crisistypes "github.com/cosmos/cosmos-sdk/x/crisis/types"
-	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
-	evidencetypes "github.com/cosmos/cosmos-sdk/x/evidence/types"
-	feegranttypes "github.com/cosmos/cosmos-sdk/x/feegrant"
-	govv1types "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
-	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
-	paramproposaltypes "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
-	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
-	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
-	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
-	icatypes "github.com/cosmos/ibc-go/v7/modules/apps/27-interchain-accounts/types"
-	ibcfeetypes "github.com/cosmos/ibc-go/v7/modules/apps/29-fee/types"
-	ibcapplicationtypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
-	ibccoretypes "github.com/cosmos/ibc-go/v7/modules/core/types"
-	ibclightclienttypes "github.com/cosmos/ibc-go/v7/modules/light-clients/06-solomachine"
-	ibctenderminttypes "github.com/cosmos/ibc-go/v7/modules/light-clients/07-tendermint"
+	"github.com/pkg/errors"
 )
 
 // NewTxConfig initializes new Cosmos TxConfig with certain signModes enabled.
 func NewTxConfig(signModes []signingtypes.SignMode) client.TxConfig {
-	interfaceRegistry := types.NewInterfaceRegistry()
-	keyscodec.RegisterInterfaces(interfaceRegistry)
-	std.RegisterInterfaces(interfaceRegistry)
-	exchange.RegisterInterfaces(interfaceRegistry)
-	oracle.RegisterInterfaces(interfaceRegistry)
-	insurance.RegisterInterfaces(interfaceRegistry)
-	auction.RegisterInterfaces(interfaceRegistry)
-	peggy.RegisterInterfaces(interfaceRegistry)
-	ocr.RegisterInterfaces(interfaceRegistry)
-	wasmx.RegisterInterfaces(interfaceRegistry)
-	chaintypes.RegisterInterfaces(interfaceRegistry)
-	tokenfactory.RegisterInterfaces(interfaceRegistry)
-
-	// more cosmos types
-	authtypes.RegisterInterfaces(interfaceRegistry)
-	authztypes.RegisterInterfaces(interfaceRegistry)
-	vestingtypes.RegisterInterfaces(interfaceRegistry)
-	banktypes.RegisterInterfaces(interfaceRegistry)
-	crisistypes.RegisterInterfaces(interfaceRegistry)
-	distributiontypes.RegisterInterfaces(interfaceRegistry)
-	evidencetypes.RegisterInterfaces(interfaceRegistry)
-	govtypes.RegisterInterfaces(interfaceRegistry)
-	govv1types.RegisterInterfaces(interfaceRegistry)
-	paramproposaltypes.RegisterInterfaces(interfaceRegistry)
-	ibcapplicationtypes.RegisterInterfaces(interfaceRegistry)
-	ibccoretypes.RegisterInterfaces(interfaceRegistry)
-	ibclightclienttypes.RegisterInterfaces(interfaceRegistry)
-	ibctenderminttypes.RegisterInterfaces(interfaceRegistry)
-	slashingtypes.RegisterInterfaces(interfaceRegistry)
-	stakingtypes.RegisterInterfaces(interfaceRegistry)
-	upgradetypes.RegisterInterfaces(interfaceRegistry)
-	feegranttypes.RegisterInterfaces(interfaceRegistry)
-	wasmtypes.RegisterInterfaces(interfaceRegistry)
-	icatypes.RegisterInterfaces(interfaceRegistry)
-
+	interfaceRegistry := NewInterfaceRegistry()
 	marshaler := codec.NewProtoCodec(interfaceRegistry)
 	return tx.NewTxConfig(marshaler, signModes)
 }
@@ -98,42 +29,7 @@ func NewClientContext(
 ) (client.Context, error) {
 	clientCtx := client.Context{}
 
-	interfaceRegistry := types.NewInterfaceRegistry()
-	keyscodec.RegisterInterfaces(interfaceRegistry)
-	std.RegisterInterfaces(interfaceRegistry)
-	exchange.RegisterInterfaces(interfaceRegistry)
-	insurance.RegisterInterfaces(interfaceRegistry)
-	auction.RegisterInterfaces(interfaceRegistry)
-	oracle.RegisterInterfaces(interfaceRegistry)
-	peggy.RegisterInterfaces(interfaceRegistry)
-	ocr.RegisterInterfaces(interfaceRegistry)
-	wasmx.RegisterInterfaces(interfaceRegistry)
-	chaintypes.RegisterInterfaces(interfaceRegistry)
-	tokenfactory.RegisterInterfaces(interfaceRegistry)
-
-	// more cosmos types
-	authtypes.RegisterInterfaces(interfaceRegistry)
-	authztypes.RegisterInterfaces(interfaceRegistry)
-	vestingtypes.RegisterInterfaces(interfaceRegistry)
-	banktypes.RegisterInterfaces(interfaceRegistry)
-	crisistypes.RegisterInterfaces(interfaceRegistry)
-	distributiontypes.RegisterInterfaces(interfaceRegistry)
-	evidencetypes.RegisterInterfaces(interfaceRegistry)
-	govtypes.RegisterInterfaces(interfaceRegistry)
-	govv1types.RegisterInterfaces(interfaceRegistry)
-	paramproposaltypes.RegisterInterfaces(interfaceRegistry)
-	ibcapplicationtypes.RegisterInterfaces(interfaceRegistry)
-	ibccoretypes.RegisterInterfaces(interfaceRegistry)
-	ibclightclienttypes.RegisterInterfaces(interfaceRegistry)
-	ibctenderminttypes.RegisterInterfaces(interfaceRegistry)
-	slashingtypes.RegisterInterfaces(interfaceRegistry)
-	stakingtypes.RegisterInterfaces(interfaceRegistry)
-	upgradetypes.RegisterInterfaces(interfaceRegistry)
-	feegranttypes.RegisterInterfaces(interfaceRegistry)
-	wasmtypes.RegisterInterfaces(interfaceRegistry)
-	icatypes.RegisterInterfaces(interfaceRegistry)
-	ibcfeetypes.RegisterInterfaces(interfaceRegistry)
-
+	interfaceRegistry := NewInterfaceRegistry()
 	marshaler := codec.NewProtoCodec(interfaceRegistry)
 	encodingConfig := EncodingConfig{
 		InterfaceRegistry: interfaceRegistry,
@@ -146,23 +42,11 @@ func NewClientContext(
 	var keyInfo keyring.Record
 
 	if kb != nil {
-		addr, err := cosmostypes.AccAddressFromBech32(fromSpec)
-		if err == nil {
-			record, err := kb.KeyByAddress(addr)
-			if err != nil {
-				err = errors.Wrapf(err, "failed to load key info by address %s", addr.String())
-				return clientCtx, err
-			}
-			keyInfo = *record
-		} else {
-			// failed to parse Bech32, is it a name?
-			record, err := kb.Key(fromSpec)
-			if err != nil {
-				err = errors.Wrapf(err, "no key in keyring for name: %s", fromSpec)
-				return clientCtx, err
-			}
-			keyInfo = *record
+		record, err := keyringRecord(kb, fromSpec)
+		if err != nil {
+			return clientCtx, err
 		}
+		keyInfo = *record
 	}
 
 	clientCtx = newContext(
@@ -175,6 +59,27 @@ func NewClientContext(
 	return clientCtx, nil
 }
 
+// keyringRecord returns the key of the keyring with the address or the name fromSpec
+func keyringRecord(kb keyring.Keyring, fromSpec string) (*keyring.Record, error) {
+	addr, err := cosmostypes.AccAddressFromBech32(fromSpec)
+	if err == nil {
+		record, err := kb.KeyByAddress(addr)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to load key info by address %s", addr.String())
+			return nil, err
+		}
+		return record, nil
+	}
+
+	// failed to parse Bech32, is it a name?
+	record, err := kb.Key(fromSpec)
+	if err != nil {
+		err = errors.Wrapf(err, "no key in keyring for name: %s", fromSpec)
+		return nil, err
+	}
+	return record, nil
+}
+
 type EncodingConfig struct {
 	InterfaceRegistry types.InterfaceRegistry
 	Marshaler         codec.Codec
@@ -0,0 +1,275 @@
+package chain
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// CreatedOrder is an order created by a tx, with the order hash echoed by the chain in the msg response
+type CreatedOrder struct {
+	// MsgIndex is the index of the msg creating the order in the tx
+	MsgIndex     int
+	MarketId     string
+	SubaccountId string
+	Cid          string
+	IsBuy        bool
+	IsDerivative bool
+	OrderHash    string
+}
+
+// CreateResponse is the typed result of a tx with order creation msgs
+type CreateResponse struct {
+	TxHash string
+	Height int64
+	// Orders has the orders created by the tx, in msg order. The orders of a MsgBatchUpdateOrders are the spot, then
+	// the derivative and then the binary options orders. Orders skipped by the chain in a MsgBatchUpdateOrders are not
+	// included
+	Orders []CreatedOrder
+}
+
+// OrderHashMismatch is an order whose echoed hash is not the hash computed locally. An empty hash is a missing order
+type OrderHashMismatch struct {
+	Index        int
+	IsDerivative bool
+	EchoedHash   string
+	ComputedHash string
+}
+
+func (m OrderHashMismatch) String() string {
+	kind := "spot"
+	if m.IsDerivative {
+		kind = "derivative"
+	}
+	return fmt.Sprintf("%s order %d: computed %s, echoed %s", kind, m.Index, hashOrNone(m.ComputedHash), hashOrNone(m.EchoedHash))
+}
+
+func hashOrNone(hash string) string {
+	if hash == "" {
+		return "none"
+	}
+	return hash
+}
+
+// CheckOrderHashes compares the hashes echoed for the orders of the subaccount with the hashes computed locally for
+// them (see ChainClient.ComputeOrderHashes), and returns the mismatches. A mismatch usually means that the local trade
+// nonce of the subaccount is out of sync, and it should be synchronized again before computing other hashes
+func (r *CreateResponse) CheckOrderHashes(subaccountId string, computed OrderHashes) []OrderHashMismatch {
+	var spotHashes, derivativeHashes []string
+	for _, order := range r.Orders {
+		if !strings.EqualFold(order.SubaccountId, subaccountId) {
+			continue
+		}
+		if order.IsDerivative {
+			derivativeHashes = append(derivativeHashes, order.OrderHash)
+		} else {
+			spotHashes = append(spotHashes, order.OrderHash)
+		}
+	}
+
+	mismatches := make([]OrderHashMismatch, 0)
+	compare := func(echoed []string, computed []string, isDerivative bool) {
+		for i := 0; i < len(echoed) || i < len(computed); i++ {
+			mismatch := OrderHashMismatch{Index: i, IsDerivative: isDerivative}
+			if i < len(echoed) {
+				mismatch.EchoedHash = echoed[i]
+			}
+			if i < len(computed) {
+				mismatch.ComputedHash = computed[i]
+			}
+			if !strings.EqualFold(mismatch.EchoedHash, mismatch.ComputedHash) {
+				mismatches = append(mismatches, mismatch)
+			}
+		}
+	}
+	compare(spotHashes, hashStrings(computed.Spot), false)
+	compare(derivativeHashes, hashStrings(computed.Derivative), true)
+	return mismatches
+}
+
+// DecodeCreateResponse decodes the order hashes echoed in the msg responses of a successful tx created with the msgs,
+// and pairs them with their orders
+func DecodeCreateResponse(txResponse *sdk.TxResponse, msgs []sdk.Msg) (*CreateResponse, error) {
+	if txResponse == nil {
+		return nil, errors.New("the tx response is empty")
+	}
+	if err := NewTxError(txResponse); err != nil {
+		return nil, err
+	}
+
+	msgData, err := decodeTxMsgData(txResponse.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the msg responses of tx %s", txResponse.TxHash)
+	}
+	if len(msgData.MsgResponses) != len(msgs) {
+		return nil, errors.Errorf("tx %s has %d msg responses for %d msgs", txResponse.TxHash, len(msgData.MsgResponses), len(msgs))
+	}
+
+	response := &CreateResponse{
+		TxHash: txResponse.TxHash,
+		Height: txResponse.Height,
+	}
+	for i, msg := range msgs {
+		orders, hashes, err := createdOrdersOfMsg(msg, msgData.MsgResponses[i].Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s in tx %s", msgData.MsgResponses[i].TypeUrl, txResponse.TxHash)
+		}
+		if len(orders) == 0 {
+			continue
+		}
+		if len(hashes) != len(orders) {
+			return nil, errors.Errorf("msg %d of tx %s has %d order hashes for %d orders", i, txResponse.TxHash, len(hashes), len(orders))
+		}
+		for j := range orders {
+			// the batch update orders failing are skipped by the chain and have no hash
+			if hashes[j] == "" {
+				continue
+			}
+			orders[j].MsgIndex = i
+			orders[j].OrderHash = hashes[j]
+			response.Orders = append(response.Orders, orders[j])
+		}
+	}
+	return response, nil
+}
+
+// hasCreateMsgs returns true if any of the msgs can create orders
+func hasCreateMsgs(msgs []sdk.Msg) bool {
+	for _, msg := range msgs {
+		switch msg.(type) {
+		case *exchangetypes.MsgCreateSpotLimitOrder,
+			*exchangetypes.MsgCreateSpotMarketOrder,
+			*exchangetypes.MsgBatchCreateSpotLimitOrders,
+			*exchangetypes.MsgCreateDerivativeLimitOrder,
+			*exchangetypes.MsgCreateDerivativeMarketOrder,
+			*exchangetypes.MsgBatchCreateDerivativeLimitOrders,
+			*exchangetypes.MsgCreateBinaryOptionsLimitOrder,
+			*exchangetypes.MsgCreateBinaryOptionsMarketOrder,
+			*exchangetypes.MsgBatchUpdateOrders:
+			return true
+		}
+	}
+	return false
+}
+
+// createdOrdersOfMsg returns the orders created by the msg and the hashes of its msg response, in the same order
+func createdOrdersOfMsg(msg sdk.Msg, data []byte) ([]CreatedOrder, []string, error) {
+	switch typedMsg := msg.(type) {
+	case *exchangetypes.MsgCreateSpotLimitOrder:
+		response := exchangetypes.MsgCreateSpotLimitOrderResponse{}
+		err := response.Unmarshal(data)
+		return []CreatedOrder{createdSpotOrder(&typedMsg.Order)}, []string{response.OrderHash}, err
+	case *exchangetypes.MsgCreateSpotMarketOrder:
+		response := exchangetypes.MsgCreateSpotMarketOrderResponse{}
+		err := response.Unmarshal(data)
+		return []CreatedOrder{createdSpotOrder(&typedMsg.Order)}, []string{response.OrderHash}, err
+	case *exchangetypes.MsgBatchCreateSpotLimitOrders:
+		response := exchangetypes.MsgBatchCreateSpotLimitOrdersResponse{}
+		err := response.Unmarshal(data)
+		orders := make([]CreatedOrder, 0, len(typedMsg.Orders))
+		for i := range typedMsg.Orders {
+			orders = append(orders, createdSpotOrder(&typedMsg.Orders[i]))
+		}
+		return orders, response.OrderHashes, err
+	case *exchangetypes.MsgCreateDerivativeLimitOrder:
+		response := exchangetypes.MsgCreateDerivativeLimitOrderResponse{}
+		err := response.Unmarshal(data)
+		return []CreatedOrder{createdDerivativeOrder(&typedMsg.Order)}, []string{response.OrderHash}, err
+	case *exchangetypes.MsgCreateDerivativeMarketOrder:
+		response := exchangetypes.MsgCreateDerivativeMarketOrderResponse{}
+		err := response.Unmarshal(data)
+		return []CreatedOrder{createdDerivativeOrder(&typedMsg.Order)}, []string{response.OrderHash}, err
+	case *exchangetypes.MsgBatchCreateDerivativeLimitOrders:
+		response := exchangetypes.MsgBatchCreateDerivativeLimitOrdersResponse{}
+		err := response.Unmarshal(data)
+		orders := make([]CreatedOrder, 0, len(typedMsg.Orders))
+		for i := range typedMsg.Orders {
+			orders = append(orders, createdDerivativeOrder(&typedMsg.Orders[i]))
+		}
+		return orders, response.OrderHashes, err
+	case *exchangetypes.MsgCreateBinaryOptionsLimitOrder:
+		response := exchangetypes.MsgCreateBinaryOptionsLimitOrderResponse{}
+		err := response.Unmarshal(data)
+		return []CreatedOrder{createdDerivativeOrder(&typedMsg.Order)}, []string{response.OrderHash}, err
+	case *exchangetypes.MsgCreateBinaryOptionsMarketOrder:
+		response := exchangetypes.MsgCreateBinaryOptionsMarketOrderResponse{}
+		err := response.Unmarshal(data)
+		return []CreatedOrder{createdDerivativeOrder(&typedMsg.Order)}, []string{response.OrderHash}, err
+	case *exchangetypes.MsgBatchUpdateOrders:
+		response := exchangetypes.MsgBatchUpdateOrdersResponse{}
+		if err := response.Unmarshal(data); err != nil {
+			return nil, nil, err
+		}
+		var orders []CreatedOrder
+		var hashes []string
+		// the hashes of each order kind are paired separately, so a count mismatch in one kind is not hidden by another
+		for _, kind := range []struct {
+			orders []CreatedOrder
+			hashes []string
+		}{
+			{orders: createdSpotOrders(typedMsg.SpotOrdersToCreate), hashes: response.SpotOrderHashes},
+			{orders: createdDerivativeOrders(typedMsg.DerivativeOrdersToCreate), hashes: response.DerivativeOrderHashes},
+			{orders: createdDerivativeOrders(typedMsg.BinaryOptionsOrdersToCreate), hashes: response.BinaryOptionsOrderHashes},
+		} {
+			if len(kind.orders) != len(kind.hashes) {
+				return nil, nil, errors.Errorf("%d order hashes for %d orders", len(kind.hashes), len(kind.orders))
+			}
+			orders = append(orders, kind.orders...)
+			hashes = append(hashes, kind.hashes...)
+		}
+		return orders, hashes, nil
+	}
+	return nil, nil, nil
+}
+
+func createdSpotOrder(order *exchangetypes.SpotOrder) CreatedOrder {
+	return CreatedOrder{
+		MarketId:     order.MarketId,
+		SubaccountId: order.OrderInfo.SubaccountId,
+		Cid:          order.OrderInfo.Cid,
+		IsBuy:        order.IsBuy(),
+	}
+}
+
+func createdDerivativeOrder(order *exchangetypes.DerivativeOrder) CreatedOrder {
+	return CreatedOrder{
+		MarketId:     order.MarketId,
+		SubaccountId: order.OrderInfo.SubaccountId,
+		Cid:          order.OrderInfo.Cid,
+		IsBuy:        order.OrderType.IsBuy(),
+		IsDerivative: true,
+	}
+}
+
+func createdSpotOrders(orders []*exchangetypes.SpotOrder) []CreatedOrder {
+	created := make([]CreatedOrder, 0, len(orders))
+	for _, order := range orders {
+		if order != nil {
+			created = append(created, createdSpotOrder(order))
+		}
+	}
+	return created
+}
+
+func createdDerivativeOrders(orders []*exchangetypes.DerivativeOrder) []CreatedOrder {
+	created := make([]CreatedOrder, 0, len(orders))
+	for _, order := range orders {
+		if order != nil {
+			created = append(created, createdDerivativeOrder(order))
+		}
+	}
+	return created
+}
+
+func hashStrings(hashes []ethcommon.Hash) []string {
+	values := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		values = append(values, hash.Hex())
+	}
+	return values
+}
@@ -0,0 +1,77 @@
+package chain
+
+import (
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func createTestMsgs() (*exchangetypes.MsgCreateSpotLimitOrder, *exchangetypes.MsgBatchUpdateOrders) {
+	spotOrder := orderGuardSpotOrderMsg(exchangetypes.OrderType_BUY, "2", "10")
+	spotOrder.Order.OrderInfo.Cid = "spot"
+	derivativeOrder := riskDerivativeOrderMsg(exchangetypes.OrderType_SELL, "100", "2", "50").Order
+	failingOrder := riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "1", "2", "0").Order
+	batch := &exchangetypes.MsgBatchUpdateOrders{
+		Sender:                   "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku",
+		DerivativeOrdersToCreate: []*exchangetypes.DerivativeOrder{&derivativeOrder, &failingOrder},
+	}
+	return spotOrder, batch
+}
+
+func TestDecodeCreateResponse(t *testing.T) {
+	spotMsg, batchMsg := createTestMsgs()
+	spotHash, err := ComputeSpotOrderHash(spotMsg.Order, 5)
+	assert.NoError(t, err)
+	derivativeHash, err := ComputeDerivativeOrderHash(*batchMsg.DerivativeOrdersToCreate[0], 6)
+	assert.NoError(t, err)
+
+	spotResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgCreateSpotLimitOrderResponse{OrderHash: spotHash.Hex()})
+	assert.NoError(t, err)
+	depositResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgDepositResponse{})
+	assert.NoError(t, err)
+	batchResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgBatchUpdateOrdersResponse{
+		DerivativeOrderHashes: []string{derivativeHash.Hex(), ""},
+	})
+	assert.NoError(t, err)
+	txResponse := cancelTestTxResponse(t, []*codectypes.Any{spotResponse, depositResponse, batchResponse})
+	msgs := []sdk.Msg{spotMsg, &exchangetypes.MsgDeposit{}, batchMsg}
+
+	response, err := DecodeCreateResponse(txResponse, msgs)
+	assert.NoError(t, err)
+	assert.Equal(t, "ABCD", response.TxHash)
+	assert.Equal(t, int64(100), response.Height)
+	assert.Equal(t, []CreatedOrder{
+		{MsgIndex: 0, MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId, Cid: "spot", IsBuy: true, OrderHash: spotHash.Hex()},
+		{MsgIndex: 2, MarketId: riskDerivativeMarketId, SubaccountId: riskSubaccountId, IsDerivative: true, OrderHash: derivativeHash.Hex()},
+	}, response.Orders)
+
+	// the hashes computed with the right nonce match
+	computed := OrderHashes{Spot: []ethcommon.Hash{spotHash}, Derivative: []ethcommon.Hash{derivativeHash}}
+	assert.Empty(t, response.CheckOrderHashes(riskSubaccountId, computed))
+	// a local nonce behind the chain one gives other hashes
+	staleHash, err := ComputeSpotOrderHash(spotMsg.Order, 4)
+	assert.NoError(t, err)
+	mismatches := response.CheckOrderHashes(riskSubaccountId, OrderHashes{Spot: []ethcommon.Hash{staleHash}, Derivative: []ethcommon.Hash{derivativeHash}})
+	assert.Equal(t, []OrderHashMismatch{{Index: 0, EchoedHash: spotHash.Hex(), ComputedHash: staleHash.Hex()}}, mismatches)
+	// the orders missing in the response are mismatches too
+	mismatches = response.CheckOrderHashes(riskSubaccountId, OrderHashes{Spot: []ethcommon.Hash{spotHash}})
+	assert.Equal(t, []OrderHashMismatch{{Index: 0, IsDerivative: true, EchoedHash: derivativeHash.Hex()}}, mismatches)
+	assert.Equal(t, "derivative order 0: computed none, echoed "+derivativeHash.Hex(), mismatches[0].String())
+
+	_, err = DecodeCreateResponse(txResponse, msgs[:2])
+	assert.EqualError(t, err, "tx ABCD has 3 msg responses for 2 msgs")
+}
+
+func TestDecodeCreateResponseHashCountMismatch(t *testing.T) {
+	_, batchMsg := createTestMsgs()
+	batchResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgBatchUpdateOrdersResponse{DerivativeOrderHashes: []string{"0x01"}})
+	assert.NoError(t, err)
+
+	_, err = DecodeCreateResponse(cancelTestTxResponse(t, []*codectypes.Any{batchResponse}), []sdk.Msg{batchMsg})
+	assert.EqualError(t, err, "failed to decode /injective.exchange.v1beta1.MsgBatchUpdateOrdersResponse in tx ABCD: 1 order hashes for 2 orders")
+}
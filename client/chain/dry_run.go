@@ -0,0 +1,198 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+// DryRunTx is a tx simulated by a client in dry run mode instead of being broadcasted
+type DryRunTx struct {
+	TxHash    string
+	Time      time.Time
+	Sequence  uint64
+	Msgs      []sdk.Msg
+	Memo      string
+	Fee       sdk.Coins
+	GasWanted uint64
+	GasUsed   uint64
+	// Responses are the msg responses of the simulation, decoded when their type is known
+	Responses []proto.Message
+	Events    []abci.Event
+	Log       string
+	// Err is the simulation error when the chain would have rejected the tx
+	Err error
+}
+
+// DryRunLog is a DryRunRecorder keeping the simulated txs in memory, for the tests and the strategies checking what
+// they would have broadcasted
+type DryRunLog struct {
+	mux   sync.Mutex
+	txs   []DryRunTx
+	limit int
+	clock clock.Clock
+}
+
+// NewDryRunLog creates a dry run log keeping the last limit txs, or all of them if limit is 0
+func NewDryRunLog(limit int) *DryRunLog {
+	return &DryRunLog{
+		limit: limit,
+		clock: clock.Real(),
+	}
+}
+
+// SetClock sets the clock of the recorded tx times
+func (l *DryRunLog) SetClock(c clock.Clock) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.clock = clock.OrReal(c)
+}
+
+// RecordDryRun decodes and records a simulated tx. Txs that can't be decoded are recorded with the decoding error
+func (l *DryRunLog) RecordDryRun(txBytes []byte, simulation *txtypes.SimulateResponse, simulationErr error) {
+	dryRunTx := DryRunTx{
+		TxHash: fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash()),
+		Err:    simulationErr,
+	}
+
+	decodedTx, err := DecodeTx(txBytes)
+	if err != nil {
+		dryRunTx.Err = err
+	} else {
+		dryRunTx.Msgs = decodedTx.Msgs
+		dryRunTx.Memo = decodedTx.Memo
+		dryRunTx.Fee = decodedTx.Fee
+		dryRunTx.GasWanted = decodedTx.GasLimit
+		if len(decodedTx.Signers) > 0 {
+			dryRunTx.Sequence = decodedTx.Signers[0].Sequence
+		}
+	}
+
+	if simulation != nil {
+		if simulation.GasInfo != nil {
+			dryRunTx.GasUsed = simulation.GasInfo.GasUsed
+		}
+		if simulation.Result != nil {
+			dryRunTx.Events = simulation.Result.Events
+			dryRunTx.Log = simulation.Result.Log
+			dryRunTx.Responses = decodeMsgResponses(simulation.Result.MsgResponses)
+		}
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	dryRunTx.Time = l.clock.Now()
+	l.txs = append(l.txs, dryRunTx)
+	if l.limit > 0 && len(l.txs) > l.limit {
+		l.txs = append([]DryRunTx{}, l.txs[len(l.txs)-l.limit:]...)
+	}
+}
+
+// Txs returns the recorded txs, oldest first
+func (l *DryRunLog) Txs() []DryRunTx {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return append([]DryRunTx{}, l.txs...)
+}
+
+// Reset removes the recorded txs
+func (l *DryRunLog) Reset() {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	l.txs = nil
+}
+
+func decodeMsgResponses(msgResponses []*codectypes.Any) []proto.Message {
+	registry := ProtoCodec().InterfaceRegistry()
+	responses := make([]proto.Message, 0, len(msgResponses))
+	for _, msgResponse := range msgResponses {
+		var response txtypes.MsgResponse
+		if err := registry.UnpackAny(msgResponse, &response); err != nil {
+			continue
+		}
+		if message, ok := response.(proto.Message); ok {
+			responses = append(responses, message)
+		}
+	}
+	return responses
+}
+
+// dryRunTx simulates the msgs, signs the tx with the simulated gas and reports it to the dry run recorder instead of
+// broadcasting it
+func (c *chainClient) dryRunTx(clientCtx client.Context, txf tx.Factory, msgs []sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	simTxBytes, err := txf.BuildSimTx(msgs...)
+	if err != nil {
+		err = errors.Wrap(err, "failed to build sim tx bytes")
+		return nil, err
+	}
+	ctx := c.getCookie(context.Background())
+	simRes, simErr := c.txClient.Simulate(ctx, &txtypes.SimulateRequest{TxBytes: simTxBytes})
+	if simErr == nil {
+		txf = txf.WithGas(uint64(c.gasAdjustment(txf, msgs) * float64(simRes.GasInfo.GasUsed)))
+	}
+
+	txBytes, err := c.signTx(clientCtx, txf, msgs)
+	if err != nil {
+		return nil, err
+	}
+	c.opts.DryRun.RecordDryRun(txBytes, simRes, simErr)
+	if simErr != nil {
+		return nil, errors.Wrap(simErr, "failed to CalculateGas")
+	}
+	c.gasWanted = txf.Gas()
+
+	return dryRunResponse(txBytes, txf.Gas(), simRes), nil
+}
+
+// dryRunSignedTx simulates a signed tx and reports it to the dry run recorder instead of broadcasting it
+func (c *chainClient) dryRunSignedTx(txBytes []byte) (*txtypes.BroadcastTxResponse, error) {
+	ctx := c.getCookie(context.Background())
+	simRes, err := c.txClient.Simulate(ctx, &txtypes.SimulateRequest{TxBytes: txBytes})
+	c.opts.DryRun.RecordDryRun(txBytes, simRes, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to simulate the tx")
+	}
+
+	var gasWanted uint64
+	if decodedTx, err := DecodeTx(txBytes); err == nil {
+		gasWanted = decodedTx.GasLimit
+	}
+	return dryRunResponse(txBytes, gasWanted, simRes), nil
+}
+
+// dryRunResponse is the response of a simulated tx, as if it was included in a block: the msg responses are in the
+// data, so they can be decoded like the ones of a broadcasted tx. The height is 0
+func dryRunResponse(txBytes []byte, gasWanted uint64, simRes *txtypes.SimulateResponse) *txtypes.BroadcastTxResponse {
+	txResponse := &sdk.TxResponse{
+		TxHash:    fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash()),
+		GasWanted: int64(gasWanted),
+	}
+	if simRes.GasInfo != nil {
+		txResponse.GasUsed = int64(simRes.GasInfo.GasUsed)
+	}
+	if simRes.Result != nil {
+		txResponse.RawLog = simRes.Result.Log
+		txResponse.Events = simRes.Result.Events
+		msgData := sdk.TxMsgData{MsgResponses: simRes.Result.MsgResponses}
+		if data, err := msgData.Marshal(); err == nil {
+			txResponse.Data = fmt.Sprintf("%X", data)
+		}
+	}
+	return &txtypes.BroadcastTxResponse{TxResponse: txResponse}
+}
@@ -0,0 +1,173 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+type fakeAccountRetriever struct{}
+
+func (fakeAccountRetriever) GetAccount(client.Context, sdk.AccAddress) (client.Account, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fakeAccountRetriever) GetAccountWithHeight(client.Context, sdk.AccAddress) (client.Account, int64, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (fakeAccountRetriever) EnsureExists(client.Context, sdk.AccAddress) error { return nil }
+
+func (fakeAccountRetriever) GetAccountNumberSequence(client.Context, sdk.AccAddress) (uint64, uint64, error) {
+	return 3, 9, nil
+}
+
+// simulatingTxClient answers the simulations, and fails the test if a tx is broadcasted
+type simulatingTxClient struct {
+	txtypes.ServiceClient
+	t           *testing.T
+	simulations int
+	err         error
+}
+
+func (c *simulatingTxClient) Simulate(_ context.Context, _ *txtypes.SimulateRequest, _ ...grpc.CallOption) (*txtypes.SimulateResponse, error) {
+	c.simulations++
+	if c.err != nil {
+		return nil, c.err
+	}
+	msgResponse, err := codectypes.NewAnyWithValue(&banktypes.MsgSendResponse{})
+	assert.NoError(c.t, err)
+	return &txtypes.SimulateResponse{
+		GasInfo: &sdk.GasInfo{GasUsed: 100000},
+		Result: &sdk.Result{
+			Log:          "simulated",
+			Events:       []abci.Event{{Type: "transfer"}},
+			MsgResponses: []*codectypes.Any{msgResponse},
+		},
+	}, nil
+}
+
+func (c *simulatingTxClient) BroadcastTx(context.Context, *txtypes.BroadcastTxRequest, ...grpc.CallOption) (*txtypes.BroadcastTxResponse, error) {
+	c.t.Fatal("a tx was broadcasted in dry run mode")
+	return nil, nil
+}
+
+func newDryRunClient(t *testing.T, dryRunLog *DryRunLog) (*chainClient, *simulatingTxClient, sdk.AccAddress) {
+	key, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	kb, err := KeyringForPrivKey("dry-run", key)
+	assert.NoError(t, err)
+	address := sdk.AccAddress(key.PubKey().Address())
+	clientCtx, err := NewClientContext("injective-1", address.String(), kb)
+	assert.NoError(t, err)
+
+	opts := common.DefaultClientOptions()
+	assert.NoError(t, common.OptionDryRun(dryRunLog)(opts))
+	assert.NoError(t, common.OptionGasPrices("500000000inj")(opts))
+	txClient := &simulatingTxClient{t: t}
+	return &chainClient{
+		ctx:       clientCtx,
+		network:   common.LoadNetwork("local", ""),
+		opts:      opts,
+		logger:    log.WithField("module", "test"),
+		txFactory: NewTxFactory(clientCtx).WithAccountRetriever(fakeAccountRetriever{}).WithGasPrices(opts.GasPrices),
+		txClient:  txClient,
+		syncMux:   new(sync.Mutex),
+		canSign:   true,
+		accNum:    3,
+		accSeq:    9,
+	}, txClient, address
+}
+
+func TestDryRunSimulatesInsteadOfBroadcasting(t *testing.T) {
+	dryRunLog := NewDryRunLog(0)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dryRunLog.SetClock(clock.NewFake(now))
+	c, txClient, address := newDryRunClient(t, dryRunLog)
+	msg := banktypes.NewMsgSend(address, address, sdk.NewCoins(sdk.NewInt64Coin("inj", 1)))
+
+	res, err := c.SyncBroadcastMsgWithMemo("strategy test", msg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), res.TxResponse.Height)
+	assert.Equal(t, uint32(0), res.TxResponse.Code)
+	assert.Equal(t, int64(100000), res.TxResponse.GasUsed)
+	assert.Equal(t, int64(150000), res.TxResponse.GasWanted)
+	assert.Equal(t, "simulated", res.TxResponse.RawLog)
+	msgData, err := decodeTxMsgData(res.TxResponse.Data)
+	assert.NoError(t, err)
+	assert.Len(t, msgData.MsgResponses, 1)
+
+	// the sequence is not used by the dry run txs
+	_, err = c.AsyncBroadcastMsg(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), c.accSeq)
+	assert.Equal(t, 2, txClient.simulations)
+
+	txs := dryRunLog.Txs()
+	assert.Len(t, txs, 2)
+	dryRunTx := txs[0]
+	assert.Equal(t, res.TxResponse.TxHash, dryRunTx.TxHash)
+	assert.Equal(t, now, dryRunTx.Time)
+	assert.Equal(t, uint64(9), dryRunTx.Sequence)
+	assert.Equal(t, uint64(9), txs[1].Sequence)
+	assert.Equal(t, "strategy test", dryRunTx.Memo)
+	assert.Equal(t, []sdk.Msg{msg}, dryRunTx.Msgs)
+	assert.Equal(t, uint64(150000), dryRunTx.GasWanted)
+	assert.Equal(t, uint64(100000), dryRunTx.GasUsed)
+	assert.Equal(t, sdk.NewCoins(sdk.NewCoin("inj", sdk.NewInt(75000000000000))), dryRunTx.Fee)
+	assert.Len(t, dryRunTx.Responses, 1)
+	assert.IsType(t, &banktypes.MsgSendResponse{}, dryRunTx.Responses[0])
+	assert.Equal(t, []abci.Event{{Type: "transfer"}}, dryRunTx.Events)
+	assert.NoError(t, dryRunTx.Err)
+
+	signedTxBytes, err := c.signTx(c.ctx, c.txFactory.WithSequence(9).WithAccountNumber(3).WithGas(200000), []sdk.Msg{msg})
+	assert.NoError(t, err)
+	res, err = c.SyncBroadcastSignedTx(signedTxBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(200000), res.TxResponse.GasWanted)
+	assert.Len(t, dryRunLog.Txs(), 3)
+
+	dryRunLog.Reset()
+	assert.Empty(t, dryRunLog.Txs())
+}
+
+func TestDryRunRecordsRejectedTxs(t *testing.T) {
+	dryRunLog := NewDryRunLog(1)
+	c, txClient, address := newDryRunClient(t, dryRunLog)
+	msg := banktypes.NewMsgSend(address, address, sdk.NewCoins(sdk.NewInt64Coin("inj", 1)))
+
+	_, err := c.SyncBroadcastMsg(msg)
+	assert.NoError(t, err)
+
+	txClient.err = errors.New("insufficient funds")
+	_, err = c.SyncBroadcastMsg(msg)
+	assert.EqualError(t, err, "failed to CalculateGas: insufficient funds")
+
+	// the log keeps the last tx only
+	txs := dryRunLog.Txs()
+	assert.Len(t, txs, 1)
+	assert.EqualError(t, txs[0].Err, "insufficient funds")
+	assert.Equal(t, []sdk.Msg{msg}, txs[0].Msgs)
+	assert.Empty(t, txs[0].Responses)
+
+	dryRunLog.RecordDryRun([]byte("not a tx"), nil, nil)
+	assert.Error(t, dryRunLog.Txs()[0].Err)
+
+	assert.EqualError(t, common.OptionDryRun(nil)(common.DefaultClientOptions()), "the dry run recorder is nil")
+}
@@ -0,0 +1,436 @@
+package chain
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+const defaultFlattenSlippage = "0.05"
+
+type FlattenStep string
+
+const (
+	FlattenStepCancelOrders  FlattenStep = "cancel_orders"
+	FlattenStepClosePosition FlattenStep = "close_position"
+)
+
+// FlattenProgress is reported for every market processed by EmergencyFlatten. Err is set if the step failed
+type FlattenProgress struct {
+	Step     FlattenStep
+	MarketId string
+	TxHash   string
+	// CancelledOrders has the orders cancelled in the market by a cancel_orders step
+	CancelledOrders []CancelledOrder
+	Err             error
+}
+
+// FlattenReport summarizes the result of EmergencyFlatten. Failed steps don't stop the process, they are included
+// in Failures
+type FlattenReport struct {
+	CancelledMarkets []string
+	ClosedPositions  []string
+	Failures         []FlattenProgress
+}
+
+func (r *FlattenReport) HasFailures() bool {
+	return len(r.Failures) > 0
+}
+
+type KillSwitchConfig struct {
+	// Slippage is the fraction applied to the reference price to calculate the worst price of the market orders
+	// closing positions (5% by default)
+	Slippage sdk.Dec
+	// Progress is called after each step, if set
+	Progress func(progress FlattenProgress)
+}
+
+// KillSwitch cancels all the resting orders of a subaccount and closes its positions, for incident response
+type KillSwitch struct {
+	chainClient ChainClient
+	config      KillSwitchConfig
+}
+
+func NewKillSwitch(chainClient ChainClient, config KillSwitchConfig) *KillSwitch {
+	if config.Slippage.IsNil() {
+		config.Slippage = sdk.MustNewDecFromStr(defaultFlattenSlippage)
+	}
+
+	return &KillSwitch{
+		chainClient: chainClient,
+		config:      config,
+	}
+}
+
+// EmergencyFlatten cancels the resting and conditional orders of the subaccount in all the active spot, derivative
+// and binary options markets, and then closes every derivative and binary options position with a reduce-only market
+// order. Each position is closed in its own tx, so a failure in one market doesn't prevent closing the others. An
+// error is returned only if the orders and positions could not be queried
+func (k *KillSwitch) EmergencyFlatten(ctx context.Context, subaccountId string) (*FlattenReport, error) {
+	report := &FlattenReport{}
+
+	markets, err := k.marketsWithOrders(ctx, subaccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := k.chainClient.FetchChainSubaccountPositions(ctx, subaccountId)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the subaccount positions")
+	}
+
+	k.cancelOrders(report, subaccountId, markets)
+
+	for _, position := range positions.State {
+		if position.Position == nil || position.Position.Quantity.IsZero() {
+			continue
+		}
+
+		progress := FlattenProgress{Step: FlattenStepClosePosition, MarketId: position.MarketId}
+		if fullMarket, found := markets.derivativeMarketsById[position.MarketId]; found {
+			progress.TxHash, progress.Err = k.closePosition(subaccountId, fullMarket, position.Position)
+		} else if binaryOptionsMarket, found := markets.binaryOptionsMarketsById[position.MarketId]; found {
+			progress.TxHash, progress.Err = k.closeBinaryOptionsPosition(subaccountId, binaryOptionsMarket, position.Position)
+		} else {
+			progress.Err = errors.Errorf("market %s is not an active derivative or binary options market", position.MarketId)
+		}
+
+		if progress.Err == nil {
+			report.ClosedPositions = append(report.ClosedPositions, position.MarketId)
+		}
+		k.report(report, progress)
+	}
+
+	return report, nil
+}
+
+// CancelAllOrders cancels the resting and conditional orders of the subaccount in all the active markets, keeping the
+// positions open. It can be used as a lifecycle stop hook to leave no orders in the orderbooks when the process stops
+func (k *KillSwitch) CancelAllOrders(ctx context.Context, subaccountId string) (*FlattenReport, error) {
+	report := &FlattenReport{}
+
+	markets, err := k.marketsWithOrders(ctx, subaccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	k.cancelOrders(report, subaccountId, markets)
+	return report, nil
+}
+
+// flattenMarkets are the active markets of a subaccount flatten, with the markets that have orders to cancel
+type flattenMarkets struct {
+	spotMarketIds          []string
+	derivativeMarketIds    []string
+	binaryOptionsMarketIds []string
+	// conditionalOrders are the untriggered conditional orders, cancelled one by one because the cancel all of the
+	// derivative markets only removes the resting limit orders
+	conditionalOrders        []*exchangetypes.OrderData
+	derivativeMarketsById    map[string]*exchangetypes.FullDerivativeMarket
+	binaryOptionsMarketsById map[string]*exchangetypes.BinaryOptionsMarket
+}
+
+// marketIdsWithOrders returns the ids of the markets with orders to cancel, in the cancel msg order
+func (m *flattenMarkets) marketIdsWithOrders() []string {
+	marketIds := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(marketId string) {
+		if !seen[marketId] {
+			seen[marketId] = true
+			marketIds = append(marketIds, marketId)
+		}
+	}
+	for _, marketId := range m.spotMarketIds {
+		add(marketId)
+	}
+	for _, marketId := range m.derivativeMarketIds {
+		add(marketId)
+	}
+	for _, order := range m.conditionalOrders {
+		add(order.MarketId)
+	}
+	for _, marketId := range m.binaryOptionsMarketIds {
+		add(marketId)
+	}
+	return marketIds
+}
+
+// marketsWithOrders returns the active markets with resting or conditional orders of the subaccount, and all the
+// active derivative and binary options markets by id
+func (k *KillSwitch) marketsWithOrders(ctx context.Context, subaccountId string) (*flattenMarkets, error) {
+	spotMarkets, err := k.chainClient.FetchChainSpotMarkets(ctx, "Active", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the spot markets")
+	}
+	derivativeMarkets, err := k.chainClient.FetchChainDerivativeMarkets(ctx, "Active", nil, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the derivative markets")
+	}
+	binaryOptionsMarkets, err := k.chainClient.FetchChainBinaryOptionsMarkets(ctx, "Active")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the binary options markets")
+	}
+
+	markets := &flattenMarkets{
+		spotMarketIds:            make([]string, 0),
+		derivativeMarketIds:      make([]string, 0),
+		binaryOptionsMarketIds:   make([]string, 0),
+		derivativeMarketsById:    make(map[string]*exchangetypes.FullDerivativeMarket),
+		binaryOptionsMarketsById: make(map[string]*exchangetypes.BinaryOptionsMarket),
+	}
+
+	for _, market := range spotMarkets.Markets {
+		orders, err := k.chainClient.FetchChainTraderSpotOrders(ctx, market.MarketId, subaccountId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the spot orders in market %s", market.MarketId)
+		}
+		if len(orders.Orders) > 0 {
+			markets.spotMarketIds = append(markets.spotMarketIds, market.MarketId)
+		}
+	}
+
+	for _, fullMarket := range derivativeMarkets.Markets {
+		marketId := fullMarket.Market.MarketId
+		markets.derivativeMarketsById[marketId] = fullMarket
+		orders, err := k.chainClient.FetchChainTraderDerivativeOrders(ctx, marketId, subaccountId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the derivative orders in market %s", marketId)
+		}
+		if len(orders.Orders) > 0 {
+			markets.derivativeMarketIds = append(markets.derivativeMarketIds, marketId)
+		}
+
+		conditionalOrders, err := k.chainClient.FetchTraderDerivativeConditionalOrders(ctx, subaccountId, marketId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the conditional orders in market %s", marketId)
+		}
+		for _, order := range conditionalOrders.Orders {
+			markets.conditionalOrders = append(markets.conditionalOrders, &exchangetypes.OrderData{
+				MarketId:     marketId,
+				SubaccountId: subaccountId,
+				OrderHash:    order.OrderHash,
+				OrderMask:    int32(exchangetypes.OrderMask_CONDITIONAL),
+			})
+		}
+	}
+
+	for _, market := range binaryOptionsMarkets.Markets {
+		markets.binaryOptionsMarketsById[market.MarketId] = market
+		// binary options limit orders are stored with the derivative orders
+		orders, err := k.chainClient.FetchChainTraderDerivativeOrders(ctx, market.MarketId, subaccountId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the binary options orders in market %s", market.MarketId)
+		}
+		if len(orders.Orders) > 0 {
+			markets.binaryOptionsMarketIds = append(markets.binaryOptionsMarketIds, market.MarketId)
+		}
+	}
+
+	return markets, nil
+}
+
+// cancelOrders cancels all the orders with a single tx, and falls back to one tx per market if it fails
+func (k *KillSwitch) cancelOrders(report *FlattenReport, subaccountId string, markets *flattenMarkets) {
+	marketIds := markets.marketIdsWithOrders()
+	if len(marketIds) == 0 {
+		return
+	}
+
+	txHash, cancelResponse, err := k.broadcastCancel(k.cancelMsg(subaccountId, markets, ""))
+	if err == nil {
+		for _, marketId := range marketIds {
+			report.CancelledMarkets = append(report.CancelledMarkets, marketId)
+			k.report(report, FlattenProgress{
+				Step:            FlattenStepCancelOrders,
+				MarketId:        marketId,
+				TxHash:          txHash,
+				CancelledOrders: cancelledOrdersInMarket(cancelResponse, marketId),
+			})
+		}
+		return
+	}
+
+	for _, marketId := range marketIds {
+		progress := FlattenProgress{Step: FlattenStepCancelOrders, MarketId: marketId}
+		txHash, cancelResponse, err := k.broadcastCancel(k.cancelMsg(subaccountId, markets, marketId))
+		progress.TxHash, progress.Err = txHash, err
+		if progress.Err == nil {
+			report.CancelledMarkets = append(report.CancelledMarkets, marketId)
+			progress.CancelledOrders = cancelledOrdersInMarket(cancelResponse, marketId)
+		}
+		k.report(report, progress)
+	}
+}
+
+// cancelMsg builds the msg cancelling the orders in the market, or in all the markets if marketId is empty
+func (k *KillSwitch) cancelMsg(subaccountId string, markets *flattenMarkets, marketId string) *exchangetypes.MsgBatchUpdateOrders {
+	inMarket := func(marketIds []string) []string {
+		if marketId == "" {
+			return marketIds
+		}
+		for _, id := range marketIds {
+			if id == marketId {
+				return []string{marketId}
+			}
+		}
+		return nil
+	}
+
+	msg := &exchangetypes.MsgBatchUpdateOrders{
+		Sender:                            k.chainClient.FromAddress().String(),
+		SubaccountId:                      subaccountId,
+		SpotMarketIdsToCancelAll:          inMarket(markets.spotMarketIds),
+		DerivativeMarketIdsToCancelAll:    inMarket(markets.derivativeMarketIds),
+		BinaryOptionsMarketIdsToCancelAll: inMarket(markets.binaryOptionsMarketIds),
+	}
+	for _, order := range markets.conditionalOrders {
+		if marketId == "" || order.MarketId == marketId {
+			msg.DerivativeOrdersToCancel = append(msg.DerivativeOrdersToCancel, order)
+		}
+	}
+	return msg
+}
+
+func (k *KillSwitch) closePosition(subaccountId string, fullMarket *exchangetypes.FullDerivativeMarket, position *exchangetypes.Position) (string, error) {
+	worstPrice, err := closingOrderPrice(fullMarket, position.IsLong, k.config.Slippage)
+	if err != nil {
+		return "", err
+	}
+
+	return k.broadcast(&exchangetypes.MsgCreateDerivativeMarketOrder{
+		Sender: k.chainClient.FromAddress().String(),
+		Order: exchangetypes.DerivativeOrder{
+			MarketId: fullMarket.Market.MarketId,
+			OrderInfo: exchangetypes.OrderInfo{
+				SubaccountId: subaccountId,
+				FeeRecipient: k.chainClient.FromAddress().String(),
+				Price:        worstPrice,
+				Quantity:     position.Quantity,
+			},
+			OrderType: closingOrderType(position.IsLong),
+			Margin:    sdk.ZeroDec(),
+		},
+	})
+}
+
+func (k *KillSwitch) closeBinaryOptionsPosition(subaccountId string, market *exchangetypes.BinaryOptionsMarket, position *exchangetypes.Position) (string, error) {
+	worstPrice, err := binaryOptionsClosingOrderPrice(market, position.IsLong)
+	if err != nil {
+		return "", err
+	}
+
+	return k.broadcast(exchangetypes.NewMsgCreateBinaryOptionsMarketOrder(
+		k.chainClient.FromAddress(),
+		market,
+		subaccountId,
+		k.chainClient.FromAddress().String(),
+		worstPrice,
+		position.Quantity,
+		closingOrderType(position.IsLong),
+		true,
+	))
+}
+
+func closingOrderType(isLong bool) exchangetypes.OrderType {
+	if isLong {
+		return exchangetypes.OrderType_SELL
+	}
+	return exchangetypes.OrderType_BUY
+}
+
+// closingOrderPrice is the worst price of the market order closing a position: the top of the book (or the mark
+// price if there is no top of the book) moved by the slippage against the position, rounded to the price tick size
+func closingOrderPrice(fullMarket *exchangetypes.FullDerivativeMarket, isLong bool, slippage sdk.Dec) (sdk.Dec, error) {
+	market := fullMarket.Market
+	referencePrice := fullMarket.MarkPrice
+
+	var worstPrice sdk.Dec
+	if isLong {
+		if tob := fullMarket.MidPriceAndTob; tob != nil && tob.BestBuyPrice != nil && !tob.BestBuyPrice.IsNil() {
+			referencePrice = *tob.BestBuyPrice
+		}
+		if !referencePrice.IsNil() {
+			worstPrice = floorToTickSize(referencePrice.Mul(sdk.OneDec().Sub(slippage)), market.MinPriceTickSize)
+		}
+	} else {
+		if tob := fullMarket.MidPriceAndTob; tob != nil && tob.BestSellPrice != nil && !tob.BestSellPrice.IsNil() {
+			referencePrice = *tob.BestSellPrice
+		}
+		if !referencePrice.IsNil() {
+			worstPrice = ceilToTickSize(referencePrice.Mul(sdk.OneDec().Add(slippage)), market.MinPriceTickSize)
+		}
+	}
+	if worstPrice.IsNil() || !worstPrice.IsPositive() {
+		return sdk.Dec{}, errors.Errorf("there is no valid price to close the position in market %s", market.MarketId)
+	}
+	return worstPrice, nil
+}
+
+// binaryOptionsClosingOrderPrice is the most aggressive valid price (one tick from the 0 and 1 bounds), because binary
+// options markets have no mark price to apply the slippage to
+func binaryOptionsClosingOrderPrice(market *exchangetypes.BinaryOptionsMarket, isLong bool) (sdk.Dec, error) {
+	worstPrice := market.MinPriceTickSize
+	if !isLong {
+		worstPrice = exchangetypes.GetScaledPrice(sdk.OneDec(), market.OracleScaleFactor).Sub(market.MinPriceTickSize)
+	}
+	if !worstPrice.IsPositive() {
+		return sdk.Dec{}, errors.Errorf("there is no valid price to close the position in market %s", market.MarketId)
+	}
+	return worstPrice, nil
+}
+
+func (k *KillSwitch) broadcast(msg sdk.Msg) (string, error) {
+	res, err := k.chainClient.SyncBroadcastMsg(msg)
+	if err != nil {
+		return "", err
+	}
+	if res.TxResponse == nil {
+		return "", nil
+	}
+	return res.TxResponse.TxHash, NewTxError(res.TxResponse)
+}
+
+// broadcastCancel broadcasts the cancel msg and returns the cancelled orders decoded from the tx response
+func (k *KillSwitch) broadcastCancel(msg sdk.Msg) (string, *CancelResponse, error) {
+	res, cancelResponse, err := k.chainClient.SyncBroadcastCancelMsg(msg)
+	if err != nil {
+		return "", nil, err
+	}
+	if res.TxResponse == nil {
+		return "", nil, nil
+	}
+	return res.TxResponse.TxHash, cancelResponse, NewTxError(res.TxResponse)
+}
+
+func cancelledOrdersInMarket(cancelResponse *CancelResponse, marketId string) []CancelledOrder {
+	if cancelResponse == nil {
+		return nil
+	}
+	var orders []CancelledOrder
+	for _, order := range cancelResponse.CancelledOrders {
+		if order.MarketId == marketId {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
+func (k *KillSwitch) report(report *FlattenReport, progress FlattenProgress) {
+	if progress.Err != nil {
+		report.Failures = append(report.Failures, progress)
+	}
+	if k.config.Progress != nil {
+		k.config.Progress(progress)
+	}
+}
+
+func floorToTickSize(value sdk.Dec, tickSize sdk.Dec) sdk.Dec {
+	return value.Quo(tickSize).TruncateDec().Mul(tickSize)
+}
+
+func ceilToTickSize(value sdk.Dec, tickSize sdk.Dec) sdk.Dec {
+	return value.Quo(tickSize).Ceil().Mul(tickSize)
+}
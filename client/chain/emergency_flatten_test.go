@@ -0,0 +1,197 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+type flattenTestChainClient struct {
+	MockChainClient
+	derivativeMarkets    []*exchangetypes.FullDerivativeMarket
+	binaryOptionsMarkets []*exchangetypes.BinaryOptionsMarket
+	ordersByMarket       map[string]int
+	conditionalByMarket  map[string][]string
+	positions            []exchangetypes.DerivativePosition
+	failingMarkets       map[string]bool
+	broadcastedMsgs      []sdk.Msg
+}
+
+func (c *flattenTestChainClient) FetchChainSpotMarkets(ctx context.Context, status string, marketIds []string) (*exchangetypes.QuerySpotMarketsResponse, error) {
+	return &exchangetypes.QuerySpotMarketsResponse{Markets: []*exchangetypes.SpotMarket{{MarketId: riskSpotMarketId}}}, nil
+}
+
+func (c *flattenTestChainClient) FetchChainDerivativeMarkets(ctx context.Context, status string, marketIds []string, withMidPriceAndTob bool) (*exchangetypes.QueryDerivativeMarketsResponse, error) {
+	return &exchangetypes.QueryDerivativeMarketsResponse{Markets: c.derivativeMarkets}, nil
+}
+
+func (c *flattenTestChainClient) FetchChainBinaryOptionsMarkets(ctx context.Context, status string) (*exchangetypes.QueryBinaryMarketsResponse, error) {
+	return &exchangetypes.QueryBinaryMarketsResponse{Markets: c.binaryOptionsMarkets}, nil
+}
+
+func (c *flattenTestChainClient) FetchTraderDerivativeConditionalOrders(ctx context.Context, subaccountId string, marketId string) (*exchangetypes.QueryTraderDerivativeConditionalOrdersResponse, error) {
+	orders := make([]*exchangetypes.TrimmedDerivativeConditionalOrder, 0)
+	for _, orderHash := range c.conditionalByMarket[marketId] {
+		orders = append(orders, &exchangetypes.TrimmedDerivativeConditionalOrder{OrderHash: orderHash})
+	}
+	return &exchangetypes.QueryTraderDerivativeConditionalOrdersResponse{Orders: orders}, nil
+}
+
+func (c *flattenTestChainClient) FetchChainTraderSpotOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderSpotOrdersResponse, error) {
+	orders := make([]*exchangetypes.TrimmedSpotLimitOrder, c.ordersByMarket[marketId])
+	return &exchangetypes.QueryTraderSpotOrdersResponse{Orders: orders}, nil
+}
+
+func (c *flattenTestChainClient) FetchChainTraderDerivativeOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderDerivativeOrdersResponse, error) {
+	orders := make([]*exchangetypes.TrimmedDerivativeLimitOrder, c.ordersByMarket[marketId])
+	return &exchangetypes.QueryTraderDerivativeOrdersResponse{Orders: orders}, nil
+}
+
+func (c *flattenTestChainClient) FetchChainSubaccountPositions(ctx context.Context, subaccountId string) (*exchangetypes.QuerySubaccountPositionsResponse, error) {
+	return &exchangetypes.QuerySubaccountPositionsResponse{State: c.positions}, nil
+}
+
+func (c *flattenTestChainClient) SyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	c.broadcastedMsgs = append(c.broadcastedMsgs, msgs...)
+	response := &sdk.TxResponse{TxHash: "ABCD"}
+	if marketOrder, isMarketOrder := msgs[0].(*exchangetypes.MsgCreateDerivativeMarketOrder); isMarketOrder && c.failingMarkets[marketOrder.Order.MarketId] {
+		response.Code = exchangetypes.ErrNoLiquidity.ABCICode()
+		response.Codespace = exchangetypes.ModuleName
+	}
+	return &txtypes.BroadcastTxResponse{TxResponse: response}, nil
+}
+
+func (c *flattenTestChainClient) SyncBroadcastCancelMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error) {
+	res, err := c.SyncBroadcastMsg(msgs...)
+	return res, &CancelResponse{TxHash: res.TxResponse.TxHash}, err
+}
+
+func flattenTestDerivativeMarket(marketId string) *exchangetypes.FullDerivativeMarket {
+	bestBuyPrice := sdk.MustNewDecFromStr("99.5")
+	return &exchangetypes.FullDerivativeMarket{
+		Market: &exchangetypes.DerivativeMarket{
+			MarketId:         marketId,
+			MinPriceTickSize: sdk.MustNewDecFromStr("0.1"),
+		},
+		MarkPrice:      sdk.MustNewDecFromStr("100"),
+		MidPriceAndTob: &exchangetypes.MidPriceAndTOB{BestBuyPrice: &bestBuyPrice},
+	}
+}
+
+func TestEmergencyFlattenCancelsOrdersAndClosesPositions(t *testing.T) {
+	secondMarketId := "0x4ca0f92fc28be0c9761326016b5a1a2177dd6375558365116b5bdda9abc229ce"
+	chainClient := &flattenTestChainClient{
+		derivativeMarkets: []*exchangetypes.FullDerivativeMarket{
+			flattenTestDerivativeMarket(riskDerivativeMarketId),
+			flattenTestDerivativeMarket(secondMarketId),
+		},
+		ordersByMarket: map[string]int{riskSpotMarketId: 2, riskDerivativeMarketId: 1},
+		positions: []exchangetypes.DerivativePosition{
+			{
+				SubaccountId: riskSubaccountId,
+				MarketId:     riskDerivativeMarketId,
+				Position:     &exchangetypes.Position{IsLong: true, Quantity: sdk.MustNewDecFromStr("2")},
+			},
+			{
+				SubaccountId: riskSubaccountId,
+				MarketId:     secondMarketId,
+				Position:     &exchangetypes.Position{IsLong: false, Quantity: sdk.MustNewDecFromStr("1")},
+			},
+		},
+		failingMarkets: map[string]bool{secondMarketId: true},
+	}
+
+	var progress []FlattenProgress
+	killSwitch := NewKillSwitch(chainClient, KillSwitchConfig{
+		Progress: func(p FlattenProgress) { progress = append(progress, p) },
+	})
+
+	report, err := killSwitch.EmergencyFlatten(context.Background(), riskSubaccountId)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{riskSpotMarketId, riskDerivativeMarketId}, report.CancelledMarkets)
+	assert.Equal(t, []string{riskDerivativeMarketId}, report.ClosedPositions)
+	assert.True(t, report.HasFailures())
+	assert.Len(t, report.Failures, 1)
+	assert.Equal(t, secondMarketId, report.Failures[0].MarketId)
+	assert.Len(t, progress, 4)
+
+	assert.Len(t, chainClient.broadcastedMsgs, 3)
+	cancelMsg := chainClient.broadcastedMsgs[0].(*exchangetypes.MsgBatchUpdateOrders)
+	assert.Equal(t, []string{riskSpotMarketId}, cancelMsg.SpotMarketIdsToCancelAll)
+	assert.Equal(t, []string{riskDerivativeMarketId}, cancelMsg.DerivativeMarketIdsToCancelAll)
+
+	closeLong := chainClient.broadcastedMsgs[1].(*exchangetypes.MsgCreateDerivativeMarketOrder)
+	assert.Equal(t, exchangetypes.OrderType_SELL, closeLong.Order.OrderType)
+	assert.True(t, closeLong.Order.IsReduceOnly())
+	assert.Equal(t, "2.000000000000000000", closeLong.Order.OrderInfo.Quantity.String())
+	// 99.5 * 0.95 = 94.525 rounded down to the tick size
+	assert.Equal(t, "94.500000000000000000", closeLong.Order.OrderInfo.Price.String())
+
+	closeShort := chainClient.broadcastedMsgs[2].(*exchangetypes.MsgCreateDerivativeMarketOrder)
+	assert.Equal(t, exchangetypes.OrderType_BUY, closeShort.Order.OrderType)
+	// there is no best sell price, so the mark price is used: 100 * 1.05
+	assert.Equal(t, "105.000000000000000000", closeShort.Order.OrderInfo.Price.String())
+}
+
+func TestCancelAllOrdersKeepsPositions(t *testing.T) {
+	chainClient := &flattenTestChainClient{
+		derivativeMarkets: []*exchangetypes.FullDerivativeMarket{flattenTestDerivativeMarket(riskDerivativeMarketId)},
+		ordersByMarket:    map[string]int{riskDerivativeMarketId: 1},
+		positions: []exchangetypes.DerivativePosition{{
+			SubaccountId: riskSubaccountId,
+			MarketId:     riskDerivativeMarketId,
+			Position:     &exchangetypes.Position{IsLong: true, Quantity: sdk.MustNewDecFromStr("2")},
+		}},
+	}
+
+	report, err := NewKillSwitch(chainClient, KillSwitchConfig{}).CancelAllOrders(context.Background(), riskSubaccountId)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{riskDerivativeMarketId}, report.CancelledMarkets)
+	assert.Empty(t, report.ClosedPositions)
+	assert.Len(t, chainClient.broadcastedMsgs, 1)
+}
+
+func TestEmergencyFlattenCancelsConditionalAndBinaryOptionsOrders(t *testing.T) {
+	binaryOptionsMarketId := "0x230dcce315364ff6360097838701b14713e2f4007d704df20ed3d81d09eec957"
+	chainClient := &flattenTestChainClient{
+		derivativeMarkets: []*exchangetypes.FullDerivativeMarket{flattenTestDerivativeMarket(riskDerivativeMarketId)},
+		binaryOptionsMarkets: []*exchangetypes.BinaryOptionsMarket{{
+			MarketId:          binaryOptionsMarketId,
+			OracleScaleFactor: 6,
+			MinPriceTickSize:  sdk.MustNewDecFromStr("10000"),
+		}},
+		ordersByMarket:      map[string]int{binaryOptionsMarketId: 1},
+		conditionalByMarket: map[string][]string{riskDerivativeMarketId: {"0x01", "0x02"}},
+		positions: []exchangetypes.DerivativePosition{{
+			SubaccountId: riskSubaccountId,
+			MarketId:     binaryOptionsMarketId,
+			Position:     &exchangetypes.Position{IsLong: false, Quantity: sdk.MustNewDecFromStr("3")},
+		}},
+	}
+
+	report, err := NewKillSwitch(chainClient, KillSwitchConfig{}).EmergencyFlatten(context.Background(), riskSubaccountId)
+	assert.NoError(t, err)
+	assert.False(t, report.HasFailures())
+	assert.Equal(t, []string{riskDerivativeMarketId, binaryOptionsMarketId}, report.CancelledMarkets)
+	assert.Equal(t, []string{binaryOptionsMarketId}, report.ClosedPositions)
+
+	assert.Len(t, chainClient.broadcastedMsgs, 2)
+	cancelMsg := chainClient.broadcastedMsgs[0].(*exchangetypes.MsgBatchUpdateOrders)
+	assert.Empty(t, cancelMsg.DerivativeMarketIdsToCancelAll)
+	assert.Equal(t, []string{binaryOptionsMarketId}, cancelMsg.BinaryOptionsMarketIdsToCancelAll)
+	assert.Len(t, cancelMsg.DerivativeOrdersToCancel, 2)
+	assert.Equal(t, "0x02", cancelMsg.DerivativeOrdersToCancel[1].OrderHash)
+	assert.Equal(t, int32(exchangetypes.OrderMask_CONDITIONAL), cancelMsg.DerivativeOrdersToCancel[1].OrderMask)
+
+	closeShort := chainClient.broadcastedMsgs[1].(*exchangetypes.MsgCreateBinaryOptionsMarketOrder)
+	assert.Equal(t, exchangetypes.OrderType_BUY, closeShort.Order.OrderType)
+	assert.True(t, closeShort.Order.IsReduceOnly())
+	// one tick below the scaled price of 1
+	assert.Equal(t, "990000.000000000000000000", closeShort.Order.OrderInfo.Price.String())
+}
@@ -0,0 +1,72 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+// the exchange params only change through governance proposals
+const defaultExchangeParamsCacheTTL = 10 * time.Minute
+
+type exchangeParamsFetcher func(ctx context.Context) (*exchangetypes.Params, error)
+
+// exchangeParamsCache keeps the last exchange params snapshot, fetching them again once the TTL expires
+type exchangeParamsCache struct {
+	mux       sync.Mutex
+	ttl       time.Duration
+	params    *exchangetypes.Params
+	fetchedAt time.Time
+	clock     clock.Clock
+}
+
+func newExchangeParamsCache(ttl time.Duration, c clock.Clock) *exchangeParamsCache {
+	return &exchangeParamsCache{ttl: ttl, clock: c}
+}
+
+func (c *exchangeParamsCache) get(ctx context.Context, fetch exchangeParamsFetcher) (exchangetypes.Params, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.params != nil && c.clock.Since(c.fetchedAt) < c.ttl {
+		return *c.params, nil
+	}
+
+	params, err := fetch(ctx)
+	if err != nil {
+		return exchangetypes.Params{}, err
+	}
+	c.params = params
+	c.fetchedAt = c.clock.Now()
+
+	return *params, nil
+}
+
+func decimalFromDec(value sdk.Dec) decimal.Decimal {
+	if value.IsNil() {
+		return decimal.Zero
+	}
+	return decimal.RequireFromString(value.String())
+}
+
+// DefaultSpotFeeRates returns the fee rates used by the exchange params for new spot markets
+func DefaultSpotFeeRates(params exchangetypes.Params) FeeRates {
+	return FeeRates{
+		MakerFeeRate: decimalFromDec(params.DefaultSpotMakerFeeRate),
+		TakerFeeRate: decimalFromDec(params.DefaultSpotTakerFeeRate),
+	}
+}
+
+// DefaultDerivativeFeeRates returns the fee rates used by the exchange params for new derivative markets
+func DefaultDerivativeFeeRates(params exchangetypes.Params) FeeRates {
+	return FeeRates{
+		MakerFeeRate: decimalFromDec(params.DefaultDerivativeMakerFeeRate),
+		TakerFeeRate: decimalFromDec(params.DefaultDerivativeTakerFeeRate),
+	}
+}
@@ -0,0 +1,46 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+func TestExchangeParamsCacheRefreshesAfterTTL(t *testing.T) {
+	fetches := 0
+	fetch := func(ctx context.Context) (*exchangetypes.Params, error) {
+		fetches++
+		return &exchangetypes.Params{
+			DefaultSpotMakerFeeRate: sdk.MustNewDecFromStr("-0.0001"),
+			DefaultSpotTakerFeeRate: sdk.MustNewDecFromStr("0.001"),
+		}, nil
+	}
+
+	fakeClock := clock.NewFake(time.Unix(1700000000, 0))
+	cache := newExchangeParamsCache(time.Hour, fakeClock)
+	_, err := cache.get(context.Background(), fetch)
+	assert.NoError(t, err)
+	params, err := cache.get(context.Background(), fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+
+	rates := DefaultSpotFeeRates(params)
+	assert.Equal(t, "-0.0001", rates.MakerFeeRate.String())
+	assert.Equal(t, "0.001", rates.TakerFeeRate.String())
+	assert.True(t, DefaultDerivativeFeeRates(params).TakerFeeRate.IsZero())
+
+	fakeClock.Advance(time.Hour)
+	_, _ = cache.get(context.Background(), fetch)
+	assert.Equal(t, 2, fetches)
+
+	cache = newExchangeParamsCache(0, fakeClock)
+	_, _ = cache.get(context.Background(), fetch)
+	_, _ = cache.get(context.Background(), fetch)
+	assert.Equal(t, 4, fetches)
+}
@@ -0,0 +1,327 @@
+package chain
+
+import (
+	"context"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// ExchangeStateQuerier has the queries used to read the exchange module state. ChainClient implements it
+type ExchangeStateQuerier interface {
+	FetchChainSpotMarkets(ctx context.Context, status string, marketIds []string) (*exchangetypes.QuerySpotMarketsResponse, error)
+	FetchChainDerivativeMarkets(ctx context.Context, status string, marketIds []string, withMidPriceAndTob bool) (*exchangetypes.QueryDerivativeMarketsResponse, error)
+	FetchChainTraderSpotOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderSpotOrdersResponse, error)
+	FetchChainTraderDerivativeOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderDerivativeOrdersResponse, error)
+}
+
+// ExchangeStateScope selects the state read by FetchExchangeState. The chain only lists the orders of a subaccount
+// in a market, so the orders are read for every pair of selected market and subaccount
+type ExchangeStateScope struct {
+	// MarketIds are the markets read. Empty reads all the spot and derivative markets
+	MarketIds []string
+	// SubaccountIds are the subaccounts whose resting orders are read. Empty reads no orders
+	SubaccountIds []string
+}
+
+// MarketState is the parameters of a market. Params has the status, fee rates, tick sizes and (for derivative
+// markets) margin ratios, formatted as strings so they can be compared
+type MarketState struct {
+	MarketId     string
+	Ticker       string
+	IsDerivative bool
+	Params       map[string]string
+}
+
+// RestingOrderState is a limit order resting in the orderbook
+type RestingOrderState struct {
+	OrderHash    string
+	MarketId     string
+	SubaccountId string
+	IsBuy        bool
+	IsDerivative bool
+	Price        sdk.Dec
+	Quantity     sdk.Dec
+	Fillable     sdk.Dec
+	// Margin is only set for derivative orders
+	Margin sdk.Dec
+}
+
+// ExchangeState is the exchange module state at a block height
+type ExchangeState struct {
+	// Height is the height requested, ResolvedHeight the one reported by the node (0 if it did not report it)
+	Height         int64
+	ResolvedHeight int64
+	Markets        map[string]MarketState
+	Orders         map[string]RestingOrderState
+}
+
+// FetchExchangeState reads the markets and the resting orders of the scope at the block height (0 for the latest
+// height). The node must keep the state of the height, i.e. be an archive node for old heights
+func FetchExchangeState(ctx context.Context, querier ExchangeStateQuerier, height int64, scope ExchangeStateScope) (*ExchangeState, error) {
+	state, resolvedHeight, err := QueryAtHeight(ctx, height, func(ctx context.Context) (*ExchangeState, error) {
+		return fetchExchangeState(ctx, querier, scope)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch the exchange state at height %d", height)
+	}
+	state.Height = height
+	state.ResolvedHeight = resolvedHeight
+	return state, nil
+}
+
+func fetchExchangeState(ctx context.Context, querier ExchangeStateQuerier, scope ExchangeStateScope) (*ExchangeState, error) {
+	state := &ExchangeState{
+		Markets: make(map[string]MarketState),
+		Orders:  make(map[string]RestingOrderState),
+	}
+
+	spotMarkets, err := querier.FetchChainSpotMarkets(ctx, "", scope.MarketIds)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the spot markets")
+	}
+	for _, market := range spotMarkets.Markets {
+		state.Markets[market.MarketId] = spotMarketState(market)
+	}
+	derivativeMarkets, err := querier.FetchChainDerivativeMarkets(ctx, "", scope.MarketIds, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the derivative markets")
+	}
+	for _, fullMarket := range derivativeMarkets.Markets {
+		if fullMarket.Market != nil {
+			state.Markets[fullMarket.Market.MarketId] = derivativeMarketState(fullMarket.Market)
+		}
+	}
+
+	for _, market := range state.Markets {
+		for _, subaccountId := range scope.SubaccountIds {
+			if err := fetchRestingOrders(ctx, querier, state, market, subaccountId); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return state, nil
+}
+
+func fetchRestingOrders(ctx context.Context, querier ExchangeStateQuerier, state *ExchangeState, market MarketState, subaccountId string) error {
+	if !market.IsDerivative {
+		res, err := querier.FetchChainTraderSpotOrders(ctx, market.MarketId, subaccountId)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch the spot orders of subaccount %s in market %s", subaccountId, market.MarketId)
+		}
+		for _, order := range res.Orders {
+			state.Orders[order.OrderHash] = RestingOrderState{
+				OrderHash:    order.OrderHash,
+				MarketId:     market.MarketId,
+				SubaccountId: subaccountId,
+				IsBuy:        order.IsBuy,
+				Price:        order.Price,
+				Quantity:     order.Quantity,
+				Fillable:     order.Fillable,
+			}
+		}
+		return nil
+	}
+
+	res, err := querier.FetchChainTraderDerivativeOrders(ctx, market.MarketId, subaccountId)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch the derivative orders of subaccount %s in market %s", subaccountId, market.MarketId)
+	}
+	for _, order := range res.Orders {
+		state.Orders[order.OrderHash] = RestingOrderState{
+			OrderHash:    order.OrderHash,
+			MarketId:     market.MarketId,
+			SubaccountId: subaccountId,
+			IsBuy:        order.IsBuy,
+			IsDerivative: true,
+			Price:        order.Price,
+			Quantity:     order.Quantity,
+			Fillable:     order.Fillable,
+			Margin:       order.Margin,
+		}
+	}
+	return nil
+}
+
+func spotMarketState(market *exchangetypes.SpotMarket) MarketState {
+	return MarketState{
+		MarketId: market.MarketId,
+		Ticker:   market.Ticker,
+		Params: map[string]string{
+			"status":                 market.Status.String(),
+			"maker_fee_rate":         market.MakerFeeRate.String(),
+			"taker_fee_rate":         market.TakerFeeRate.String(),
+			"relayer_fee_share_rate": market.RelayerFeeShareRate.String(),
+			"min_price_tick_size":    market.MinPriceTickSize.String(),
+			"min_quantity_tick_size": market.MinQuantityTickSize.String(),
+		},
+	}
+}
+
+func derivativeMarketState(market *exchangetypes.DerivativeMarket) MarketState {
+	return MarketState{
+		MarketId:     market.MarketId,
+		Ticker:       market.Ticker,
+		IsDerivative: true,
+		Params: map[string]string{
+			"status":                   market.Status.String(),
+			"maker_fee_rate":           market.MakerFeeRate.String(),
+			"taker_fee_rate":           market.TakerFeeRate.String(),
+			"relayer_fee_share_rate":   market.RelayerFeeShareRate.String(),
+			"min_price_tick_size":      market.MinPriceTickSize.String(),
+			"min_quantity_tick_size":   market.MinQuantityTickSize.String(),
+			"initial_margin_ratio":     market.InitialMarginRatio.String(),
+			"maintenance_margin_ratio": market.MaintenanceMarginRatio.String(),
+		},
+	}
+}
+
+// OrderFill is an order resting at both heights whose fillable quantity decreased
+type OrderFill struct {
+	Order RestingOrderState
+	// FilledQuantity is the fillable quantity at the first height minus the one at the second height
+	FilledQuantity sdk.Dec
+}
+
+// MarketParamChange is a market parameter with different values at the two heights
+type MarketParamChange struct {
+	MarketId string
+	Ticker   string
+	Param    string
+	From     string
+	To       string
+}
+
+// ExchangeStateDiff is the difference between the exchange state at two heights. All the lists are sorted by market
+// and then by order hash or param name
+type ExchangeStateDiff struct {
+	FromHeight int64
+	ToHeight   int64
+	// NewOrders are resting at the second height only
+	NewOrders []RestingOrderState
+	// ClosedOrders are resting at the first height only: they were either cancelled or fully filled. The state can't
+	// tell them apart, use SplitClosedOrders with the hashes of the orders filled between the heights (e.g. from the
+	// indexer fills) for that
+	ClosedOrders []RestingOrderState
+	// PartiallyFilledOrders are resting at both heights with a lower fillable quantity at the second height
+	PartiallyFilledOrders []OrderFill
+	NewMarkets            []MarketState
+	RemovedMarkets        []MarketState
+	MarketParamChanges    []MarketParamChange
+}
+
+// DiffExchangeStates compares the exchange state at two heights, from being the state at the lower height
+func DiffExchangeStates(from *ExchangeState, to *ExchangeState) *ExchangeStateDiff {
+	diff := &ExchangeStateDiff{
+		FromHeight:            from.Height,
+		ToHeight:              to.Height,
+		NewOrders:             make([]RestingOrderState, 0),
+		ClosedOrders:          make([]RestingOrderState, 0),
+		PartiallyFilledOrders: make([]OrderFill, 0),
+		NewMarkets:            make([]MarketState, 0),
+		RemovedMarkets:        make([]MarketState, 0),
+		MarketParamChanges:    make([]MarketParamChange, 0),
+	}
+
+	for orderHash, order := range to.Orders {
+		previous, found := from.Orders[orderHash]
+		switch {
+		case !found:
+			diff.NewOrders = append(diff.NewOrders, order)
+		case order.Fillable.LT(previous.Fillable):
+			diff.PartiallyFilledOrders = append(diff.PartiallyFilledOrders, OrderFill{Order: order, FilledQuantity: previous.Fillable.Sub(order.Fillable)})
+		}
+	}
+	for orderHash, order := range from.Orders {
+		if _, found := to.Orders[orderHash]; !found {
+			diff.ClosedOrders = append(diff.ClosedOrders, order)
+		}
+	}
+
+	for marketId, market := range to.Markets {
+		previous, found := from.Markets[marketId]
+		if !found {
+			diff.NewMarkets = append(diff.NewMarkets, market)
+			continue
+		}
+		for param, value := range market.Params {
+			if previousValue := previous.Params[param]; previousValue != value {
+				diff.MarketParamChanges = append(diff.MarketParamChanges, MarketParamChange{
+					MarketId: marketId,
+					Ticker:   market.Ticker,
+					Param:    param,
+					From:     previousValue,
+					To:       value,
+				})
+			}
+		}
+	}
+	for marketId, market := range from.Markets {
+		if _, found := to.Markets[marketId]; !found {
+			diff.RemovedMarkets = append(diff.RemovedMarkets, market)
+		}
+	}
+
+	sortRestingOrders(diff.NewOrders)
+	sortRestingOrders(diff.ClosedOrders)
+	sort.Slice(diff.PartiallyFilledOrders, func(i, j int) bool {
+		return restingOrderLess(diff.PartiallyFilledOrders[i].Order, diff.PartiallyFilledOrders[j].Order)
+	})
+	sortMarketStates(diff.NewMarkets)
+	sortMarketStates(diff.RemovedMarkets)
+	sort.Slice(diff.MarketParamChanges, func(i, j int) bool {
+		if diff.MarketParamChanges[i].MarketId != diff.MarketParamChanges[j].MarketId {
+			return diff.MarketParamChanges[i].MarketId < diff.MarketParamChanges[j].MarketId
+		}
+		return diff.MarketParamChanges[i].Param < diff.MarketParamChanges[j].Param
+	})
+	return diff
+}
+
+// SplitClosedOrders separates the closed orders that were filled (their hash is in filledOrderHashes) from the
+// cancelled ones
+func (d *ExchangeStateDiff) SplitClosedOrders(filledOrderHashes []string) (filled []RestingOrderState, cancelled []RestingOrderState) {
+	filledHashes := make(map[string]struct{}, len(filledOrderHashes))
+	for _, orderHash := range filledOrderHashes {
+		filledHashes[orderHash] = struct{}{}
+	}
+
+	filled = make([]RestingOrderState, 0)
+	cancelled = make([]RestingOrderState, 0)
+	for _, order := range d.ClosedOrders {
+		if _, found := filledHashes[order.OrderHash]; found {
+			filled = append(filled, order)
+		} else {
+			cancelled = append(cancelled, order)
+		}
+	}
+	return filled, cancelled
+}
+
+// IsEmpty returns true if the state did not change between the heights
+func (d *ExchangeStateDiff) IsEmpty() bool {
+	return len(d.NewOrders) == 0 && len(d.ClosedOrders) == 0 && len(d.PartiallyFilledOrders) == 0 &&
+		len(d.NewMarkets) == 0 && len(d.RemovedMarkets) == 0 && len(d.MarketParamChanges) == 0
+}
+
+func restingOrderLess(a RestingOrderState, b RestingOrderState) bool {
+	if a.MarketId != b.MarketId {
+		return a.MarketId < b.MarketId
+	}
+	return a.OrderHash < b.OrderHash
+}
+
+func sortRestingOrders(orders []RestingOrderState) {
+	sort.Slice(orders, func(i, j int) bool {
+		return restingOrderLess(orders[i], orders[j])
+	})
+}
+
+func sortMarketStates(markets []MarketState) {
+	sort.Slice(markets, func(i, j int) bool {
+		return markets[i].MarketId < markets[j].MarketId
+	})
+}
@@ -0,0 +1,132 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// stateDiffTestQuerier serves a different state depending on the height the queries are pinned to
+type stateDiffTestQuerier struct {
+	failAt int64
+}
+
+func (q *stateDiffTestQuerier) height(ctx context.Context) int64 {
+	return ctx.Value(heightQueryKey{}).(*heightQuery).height
+}
+
+func (q *stateDiffTestQuerier) FetchChainSpotMarkets(ctx context.Context, _ string, _ []string) (*exchangetypes.QuerySpotMarketsResponse, error) {
+	if q.height(ctx) == q.failAt {
+		return nil, errors.New("height is not available")
+	}
+	makerFeeRate := "0.001"
+	if q.height(ctx) > 100 {
+		makerFeeRate = "-0.0001"
+	}
+	return &exchangetypes.QuerySpotMarketsResponse{Markets: []*exchangetypes.SpotMarket{{
+		MarketId:            riskSpotMarketId,
+		Ticker:              "INJ/USDT",
+		Status:              exchangetypes.MarketStatus_Active,
+		MakerFeeRate:        sdk.MustNewDecFromStr(makerFeeRate),
+		TakerFeeRate:        sdk.MustNewDecFromStr("0.002"),
+		RelayerFeeShareRate: sdk.MustNewDecFromStr("0.4"),
+		MinPriceTickSize:    sdk.MustNewDecFromStr("0.001"),
+		MinQuantityTickSize: sdk.MustNewDecFromStr("1000"),
+	}}}, nil
+}
+
+func (q *stateDiffTestQuerier) FetchChainDerivativeMarkets(ctx context.Context, _ string, _ []string, _ bool) (*exchangetypes.QueryDerivativeMarketsResponse, error) {
+	if q.height(ctx) <= 100 {
+		return &exchangetypes.QueryDerivativeMarketsResponse{}, nil
+	}
+	return &exchangetypes.QueryDerivativeMarketsResponse{Markets: []*exchangetypes.FullDerivativeMarket{{Market: &exchangetypes.DerivativeMarket{
+		MarketId:               riskDerivativeMarketId,
+		Ticker:                 "INJ/USDT PERP",
+		Status:                 exchangetypes.MarketStatus_Active,
+		MakerFeeRate:           sdk.MustNewDecFromStr("0.001"),
+		TakerFeeRate:           sdk.MustNewDecFromStr("0.002"),
+		RelayerFeeShareRate:    sdk.MustNewDecFromStr("0.4"),
+		MinPriceTickSize:       sdk.MustNewDecFromStr("0.001"),
+		MinQuantityTickSize:    sdk.MustNewDecFromStr("0.01"),
+		InitialMarginRatio:     sdk.MustNewDecFromStr("0.05"),
+		MaintenanceMarginRatio: sdk.MustNewDecFromStr("0.02"),
+	}}}}, nil
+}
+
+func (q *stateDiffTestQuerier) FetchChainTraderSpotOrders(ctx context.Context, _ string, _ string) (*exchangetypes.QueryTraderSpotOrdersResponse, error) {
+	order := func(orderHash string, fillable string) *exchangetypes.TrimmedSpotLimitOrder {
+		return &exchangetypes.TrimmedSpotLimitOrder{
+			OrderHash: orderHash,
+			IsBuy:     true,
+			Price:     sdk.MustNewDecFromStr("0.000000000001"),
+			Quantity:  sdk.MustNewDecFromStr("10000"),
+			Fillable:  sdk.MustNewDecFromStr(fillable),
+		}
+	}
+	if q.height(ctx) <= 100 {
+		return &exchangetypes.QueryTraderSpotOrdersResponse{Orders: []*exchangetypes.TrimmedSpotLimitOrder{
+			order("0x01", "10000"), order("0x02", "10000"), order("0x03", "10000"), order("0x04", "10000"),
+		}}, nil
+	}
+	return &exchangetypes.QueryTraderSpotOrdersResponse{Orders: []*exchangetypes.TrimmedSpotLimitOrder{
+		order("0x02", "4000"), order("0x04", "10000"), order("0x05", "10000"),
+	}}, nil
+}
+
+func (q *stateDiffTestQuerier) FetchChainTraderDerivativeOrders(context.Context, string, string) (*exchangetypes.QueryTraderDerivativeOrdersResponse, error) {
+	return &exchangetypes.QueryTraderDerivativeOrdersResponse{}, nil
+}
+
+func TestDiffExchangeStates(t *testing.T) {
+	querier := &stateDiffTestQuerier{}
+	ctx := context.Background()
+	scope := ExchangeStateScope{SubaccountIds: []string{riskSubaccountId}}
+
+	from, err := FetchExchangeState(ctx, querier, 100, scope)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), from.Height)
+	assert.Len(t, from.Orders, 4)
+	to, err := FetchExchangeState(ctx, querier, 200, scope)
+	assert.NoError(t, err)
+
+	diff := DiffExchangeStates(from, to)
+	assert.Equal(t, int64(100), diff.FromHeight)
+	assert.Equal(t, int64(200), diff.ToHeight)
+	assert.False(t, diff.IsEmpty())
+	assert.Len(t, diff.NewOrders, 1)
+	assert.Equal(t, "0x05", diff.NewOrders[0].OrderHash)
+	assert.Equal(t, riskSubaccountId, diff.NewOrders[0].SubaccountId)
+	assert.Len(t, diff.ClosedOrders, 2)
+	assert.Len(t, diff.PartiallyFilledOrders, 1)
+	assert.Equal(t, "0x02", diff.PartiallyFilledOrders[0].Order.OrderHash)
+	assert.Equal(t, "6000.000000000000000000", diff.PartiallyFilledOrders[0].FilledQuantity.String())
+
+	filled, cancelled := diff.SplitClosedOrders([]string{"0x03", "0x02"})
+	assert.Len(t, filled, 1)
+	assert.Equal(t, "0x03", filled[0].OrderHash)
+	assert.Len(t, cancelled, 1)
+	assert.Equal(t, "0x01", cancelled[0].OrderHash)
+
+	assert.Len(t, diff.NewMarkets, 1)
+	assert.Equal(t, "INJ/USDT PERP", diff.NewMarkets[0].Ticker)
+	assert.Equal(t, "0.050000000000000000", diff.NewMarkets[0].Params["initial_margin_ratio"])
+	assert.Empty(t, diff.RemovedMarkets)
+	assert.Equal(t, []MarketParamChange{{
+		MarketId: riskSpotMarketId,
+		Ticker:   "INJ/USDT",
+		Param:    "maker_fee_rate",
+		From:     "0.001000000000000000",
+		To:       "-0.000100000000000000",
+	}}, diff.MarketParamChanges)
+
+	assert.True(t, DiffExchangeStates(to, to).IsEmpty())
+
+	querier.failAt = 50
+	_, err = FetchExchangeState(ctx, querier, 50, scope)
+	assert.EqualError(t, err, "failed to fetch the exchange state at height 50: failed to fetch the spot markets: height is not available")
+}
@@ -0,0 +1,227 @@
+package chain
+
+import (
+	"context"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const defaultExpirySweepInterval = 10 * time.Second
+
+type ExpiryPolicy int
+
+const (
+	// ExpiryCancel cancels the orders about to expire
+	ExpiryCancel ExpiryPolicy = iota
+	// ExpiryRefresh replaces every order about to expire with the same order and a new expiry height
+	ExpiryRefresh
+)
+
+// BlockHeightSource returns the latest block height of the chain (tm.TendermintClient implements it)
+type BlockHeightSource interface {
+	GetLatestBlockHeight(ctx context.Context) (int64, error)
+}
+
+type ExpirySweeperConfig struct {
+	Interval time.Duration
+	// HorizonBlocks is how many blocks ahead of the latest height the orders are considered about to expire
+	HorizonBlocks int64
+	Policy        ExpiryPolicy
+	// RefreshBlocks is the lifetime of the refreshed orders from the latest height. It must be greater than
+	// HorizonBlocks with ExpiryRefresh, or the refreshed orders would be swept again right away
+	RefreshBlocks int64
+	// FeeRecipient of the refreshed orders, the sender by default
+	FeeRecipient string
+	// OnSweep is called after every sweep that found orders about to expire
+	OnSweep func(result ExpirySweepResult)
+	// Clock of the sweep interval, the real clock by default
+	Clock clock.Clock
+}
+
+// ExpirySweepResult has the orders swept at a block height
+type ExpirySweepResult struct {
+	Height    int64
+	Cancelled []string
+	// Refreshed has the replacement of every refreshed order, by the hash of the replaced order
+	Refreshed map[string]string
+	// Failed has the error of every order that could not be refreshed, by order hash. Failed orders stay tracked and
+	// are retried in the next sweep
+	Failed   map[string]error
+	TxHashes []string
+}
+
+// ExpirySweeper cancels or refreshes the tracked orders before their expiry height (see OrderTracker.SetExpiry), so
+// no order stays on the book past the lifetime the client gave it
+type ExpirySweeper struct {
+	chainClient ChainClient
+	tracker     *OrderTracker
+	heights     BlockHeightSource
+	replacer    *OrderReplacer
+	config      ExpirySweeperConfig
+	logger      log.Logger
+}
+
+func NewExpirySweeper(chainClient ChainClient, tracker *OrderTracker, heights BlockHeightSource, config ExpirySweeperConfig) (*ExpirySweeper, error) {
+	if config.HorizonBlocks < 0 {
+		return nil, errors.Errorf("invalid horizon of %d blocks", config.HorizonBlocks)
+	}
+	if config.Policy == ExpiryRefresh && config.RefreshBlocks <= config.HorizonBlocks {
+		return nil, errors.Errorf("the refresh lifetime of %d blocks must be greater than the horizon of %d blocks", config.RefreshBlocks, config.HorizonBlocks)
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultExpirySweepInterval
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &ExpirySweeper{
+		chainClient: chainClient,
+		tracker:     tracker,
+		heights:     heights,
+		replacer:    NewOrderReplacer(chainClient, tracker),
+		config:      config,
+		logger:      log.WithField("module", "expiry-sweeper"),
+	}, nil
+}
+
+// Run sweeps the orders every interval until the context is done. Sweep errors are logged
+func (s *ExpirySweeper) Run(ctx context.Context) {
+	ticker := s.config.Clock.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sweep(ctx); err != nil {
+			s.logger.WithError(err).Warningln("failed to sweep the expiring orders")
+		}
+
+		select {
+		case <-ticker.C():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Sweep cancels or refreshes, per the policy, the tracked orders expiring within the horizon of the latest height
+func (s *ExpirySweeper) Sweep(ctx context.Context) (*ExpirySweepResult, error) {
+	height, err := s.heights.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the latest block height")
+	}
+
+	result := &ExpirySweepResult{Height: height, Refreshed: make(map[string]string), Failed: make(map[string]error)}
+	orders := s.tracker.ExpiringOrders(height + s.config.HorizonBlocks)
+	if len(orders) == 0 {
+		return result, nil
+	}
+
+	switch s.config.Policy {
+	case ExpiryRefresh:
+		s.refresh(ctx, height, orders, result)
+	default:
+		err = s.cancel(orders, result)
+	}
+
+	if s.config.OnSweep != nil {
+		s.config.OnSweep(*result)
+	}
+	return result, err
+}
+
+// cancel sends the cancels of all the orders in a single batch update. As in QuoteManager.Refresh, the cancels that
+// fail are for orders already filled or cancelled, so all the orders stop being tracked once the tx succeeds
+func (s *ExpirySweeper) cancel(orders []TrackedOrder, result *ExpirySweepResult) error {
+	msg := &exchangetypes.MsgBatchUpdateOrders{Sender: s.chainClient.FromAddress().String()}
+	for _, order := range orders {
+		orderData := &exchangetypes.OrderData{
+			MarketId:     order.MarketId,
+			SubaccountId: order.SubaccountId,
+			OrderHash:    order.OrderHash,
+		}
+		if order.IsDerivative {
+			msg.DerivativeOrdersToCancel = append(msg.DerivativeOrdersToCancel, orderData)
+		} else {
+			msg.SpotOrdersToCancel = append(msg.SpotOrdersToCancel, orderData)
+		}
+		s.tracker.MarkCancelPending(order.OrderHash)
+	}
+
+	res, err := s.chainClient.SyncBroadcastMsg(msg)
+	if err != nil {
+		// the tx may have been sent, so the cancels stay pending
+		return errors.Wrap(err, "failed to cancel the expiring orders")
+	}
+	if res == nil || res.TxResponse == nil {
+		return errors.New("the cancel of the expiring orders returned no tx response")
+	}
+	txResult, err := NewComposedTxResult([]sdk.Msg{msg}, res.TxResponse)
+	if err != nil {
+		return err
+	}
+	result.TxHashes = append(result.TxHashes, txResult.TxHash)
+	if txResult.Err != nil {
+		for _, order := range orders {
+			s.tracker.ClearCancelPending(order.OrderHash)
+		}
+		return errors.Wrap(txResult.Err, "failed to cancel the expiring orders")
+	}
+
+	for _, order := range orders {
+		s.tracker.Remove(order.OrderHash)
+		result.Cancelled = append(result.Cancelled, order.OrderHash)
+	}
+	return nil
+}
+
+// refresh replaces the orders one by one, so an order that was filled in the meantime is not created again
+func (s *ExpirySweeper) refresh(ctx context.Context, height int64, orders []TrackedOrder, result *ExpirySweepResult) {
+	feeRecipient := s.config.FeeRecipient
+	if feeRecipient == "" {
+		feeRecipient = s.chainClient.FromAddress().String()
+	}
+
+	for _, order := range orders {
+		if ctx.Err() != nil {
+			result.Failed[order.OrderHash] = ctx.Err()
+			continue
+		}
+
+		replaceResult, err := s.replacer.ReplaceOrder(ctx, order.OrderHash, refreshedOrder(order, feeRecipient))
+		if err != nil {
+			result.Failed[order.OrderHash] = err
+			s.logger.WithError(err).WithField("orderHash", order.OrderHash).Warningln("failed to refresh the expiring order")
+			continue
+		}
+		s.tracker.SetExpiry(replaceResult.NewOrderHash, height+s.config.RefreshBlocks)
+		result.Refreshed[order.OrderHash] = replaceResult.NewOrderHash
+		result.TxHashes = append(result.TxHashes, replaceResult.TxHash)
+	}
+}
+
+func refreshedOrder(order TrackedOrder, feeRecipient string) exchangetypes.IOrder {
+	orderInfo := exchangetypes.OrderInfo{
+		SubaccountId: order.SubaccountId,
+		FeeRecipient: feeRecipient,
+		Price:        order.Price,
+		Quantity:     order.Quantity,
+		Cid:          order.Cid,
+	}
+	if order.IsDerivative {
+		return &exchangetypes.DerivativeOrder{
+			MarketId:  order.MarketId,
+			OrderInfo: orderInfo,
+			OrderType: order.OrderType,
+			Margin:    order.Margin,
+		}
+	}
+	return &exchangetypes.SpotOrder{
+		MarketId:  order.MarketId,
+		OrderInfo: orderInfo,
+		OrderType: order.OrderType,
+	}
+}
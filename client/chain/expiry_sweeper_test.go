@@ -0,0 +1,199 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+type expiryTestHeights struct {
+	height int64
+	err    error
+}
+
+func (h *expiryTestHeights) GetLatestBlockHeight(context.Context) (int64, error) {
+	return h.height, h.err
+}
+
+func expiryTestTracker() *OrderTracker {
+	tracker := NewOrderTracker()
+	for orderHash, expiry := range map[string]int64{"0x01": 105, "0x02": 130, "0x03": 0, "0x04": 98} {
+		tracker.Track(TrackedOrder{
+			OrderHash:       orderHash,
+			MarketId:        riskSpotMarketId,
+			SubaccountId:    riskSubaccountId,
+			OrderType:       exchangetypes.OrderType_BUY,
+			Price:           sdk.MustNewDecFromStr("2"),
+			Quantity:        sdk.MustNewDecFromStr("1"),
+			ExpiresAtHeight: expiry,
+		})
+	}
+	return tracker
+}
+
+func TestExpirySweeperCancelsExpiringOrders(t *testing.T) {
+	tracker := expiryTestTracker()
+	chainClient := &quoteTestChainClient{}
+	var swept []ExpirySweepResult
+	sweeper, err := NewExpirySweeper(chainClient, tracker, &expiryTestHeights{height: 100}, ExpirySweeperConfig{
+		HorizonBlocks: 10,
+		OnSweep:       func(result ExpirySweepResult) { swept = append(swept, result) },
+	})
+	assert.NoError(t, err)
+
+	result, err := sweeper.Sweep(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x04", "0x01"}, result.Cancelled)
+	assert.Equal(t, []string{"ABCD"}, result.TxHashes)
+	assert.Len(t, chainClient.batches, 1)
+	assert.Len(t, chainClient.batches[0].SpotOrdersToCancel, 2)
+	assert.Empty(t, chainClient.batches[0].SpotOrdersToCreate)
+	_, found := tracker.Order("0x01")
+	assert.False(t, found)
+	_, found = tracker.Order("0x02")
+	assert.True(t, found)
+	assert.Len(t, swept, 1)
+
+	result, err = sweeper.Sweep(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, result.Cancelled)
+	assert.Len(t, chainClient.batches, 1)
+	assert.Len(t, swept, 1)
+}
+
+func TestExpirySweeperRunSweepsEveryInterval(t *testing.T) {
+	fakeClock := clock.NewFake(time.Unix(1700000000, 0))
+	heights := &expiryTestHeights{height: 100}
+	chainClient := &quoteTestChainClient{}
+	sweptCh := make(chan ExpirySweepResult, 1)
+	sweeper, err := NewExpirySweeper(chainClient, expiryTestTracker(), heights, ExpirySweeperConfig{
+		HorizonBlocks: 10,
+		Interval:      time.Minute,
+		OnSweep:       func(result ExpirySweepResult) { sweptCh <- result },
+		Clock:         fakeClock,
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sweeper.Run(ctx)
+		close(done)
+	}()
+
+	// the first sweep runs when the sweeper starts
+	assert.Equal(t, []string{"0x04", "0x01"}, (<-sweptCh).Cancelled)
+	heights.height = 125
+	fakeClock.Advance(59 * time.Second)
+	select {
+	case <-sweptCh:
+		t.Fatal("the sweep ran before the interval")
+	default:
+	}
+
+	fakeClock.Advance(time.Second)
+	assert.Equal(t, []string{"0x02"}, (<-sweptCh).Cancelled)
+
+	cancel()
+	<-done
+	assert.Len(t, chainClient.batches, 2)
+	assert.Equal(t, 0, fakeClock.Waiters())
+}
+
+func TestExpirySweeperRefreshesExpiringOrders(t *testing.T) {
+	tracker := NewOrderTracker()
+	tracker.Track(TrackedOrder{
+		OrderHash:       "0x01",
+		Cid:             "quote-1",
+		MarketId:        riskSpotMarketId,
+		SubaccountId:    riskSubaccountId,
+		OrderType:       exchangetypes.OrderType_BUY,
+		Price:           sdk.MustNewDecFromStr("2"),
+		Quantity:        sdk.MustNewDecFromStr("1"),
+		ExpiresAtHeight: 105,
+	})
+	chainClient := &composerTestChainClient{txResponse: replaceTestTxResponse(t, "0x02")}
+	sweeper, err := NewExpirySweeper(chainClient, tracker, &expiryTestHeights{height: 100}, ExpirySweeperConfig{
+		HorizonBlocks: 10,
+		Policy:        ExpiryRefresh,
+		RefreshBlocks: 50,
+		FeeRecipient:  "inj1feerecipient",
+	})
+	assert.NoError(t, err)
+
+	result, err := sweeper.Sweep(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"0x01": "0x02"}, result.Refreshed)
+	assert.Empty(t, result.Failed)
+	createMsg, isCreate := chainClient.broadcastedMsgs[1].(*exchangetypes.MsgCreateSpotLimitOrder)
+	assert.True(t, isCreate)
+	assert.Equal(t, "inj1feerecipient", createMsg.Order.OrderInfo.FeeRecipient)
+	assert.Equal(t, "quote-1", createMsg.Order.OrderInfo.Cid)
+	assert.True(t, createMsg.Order.OrderInfo.Price.Equal(sdk.MustNewDecFromStr("2")))
+
+	refreshed, found := tracker.Order("0x02")
+	assert.True(t, found)
+	assert.Equal(t, int64(150), refreshed.ExpiresAtHeight)
+	assert.Equal(t, "0x01", refreshed.ReplacesOrderHash)
+}
+
+func TestExpirySweeperKeepsOrdersWhenTheRefreshFails(t *testing.T) {
+	tracker := expiryTestTracker()
+	chainClient := &composerTestChainClient{txResponse: &sdk.TxResponse{
+		TxHash:    "ABCD",
+		Codespace: exchangetypes.ModuleName,
+		Code:      exchangetypes.ErrOrderDoesntExist.ABCICode(),
+		RawLog:    "failed to execute message; message index: 0: order doesnt exist",
+	}}
+	sweeper, err := NewExpirySweeper(chainClient, tracker, &expiryTestHeights{height: 100}, ExpirySweeperConfig{
+		HorizonBlocks: 10,
+		Policy:        ExpiryRefresh,
+		RefreshBlocks: 50,
+	})
+	assert.NoError(t, err)
+
+	result, err := sweeper.Sweep(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Refreshed)
+	assert.Len(t, result.Failed, 2)
+	assert.True(t, errors.Is(result.Failed["0x01"], exchangetypes.ErrOrderDoesntExist))
+	_, found := tracker.Order("0x01")
+	assert.True(t, found)
+}
+
+func TestExpirySweeperConfigAndHeightErrors(t *testing.T) {
+	_, err := NewExpirySweeper(&quoteTestChainClient{}, NewOrderTracker(), &expiryTestHeights{}, ExpirySweeperConfig{
+		HorizonBlocks: 10,
+		Policy:        ExpiryRefresh,
+		RefreshBlocks: 10,
+	})
+	assert.Error(t, err)
+
+	sweeper, err := NewExpirySweeper(&quoteTestChainClient{}, expiryTestTracker(), &expiryTestHeights{err: errors.New("connection refused")}, ExpirySweeperConfig{})
+	assert.NoError(t, err)
+	_, err = sweeper.Sweep(context.Background())
+	assert.Error(t, err)
+}
+
+func TestOrderTrackerExpiringOrders(t *testing.T) {
+	tracker := expiryTestTracker()
+	tracker.MarkCancelPending("0x04")
+
+	expiring := tracker.ExpiringOrders(110)
+
+	assert.Len(t, expiring, 1)
+	assert.Equal(t, "0x01", expiring[0].OrderHash)
+	assert.True(t, tracker.SetExpiry("0x03", 101))
+	assert.Len(t, tracker.ExpiringOrders(110), 2)
+	assert.False(t, tracker.SetExpiry("0x09", 101))
+}
@@ -0,0 +1,122 @@
+package chain
+
+import (
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// FeeRates are the maker and taker fee rates of a market, after applying the account fee discount
+type FeeRates struct {
+	MakerFeeRate decimal.Decimal
+	TakerFeeRate decimal.Decimal
+}
+
+func discountedFeeRate(feeRate decimal.Decimal, discountRate decimal.Decimal) decimal.Decimal {
+	// negative fee rates are rebates, and the chain does not discount them
+	if !feeRate.IsPositive() {
+		return feeRate
+	}
+	return feeRate.Mul(decimal.NewFromInt(1).Sub(discountRate))
+}
+
+// ComputeEffectiveFeeRates applies the fee discount tier of the account (the AccountInfo returned by
+// FetchFeeDiscountAccountInfo) to the market fee rates. A nil tierInfo means the account has no discount
+func ComputeEffectiveFeeRates(makerFeeRate decimal.Decimal, takerFeeRate decimal.Decimal, tierInfo *exchangetypes.FeeDiscountTierInfo) FeeRates {
+	rates := FeeRates{
+		MakerFeeRate: makerFeeRate,
+		TakerFeeRate: takerFeeRate,
+	}
+	if tierInfo == nil {
+		return rates
+	}
+
+	if !tierInfo.MakerDiscountRate.IsNil() {
+		rates.MakerFeeRate = discountedFeeRate(makerFeeRate, decimalFromDec(tierInfo.MakerDiscountRate))
+	}
+	if !tierInfo.TakerDiscountRate.IsNil() {
+		rates.TakerFeeRate = discountedFeeRate(takerFeeRate, decimalFromDec(tierInfo.TakerDiscountRate))
+	}
+
+	return rates
+}
+
+// OrderFeeRate returns the fee rate the chain uses to compute the order balance hold. Post only orders can only be
+// maker orders, any other order might be matched as taker
+func (r FeeRates) OrderFeeRate(orderType exchangetypes.OrderType) decimal.Decimal {
+	if orderType.IsPostOnly() {
+		return r.MakerFeeRate
+	}
+	return r.TakerFeeRate
+}
+
+// SpotOrderBalanceHold returns the amount locked when creating a spot order. Buy orders lock the quote notional plus
+// the fee (rebates are not subtracted), sell orders lock the base quantity
+func SpotOrderBalanceHold(orderType exchangetypes.OrderType, price decimal.Decimal, quantity decimal.Decimal, rates FeeRates) decimal.Decimal {
+	if !orderType.IsBuy() {
+		return quantity
+	}
+
+	notional := price.Mul(quantity)
+	feeRate := rates.OrderFeeRate(orderType)
+	if feeRate.IsPositive() {
+		return notional.Add(notional.Mul(feeRate))
+	}
+	return notional
+}
+
+// DerivativeOrderMarginHold returns the amount locked when creating a derivative order: the order margin plus the fee
+// for the order notional (rebates are not subtracted)
+func DerivativeOrderMarginHold(orderType exchangetypes.OrderType, price decimal.Decimal, quantity decimal.Decimal, margin decimal.Decimal, rates FeeRates) decimal.Decimal {
+	feeRate := rates.OrderFeeRate(orderType)
+	if feeRate.IsPositive() {
+		return margin.Add(price.Mul(quantity).Mul(feeRate))
+	}
+	return margin
+}
+
+// SpotFeeBreakdown is the fee of a spot order and its price after the fee, if the order is matched as maker and as
+// taker. The amounts are in the units of the order price and quantity (chain format or human readable)
+type SpotFeeBreakdown struct {
+	// Notional is the order price times the order quantity
+	Notional decimal.Decimal
+	// MakerFee and TakerFee are the fees in quote currency. A negative fee is a rebate paid to the account
+	MakerFee decimal.Decimal
+	TakerFee decimal.Decimal
+	// MakerEffectivePrice and TakerEffectivePrice are the prices per base unit after the fee: the price paid by a buy
+	// order, and the price received by a sell order
+	MakerEffectivePrice decimal.Decimal
+	TakerEffectivePrice decimal.Decimal
+}
+
+// ComputeSpotFeeBreakdown returns the fees of a spot order with the fee rates of the account in the market (see
+// FetchEffectiveFeeRates). Post only orders are only matched as maker, so their taker fee and price are the maker ones
+func ComputeSpotFeeBreakdown(orderType exchangetypes.OrderType, price decimal.Decimal, quantity decimal.Decimal, rates FeeRates) SpotFeeBreakdown {
+	takerFeeRate := rates.OrderFeeRate(orderType)
+	breakdown := SpotFeeBreakdown{
+		Notional: price.Mul(quantity),
+	}
+	breakdown.MakerFee = breakdown.Notional.Mul(rates.MakerFeeRate)
+	breakdown.TakerFee = breakdown.Notional.Mul(takerFeeRate)
+	breakdown.MakerEffectivePrice = spotEffectivePrice(orderType, price, rates.MakerFeeRate)
+	breakdown.TakerEffectivePrice = spotEffectivePrice(orderType, price, takerFeeRate)
+	return breakdown
+}
+
+// spotEffectivePrice adds the fee to the price paid by buy orders, and subtracts it from the price received by sell
+// orders
+func spotEffectivePrice(orderType exchangetypes.OrderType, price decimal.Decimal, feeRate decimal.Decimal) decimal.Decimal {
+	if orderType.IsBuy() {
+		return price.Mul(decimal.NewFromInt(1).Add(feeRate))
+	}
+	return price.Mul(decimal.NewFromInt(1).Sub(feeRate))
+}
+
+// RelayerFeeShare returns the part of an order fee paid to the order fee recipient, with the relayer fee share rate of
+// the market. Rebates are not shared
+func RelayerFeeShare(fee decimal.Decimal, relayerFeeShareRate decimal.Decimal) decimal.Decimal {
+	if !fee.IsPositive() {
+		return decimal.Zero
+	}
+	return fee.Mul(relayerFeeShareRate)
+}
@@ -0,0 +1,72 @@
+package chain
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func TestComputeEffectiveFeeRatesDiscountsOnlyPositiveFees(t *testing.T) {
+	tierInfo := &exchangetypes.FeeDiscountTierInfo{
+		MakerDiscountRate: sdk.MustNewDecFromStr("0.1"),
+		TakerDiscountRate: sdk.MustNewDecFromStr("0.2"),
+	}
+
+	rates := ComputeEffectiveFeeRates(decimal.RequireFromString("-0.0001"), decimal.RequireFromString("0.001"), tierInfo)
+	assert.Equal(t, "-0.0001", rates.MakerFeeRate.String())
+	assert.Equal(t, "0.0008", rates.TakerFeeRate.String())
+
+	rates = ComputeEffectiveFeeRates(decimal.RequireFromString("0.0005"), decimal.RequireFromString("0.001"), tierInfo)
+	assert.Equal(t, "0.00045", rates.MakerFeeRate.String())
+
+	rates = ComputeEffectiveFeeRates(decimal.RequireFromString("0.0005"), decimal.RequireFromString("0.001"), nil)
+	assert.Equal(t, "0.0005", rates.MakerFeeRate.String())
+	assert.Equal(t, "0.001", rates.TakerFeeRate.String())
+}
+
+func TestOrderBalanceHolds(t *testing.T) {
+	rates := FeeRates{
+		MakerFeeRate: decimal.RequireFromString("-0.0001"),
+		TakerFeeRate: decimal.RequireFromString("0.0015"),
+	}
+	price := decimal.RequireFromString("10")
+	quantity := decimal.RequireFromString("2")
+
+	assert.Equal(t, "20.03", SpotOrderBalanceHold(exchangetypes.OrderType_BUY, price, quantity, rates).String())
+	assert.Equal(t, "20", SpotOrderBalanceHold(exchangetypes.OrderType_BUY_PO, price, quantity, rates).String())
+	assert.Equal(t, "2", SpotOrderBalanceHold(exchangetypes.OrderType_SELL, price, quantity, rates).String())
+
+	margin := decimal.RequireFromString("5")
+	assert.Equal(t, "5.03", DerivativeOrderMarginHold(exchangetypes.OrderType_SELL, price, quantity, margin, rates).String())
+	assert.Equal(t, "5", DerivativeOrderMarginHold(exchangetypes.OrderType_SELL_PO, price, quantity, margin, rates).String())
+}
+
+func TestComputeSpotFeeBreakdown(t *testing.T) {
+	rates := FeeRates{
+		MakerFeeRate: decimal.RequireFromString("-0.0001"),
+		TakerFeeRate: decimal.RequireFromString("0.0015"),
+	}
+	price := decimal.RequireFromString("10")
+	quantity := decimal.RequireFromString("2")
+
+	buy := ComputeSpotFeeBreakdown(exchangetypes.OrderType_BUY, price, quantity, rates)
+	assert.Equal(t, "20", buy.Notional.String())
+	assert.Equal(t, "-0.002", buy.MakerFee.String())
+	assert.Equal(t, "0.03", buy.TakerFee.String())
+	assert.Equal(t, "9.999", buy.MakerEffectivePrice.String())
+	assert.Equal(t, "10.015", buy.TakerEffectivePrice.String())
+
+	sell := ComputeSpotFeeBreakdown(exchangetypes.OrderType_SELL, price, quantity, rates)
+	assert.Equal(t, "0.03", sell.TakerFee.String())
+	assert.Equal(t, "10.001", sell.MakerEffectivePrice.String())
+	assert.Equal(t, "9.985", sell.TakerEffectivePrice.String())
+
+	// post only orders are never matched as taker
+	postOnly := ComputeSpotFeeBreakdown(exchangetypes.OrderType_SELL_PO, price, quantity, rates)
+	assert.Equal(t, "-0.002", postOnly.TakerFee.String())
+	assert.Equal(t, "10.001", postOnly.TakerEffectivePrice.String())
+}
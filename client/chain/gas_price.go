@@ -0,0 +1,182 @@
+package chain
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	"github.com/cosmos/cosmos-sdk/client/grpc/node"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const (
+	defaultGasPriceBlocks        = 10
+	defaultGasPriceCacheDuration = 10 * time.Second
+)
+
+// FixedGasPriceStrategy always uses the same gas prices
+type FixedGasPriceStrategy struct {
+	gasPrices sdk.DecCoins
+}
+
+func NewFixedGasPriceStrategy(gasPrices string) (*FixedGasPriceStrategy, error) {
+	prices, err := sdk.ParseDecCoins(gasPrices)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to ParseDecCoins %s", gasPrices)
+	}
+	return &FixedGasPriceStrategy{gasPrices: prices}, nil
+}
+
+func (s *FixedGasPriceStrategy) GasPrices(ctx context.Context) (sdk.DecCoins, error) {
+	return s.gasPrices, nil
+}
+
+// NodeGasPriceStrategy uses the minimum gas prices configured in the node the txs are broadcast to, which is the
+// cheapest price the node accepts in its mempool
+type NodeGasPriceStrategy struct {
+	nodeClient node.ServiceClient
+}
+
+func NewNodeGasPriceStrategy(conn *grpc.ClientConn) *NodeGasPriceStrategy {
+	return &NodeGasPriceStrategy{nodeClient: node.NewServiceClient(conn)}
+}
+
+func (s *NodeGasPriceStrategy) GasPrices(ctx context.Context) (sdk.DecCoins, error) {
+	res, err := s.nodeClient.Config(ctx, &node.ConfigRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query the node config")
+	}
+	if res.MinimumGasPrice == "" {
+		return nil, errors.New("the node has no minimum gas price configured")
+	}
+	prices, err := sdk.ParseDecCoins(res.MinimumGasPrice)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to ParseDecCoins %s", res.MinimumGasPrice)
+	}
+	return prices, nil
+}
+
+// PercentileGasPriceStrategy uses a percentile of the gas prices paid by the txs included in the recent blocks. Higher
+// percentiles outbid more of the recent txs, trading a higher cost for a faster inclusion when blocks are full.
+// The gas price is never lower than the floor, which is also used when the recent blocks have no txs
+type PercentileGasPriceStrategy struct {
+	rpcClient  rpcclient.SignClient
+	denom      string
+	percentile int
+	floor      sdk.Dec
+	// Blocks is the number of recent blocks inspected
+	Blocks int
+	// CacheDuration is the time the calculated gas price is reused before inspecting the blocks again
+	CacheDuration time.Duration
+
+	mux       sync.Mutex
+	cached    sdk.DecCoins
+	fetchedAt time.Time
+	clock     clock.Clock
+}
+
+func NewPercentileGasPriceStrategy(rpcClient rpcclient.SignClient, denom string, percentile int, floor sdk.Dec) (*PercentileGasPriceStrategy, error) {
+	if percentile < 0 || percentile > 100 {
+		return nil, errors.Errorf("the gas price percentile must be between 0 and 100, got %d", percentile)
+	}
+	if floor.IsNil() || floor.IsNegative() {
+		return nil, errors.New("the gas price floor can not be negative")
+	}
+	return &PercentileGasPriceStrategy{
+		rpcClient:     rpcClient,
+		denom:         denom,
+		percentile:    percentile,
+		floor:         floor,
+		Blocks:        defaultGasPriceBlocks,
+		CacheDuration: defaultGasPriceCacheDuration,
+		clock:         clock.Real(),
+	}, nil
+}
+
+// SetClock sets the clock of the gas price cache
+func (s *PercentileGasPriceStrategy) SetClock(c clock.Clock) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.clock = clock.OrReal(c)
+}
+
+func (s *PercentileGasPriceStrategy) GasPrices(ctx context.Context) (sdk.DecCoins, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.cached != nil && s.clock.Now().Sub(s.fetchedAt) < s.CacheDuration {
+		return s.cached, nil
+	}
+
+	paidPrices, err := s.recentGasPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	price := s.floor
+	if percentilePrice, found := gasPricePercentile(paidPrices, s.percentile); found && percentilePrice.GT(price) {
+		price = percentilePrice
+	}
+	s.cached = sdk.NewDecCoins(sdk.NewDecCoinFromDec(s.denom, price))
+	s.fetchedAt = s.clock.Now()
+	return s.cached, nil
+}
+
+// recentGasPrices returns the gas price in the strategy denom of every tx in the recent blocks
+func (s *PercentileGasPriceStrategy) recentGasPrices(ctx context.Context) ([]sdk.Dec, error) {
+	latest, err := s.rpcClient.Block(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the latest block")
+	}
+
+	var prices []sdk.Dec
+	height := latest.Block.Height
+	block := latest
+	for i := 0; i < s.Blocks && height > 0; i++ {
+		if i > 0 {
+			block, err = s.rpcClient.Block(ctx, &height)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get block %d", height)
+			}
+		}
+		for _, txBytes := range block.Block.Txs {
+			// txs that can't be decoded (e.g. of unknown msg types) are not used for the percentile
+			decodedTx, err := DecodeTx(txBytes)
+			if err != nil || decodedTx.GasLimit == 0 {
+				continue
+			}
+			// txs paying fees in another denom (or fee granted txs without fees) say nothing about the denom price
+			fee := decodedTx.Fee.AmountOf(s.denom)
+			if !fee.IsPositive() {
+				continue
+			}
+			prices = append(prices, sdk.NewDecFromInt(fee).QuoInt64(int64(decodedTx.GasLimit)))
+		}
+		height--
+	}
+	return prices, nil
+}
+
+// gasPricePercentile returns the nearest-rank percentile of the prices
+func gasPricePercentile(prices []sdk.Dec, percentile int) (sdk.Dec, bool) {
+	if len(prices) == 0 {
+		return sdk.Dec{}, false
+	}
+	sorted := make([]sdk.Dec, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LT(sorted[j])
+	})
+
+	rank := (percentile*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1], true
+}
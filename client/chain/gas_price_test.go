@@ -0,0 +1,107 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+type fakeBlockClient struct {
+	rpcclient.SignClient
+	blocks   map[int64][]cmttypes.Tx
+	latest   int64
+	requests int
+}
+
+func (c *fakeBlockClient) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	c.requests++
+	blockHeight := c.latest
+	if height != nil {
+		blockHeight = *height
+	}
+	return &ctypes.ResultBlock{Block: &cmttypes.Block{
+		Header: cmttypes.Header{Height: blockHeight},
+		Data:   cmttypes.Data{Txs: c.blocks[blockHeight]},
+	}}, nil
+}
+
+func encodeTestTxWithFee(t *testing.T, fee int64, gasLimit uint64) cmttypes.Tx {
+	txConfig := txDecoderConfig()
+	builder := txConfig.NewTxBuilder()
+	assert.NoError(t, builder.SetMsgs(&banktypes.MsgSend{}))
+	builder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("inj", fee)))
+	builder.SetGasLimit(gasLimit)
+
+	txBytes, err := txConfig.TxEncoder()(builder.GetTx())
+	assert.NoError(t, err)
+
+	return txBytes
+}
+
+func TestPercentileGasPriceStrategyUsesRecentBlocks(t *testing.T) {
+	client := &fakeBlockClient{
+		latest: 3,
+		blocks: map[int64][]cmttypes.Tx{
+			3: {encodeTestTxWithFee(t, 500000, 1000), encodeTestTxWithFee(t, 800000, 1000), []byte("invalid")},
+			2: {encodeTestTxWithFee(t, 1000000, 1000)},
+			1: {encodeTestTxWithFee(t, 600000, 1000)},
+		},
+	}
+	strategy, err := NewPercentileGasPriceStrategy(client, "inj", 75, sdk.NewDec(550))
+	assert.NoError(t, err)
+	fakeClock := clock.NewFake(time.Unix(1700000000, 0))
+	strategy.SetClock(fakeClock)
+
+	prices, err := strategy.GasPrices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "800.000000000000000000inj", prices.String())
+	assert.Equal(t, 3, client.requests)
+
+	_, err = strategy.GasPrices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, client.requests)
+
+	strategy.percentile = 0
+	fakeClock.Advance(defaultGasPriceCacheDuration)
+	prices, err = strategy.GasPrices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "550.000000000000000000inj", prices.String())
+
+	_, err = NewPercentileGasPriceStrategy(client, "inj", 101, sdk.ZeroDec())
+	assert.Error(t, err)
+}
+
+func TestPercentileGasPriceStrategySkipsTxsWithoutFees(t *testing.T) {
+	client := &fakeBlockClient{
+		latest: 1,
+		blocks: map[int64][]cmttypes.Tx{
+			1: {encodeTestTxWithFee(t, 0, 1000), encodeTestTxWithFee(t, 0, 1000), encodeTestTxWithFee(t, 700000, 1000)},
+		},
+	}
+	strategy, err := NewPercentileGasPriceStrategy(client, "inj", 50, sdk.NewDec(1))
+	assert.NoError(t, err)
+
+	prices, err := strategy.GasPrices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "700.000000000000000000inj", prices.String())
+}
+
+func TestFixedGasPriceStrategy(t *testing.T) {
+	strategy, err := NewFixedGasPriceStrategy("500000000inj")
+	assert.NoError(t, err)
+	prices, err := strategy.GasPrices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "500000000.000000000000000000inj", prices.String())
+
+	_, err = NewFixedGasPriceStrategy("invalid price")
+	assert.Error(t, err)
+}
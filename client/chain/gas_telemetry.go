@@ -0,0 +1,162 @@
+package chain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GasTelemetryConfig bounds the gas adjustment tuned by GasTelemetry. The adjustment for a msg types combination is
+// the highest used/simulated gas ratio of the last Window txs, plus Headroom. Out of gas failures count as a ratio
+// of gas wanted/simulated plus OutOfGasStep, so the adjustment grows until the txs stop failing
+type GasTelemetryConfig struct {
+	MinAdjustment float64
+	MaxAdjustment float64
+	Headroom      float64
+	OutOfGasStep  float64
+	Window        int
+}
+
+func DefaultGasTelemetryConfig() GasTelemetryConfig {
+	return GasTelemetryConfig{
+		MinAdjustment: 1.05,
+		MaxAdjustment: 2.5,
+		Headroom:      0.05,
+		OutOfGasStep:  0.2,
+		Window:        20,
+	}
+}
+
+// GasStats are the gas figures recorded for a msg types combination
+type GasStats struct {
+	MsgTypes          string
+	Samples           int
+	OutOfGas          int
+	TotalSimulatedGas uint64
+	TotalGasWanted    uint64
+	TotalGasUsed      uint64
+	// GasAdjustment is the adjustment currently suggested for the msg types
+	GasAdjustment float64
+}
+
+// AverageUsedRatio is the average of gas used / simulated gas
+func (s GasStats) AverageUsedRatio() float64 {
+	if s.TotalSimulatedGas == 0 {
+		return 0
+	}
+	return float64(s.TotalGasUsed) / float64(s.TotalSimulatedGas)
+}
+
+type gasSamples struct {
+	stats  GasStats
+	ratios []float64
+}
+
+// GasTelemetry records the simulated gas and the gas used of the txs broadcast by the client, grouped by the msg
+// types in the tx, and tunes the gas adjustment used for the next txs with the same msg types. Set it in the client
+// with common.OptionGasTelemetry
+type GasTelemetry struct {
+	config GasTelemetryConfig
+
+	mux     sync.RWMutex
+	samples map[string]*gasSamples
+}
+
+func NewGasTelemetry(config GasTelemetryConfig) *GasTelemetry {
+	if config.Window <= 0 {
+		config.Window = DefaultGasTelemetryConfig().Window
+	}
+	if config.MaxAdjustment < config.MinAdjustment {
+		config.MaxAdjustment = config.MinAdjustment
+	}
+	return &GasTelemetry{
+		config:  config,
+		samples: make(map[string]*gasSamples),
+	}
+}
+
+// GasAdjustment returns the tuned adjustment for the msgs, or defaultAdjustment if no tx with the same msg types was
+// recorded yet
+func (g *GasTelemetry) GasAdjustment(msgs []sdk.Msg, defaultAdjustment float64) float64 {
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+
+	samples, found := g.samples[msgTypesKey(msgs)]
+	if !found || len(samples.ratios) == 0 {
+		return defaultAdjustment
+	}
+	return samples.stats.GasAdjustment
+}
+
+// RecordGas records the gas of a tx included in a block. outOfGas is true if the tx failed because gasWanted was not
+// enough
+func (g *GasTelemetry) RecordGas(msgs []sdk.Msg, simulatedGas uint64, gasWanted uint64, gasUsed uint64, outOfGas bool) {
+	if simulatedGas == 0 {
+		return
+	}
+
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	key := msgTypesKey(msgs)
+	samples, found := g.samples[key]
+	if !found {
+		samples = &gasSamples{stats: GasStats{MsgTypes: key}}
+		g.samples[key] = samples
+	}
+
+	ratio := float64(gasUsed) / float64(simulatedGas)
+	samples.stats.Samples++
+	if outOfGas {
+		samples.stats.OutOfGas++
+		ratio = float64(gasWanted)/float64(simulatedGas) + g.config.OutOfGasStep
+	}
+	samples.stats.TotalSimulatedGas += simulatedGas
+	samples.stats.TotalGasWanted += gasWanted
+	samples.stats.TotalGasUsed += gasUsed
+
+	samples.ratios = append(samples.ratios, ratio)
+	if len(samples.ratios) > g.config.Window {
+		samples.ratios = samples.ratios[len(samples.ratios)-g.config.Window:]
+	}
+
+	maxRatio := 0.0
+	for _, r := range samples.ratios {
+		maxRatio = math.Max(maxRatio, r)
+	}
+	adjustment := maxRatio * (1 + g.config.Headroom)
+	samples.stats.GasAdjustment = math.Min(g.config.MaxAdjustment, math.Max(g.config.MinAdjustment, adjustment))
+}
+
+// Stats returns the recorded figures of every msg types combination, sorted by msg types
+func (g *GasTelemetry) Stats() []GasStats {
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+
+	stats := make([]GasStats, 0, len(g.samples))
+	for _, samples := range g.samples {
+		stats = append(stats, samples.stats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].MsgTypes < stats[j].MsgTypes
+	})
+	return stats
+}
+
+// msgTypesKey is the sorted list of the distinct msg types, so txs with the same msgs in any order share the stats
+func msgTypesKey(msgs []sdk.Msg) string {
+	seen := make(map[string]bool)
+	var msgTypes []string
+	for _, msg := range msgs {
+		msgType := sdk.MsgTypeURL(msg)
+		if !seen[msgType] {
+			seen[msgType] = true
+			msgTypes = append(msgTypes, msgType)
+		}
+	}
+	sort.Strings(msgTypes)
+	return strings.Join(msgTypes, ",")
+}
@@ -0,0 +1,58 @@
+package chain
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func TestGasTelemetryTunesAdjustmentPerMsgTypes(t *testing.T) {
+	telemetry := NewGasTelemetry(GasTelemetryConfig{
+		MinAdjustment: 1.05,
+		MaxAdjustment: 2,
+		Headroom:      0.1,
+		OutOfGasStep:  0.2,
+		Window:        2,
+	})
+	orderMsgs := []sdk.Msg{&exchangetypes.MsgCreateSpotLimitOrder{}, &exchangetypes.MsgCancelSpotOrder{}}
+	sendMsgs := []sdk.Msg{&banktypes.MsgSend{}}
+
+	assert.Equal(t, 1.5, telemetry.GasAdjustment(orderMsgs, 1.5))
+
+	telemetry.RecordGas(orderMsgs, 100000, 150000, 120000, false)
+	assert.InDelta(t, 1.32, telemetry.GasAdjustment(orderMsgs, 1.5), 1e-9)
+	// the msgs order does not change the stats used
+	reversed := []sdk.Msg{orderMsgs[1], orderMsgs[0]}
+	assert.InDelta(t, 1.32, telemetry.GasAdjustment(reversed, 1.5), 1e-9)
+	assert.Equal(t, 1.5, telemetry.GasAdjustment(sendMsgs, 1.5))
+
+	telemetry.RecordGas(sendMsgs, 100000, 150000, 90000, false)
+	assert.Equal(t, 1.05, telemetry.GasAdjustment(sendMsgs, 1.5))
+
+	telemetry.RecordGas(orderMsgs, 100000, 132000, 132000, true)
+	assert.InDelta(t, 1.672, telemetry.GasAdjustment(orderMsgs, 1.5), 1e-9)
+
+	telemetry.RecordGas(orderMsgs, 100000, 167200, 110000, false)
+	telemetry.RecordGas(orderMsgs, 100000, 167200, 110000, false)
+	assert.InDelta(t, 1.21, telemetry.GasAdjustment(orderMsgs, 1.5), 1e-9)
+
+	telemetry.RecordGas(sendMsgs, 100000, 105000, 105000, true)
+	telemetry.RecordGas(sendMsgs, 100000, 125000, 125000, true)
+	telemetry.RecordGas(sendMsgs, 100000, 165000, 165000, true)
+	telemetry.RecordGas(sendMsgs, 100000, 200000, 200000, true)
+	assert.Equal(t, 2.0, telemetry.GasAdjustment(sendMsgs, 1.5))
+
+	stats := telemetry.Stats()
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "/cosmos.bank.v1beta1.MsgSend", stats[0].MsgTypes)
+	assert.Equal(t, 4, stats[0].OutOfGas)
+	assert.Equal(t, "/injective.exchange.v1beta1.MsgCancelSpotOrder,/injective.exchange.v1beta1.MsgCreateSpotLimitOrder", stats[1].MsgTypes)
+	assert.Equal(t, 4, stats[1].Samples)
+	assert.Equal(t, 1, stats[1].OutOfGas)
+	assert.Equal(t, uint64(472000), stats[1].TotalGasUsed)
+	assert.InDelta(t, 1.18, stats[1].AverageUsedRatio(), 1e-9)
+}
@@ -0,0 +1,99 @@
+package chain
+
+import (
+	"encoding/json"
+
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// ReadGenesisFile loads a genesis file, returning the genesis doc and its app state by module name
+func ReadGenesisFile(path string) (map[string]json.RawMessage, *tmtypes.GenesisDoc, error) {
+	appState, genDoc, err := genutiltypes.GenesisStateFromGenFile(path)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read the genesis file %s", path)
+	}
+	return appState, genDoc, nil
+}
+
+// WriteGenesisFile stores the genesis doc in the file, replacing its app state with the provided one
+func WriteGenesisFile(path string, genDoc *tmtypes.GenesisDoc, appState map[string]json.RawMessage) error {
+	appStateJSON, err := json.Marshal(appState)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the genesis app state")
+	}
+	genDoc.AppState = appStateJSON
+
+	if err := genDoc.ValidateAndComplete(); err != nil {
+		return errors.Wrap(err, "invalid genesis doc")
+	}
+	if err := genDoc.SaveAs(path); err != nil {
+		return errors.Wrapf(err, "failed to write the genesis file %s", path)
+	}
+	return nil
+}
+
+// ModuleGenesisState decodes the genesis state of the module from the app state. It returns false if the app
+// state has no genesis state for the module
+func ModuleGenesisState(cdc codec.JSONCodec, appState map[string]json.RawMessage, moduleName string, genesisState proto.Message) (bool, error) {
+	moduleState, found := appState[moduleName]
+	if !found {
+		return false, nil
+	}
+	if err := cdc.UnmarshalJSON(moduleState, genesisState); err != nil {
+		return false, errors.Wrapf(err, "failed to decode the %s genesis state", moduleName)
+	}
+	return true, nil
+}
+
+// SetModuleGenesisState encodes the genesis state of the module into the app state
+func SetModuleGenesisState(cdc codec.JSONCodec, appState map[string]json.RawMessage, moduleName string, genesisState proto.Message) error {
+	moduleState, err := cdc.MarshalJSON(genesisState)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode the %s genesis state", moduleName)
+	}
+	appState[moduleName] = moduleState
+	return nil
+}
+
+// ExchangeGenesisState returns the validated exchange module genesis state from the app state, or the default
+// genesis state if the app state has none
+func ExchangeGenesisState(cdc codec.JSONCodec, appState map[string]json.RawMessage) (*exchangetypes.GenesisState, error) {
+	var genesisState exchangetypes.GenesisState
+	found, err := ModuleGenesisState(cdc, appState, exchangetypes.ModuleName, &genesisState)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return exchangetypes.DefaultGenesisState(), nil
+	}
+
+	if err := genesisState.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid exchange genesis state")
+	}
+	return &genesisState, nil
+}
+
+// SetExchangeGenesisState validates the exchange module genesis state and stores it in the app state
+func SetExchangeGenesisState(cdc codec.JSONCodec, appState map[string]json.RawMessage, genesisState *exchangetypes.GenesisState) error {
+	if err := genesisState.Validate(); err != nil {
+		return errors.Wrap(err, "invalid exchange genesis state")
+	}
+	return SetModuleGenesisState(cdc, appState, exchangetypes.ModuleName, genesisState)
+}
+
+// SetExchangeParams validates the exchange module params and replaces them in the genesis state of the app state,
+// keeping the rest of the module genesis state
+func SetExchangeParams(cdc codec.JSONCodec, appState map[string]json.RawMessage, params exchangetypes.Params) error {
+	genesisState, err := ExchangeGenesisState(cdc, appState)
+	if err != nil {
+		return err
+	}
+	genesisState.Params = params
+	return SetExchangeGenesisState(cdc, appState, genesisState)
+}
@@ -0,0 +1,54 @@
+package chain
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func TestExchangeGenesisStateDefaultsWhenMissing(t *testing.T) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+
+	genesisState, err := ExchangeGenesisState(cdc, map[string]json.RawMessage{})
+	assert.NoError(t, err)
+	assert.Equal(t, exchangetypes.DefaultGenesisState(), genesisState)
+}
+
+func TestSetExchangeParamsRejectsInvalidParams(t *testing.T) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	params := exchangetypes.DefaultParams()
+	params.DefaultSpotTakerFeeRate = sdk.NewDec(2)
+
+	assert.Error(t, SetExchangeParams(cdc, map[string]json.RawMessage{}, params))
+}
+
+func TestGenesisFileRoundTrip(t *testing.T) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	path := filepath.Join(t.TempDir(), "genesis.json")
+
+	appState := map[string]json.RawMessage{}
+	params := exchangetypes.DefaultParams()
+	params.DefaultSpotTakerFeeRate = sdk.NewDecWithPrec(2, 3)
+	assert.NoError(t, SetExchangeParams(cdc, appState, params))
+
+	genDoc := &tmtypes.GenesisDoc{ChainID: "injective-777", GenesisTime: time.Unix(1700000000, 0).UTC()}
+	assert.NoError(t, WriteGenesisFile(path, genDoc, appState))
+
+	readAppState, readGenDoc, err := ReadGenesisFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "injective-777", readGenDoc.ChainID)
+
+	genesisState, err := ExchangeGenesisState(cdc, readAppState)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.002000000000000000", genesisState.Params.DefaultSpotTakerFeeRate.String())
+	assert.True(t, genesisState.IsSpotExchangeEnabled)
+}
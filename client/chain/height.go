@@ -0,0 +1,87 @@
+package chain
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type heightCallOption struct {
+	grpc.EmptyCallOption
+	height int64
+}
+
+// WithHeight returns a call option pinning a chain query to the state at the given block height, for reproducible
+// reads. It applies to the gRPC query clients built on the chain client connection (ChainClient.QueryClient()), e.g.
+// exchangetypes.NewQueryClient(chainClient.QueryClient()).SpotMarkets(ctx, req, WithHeight(h)). Combine it with
+// grpc.Header and ResolvedHeight to read the height the node used. A height of 0 queries the latest height
+func WithHeight(height int64) grpc.CallOption {
+	return heightCallOption{height: height}
+}
+
+type heightQueryKey struct{}
+
+type heightQuery struct {
+	height   int64
+	resolved int64
+}
+
+// QueryAtHeight runs query, made of one or more ChainClient query methods, pinned to the block height (0 for the
+// latest height), and returns its result with the block height the node resolved it at (0 if the node did not report
+// it)
+func QueryAtHeight[T any](ctx context.Context, height int64, query func(ctx context.Context) (T, error)) (T, int64, error) {
+	pinned := &heightQuery{height: height}
+	res, err := query(context.WithValue(ctx, heightQueryKey{}, pinned))
+	if err != nil {
+		return res, 0, err
+	}
+	return res, atomic.LoadInt64(&pinned.resolved), nil
+}
+
+// ResolvedHeight returns the block height reported in the header of a query response
+func ResolvedHeight(header metadata.MD) (int64, error) {
+	values := header.Get(grpctypes.GRPCBlockHeightHeader)
+	if len(values) == 0 {
+		return 0, errors.Errorf("the response header has no %s", grpctypes.GRPCBlockHeightHeader)
+	}
+	height, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s %q", grpctypes.GRPCBlockHeightHeader, values[0])
+	}
+	return height, nil
+}
+
+// heightInterceptor sets the block height header of the calls made with WithHeight or inside QueryAtHeight, and
+// reads the response height of the latter
+func heightInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var height int64
+	for _, opt := range opts {
+		if heightOption, ok := opt.(heightCallOption); ok {
+			height = heightOption.height
+		}
+	}
+	query, _ := ctx.Value(heightQueryKey{}).(*heightQuery)
+	if height == 0 && query != nil {
+		height = query.height
+	}
+	if height > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+	}
+	if query == nil {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	var header metadata.MD
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+	if err == nil {
+		if resolved, heightErr := ResolvedHeight(header); heightErr == nil {
+			atomic.StoreInt64(&query.resolved, resolved)
+		}
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestResolvedHeight(t *testing.T) {
+	height, err := ResolvedHeight(metadata.Pairs(grpctypes.GRPCBlockHeightHeader, "987"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(987), height)
+
+	_, err = ResolvedHeight(metadata.MD{})
+	assert.Error(t, err)
+	_, err = ResolvedHeight(metadata.Pairs(grpctypes.GRPCBlockHeightHeader, "latest"))
+	assert.Error(t, err)
+}
+
+// heightTestInvoker answers the calls with the requested height, or 555 for the latest height
+func heightTestInvoker(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	height := "555"
+	if md, found := metadata.FromOutgoingContext(ctx); found && len(md.Get(grpctypes.GRPCBlockHeightHeader)) > 0 {
+		height = md.Get(grpctypes.GRPCBlockHeightHeader)[0]
+	}
+	for _, opt := range opts {
+		if headerOption, ok := opt.(grpc.HeaderCallOption); ok {
+			*headerOption.HeaderAddr = metadata.Pairs(grpctypes.GRPCBlockHeightHeader, height)
+		}
+	}
+	return nil
+}
+
+func TestWithHeightCallOption(t *testing.T) {
+	var header metadata.MD
+	err := heightInterceptor(context.Background(), "/test", nil, nil, nil, heightTestInvoker, WithHeight(12345), grpc.Header(&header))
+	assert.NoError(t, err)
+	height, err := ResolvedHeight(header)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), height)
+
+	err = heightInterceptor(context.Background(), "/test", nil, nil, nil, heightTestInvoker, WithHeight(0), grpc.Header(&header))
+	assert.NoError(t, err)
+	height, _ = ResolvedHeight(header)
+	assert.Equal(t, int64(555), height)
+}
+
+func TestQueryAtHeightReturnsTheResolvedHeight(t *testing.T) {
+	query := func(ctx context.Context) (string, error) {
+		return "result", heightInterceptor(ctx, "/test", nil, nil, nil, heightTestInvoker)
+	}
+
+	res, height, err := QueryAtHeight(context.Background(), 0, query)
+	assert.NoError(t, err)
+	assert.Equal(t, "result", res)
+	assert.Equal(t, int64(555), height)
+
+	_, height, err = QueryAtHeight(context.Background(), 777, query)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(777), height)
+
+	// calls made outside QueryAtHeight are not pinned
+	assert.NoError(t, heightInterceptor(context.Background(), "/test", nil, nil, nil, heightTestInvoker))
+}
@@ -0,0 +1,101 @@
+package chain
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v7/modules/core/exported"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultIBCTransferTimeoutBlocks   = 1000
+	DefaultIBCTransferTimeoutDuration = 10 * time.Minute
+)
+
+// IBCTimeoutHeight returns the height blockOffset blocks after the latest counterparty height, keeping the revision number
+func IBCTimeoutHeight(latestHeight ibcexported.Height, blockOffset uint64) clienttypes.Height {
+	if latestHeight == nil || blockOffset == 0 {
+		return clienttypes.ZeroHeight()
+	}
+	return clienttypes.NewHeight(latestHeight.GetRevisionNumber(), latestHeight.GetRevisionHeight()+blockOffset)
+}
+
+// IBCTimeoutTimestamp returns the timeout timestamp (in nanoseconds) expected by MsgTransfer. A zero duration disables
+// the timestamp timeout
+func IBCTimeoutTimestamp(now time.Time, timeoutDuration time.Duration) uint64 {
+	if timeoutDuration <= 0 {
+		return 0
+	}
+	return uint64(now.Add(timeoutDuration).UnixNano())
+}
+
+// NewMsgIBCTransfer builds an ICS-20 MsgTransfer. At least one of timeoutHeight or timeoutTimestamp must be set,
+// use chainClient.ComputeIBCTransferTimeout to calculate them from the channel state
+func NewMsgIBCTransfer(
+	sourcePort string,
+	sourceChannel string,
+	token sdk.Coin,
+	sender string,
+	receiver string,
+	timeoutHeight clienttypes.Height,
+	timeoutTimestamp uint64,
+	memo string,
+) (*ibctransfertypes.MsgTransfer, error) {
+	if timeoutHeight.IsZero() && timeoutTimestamp == 0 {
+		return nil, errors.New("IBC transfer timeout height and timeout timestamp can not be both zero")
+	}
+
+	msg := ibctransfertypes.NewMsgTransfer(sourcePort, sourceChannel, token, sender, receiver, timeoutHeight, timeoutTimestamp, memo)
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// EncodeIBCTransferMemo marshals the memo payload to JSON (e.g. the wasm hooks or packet forward middleware metadata).
+// Strings are used as they are
+func EncodeIBCTransferMemo(payload interface{}) (string, error) {
+	switch m := payload.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return m, nil
+	default:
+		memo, err := json.Marshal(payload)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to encode IBC transfer memo")
+		}
+		return string(memo), nil
+	}
+}
+
+// IBCDenomFromTrace returns the voucher denom (ibc/{hash}) for a full denom trace path (e.g. transfer/channel-1/uatom).
+// Non prefixed (native) denoms are returned without changes
+func IBCDenomFromTrace(fullDenomPath string) string {
+	return ibctransfertypes.ParseDenomTrace(fullDenomPath).IBCDenom()
+}
+
+// IBCDenomHash extracts the hex hash from an IBC voucher denom (ibc/{hash}), to be used with FetchDenomTrace
+func IBCDenomHash(denom string) (string, error) {
+	if err := ibctransfertypes.ValidateIBCDenom(denom); err != nil {
+		return "", err
+	}
+
+	prefix := ibctransfertypes.DenomPrefix + "/"
+	if !strings.HasPrefix(denom, prefix) {
+		return "", errors.Errorf("%s is not an IBC voucher denom", denom)
+	}
+	hash := strings.TrimPrefix(denom, prefix)
+
+	if _, err := ibctransfertypes.ParseHexHash(hash); err != nil {
+		return "", errors.Wrapf(err, "invalid IBC denom hash %s", hash)
+	}
+
+	return hash, nil
+}
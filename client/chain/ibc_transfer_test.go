@@ -0,0 +1,54 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIBCTimeoutHeightKeepsRevision(t *testing.T) {
+	latest := clienttypes.NewHeight(4, 1000)
+
+	assert.Equal(t, clienttypes.NewHeight(4, 1100), IBCTimeoutHeight(latest, 100))
+	assert.True(t, IBCTimeoutHeight(latest, 0).IsZero())
+	assert.True(t, IBCTimeoutHeight(nil, 100).IsZero())
+}
+
+func TestIBCTimeoutTimestamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	assert.Equal(t, uint64(now.Add(time.Minute).UnixNano()), IBCTimeoutTimestamp(now, time.Minute))
+	assert.Equal(t, uint64(0), IBCTimeoutTimestamp(now, 0))
+}
+
+func TestIBCDenomResolution(t *testing.T) {
+	denom := IBCDenomFromTrace("transfer/channel-1/uatom")
+	assert.Equal(t, "ibc/C4CFF46FD6DE35CA4CF4CE031E643C8FDC9BA4B99AE598E9B0ED98FE3A2319F9", denom)
+	assert.Equal(t, "inj", IBCDenomFromTrace("inj"))
+
+	hash, err := IBCDenomHash(denom)
+	assert.NoError(t, err)
+	assert.Equal(t, "C4CFF46FD6DE35CA4CF4CE031E643C8FDC9BA4B99AE598E9B0ED98FE3A2319F9", hash)
+
+	_, err = IBCDenomHash("peggy0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	assert.Error(t, err)
+}
+
+func TestNewMsgIBCTransferRequiresTimeout(t *testing.T) {
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	receiver := "cosmos1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r"
+	token := sdk.NewInt64Coin("inj", 1000)
+
+	_, err := NewMsgIBCTransfer("transfer", "channel-1", token, sender, receiver, clienttypes.ZeroHeight(), 0, "")
+	assert.Error(t, err)
+
+	memo, err := EncodeIBCTransferMemo(map[string]interface{}{"forward": map[string]string{"receiver": receiver}})
+	assert.NoError(t, err)
+
+	msg, err := NewMsgIBCTransfer("transfer", "channel-1", token, sender, receiver, clienttypes.NewHeight(4, 1100), 0, memo)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"forward":{"receiver":"cosmos1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r"}}`, msg.Memo)
+}
@@ -0,0 +1,67 @@
+package chain
+
+import (
+	"context"
+
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
+)
+
+// The interfaces below are subsets of ChainClient. Components that only need part of the client should depend on
+// them, so the client can be mocked or decorated (e.g. with metrics or rate limiting) by implementing a few methods
+
+// OrderSubmitter broadcasts txs with exchange (or any other) msgs
+type OrderSubmitter interface {
+	AsyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error)
+	SyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error)
+	// same as AsyncBroadcastMsg and SyncBroadcastMsg, using the memo instead of the client default memo.
+	// Use EncodeTxMemo to include the client metadata (strategy and session ids) in the memo
+	AsyncBroadcastMsgWithMemo(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error)
+	SyncBroadcastMsgWithMemo(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error)
+	// same as AsyncBroadcastMsg and SyncBroadcastMsg, returning also the decoded results of the cancel msgs
+	AsyncBroadcastCancelMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error)
+	SyncBroadcastCancelMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CancelResponse, error)
+	SyncBroadcastCreateMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, *CreateResponse, error)
+	QueueBroadcastMsg(msgs ...sdk.Msg) error
+}
+
+// MarketQuerier queries the exchange module markets and orderbooks
+type MarketQuerier interface {
+	FetchChainSpotMarkets(ctx context.Context, status string, marketIds []string) (*exchangetypes.QuerySpotMarketsResponse, error)
+	FetchChainSpotMarket(ctx context.Context, marketId string) (*exchangetypes.QuerySpotMarketResponse, error)
+	FetchChainDerivativeMarkets(ctx context.Context, status string, marketIds []string, withMidPriceAndTob bool) (*exchangetypes.QueryDerivativeMarketsResponse, error)
+	FetchChainDerivativeMarket(ctx context.Context, marketId string) (*exchangetypes.QueryDerivativeMarketResponse, error)
+	FetchChainBinaryOptionsMarkets(ctx context.Context, status string) (*exchangetypes.QueryBinaryMarketsResponse, error)
+	FetchChainSpotOrderbook(ctx context.Context, marketId string, limit uint64, orderSide exchangetypes.OrderSide, limitCumulativeNotional sdk.Dec, limitCumulativeQuantity sdk.Dec) (*exchangetypes.QuerySpotOrderbookResponse, error)
+	FetchChainDerivativeOrderbook(ctx context.Context, marketId string, limit uint64, limitCumulativeNotional sdk.Dec) (*exchangetypes.QueryDerivativeOrderbookResponse, error)
+	FetchSpotMidPriceAndTOB(ctx context.Context, marketId string) (*exchangetypes.QuerySpotMidPriceAndTOBResponse, error)
+	FetchDerivativeMidPriceAndTOB(ctx context.Context, marketId string) (*exchangetypes.QueryDerivativeMidPriceAndTOBResponse, error)
+	FetchChainPerpetualMarketInfo(ctx context.Context, marketId string) (*exchangetypes.QueryPerpetualMarketInfoResponse, error)
+	FetchChainPerpetualMarketFunding(ctx context.Context, marketId string) (*exchangetypes.QueryPerpetualMarketFundingResponse, error)
+}
+
+// Streamer subscribes to the chain events and to the chain stream
+type Streamer interface {
+	StreamEventOrderFail(sender string, failEventCh chan map[string]uint)
+	StreamEventOrderFailWithWebsocket(sender string, websocket *rpchttp.HTTP, failEventCh chan map[string]uint)
+	StreamOrderbookUpdateEvents(orderbookType OrderbookType, marketIds []string, orderbookCh chan exchangetypes.Orderbook)
+	StreamOrderbookUpdateEventsWithWebsocket(orderbookType OrderbookType, marketIds []string, websocket *rpchttp.HTTP, orderbookCh chan exchangetypes.Orderbook)
+
+	ChainStream(ctx context.Context, req chainstreamtypes.StreamRequest) (chainstreamtypes.Stream_StreamClient, error)
+}
+
+// BalanceQuerier queries the bank balances of accounts and the exchange deposits of subaccounts
+type BalanceQuerier interface {
+	GetBankBalances(ctx context.Context, address string) (*banktypes.QueryAllBalancesResponse, error)
+	GetBankBalance(ctx context.Context, address string, denom string) (*banktypes.QueryBalanceResponse, error)
+	GetBankSpendableBalances(ctx context.Context, address string, pagination *query.PageRequest) (*banktypes.QuerySpendableBalancesResponse, error)
+	GetBankSpendableBalancesByDenom(ctx context.Context, address string, denom string) (*banktypes.QuerySpendableBalanceByDenomResponse, error)
+	FetchSubaccountDeposits(ctx context.Context, subaccountID string) (*exchangetypes.QuerySubaccountDepositsResponse, error)
+	FetchSubaccountDeposit(ctx context.Context, subaccountId string, denom string) (*exchangetypes.QuerySubaccountDepositResponse, error)
+}
@@ -0,0 +1,136 @@
+package chain
+
+import (
+	"context"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+const defaultAccountSequencePollInterval = time.Second
+
+// KeyRotationGrantMsgTypes are the msgs the new key is allowed to execute on behalf of the old key when
+// KeyRotationOptions.GrantOrderManagement is set, to cancel or replace the orders of the old key subaccounts
+var KeyRotationGrantMsgTypes = []string{
+	sdk.MsgTypeURL(&exchangetypes.MsgBatchUpdateOrders{}),
+	sdk.MsgTypeURL(&exchangetypes.MsgCancelSpotOrder{}),
+	sdk.MsgTypeURL(&exchangetypes.MsgBatchCancelSpotOrders{}),
+	sdk.MsgTypeURL(&exchangetypes.MsgCancelDerivativeOrder{}),
+	sdk.MsgTypeURL(&exchangetypes.MsgBatchCancelDerivativeOrders{}),
+	sdk.MsgTypeURL(&exchangetypes.MsgCancelBinaryOptionsOrder{}),
+	sdk.MsgTypeURL(&exchangetypes.MsgBatchCancelBinaryOptionsOrders{}),
+}
+
+type KeyRotationOptions struct {
+	// Keyring has the new key. Nil uses the keyring of the current key
+	Keyring keyring.Keyring
+	// GrantOrderManagement broadcasts, signed with the old key before the rotation, the authz grants allowing the new
+	// key to run KeyRotationGrantMsgTypes for the old key until GrantExpiration. The open orders of the old key can
+	// then be cancelled and re-quoted by the new key with MsgExec. The key is not rotated if the grants fail
+	GrantOrderManagement bool
+	GrantExpiration      time.Time
+}
+
+// KeyRotation is the result of ChainClient.RotateSigningKey
+type KeyRotation struct {
+	OldAddress sdk.AccAddress
+	NewAddress sdk.AccAddress
+	// OldSequence is the sequence following the last tx signed with the old key. All the txs of the old key still in
+	// flight are committed once the sequence of the old account reaches it (see ChainClient.AwaitAccountSequence)
+	OldSequence uint64
+	// GrantTxHash is the hash of the authz grants tx, empty if the grants were not requested
+	GrantTxHash string
+}
+
+// RotateSigningKey replaces the key signing the txs of the client, without restarting it. The key is swapped between
+// two broadcasts, so every tx is signed either with the old key or with the new key using the sequence of its account.
+// The txs already broadcasted with the old key are not affected. The msgs queued with QueueBroadcastMsg and not
+// broadcasted yet are signed with the new key, so they should have the new key as sender
+func (c *chainClient) RotateSigningKey(from string, options KeyRotationOptions) (*KeyRotation, error) {
+	if !c.canSign {
+		return nil, errors.New("the client can not sign transactions, it has no signing key to rotate")
+	}
+	if c.opts.TxSigner != nil {
+		return nil, errors.New("the client signs with a tx signer, its key is rotated by the signer")
+	}
+	kb := options.Keyring
+	if kb == nil {
+		kb = c.ctx.Keyring
+	}
+	record, err := keyringRecord(kb, from)
+	if err != nil {
+		return nil, err
+	}
+	newAddress, err := record.GetAddress()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the address of key %s", record.Name)
+	}
+	if options.GrantOrderManagement && !options.GrantExpiration.After(c.opts.Clock.Now()) {
+		return nil, errors.Errorf("the order management grants expiration %s is not in the future", options.GrantExpiration.UTC().Format(time.RFC3339))
+	}
+
+	c.syncMux.Lock()
+	defer c.syncMux.Unlock()
+
+	oldAddress := c.ctx.FromAddress
+	if newAddress.Equals(oldAddress) {
+		return nil, errors.Errorf("%s is already the signing key", newAddress.String())
+	}
+	accNum, accSeq, err := c.txFactory.AccountRetriever().GetAccountNumberSequence(c.ctx, newAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the account number and sequence of %s, the account must exist on chain", newAddress.String())
+	}
+
+	rotation := &KeyRotation{OldAddress: oldAddress, NewAddress: newAddress}
+	if options.GrantOrderManagement {
+		grants := make([]sdk.Msg, 0, len(KeyRotationGrantMsgTypes))
+		for _, msgType := range KeyRotationGrantMsgTypes {
+			grants = append(grants, c.BuildGenericAuthz(oldAddress.String(), newAddress.String(), msgType, options.GrantExpiration))
+		}
+		res, _, err := c.syncBroadcastMsg(c.txFactory.Memo(), grants...)
+		if err == nil && res.TxResponse != nil {
+			err = NewTxError(res.TxResponse)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to grant the order management to the new key")
+		}
+		rotation.GrantTxHash = res.TxResponse.TxHash
+	}
+	rotation.OldSequence = c.accSeq
+
+	c.ctx = c.ctx.WithKeyring(kb).WithFromAddress(newAddress).WithFromName(record.Name).WithFrom(record.Name)
+	c.txFactory = c.txFactory.WithKeybase(kb).WithAccountNumber(accNum).WithSequence(accSeq)
+	c.accNum = accNum
+	c.accSeq = accSeq
+
+	c.logger.WithField("old", oldAddress.String()).WithField("new", newAddress.String()).Infoln("rotated the signing key")
+	return rotation, nil
+}
+
+// AwaitAccountSequence waits until the sequence of the account on chain is at least sequence, e.g. to know when the
+// txs signed with a rotated key are all committed. It returns when the context is done, since txs evicted from the
+// mempool never increase the sequence
+func (c *chainClient) AwaitAccountSequence(ctx context.Context, address sdk.AccAddress, sequence uint64) error {
+	c.syncMux.Lock()
+	clientCtx, retriever := c.ctx, c.txFactory.AccountRetriever()
+	c.syncMux.Unlock()
+
+	for {
+		_, accSeq, err := retriever.GetAccountNumberSequence(clientCtx, address)
+		if err != nil {
+			c.logger.WithError(err).WithField("address", address.String()).Warningln("failed to get the account sequence")
+		} else if accSeq >= sequence {
+			return nil
+		}
+
+		select {
+		case <-c.opts.Clock.After(defaultAccountSequencePollInterval):
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "the sequence of %s did not reach %d", address.String(), sequence)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+// rotationAccountRetriever returns the account numbers and sequences of the test accounts, and increases the
+// sequence of an account every time it is read
+type rotationAccountRetriever struct {
+	fakeAccountRetriever
+	mux       sync.Mutex
+	accounts  map[string][2]uint64
+	increment map[string]bool
+}
+
+func (r *rotationAccountRetriever) GetAccountNumberSequence(_ client.Context, address sdk.AccAddress) (uint64, uint64, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	account, found := r.accounts[address.String()]
+	if !found {
+		return 0, 0, errors.Errorf("account %s not found", address.String())
+	}
+	if r.increment[address.String()] {
+		r.accounts[address.String()] = [2]uint64{account[0], account[1] + 1}
+	}
+	return account[0], account[1], nil
+}
+
+func TestRotateSigningKey(t *testing.T) {
+	dryRunLog := NewDryRunLog(0)
+	c, _, oldAddress := newDryRunClient(t, dryRunLog)
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.opts.Clock = fakeClock
+	key, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	newKeyring, err := KeyringForPrivKey("rotated", key)
+	assert.NoError(t, err)
+	newAddress := sdk.AccAddress(key.PubKey().Address())
+	retriever := &rotationAccountRetriever{accounts: map[string][2]uint64{oldAddress.String(): {3, 9}}, increment: map[string]bool{}}
+	c.txFactory = c.txFactory.WithAccountRetriever(retriever)
+
+	_, err = c.RotateSigningKey(oldAddress.String(), KeyRotationOptions{})
+	assert.EqualError(t, err, oldAddress.String()+" is already the signing key")
+	_, err = c.RotateSigningKey("unknown", KeyRotationOptions{})
+	assert.EqualError(t, err, "no key in keyring for name: unknown: unknown.info: key not found")
+	// the new account must exist on chain
+	_, err = c.RotateSigningKey(newAddress.String(), KeyRotationOptions{Keyring: newKeyring})
+	assert.EqualError(t, err, "failed to get the account number and sequence of "+newAddress.String()+", the account must exist on chain: account "+newAddress.String()+" not found")
+	_, err = c.RotateSigningKey("rotated", KeyRotationOptions{Keyring: newKeyring, GrantOrderManagement: true, GrantExpiration: fakeClock.Now()})
+	assert.Error(t, err)
+	assert.Equal(t, oldAddress, c.FromAddress())
+
+	retriever.accounts[newAddress.String()] = [2]uint64{7, 2}
+	rotation, err := c.RotateSigningKey("rotated", KeyRotationOptions{
+		Keyring:              newKeyring,
+		GrantOrderManagement: true,
+		GrantExpiration:      fakeClock.Now().Add(24 * time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, oldAddress, rotation.OldAddress)
+	assert.Equal(t, newAddress, rotation.NewAddress)
+	assert.Equal(t, uint64(9), rotation.OldSequence)
+	assert.NotEmpty(t, rotation.GrantTxHash)
+
+	// the grants were signed with the old key
+	txs := dryRunLog.Txs()
+	assert.Len(t, txs, 1)
+	assert.Equal(t, uint64(9), txs[0].Sequence)
+	assert.Len(t, txs[0].Msgs, len(KeyRotationGrantMsgTypes))
+	grant := txs[0].Msgs[0].(*authztypes.MsgGrant)
+	assert.Equal(t, oldAddress.String(), grant.Granter)
+	assert.Equal(t, newAddress.String(), grant.Grantee)
+
+	// the next txs are signed with the new key and its account sequence
+	assert.Equal(t, newAddress, c.FromAddress())
+	accNum, accSeq := c.GetAccNonce()
+	assert.Equal(t, uint64(7), accNum)
+	assert.Equal(t, uint64(2), accSeq)
+	msg := banktypes.NewMsgSend(newAddress, newAddress, sdk.NewCoins(sdk.NewInt64Coin("inj", 1)))
+	_, err = c.SyncBroadcastMsg(msg)
+	assert.NoError(t, err)
+	txs = dryRunLog.Txs()
+	assert.Len(t, txs, 2)
+	assert.Equal(t, uint64(2), txs[1].Sequence)
+	assert.NoError(t, txs[1].Err)
+}
+
+func TestAwaitAccountSequence(t *testing.T) {
+	c, _, address := newDryRunClient(t, NewDryRunLog(0))
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.opts.Clock = fakeClock
+	retriever := &rotationAccountRetriever{accounts: map[string][2]uint64{address.String(): {3, 9}}, increment: map[string]bool{address.String(): true}}
+	c.txFactory = c.txFactory.WithAccountRetriever(retriever)
+
+	assert.NoError(t, c.AwaitAccountSequence(context.Background(), address, 9))
+
+	done := make(chan error)
+	go func() {
+		done <- c.AwaitAccountSequence(context.Background(), address, 11)
+	}()
+	assert.NoError(t, fakeClock.BlockUntil(context.Background(), 1))
+	fakeClock.Advance(time.Second)
+	assert.NoError(t, <-done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.AwaitAccountSequence(ctx, address, 100)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
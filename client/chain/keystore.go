@@ -0,0 +1,245 @@
+package chain
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+)
+
+const (
+	// KeystoreVersion is the version of the Argon2id keystore files written by EncryptKeystore
+	KeystoreVersion = 1
+
+	keystoreKdf        = "argon2id"
+	keystoreCipher     = "aes-256-gcm"
+	keystoreKeyLength  = 32
+	keystoreSaltLength = 32
+	ethKeystoreVersion = 3
+
+	// maxArgon2Time and maxArgon2Memory bound the cost of the key derivation of the keystore files, which could
+	// otherwise make DecryptKeystore run for hours or allocate terabytes
+	maxArgon2Time   = 64
+	maxArgon2Memory = 4 * 1024 * 1024
+)
+
+var ErrKeystoreDecrypt = errors.New("could not decrypt the keystore with the passphrase")
+
+// Argon2Params are the cost parameters of the Argon2id key derivation
+type Argon2Params struct {
+	// Time is the number of passes over the memory, at most 64
+	Time uint32 `json:"time"`
+	// Memory is the memory used, in KiB, at most 4 GiB
+	Memory uint32 `json:"memory"`
+	// Threads is the degree of parallelism, at most 255
+	Threads uint8 `json:"threads"`
+}
+
+// DefaultArgon2Params returns the parameters recommended by RFC 9106 for memory constrained environments: 3 passes
+// over 64 MiB with 4 threads
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{Time: 3, Memory: 64 * 1024, Threads: 4}
+}
+
+func (p Argon2Params) validate() error {
+	if p.Time == 0 || p.Time > maxArgon2Time || p.Threads == 0 || p.Memory < 8*uint32(p.Threads) || p.Memory > maxArgon2Memory {
+		return errors.Errorf("invalid argon2id parameters: time %d, memory %d KiB, threads %d", p.Time, p.Memory, p.Threads)
+	}
+	return nil
+}
+
+type keystoreKdfParams struct {
+	Argon2Params
+	Salt   string `json:"salt"`
+	KeyLen int    `json:"keylen"`
+}
+
+type keystoreCipherParams struct {
+	Nonce string `json:"nonce"`
+}
+
+type keystoreCrypto struct {
+	Cipher       string               `json:"cipher"`
+	CipherText   string               `json:"ciphertext"`
+	CipherParams keystoreCipherParams `json:"cipherparams"`
+	Kdf          string               `json:"kdf"`
+	KdfParams    keystoreKdfParams    `json:"kdfparams"`
+}
+
+// keystoreFile has the layout of the Ethereum keystore v3 files, with an Argon2id key derivation and an AES-GCM
+// cipher authenticating the ciphertext instead of the scrypt/pbkdf2 derivation and the keccak MAC
+type keystoreFile struct {
+	Address string         `json:"address"`
+	Crypto  keystoreCrypto `json:"crypto"`
+	Id      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+// EncryptKeystore encrypts the eth_secp256k1 key with the passphrase, as a JSON keystore using Argon2id to derive
+// the AES-256-GCM key
+func EncryptKeystore(privKey *ethsecp256k1.PrivKey, passphrase string, params Argon2Params) ([]byte, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if len(privKey.Key) != ethsecp256k1.PrivKeySize {
+		return nil, errors.Errorf("invalid private key length %d", len(privKey.Key))
+	}
+
+	salt := make([]byte, keystoreSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate the keystore salt")
+	}
+	aead, err := keystoreAEAD(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate the keystore nonce")
+	}
+
+	file := keystoreFile{
+		Address: hex.EncodeToString(ethcrypto.PubkeyToAddress(privKey.ToECDSA().PublicKey).Bytes()),
+		Crypto: keystoreCrypto{
+			Cipher:       keystoreCipher,
+			CipherText:   hex.EncodeToString(aead.Seal(nil, nonce, privKey.Key, nil)),
+			CipherParams: keystoreCipherParams{Nonce: hex.EncodeToString(nonce)},
+			Kdf:          keystoreKdf,
+			KdfParams: keystoreKdfParams{
+				Argon2Params: params,
+				Salt:         hex.EncodeToString(salt),
+				KeyLen:       keystoreKeyLength,
+			},
+		},
+		Id:      uuid.New().String(),
+		Version: KeystoreVersion,
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// DecryptKeystore decrypts a keystore written by EncryptKeystore, or an Ethereum keystore v3 (e.g. exported by geth
+// or MetaMask). Wrong passphrases return ErrKeystoreDecrypt
+func DecryptKeystore(data []byte, passphrase string) (*ethsecp256k1.PrivKey, error) {
+	var file keystoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the keystore")
+	}
+
+	var key []byte
+	switch {
+	case file.Version == ethKeystoreVersion:
+		ethKey, err := ethkeystore.DecryptKey(data, passphrase)
+		if errors.Is(err, ethkeystore.ErrDecrypt) {
+			return nil, ErrKeystoreDecrypt
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt the v3 keystore")
+		}
+		key = ethcrypto.FromECDSA(ethKey.PrivateKey)
+	case file.Version == KeystoreVersion && file.Crypto.Kdf == keystoreKdf && file.Crypto.Cipher == keystoreCipher:
+		decrypted, err := decryptKeystoreFile(file, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		key = decrypted
+	default:
+		return nil, errors.Errorf("unsupported keystore version %d with kdf %q and cipher %q", file.Version, file.Crypto.Kdf, file.Crypto.Cipher)
+	}
+
+	privKey := &ethsecp256k1.PrivKey{Key: key}
+	address := hex.EncodeToString(ethcrypto.PubkeyToAddress(privKey.ToECDSA().PublicKey).Bytes())
+	if file.Address != "" && !strings.EqualFold(strings.TrimPrefix(file.Address, "0x"), address) {
+		return nil, errors.Errorf("the keystore key has address 0x%s instead of 0x%s", address, strings.TrimPrefix(file.Address, "0x"))
+	}
+	return privKey, nil
+}
+
+// ExportKeystoreV3 encrypts the key as an Ethereum keystore v3 (scrypt and AES-128-CTR), readable by the Ethereum
+// wallets. scryptN and scryptP are the scrypt costs, e.g. keystore.StandardScryptN and keystore.StandardScryptP from
+// go-ethereum
+func ExportKeystoreV3(privKey *ethsecp256k1.PrivKey, passphrase string, scryptN int, scryptP int) ([]byte, error) {
+	if len(privKey.Key) != ethsecp256k1.PrivKeySize {
+		return nil, errors.Errorf("invalid private key length %d", len(privKey.Key))
+	}
+	ecdsaKey := privKey.ToECDSA()
+	key := &ethkeystore.Key{
+		Id:         uuid.New(),
+		Address:    ethcrypto.PubkeyToAddress(ecdsaKey.PublicKey),
+		PrivateKey: ecdsaKey,
+	}
+	data, err := ethkeystore.EncryptKey(key, passphrase, scryptN, scryptP)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt the v3 keystore")
+	}
+	return data, nil
+}
+
+// KeyringForKeystore decrypts the keystore (see DecryptKeystore) into a temporary in-mem keyring with the key name,
+// and returns the address of the key
+func KeyringForKeystore(name string, data []byte, passphrase string) (sdk.AccAddress, keyring.Keyring, error) {
+	privKey, err := DecryptKeystore(data, passphrase)
+	if err != nil {
+		return emptyCosmosAddress, nil, err
+	}
+	kb, err := KeyringForPrivKey(name, privKey)
+	if err != nil {
+		return emptyCosmosAddress, nil, err
+	}
+	return sdk.AccAddress(privKey.PubKey().Address().Bytes()), kb, nil
+}
+
+func decryptKeystoreFile(file keystoreFile, passphrase string) ([]byte, error) {
+	params := file.Crypto.KdfParams
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if params.KeyLen != keystoreKeyLength {
+		return nil, errors.Errorf("unsupported keystore key length %d", params.KeyLen)
+	}
+	salt, saltErr := hex.DecodeString(params.Salt)
+	nonce, nonceErr := hex.DecodeString(file.Crypto.CipherParams.Nonce)
+	cipherText, cipherTextErr := hex.DecodeString(file.Crypto.CipherText)
+	if saltErr != nil || nonceErr != nil || cipherTextErr != nil {
+		return nil, errors.New("the keystore salt, nonce or ciphertext is not valid hex")
+	}
+
+	aead, err := keystoreAEAD(passphrase, salt, params.Argon2Params)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.Errorf("invalid keystore nonce length %d", len(nonce))
+	}
+	key, err := aead.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, ErrKeystoreDecrypt
+	}
+	if len(key) != ethsecp256k1.PrivKeySize {
+		return nil, errors.Errorf("invalid private key length %d", len(key))
+	}
+	return key, nil
+}
+
+func keystoreAEAD(passphrase string, salt []byte, params Argon2Params) (cipher.AEAD, error) {
+	derivedKey := argon2.IDKey([]byte(passphrase), salt, params.Time, params.Memory, params.Threads, keystoreKeyLength)
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the keystore cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the keystore cipher")
+	}
+	return aead, nil
+}
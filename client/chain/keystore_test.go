@@ -0,0 +1,104 @@
+package chain
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+)
+
+var testArgon2Params = Argon2Params{Time: 1, Memory: 64, Threads: 1}
+
+func TestKeystore(t *testing.T) {
+	key, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+
+	data, err := EncryptKeystore(key, "passphrase", testArgon2Params)
+	assert.NoError(t, err)
+	var file map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &file))
+	assert.Equal(t, float64(KeystoreVersion), file["version"])
+	assert.Equal(t, "argon2id", file["crypto"].(map[string]interface{})["kdf"])
+	assert.NotContains(t, string(data), string(key.Key))
+
+	decrypted, err := DecryptKeystore(data, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, key.Key, decrypted.Key)
+	_, err = DecryptKeystore(data, "wrong")
+	assert.True(t, errors.Is(err, ErrKeystoreDecrypt))
+
+	address, kb, err := KeyringForKeystore("bot", data, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, sdk.AccAddress(key.PubKey().Address()), address)
+	record, err := kb.Key("bot")
+	assert.NoError(t, err)
+	recordAddress, err := record.GetAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, address, recordAddress)
+
+	_, err = EncryptKeystore(key, "passphrase", Argon2Params{Time: 1, Memory: 4, Threads: 1})
+	assert.EqualError(t, err, "invalid argon2id parameters: time 1, memory 4 KiB, threads 1")
+}
+
+func TestDecryptKeystoreRejectsOversizedParams(t *testing.T) {
+	key, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	data, err := EncryptKeystore(key, "passphrase", testArgon2Params)
+	assert.NoError(t, err)
+
+	withKdfParam := func(name string, value interface{}) []byte {
+		var file map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &file))
+		file["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})[name] = value
+		modified, err := json.Marshal(file)
+		assert.NoError(t, err)
+		return modified
+	}
+
+	_, err = DecryptKeystore(withKdfParam("memory", 4*1024*1024+1), "passphrase")
+	assert.EqualError(t, err, "invalid argon2id parameters: time 1, memory 4194305 KiB, threads 1")
+	_, err = DecryptKeystore(withKdfParam("time", 1000000), "passphrase")
+	assert.EqualError(t, err, "invalid argon2id parameters: time 1000000, memory 64 KiB, threads 1")
+	_, err = DecryptKeystore(withKdfParam("threads", 256), "passphrase")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrKeystoreDecrypt))
+}
+
+func TestKeystoreV3(t *testing.T) {
+	key, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+
+	data, err := ExportKeystoreV3(key, "passphrase", ethkeystore.LightScryptN, ethkeystore.LightScryptP)
+	assert.NoError(t, err)
+	ethKey, err := ethkeystore.DecryptKey(data, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, key.ToECDSA().D, ethKey.PrivateKey.D)
+
+	decrypted, err := DecryptKeystore(data, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, key.Key, decrypted.Key)
+	_, err = DecryptKeystore(data, "wrong")
+	assert.True(t, errors.Is(err, ErrKeystoreDecrypt))
+
+	// a v3 keystore with the address of another key is rejected
+	other, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	otherData, err := ExportKeystoreV3(other, "passphrase", ethkeystore.LightScryptN, ethkeystore.LightScryptP)
+	assert.NoError(t, err)
+	var file, otherFile map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &file))
+	assert.NoError(t, json.Unmarshal(otherData, &otherFile))
+	file["address"] = otherFile["address"]
+	tampered, err := json.Marshal(file)
+	assert.NoError(t, err)
+	_, err = DecryptKeystore(tampered, "passphrase")
+	assert.Error(t, err)
+
+	_, err = DecryptKeystore([]byte(`{"version": 2}`), "passphrase")
+	assert.EqualError(t, err, `unsupported keystore version 2 with kdf "" and cipher ""`)
+}
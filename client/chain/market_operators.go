@@ -0,0 +1,124 @@
+package chain
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+var ErrUnauthorizedMarketOperation = errors.New("the sender is not allowed to operate the market")
+
+// MarketOperatorRole is the reason a sender is allowed to change a market
+type MarketOperatorRole string
+
+const (
+	// MarketOperatorGovernance is the gov module account, changing the markets through proposals
+	MarketOperatorGovernance MarketOperatorRole = "governance"
+	// MarketOperatorAdmin is the admin designated when a binary options market is launched
+	MarketOperatorAdmin MarketOperatorRole = "admin"
+)
+
+// MarketOperatorQuerier has the queries used to check the market permissions. ChainClient implements it
+type MarketOperatorQuerier interface {
+	FetchChainBinaryOptionsMarkets(ctx context.Context, status string) (*exchangetypes.QueryBinaryMarketsResponse, error)
+	FetchExchangeParams(ctx context.Context) (*exchangetypes.QueryExchangeParamsResponse, error)
+}
+
+// GovernanceAuthority returns the address of the gov module account, the authority of the exchange msgs reserved to
+// governance (e.g. MsgUpdateParams)
+func GovernanceAuthority() string {
+	return authtypes.NewModuleAddress(govtypes.ModuleName).String()
+}
+
+// MarketOperators are the accounts allowed to suspend, resume or settle a market
+type MarketOperators struct {
+	MarketId   string
+	Governance string
+	// Admin is the admin of a binary options market. Spot and derivative markets have no admin and can only be
+	// changed by governance
+	Admin string
+}
+
+// FetchMarketOperators returns the operators of the market. Markets that are not binary options markets are not
+// queried, since only governance can change them
+func FetchMarketOperators(ctx context.Context, querier MarketOperatorQuerier, marketId string) (MarketOperators, error) {
+	operators := MarketOperators{MarketId: marketId, Governance: GovernanceAuthority()}
+
+	market, found, err := fetchBinaryOptionsMarket(ctx, querier, marketId)
+	if err != nil {
+		return MarketOperators{}, err
+	}
+	if found {
+		operators.Admin = market.Admin
+	}
+	return operators, nil
+}
+
+// Role returns the role allowing the sender to operate the market, or ErrUnauthorizedMarketOperation
+func (o MarketOperators) Role(sender string) (MarketOperatorRole, error) {
+	switch {
+	case sender == o.Governance:
+		return MarketOperatorGovernance, nil
+	case o.Admin != "" && sender == o.Admin:
+		return MarketOperatorAdmin, nil
+	case o.Admin != "":
+		return "", errors.Wrapf(ErrUnauthorizedMarketOperation, "%s is not the admin (%s) of market %s", sender, o.Admin, o.MarketId)
+	default:
+		return "", errors.Wrapf(ErrUnauthorizedMarketOperation, "market %s can only be changed through a governance proposal, not by %s", o.MarketId, sender)
+	}
+}
+
+// PreflightMarketMsg checks that the signer of a permissioned exchange msg is allowed to send it, so the msg is not
+// rejected by the chain after paying the fees:
+//   - MsgAdminUpdateBinaryOptionsMarket (settling, demolishing or changing a market) must be sent by the market admin
+//   - MsgUpdateParams must have the governance authority
+//   - the instant launches of perpetual and expiry futures markets must be enabled in the exchange params, otherwise
+//     the markets have to be launched by governance
+//
+// Spot and binary options markets can be launched by anyone paying the listing fee. Other msgs are not checked
+func PreflightMarketMsg(ctx context.Context, querier MarketOperatorQuerier, msg sdk.Msg) error {
+	switch msg := msg.(type) {
+	case *exchangetypes.MsgAdminUpdateBinaryOptionsMarket:
+		market, found, err := fetchBinaryOptionsMarket(ctx, querier, msg.MarketId)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.Wrapf(ErrMarketNotFound, "binary options market %s", msg.MarketId)
+		}
+		if msg.Sender != market.Admin {
+			return errors.Wrapf(ErrUnauthorizedMarketOperation, "%s is not the admin (%s) of market %s", msg.Sender, market.Admin, msg.MarketId)
+		}
+	case *exchangetypes.MsgUpdateParams:
+		if authority := GovernanceAuthority(); msg.Authority != authority {
+			return errors.Wrapf(ErrUnauthorizedMarketOperation, "the exchange params can only be updated by the governance authority %s, not by %s", authority, msg.Authority)
+		}
+	case *exchangetypes.MsgInstantPerpetualMarketLaunch, *exchangetypes.MsgInstantExpiryFuturesMarketLaunch:
+		res, err := querier.FetchExchangeParams(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch the exchange params")
+		}
+		if !res.Params.IsInstantDerivativeMarketLaunchEnabled {
+			return errors.Wrap(ErrUnauthorizedMarketOperation, "the instant derivative market launches are disabled, the market has to be launched through a governance proposal")
+		}
+	}
+	return nil
+}
+
+func fetchBinaryOptionsMarket(ctx context.Context, querier MarketOperatorQuerier, marketId string) (*exchangetypes.BinaryOptionsMarket, bool, error) {
+	res, err := querier.FetchChainBinaryOptionsMarkets(ctx, "")
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to fetch the binary options markets")
+	}
+	for _, market := range res.Markets {
+		if market.MarketId == marketId {
+			return market, true, nil
+		}
+	}
+	return nil, false, nil
+}
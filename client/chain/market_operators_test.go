@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+const (
+	operatorsTestBinaryMarketId = "0x230dcce315364ff6360097838701b14713e2f4007d704df20ed3d81d09eec957"
+	operatorsTestAdmin          = "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r"
+	operatorsTestSender         = "inj1cml96vmptgw99syqrrz8az79xer2pcgp0a885r"
+)
+
+type operatorsTestQuerier struct {
+	params exchangetypes.Params
+	err    error
+}
+
+func (q *operatorsTestQuerier) FetchChainBinaryOptionsMarkets(context.Context, string) (*exchangetypes.QueryBinaryMarketsResponse, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	return &exchangetypes.QueryBinaryMarketsResponse{Markets: []*exchangetypes.BinaryOptionsMarket{
+		{MarketId: operatorsTestBinaryMarketId, Admin: operatorsTestAdmin, Status: exchangetypes.MarketStatus_Active},
+	}}, nil
+}
+
+func (q *operatorsTestQuerier) FetchExchangeParams(context.Context) (*exchangetypes.QueryExchangeParamsResponse, error) {
+	return &exchangetypes.QueryExchangeParamsResponse{Params: q.params}, nil
+}
+
+func TestMarketOperatorsRoles(t *testing.T) {
+	querier := &operatorsTestQuerier{}
+	governance := GovernanceAuthority()
+	assert.Equal(t, "inj10d07y265gmmuvt4z0w9aw880jnsr700jstypyt", governance)
+
+	operators, err := FetchMarketOperators(context.Background(), querier, operatorsTestBinaryMarketId)
+	assert.NoError(t, err)
+	assert.Equal(t, operatorsTestAdmin, operators.Admin)
+	role, err := operators.Role(operatorsTestAdmin)
+	assert.NoError(t, err)
+	assert.Equal(t, MarketOperatorAdmin, role)
+	role, err = operators.Role(governance)
+	assert.NoError(t, err)
+	assert.Equal(t, MarketOperatorGovernance, role)
+	_, err = operators.Role(operatorsTestSender)
+	assert.True(t, errors.Is(err, ErrUnauthorizedMarketOperation))
+
+	// spot and derivative markets have no admin
+	operators, err = FetchMarketOperators(context.Background(), querier, arbitrageSpotMarketId)
+	assert.NoError(t, err)
+	assert.Empty(t, operators.Admin)
+	_, err = operators.Role(operatorsTestAdmin)
+	assert.EqualError(t, err, "market "+arbitrageSpotMarketId+" can only be changed through a governance proposal, not by "+operatorsTestAdmin+": the sender is not allowed to operate the market")
+
+	querier.err = errors.New("connection refused")
+	_, err = FetchMarketOperators(context.Background(), querier, operatorsTestBinaryMarketId)
+	assert.EqualError(t, err, "failed to fetch the binary options markets: connection refused")
+}
+
+func TestPreflightMarketMsg(t *testing.T) {
+	querier := &operatorsTestQuerier{}
+	ctx := context.Background()
+
+	settlementPrice := sdk.NewDec(1)
+	adminMsg := &exchangetypes.MsgAdminUpdateBinaryOptionsMarket{
+		Sender:          operatorsTestAdmin,
+		MarketId:        operatorsTestBinaryMarketId,
+		SettlementPrice: &settlementPrice,
+		Status:          exchangetypes.MarketStatus_Demolished,
+	}
+	assert.NoError(t, PreflightMarketMsg(ctx, querier, adminMsg))
+	adminMsg.Sender = operatorsTestSender
+	assert.True(t, errors.Is(PreflightMarketMsg(ctx, querier, adminMsg), ErrUnauthorizedMarketOperation))
+	adminMsg.MarketId = arbitrageSpotMarketId
+	assert.True(t, errors.Is(PreflightMarketMsg(ctx, querier, adminMsg), ErrMarketNotFound))
+
+	paramsMsg := &exchangetypes.MsgUpdateParams{Authority: operatorsTestSender}
+	assert.True(t, errors.Is(PreflightMarketMsg(ctx, querier, paramsMsg), ErrUnauthorizedMarketOperation))
+	paramsMsg.Authority = GovernanceAuthority()
+	assert.NoError(t, PreflightMarketMsg(ctx, querier, paramsMsg))
+
+	launchMsg := &exchangetypes.MsgInstantPerpetualMarketLaunch{Sender: operatorsTestSender}
+	assert.True(t, errors.Is(PreflightMarketMsg(ctx, querier, launchMsg), ErrUnauthorizedMarketOperation))
+	querier.params.IsInstantDerivativeMarketLaunchEnabled = true
+	assert.NoError(t, PreflightMarketMsg(ctx, querier, launchMsg))
+	assert.NoError(t, PreflightMarketMsg(ctx, querier, &exchangetypes.MsgInstantExpiryFuturesMarketLaunch{Sender: operatorsTestSender}))
+
+	// spot markets are launched by anyone paying the listing fee
+	assert.NoError(t, PreflightMarketMsg(ctx, querier, &exchangetypes.MsgInstantSpotMarketLaunch{Sender: operatorsTestSender}))
+}
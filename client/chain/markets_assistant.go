@@ -138,7 +138,7 @@ func NewMarketsAssistant(networkName string) (MarketsAssistant, error) {
 	return legacyMarketAssistant, nil
 }
 
-func NewMarketsAssistantInitializedFromChain(ctx context.Context, exchangeClient exchange.ExchangeClient) (MarketsAssistant, error) {
+func NewMarketsAssistantInitializedFromChain(ctx context.Context, exchangeClient exchange.MarketQuerier) (MarketsAssistant, error) {
 	assistant := newMarketsAssistant()
 	spotMarketsRequest := spotExchangePB.MarketsRequest{
 		MarketStatus: "active",
@@ -225,6 +225,7 @@ func NewMarketsAssistantInitializedFromChain(ctx context.Context, exchangeClient
 				ServiceProviderFee:     serviceProviderFee,
 				MinPriceTickSize:       minPriceTickSize,
 				MinQuantityTickSize:    minQuantityTickSize,
+				ExpirationTimestamp:    marketInfo.GetExpiryFuturesMarketInfo().GetExpirationTimestamp(),
 			}
 
 			assistant.derivativeMarkets[market.Id] = market
@@ -328,6 +329,12 @@ func (assistant MarketsAssistant) AllDerivativeMarkets() map[string]core.Derivat
 	return assistant.derivativeMarkets
 }
 
+// DerivativeMarketSnapshots returns a new cache with the snapshots of the derivative markets. Share the cache between
+// the goroutines updating the index prices and the ones validating orders, instead of the AllDerivativeMarkets map
+func (assistant MarketsAssistant) DerivativeMarketSnapshots() *core.MarketSnapshotCache {
+	return core.NewMarketSnapshotCache(assistant.derivativeMarkets)
+}
+
 func (assistant MarketsAssistant) initializeTokensFromChainDenoms(ctx context.Context, chainClient ChainClient) {
 	var denomsMetadata []banktypes.Metadata
 	var nextKey []byte
@@ -0,0 +1,332 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const (
+	defaultMempoolPollInterval = 200 * time.Millisecond
+	// CometBFT returns at most 100 txs per unconfirmed_txs request, and the endpoint has no pagination
+	mempoolUnconfirmedTxsLimit = 100
+)
+
+type PendingOrderAction int
+
+const (
+	PendingOrderCreation PendingOrderAction = iota
+	PendingOrderCancellation
+)
+
+type PendingOrderMarketType string
+
+const (
+	PendingSpotOrder          PendingOrderMarketType = "spot"
+	PendingDerivativeOrder    PendingOrderMarketType = "derivative"
+	PendingBinaryOptionsOrder PendingOrderMarketType = "binary_options"
+)
+
+// PendingOrderEvent is an order creation or cancellation found in a tx that is still in the mempool.
+// Only one of SpotOrder, DerivativeOrder or Cancellation is set
+type PendingOrderEvent struct {
+	Action     PendingOrderAction
+	MarketType PendingOrderMarketType
+	TxHash     string
+	Sender     string
+	// MsgType is the type URL of the exchange message including the order
+	MsgType       string
+	IsMarketOrder bool
+	// CancelAll is set for cancellation of all the subaccount orders in the market (MsgBatchUpdateOrders)
+	CancelAll       bool
+	SpotOrder       *exchangetypes.SpotOrder
+	DerivativeOrder *exchangetypes.DerivativeOrder
+	Cancellation    *exchangetypes.OrderData
+	SeenAt          time.Time
+}
+
+// MempoolWatcher polls the node mempool for unconfirmed txs and emits the exchange orders they include,
+// before they are included in a block. Each tx is reported only once while it stays in the mempool.
+// The unconfirmed_txs endpoint only returns the first txs of the mempool (at most 100, see SetTxsLimit), so on a busy
+// mempool the txs after them are reported once the txs ahead are included in a block. MissedTxs tells how many txs
+// were not fetched in the last poll
+type MempoolWatcher struct {
+	rpcClient    rpcclient.MempoolClient
+	pollInterval time.Duration
+	txsLimit     int
+	clock        clock.Clock
+	logger       log.Logger
+	seenTxs      map[string]struct{}
+	missedTxs    int
+}
+
+// NewMempoolWatcher creates a watcher using the given Tendermint RPC client (e.g. rpchttp.HTTP).
+// A zero pollInterval uses the default interval
+func NewMempoolWatcher(rpcClient rpcclient.MempoolClient, pollInterval time.Duration) *MempoolWatcher {
+	if pollInterval <= 0 {
+		pollInterval = defaultMempoolPollInterval
+	}
+
+	return &MempoolWatcher{
+		rpcClient:    rpcClient,
+		pollInterval: pollInterval,
+		txsLimit:     mempoolUnconfirmedTxsLimit,
+		clock:        clock.Real(),
+		logger:       log.WithField("module", "mempool-watcher"),
+		seenTxs:      make(map[string]struct{}),
+	}
+}
+
+// SetTxsLimit sets the number of unconfirmed txs requested on every poll. Nodes cap it at 100
+func (w *MempoolWatcher) SetTxsLimit(limit int) {
+	if limit > 0 {
+		w.txsLimit = limit
+	}
+}
+
+// SetClock sets the clock of the polls and of the times the txs are seen, before calling Watch
+func (w *MempoolWatcher) SetClock(c clock.Clock) {
+	w.clock = clock.OrReal(c)
+}
+
+// MissedTxs returns the number of mempool txs that were not fetched in the last poll because of the txs limit
+func (w *MempoolWatcher) MissedTxs() int {
+	return w.missedTxs
+}
+
+// Watch sends the pending order events to eventCh until the context is done.
+// RPC and decoding errors are logged and do not stop the watcher
+func (w *MempoolWatcher) Watch(ctx context.Context, eventCh chan<- PendingOrderEvent) {
+	ticker := w.clock.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		events, err := w.Poll(ctx)
+		if err != nil {
+			w.logger.WithError(err).Warningln("failed to poll unconfirmed txs")
+		}
+
+		for _, event := range events {
+			select {
+			case eventCh <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Poll fetches the current unconfirmed txs and returns the events of the txs not reported before
+func (w *MempoolWatcher) Poll(ctx context.Context) ([]PendingOrderEvent, error) {
+	limit := w.txsLimit
+	res, err := w.rpcClient.UnconfirmedTxs(ctx, &limit)
+	if err != nil {
+		return nil, err
+	}
+
+	w.missedTxs = 0
+	if res.Total > len(res.Txs) {
+		w.missedTxs = res.Total - len(res.Txs)
+		w.logger.WithField("missedTxs", w.missedTxs).Debugln("the mempool has more txs than the unconfirmed txs limit")
+	}
+
+	seenAt := w.clock.Now()
+	currentTxs := make(map[string]struct{}, len(res.Txs))
+	var events []PendingOrderEvent
+
+	for _, tx := range res.Txs {
+		txHash := fmt.Sprintf("%X", tx.Hash())
+		currentTxs[txHash] = struct{}{}
+		if _, found := w.seenTxs[txHash]; found {
+			continue
+		}
+
+		decodedTx, err := DecodeTx(tx)
+		if err != nil {
+			w.logger.WithError(err).WithField("txHash", txHash).Debugln("failed to decode unconfirmed tx")
+			continue
+		}
+
+		events = append(events, ExtractPendingOrderEvents(txHash, decodedTx.Msgs, seenAt)...)
+	}
+
+	// only the txs still in the mempool are remembered, to keep the memory bounded
+	w.seenTxs = currentTxs
+
+	return events, nil
+}
+
+// ExtractPendingOrderEvents returns the order creations and cancellations included in the messages,
+// including the ones executed through authz MsgExec
+func ExtractPendingOrderEvents(txHash string, msgs []sdk.Msg, seenAt time.Time) []PendingOrderEvent {
+	var events []PendingOrderEvent
+
+	for _, msg := range msgs {
+		base := PendingOrderEvent{
+			TxHash:  txHash,
+			MsgType: sdk.MsgTypeURL(msg),
+			SeenAt:  seenAt,
+		}
+
+		switch m := msg.(type) {
+		case *authztypes.MsgExec:
+			innerMsgs, err := m.GetMessages()
+			if err != nil {
+				continue
+			}
+			events = append(events, ExtractPendingOrderEvents(txHash, innerMsgs, seenAt)...)
+
+		case *exchangetypes.MsgCreateSpotLimitOrder:
+			events = append(events, newPendingSpotOrderEvent(base, m.Sender, m.Order, false))
+		case *exchangetypes.MsgCreateSpotMarketOrder:
+			events = append(events, newPendingSpotOrderEvent(base, m.Sender, m.Order, true))
+		case *exchangetypes.MsgBatchCreateSpotLimitOrders:
+			for _, order := range m.Orders {
+				events = append(events, newPendingSpotOrderEvent(base, m.Sender, order, false))
+			}
+
+		case *exchangetypes.MsgCreateDerivativeLimitOrder:
+			events = append(events, newPendingDerivativeOrderEvent(base, PendingDerivativeOrder, m.Sender, m.Order, false))
+		case *exchangetypes.MsgCreateDerivativeMarketOrder:
+			events = append(events, newPendingDerivativeOrderEvent(base, PendingDerivativeOrder, m.Sender, m.Order, true))
+		case *exchangetypes.MsgBatchCreateDerivativeLimitOrders:
+			for _, order := range m.Orders {
+				events = append(events, newPendingDerivativeOrderEvent(base, PendingDerivativeOrder, m.Sender, order, false))
+			}
+		case *exchangetypes.MsgCreateBinaryOptionsLimitOrder:
+			events = append(events, newPendingDerivativeOrderEvent(base, PendingBinaryOptionsOrder, m.Sender, m.Order, false))
+		case *exchangetypes.MsgCreateBinaryOptionsMarketOrder:
+			events = append(events, newPendingDerivativeOrderEvent(base, PendingBinaryOptionsOrder, m.Sender, m.Order, true))
+
+		case *exchangetypes.MsgCancelSpotOrder:
+			events = append(events, newPendingCancellationEvent(base, PendingSpotOrder, m.Sender, exchangetypes.OrderData{
+				MarketId:     m.MarketId,
+				SubaccountId: m.SubaccountId,
+				OrderHash:    m.OrderHash,
+				Cid:          m.Cid,
+			}, false))
+		case *exchangetypes.MsgBatchCancelSpotOrders:
+			for _, data := range m.Data {
+				events = append(events, newPendingCancellationEvent(base, PendingSpotOrder, m.Sender, data, false))
+			}
+		case *exchangetypes.MsgCancelDerivativeOrder:
+			events = append(events, newPendingCancellationEvent(base, PendingDerivativeOrder, m.Sender, exchangetypes.OrderData{
+				MarketId:     m.MarketId,
+				SubaccountId: m.SubaccountId,
+				OrderHash:    m.OrderHash,
+				OrderMask:    m.OrderMask,
+				Cid:          m.Cid,
+			}, false))
+		case *exchangetypes.MsgBatchCancelDerivativeOrders:
+			for _, data := range m.Data {
+				events = append(events, newPendingCancellationEvent(base, PendingDerivativeOrder, m.Sender, data, false))
+			}
+		case *exchangetypes.MsgCancelBinaryOptionsOrder:
+			events = append(events, newPendingCancellationEvent(base, PendingBinaryOptionsOrder, m.Sender, exchangetypes.OrderData{
+				MarketId:     m.MarketId,
+				SubaccountId: m.SubaccountId,
+				OrderHash:    m.OrderHash,
+				OrderMask:    m.OrderMask,
+				Cid:          m.Cid,
+			}, false))
+		case *exchangetypes.MsgBatchCancelBinaryOptionsOrders:
+			for _, data := range m.Data {
+				events = append(events, newPendingCancellationEvent(base, PendingBinaryOptionsOrder, m.Sender, data, false))
+			}
+
+		case *exchangetypes.MsgBatchUpdateOrders:
+			events = append(events, extractBatchUpdateEvents(base, m)...)
+		}
+	}
+
+	return events
+}
+
+func extractBatchUpdateEvents(base PendingOrderEvent, msg *exchangetypes.MsgBatchUpdateOrders) []PendingOrderEvent {
+	var events []PendingOrderEvent
+
+	cancelAll := func(marketType PendingOrderMarketType, marketIds []string) {
+		for _, marketId := range marketIds {
+			events = append(events, newPendingCancellationEvent(base, marketType, msg.Sender, exchangetypes.OrderData{
+				MarketId:     marketId,
+				SubaccountId: msg.SubaccountId,
+			}, true))
+		}
+	}
+	cancelOrders := func(marketType PendingOrderMarketType, orders []*exchangetypes.OrderData) {
+		for _, data := range orders {
+			if data != nil {
+				events = append(events, newPendingCancellationEvent(base, marketType, msg.Sender, *data, false))
+			}
+		}
+	}
+	createDerivativeOrders := func(marketType PendingOrderMarketType, orders []*exchangetypes.DerivativeOrder) {
+		for _, order := range orders {
+			if order != nil {
+				events = append(events, newPendingDerivativeOrderEvent(base, marketType, msg.Sender, *order, false))
+			}
+		}
+	}
+
+	// the chain processes the cancellations before the creations
+	cancelAll(PendingSpotOrder, msg.SpotMarketIdsToCancelAll)
+	cancelAll(PendingDerivativeOrder, msg.DerivativeMarketIdsToCancelAll)
+	cancelAll(PendingBinaryOptionsOrder, msg.BinaryOptionsMarketIdsToCancelAll)
+	cancelOrders(PendingSpotOrder, msg.SpotOrdersToCancel)
+	cancelOrders(PendingDerivativeOrder, msg.DerivativeOrdersToCancel)
+	cancelOrders(PendingBinaryOptionsOrder, msg.BinaryOptionsOrdersToCancel)
+
+	for _, order := range msg.SpotOrdersToCreate {
+		if order != nil {
+			events = append(events, newPendingSpotOrderEvent(base, msg.Sender, *order, false))
+		}
+	}
+	createDerivativeOrders(PendingDerivativeOrder, msg.DerivativeOrdersToCreate)
+	createDerivativeOrders(PendingBinaryOptionsOrder, msg.BinaryOptionsOrdersToCreate)
+
+	return events
+}
+
+func newPendingSpotOrderEvent(base PendingOrderEvent, sender string, order exchangetypes.SpotOrder, isMarketOrder bool) PendingOrderEvent {
+	event := base
+	event.Action = PendingOrderCreation
+	event.MarketType = PendingSpotOrder
+	event.Sender = sender
+	event.IsMarketOrder = isMarketOrder
+	event.SpotOrder = &order
+	return event
+}
+
+func newPendingDerivativeOrderEvent(base PendingOrderEvent, marketType PendingOrderMarketType, sender string, order exchangetypes.DerivativeOrder, isMarketOrder bool) PendingOrderEvent {
+	event := base
+	event.Action = PendingOrderCreation
+	event.MarketType = marketType
+	event.Sender = sender
+	event.IsMarketOrder = isMarketOrder
+	event.DerivativeOrder = &order
+	return event
+}
+
+func newPendingCancellationEvent(base PendingOrderEvent, marketType PendingOrderMarketType, sender string, data exchangetypes.OrderData, cancelAll bool) PendingOrderEvent {
+	event := base
+	event.Action = PendingOrderCancellation
+	event.MarketType = marketType
+	event.Sender = sender
+	event.CancelAll = cancelAll
+	event.Cancellation = &data
+	return event
+}
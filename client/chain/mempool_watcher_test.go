@@ -0,0 +1,168 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+type fakeMempoolClient struct {
+	txs []cmttypes.Tx
+}
+
+func (c *fakeMempoolClient) UnconfirmedTxs(ctx context.Context, limit *int) (*ctypes.ResultUnconfirmedTxs, error) {
+	txs := c.txs
+	if limit != nil && *limit < len(txs) {
+		txs = txs[:*limit]
+	}
+	return &ctypes.ResultUnconfirmedTxs{Count: len(txs), Total: len(c.txs), Txs: txs}, nil
+}
+
+func (c *fakeMempoolClient) NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUnconfirmedTxs, error) {
+	return &ctypes.ResultUnconfirmedTxs{Count: len(c.txs), Total: len(c.txs)}, nil
+}
+
+func (c *fakeMempoolClient) CheckTx(ctx context.Context, tx cmttypes.Tx) (*ctypes.ResultCheckTx, error) {
+	return &ctypes.ResultCheckTx{}, nil
+}
+
+func encodeTestTx(t *testing.T, msgs ...sdk.Msg) cmttypes.Tx {
+	txConfig := txDecoderConfig()
+	builder := txConfig.NewTxBuilder()
+	assert.NoError(t, builder.SetMsgs(msgs...))
+
+	txBytes, err := txConfig.TxEncoder()(builder.GetTx())
+	assert.NoError(t, err)
+
+	return txBytes
+}
+
+func testSpotOrder() exchangetypes.SpotOrder {
+	return exchangetypes.SpotOrder{
+		MarketId: "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe",
+		OrderInfo: exchangetypes.OrderInfo{
+			SubaccountId: "0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000",
+			Price:        sdk.MustNewDecFromStr("0.000000000012"),
+			Quantity:     sdk.MustNewDecFromStr("1000000000000000000"),
+		},
+		OrderType: exchangetypes.OrderType_BUY,
+	}
+}
+
+func TestMempoolWatcherReportsEachTxOnce(t *testing.T) {
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	order := testSpotOrder()
+	client := &fakeMempoolClient{
+		txs: []cmttypes.Tx{encodeTestTx(t, &exchangetypes.MsgCreateSpotLimitOrder{Sender: sender, Order: order})},
+	}
+	watcher := NewMempoolWatcher(client, time.Millisecond)
+
+	events, err := watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, PendingOrderCreation, events[0].Action)
+	assert.Equal(t, PendingSpotOrder, events[0].MarketType)
+	assert.Equal(t, sender, events[0].Sender)
+	assert.Equal(t, order.MarketId, events[0].SpotOrder.MarketId)
+	assert.Equal(t, "/injective.exchange.v1beta1.MsgCreateSpotLimitOrder", events[0].MsgType)
+
+	events, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestMempoolWatcherReportsMissedTxs(t *testing.T) {
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	first := encodeTestTx(t, &exchangetypes.MsgCreateSpotLimitOrder{Sender: sender, Order: testSpotOrder()})
+	second := encodeTestTx(t, &exchangetypes.MsgCancelSpotOrder{Sender: sender, MarketId: testSpotOrder().MarketId})
+	client := &fakeMempoolClient{txs: []cmttypes.Tx{first, second}}
+	watcher := NewMempoolWatcher(client, time.Millisecond)
+	watcher.SetTxsLimit(1)
+
+	events, err := watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, 1, watcher.MissedTxs())
+
+	// the second tx is reported once the first one leaves the mempool
+	client.txs = client.txs[1:]
+	events, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, PendingOrderCancellation, events[0].Action)
+	assert.Equal(t, 0, watcher.MissedTxs())
+}
+
+func TestMempoolWatcherIgnoresUndecodableTxs(t *testing.T) {
+	client := &fakeMempoolClient{txs: []cmttypes.Tx{[]byte("garbage")}}
+	watcher := NewMempoolWatcher(client, time.Millisecond)
+
+	events, err := watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestExtractPendingOrderEventsFromBatchUpdateAndAuthz(t *testing.T) {
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	grantee := "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r"
+	order := testSpotOrder()
+	batchUpdate := &exchangetypes.MsgBatchUpdateOrders{
+		Sender:                         sender,
+		SubaccountId:                   order.OrderInfo.SubaccountId,
+		DerivativeMarketIdsToCancelAll: []string{"0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"},
+		SpotOrdersToCancel: []*exchangetypes.OrderData{{
+			MarketId:     order.MarketId,
+			SubaccountId: order.OrderInfo.SubaccountId,
+			OrderHash:    "0x1a2b",
+		}},
+		SpotOrdersToCreate: []*exchangetypes.SpotOrder{&order},
+	}
+	exec := authztypes.NewMsgExec(sdk.MustAccAddressFromBech32(grantee), []sdk.Msg{batchUpdate})
+
+	tx := encodeTestTx(t, &exec)
+	decodedTx, err := DecodeTx(tx)
+	assert.NoError(t, err)
+
+	events := ExtractPendingOrderEvents("HASH", decodedTx.Msgs, time.Now())
+	assert.Len(t, events, 3)
+
+	assert.Equal(t, PendingOrderCancellation, events[0].Action)
+	assert.Equal(t, PendingDerivativeOrder, events[0].MarketType)
+	assert.True(t, events[0].CancelAll)
+
+	assert.Equal(t, PendingOrderCancellation, events[1].Action)
+	assert.Equal(t, "0x1a2b", events[1].Cancellation.OrderHash)
+	assert.False(t, events[1].CancelAll)
+
+	assert.Equal(t, PendingOrderCreation, events[2].Action)
+	assert.Equal(t, sender, events[2].Sender)
+	assert.Equal(t, "HASH", events[2].TxHash)
+	assert.Equal(t, order.OrderInfo.Price, events[2].SpotOrder.OrderInfo.Price)
+}
+
+func TestMempoolWatcherStopsWhenContextIsDone(t *testing.T) {
+	client := &fakeMempoolClient{}
+	watcher := NewMempoolWatcher(client, time.Millisecond)
+	ctx, cancelFn := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		watcher.Watch(ctx, make(chan PendingOrderEvent))
+		close(done)
+	}()
+
+	cancelFn()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watcher did not stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,194 @@
+package chain
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+type OpenOrderMarketType string
+
+const (
+	OpenOrderSpot          OpenOrderMarketType = "spot"
+	OpenOrderDerivative    OpenOrderMarketType = "derivative"
+	OpenOrderBinaryOptions OpenOrderMarketType = "binary_options"
+)
+
+// OpenOrder is a resting limit order. Values are in chain format, and Fillable is the quantity not filled yet. Margin
+// is zero for spot orders
+type OpenOrder struct {
+	MarketType OpenOrderMarketType
+	MarketId   string
+	OrderHash  string
+	IsBuy      bool
+	Price      sdk.Dec
+	Quantity   sdk.Dec
+	Fillable   sdk.Dec
+	Margin     sdk.Dec
+}
+
+// OpenOrdersSnapshot has all the resting orders of a subaccount at a block height, sorted by market, side (buys
+// first), price and order hash
+type OpenOrdersSnapshot struct {
+	SubaccountId string
+	Height       int64
+	Orders       []OpenOrder
+}
+
+var openOrdersCSVHeader = []string{"market_type", "market_id", "side", "price", "quantity", "fillable", "margin", "order_hash"}
+
+// ExportOpenOrders lists the resting orders of the subaccount in all the active spot, derivative and binary options
+// markets, with every query pinned to the same block height. A zero height uses the latest height, resolved by the
+// first query. It is meant to reconcile internal books against the chain state at a known height
+func ExportOpenOrders(ctx context.Context, chainClient ChainClient, subaccountId string, height int64) (*OpenOrdersSnapshot, error) {
+	spotMarkets, resolvedHeight, err := QueryAtHeight(ctx, height, func(ctx context.Context) ([]string, error) {
+		res, err := chainClient.FetchChainSpotMarkets(ctx, "Active", nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the spot markets")
+		}
+		marketIds := make([]string, 0, len(res.Markets))
+		for _, market := range res.Markets {
+			marketIds = append(marketIds, market.MarketId)
+		}
+		return marketIds, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if height == 0 {
+		height = resolvedHeight
+	}
+	if height == 0 {
+		return nil, errors.New("the node did not report the block height of the latest state")
+	}
+	if resolvedHeight != 0 && resolvedHeight != height {
+		return nil, errors.Errorf("the node answered at height %d instead of %d", resolvedHeight, height)
+	}
+
+	orders, _, err := QueryAtHeight(ctx, height, func(ctx context.Context) ([]OpenOrder, error) {
+		return fetchOpenOrders(ctx, chainClient, subaccountId, spotMarkets)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortOpenOrders(orders)
+
+	return &OpenOrdersSnapshot{
+		SubaccountId: subaccountId,
+		Height:       height,
+		Orders:       orders,
+	}, nil
+}
+
+func fetchOpenOrders(ctx context.Context, chainClient ChainClient, subaccountId string, spotMarketIds []string) ([]OpenOrder, error) {
+	orders := make([]OpenOrder, 0)
+	for _, marketId := range spotMarketIds {
+		res, err := chainClient.FetchChainTraderSpotOrders(ctx, marketId, subaccountId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the spot orders in market %s", marketId)
+		}
+		for _, order := range res.Orders {
+			orders = append(orders, OpenOrder{
+				MarketType: OpenOrderSpot,
+				MarketId:   marketId,
+				OrderHash:  order.OrderHash,
+				IsBuy:      order.IsBuy,
+				Price:      order.Price,
+				Quantity:   order.Quantity,
+				Fillable:   order.Fillable,
+				Margin:     sdk.ZeroDec(),
+			})
+		}
+	}
+
+	derivativeMarkets, err := chainClient.FetchChainDerivativeMarkets(ctx, "Active", nil, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the derivative markets")
+	}
+	marketTypes := make(map[string]OpenOrderMarketType)
+	derivativeMarketIds := make([]string, 0)
+	for _, fullMarket := range derivativeMarkets.Markets {
+		marketTypes[fullMarket.Market.MarketId] = OpenOrderDerivative
+		derivativeMarketIds = append(derivativeMarketIds, fullMarket.Market.MarketId)
+	}
+	binaryOptionsMarkets, err := chainClient.FetchChainBinaryOptionsMarkets(ctx, "Active")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the binary options markets")
+	}
+	for _, market := range binaryOptionsMarkets.Markets {
+		marketTypes[market.MarketId] = OpenOrderBinaryOptions
+		derivativeMarketIds = append(derivativeMarketIds, market.MarketId)
+	}
+
+	// binary options limit orders are stored with the derivative orders
+	for _, marketId := range derivativeMarketIds {
+		res, err := chainClient.FetchChainTraderDerivativeOrders(ctx, marketId, subaccountId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the derivative orders in market %s", marketId)
+		}
+		for _, order := range res.Orders {
+			orders = append(orders, OpenOrder{
+				MarketType: marketTypes[marketId],
+				MarketId:   marketId,
+				OrderHash:  order.OrderHash,
+				IsBuy:      order.IsBuy,
+				Price:      order.Price,
+				Quantity:   order.Quantity,
+				Fillable:   order.Fillable,
+				Margin:     order.Margin,
+			})
+		}
+	}
+
+	return orders, nil
+}
+
+func sortOpenOrders(orders []OpenOrder) {
+	sort.Slice(orders, func(i, j int) bool {
+		a, b := orders[i], orders[j]
+		if a.MarketId != b.MarketId {
+			return a.MarketId < b.MarketId
+		}
+		if a.IsBuy != b.IsBuy {
+			return a.IsBuy
+		}
+		if !a.Price.Equal(b.Price) {
+			return a.Price.LT(b.Price)
+		}
+		return a.OrderHash < b.OrderHash
+	})
+}
+
+// WriteCSV writes the orders with a header row, one order per row in the snapshot order, so two snapshots of the
+// same orders produce the same output
+func (s *OpenOrdersSnapshot) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(openOrdersCSVHeader); err != nil {
+		return errors.Wrap(err, "failed to write the open orders header")
+	}
+	for _, order := range s.Orders {
+		side := "sell"
+		if order.IsBuy {
+			side = "buy"
+		}
+		record := []string{
+			string(order.MarketType),
+			order.MarketId,
+			side,
+			order.Price.String(),
+			order.Quantity.String(),
+			order.Fillable.String(),
+			order.Margin.String(),
+			order.OrderHash,
+		}
+		if err := writer.Write(record); err != nil {
+			return errors.Wrapf(err, "failed to write order %s", order.OrderHash)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,70 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+type openOrdersTestChainClient struct {
+	MockChainClient
+	binaryOptionsMarketId string
+}
+
+func (c *openOrdersTestChainClient) FetchChainSpotMarkets(ctx context.Context, status string, marketIds []string) (*exchangetypes.QuerySpotMarketsResponse, error) {
+	return &exchangetypes.QuerySpotMarketsResponse{Markets: []*exchangetypes.SpotMarket{{MarketId: riskSpotMarketId}}}, nil
+}
+
+func (c *openOrdersTestChainClient) FetchChainDerivativeMarkets(ctx context.Context, status string, marketIds []string, withMidPriceAndTob bool) (*exchangetypes.QueryDerivativeMarketsResponse, error) {
+	return &exchangetypes.QueryDerivativeMarketsResponse{Markets: []*exchangetypes.FullDerivativeMarket{
+		{Market: &exchangetypes.DerivativeMarket{MarketId: riskDerivativeMarketId}},
+	}}, nil
+}
+
+func (c *openOrdersTestChainClient) FetchChainBinaryOptionsMarkets(ctx context.Context, status string) (*exchangetypes.QueryBinaryMarketsResponse, error) {
+	return &exchangetypes.QueryBinaryMarketsResponse{Markets: []*exchangetypes.BinaryOptionsMarket{{MarketId: c.binaryOptionsMarketId}}}, nil
+}
+
+func (c *openOrdersTestChainClient) FetchChainTraderSpotOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderSpotOrdersResponse, error) {
+	return &exchangetypes.QueryTraderSpotOrdersResponse{Orders: []*exchangetypes.TrimmedSpotLimitOrder{
+		{Price: sdk.MustNewDecFromStr("3"), Quantity: sdk.MustNewDecFromStr("1"), Fillable: sdk.MustNewDecFromStr("1"), OrderHash: "0x02"},
+		{Price: sdk.MustNewDecFromStr("2"), Quantity: sdk.MustNewDecFromStr("5"), Fillable: sdk.MustNewDecFromStr("4"), IsBuy: true, OrderHash: "0x03"},
+		{Price: sdk.MustNewDecFromStr("1"), Quantity: sdk.MustNewDecFromStr("1"), Fillable: sdk.MustNewDecFromStr("1"), IsBuy: true, OrderHash: "0x01"},
+	}}, nil
+}
+
+func (c *openOrdersTestChainClient) FetchChainTraderDerivativeOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderDerivativeOrdersResponse, error) {
+	if marketId != c.binaryOptionsMarketId {
+		return &exchangetypes.QueryTraderDerivativeOrdersResponse{}, nil
+	}
+	return &exchangetypes.QueryTraderDerivativeOrdersResponse{Orders: []*exchangetypes.TrimmedDerivativeLimitOrder{
+		{Price: sdk.MustNewDecFromStr("0.4"), Quantity: sdk.MustNewDecFromStr("10"), Fillable: sdk.MustNewDecFromStr("10"), Margin: sdk.MustNewDecFromStr("4"), IsBuy: true, OrderHash: "0x04"},
+	}}, nil
+}
+
+func TestExportOpenOrdersIsSortedAndCanonical(t *testing.T) {
+	chainClient := &openOrdersTestChainClient{binaryOptionsMarketId: "0xff"}
+
+	snapshot, err := ExportOpenOrders(context.Background(), chainClient, riskSubaccountId, 1234)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234), snapshot.Height)
+	assert.Len(t, snapshot.Orders, 4)
+
+	var output bytes.Buffer
+	assert.NoError(t, snapshot.WriteCSV(&output))
+	expected := "market_type,market_id,side,price,quantity,fillable,margin,order_hash\n" +
+		"spot," + riskSpotMarketId + ",buy,1.000000000000000000,1.000000000000000000,1.000000000000000000,0.000000000000000000,0x01\n" +
+		"spot," + riskSpotMarketId + ",buy,2.000000000000000000,5.000000000000000000,4.000000000000000000,0.000000000000000000,0x03\n" +
+		"spot," + riskSpotMarketId + ",sell,3.000000000000000000,1.000000000000000000,1.000000000000000000,0.000000000000000000,0x02\n" +
+		"binary_options,0xff,buy,0.400000000000000000,10.000000000000000000,10.000000000000000000,4.000000000000000000,0x04\n"
+	assert.Equal(t, expected, output.String())
+
+	// the latest height has to be reported by the node to pin the rest of the queries
+	_, err = ExportOpenOrders(context.Background(), chainClient, riskSubaccountId, 0)
+	assert.Error(t, err)
+}
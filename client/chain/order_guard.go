@@ -0,0 +1,235 @@
+package chain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+var ErrOrderRejected = errors.New("order rejected by the order guard")
+
+// OrderRejectionReason is the check an order failed
+type OrderRejectionReason string
+
+const (
+	// RejectionPriceDeviation is an order price too far from the reference price of the market
+	RejectionPriceDeviation OrderRejectionReason = "price_deviation"
+	// RejectionNoReferencePrice is an order in a market with a max price deviation but without reference price
+	RejectionNoReferencePrice OrderRejectionReason = "no_reference_price"
+	// RejectionStaleReferencePrice is an order in a market whose reference price is older than its max age
+	RejectionStaleReferencePrice OrderRejectionReason = "stale_reference_price"
+	// RejectionMaxOrderNotional is an order with a notional (price * quantity) greater than the market limit
+	RejectionMaxOrderNotional OrderRejectionReason = "max_order_notional"
+	// RejectionThrottled is an order exceeding the number of orders a subaccount can create in the throttle interval
+	RejectionThrottled OrderRejectionReason = "throttled"
+)
+
+// OrderGuardLimits are the fat finger limits of a market. Prices and notionals are in chain format (the same units
+// used in the order messages), and nil or zero values are not enforced
+type OrderGuardLimits struct {
+	// MaxPriceDeviation is the max relative difference between the order price and the reference price of the market
+	// (e.g. 0.05 for 5%). Orders are rejected when the market has no reference price
+	MaxPriceDeviation *sdk.Dec
+	// MaxReferencePriceAge is the oldest reference price used for the price deviation check
+	MaxReferencePriceAge time.Duration
+	// MaxOrderNotional is the max notional (price * quantity) of a single order
+	MaxOrderNotional *sdk.Dec
+	// MaxOrders is the max number of orders a subaccount can create in the market within ThrottleInterval
+	MaxOrders        int
+	ThrottleInterval time.Duration
+}
+
+// OrderRejection describes the order rejected by the guard and the check it failed. Limit and Value are the exceeded
+// limit and the value of the order (the price deviation, the notional or the number of orders in the interval)
+type OrderRejection struct {
+	Reason         OrderRejectionReason
+	MarketId       string
+	SubaccountId   string
+	IsBuy          bool
+	Price          sdk.Dec
+	Quantity       sdk.Dec
+	ReferencePrice sdk.Dec
+	Limit          sdk.Dec
+	Value          sdk.Dec
+}
+
+type OrderRejectionError struct {
+	Rejection OrderRejection
+}
+
+func (e *OrderRejectionError) Error() string {
+	r := e.Rejection
+	var detail string
+	switch r.Reason {
+	case RejectionPriceDeviation:
+		detail = fmt.Sprintf("price %s deviates %s from the reference price %s (limit %s)", r.Price.String(), r.Value.String(), r.ReferencePrice.String(), r.Limit.String())
+	case RejectionNoReferencePrice:
+		detail = "the market has no reference price"
+	case RejectionStaleReferencePrice:
+		detail = "the reference price of the market is stale"
+	case RejectionMaxOrderNotional:
+		detail = fmt.Sprintf("notional %s is greater than %s", r.Value.String(), r.Limit.String())
+	case RejectionThrottled:
+		detail = fmt.Sprintf("%s orders in the throttle interval (limit %s)", r.Value.String(), r.Limit.String())
+	}
+	return fmt.Sprintf("%s: %s for market %s and subaccount %s: %s", ErrOrderRejected.Error(), r.Reason, r.MarketId, r.SubaccountId, detail)
+}
+
+func (e *OrderRejectionError) Unwrap() error {
+	return ErrOrderRejected
+}
+
+type referencePrice struct {
+	price     sdk.Dec
+	updatedAt time.Time
+}
+
+// OrderGuard rejects the orders with a price too far from the mid or mark price of their market, orders too large,
+// and subaccounts creating too many orders, before they are broadcasted. It can be installed in the chain client with
+// common.OptionPreBroadcastCheck(guard.CheckMsgs).
+//
+// The guard doesn't query the chain: the reference prices have to be kept updated by the caller (usually the mid
+// price of the orderbook stream for spot markets and the mark price for derivative markets)
+type OrderGuard struct {
+	mux             sync.Mutex
+	limits          map[riskKey]OrderGuardLimits
+	referencePrices map[string]referencePrice
+	orderTimes      map[riskKey][]time.Time
+	clock           clock.Clock
+}
+
+func NewOrderGuard() *OrderGuard {
+	return &OrderGuard{
+		limits:          make(map[riskKey]OrderGuardLimits),
+		referencePrices: make(map[string]referencePrice),
+		orderTimes:      make(map[riskKey][]time.Time),
+		clock:           clock.Real(),
+	}
+}
+
+// SetClock sets the clock of the reference price ages and the throttle intervals
+func (g *OrderGuard) SetClock(c clock.Clock) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.clock = clock.OrReal(c)
+}
+
+// SetLimits sets the limits for a subaccount in a market. An empty subaccountId sets the limits used for all the
+// subaccounts without specific limits
+func (g *OrderGuard) SetLimits(marketId string, subaccountId string, limits OrderGuardLimits) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.limits[riskKey{marketId: marketId, subaccountId: subaccountId}] = limits
+}
+
+// SetReferencePrice updates the price the order prices are compared to
+func (g *OrderGuard) SetReferencePrice(marketId string, price sdk.Dec) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.referencePrices[marketId] = referencePrice{price: price, updatedAt: g.clock.Now()}
+}
+
+// CheckMsgs returns an *OrderRejectionError for the first order created by the msgs that fails the limits of its
+// market. Orders in the same call count together for the throttle, and are only counted if all of them are accepted.
+// Cancellations are never rejected
+func (g *OrderGuard) CheckMsgs(msgs ...sdk.Msg) error {
+	orders := riskOrdersFromMsgs(msgs)
+	if len(orders) == 0 {
+		return nil
+	}
+
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	now := g.clock.Now()
+	counts := make(map[riskKey]int)
+	for _, order := range orders {
+		key := riskKey{marketId: order.marketId, subaccountId: order.subaccountId}
+		limits, found := g.limitsFor(key)
+		if !found {
+			continue
+		}
+		rejection := OrderRejection{
+			MarketId:     order.marketId,
+			SubaccountId: order.subaccountId,
+			IsBuy:        order.isBuy,
+			Price:        order.price,
+			Quantity:     order.quantity,
+		}
+
+		if limits.MaxPriceDeviation != nil {
+			reference, found := g.referencePrices[order.marketId]
+			switch {
+			case !found || !reference.price.IsPositive():
+				rejection.Reason = RejectionNoReferencePrice
+				return &OrderRejectionError{Rejection: rejection}
+			case limits.MaxReferencePriceAge > 0 && now.Sub(reference.updatedAt) > limits.MaxReferencePriceAge:
+				rejection.Reason = RejectionStaleReferencePrice
+				rejection.ReferencePrice = reference.price
+				return &OrderRejectionError{Rejection: rejection}
+			}
+			deviation := order.price.Sub(reference.price).Abs().Quo(reference.price)
+			if deviation.GT(*limits.MaxPriceDeviation) {
+				rejection.Reason = RejectionPriceDeviation
+				rejection.ReferencePrice = reference.price
+				rejection.Limit = *limits.MaxPriceDeviation
+				rejection.Value = deviation
+				return &OrderRejectionError{Rejection: rejection}
+			}
+		}
+
+		if limits.MaxOrderNotional != nil {
+			if notional := order.price.Mul(order.quantity); notional.GT(*limits.MaxOrderNotional) {
+				rejection.Reason = RejectionMaxOrderNotional
+				rejection.Limit = *limits.MaxOrderNotional
+				rejection.Value = notional
+				return &OrderRejectionError{Rejection: rejection}
+			}
+		}
+
+		if limits.MaxOrders > 0 && limits.ThrottleInterval > 0 {
+			if _, found := counts[key]; !found {
+				counts[key] = g.recentOrders(key, now, limits.ThrottleInterval)
+			}
+			counts[key]++
+			if counts[key] > limits.MaxOrders {
+				rejection.Reason = RejectionThrottled
+				rejection.Limit = sdk.NewDec(int64(limits.MaxOrders))
+				rejection.Value = sdk.NewDec(int64(counts[key]))
+				return &OrderRejectionError{Rejection: rejection}
+			}
+		}
+	}
+
+	for key, count := range counts {
+		for len(g.orderTimes[key]) < count {
+			g.orderTimes[key] = append(g.orderTimes[key], now)
+		}
+	}
+
+	return nil
+}
+
+func (g *OrderGuard) limitsFor(key riskKey) (OrderGuardLimits, bool) {
+	if limits, found := g.limits[key]; found {
+		return limits, true
+	}
+	limits, found := g.limits[riskKey{marketId: key.marketId}]
+	return limits, found
+}
+
+// recentOrders drops the order times older than the interval and returns the number of remaining orders
+func (g *OrderGuard) recentOrders(key riskKey, now time.Time, interval time.Duration) int {
+	times := g.orderTimes[key]
+	start := 0
+	for start < len(times) && now.Sub(times[start]) >= interval {
+		start++
+	}
+	g.orderTimes[key] = times[start:]
+	return len(g.orderTimes[key])
+}
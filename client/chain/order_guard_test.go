@@ -0,0 +1,107 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+func orderGuardSpotOrderMsg(orderType exchangetypes.OrderType, price string, quantity string) *exchangetypes.MsgCreateSpotLimitOrder {
+	return &exchangetypes.MsgCreateSpotLimitOrder{
+		Sender: "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku",
+		Order: exchangetypes.SpotOrder{
+			MarketId: riskSpotMarketId,
+			OrderInfo: exchangetypes.OrderInfo{
+				SubaccountId: riskSubaccountId,
+				Price:        sdk.MustNewDecFromStr(price),
+				Quantity:     sdk.MustNewDecFromStr(quantity),
+			},
+			OrderType: orderType,
+		},
+	}
+}
+
+func orderRejection(t *testing.T, err error) OrderRejection {
+	assert.True(t, errors.Is(err, ErrOrderRejected))
+	var rejectionErr *OrderRejectionError
+	if !assert.True(t, errors.As(err, &rejectionErr)) {
+		return OrderRejection{}
+	}
+	return rejectionErr.Rejection
+}
+
+func TestOrderGuardPriceDeviation(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	guard := NewOrderGuard()
+	guard.SetClock(fakeClock)
+	guard.SetLimits(riskSpotMarketId, "", OrderGuardLimits{MaxPriceDeviation: riskDec("0.05"), MaxReferencePriceAge: time.Minute})
+
+	rejection := orderRejection(t, guard.CheckMsgs(orderGuardSpotOrderMsg(exchangetypes.OrderType_BUY, "10", "1")))
+	assert.Equal(t, RejectionNoReferencePrice, rejection.Reason)
+
+	guard.SetReferencePrice(riskSpotMarketId, sdk.MustNewDecFromStr("10"))
+	assert.NoError(t, guard.CheckMsgs(orderGuardSpotOrderMsg(exchangetypes.OrderType_BUY, "10.5", "1")))
+	assert.NoError(t, guard.CheckMsgs(orderGuardSpotOrderMsg(exchangetypes.OrderType_SELL, "9.5", "1")))
+
+	err := guard.CheckMsgs(orderGuardSpotOrderMsg(exchangetypes.OrderType_SELL, "1", "1"))
+	rejection = orderRejection(t, err)
+	assert.Equal(t, RejectionPriceDeviation, rejection.Reason)
+	assert.False(t, rejection.IsBuy)
+	assert.Equal(t, "0.900000000000000000", rejection.Value.String())
+	assert.EqualError(t, err, "order rejected by the order guard: price_deviation for market "+riskSpotMarketId+" and subaccount "+riskSubaccountId+": price 1.000000000000000000 deviates 0.900000000000000000 from the reference price 10.000000000000000000 (limit 0.050000000000000000)")
+
+	// derivative markets without limits are not checked
+	assert.NoError(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "1000", "1", "1000")))
+
+	fakeClock.Advance(2 * time.Minute)
+	rejection = orderRejection(t, guard.CheckMsgs(orderGuardSpotOrderMsg(exchangetypes.OrderType_BUY, "10", "1")))
+	assert.Equal(t, RejectionStaleReferencePrice, rejection.Reason)
+}
+
+func TestOrderGuardMaxOrderNotional(t *testing.T) {
+	guard := NewOrderGuard()
+	guard.SetLimits(riskDerivativeMarketId, "", OrderGuardLimits{MaxOrderNotional: riskDec("1000")})
+	// the subaccount specific limits replace the market limits
+	guard.SetLimits(riskDerivativeMarketId, riskSubaccountId, OrderGuardLimits{MaxOrderNotional: riskDec("500")})
+
+	assert.NoError(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "5", "500")))
+	rejection := orderRejection(t, guard.CheckMsgs(&exchangetypes.MsgBatchUpdateOrders{
+		DerivativeOrdersToCreate: []*exchangetypes.DerivativeOrder{&riskDerivativeOrderMsg(exchangetypes.OrderType_SELL, "100", "6", "600").Order},
+	}))
+	assert.Equal(t, RejectionMaxOrderNotional, rejection.Reason)
+	assert.Equal(t, "600.000000000000000000", rejection.Value.String())
+	assert.Equal(t, "500.000000000000000000", rejection.Limit.String())
+
+	msg := riskDerivativeOrderMsg(exchangetypes.OrderType_SELL, "100", "6", "600")
+	msg.Order.OrderInfo.SubaccountId = "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000"
+	assert.NoError(t, guard.CheckMsgs(msg))
+}
+
+func TestOrderGuardThrottle(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	guard := NewOrderGuard()
+	guard.SetClock(fakeClock)
+	guard.SetLimits(riskSpotMarketId, "", OrderGuardLimits{MaxOrders: 3, ThrottleInterval: time.Second})
+	order := orderGuardSpotOrderMsg(exchangetypes.OrderType_BUY, "10", "1")
+
+	assert.NoError(t, guard.CheckMsgs(order, order))
+	// the rejected msgs are not counted
+	rejection := orderRejection(t, guard.CheckMsgs(order, order))
+	assert.Equal(t, RejectionThrottled, rejection.Reason)
+	assert.Equal(t, "3.000000000000000000", rejection.Limit.String())
+	assert.Equal(t, "4.000000000000000000", rejection.Value.String())
+	assert.NoError(t, guard.CheckMsgs(order))
+	assert.Error(t, guard.CheckMsgs(order))
+
+	// cancellations are never throttled
+	assert.NoError(t, guard.CheckMsgs(&exchangetypes.MsgCancelSpotOrder{MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId}))
+
+	fakeClock.Advance(time.Second)
+	assert.NoError(t, guard.CheckMsgs(order, order, order))
+}
@@ -0,0 +1,116 @@
+package chain
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// ReplaceOrderResult has the hashes of the cancelled and created orders
+type ReplaceOrderResult struct {
+	TxHash       string
+	Height       int64
+	OldOrderHash string
+	NewOrderHash string
+}
+
+// OrderReplacer cancels tracked orders and creates their replacements in a single tx
+type OrderReplacer struct {
+	chainClient ChainClient
+	tracker     *OrderTracker
+}
+
+func NewOrderReplacer(chainClient ChainClient, tracker *OrderTracker) *OrderReplacer {
+	return &OrderReplacer{
+		chainClient: chainClient,
+		tracker:     tracker,
+	}
+}
+
+// ReplaceOrder cancels the tracked order and creates newOrder (a *exchangetypes.SpotOrder or
+// *exchangetypes.DerivativeOrder in the same market) atomically: if the cancel fails (e.g. the order was already
+// filled) the new order is not created. The new order is tracked and linked to the old one
+func (r *OrderReplacer) ReplaceOrder(ctx context.Context, oldOrderHash string, newOrder exchangetypes.IOrder) (*ReplaceOrderResult, error) {
+	oldOrder, found := r.tracker.Order(oldOrderHash)
+	if !found {
+		return nil, errors.Errorf("order %s is not tracked", oldOrderHash)
+	}
+
+	sender := r.chainClient.FromAddress().String()
+	composer := NewTxComposer(r.chainClient)
+
+	switch order := newOrder.(type) {
+	case *exchangetypes.SpotOrder:
+		if oldOrder.IsDerivative || order.MarketId != oldOrder.MarketId {
+			return nil, errors.Errorf("order %s can not be replaced with an order in spot market %s", oldOrderHash, order.MarketId)
+		}
+		composer.Add(
+			&exchangetypes.MsgCancelSpotOrder{
+				Sender:       sender,
+				MarketId:     oldOrder.MarketId,
+				SubaccountId: oldOrder.SubaccountId,
+				OrderHash:    oldOrderHash,
+			},
+			&exchangetypes.MsgCreateSpotLimitOrder{Sender: sender, Order: *order},
+		)
+	case *exchangetypes.DerivativeOrder:
+		if !oldOrder.IsDerivative || order.MarketId != oldOrder.MarketId {
+			return nil, errors.Errorf("order %s can not be replaced with an order in derivative market %s", oldOrderHash, order.MarketId)
+		}
+		composer.Add(
+			&exchangetypes.MsgCancelDerivativeOrder{
+				Sender:       sender,
+				MarketId:     oldOrder.MarketId,
+				SubaccountId: oldOrder.SubaccountId,
+				OrderHash:    oldOrderHash,
+			},
+			&exchangetypes.MsgCreateDerivativeLimitOrder{Sender: sender, Order: *order},
+		)
+	default:
+		return nil, errors.Errorf("unsupported order type %T", newOrder)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result, err := composer.Broadcast()
+	if err != nil {
+		return nil, err
+	}
+	if result.Err != nil {
+		return nil, errors.Wrapf(result.Err, "failed to replace order %s", oldOrderHash)
+	}
+
+	newOrderHash, err := createdOrderHash(result.Results[1].Response)
+	if err != nil {
+		return nil, errors.Wrapf(err, "order %s was replaced in tx %s", oldOrderHash, result.TxHash)
+	}
+
+	switch order := newOrder.(type) {
+	case *exchangetypes.SpotOrder:
+		r.tracker.TrackSpotOrder(newOrderHash, order)
+	case *exchangetypes.DerivativeOrder:
+		r.tracker.TrackDerivativeOrder(newOrderHash, order)
+	}
+	r.tracker.LinkReplacement(oldOrderHash, newOrderHash)
+
+	return &ReplaceOrderResult{
+		TxHash:       result.TxHash,
+		Height:       result.Height,
+		OldOrderHash: oldOrderHash,
+		NewOrderHash: newOrderHash,
+	}, nil
+}
+
+func createdOrderHash(response interface{}) (string, error) {
+	switch res := response.(type) {
+	case *exchangetypes.MsgCreateSpotLimitOrderResponse:
+		return res.OrderHash, nil
+	case *exchangetypes.MsgCreateDerivativeLimitOrderResponse:
+		return res.OrderHash, nil
+	default:
+		return "", errors.Errorf("the tx has no order creation response (got %T)", response)
+	}
+}
@@ -0,0 +1,80 @@
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func replaceTestTxResponse(t *testing.T, newOrderHash string) *sdk.TxResponse {
+	cancelResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgCancelSpotOrderResponse{})
+	assert.NoError(t, err)
+	createResponse, err := codectypes.NewAnyWithValue(&exchangetypes.MsgCreateSpotLimitOrderResponse{OrderHash: newOrderHash})
+	assert.NoError(t, err)
+	data, err := (&sdk.TxMsgData{MsgResponses: []*codectypes.Any{cancelResponse, createResponse}}).Marshal()
+	assert.NoError(t, err)
+	return &sdk.TxResponse{TxHash: "ABCD", Height: 10, Data: hex.EncodeToString(data)}
+}
+
+func TestReplaceOrder(t *testing.T) {
+	tracker := NewOrderTracker()
+	tracker.Track(TrackedOrder{OrderHash: "0x01", MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId, OrderType: exchangetypes.OrderType_BUY})
+	chainClient := &composerTestChainClient{txResponse: replaceTestTxResponse(t, "0x02")}
+	replacer := NewOrderReplacer(chainClient, tracker)
+
+	newOrder := &exchangetypes.SpotOrder{
+		MarketId:  riskSpotMarketId,
+		OrderType: exchangetypes.OrderType_BUY,
+		OrderInfo: exchangetypes.OrderInfo{SubaccountId: riskSubaccountId, Price: sdk.MustNewDecFromStr("2"), Quantity: sdk.MustNewDecFromStr("1")},
+	}
+	result, err := replacer.ReplaceOrder(context.Background(), "0x01", newOrder)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x01", result.OldOrderHash)
+	assert.Equal(t, "0x02", result.NewOrderHash)
+	assert.Equal(t, "ABCD", result.TxHash)
+
+	assert.Len(t, chainClient.broadcastedMsgs, 2)
+	cancelMsg, isCancel := chainClient.broadcastedMsgs[0].(*exchangetypes.MsgCancelSpotOrder)
+	assert.True(t, isCancel)
+	assert.Equal(t, "0x01", cancelMsg.OrderHash)
+	assert.IsType(t, &exchangetypes.MsgCreateSpotLimitOrder{}, chainClient.broadcastedMsgs[1])
+
+	_, found := tracker.Order("0x01")
+	assert.False(t, found)
+	replacement, found := tracker.Order("0x02")
+	assert.True(t, found)
+	assert.Equal(t, "0x01", replacement.ReplacesOrderHash)
+	assert.Equal(t, "0x02", tracker.LatestReplacement("0x01"))
+}
+
+func TestReplaceOrderFailures(t *testing.T) {
+	tracker := NewOrderTracker()
+	tracker.Track(TrackedOrder{OrderHash: "0x01", MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId})
+	chainClient := &composerTestChainClient{txResponse: &sdk.TxResponse{
+		TxHash:    "ABCD",
+		Codespace: exchangetypes.ModuleName,
+		Code:      exchangetypes.ErrOrderDoesntExist.ABCICode(),
+		RawLog:    "failed to execute message; message index: 0: order doesnt exist",
+	}}
+	replacer := NewOrderReplacer(chainClient, tracker)
+	newOrder := &exchangetypes.SpotOrder{MarketId: riskSpotMarketId}
+
+	_, err := replacer.ReplaceOrder(context.Background(), "0x09", newOrder)
+	assert.Error(t, err)
+
+	_, err = replacer.ReplaceOrder(context.Background(), "0x01", &exchangetypes.DerivativeOrder{MarketId: riskDerivativeMarketId})
+	assert.Error(t, err)
+	assert.Empty(t, chainClient.broadcastedMsgs)
+
+	_, err = replacer.ReplaceOrder(context.Background(), "0x01", newOrder)
+	assert.True(t, errors.Is(err, exchangetypes.ErrOrderDoesntExist))
+	_, found := tracker.Order("0x01")
+	assert.True(t, found)
+}
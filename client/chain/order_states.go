@@ -0,0 +1,183 @@
+package chain
+
+import (
+	"context"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/exchange"
+	accountPB "github.com/InjectiveLabs/sdk-go/exchange/accounts_rpc/pb"
+)
+
+// DefaultOrderStatesChunkSize is the max number of order hashes of each indexer query made by
+// OrderStateFetcher.GetOrderStates
+const DefaultOrderStatesChunkSize = 100
+
+// OrderStatus is the status of an order in the indexer
+type OrderStatus string
+
+const (
+	OrderStatusBooked        OrderStatus = "booked"
+	OrderStatusPartialFilled OrderStatus = "partial_filled"
+	OrderStatusFilled        OrderStatus = "filled"
+	OrderStatusCanceled      OrderStatus = "canceled"
+)
+
+// OrderState is the state of an order in the indexer. The quantities are in chain format
+type OrderState struct {
+	OrderHash         ethcommon.Hash
+	MarketId          string
+	SubaccountId      string
+	IsDerivative      bool
+	Status            OrderStatus
+	QuantityFilled    decimal.Decimal
+	QuantityRemaining decimal.Decimal
+	UpdatedAt         time.Time
+	// ExpiresAtHeight is the expiry height of the order in the order tracker of the fetcher (see
+	// OrderTracker.SetExpiry). Zero for the orders without expiry or not tracked
+	ExpiresAtHeight int64
+}
+
+// IsOpen returns true if the order can still be filled
+func (s OrderState) IsOpen() bool {
+	return s.Status == OrderStatusBooked || s.Status == OrderStatusPartialFilled
+}
+
+func (s OrderState) IsCanceled() bool {
+	return s.Status == OrderStatusCanceled
+}
+
+// OrderStatesClient is the indexer query used by OrderStateFetcher, implemented by exchange.ExchangeClient
+type OrderStatesClient interface {
+	GetOrderStates(ctx context.Context, req *accountPB.OrderStatesRequest) (*accountPB.OrderStatesResponse, error)
+}
+
+var _ OrderStatesClient = exchange.ExchangeClient(nil)
+
+// OrderStateFetcher gets the states of many orders by hash from the indexer, in chunks of a few orders per query
+type OrderStateFetcher struct {
+	client    OrderStatesClient
+	chunkSize int
+	tracker   *OrderTracker
+}
+
+func NewOrderStateFetcher(client OrderStatesClient) *OrderStateFetcher {
+	return &OrderStateFetcher{
+		client:    client,
+		chunkSize: DefaultOrderStatesChunkSize,
+	}
+}
+
+// SetChunkSize sets the max number of order hashes of each query (DefaultOrderStatesChunkSize by default)
+func (f *OrderStateFetcher) SetChunkSize(size int) error {
+	if size < 1 {
+		return errors.Errorf("invalid order states chunk size %d", size)
+	}
+	f.chunkSize = size
+	return nil
+}
+
+// SetOrderTracker sets the tracker with the expiry heights of the orders. The tracked orders are also only queried as
+// spot or as derivative orders, while the others are queried as both
+func (f *OrderStateFetcher) SetOrderTracker(tracker *OrderTracker) {
+	f.tracker = tracker
+}
+
+// GetOrderStates returns the states of the orders, by hash. The orders unknown to the indexer are not in the result
+// (e.g. orders not indexed yet). The fetch stops at the first query failing or when the context is done
+func (f *OrderStateFetcher) GetOrderStates(ctx context.Context, hashes []ethcommon.Hash) (map[ethcommon.Hash]OrderState, error) {
+	states := make(map[ethcommon.Hash]OrderState, len(hashes))
+	for start := 0; start < len(hashes); start += f.chunkSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := start + f.chunkSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		res, err := f.client.GetOrderStates(ctx, f.orderStatesRequest(hashes[start:end]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the states of the orders %d to %d", start, end-1)
+		}
+		if err := f.addOrderStates(states, res.SpotOrderStates, false); err != nil {
+			return nil, err
+		}
+		if err := f.addOrderStates(states, res.DerivativeOrderStates, true); err != nil {
+			return nil, err
+		}
+	}
+	return states, nil
+}
+
+func (f *OrderStateFetcher) orderStatesRequest(hashes []ethcommon.Hash) *accountPB.OrderStatesRequest {
+	req := &accountPB.OrderStatesRequest{}
+	for _, hash := range hashes {
+		isSpot, isDerivative := true, true
+		if f.tracker != nil {
+			if order, found := f.tracker.Order(hash.Hex()); found {
+				isSpot, isDerivative = !order.IsDerivative, order.IsDerivative
+			}
+		}
+		if isSpot {
+			req.SpotOrderHashes = append(req.SpotOrderHashes, hash.Hex())
+		}
+		if isDerivative {
+			req.DerivativeOrderHashes = append(req.DerivativeOrderHashes, hash.Hex())
+		}
+	}
+	return req
+}
+
+func (f *OrderStateFetcher) addOrderStates(states map[ethcommon.Hash]OrderState, records []*accountPB.OrderStateRecord, isDerivative bool) error {
+	for _, record := range records {
+		hash, err := common.SafeParseHash(record.OrderHash)
+		if err != nil {
+			return errors.Wrapf(err, "invalid order hash %q in the order states", record.OrderHash)
+		}
+		state := OrderState{
+			OrderHash:    hash,
+			MarketId:     record.MarketId,
+			SubaccountId: record.SubaccountId,
+			IsDerivative: isDerivative,
+			Status:       OrderStatus(record.State),
+			UpdatedAt:    time.UnixMilli(record.UpdatedAt),
+		}
+		if state.QuantityFilled, err = common.SafeParseDecimal(record.QuantityFilled); err != nil {
+			return errors.Wrapf(err, "invalid filled quantity of order %s", record.OrderHash)
+		}
+		if state.QuantityRemaining, err = common.SafeParseDecimal(record.QuantityRemaining); err != nil {
+			return errors.Wrapf(err, "invalid remaining quantity of order %s", record.OrderHash)
+		}
+		if f.tracker != nil {
+			if order, found := f.tracker.Order(hash.Hex()); found {
+				state.ExpiresAtHeight = order.ExpiresAtHeight
+			}
+		}
+		states[hash] = state
+	}
+	return nil
+}
+
+// ReconcileOrderTracker gets the states of all the orders of the order tracker (see SetOrderTracker), and stops
+// tracking the ones filled or cancelled, returned sorted by order hash
+func (f *OrderStateFetcher) ReconcileOrderTracker(ctx context.Context) ([]TrackedOrder, error) {
+	if f.tracker == nil {
+		return nil, errors.New("the order state fetcher has no order tracker")
+	}
+
+	orderHashes := f.tracker.OrderHashes()
+	hashes := make([]ethcommon.Hash, 0, len(orderHashes))
+	for _, orderHash := range orderHashes {
+		hashes = append(hashes, ethcommon.HexToHash(orderHash))
+	}
+	states, err := f.GetOrderStates(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return f.tracker.ApplyOrderStates(states), nil
+}
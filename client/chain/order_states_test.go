@@ -0,0 +1,138 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	accountPB "github.com/InjectiveLabs/sdk-go/exchange/accounts_rpc/pb"
+)
+
+// fakeOrderStatesClient answers with the records of the spot and derivative hashes it knows
+type fakeOrderStatesClient struct {
+	spot       map[string]*accountPB.OrderStateRecord
+	derivative map[string]*accountPB.OrderStateRecord
+	requests   []*accountPB.OrderStatesRequest
+	err        error
+}
+
+func (c *fakeOrderStatesClient) GetOrderStates(_ context.Context, req *accountPB.OrderStatesRequest) (*accountPB.OrderStatesResponse, error) {
+	c.requests = append(c.requests, req)
+	if c.err != nil {
+		return &accountPB.OrderStatesResponse{}, c.err
+	}
+	res := &accountPB.OrderStatesResponse{}
+	for _, hash := range req.SpotOrderHashes {
+		if record, found := c.spot[hash]; found {
+			res.SpotOrderStates = append(res.SpotOrderStates, record)
+		}
+	}
+	for _, hash := range req.DerivativeOrderHashes {
+		if record, found := c.derivative[hash]; found {
+			res.DerivativeOrderStates = append(res.DerivativeOrderStates, record)
+		}
+	}
+	return res, nil
+}
+
+func orderStatesTestHash(i int) ethcommon.Hash {
+	return ethcommon.HexToHash(fmt.Sprintf("0x%064x", i+1))
+}
+
+func orderStateRecord(hash ethcommon.Hash, state OrderStatus, filled string, remaining string) *accountPB.OrderStateRecord {
+	return &accountPB.OrderStateRecord{
+		OrderHash:         hash.Hex(),
+		MarketId:          riskSpotMarketId,
+		SubaccountId:      riskSubaccountId,
+		State:             string(state),
+		QuantityFilled:    filled,
+		QuantityRemaining: remaining,
+		UpdatedAt:         1700000000000,
+	}
+}
+
+func TestOrderStateFetcherChunksTheQueries(t *testing.T) {
+	client := &fakeOrderStatesClient{
+		spot: map[string]*accountPB.OrderStateRecord{
+			orderStatesTestHash(0).Hex(): orderStateRecord(orderStatesTestHash(0), OrderStatusPartialFilled, "1", "2"),
+			orderStatesTestHash(4).Hex(): orderStateRecord(orderStatesTestHash(4), OrderStatusCanceled, "0", "3"),
+		},
+		derivative: map[string]*accountPB.OrderStateRecord{
+			orderStatesTestHash(2).Hex(): orderStateRecord(orderStatesTestHash(2), OrderStatusFilled, "3", "0"),
+		},
+	}
+	fetcher := NewOrderStateFetcher(client)
+	assert.NoError(t, fetcher.SetChunkSize(2))
+	assert.Error(t, fetcher.SetChunkSize(0))
+
+	hashes := make([]ethcommon.Hash, 0, 5)
+	for i := 0; i < 5; i++ {
+		hashes = append(hashes, orderStatesTestHash(i))
+	}
+	states, err := fetcher.GetOrderStates(context.Background(), hashes)
+	assert.NoError(t, err)
+	assert.Len(t, client.requests, 3)
+	assert.Len(t, client.requests[2].SpotOrderHashes, 1)
+	assert.Equal(t, client.requests[0].SpotOrderHashes, client.requests[0].DerivativeOrderHashes)
+
+	assert.Len(t, states, 3)
+	partial := states[orderStatesTestHash(0)]
+	assert.True(t, partial.IsOpen())
+	assert.False(t, partial.IsDerivative)
+	assert.Equal(t, "1", partial.QuantityFilled.String())
+	assert.Equal(t, int64(1700000000), partial.UpdatedAt.Unix())
+	assert.True(t, states[orderStatesTestHash(2)].IsDerivative)
+	assert.False(t, states[orderStatesTestHash(2)].IsOpen())
+	assert.True(t, states[orderStatesTestHash(4)].IsCanceled())
+
+	client.err = errors.New("unavailable")
+	_, err = fetcher.GetOrderStates(context.Background(), hashes)
+	assert.EqualError(t, err, "failed to get the states of the orders 0 to 1: unavailable")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = fetcher.GetOrderStates(ctx, hashes)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestOrderStateFetcherReconcilesTheOrderTracker(t *testing.T) {
+	tracker := NewOrderTracker()
+	for i := 0; i < 4; i++ {
+		tracker.Track(TrackedOrder{OrderHash: orderStatesTestHash(i).Hex(), MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId, IsDerivative: i == 1})
+	}
+	tracker.SetExpiry(orderStatesTestHash(0).Hex(), 120)
+	tracker.MarkCancelPending(orderStatesTestHash(2).Hex())
+	client := &fakeOrderStatesClient{
+		spot: map[string]*accountPB.OrderStateRecord{
+			orderStatesTestHash(0).Hex(): orderStateRecord(orderStatesTestHash(0), OrderStatusBooked, "0", "2"),
+			orderStatesTestHash(2).Hex(): orderStateRecord(orderStatesTestHash(2), OrderStatusCanceled, "0", "2"),
+		},
+		derivative: map[string]*accountPB.OrderStateRecord{
+			orderStatesTestHash(1).Hex(): orderStateRecord(orderStatesTestHash(1), OrderStatusFilled, "2", "0"),
+		},
+	}
+	fetcher := NewOrderStateFetcher(client)
+	_, err := fetcher.ReconcileOrderTracker(context.Background())
+	assert.EqualError(t, err, "the order state fetcher has no order tracker")
+	fetcher.SetOrderTracker(tracker)
+
+	states, err := fetcher.GetOrderStates(context.Background(), []ethcommon.Hash{orderStatesTestHash(0), orderStatesTestHash(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(120), states[orderStatesTestHash(0)].ExpiresAtHeight)
+	// the tracked orders are only queried with their kind
+	assert.Equal(t, []string{orderStatesTestHash(0).Hex()}, client.requests[0].SpotOrderHashes)
+	assert.Equal(t, []string{orderStatesTestHash(1).Hex()}, client.requests[0].DerivativeOrderHashes)
+
+	closed, err := fetcher.ReconcileOrderTracker(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, closed, 2)
+	assert.Equal(t, orderStatesTestHash(1).Hex(), closed[0].OrderHash)
+	assert.Equal(t, orderStatesTestHash(2).Hex(), closed[1].OrderHash)
+	// the open order and the order unknown to the indexer are kept
+	assert.Equal(t, []string{orderStatesTestHash(0).Hex(), orderStatesTestHash(3).Hex()}, tracker.OrderHashes())
+	assert.Empty(t, tracker.PendingCancels())
+}
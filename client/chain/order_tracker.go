@@ -0,0 +1,290 @@
+package chain
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+// TrackedOrder is an order created by the client. Price, Quantity and Margin are in chain format
+type TrackedOrder struct {
+	OrderHash    string                  `json:"order_hash"`
+	Cid          string                  `json:"cid,omitempty"`
+	MarketId     string                  `json:"market_id"`
+	SubaccountId string                  `json:"subaccount_id"`
+	OrderType    exchangetypes.OrderType `json:"order_type"`
+	IsDerivative bool                    `json:"is_derivative,omitempty"`
+	Price        sdk.Dec                 `json:"price"`
+	Quantity     sdk.Dec                 `json:"quantity"`
+	Margin       sdk.Dec                 `json:"margin"`
+	// ReplacesOrderHash is the order cancelled in the same tx this order was created in (see ReplaceOrder)
+	ReplacesOrderHash string `json:"replaces_order_hash,omitempty"`
+	// ExpiresAtHeight is the block height the client wants the order gone by (see SetExpiry and ExpirySweeper).
+	// Zero for orders that rest until they are filled or cancelled
+	ExpiresAtHeight int64     `json:"expires_at_height,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (o TrackedOrder) IsBuy() bool {
+	return o.OrderType.IsBuy()
+}
+
+// OrderTracker keeps the open orders created by the client, the orders with a cancel sent but not confirmed yet, and
+// the links between replaced orders and their replacements
+type OrderTracker struct {
+	mux            sync.RWMutex
+	orders         map[string]TrackedOrder
+	pendingCancels map[string]bool
+	replacedBy     map[string]string
+
+	clock clock.Clock
+}
+
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		orders:         make(map[string]TrackedOrder),
+		pendingCancels: make(map[string]bool),
+		replacedBy:     make(map[string]string),
+		clock:          clock.Real(),
+	}
+}
+
+// SetClock sets the clock of the creation times of the tracked orders and of the state snapshots
+func (t *OrderTracker) SetClock(c clock.Clock) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.clock = clock.OrReal(c)
+}
+
+// Track adds the order, or updates it if it is already tracked
+func (t *OrderTracker) Track(order TrackedOrder) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = t.clock.Now()
+	}
+	if order.Margin.IsNil() {
+		order.Margin = sdk.ZeroDec()
+	}
+	t.orders[order.OrderHash] = order
+}
+
+func (t *OrderTracker) TrackSpotOrder(orderHash string, order *exchangetypes.SpotOrder) {
+	t.Track(TrackedOrder{
+		OrderHash:    orderHash,
+		Cid:          order.OrderInfo.Cid,
+		MarketId:     order.MarketId,
+		SubaccountId: order.OrderInfo.SubaccountId,
+		OrderType:    order.OrderType,
+		Price:        order.OrderInfo.Price,
+		Quantity:     order.OrderInfo.Quantity,
+	})
+}
+
+func (t *OrderTracker) TrackDerivativeOrder(orderHash string, order *exchangetypes.DerivativeOrder) {
+	t.Track(TrackedOrder{
+		OrderHash:    orderHash,
+		Cid:          order.OrderInfo.Cid,
+		MarketId:     order.MarketId,
+		SubaccountId: order.OrderInfo.SubaccountId,
+		OrderType:    order.OrderType,
+		IsDerivative: true,
+		Price:        order.OrderInfo.Price,
+		Quantity:     order.OrderInfo.Quantity,
+		Margin:       order.Margin,
+	})
+}
+
+// Remove stops tracking the order (e.g. after it was filled or cancelled). Replacement links are kept
+func (t *OrderTracker) Remove(orderHash string) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	delete(t.orders, orderHash)
+	delete(t.pendingCancels, orderHash)
+}
+
+// MarkCancelPending records that a cancel was sent for the tracked order. The mark is cleared when the order is
+// removed, or with ClearCancelPending if the cancel failed
+func (t *OrderTracker) MarkCancelPending(orderHash string) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if _, found := t.orders[orderHash]; found {
+		t.pendingCancels[orderHash] = true
+	}
+}
+
+func (t *OrderTracker) ClearCancelPending(orderHash string) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	delete(t.pendingCancels, orderHash)
+}
+
+func (t *OrderTracker) IsCancelPending(orderHash string) bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.pendingCancels[orderHash]
+}
+
+// PendingCancels returns the orders with a pending cancel, sorted by order hash
+func (t *OrderTracker) PendingCancels() []string {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	orderHashes := make([]string, 0, len(t.pendingCancels))
+	for orderHash := range t.pendingCancels {
+		orderHashes = append(orderHashes, orderHash)
+	}
+	sort.Strings(orderHashes)
+	return orderHashes
+}
+
+func (t *OrderTracker) Order(orderHash string) (TrackedOrder, bool) {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	order, found := t.orders[orderHash]
+	return order, found
+}
+
+// OrderHashes returns the hashes of all the tracked orders, sorted
+func (t *OrderTracker) OrderHashes() []string {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	orderHashes := make([]string, 0, len(t.orders))
+	for orderHash := range t.orders {
+		orderHashes = append(orderHashes, orderHash)
+	}
+	sort.Strings(orderHashes)
+	return orderHashes
+}
+
+// ApplyOrderStates stops tracking the orders filled or cancelled according to the states (see
+// OrderStateFetcher.GetOrderStates), and returns them sorted by order hash. The orders without state are kept
+func (t *OrderTracker) ApplyOrderStates(states map[ethcommon.Hash]OrderState) []TrackedOrder {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	closed := make([]TrackedOrder, 0)
+	for orderHash, order := range t.orders {
+		state, found := states[ethcommon.HexToHash(orderHash)]
+		if !found || (state.Status != OrderStatusFilled && !state.IsCanceled()) {
+			continue
+		}
+		closed = append(closed, order)
+		delete(t.orders, orderHash)
+		delete(t.pendingCancels, orderHash)
+	}
+	sort.Slice(closed, func(i, j int) bool {
+		return closed[i].OrderHash < closed[j].OrderHash
+	})
+	return closed
+}
+
+// OpenOrders returns the tracked orders of the subaccount in the market, sorted by creation time
+func (t *OrderTracker) OpenOrders(marketId string, subaccountId string) []TrackedOrder {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	var orders []TrackedOrder
+	for _, order := range t.orders {
+		if order.MarketId == marketId && order.SubaccountId == subaccountId {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].OrderHash < orders[j].OrderHash
+		}
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	return orders
+}
+
+// SetExpiry sets the expiry height of the tracked order (zero removes it). It returns false if the order is not
+// tracked
+func (t *OrderTracker) SetExpiry(orderHash string, height int64) bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	order, found := t.orders[orderHash]
+	if !found {
+		return false
+	}
+	order.ExpiresAtHeight = height
+	t.orders[orderHash] = order
+	return true
+}
+
+// ExpiringOrders returns the tracked orders with an expiry height up to height and no pending cancel, sorted by
+// expiry height
+func (t *OrderTracker) ExpiringOrders(height int64) []TrackedOrder {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	var orders []TrackedOrder
+	for _, order := range t.orders {
+		if order.ExpiresAtHeight > 0 && order.ExpiresAtHeight <= height && !t.pendingCancels[order.OrderHash] {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].ExpiresAtHeight == orders[j].ExpiresAtHeight {
+			return orders[i].OrderHash < orders[j].OrderHash
+		}
+		return orders[i].ExpiresAtHeight < orders[j].ExpiresAtHeight
+	})
+	return orders
+}
+
+// LinkReplacement records that newOrderHash replaced oldOrderHash, and stops tracking the old order
+func (t *OrderTracker) LinkReplacement(oldOrderHash string, newOrderHash string) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.replacedBy[oldOrderHash] = newOrderHash
+	delete(t.orders, oldOrderHash)
+	delete(t.pendingCancels, oldOrderHash)
+	if order, found := t.orders[newOrderHash]; found {
+		order.ReplacesOrderHash = oldOrderHash
+		t.orders[newOrderHash] = order
+	}
+}
+
+// ReplacedBy returns the order that replaced the order hash
+func (t *OrderTracker) ReplacedBy(orderHash string) (string, bool) {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	newOrderHash, found := t.replacedBy[orderHash]
+	return newOrderHash, found
+}
+
+// LatestReplacement follows the replacement links from the order hash and returns the last order of the chain (the
+// order hash itself if it was never replaced)
+func (t *OrderTracker) LatestReplacement(orderHash string) string {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	visited := make(map[string]bool)
+	for !visited[orderHash] {
+		visited[orderHash] = true
+		newOrderHash, found := t.replacedBy[orderHash]
+		if !found {
+			break
+		}
+		orderHash = newOrderHash
+	}
+	return orderHash
+}
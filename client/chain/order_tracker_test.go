@@ -0,0 +1,61 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+func TestOrderTrackerOpenOrdersAndReplacements(t *testing.T) {
+	tracker := NewOrderTracker()
+	now := time.Unix(1700000000, 0)
+	tracker.SetClock(clock.NewFake(now))
+
+	tracker.TrackSpotOrder("0x02", &exchangetypes.SpotOrder{
+		MarketId:  riskSpotMarketId,
+		OrderType: exchangetypes.OrderType_SELL,
+		OrderInfo: exchangetypes.OrderInfo{SubaccountId: riskSubaccountId, Price: sdk.MustNewDecFromStr("2"), Quantity: sdk.MustNewDecFromStr("1")},
+	})
+	tracker.Track(TrackedOrder{OrderHash: "0x01", MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId, OrderType: exchangetypes.OrderType_BUY})
+	tracker.TrackDerivativeOrder("0x03", &exchangetypes.DerivativeOrder{
+		MarketId:  riskDerivativeMarketId,
+		OrderType: exchangetypes.OrderType_BUY,
+		Margin:    sdk.MustNewDecFromStr("10"),
+		OrderInfo: exchangetypes.OrderInfo{SubaccountId: riskSubaccountId},
+	})
+
+	orders := tracker.OpenOrders(riskSpotMarketId, riskSubaccountId)
+	assert.Len(t, orders, 2)
+	assert.Equal(t, "0x01", orders[0].OrderHash)
+	assert.True(t, orders[0].IsBuy())
+	assert.True(t, orders[0].Margin.IsZero())
+	assert.False(t, orders[1].IsBuy())
+
+	derivativeOrder, found := tracker.Order("0x03")
+	assert.True(t, found)
+	assert.True(t, derivativeOrder.IsDerivative)
+	assert.Equal(t, sdk.MustNewDecFromStr("10"), derivativeOrder.Margin)
+
+	tracker.Track(TrackedOrder{OrderHash: "0x04", MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId})
+	tracker.LinkReplacement("0x01", "0x04")
+	tracker.Track(TrackedOrder{OrderHash: "0x05", MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId})
+	tracker.LinkReplacement("0x04", "0x05")
+
+	_, found = tracker.Order("0x01")
+	assert.False(t, found)
+	replacement, found := tracker.ReplacedBy("0x01")
+	assert.True(t, found)
+	assert.Equal(t, "0x04", replacement)
+	assert.Equal(t, "0x05", tracker.LatestReplacement("0x01"))
+	assert.Equal(t, "0x02", tracker.LatestReplacement("0x02"))
+	latest, _ := tracker.Order("0x05")
+	assert.Equal(t, "0x04", latest.ReplacesOrderHash)
+
+	tracker.Remove("0x02")
+	assert.Len(t, tracker.OpenOrders(riskSpotMarketId, riskSubaccountId), 1)
+}
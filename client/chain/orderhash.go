@@ -6,7 +6,6 @@ import (
 
 	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
 	"github.com/ethereum/go-ethereum/common"
-	ethmath "github.com/ethereum/go-ethereum/common/math"
 	gethsigner "github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"golang.org/x/crypto/sha3"
 )
@@ -49,17 +48,15 @@ type OrderHashes struct {
 	Derivative []common.Hash
 }
 
-var domain = gethsigner.TypedDataDomain{
-	Name:              "Injective Protocol",
-	Version:           "2.0.0",
-	ChainId:           ethmath.NewHexOrDecimal256(888),
-	VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
-	Salt:              "0x0000000000000000000000000000000000000000000000000000000000000000",
+func (c *chainClient) UpdateSubaccountNonceFromChain() error {
+	return c.UpdateSubaccountNonceFromChainWithContext(context.Background())
 }
 
-func (c *chainClient) UpdateSubaccountNonceFromChain() error {
+// UpdateSubaccountNonceFromChainWithContext refreshes the trade nonces of all the known subaccounts, stopping at the
+// first query failing or cancelled by the context
+func (c *chainClient) UpdateSubaccountNonceFromChainWithContext(ctx context.Context) error {
 	for subaccountId := range c.subaccountToNonce {
-		err := c.SynchronizeSubaccountNonce(subaccountId)
+		err := c.SynchronizeSubaccountNonceWithContext(ctx, subaccountId)
 		if err != nil {
 			return err
 		}
@@ -68,7 +65,11 @@ func (c *chainClient) UpdateSubaccountNonceFromChain() error {
 }
 
 func (c *chainClient) SynchronizeSubaccountNonce(subaccountId common.Hash) error {
-	res, err := c.GetSubAccountNonce(context.Background(), subaccountId)
+	return c.SynchronizeSubaccountNonceWithContext(context.Background(), subaccountId)
+}
+
+func (c *chainClient) SynchronizeSubaccountNonceWithContext(ctx context.Context, subaccountId common.Hash) error {
+	res, err := c.GetSubAccountNonce(ctx, subaccountId)
 	if err != nil {
 		return err
 	}
@@ -77,101 +78,44 @@ func (c *chainClient) SynchronizeSubaccountNonce(subaccountId common.Hash) error
 }
 
 func (c *chainClient) ComputeOrderHashes(spotOrders []exchangetypes.SpotOrder, derivativeOrders []exchangetypes.DerivativeOrder, subaccountId common.Hash) (OrderHashes, error) {
+	return c.ComputeOrderHashesWithContext(context.Background(), spotOrders, derivativeOrders, subaccountId)
+}
+
+// ComputeOrderHashesWithContext is ComputeOrderHashes with a context bounding the subaccount nonce query done the
+// first time the subaccount is used. The hashes are not computed if the context is done
+func (c *chainClient) ComputeOrderHashesWithContext(ctx context.Context, spotOrders []exchangetypes.SpotOrder, derivativeOrders []exchangetypes.DerivativeOrder, subaccountId common.Hash) (OrderHashes, error) {
 	if len(spotOrders)+len(derivativeOrders) == 0 {
 		return OrderHashes{}, nil
 	}
+	if err := ctx.Err(); err != nil {
+		return OrderHashes{}, err
+	}
 
-	orderHashes := OrderHashes{}
 	// get nonce
 	if _, exist := c.subaccountToNonce[subaccountId]; !exist {
-		if err := c.SynchronizeSubaccountNonce(subaccountId); err != nil {
+		if err := c.SynchronizeSubaccountNonceWithContext(ctx, subaccountId); err != nil {
 			return OrderHashes{}, err
 		}
 	}
 
+	orderHashes := OrderHashes{}
+	orderDomain := c.OrderHashDomain()
 	nonce := c.subaccountToNonce[subaccountId]
 	for _, o := range spotOrders {
 		nonce += 1
-		triggerPrice := ""
-		if o.TriggerPrice != nil {
-			triggerPrice = o.TriggerPrice.String()
-		}
-		message := map[string]interface{}{
-			"MarketId": o.MarketId,
-			"OrderInfo": map[string]interface{}{
-				"SubaccountId": o.OrderInfo.SubaccountId,
-				"FeeRecipient": o.OrderInfo.FeeRecipient,
-				"Price":        o.OrderInfo.Price.String(),
-				"Quantity":     o.OrderInfo.Quantity.String(),
-			},
-			"Salt":         strconv.Itoa(int(nonce)),
-			"OrderType":    string(o.OrderType),
-			"TriggerPrice": triggerPrice,
-		}
-		typedData := gethsigner.TypedData{
-			Types:       eip712OrderTypes,
-			PrimaryType: "SpotOrder",
-			Domain:      domain,
-			Message:     message,
-		}
-		domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+		hash, err := ComputeSpotOrderHashWithDomain(o, nonce, orderDomain)
 		if err != nil {
 			return OrderHashes{}, err
 		}
-		typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
-		if err != nil {
-			return OrderHashes{}, err
-		}
-
-		w := sha3.NewLegacyKeccak256()
-		w.Write([]byte("\x19\x01"))
-		w.Write([]byte(domainSeparator))
-		w.Write([]byte(typedDataHash))
-
-		hash := common.BytesToHash(w.Sum(nil))
 		orderHashes.Spot = append(orderHashes.Spot, hash)
 	}
 
 	for _, o := range derivativeOrders {
 		nonce += 1
-		triggerPrice := ""
-		if o.TriggerPrice != nil {
-			triggerPrice = o.TriggerPrice.String()
-		}
-		message := map[string]interface{}{
-			"MarketId": o.MarketId,
-			"OrderInfo": map[string]interface{}{
-				"SubaccountId": o.OrderInfo.SubaccountId,
-				"FeeRecipient": o.OrderInfo.FeeRecipient,
-				"Price":        o.OrderInfo.Price.String(),
-				"Quantity":     o.OrderInfo.Quantity.String(),
-			},
-			"Margin":       o.Margin.String(),
-			"OrderType":    string(o.OrderType),
-			"TriggerPrice": triggerPrice,
-			"Salt":         strconv.Itoa(int(nonce)),
-		}
-		typedData := gethsigner.TypedData{
-			Types:       eip712OrderTypes,
-			PrimaryType: "DerivativeOrder",
-			Domain:      domain,
-			Message:     message,
-		}
-		domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
-		if err != nil {
-			return OrderHashes{}, err
-		}
-		typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+		hash, err := ComputeDerivativeOrderHashWithDomain(o, nonce, orderDomain)
 		if err != nil {
 			return OrderHashes{}, err
 		}
-
-		w := sha3.NewLegacyKeccak256()
-		w.Write([]byte("\x19\x01"))
-		w.Write([]byte(domainSeparator))
-		w.Write([]byte(typedDataHash))
-
-		hash := common.BytesToHash(w.Sum(nil))
 		orderHashes.Derivative = append(orderHashes.Derivative, hash)
 	}
 
@@ -179,3 +123,89 @@ func (c *chainClient) ComputeOrderHashes(spotOrders []exchangetypes.SpotOrder, d
 
 	return orderHashes, nil
 }
+
+// ComputeSpotOrderHash returns the hash the chain assigns to the spot order, where nonce is the subaccount trade nonce
+// after the order creation (i.e. the current nonce plus the position of the order in the tx, starting at 1). The hash
+// is computed with the default order hash domain
+func ComputeSpotOrderHash(o exchangetypes.SpotOrder, nonce uint32) (common.Hash, error) {
+	return ComputeSpotOrderHashWithDomain(o, nonce, DefaultOrderHashDomain())
+}
+
+// ComputeSpotOrderHashWithDomain is ComputeSpotOrderHash with the order hash domain of a network (see
+// NetworkOrderHashDomain)
+func ComputeSpotOrderHashWithDomain(o exchangetypes.SpotOrder, nonce uint32, orderDomain gethsigner.TypedDataDomain) (common.Hash, error) {
+	return computeEIP712OrderHash("SpotOrder", spotOrderMessage(o, nonce), orderDomain)
+}
+
+// ComputeDerivativeOrderHash returns the hash the chain assigns to the derivative order, see ComputeSpotOrderHash
+func ComputeDerivativeOrderHash(o exchangetypes.DerivativeOrder, nonce uint32) (common.Hash, error) {
+	return ComputeDerivativeOrderHashWithDomain(o, nonce, DefaultOrderHashDomain())
+}
+
+// ComputeDerivativeOrderHashWithDomain is ComputeDerivativeOrderHash with the order hash domain of a network
+func ComputeDerivativeOrderHashWithDomain(o exchangetypes.DerivativeOrder, nonce uint32, orderDomain gethsigner.TypedDataDomain) (common.Hash, error) {
+	return computeEIP712OrderHash("DerivativeOrder", derivativeOrderMessage(o, nonce), orderDomain)
+}
+
+func spotOrderMessage(o exchangetypes.SpotOrder, nonce uint32) map[string]interface{} {
+	triggerPrice := ""
+	if o.TriggerPrice != nil {
+		triggerPrice = o.TriggerPrice.String()
+	}
+	return map[string]interface{}{
+		"MarketId": o.MarketId,
+		"OrderInfo": map[string]interface{}{
+			"SubaccountId": o.OrderInfo.SubaccountId,
+			"FeeRecipient": o.OrderInfo.FeeRecipient,
+			"Price":        o.OrderInfo.Price.String(),
+			"Quantity":     o.OrderInfo.Quantity.String(),
+		},
+		"Salt":         strconv.Itoa(int(nonce)),
+		"OrderType":    string(o.OrderType),
+		"TriggerPrice": triggerPrice,
+	}
+}
+
+func derivativeOrderMessage(o exchangetypes.DerivativeOrder, nonce uint32) map[string]interface{} {
+	triggerPrice := ""
+	if o.TriggerPrice != nil {
+		triggerPrice = o.TriggerPrice.String()
+	}
+	return map[string]interface{}{
+		"MarketId": o.MarketId,
+		"OrderInfo": map[string]interface{}{
+			"SubaccountId": o.OrderInfo.SubaccountId,
+			"FeeRecipient": o.OrderInfo.FeeRecipient,
+			"Price":        o.OrderInfo.Price.String(),
+			"Quantity":     o.OrderInfo.Quantity.String(),
+		},
+		"Margin":       o.Margin.String(),
+		"OrderType":    string(o.OrderType),
+		"TriggerPrice": triggerPrice,
+		"Salt":         strconv.Itoa(int(nonce)),
+	}
+}
+
+func computeEIP712OrderHash(primaryType string, message map[string]interface{}, orderDomain gethsigner.TypedDataDomain) (common.Hash, error) {
+	typedData := gethsigner.TypedData{
+		Types:       eip712OrderTypes,
+		PrimaryType: primaryType,
+		Domain:      orderDomain,
+		Message:     message,
+	}
+	domainSeparator, err := OrderHashDomainSeparator(orderDomain)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	w := sha3.NewLegacyKeccak256()
+	w.Write([]byte("\x19\x01"))
+	w.Write(domainSeparator.Bytes())
+	w.Write([]byte(typedDataHash))
+
+	return common.BytesToHash(w.Sum(nil)), nil
+}
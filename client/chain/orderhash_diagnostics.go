@@ -0,0 +1,281 @@
+package chain
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethsigner "github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// orderHashNonceDrift is how far from the given nonce the diagnosis looks for the nonce the chain used
+const orderHashNonceDrift = 10
+
+// OrderHashStep is a 32 bytes value computed while hashing an order. Name is the path of the value (e.g.
+// "SpotOrder.OrderInfo.Price" for the encoding of the price, "SpotOrder.OrderInfo" for the hash of the order info)
+type OrderHashStep struct {
+	Name string
+	// Input is the value encoded by the step, empty for the type hashes and the struct hashes
+	Input string
+	Value common.Hash
+}
+
+// OrderHashTrace is the EIP712 hash of an order computed step by step: the domain separator ("EIP712Domain"), the
+// struct hash of the order with its type hash and field encodings, and the final digest ("digest"). The fields of a
+// struct come before its hash
+type OrderHashTrace struct {
+	Steps []OrderHashStep
+	Hash  common.Hash
+}
+
+// Step returns the step with the name, or nil if the trace has no such step
+func (t *OrderHashTrace) Step(name string) *OrderHashStep {
+	for i := range t.Steps {
+		if t.Steps[i].Name == name {
+			return &t.Steps[i]
+		}
+	}
+	return nil
+}
+
+// OrderHashDivergence is the first step whose value is not the expected one
+type OrderHashDivergence struct {
+	Step          string
+	ComputedInput string
+	ComputedValue common.Hash
+	ExpectedInput string
+	ExpectedValue common.Hash
+}
+
+func (d OrderHashDivergence) String() string {
+	if d.ComputedInput == "" && d.ExpectedInput == "" {
+		return fmt.Sprintf("%s: computed %s, expected %s", d.Step, d.ComputedValue.Hex(), d.ExpectedValue.Hex())
+	}
+	return fmt.Sprintf("%s: computed %q, expected %q", d.Step, d.ComputedInput, d.ExpectedInput)
+}
+
+// OrderHashDiagnosis explains why the hash computed for an order is not the expected one (e.g. the hash of the
+// order on chain)
+type OrderHashDiagnosis struct {
+	Trace    *OrderHashTrace
+	Expected common.Hash
+	Matches  bool
+	// Cause describes the change to the order that reproduces the expected hash, empty if none is found
+	Cause string
+	// FirstDivergence is the first step differing from the trace of the order reproducing the expected hash. When no
+	// cause is found, only the digest is known to diverge. Nil if the hashes match
+	FirstDivergence *OrderHashDivergence
+}
+
+func (d *OrderHashDiagnosis) String() string {
+	if d.Matches {
+		return fmt.Sprintf("the order hash %s is the expected one", d.Trace.Hash.Hex())
+	}
+	if d.Cause == "" {
+		return fmt.Sprintf("the order hash %s is not the expected %s, and no known cause reproduces it: check the order hash domain and the order fields used", d.Trace.Hash.Hex(), d.Expected.Hex())
+	}
+	return fmt.Sprintf("the order hash %s is not the expected %s: %s (first divergence at %s)", d.Trace.Hash.Hex(), d.Expected.Hex(), d.Cause, d.FirstDivergence.String())
+}
+
+// TraceSpotOrderHash computes the hash of the spot order with the order hash domain like
+// ComputeSpotOrderHashWithDomain, keeping every step
+func TraceSpotOrderHash(o exchangetypes.SpotOrder, nonce uint32, orderDomain gethsigner.TypedDataDomain) (*OrderHashTrace, error) {
+	return traceEIP712OrderHash("SpotOrder", spotOrderMessage(o, nonce), orderDomain)
+}
+
+// TraceDerivativeOrderHash is TraceSpotOrderHash for a derivative order
+func TraceDerivativeOrderHash(o exchangetypes.DerivativeOrder, nonce uint32, orderDomain gethsigner.TypedDataDomain) (*OrderHashTrace, error) {
+	return traceEIP712OrderHash("DerivativeOrder", derivativeOrderMessage(o, nonce), orderDomain)
+}
+
+// CompareOrderHashTraces returns the first step of the computed trace whose value is not the value of the step with
+// the same name in the expected trace (e.g. a trace logged by another implementation), or nil if all the steps match
+func CompareOrderHashTraces(computed *OrderHashTrace, expected *OrderHashTrace) *OrderHashDivergence {
+	for _, step := range computed.Steps {
+		divergence := &OrderHashDivergence{Step: step.Name, ComputedInput: step.Input, ComputedValue: step.Value}
+		expectedStep := expected.Step(step.Name)
+		if expectedStep == nil {
+			return divergence
+		}
+		if expectedStep.Value != step.Value {
+			divergence.ExpectedInput = expectedStep.Input
+			divergence.ExpectedValue = expectedStep.Value
+			return divergence
+		}
+	}
+	return nil
+}
+
+// DiagnoseSpotOrderHash compares the hash of a spot order, computed with the order hash domain (e.g.
+// ChainClient.OrderHashDomain), with the hash expected for it, usually the hash given by the chain for an order
+// rejected or not found. When they differ, the usual causes of a mismatch (a trade nonce out of sync, the fee recipient
+// defaulted by the chain to the sender, the trigger price encoding, a domain other than the default one) are tried to
+// find the one reproducing the expected hash
+func DiagnoseSpotOrderHash(o exchangetypes.SpotOrder, nonce uint32, orderDomain gethsigner.TypedDataDomain, expected common.Hash) (*OrderHashDiagnosis, error) {
+	return diagnoseOrderHash("SpotOrder", spotOrderMessage(o, nonce), nonce, orderDomain, expected)
+}
+
+// DiagnoseDerivativeOrderHash is DiagnoseSpotOrderHash for a derivative order
+func DiagnoseDerivativeOrderHash(o exchangetypes.DerivativeOrder, nonce uint32, orderDomain gethsigner.TypedDataDomain, expected common.Hash) (*OrderHashDiagnosis, error) {
+	return diagnoseOrderHash("DerivativeOrder", derivativeOrderMessage(o, nonce), nonce, orderDomain, expected)
+}
+
+// orderHashVariation is the message and domain of an order changed in the way the chain may have hashed it
+type orderHashVariation struct {
+	cause       string
+	message     map[string]interface{}
+	orderDomain gethsigner.TypedDataDomain
+}
+
+func diagnoseOrderHash(primaryType string, message map[string]interface{}, nonce uint32, orderDomain gethsigner.TypedDataDomain, expected common.Hash) (*OrderHashDiagnosis, error) {
+	trace, err := traceEIP712OrderHash(primaryType, message, orderDomain)
+	if err != nil {
+		return nil, err
+	}
+	diagnosis := &OrderHashDiagnosis{
+		Trace:    trace,
+		Expected: expected,
+		Matches:  trace.Hash == expected,
+	}
+	if diagnosis.Matches {
+		return diagnosis, nil
+	}
+
+	for _, variation := range orderHashVariations(message, nonce, orderDomain) {
+		variationTrace, err := traceEIP712OrderHash(primaryType, variation.message, variation.orderDomain)
+		if err != nil {
+			continue
+		}
+		if variationTrace.Hash == expected {
+			diagnosis.Cause = variation.cause
+			diagnosis.FirstDivergence = CompareOrderHashTraces(trace, variationTrace)
+			return diagnosis, nil
+		}
+	}
+	diagnosis.FirstDivergence = &OrderHashDivergence{Step: "digest", ComputedValue: trace.Hash, ExpectedValue: expected}
+	return diagnosis, nil
+}
+
+func orderHashVariations(message map[string]interface{}, nonce uint32, orderDomain gethsigner.TypedDataDomain) []orderHashVariation {
+	var variations []orderHashVariation
+	if defaultDomain := DefaultOrderHashDomain(); CompareOrderHashDomains(orderDomain, defaultDomain) != nil {
+		variations = append(variations, orderHashVariation{
+			cause:       "the chain hashed the order with the default order hash domain",
+			message:     message,
+			orderDomain: defaultDomain,
+		})
+	}
+	for drift := -orderHashNonceDrift; drift <= orderHashNonceDrift; drift++ {
+		otherNonce := int64(nonce) + int64(drift)
+		if drift == 0 || otherNonce < 0 {
+			continue
+		}
+		variation := copyOrderMessage(message)
+		variation["Salt"] = strconv.FormatInt(otherNonce, 10)
+		variations = append(variations, orderHashVariation{
+			cause:       fmt.Sprintf("the chain hashed the order with the nonce %d instead of %d, the subaccount nonce is out of sync", otherNonce, nonce),
+			message:     variation,
+			orderDomain: orderDomain,
+		})
+	}
+
+	orderInfo := message["OrderInfo"].(map[string]interface{})
+	if feeRecipient := orderInfo["FeeRecipient"].(string); feeRecipient == "" {
+		subaccountId := orderInfo["SubaccountId"].(string)
+		if len(common.FromHex(subaccountId)) == common.HashLength {
+			sender := exchangetypes.SubaccountIDToSdkAddress(common.HexToHash(subaccountId)).String()
+			variation := copyOrderMessage(message)
+			variation["OrderInfo"].(map[string]interface{})["FeeRecipient"] = sender
+			variations = append(variations, orderHashVariation{
+				cause:       fmt.Sprintf("the chain hashed the order with the sender %s as fee recipient", sender),
+				message:     variation,
+				orderDomain: orderDomain,
+			})
+		}
+	} else {
+		variation := copyOrderMessage(message)
+		variation["OrderInfo"].(map[string]interface{})["FeeRecipient"] = ""
+		variations = append(variations, orderHashVariation{
+			cause:       "the chain hashed the order without fee recipient",
+			message:     variation,
+			orderDomain: orderDomain,
+		})
+	}
+
+	variation := copyOrderMessage(message)
+	if message["TriggerPrice"].(string) == "" {
+		variation["TriggerPrice"] = "0.000000000000000000"
+		variations = append(variations, orderHashVariation{cause: "the chain hashed the order with a zero trigger price", message: variation, orderDomain: orderDomain})
+	} else {
+		variation["TriggerPrice"] = ""
+		variations = append(variations, orderHashVariation{cause: "the chain hashed the order without trigger price", message: variation, orderDomain: orderDomain})
+	}
+	return variations
+}
+
+func copyOrderMessage(message map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(message))
+	for key, value := range message {
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = copyOrderMessage(nested)
+		}
+		copied[key] = value
+	}
+	return copied
+}
+
+// traceEIP712OrderHash computes the same hash as computeEIP712OrderHash, encoding each field like
+// TypedData.EncodeData
+func traceEIP712OrderHash(primaryType string, message map[string]interface{}, orderDomain gethsigner.TypedDataDomain) (*OrderHashTrace, error) {
+	trace := &OrderHashTrace{}
+	separator, err := traceEIP712Struct(trace, "EIP712Domain", "EIP712Domain", orderDomain.Map())
+	if err != nil {
+		return nil, err
+	}
+	structHash, err := traceEIP712Struct(trace, primaryType, primaryType, message)
+	if err != nil {
+		return nil, err
+	}
+	trace.Hash = common.BytesToHash(crypto.Keccak256([]byte("\x19\x01"), separator.Bytes(), structHash.Bytes()))
+	trace.Steps = append(trace.Steps, OrderHashStep{Name: "digest", Value: trace.Hash})
+	return trace, nil
+}
+
+func traceEIP712Struct(trace *OrderHashTrace, name string, structType string, data map[string]interface{}) (common.Hash, error) {
+	typedData := gethsigner.TypedData{Types: eip712OrderTypes}
+	typeHash := common.BytesToHash(typedData.TypeHash(structType))
+	trace.Steps = append(trace.Steps, OrderHashStep{Name: name + ".typeHash", Input: string(typedData.EncodeType(structType)), Value: typeHash})
+
+	encoded := typeHash.Bytes()
+	for _, field := range eip712OrderTypes[structType] {
+		fieldName := name + "." + field.Name
+		value := data[field.Name]
+		var fieldValue common.Hash
+		if _, isStruct := eip712OrderTypes[field.Type]; isStruct {
+			nested, ok := value.(map[string]interface{})
+			if !ok {
+				return common.Hash{}, errors.Errorf("%s is not a %s", fieldName, field.Type)
+			}
+			var err error
+			if fieldValue, err = traceEIP712Struct(trace, fieldName, field.Type, nested); err != nil {
+				return common.Hash{}, err
+			}
+		} else {
+			fieldBytes, err := typedData.EncodePrimitiveValue(field.Type, value, 1)
+			if err != nil {
+				return common.Hash{}, errors.Wrapf(err, "failed to encode %s", fieldName)
+			}
+			fieldValue = common.BytesToHash(fieldBytes)
+			trace.Steps = append(trace.Steps, OrderHashStep{Name: fieldName, Input: fmt.Sprint(value), Value: fieldValue})
+		}
+		encoded = append(encoded, fieldValue.Bytes()...)
+	}
+
+	structHash := common.BytesToHash(crypto.Keccak256(encoded))
+	trace.Steps = append(trace.Steps, OrderHashStep{Name: name, Value: structHash})
+	return structHash, nil
+}
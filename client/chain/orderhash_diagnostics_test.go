@@ -0,0 +1,88 @@
+package chain
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func TestTraceOrderHash(t *testing.T) {
+	spotOrder := orderGuardSpotOrderMsg(exchangetypes.OrderType_BUY, "2", "10").Order
+	spotHash, err := ComputeSpotOrderHash(spotOrder, 5)
+	assert.NoError(t, err)
+	trace, err := TraceSpotOrderHash(spotOrder, 5, DefaultOrderHashDomain())
+	assert.NoError(t, err)
+	assert.Equal(t, spotHash, trace.Hash)
+	assert.Equal(t, "digest", trace.Steps[len(trace.Steps)-1].Name)
+	assert.Equal(t, "2.000000000000000000", trace.Step("SpotOrder.OrderInfo.Price").Input)
+	assert.Equal(t, "SpotOrder(string MarketId,OrderInfo OrderInfo,string Salt,string OrderType,string TriggerPrice)OrderInfo(string SubaccountId,string FeeRecipient,string Price,string Quantity)", trace.Step("SpotOrder.typeHash").Input)
+	assert.NotNil(t, trace.Step("EIP712Domain"))
+	assert.Nil(t, trace.Step("SpotOrder.Margin"))
+
+	derivativeOrder := riskDerivativeOrderMsg(exchangetypes.OrderType_SELL, "100", "2", "50").Order
+	derivativeHash, err := ComputeDerivativeOrderHash(derivativeOrder, 6)
+	assert.NoError(t, err)
+	derivativeTrace, err := TraceDerivativeOrderHash(derivativeOrder, 6, DefaultOrderHashDomain())
+	assert.NoError(t, err)
+	assert.Equal(t, derivativeHash, derivativeTrace.Hash)
+	assert.Equal(t, "50.000000000000000000", derivativeTrace.Step("DerivativeOrder.Margin").Input)
+
+	otherTrace, err := TraceDerivativeOrderHash(derivativeOrder, 7, DefaultOrderHashDomain())
+	assert.NoError(t, err)
+	assert.Nil(t, CompareOrderHashTraces(derivativeTrace, derivativeTrace))
+	divergence := CompareOrderHashTraces(derivativeTrace, otherTrace)
+	assert.Equal(t, "DerivativeOrder.Salt", divergence.Step)
+	assert.Equal(t, `DerivativeOrder.Salt: computed "6", expected "7"`, divergence.String())
+}
+
+func TestDiagnoseOrderHash(t *testing.T) {
+	spotOrder := orderGuardSpotOrderMsg(exchangetypes.OrderType_BUY, "2", "10").Order
+	spotHash, err := ComputeSpotOrderHash(spotOrder, 5)
+	assert.NoError(t, err)
+
+	diagnosis, err := DiagnoseSpotOrderHash(spotOrder, 5, DefaultOrderHashDomain(), spotHash)
+	assert.NoError(t, err)
+	assert.True(t, diagnosis.Matches)
+	assert.Nil(t, diagnosis.FirstDivergence)
+
+	// the local nonce is behind the chain one
+	diagnosis, err = DiagnoseSpotOrderHash(spotOrder, 3, DefaultOrderHashDomain(), spotHash)
+	assert.NoError(t, err)
+	assert.False(t, diagnosis.Matches)
+	assert.Equal(t, "the chain hashed the order with the nonce 5 instead of 3, the subaccount nonce is out of sync", diagnosis.Cause)
+	assert.Equal(t, "SpotOrder.Salt", diagnosis.FirstDivergence.Step)
+
+	// the chain uses the sender as fee recipient of the orders without one
+	spotOrder.OrderInfo.FeeRecipient = ""
+	sender := exchangetypes.SubaccountIDToSdkAddress(ethcommon.HexToHash(riskSubaccountId))
+	defaulted := spotOrder
+	defaulted.OrderInfo.FeeRecipient = sender.String()
+	defaultedHash, err := ComputeSpotOrderHash(defaulted, 5)
+	assert.NoError(t, err)
+	diagnosis, err = DiagnoseSpotOrderHash(spotOrder, 5, DefaultOrderHashDomain(), defaultedHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "the chain hashed the order with the sender "+sender.String()+" as fee recipient", diagnosis.Cause)
+	assert.Equal(t, "SpotOrder.OrderInfo.FeeRecipient", diagnosis.FirstDivergence.Step)
+
+	derivativeOrder := riskDerivativeOrderMsg(exchangetypes.OrderType_SELL, "100", "2", "50").Order
+	zeroTrigger := derivativeOrder
+	zero := sdk.ZeroDec()
+	zeroTrigger.TriggerPrice = &zero
+	zeroTriggerHash, err := ComputeDerivativeOrderHash(zeroTrigger, 6)
+	assert.NoError(t, err)
+	diagnosis, err = DiagnoseDerivativeOrderHash(derivativeOrder, 6, DefaultOrderHashDomain(), zeroTriggerHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "the chain hashed the order with a zero trigger price", diagnosis.Cause)
+	assert.Equal(t, `DerivativeOrder.TriggerPrice: computed "", expected "0.000000000000000000"`, diagnosis.FirstDivergence.String())
+
+	// an unrelated hash has no known cause
+	diagnosis, err = DiagnoseDerivativeOrderHash(derivativeOrder, 6, DefaultOrderHashDomain(), spotHash)
+	assert.NoError(t, err)
+	assert.Empty(t, diagnosis.Cause)
+	assert.Equal(t, "digest", diagnosis.FirstDivergence.Step)
+	assert.Contains(t, diagnosis.String(), "no known cause reproduces it")
+}
@@ -0,0 +1,115 @@
+package chain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	gethsigner "github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+// ErrOrderHashDomainMismatch is returned when two order hash domains have different domain separators, so the same
+// order has different hashes with each of them
+var ErrOrderHashDomainMismatch = errors.New("order hash domain mismatch")
+
+const orderHashDomainSalt = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+// the domain separator only depends on the domain, so it is hashed once per domain instead of for every order
+var domainSeparators sync.Map
+
+// DefaultOrderHashDomain returns the EIP712 domain of the order hashes in all the Injective networks
+func DefaultOrderHashDomain() gethsigner.TypedDataDomain {
+	return NetworkOrderHashDomain(common.Network{})
+}
+
+// NetworkOrderHashDomain returns the EIP712 domain of the order hashes in the network, with the default values for
+// the ones not set in the network
+func NetworkOrderHashDomain(network common.Network) gethsigner.TypedDataDomain {
+	orderDomain := gethsigner.TypedDataDomain{
+		Name:              network.OrderHashDomainName,
+		Version:           network.OrderHashDomainVersion,
+		ChainId:           ethmath.NewHexOrDecimal256(network.OrderHashChainId),
+		VerifyingContract: network.ExchangeAddress,
+		Salt:              orderHashDomainSalt,
+	}
+	if orderDomain.Name == "" {
+		orderDomain.Name = common.DefaultOrderHashDomainName
+	}
+	if orderDomain.Version == "" {
+		orderDomain.Version = common.DefaultOrderHashDomainVersion
+	}
+	if network.OrderHashChainId == 0 {
+		orderDomain.ChainId = ethmath.NewHexOrDecimal256(common.DefaultOrderHashChainId)
+	}
+	if orderDomain.VerifyingContract == "" {
+		orderDomain.VerifyingContract = common.DefaultExchangeAddress
+	}
+	return orderDomain
+}
+
+// OrderHashDomainSeparator returns the EIP712 domain separator of the order hash domain
+func OrderHashDomainSeparator(orderDomain gethsigner.TypedDataDomain) (ethcommon.Hash, error) {
+	key := orderHashDomainKey(orderDomain)
+	if separator, found := domainSeparators.Load(key); found {
+		return separator.(ethcommon.Hash), nil
+	}
+
+	typedData := gethsigner.TypedData{Types: eip712OrderTypes, Domain: orderDomain}
+	separator, err := typedData.HashStruct("EIP712Domain", orderDomain.Map())
+	if err != nil {
+		return ethcommon.Hash{}, errors.Wrap(err, "failed to hash the order hash domain")
+	}
+	domainSeparators.Store(key, ethcommon.BytesToHash(separator))
+	return ethcommon.BytesToHash(separator), nil
+}
+
+// CompareOrderHashDomains returns an ErrOrderHashDomainMismatch with the fields that differ if the domain separators
+// of the domains are not equal
+func CompareOrderHashDomains(computed gethsigner.TypedDataDomain, expected gethsigner.TypedDataDomain) error {
+	computedSeparator, err := OrderHashDomainSeparator(computed)
+	if err != nil {
+		return err
+	}
+	expectedSeparator, err := OrderHashDomainSeparator(expected)
+	if err != nil {
+		return err
+	}
+	if computedSeparator == expectedSeparator {
+		return nil
+	}
+
+	var differences []string
+	addDifference := func(field string, computedValue string, expectedValue string) {
+		if computedValue != expectedValue {
+			differences = append(differences, fmt.Sprintf("%s %q instead of %q", field, computedValue, expectedValue))
+		}
+	}
+	addDifference("name", computed.Name, expected.Name)
+	addDifference("version", computed.Version, expected.Version)
+	addDifference("chainId", domainChainId(computed), domainChainId(expected))
+	addDifference("verifyingContract", strings.ToLower(computed.VerifyingContract), strings.ToLower(expected.VerifyingContract))
+	addDifference("salt", strings.ToLower(computed.Salt), strings.ToLower(expected.Salt))
+	return errors.Wrapf(ErrOrderHashDomainMismatch, "domain separator %s instead of %s: %s", computedSeparator.Hex(), expectedSeparator.Hex(), strings.Join(differences, ", "))
+}
+
+func domainChainId(orderDomain gethsigner.TypedDataDomain) string {
+	if orderDomain.ChainId == nil {
+		return ""
+	}
+	return (*big.Int)(orderDomain.ChainId).String()
+}
+
+func orderHashDomainKey(orderDomain gethsigner.TypedDataDomain) string {
+	return strings.Join([]string{orderDomain.Name, orderDomain.Version, domainChainId(orderDomain), orderDomain.VerifyingContract, orderDomain.Salt}, "\x00")
+}
+
+// OrderHashDomain returns the EIP712 domain the client computes the order hashes with, from the network preset
+func (c *chainClient) OrderHashDomain() gethsigner.TypedDataDomain {
+	return NetworkOrderHashDomain(c.network)
+}
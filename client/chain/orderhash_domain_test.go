@@ -0,0 +1,100 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	gethsigner "github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+func TestNetworkOrderHashDomain(t *testing.T) {
+	defaultDomain := gethsigner.TypedDataDomain{
+		Name:              "Injective Protocol",
+		Version:           "2.0.0",
+		ChainId:           ethmath.NewHexOrDecimal256(888),
+		VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+		Salt:              "0x0000000000000000000000000000000000000000000000000000000000000000",
+	}
+	assert.Equal(t, defaultDomain, DefaultOrderHashDomain())
+	assert.Equal(t, defaultDomain, NetworkOrderHashDomain(common.LoadNetwork("mainnet", "lb")))
+
+	network := common.Network{Name: "custom", OrderHashChainId: 1776, OrderHashDomainVersion: "3.0.0"}
+	orderDomain := NetworkOrderHashDomain(network)
+	assert.Equal(t, "Injective Protocol", orderDomain.Name)
+	assert.Equal(t, "3.0.0", orderDomain.Version)
+	assert.Equal(t, "1776", domainChainId(orderDomain))
+	assert.NoError(t, ValidateOrderHashDomain(network, orderDomain))
+}
+
+func TestOrderHashDomainSeparator(t *testing.T) {
+	orderDomain := DefaultOrderHashDomain()
+	typedData := gethsigner.TypedData{Types: eip712OrderTypes, Domain: orderDomain}
+	expected, err := typedData.HashStruct("EIP712Domain", orderDomain.Map())
+	assert.NoError(t, err)
+
+	// the second call gets the cached separator
+	for i := 0; i < 2; i++ {
+		separator, err := OrderHashDomainSeparator(orderDomain)
+		assert.NoError(t, err)
+		assert.Equal(t, ethcommon.BytesToHash(expected), separator)
+	}
+
+	otherDomain := orderDomain
+	otherDomain.Version = "3.0.0"
+	otherSeparator, err := OrderHashDomainSeparator(otherDomain)
+	assert.NoError(t, err)
+	assert.NotEqual(t, ethcommon.BytesToHash(expected), otherSeparator)
+
+	trace, err := TraceSpotOrderHash(exchangetypes.SpotOrder{}, 1, otherDomain)
+	assert.NoError(t, err)
+	assert.Equal(t, otherSeparator, trace.Step("EIP712Domain").Value)
+}
+
+func TestCompareOrderHashDomains(t *testing.T) {
+	orderDomain := DefaultOrderHashDomain()
+	assert.NoError(t, CompareOrderHashDomains(orderDomain, DefaultOrderHashDomain()))
+
+	// the verifying contract is an address, its case does not change the separator
+	lowerCaseContract := orderDomain
+	lowerCaseContract.VerifyingContract = "0xcccccccccccccccccccccccccccccccccccccccc"
+	assert.NoError(t, CompareOrderHashDomains(lowerCaseContract, orderDomain))
+
+	otherDomain := orderDomain
+	otherDomain.ChainId = ethmath.NewHexOrDecimal256(1776)
+	otherDomain.VerifyingContract = "0x0000000000000000000000000000000000000001"
+	err := CompareOrderHashDomains(otherDomain, orderDomain)
+	assert.True(t, errors.Is(err, ErrOrderHashDomainMismatch))
+	assert.ErrorContains(t, err, `chainId "1776" instead of "888", verifyingContract "0x0000000000000000000000000000000000000001" instead of "0xcccccccccccccccccccccccccccccccccccccccc"`)
+}
+
+func TestComputeOrderHashesUsesTheNetworkDomain(t *testing.T) {
+	network := common.LoadNetwork("mainnet", "lb")
+	network.ExchangeAddress = "0x0000000000000000000000000000000000000001"
+	c := &chainClient{
+		network:           network,
+		opts:              common.DefaultClientOptions(),
+		subaccountToNonce: map[ethcommon.Hash]uint32{AuctionSubaccountID: 4},
+	}
+	order := orderGuardSpotOrderMsg(exchangetypes.OrderType_BUY, "2", "10").Order
+
+	hashes, err := c.ComputeOrderHashes([]exchangetypes.SpotOrder{order}, nil, AuctionSubaccountID)
+	assert.NoError(t, err)
+	expected, err := ComputeSpotOrderHashWithDomain(order, 5, c.OrderHashDomain())
+	assert.NoError(t, err)
+	assert.Equal(t, []ethcommon.Hash{expected}, hashes.Spot)
+
+	// an order hashed by the chain with the default domain
+	defaultHash, err := ComputeSpotOrderHash(order, 5)
+	assert.NoError(t, err)
+	assert.NotEqual(t, defaultHash, expected)
+	diagnosis, err := DiagnoseSpotOrderHash(order, 5, c.OrderHashDomain(), defaultHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "the chain hashed the order with the default order hash domain", diagnosis.Cause)
+	assert.Equal(t, "EIP712Domain.verifyingContract", diagnosis.FirstDivergence.Step)
+}
@@ -0,0 +1,86 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+func benchmarkDerivativeOrder() exchangetypes.DerivativeOrder {
+	return exchangetypes.DerivativeOrder{
+		MarketId: riskDerivativeMarketId,
+		OrderInfo: exchangetypes.OrderInfo{
+			SubaccountId: riskSubaccountId,
+			FeeRecipient: "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r",
+			Price:        sdk.MustNewDecFromStr("25000000000"),
+			Quantity:     sdk.MustNewDecFromStr("0.1"),
+		},
+		OrderType: exchangetypes.OrderType_BUY,
+		Margin:    sdk.MustNewDecFromStr("2500000000"),
+	}
+}
+
+func TestComputeOrderHashesWithContextStopsWhenTheContextIsDone(t *testing.T) {
+	c := &chainClient{network: common.Network{Name: "custom"}, opts: common.DefaultClientOptions()}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the subaccount nonce is not queried
+	_, err := c.ComputeOrderHashesWithContext(ctx, []exchangetypes.SpotOrder{{}}, nil, AuctionSubaccountID)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func BenchmarkComputeSpotOrderHash(b *testing.B) {
+	derivativeOrder := benchmarkDerivativeOrder()
+	order := exchangetypes.SpotOrder{
+		MarketId:  riskSpotMarketId,
+		OrderInfo: derivativeOrder.OrderInfo,
+		OrderType: exchangetypes.OrderType_SELL_PO,
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ComputeSpotOrderHash(order, uint32(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkComputeDerivativeOrderHash(b *testing.B) {
+	order := benchmarkDerivativeOrder()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ComputeDerivativeOrderHash(order, uint32(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBatchUpdateOrdersValidateBasic(b *testing.B) {
+	orders := make([]*exchangetypes.DerivativeOrder, 0, 20)
+	for i := 0; i < 20; i++ {
+		order := benchmarkDerivativeOrder()
+		orders = append(orders, &order)
+	}
+	// the sender is the owner of the subaccount
+	sender := sdk.AccAddress(ethcommon.HexToAddress(riskSubaccountId[:42]).Bytes())
+	msg := &exchangetypes.MsgBatchUpdateOrders{
+		Sender:                   sender.String(),
+		DerivativeOrdersToCreate: orders,
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := msg.ValidateBasic(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
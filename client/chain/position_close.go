@@ -0,0 +1,149 @@
+package chain
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// ClosePositionResult is the reduce-only order broadcasted by PositionCloser.ClosePosition. RemainingQuantity is the
+// position quantity left open, including any dust below the quantity tick size
+type ClosePositionResult struct {
+	TxHash            string
+	MarketId          string
+	OrderType         exchangetypes.OrderType
+	Price             sdk.Dec
+	Quantity          sdk.Dec
+	RemainingQuantity sdk.Dec
+}
+
+type PositionCloserConfig struct {
+	// Slippage is the fraction applied to the reference price to calculate the worst price of the closing orders (5%
+	// by default). It is not used in binary options markets
+	Slippage sdk.Dec
+}
+
+// PositionCloser closes all or part of a derivative or binary options position with a reduce-only market order
+type PositionCloser struct {
+	chainClient ChainClient
+	config      PositionCloserConfig
+}
+
+func NewPositionCloser(chainClient ChainClient, config PositionCloserConfig) *PositionCloser {
+	if config.Slippage.IsNil() {
+		config.Slippage = sdk.MustNewDecFromStr(defaultFlattenSlippage)
+	}
+
+	return &PositionCloser{
+		chainClient: chainClient,
+		config:      config,
+	}
+}
+
+// ClosePosition reads the position of the subaccount in the market and closes the fraction (in (0, 1]) of its
+// quantity, rounded down to the market quantity tick size. The order side is the opposite of the position, and its
+// worst price is the top of the book (or the mark price) moved by the slippage. A fraction of one closes the whole
+// position when its quantity is a multiple of the tick size
+func (p *PositionCloser) ClosePosition(ctx context.Context, subaccountId string, marketId string, fraction sdk.Dec) (*ClosePositionResult, error) {
+	if fraction.IsNil() || !fraction.IsPositive() || fraction.GT(sdk.OneDec()) {
+		return nil, errors.Errorf("invalid fraction %v: it must be greater than zero and at most one", fraction)
+	}
+
+	positionRes, err := p.chainClient.FetchChainSubaccountPositionInMarket(ctx, subaccountId, marketId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch the position in market %s", marketId)
+	}
+	position := positionRes.State
+	if position == nil || position.Quantity.IsNil() || !position.Quantity.IsPositive() {
+		return nil, errors.Errorf("subaccount %s has no position in market %s", subaccountId, marketId)
+	}
+
+	binaryOptionsMarket, err := p.binaryOptionsMarket(ctx, marketId)
+	if err != nil {
+		return nil, err
+	}
+
+	var quantityTickSize, worstPrice sdk.Dec
+	if binaryOptionsMarket != nil {
+		quantityTickSize = binaryOptionsMarket.MinQuantityTickSize
+		worstPrice, err = binaryOptionsClosingOrderPrice(binaryOptionsMarket, position.IsLong)
+	} else {
+		marketRes, fetchErr := p.chainClient.FetchChainDerivativeMarket(ctx, marketId)
+		if fetchErr != nil {
+			return nil, errors.Wrapf(fetchErr, "failed to fetch the derivative market %s", marketId)
+		}
+		if marketRes.Market == nil || marketRes.Market.Market == nil {
+			return nil, errors.Errorf("derivative market %s not found", marketId)
+		}
+		quantityTickSize = marketRes.Market.Market.MinQuantityTickSize
+		worstPrice, err = closingOrderPrice(marketRes.Market, position.IsLong, p.config.Slippage)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := floorToTickSize(position.Quantity.Mul(fraction), quantityTickSize)
+	if !quantity.IsPositive() {
+		return nil, errors.Errorf("the quantity to close %s in market %s is below the quantity tick size %s", position.Quantity.Mul(fraction), marketId, quantityTickSize)
+	}
+
+	result := &ClosePositionResult{
+		MarketId:          marketId,
+		OrderType:         closingOrderType(position.IsLong),
+		Price:             worstPrice,
+		Quantity:          quantity,
+		RemainingQuantity: position.Quantity.Sub(quantity),
+	}
+
+	var msg sdk.Msg
+	sender := p.chainClient.FromAddress()
+	if binaryOptionsMarket != nil {
+		msg = exchangetypes.NewMsgCreateBinaryOptionsMarketOrder(sender, binaryOptionsMarket, subaccountId, sender.String(), worstPrice, quantity, result.OrderType, true)
+	} else {
+		msg = &exchangetypes.MsgCreateDerivativeMarketOrder{
+			Sender: sender.String(),
+			Order: exchangetypes.DerivativeOrder{
+				MarketId: marketId,
+				OrderInfo: exchangetypes.OrderInfo{
+					SubaccountId: subaccountId,
+					FeeRecipient: sender.String(),
+					Price:        worstPrice,
+					Quantity:     quantity,
+				},
+				OrderType: result.OrderType,
+				// a zero margin makes the order reduce-only
+				Margin: sdk.ZeroDec(),
+			},
+		}
+	}
+
+	res, err := p.chainClient.SyncBroadcastMsg(msg)
+	if err != nil {
+		return nil, err
+	}
+	if res.TxResponse != nil {
+		result.TxHash = res.TxResponse.TxHash
+		if err := NewTxError(res.TxResponse); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// binaryOptionsMarket returns the active binary options market with the id, or nil if it is not a binary options
+// market
+func (p *PositionCloser) binaryOptionsMarket(ctx context.Context, marketId string) (*exchangetypes.BinaryOptionsMarket, error) {
+	res, err := p.chainClient.FetchChainBinaryOptionsMarkets(ctx, "Active")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the binary options markets")
+	}
+	for _, market := range res.Markets {
+		if market.MarketId == marketId {
+			return market, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,100 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+type positionCloseTestChainClient struct {
+	MockChainClient
+	position        *exchangetypes.Position
+	market          *exchangetypes.FullDerivativeMarket
+	binaryMarkets   []*exchangetypes.BinaryOptionsMarket
+	broadcastedMsgs []sdk.Msg
+}
+
+func (c *positionCloseTestChainClient) FetchChainSubaccountPositionInMarket(ctx context.Context, subaccountId string, marketId string) (*exchangetypes.QuerySubaccountPositionInMarketResponse, error) {
+	return &exchangetypes.QuerySubaccountPositionInMarketResponse{State: c.position}, nil
+}
+
+func (c *positionCloseTestChainClient) FetchChainDerivativeMarket(ctx context.Context, marketId string) (*exchangetypes.QueryDerivativeMarketResponse, error) {
+	return &exchangetypes.QueryDerivativeMarketResponse{Market: c.market}, nil
+}
+
+func (c *positionCloseTestChainClient) FetchChainBinaryOptionsMarkets(ctx context.Context, status string) (*exchangetypes.QueryBinaryMarketsResponse, error) {
+	return &exchangetypes.QueryBinaryMarketsResponse{Markets: c.binaryMarkets}, nil
+}
+
+func (c *positionCloseTestChainClient) SyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	c.broadcastedMsgs = append(c.broadcastedMsgs, msgs...)
+	return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: "ABCD"}}, nil
+}
+
+func TestClosePositionRoundsTheFractionToTheQuantityTickSize(t *testing.T) {
+	market := flattenTestDerivativeMarket(riskDerivativeMarketId)
+	market.Market.MinQuantityTickSize = sdk.MustNewDecFromStr("0.01")
+	chainClient := &positionCloseTestChainClient{
+		position: &exchangetypes.Position{IsLong: true, Quantity: sdk.MustNewDecFromStr("1.235")},
+		market:   market,
+	}
+	closer := NewPositionCloser(chainClient, PositionCloserConfig{})
+
+	result, err := closer.ClosePosition(context.Background(), riskSubaccountId, riskDerivativeMarketId, sdk.MustNewDecFromStr("0.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ABCD", result.TxHash)
+	// 1.235 * 0.5 = 0.6175 rounded down to the tick size
+	assert.Equal(t, "0.610000000000000000", result.Quantity.String())
+	assert.Equal(t, "0.625000000000000000", result.RemainingQuantity.String())
+
+	order := chainClient.broadcastedMsgs[0].(*exchangetypes.MsgCreateDerivativeMarketOrder)
+	assert.Equal(t, exchangetypes.OrderType_SELL, order.Order.OrderType)
+	assert.True(t, order.Order.IsReduceOnly())
+	assert.Equal(t, "94.500000000000000000", order.Order.OrderInfo.Price.String())
+
+	result, err = closer.ClosePosition(context.Background(), riskSubaccountId, riskDerivativeMarketId, sdk.OneDec())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.230000000000000000", result.Quantity.String())
+	assert.Equal(t, "0.005000000000000000", result.RemainingQuantity.String())
+
+	_, err = closer.ClosePosition(context.Background(), riskSubaccountId, riskDerivativeMarketId, sdk.MustNewDecFromStr("0.001"))
+	assert.Error(t, err)
+	_, err = closer.ClosePosition(context.Background(), riskSubaccountId, riskDerivativeMarketId, sdk.MustNewDecFromStr("1.5"))
+	assert.Error(t, err)
+	assert.Len(t, chainClient.broadcastedMsgs, 2)
+}
+
+func TestClosePositionInBinaryOptionsMarket(t *testing.T) {
+	chainClient := &positionCloseTestChainClient{
+		position: &exchangetypes.Position{IsLong: false, Quantity: sdk.MustNewDecFromStr("10")},
+		binaryMarkets: []*exchangetypes.BinaryOptionsMarket{{
+			MarketId:            riskDerivativeMarketId,
+			MinPriceTickSize:    sdk.MustNewDecFromStr("0.01"),
+			MinQuantityTickSize: sdk.OneDec(),
+		}},
+	}
+	closer := NewPositionCloser(chainClient, PositionCloserConfig{})
+
+	result, err := closer.ClosePosition(context.Background(), riskSubaccountId, riskDerivativeMarketId, sdk.OneDec())
+	assert.NoError(t, err)
+	assert.Equal(t, exchangetypes.OrderType_BUY, result.OrderType)
+	assert.True(t, result.RemainingQuantity.IsZero())
+
+	order := chainClient.broadcastedMsgs[0].(*exchangetypes.MsgCreateBinaryOptionsMarketOrder)
+	assert.True(t, order.Order.IsReduceOnly())
+	assert.Equal(t, "0.990000000000000000", order.Order.OrderInfo.Price.String())
+}
+
+func TestClosePositionWithoutPosition(t *testing.T) {
+	chainClient := &positionCloseTestChainClient{market: flattenTestDerivativeMarket(riskDerivativeMarketId)}
+	closer := NewPositionCloser(chainClient, PositionCloserConfig{})
+
+	_, err := closer.ClosePosition(context.Background(), riskSubaccountId, riskDerivativeMarketId, sdk.OneDec())
+	assert.Error(t, err)
+	assert.Empty(t, chainClient.broadcastedMsgs)
+}
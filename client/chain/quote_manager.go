@@ -0,0 +1,339 @@
+package chain
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+	"github.com/InjectiveLabs/sdk-go/client/core"
+)
+
+// QuoteConfig describes the two-sided quotes maintained in a market. Spread, LevelSpacing, Skew and PriceTolerance
+// are fractions of the mid price (0.001 is 10 basis points). Size is the human readable quantity of every level
+type QuoteConfig struct {
+	MarketId     string `json:"market_id"`
+	SubaccountId string `json:"subaccount_id"`
+	FeeRecipient string `json:"fee_recipient,omitempty"`
+	// Levels is the number of quotes on each side of the book
+	Levels int `json:"levels"`
+	// Spread is the distance between the mid price and the first level on each side
+	Spread decimal.Decimal `json:"spread"`
+	// LevelSpacing is the distance between consecutive levels
+	LevelSpacing decimal.Decimal `json:"level_spacing"`
+	Size         decimal.Decimal `json:"size"`
+	// Skew moves all the quotes away from the mid price: a positive skew lowers the prices (to reduce a long
+	// inventory) and a negative skew raises them
+	Skew decimal.Decimal `json:"skew"`
+	// PriceTolerance is the max difference between a resting order price and its desired price for the order to be
+	// kept instead of replaced
+	PriceTolerance decimal.Decimal `json:"price_tolerance"`
+	// Leverage is used to calculate the margin of derivative market quotes
+	Leverage decimal.Decimal `json:"leverage"`
+}
+
+// QuoteTarget is the last quote config refreshed in a market, with the human readable mid price it was quoted around
+type QuoteTarget struct {
+	Config    QuoteConfig     `json:"config"`
+	MidPrice  decimal.Decimal `json:"mid_price"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// DesiredQuote is a quote the manager wants resting in the book, in chain format
+type DesiredQuote struct {
+	OrderType exchangetypes.OrderType
+	Level     int
+	Price     sdk.Dec
+	Quantity  sdk.Dec
+	Margin    sdk.Dec
+}
+
+// QuoteDiff is the minimal set of changes that turns the resting orders into the desired quotes
+type QuoteDiff struct {
+	IsDerivative bool
+	ToCancel     []TrackedOrder
+	ToCreate     []DesiredQuote
+	Kept         []TrackedOrder
+}
+
+func (d *QuoteDiff) IsEmpty() bool {
+	return len(d.ToCancel) == 0 && len(d.ToCreate) == 0
+}
+
+// QuoteManager keeps N levels of two-sided quotes per market. Every refresh tick it compares the desired quotes with
+// the resting orders in the OrderTracker and sends a single batch update with only the cancels and creates needed.
+// The last target of each market and subaccount is kept, to be saved with the tracker state (see TakeStateSnapshot)
+type QuoteManager struct {
+	chainClient      ChainClient
+	marketsAssistant MarketsAssistant
+	tracker          *OrderTracker
+
+	mux     sync.RWMutex
+	targets map[string]QuoteTarget
+
+	clock clock.Clock
+}
+
+func NewQuoteManager(chainClient ChainClient, marketsAssistant MarketsAssistant, tracker *OrderTracker) *QuoteManager {
+	return &QuoteManager{
+		chainClient:      chainClient,
+		marketsAssistant: marketsAssistant,
+		tracker:          tracker,
+		targets:          make(map[string]QuoteTarget),
+		clock:            clock.Real(),
+	}
+}
+
+// SetClock sets the clock of the update times of the quote targets
+func (m *QuoteManager) SetClock(c clock.Clock) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.clock = clock.OrReal(c)
+}
+
+// Targets returns the last quote target of every market and subaccount, sorted by market and subaccount
+func (m *QuoteManager) Targets() []QuoteTarget {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	targets := make([]QuoteTarget, 0, len(m.targets))
+	for _, target := range m.targets {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Config.MarketId != targets[j].Config.MarketId {
+			return targets[i].Config.MarketId < targets[j].Config.MarketId
+		}
+		return targets[i].Config.SubaccountId < targets[j].Config.SubaccountId
+	})
+	return targets
+}
+
+func (m *QuoteManager) setTarget(target QuoteTarget) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.targets[target.Config.MarketId+"/"+target.Config.SubaccountId] = target
+}
+
+// Diff calculates the changes needed to quote around the human readable mid price
+func (m *QuoteManager) Diff(config QuoteConfig, midPrice decimal.Decimal) (*QuoteDiff, error) {
+	if config.Levels <= 0 {
+		return nil, errors.Errorf("the number of quote levels must be positive, got %d", config.Levels)
+	}
+	if !midPrice.IsPositive() {
+		return nil, errors.Errorf("the mid price must be positive, got %s", midPrice.String())
+	}
+	if !config.Size.IsPositive() {
+		return nil, errors.Errorf("the quote size must be positive, got %s", config.Size.String())
+	}
+
+	desired, isDerivative, err := m.desiredQuotes(config, midPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &QuoteDiff{IsDerivative: isDerivative}
+	matched := make([]bool, len(desired))
+	for _, order := range m.tracker.OpenOrders(config.MarketId, config.SubaccountId) {
+		index := matchingQuote(order, desired, matched, config.PriceTolerance)
+		if index < 0 {
+			diff.ToCancel = append(diff.ToCancel, order)
+			continue
+		}
+		matched[index] = true
+		diff.Kept = append(diff.Kept, order)
+	}
+	for i, quote := range desired {
+		if !matched[i] {
+			diff.ToCreate = append(diff.ToCreate, quote)
+		}
+	}
+
+	return diff, nil
+}
+
+// Refresh sends the batch update for the quotes diff and updates the tracker with its results. It returns the diff
+// that was applied; nothing is broadcast if the resting orders already match the desired quotes. The orders to cancel
+// are marked as pending cancels in the tracker until the tx result is known
+func (m *QuoteManager) Refresh(ctx context.Context, config QuoteConfig, midPrice decimal.Decimal) (*QuoteDiff, error) {
+	diff, err := m.Diff(config, midPrice)
+	if err != nil {
+		return nil, err
+	}
+	m.setTarget(QuoteTarget{Config: config, MidPrice: midPrice, UpdatedAt: m.clock.Now()})
+	if diff.IsEmpty() {
+		return diff, nil
+	}
+
+	msg := m.batchUpdateMsg(config, diff)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, order := range diff.ToCancel {
+		m.tracker.MarkCancelPending(order.OrderHash)
+	}
+	res, err := m.chainClient.SyncBroadcastMsg(msg)
+	if err != nil {
+		// the tx may have been sent, so the cancels stay pending
+		return nil, errors.Wrapf(err, "failed to refresh the quotes in market %s", config.MarketId)
+	}
+	if res == nil || res.TxResponse == nil {
+		return nil, errors.Errorf("the quotes refresh in market %s returned no tx response", config.MarketId)
+	}
+	result, err := NewComposedTxResult([]sdk.Msg{msg}, res.TxResponse)
+	if err != nil {
+		return nil, err
+	}
+	if result.Err != nil {
+		for _, order := range diff.ToCancel {
+			m.tracker.ClearCancelPending(order.OrderHash)
+		}
+		return nil, errors.Wrapf(result.Err, "failed to refresh the quotes in market %s", config.MarketId)
+	}
+
+	if response, ok := result.Results[0].Response.(*exchangetypes.MsgBatchUpdateOrdersResponse); ok {
+		m.applyBatchResponse(msg, diff, response)
+	}
+	return diff, nil
+}
+
+func (m *QuoteManager) desiredQuotes(config QuoteConfig, midPrice decimal.Decimal) ([]DesiredQuote, bool, error) {
+	skewedMid := midPrice.Mul(decimal.NewFromInt(1).Sub(config.Skew))
+
+	var derivativeMarket core.DerivativeMarket
+	spotMarket, isSpot := m.marketsAssistant.AllSpotMarkets()[config.MarketId]
+	if !isSpot {
+		var found bool
+		derivativeMarket, found = m.marketsAssistant.AllDerivativeMarkets()[config.MarketId]
+		if !found {
+			return nil, false, errors.Errorf("market %s not found", config.MarketId)
+		}
+		if !config.Leverage.IsPositive() {
+			return nil, false, errors.Errorf("the leverage must be positive to quote in derivative market %s", config.MarketId)
+		}
+	}
+
+	quotes := make([]DesiredQuote, 0, 2*config.Levels)
+	for level := 0; level < config.Levels; level++ {
+		distance := config.Spread.Add(config.LevelSpacing.Mul(decimal.NewFromInt(int64(level))))
+		for _, orderType := range []exchangetypes.OrderType{exchangetypes.OrderType_BUY, exchangetypes.OrderType_SELL} {
+			price := skewedMid.Mul(decimal.NewFromInt(1).Add(distance))
+			if orderType == exchangetypes.OrderType_BUY {
+				price = skewedMid.Mul(decimal.NewFromInt(1).Sub(distance))
+			}
+			if !price.IsPositive() {
+				continue
+			}
+
+			quote := DesiredQuote{OrderType: orderType, Level: level, Margin: sdk.ZeroDec()}
+			if isSpot {
+				quote.Price = spotMarket.PriceToChainFormat(price)
+				quote.Quantity = spotMarket.QuantityToChainFormat(config.Size)
+			} else {
+				quote.Price = derivativeMarket.PriceToChainFormat(price)
+				quote.Quantity = derivativeMarket.QuantityToChainFormat(config.Size)
+				quote.Margin = derivativeMarket.CalculateMarginInChainFormat(config.Size, price, config.Leverage)
+			}
+			if quote.Price.IsPositive() && quote.Quantity.IsPositive() {
+				quotes = append(quotes, quote)
+			}
+		}
+	}
+	return quotes, !isSpot, nil
+}
+
+// matchingQuote returns the index of the first unmatched desired quote the order satisfies, or -1
+func matchingQuote(order TrackedOrder, desired []DesiredQuote, matched []bool, priceTolerance decimal.Decimal) int {
+	tolerance, err := sdk.NewDecFromStr(priceTolerance.String())
+	if err != nil {
+		tolerance = sdk.ZeroDec()
+	}
+	if order.Price.IsNil() || order.Quantity.IsNil() {
+		return -1
+	}
+	for i, quote := range desired {
+		if matched[i] || quote.OrderType.IsBuy() != order.IsBuy() || !quote.Quantity.Equal(order.Quantity) {
+			continue
+		}
+		if order.Price.Sub(quote.Price).Abs().LTE(quote.Price.Mul(tolerance)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *QuoteManager) batchUpdateMsg(config QuoteConfig, diff *QuoteDiff) *exchangetypes.MsgBatchUpdateOrders {
+	sender := m.chainClient.FromAddress().String()
+	feeRecipient := config.FeeRecipient
+	if feeRecipient == "" {
+		feeRecipient = sender
+	}
+
+	msg := &exchangetypes.MsgBatchUpdateOrders{Sender: sender}
+	for _, order := range diff.ToCancel {
+		orderData := &exchangetypes.OrderData{
+			MarketId:     order.MarketId,
+			SubaccountId: order.SubaccountId,
+			OrderHash:    order.OrderHash,
+		}
+		if diff.IsDerivative {
+			msg.DerivativeOrdersToCancel = append(msg.DerivativeOrdersToCancel, orderData)
+		} else {
+			msg.SpotOrdersToCancel = append(msg.SpotOrdersToCancel, orderData)
+		}
+	}
+	for _, quote := range diff.ToCreate {
+		orderInfo := exchangetypes.OrderInfo{
+			SubaccountId: config.SubaccountId,
+			FeeRecipient: feeRecipient,
+			Price:        quote.Price,
+			Quantity:     quote.Quantity,
+		}
+		if diff.IsDerivative {
+			msg.DerivativeOrdersToCreate = append(msg.DerivativeOrdersToCreate, &exchangetypes.DerivativeOrder{
+				MarketId:  config.MarketId,
+				OrderInfo: orderInfo,
+				OrderType: quote.OrderType,
+				Margin:    quote.Margin,
+			})
+		} else {
+			msg.SpotOrdersToCreate = append(msg.SpotOrdersToCreate, &exchangetypes.SpotOrder{
+				MarketId:  config.MarketId,
+				OrderInfo: orderInfo,
+				OrderType: quote.OrderType,
+			})
+		}
+	}
+	return msg
+}
+
+func (m *QuoteManager) applyBatchResponse(msg *exchangetypes.MsgBatchUpdateOrders, diff *QuoteDiff, response *exchangetypes.MsgBatchUpdateOrdersResponse) {
+	createdHashes := response.SpotOrderHashes
+	if diff.IsDerivative {
+		createdHashes = response.DerivativeOrderHashes
+	}
+
+	// the cancels that failed are for orders already filled or cancelled, so none of them is resting anymore
+	for _, order := range diff.ToCancel {
+		m.tracker.Remove(order.OrderHash)
+	}
+	for i, orderHash := range createdHashes {
+		if orderHash == "" {
+			continue
+		}
+		if diff.IsDerivative && i < len(msg.DerivativeOrdersToCreate) {
+			m.tracker.TrackDerivativeOrder(orderHash, msg.DerivativeOrdersToCreate[i])
+		}
+		if !diff.IsDerivative && i < len(msg.SpotOrdersToCreate) {
+			m.tracker.TrackSpotOrder(orderHash, msg.SpotOrdersToCreate[i])
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+type quoteTestChainClient struct {
+	MockChainClient
+	batches     []*exchangetypes.MsgBatchUpdateOrders
+	createdHash int
+}
+
+func (c *quoteTestChainClient) SyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	batch := msgs[0].(*exchangetypes.MsgBatchUpdateOrders)
+	c.batches = append(c.batches, batch)
+
+	response := &exchangetypes.MsgBatchUpdateOrdersResponse{}
+	for range batch.SpotOrdersToCancel {
+		response.SpotCancelSuccess = append(response.SpotCancelSuccess, true)
+	}
+	for range batch.SpotOrdersToCreate {
+		c.createdHash++
+		response.SpotOrderHashes = append(response.SpotOrderHashes, fmt.Sprintf("0x%02d", c.createdHash))
+	}
+	anyResponse, err := codectypes.NewAnyWithValue(response)
+	if err != nil {
+		return nil, err
+	}
+	data, err := (&sdk.TxMsgData{MsgResponses: []*codectypes.Any{anyResponse}}).Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: "ABCD", Data: hex.EncodeToString(data)}}, nil
+}
+
+func quoteTestConfig() QuoteConfig {
+	return QuoteConfig{
+		MarketId:       arbitrageSpotMarketId,
+		SubaccountId:   riskSubaccountId,
+		Levels:         2,
+		Spread:         decimal.RequireFromString("0.01"),
+		LevelSpacing:   decimal.RequireFromString("0.01"),
+		Size:           decimal.RequireFromString("1"),
+		PriceTolerance: decimal.RequireFromString("0.002"),
+	}
+}
+
+func TestQuoteManagerDesiredQuotes(t *testing.T) {
+	manager := NewQuoteManager(&quoteTestChainClient{}, spotOrderValidationTestAssistant(), NewOrderTracker())
+	config := quoteTestConfig()
+	config.Skew = decimal.RequireFromString("0.1")
+
+	diff, err := manager.Diff(config, decimal.RequireFromString("10"))
+	assert.NoError(t, err)
+	assert.Empty(t, diff.ToCancel)
+	assert.Len(t, diff.ToCreate, 4)
+
+	market := spotOrderValidationTestAssistant().AllSpotMarkets()[arbitrageSpotMarketId]
+	prices := make([]string, 0, len(diff.ToCreate))
+	for _, quote := range diff.ToCreate {
+		prices = append(prices, market.PriceFromChainFormat(quote.Price).String())
+	}
+	assert.Equal(t, []string{"8.91", "9.09", "8.82", "9.18"}, prices)
+	assert.True(t, diff.ToCreate[0].OrderType.IsBuy())
+	assert.False(t, diff.ToCreate[1].OrderType.IsBuy())
+
+	_, err = manager.Diff(config, decimal.Zero)
+	assert.Error(t, err)
+	config.Levels = 0
+	_, err = manager.Diff(config, decimal.RequireFromString("10"))
+	assert.Error(t, err)
+}
+
+func TestQuoteManagerRefreshSendsMinimalBatch(t *testing.T) {
+	chainClient := &quoteTestChainClient{}
+	tracker := NewOrderTracker()
+	manager := NewQuoteManager(chainClient, spotOrderValidationTestAssistant(), tracker)
+	config := quoteTestConfig()
+
+	diff, err := manager.Refresh(context.Background(), config, decimal.RequireFromString("10"))
+	assert.NoError(t, err)
+	assert.Len(t, diff.ToCreate, 4)
+	assert.Len(t, chainClient.batches, 1)
+	assert.Len(t, tracker.OpenOrders(config.MarketId, config.SubaccountId), 4)
+
+	diff, err = manager.Refresh(context.Background(), config, decimal.RequireFromString("10.01"))
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+	assert.Len(t, diff.Kept, 4)
+	assert.Len(t, chainClient.batches, 1)
+
+	config.Levels = 1
+	diff, err = manager.Refresh(context.Background(), config, decimal.RequireFromString("10"))
+	assert.NoError(t, err)
+	assert.Len(t, diff.ToCancel, 2)
+	assert.Empty(t, diff.ToCreate)
+	assert.Len(t, chainClient.batches, 2)
+	assert.Len(t, chainClient.batches[1].SpotOrdersToCancel, 2)
+	assert.Empty(t, chainClient.batches[1].SpotOrdersToCreate)
+	assert.Len(t, tracker.OpenOrders(config.MarketId, config.SubaccountId), 2)
+
+	diff, err = manager.Refresh(context.Background(), config, decimal.RequireFromString("11"))
+	assert.NoError(t, err)
+	assert.Len(t, diff.ToCancel, 2)
+	assert.Len(t, diff.ToCreate, 2)
+	assert.Len(t, tracker.OpenOrders(config.MarketId, config.SubaccountId), 2)
+}
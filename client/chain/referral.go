@@ -0,0 +1,51 @@
+package chain
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+var ErrInvalidFeeRecipient = errors.New("invalid fee recipient")
+
+// ValidateFeeRecipient checks that the fee recipient of an order is an account address, and that it is neither the
+// zero address nor the maker (the owner of the order subaccount), which would get the relayer fee share back instead
+// of the referral. A nil maker is not checked
+func ValidateFeeRecipient(feeRecipient string, maker sdk.AccAddress) error {
+	address, err := sdk.AccAddressFromBech32(feeRecipient)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidFeeRecipient, "%q is not an account address", feeRecipient)
+	}
+	if address.Equals(sdk.AccAddress(make([]byte, len(address)))) {
+		return errors.Wrap(ErrInvalidFeeRecipient, "the fee recipient is the zero address")
+	}
+	if maker != nil && address.Equals(maker) {
+		return errors.Wrapf(ErrInvalidFeeRecipient, "the fee recipient %s is the maker", feeRecipient)
+	}
+	return nil
+}
+
+// orderFeeRecipient returns the fee recipient of an order of the subaccount: the address of the referral when set,
+// checked with ValidateFeeRecipient, or else the fee recipient of the order data
+func (c *chainClient) orderFeeRecipient(subaccountId eth.Hash, feeRecipient string, referral string) (string, error) {
+	if referral == "" {
+		return feeRecipient, nil
+	}
+	if feeRecipient != "" {
+		return "", errors.Errorf("the order has both the fee recipient %s and the referral %s", feeRecipient, referral)
+	}
+
+	var referralFeeRecipient string
+	if c.opts != nil {
+		referralFeeRecipient = c.opts.Referrals[referral]
+	}
+	if referralFeeRecipient == "" {
+		return "", errors.Errorf("unknown referral %s", referral)
+	}
+	if err := ValidateFeeRecipient(referralFeeRecipient, exchangetypes.SubaccountIDToSdkAddress(subaccountId)); err != nil {
+		return "", errors.Wrapf(err, "invalid referral %s", referral)
+	}
+	return referralFeeRecipient, nil
+}
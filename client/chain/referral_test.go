@@ -0,0 +1,86 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+func TestValidateFeeRecipient(t *testing.T) {
+	relayer := sdk.AccAddress(bytes.Repeat([]byte{1}, 20))
+	maker := sdk.AccAddress(bytes.Repeat([]byte{2}, 20))
+
+	assert.NoError(t, ValidateFeeRecipient(relayer.String(), maker))
+	assert.NoError(t, ValidateFeeRecipient(maker.String(), nil))
+	assert.True(t, errors.Is(ValidateFeeRecipient(maker.String(), maker), ErrInvalidFeeRecipient))
+	assert.True(t, errors.Is(ValidateFeeRecipient(sdk.AccAddress(make([]byte, 20)).String(), maker), ErrInvalidFeeRecipient))
+	assert.EqualError(t, ValidateFeeRecipient("0x0101010101010101010101010101010101010101", maker), `"0x0101010101010101010101010101010101010101" is not an account address: invalid fee recipient`)
+}
+
+func TestOptionReferral(t *testing.T) {
+	relayer := sdk.AccAddress(bytes.Repeat([]byte{1}, 20))
+	opts := common.DefaultClientOptions()
+
+	assert.NoError(t, common.OptionReferral("relayer", relayer.String())(opts))
+	assert.Equal(t, map[string]string{"relayer": relayer.String()}, opts.Referrals)
+	assert.EqualError(t, common.OptionReferral("", relayer.String())(opts), "the referral name is empty")
+	assert.EqualError(t, common.OptionReferral("zero", sdk.AccAddress(make([]byte, 20)).String())(opts), "the fee recipient of referral zero is the zero address")
+	assert.Error(t, common.OptionReferral("typo", "inj1typo")(opts))
+}
+
+func TestOrdersWithReferralShareTheFeesWithTheRelayer(t *testing.T) {
+	relayer := sdk.AccAddress(bytes.Repeat([]byte{1}, 20))
+	trader := sdk.AccAddress(bytes.Repeat([]byte{2}, 20))
+	subaccountId := exchangetypes.SdkAddressToSubaccountID(trader)
+	opts := common.DefaultClientOptions()
+	assert.NoError(t, common.OptionReferral("relayer", relayer.String())(opts))
+	assert.NoError(t, common.OptionReferral("self", trader.String())(opts))
+	client := &chainClient{opts: opts}
+	assistant := spotOrderValidationTestAssistant()
+	orderData := func(referral string) *SpotOrderData {
+		return &SpotOrderData{
+			OrderType: exchangetypes.OrderType_BUY,
+			Price:     decimal.RequireFromString("10"),
+			Quantity:  decimal.RequireFromString("2"),
+			MarketId:  arbitrageSpotMarketId,
+			Referral:  referral,
+		}
+	}
+
+	order := client.CreateSpotOrder(subaccountId, orderData("relayer"), assistant)
+	assert.Equal(t, relayer.String(), order.OrderInfo.FeeRecipient)
+
+	// the relayer gets a share of the taker fee, not of the maker rebate
+	breakdown := ComputeSpotFeeBreakdown(exchangetypes.OrderType_BUY, decimal.RequireFromString("10"), decimal.RequireFromString("2"), FeeRates{
+		MakerFeeRate: decimal.RequireFromString("-0.0001"),
+		TakerFeeRate: decimal.RequireFromString("0.0015"),
+	})
+	relayerFeeShareRate := decimal.RequireFromString("0.4")
+	assert.Equal(t, "0.012", RelayerFeeShare(breakdown.TakerFee, relayerFeeShareRate).String())
+	assert.True(t, RelayerFeeShare(breakdown.MakerFee, relayerFeeShareRate).IsZero())
+
+	// a referral with the address of the maker would give the fee share back to the maker
+	assert.PanicsWithError(t, "invalid referral self: the fee recipient "+trader.String()+" is the maker: invalid fee recipient", func() {
+		client.CreateSpotOrder(subaccountId, orderData("self"), assistant)
+	})
+	assert.PanicsWithError(t, "unknown referral other", func() {
+		client.CreateSpotOrder(subaccountId, orderData("other"), assistant)
+	})
+	withFeeRecipient := orderData("relayer")
+	withFeeRecipient.FeeRecipient = trader.String()
+	assert.Panics(t, func() {
+		client.CreateSpotOrder(subaccountId, withFeeRecipient, assistant)
+	})
+
+	// the orders without referral keep their fee recipient
+	withoutReferral := orderData("")
+	withoutReferral.FeeRecipient = trader.String()
+	assert.Equal(t, trader.String(), client.CreateSpotOrder(subaccountId, withoutReferral, assistant).OrderInfo.FeeRecipient)
+}
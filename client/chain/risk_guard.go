@@ -0,0 +1,394 @@
+package chain
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+var ErrRiskLimitExceeded = errors.New("risk limit exceeded")
+
+type RiskLimitType string
+
+const (
+	RiskLimitMaxPosition          RiskLimitType = "max_position"
+	RiskLimitMaxOpenOrderNotional RiskLimitType = "max_open_order_notional"
+	RiskLimitMaxDailyLoss         RiskLimitType = "max_daily_loss"
+)
+
+// RiskLimits are the limits of a market. All values are in chain format (the same units used in the order messages),
+// and nil values are not enforced
+type RiskLimits struct {
+	// MaxPosition is the maximum absolute position quantity in a derivative market
+	MaxPosition *sdk.Dec
+	// MaxOpenOrderNotional is the maximum notional (price * quantity) of the open limit orders
+	MaxOpenOrderNotional *sdk.Dec
+	// MaxDailyLoss is the maximum realized loss in the UTC day. Once reached, new orders (except reduce-only
+	// derivative orders) are rejected. Zero means no limit
+	MaxDailyLoss *sdk.Dec
+}
+
+// RiskViolation describes the limit an order would exceed
+type RiskViolation struct {
+	Limit        RiskLimitType
+	MarketId     string
+	SubaccountId string
+	MaxValue     sdk.Dec
+	Value        sdk.Dec
+}
+
+type RiskLimitError struct {
+	Violation RiskViolation
+}
+
+func (e *RiskLimitError) Error() string {
+	return fmt.Sprintf(
+		"%s: %s for market %s and subaccount %s would be %s (limit %s)",
+		ErrRiskLimitExceeded.Error(),
+		e.Violation.Limit,
+		e.Violation.MarketId,
+		e.Violation.SubaccountId,
+		e.Violation.Value.String(),
+		e.Violation.MaxValue.String(),
+	)
+}
+
+func (e *RiskLimitError) Unwrap() error {
+	return ErrRiskLimitExceeded
+}
+
+// RiskOverrideFunc is called for every violation, and the msgs are allowed if it returns true
+type RiskOverrideFunc func(violation RiskViolation) bool
+
+// RiskRejectionStats are the number of rejected and overridden violations of a limit in a market
+type RiskRejectionStats struct {
+	MarketId   string
+	Limit      RiskLimitType
+	Rejected   uint64
+	Overridden uint64
+}
+
+type riskKey struct {
+	marketId     string
+	subaccountId string
+}
+
+type riskExposure struct {
+	position          sdk.Dec
+	openOrderNotional sdk.Dec
+	realizedPnl       sdk.Dec
+	pnlDay            string
+}
+
+func newRiskExposure() *riskExposure {
+	return &riskExposure{
+		position:          sdk.ZeroDec(),
+		openOrderNotional: sdk.ZeroDec(),
+		realizedPnl:       sdk.ZeroDec(),
+	}
+}
+
+type riskOrder struct {
+	marketId     string
+	subaccountId string
+	isBuy        bool
+	isLimit      bool
+	isDerivative bool
+	isReduceOnly bool
+	price        sdk.Dec
+	quantity     sdk.Dec
+}
+
+// RiskGuard enforces client side risk limits on the order messages before they are broadcasted. It can be installed
+// in the chain client with common.OptionPreBroadcastCheck(guard.CheckMsgs).
+//
+// The guard doesn't query the chain: positions, open orders notional and realized PnL have to be kept updated by the
+// caller (usually from the exchange streams). The notional of the accepted limit orders is added to the tracked open
+// orders notional until it is updated again
+type RiskGuard struct {
+	mux       sync.Mutex
+	limits    map[riskKey]RiskLimits
+	exposures map[riskKey]*riskExposure
+	stats     map[riskKey]map[RiskLimitType]*RiskRejectionStats
+	override  RiskOverrideFunc
+	clock     clock.Clock
+}
+
+func NewRiskGuard() *RiskGuard {
+	return &RiskGuard{
+		limits:    make(map[riskKey]RiskLimits),
+		exposures: make(map[riskKey]*riskExposure),
+		stats:     make(map[riskKey]map[RiskLimitType]*RiskRejectionStats),
+		clock:     clock.Real(),
+	}
+}
+
+// SetClock sets the clock of the day of the daily loss limits
+func (g *RiskGuard) SetClock(c clock.Clock) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.clock = clock.OrReal(c)
+}
+
+// SetLimits sets the limits for a subaccount in a market. An empty subaccountId sets the limits used for all the
+// subaccounts without specific limits
+func (g *RiskGuard) SetLimits(marketId string, subaccountId string, limits RiskLimits) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.limits[riskKey{marketId: marketId, subaccountId: subaccountId}] = limits
+}
+
+// SetOverride sets the hook used to allow msgs that violate a limit
+func (g *RiskGuard) SetOverride(override RiskOverrideFunc) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.override = override
+}
+
+// SetPosition updates the position of the subaccount, positive for long positions and negative for short positions
+func (g *RiskGuard) SetPosition(marketId string, subaccountId string, quantity sdk.Dec) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.exposure(marketId, subaccountId).position = quantity
+}
+
+// SetOpenOrderNotional updates the notional of all the open limit orders of the subaccount in the market
+func (g *RiskGuard) SetOpenOrderNotional(marketId string, subaccountId string, notional sdk.Dec) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.exposure(marketId, subaccountId).openOrderNotional = notional
+}
+
+// RecordRealizedPnl adds realized PnL (negative for losses) to the current UTC day total of the subaccount
+func (g *RiskGuard) RecordRealizedPnl(marketId string, subaccountId string, pnl sdk.Dec) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	exposure := g.exposure(marketId, subaccountId)
+	g.rollPnlDay(exposure)
+	exposure.realizedPnl = exposure.realizedPnl.Add(pnl)
+}
+
+// Stats returns the number of violations per market and limit
+func (g *RiskGuard) Stats() []RiskRejectionStats {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	result := make([]RiskRejectionStats, 0)
+	for _, statsByLimit := range g.stats {
+		for _, stats := range statsByLimit {
+			result = append(result, *stats)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MarketId != result[j].MarketId {
+			return result[i].MarketId < result[j].MarketId
+		}
+		return result[i].Limit < result[j].Limit
+	})
+	return result
+}
+
+// CheckMsgs returns a *RiskLimitError if any of the orders created by the msgs exceeds the limits of its market.
+// Orders in the same call are checked cumulatively, and cancellations are never rejected
+func (g *RiskGuard) CheckMsgs(msgs ...sdk.Msg) error {
+	orders := riskOrdersFromMsgs(msgs)
+	if len(orders) == 0 {
+		return nil
+	}
+
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	positions := make(map[riskKey]sdk.Dec)
+	notionals := make(map[riskKey]sdk.Dec)
+	for _, order := range orders {
+		key := riskKey{marketId: order.marketId, subaccountId: order.subaccountId}
+		limits, found := g.limitsFor(key)
+		if !found {
+			continue
+		}
+		exposure := g.exposure(order.marketId, order.subaccountId)
+		if _, found := positions[key]; !found {
+			positions[key] = exposure.position
+			notionals[key] = exposure.openOrderNotional
+		}
+
+		if limits.MaxDailyLoss != nil && !order.isReduceOnly {
+			g.rollPnlDay(exposure)
+			loss := exposure.realizedPnl.Neg()
+			if limits.MaxDailyLoss.IsPositive() && loss.GTE(*limits.MaxDailyLoss) {
+				if err := g.violation(key, RiskLimitMaxDailyLoss, *limits.MaxDailyLoss, loss); err != nil {
+					return err
+				}
+			}
+		}
+
+		if limits.MaxPosition != nil && order.isDerivative && !order.isReduceOnly {
+			position := positions[key]
+			newPosition := position.Add(order.quantity)
+			if !order.isBuy {
+				newPosition = position.Sub(order.quantity)
+			}
+			// orders reducing the position are always allowed
+			if newPosition.Abs().GT(*limits.MaxPosition) && newPosition.Abs().GT(position.Abs()) {
+				if err := g.violation(key, RiskLimitMaxPosition, *limits.MaxPosition, newPosition.Abs()); err != nil {
+					return err
+				}
+			}
+			positions[key] = newPosition
+		}
+
+		if limits.MaxOpenOrderNotional != nil && order.isLimit {
+			newNotional := notionals[key].Add(order.price.Mul(order.quantity))
+			if newNotional.GT(*limits.MaxOpenOrderNotional) {
+				if err := g.violation(key, RiskLimitMaxOpenOrderNotional, *limits.MaxOpenOrderNotional, newNotional); err != nil {
+					return err
+				}
+			}
+			notionals[key] = newNotional
+		}
+	}
+
+	for key, notional := range notionals {
+		g.exposure(key.marketId, key.subaccountId).openOrderNotional = notional
+	}
+
+	return nil
+}
+
+func (g *RiskGuard) violation(key riskKey, limit RiskLimitType, maxValue sdk.Dec, value sdk.Dec) error {
+	violation := RiskViolation{
+		Limit:        limit,
+		MarketId:     key.marketId,
+		SubaccountId: key.subaccountId,
+		MaxValue:     maxValue,
+		Value:        value,
+	}
+
+	statsByLimit, found := g.stats[riskKey{marketId: key.marketId}]
+	if !found {
+		statsByLimit = make(map[RiskLimitType]*RiskRejectionStats)
+		g.stats[riskKey{marketId: key.marketId}] = statsByLimit
+	}
+	stats, found := statsByLimit[limit]
+	if !found {
+		stats = &RiskRejectionStats{MarketId: key.marketId, Limit: limit}
+		statsByLimit[limit] = stats
+	}
+
+	if g.override != nil && g.override(violation) {
+		stats.Overridden++
+		return nil
+	}
+
+	stats.Rejected++
+	return &RiskLimitError{Violation: violation}
+}
+
+func (g *RiskGuard) limitsFor(key riskKey) (RiskLimits, bool) {
+	if limits, found := g.limits[key]; found {
+		return limits, true
+	}
+	limits, found := g.limits[riskKey{marketId: key.marketId}]
+	return limits, found
+}
+
+func (g *RiskGuard) exposure(marketId string, subaccountId string) *riskExposure {
+	key := riskKey{marketId: marketId, subaccountId: subaccountId}
+	exposure, found := g.exposures[key]
+	if !found {
+		exposure = newRiskExposure()
+		g.exposures[key] = exposure
+	}
+	return exposure
+}
+
+func (g *RiskGuard) rollPnlDay(exposure *riskExposure) {
+	day := g.clock.Now().UTC().Format("2006-01-02")
+	if exposure.pnlDay != day {
+		exposure.pnlDay = day
+		exposure.realizedPnl = sdk.ZeroDec()
+	}
+}
+
+func riskOrdersFromMsgs(msgs []sdk.Msg) []riskOrder {
+	orders := make([]riskOrder, 0)
+	addSpotOrder := func(order *exchangetypes.SpotOrder, isLimit bool) {
+		// nil orders in batch msgs are rejected by the chain, so they create no exposure
+		if order == nil {
+			return
+		}
+		orders = append(orders, riskOrder{
+			marketId:     order.MarketId,
+			subaccountId: order.OrderInfo.SubaccountId,
+			isBuy:        order.IsBuy(),
+			isLimit:      isLimit,
+			price:        order.OrderInfo.Price,
+			quantity:     order.OrderInfo.Quantity,
+		})
+	}
+	addDerivativeOrder := func(order *exchangetypes.DerivativeOrder, isLimit bool) {
+		if order == nil {
+			return
+		}
+		orders = append(orders, riskOrder{
+			marketId:     order.MarketId,
+			subaccountId: order.OrderInfo.SubaccountId,
+			isBuy:        order.OrderType.IsBuy(),
+			isLimit:      isLimit,
+			isDerivative: true,
+			isReduceOnly: order.IsReduceOnly(),
+			price:        order.OrderInfo.Price,
+			quantity:     order.OrderInfo.Quantity,
+		})
+	}
+
+	for _, msg := range msgs {
+		switch typedMsg := msg.(type) {
+		case *exchangetypes.MsgCreateSpotLimitOrder:
+			addSpotOrder(&typedMsg.Order, true)
+		case *exchangetypes.MsgCreateSpotMarketOrder:
+			addSpotOrder(&typedMsg.Order, false)
+		case *exchangetypes.MsgBatchCreateSpotLimitOrders:
+			for i := range typedMsg.Orders {
+				addSpotOrder(&typedMsg.Orders[i], true)
+			}
+		case *exchangetypes.MsgCreateDerivativeLimitOrder:
+			addDerivativeOrder(&typedMsg.Order, true)
+		case *exchangetypes.MsgCreateDerivativeMarketOrder:
+			addDerivativeOrder(&typedMsg.Order, false)
+		case *exchangetypes.MsgBatchCreateDerivativeLimitOrders:
+			for i := range typedMsg.Orders {
+				addDerivativeOrder(&typedMsg.Orders[i], true)
+			}
+		case *exchangetypes.MsgBatchUpdateOrders:
+			for _, order := range typedMsg.SpotOrdersToCreate {
+				addSpotOrder(order, true)
+			}
+			for _, order := range typedMsg.DerivativeOrdersToCreate {
+				addDerivativeOrder(order, true)
+			}
+			for _, order := range typedMsg.BinaryOptionsOrdersToCreate {
+				addDerivativeOrder(order, true)
+			}
+		case *exchangetypes.MsgCreateBinaryOptionsLimitOrder:
+			addDerivativeOrder(&typedMsg.Order, true)
+		case *exchangetypes.MsgCreateBinaryOptionsMarketOrder:
+			addDerivativeOrder(&typedMsg.Order, false)
+		case *authztypes.MsgExec:
+			// the orders executed on behalf of a granter are checked like the orders sent directly
+			if execMsgs, err := typedMsg.GetMessages(); err == nil {
+				orders = append(orders, riskOrdersFromMsgs(execMsgs)...)
+			}
+		}
+	}
+
+	return orders
+}
@@ -0,0 +1,166 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const (
+	riskSpotMarketId       = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	riskDerivativeMarketId = "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+	riskSubaccountId       = "0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000"
+)
+
+func riskDec(value string) *sdk.Dec {
+	dec := sdk.MustNewDecFromStr(value)
+	return &dec
+}
+
+func riskDerivativeOrderMsg(orderType exchangetypes.OrderType, price string, quantity string, margin string) *exchangetypes.MsgCreateDerivativeLimitOrder {
+	return &exchangetypes.MsgCreateDerivativeLimitOrder{
+		Sender: "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku",
+		Order: exchangetypes.DerivativeOrder{
+			MarketId: riskDerivativeMarketId,
+			OrderInfo: exchangetypes.OrderInfo{
+				SubaccountId: riskSubaccountId,
+				Price:        sdk.MustNewDecFromStr(price),
+				Quantity:     sdk.MustNewDecFromStr(quantity),
+			},
+			OrderType: orderType,
+			Margin:    sdk.MustNewDecFromStr(margin),
+		},
+	}
+}
+
+func TestRiskGuardMaxPosition(t *testing.T) {
+	guard := NewRiskGuard()
+	guard.SetLimits(riskDerivativeMarketId, "", RiskLimits{MaxPosition: riskDec("10")})
+	guard.SetPosition(riskDerivativeMarketId, riskSubaccountId, sdk.MustNewDecFromStr("8"))
+
+	err := guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "3", "300"))
+	assert.True(t, errors.Is(err, ErrRiskLimitExceeded))
+	var limitErr *RiskLimitError
+	assert.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, RiskLimitMaxPosition, limitErr.Violation.Limit)
+	assert.Equal(t, "11.000000000000000000", limitErr.Violation.Value.String())
+
+	// orders reducing the position and reduce only orders are allowed
+	assert.NoError(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_SELL, "100", "15", "1500")))
+	assert.NoError(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "5", "0")))
+
+	// orders in the same msgs are checked cumulatively
+	err = guard.CheckMsgs(
+		riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "1", "100"),
+		riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "2", "200"),
+	)
+	assert.True(t, errors.Is(err, ErrRiskLimitExceeded))
+
+	assert.Equal(t, []RiskRejectionStats{{MarketId: riskDerivativeMarketId, Limit: RiskLimitMaxPosition, Rejected: 2}}, guard.Stats())
+}
+
+func TestRiskGuardMaxOpenOrderNotional(t *testing.T) {
+	guard := NewRiskGuard()
+	guard.SetLimits(riskSpotMarketId, riskSubaccountId, RiskLimits{MaxOpenOrderNotional: riskDec("1000")})
+
+	order := exchangetypes.SpotOrder{
+		MarketId: riskSpotMarketId,
+		OrderInfo: exchangetypes.OrderInfo{
+			SubaccountId: riskSubaccountId,
+			Price:        sdk.MustNewDecFromStr("10"),
+			Quantity:     sdk.MustNewDecFromStr("60"),
+		},
+		OrderType: exchangetypes.OrderType_BUY,
+	}
+	msg := &exchangetypes.MsgBatchCreateSpotLimitOrders{Orders: []exchangetypes.SpotOrder{order}}
+
+	assert.NoError(t, guard.CheckMsgs(msg))
+	// the notional of the accepted order is tracked until the open orders notional is updated
+	assert.Error(t, guard.CheckMsgs(msg))
+	guard.SetOpenOrderNotional(riskSpotMarketId, riskSubaccountId, sdk.ZeroDec())
+	assert.NoError(t, guard.CheckMsgs(msg))
+
+	// market orders don't rest in the orderbook
+	guard.SetOpenOrderNotional(riskSpotMarketId, riskSubaccountId, sdk.MustNewDecFromStr("1000"))
+	assert.NoError(t, guard.CheckMsgs(&exchangetypes.MsgCreateSpotMarketOrder{Order: order}))
+
+	// other subaccounts have no limits
+	order.OrderInfo.SubaccountId = "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000"
+	assert.NoError(t, guard.CheckMsgs(&exchangetypes.MsgCreateSpotLimitOrder{Order: order}))
+}
+
+func TestRiskGuardMaxDailyLossWithOverride(t *testing.T) {
+	guard := NewRiskGuard()
+	fakeClock := clock.NewFake(time.Date(2023, 11, 14, 23, 0, 0, 0, time.UTC))
+	guard.SetClock(fakeClock)
+	guard.SetLimits(riskDerivativeMarketId, "", RiskLimits{MaxDailyLoss: riskDec("500")})
+
+	guard.RecordRealizedPnl(riskDerivativeMarketId, riskSubaccountId, sdk.MustNewDecFromStr("-300"))
+	assert.NoError(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "1", "100")))
+
+	guard.RecordRealizedPnl(riskDerivativeMarketId, riskSubaccountId, sdk.MustNewDecFromStr("-200"))
+	assert.Error(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "1", "100")))
+	// reduce only orders are still allowed to close positions
+	assert.NoError(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "1", "0")))
+
+	var overridden []RiskViolation
+	guard.SetOverride(func(violation RiskViolation) bool {
+		overridden = append(overridden, violation)
+		return true
+	})
+	assert.NoError(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "1", "100")))
+	assert.Len(t, overridden, 1)
+	guard.SetOverride(nil)
+
+	// the loss is reset on the next UTC day
+	fakeClock.Advance(2 * time.Hour)
+	assert.NoError(t, guard.CheckMsgs(riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "1", "100")))
+
+	assert.Equal(t, []RiskRejectionStats{{MarketId: riskDerivativeMarketId, Limit: RiskLimitMaxDailyLoss, Rejected: 1, Overridden: 1}}, guard.Stats())
+}
+
+func TestRiskGuardIgnoresCancellations(t *testing.T) {
+	guard := NewRiskGuard()
+	guard.SetLimits(riskDerivativeMarketId, "", RiskLimits{MaxDailyLoss: riskDec("0")})
+
+	assert.NoError(t, guard.CheckMsgs(&exchangetypes.MsgCancelDerivativeOrder{
+		MarketId:     riskDerivativeMarketId,
+		SubaccountId: riskSubaccountId,
+		OrderHash:    "0x1",
+	}))
+}
+
+func TestRiskGuardInspectsBatchExecAndBinaryOptionsOrders(t *testing.T) {
+	guard := NewRiskGuard()
+	guard.SetLimits(riskDerivativeMarketId, "", RiskLimits{MaxPosition: riskDec("10"), MaxDailyLoss: riskDec("0")})
+	guard.RecordRealizedPnl(riskDerivativeMarketId, riskSubaccountId, sdk.MustNewDecFromStr("-1000"))
+
+	order := riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "6", "600").Order
+	batch := &exchangetypes.MsgBatchUpdateOrders{
+		SpotOrdersToCreate:       []*exchangetypes.SpotOrder{nil},
+		DerivativeOrdersToCreate: []*exchangetypes.DerivativeOrder{nil, &order},
+	}
+	// nil orders are skipped, and a zero max daily loss is not enforced
+	assert.NoError(t, guard.CheckMsgs(batch))
+
+	batch.DerivativeOrdersToCreate = nil
+	batch.BinaryOptionsOrdersToCreate = []*exchangetypes.DerivativeOrder{&order, &order}
+	assert.True(t, errors.Is(guard.CheckMsgs(batch), ErrRiskLimitExceeded))
+
+	binaryOptionsMsg := &exchangetypes.MsgCreateBinaryOptionsLimitOrder{Order: order}
+	binaryOptionsMsg.Order.OrderInfo.Quantity = sdk.MustNewDecFromStr("11")
+	assert.True(t, errors.Is(guard.CheckMsgs(binaryOptionsMsg), ErrRiskLimitExceeded))
+
+	grantee, _ := sdk.AccAddressFromBech32("inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku")
+	execMsg := authztypes.NewMsgExec(grantee, []sdk.Msg{binaryOptionsMsg})
+	assert.True(t, errors.Is(guard.CheckMsgs(&execMsg), ErrRiskLimitExceeded))
+
+	assert.Equal(t, []RiskRejectionStats{{MarketId: riskDerivativeMarketId, Limit: RiskLimitMaxPosition, Rejected: 3}}, guard.Stats())
+}
@@ -0,0 +1,471 @@
+package chain
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/bytes"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const (
+	defaultRPCMaxBlockLag          = 5
+	defaultRPCHealthCheckInterval  = 5 * time.Second
+	defaultRPCHealthCheckTimeout   = 3 * time.Second
+	defaultRPCFailuresBeforeDemote = 3
+)
+
+// ErrBroadcastOutcomeUnknown is returned when a broadcast failed after the request may have reached the endpoint, and
+// the tx is not committed in the next endpoint. The tx is not broadcast again, because it might already be in the
+// mempool: query it by hash before signing a new tx with the same sequence
+var ErrBroadcastOutcomeUnknown = errors.New("the tx broadcast outcome is unknown")
+
+// RPCEndpoint is a named Tendermint RPC client (e.g. a *rpchttp.HTTP)
+type RPCEndpoint struct {
+	Name   string
+	Client client.TendermintRPC
+}
+
+// RPCEndpointHealth is the state of an endpoint after the last health check and the calls made since
+type RPCEndpointHealth struct {
+	Name         string
+	LatestHeight int64
+	// BlockLag is the number of blocks the endpoint is behind the highest endpoint
+	BlockLag  int64
+	Latency   time.Duration
+	Healthy   bool
+	Failures  int
+	LastError error
+	CheckedAt time.Time
+}
+
+// RPCFailoverConfig configures the demotion of the endpoints. OnHealthUpdate is the metrics hook, called with the
+// health of all the endpoints (best first) after every health check
+type RPCFailoverConfig struct {
+	// MaxBlockLag is the number of blocks an endpoint can be behind the highest endpoint before it is demoted
+	MaxBlockLag         int64
+	HealthCheckInterval time.Duration
+	HealthCheckTimeout  time.Duration
+	// FailuresBeforeDemote is the number of consecutive failed calls that demote an endpoint until the next check
+	FailuresBeforeDemote int
+	OnHealthUpdate       func(health []RPCEndpointHealth)
+	// Clock of the health checks, the real clock by default
+	Clock clock.Clock
+}
+
+func DefaultRPCFailoverConfig() RPCFailoverConfig {
+	return RPCFailoverConfig{
+		MaxBlockLag:          defaultRPCMaxBlockLag,
+		HealthCheckInterval:  defaultRPCHealthCheckInterval,
+		HealthCheckTimeout:   defaultRPCHealthCheckTimeout,
+		FailuresBeforeDemote: defaultRPCFailuresBeforeDemote,
+	}
+}
+
+// FailoverRPCClient is a Tendermint RPC client sending every query and broadcast to the healthiest of several
+// endpoints. Endpoints are scored by their latest height and latency, and the ones lagging more than MaxBlockLag
+// blocks or failing consecutive calls are demoted. A call failing because the endpoint can't be reached is retried
+// in the next endpoint; errors returned by a node that answered are returned as they are. Broadcasts are only sent to
+// the next endpoint if the connection to the endpoint could not be established (see ErrBroadcastOutcomeUnknown).
+// Use it as the client.Context client (clientCtx.WithClient) and run the health checks with Run
+type FailoverRPCClient struct {
+	config RPCFailoverConfig
+	logger log.Logger
+
+	mux       sync.RWMutex
+	endpoints []RPCEndpoint
+	health    map[string]*RPCEndpointHealth
+	// ranking has the endpoints indexes, best first
+	ranking []int
+}
+
+var _ client.TendermintRPC = &FailoverRPCClient{}
+
+func NewFailoverRPCClient(endpoints []RPCEndpoint, config RPCFailoverConfig) (*FailoverRPCClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one RPC endpoint is required")
+	}
+	defaults := DefaultRPCFailoverConfig()
+	if config.MaxBlockLag <= 0 {
+		config.MaxBlockLag = defaults.MaxBlockLag
+	}
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = defaults.HealthCheckInterval
+	}
+	if config.HealthCheckTimeout <= 0 {
+		config.HealthCheckTimeout = defaults.HealthCheckTimeout
+	}
+	if config.FailuresBeforeDemote <= 0 {
+		config.FailuresBeforeDemote = defaults.FailuresBeforeDemote
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	c := &FailoverRPCClient{
+		config:    config,
+		logger:    log.WithField("module", "rpc-failover"),
+		endpoints: endpoints,
+		health:    make(map[string]*RPCEndpointHealth),
+	}
+	for i, endpoint := range endpoints {
+		if _, found := c.health[endpoint.Name]; found {
+			return nil, errors.Errorf("duplicated RPC endpoint name %s", endpoint.Name)
+		}
+		// the endpoints are used in the configured order until the first health check
+		c.health[endpoint.Name] = &RPCEndpointHealth{Name: endpoint.Name, Healthy: true}
+		c.ranking = append(c.ranking, i)
+	}
+	return c, nil
+}
+
+// NewFailoverRPCClientFromURLs creates the failover client for the Tendermint RPC urls (e.g. network.TmEndpoint)
+func NewFailoverRPCClientFromURLs(urls []string, config RPCFailoverConfig) (*FailoverRPCClient, error) {
+	endpoints := make([]RPCEndpoint, 0, len(urls))
+	for _, url := range urls {
+		rpcClient, err := rpchttp.New(url, "/websocket")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create the RPC client for %s", url)
+		}
+		endpoints = append(endpoints, RPCEndpoint{Name: url, Client: rpcClient})
+	}
+	return NewFailoverRPCClient(endpoints, config)
+}
+
+// Run checks the endpoints health every HealthCheckInterval until ctx is done
+func (c *FailoverRPCClient) Run(ctx context.Context) {
+	ticker := c.config.Clock.NewTicker(c.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		c.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// CheckHealth queries the status of all the endpoints and ranks them again
+func (c *FailoverRPCClient) CheckHealth(ctx context.Context) []RPCEndpointHealth {
+	results := make([]RPCEndpointHealth, len(c.endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range c.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint RPCEndpoint) {
+			defer wg.Done()
+			results[i] = c.checkEndpoint(ctx, endpoint)
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	var highest int64
+	for _, result := range results {
+		if result.LastError == nil && result.LatestHeight > highest {
+			highest = result.LatestHeight
+		}
+	}
+
+	c.mux.Lock()
+	for i := range results {
+		if results[i].LastError == nil {
+			results[i].BlockLag = highest - results[i].LatestHeight
+			results[i].Healthy = results[i].BlockLag <= c.config.MaxBlockLag
+		}
+		c.health[results[i].Name] = &results[i]
+	}
+	c.rank()
+	health := c.healthSnapshot()
+	c.mux.Unlock()
+
+	for _, endpoint := range health {
+		if !endpoint.Healthy {
+			c.logger.WithField("endpoint", endpoint.Name).WithField("blockLag", endpoint.BlockLag).WithError(endpoint.LastError).Warningln("RPC endpoint demoted")
+		}
+	}
+	if c.config.OnHealthUpdate != nil {
+		c.config.OnHealthUpdate(health)
+	}
+	return health
+}
+
+// Health returns the health of all the endpoints, best first
+func (c *FailoverRPCClient) Health() []RPCEndpointHealth {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.healthSnapshot()
+}
+
+func (c *FailoverRPCClient) checkEndpoint(ctx context.Context, endpoint RPCEndpoint) RPCEndpointHealth {
+	checkCtx, cancelFn := context.WithTimeout(ctx, c.config.HealthCheckTimeout)
+	defer cancelFn()
+
+	start := c.config.Clock.Now()
+	status, err := endpoint.Client.Status(checkCtx)
+	health := RPCEndpointHealth{Name: endpoint.Name, Latency: c.config.Clock.Since(start), CheckedAt: c.config.Clock.Now()}
+	if err != nil {
+		health.LastError = errors.Wrap(err, "failed to get the endpoint status")
+		return health
+	}
+	health.LatestHeight = status.SyncInfo.LatestBlockHeight
+	if status.SyncInfo.CatchingUp {
+		health.LastError = errors.New("the endpoint is catching up")
+	}
+	return health
+}
+
+// rank sorts the endpoints: healthy first, then by block lag and latency. It must be called holding the mutex
+func (c *FailoverRPCClient) rank() {
+	sort.SliceStable(c.ranking, func(i, j int) bool {
+		a := c.health[c.endpoints[c.ranking[i]].Name]
+		b := c.health[c.endpoints[c.ranking[j]].Name]
+		if c.isUsable(a) != c.isUsable(b) {
+			return c.isUsable(a)
+		}
+		if a.BlockLag != b.BlockLag {
+			return a.BlockLag < b.BlockLag
+		}
+		return a.Latency < b.Latency
+	})
+}
+
+func (c *FailoverRPCClient) isUsable(health *RPCEndpointHealth) bool {
+	return health.Healthy && health.Failures < c.config.FailuresBeforeDemote
+}
+
+// healthSnapshot must be called holding the mutex
+func (c *FailoverRPCClient) healthSnapshot() []RPCEndpointHealth {
+	health := make([]RPCEndpointHealth, 0, len(c.ranking))
+	for _, index := range c.ranking {
+		endpointHealth := *c.health[c.endpoints[index].Name]
+		endpointHealth.Healthy = c.isUsable(&endpointHealth)
+		health = append(health, endpointHealth)
+	}
+	return health
+}
+
+func (c *FailoverRPCClient) rankedEndpoints() []RPCEndpoint {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	endpoints := make([]RPCEndpoint, 0, len(c.ranking))
+	for _, index := range c.ranking {
+		endpoints = append(endpoints, c.endpoints[index])
+	}
+	return endpoints
+}
+
+func (c *FailoverRPCClient) recordCall(endpoint RPCEndpoint, err error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	health := c.health[endpoint.Name]
+	if err == nil {
+		health.Failures = 0
+		return
+	}
+	health.Failures++
+	health.LastError = err
+	if health.Failures == c.config.FailuresBeforeDemote {
+		c.rank()
+	}
+}
+
+// call runs fn in the endpoints, best first, until one of them answers
+func (c *FailoverRPCClient) call(ctx context.Context, fn func(rpcClient client.TendermintRPC) error) error {
+	var err error
+	for _, endpoint := range c.rankedEndpoints() {
+		err = fn(endpoint.Client)
+		if err == nil || !isEndpointFailure(ctx, err) {
+			c.recordCall(endpoint, nil)
+			return err
+		}
+		c.recordCall(endpoint, err)
+		c.logger.WithField("endpoint", endpoint.Name).WithError(err).Debugln("RPC call failed, trying the next endpoint")
+	}
+	return err
+}
+
+// broadcast sends the tx like call, but it only fails over when the connection to the endpoint could not be
+// established, so the tx was surely not received. After other transport errors the next endpoint is asked for the tx:
+// its result is returned if the tx is already committed, and ErrBroadcastOutcomeUnknown otherwise
+func (c *FailoverRPCClient) broadcast(ctx context.Context, tx cmttypes.Tx, fn func(rpcClient client.TendermintRPC) error) (*ctypes.ResultTx, error) {
+	endpoints := c.rankedEndpoints()
+	var err error
+	for i, endpoint := range endpoints {
+		err = fn(endpoint.Client)
+		if err == nil || !isEndpointFailure(ctx, err) {
+			c.recordCall(endpoint, nil)
+			return nil, err
+		}
+		c.recordCall(endpoint, err)
+		if isDialFailure(err) {
+			c.logger.WithField("endpoint", endpoint.Name).WithError(err).Debugln("RPC broadcast not sent, trying the next endpoint")
+			continue
+		}
+
+		if i+1 < len(endpoints) {
+			if committed, txErr := endpoints[i+1].Client.Tx(ctx, tx.Hash(), false); txErr == nil {
+				return committed, nil
+			}
+		}
+		return nil, errors.Wrapf(ErrBroadcastOutcomeUnknown, "tx %X was sent to %s: %s", tx.Hash(), endpoint.Name, err.Error())
+	}
+	return nil, err
+}
+
+// isDialFailure tells if the request failed before connecting to the endpoint
+func isDialFailure(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isEndpointFailure tells if the error was caused by the endpoint not answering, rather than by the request
+func isEndpointFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var rpcErr *rpctypes.RPCError
+	return !errors.As(err, &rpcErr)
+}
+
+func (c *FailoverRPCClient) ABCIInfo(ctx context.Context) (res *ctypes.ResultABCIInfo, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.ABCIInfo(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) ABCIQuery(ctx context.Context, path string, data bytes.HexBytes) (res *ctypes.ResultABCIQuery, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.ABCIQuery(ctx, path, data)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) ABCIQueryWithOptions(ctx context.Context, path string, data bytes.HexBytes, opts rpcclient.ABCIQueryOptions) (res *ctypes.ResultABCIQuery, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.ABCIQueryWithOptions(ctx, path, data, opts)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) BroadcastTxCommit(ctx context.Context, tx cmttypes.Tx) (res *ctypes.ResultBroadcastTxCommit, err error) {
+	committed, err := c.broadcast(ctx, tx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.BroadcastTxCommit(ctx, tx)
+		return err
+	})
+	if committed != nil {
+		res = &ctypes.ResultBroadcastTxCommit{
+			CheckTx:   abci.ResponseCheckTx{Code: committed.TxResult.Code, Codespace: committed.TxResult.Codespace},
+			DeliverTx: committed.TxResult,
+			Hash:      committed.Hash,
+			Height:    committed.Height,
+		}
+	}
+	return res, err
+}
+
+func (c *FailoverRPCClient) BroadcastTxAsync(ctx context.Context, tx cmttypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	committed, err := c.broadcast(ctx, tx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.BroadcastTxAsync(ctx, tx)
+		return err
+	})
+	if committed != nil {
+		res = broadcastResultFromCommittedTx(committed)
+	}
+	return res, err
+}
+
+func (c *FailoverRPCClient) BroadcastTxSync(ctx context.Context, tx cmttypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	committed, err := c.broadcast(ctx, tx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.BroadcastTxSync(ctx, tx)
+		return err
+	})
+	if committed != nil {
+		res = broadcastResultFromCommittedTx(committed)
+	}
+	return res, err
+}
+
+func broadcastResultFromCommittedTx(committed *ctypes.ResultTx) *ctypes.ResultBroadcastTx {
+	return &ctypes.ResultBroadcastTx{
+		Code:      committed.TxResult.Code,
+		Data:      committed.TxResult.Data,
+		Log:       committed.TxResult.Log,
+		Codespace: committed.TxResult.Codespace,
+		Hash:      committed.Hash,
+	}
+}
+
+func (c *FailoverRPCClient) Validators(ctx context.Context, height *int64, page, perPage *int) (res *ctypes.ResultValidators, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.Validators(ctx, height, page, perPage)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) Status(ctx context.Context) (res *ctypes.ResultStatus, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.Status(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) Block(ctx context.Context, height *int64) (res *ctypes.ResultBlock, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.Block(ctx, height)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (res *ctypes.ResultBlockchainInfo, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.BlockchainInfo(ctx, minHeight, maxHeight)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) Commit(ctx context.Context, height *int64) (res *ctypes.ResultCommit, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.Commit(ctx, height)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) Tx(ctx context.Context, hash []byte, prove bool) (res *ctypes.ResultTx, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.Tx(ctx, hash, prove)
+		return err
+	})
+	return res, err
+}
+
+func (c *FailoverRPCClient) TxSearch(ctx context.Context, query string, prove bool, page, perPage *int, orderBy string) (res *ctypes.ResultTxSearch, err error) {
+	err = c.call(ctx, func(rpcClient client.TendermintRPC) (err error) {
+		res, err = rpcClient.TxSearch(ctx, query, prove, page, perPage, orderBy)
+		return err
+	})
+	return res, err
+}
@@ -0,0 +1,142 @@
+package chain
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRPCEndpoint struct {
+	client.TendermintRPC
+	height   int64
+	down     bool
+	txErr    error
+	txCalls  int
+	catching bool
+	// connectionLost makes the broadcasts fail after sending the request
+	connectionLost bool
+	broadcasts     int
+}
+
+func (e *fakeRPCEndpoint) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	if e.down {
+		return nil, errors.New("connection refused")
+	}
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: e.height, CatchingUp: e.catching}}, nil
+}
+
+func (e *fakeRPCEndpoint) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	e.txCalls++
+	if e.down {
+		return nil, errors.New("connection refused")
+	}
+	if e.txErr != nil {
+		return nil, e.txErr
+	}
+	return &ctypes.ResultTx{Hash: hash, Height: e.height}, nil
+}
+
+func (e *fakeRPCEndpoint) BroadcastTxSync(ctx context.Context, tx cmttypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	if e.down {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	e.broadcasts++
+	if e.connectionLost {
+		return nil, errors.New("post failed: EOF")
+	}
+	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
+
+func TestFailoverRPCClientDemotesLaggingEndpoints(t *testing.T) {
+	lagging := &fakeRPCEndpoint{height: 90}
+	upToDate := &fakeRPCEndpoint{height: 100}
+	var reported []RPCEndpointHealth
+	failover, err := NewFailoverRPCClient([]RPCEndpoint{
+		{Name: "lagging", Client: lagging},
+		{Name: "up-to-date", Client: upToDate},
+	}, RPCFailoverConfig{MaxBlockLag: 5, OnHealthUpdate: func(health []RPCEndpointHealth) { reported = health }})
+	assert.NoError(t, err)
+
+	res, err := failover.Tx(context.Background(), []byte{1}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(90), res.Height)
+
+	failover.CheckHealth(context.Background())
+	assert.Len(t, reported, 2)
+	assert.Equal(t, "up-to-date", reported[0].Name)
+	assert.True(t, reported[0].Healthy)
+	assert.Equal(t, "lagging", reported[1].Name)
+	assert.Equal(t, int64(10), reported[1].BlockLag)
+	assert.False(t, reported[1].Healthy)
+
+	res, err = failover.Tx(context.Background(), []byte{1}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), res.Height)
+}
+
+func TestFailoverRPCClientRetriesUnreachableEndpoints(t *testing.T) {
+	primary := &fakeRPCEndpoint{height: 100}
+	secondary := &fakeRPCEndpoint{height: 100}
+	failover, err := NewFailoverRPCClient([]RPCEndpoint{
+		{Name: "primary", Client: primary},
+		{Name: "secondary", Client: secondary},
+	}, RPCFailoverConfig{FailuresBeforeDemote: 2})
+	assert.NoError(t, err)
+
+	primary.down = true
+	for i := 0; i < 3; i++ {
+		_, err = failover.BroadcastTxSync(context.Background(), cmttypes.Tx("tx"))
+		assert.NoError(t, err)
+		_, err = failover.Tx(context.Background(), []byte{1}, false)
+		assert.NoError(t, err)
+	}
+	// the primary endpoint is not called anymore after being demoted
+	assert.Equal(t, 1, primary.txCalls)
+	assert.Equal(t, "secondary", failover.Health()[0].Name)
+	assert.False(t, failover.Health()[1].Healthy)
+
+	// errors returned by a node that answered are not retried
+	secondary.txErr = &rpctypes.RPCError{Code: -32603, Message: "tx not found"}
+	_, err = failover.Tx(context.Background(), []byte{1}, false)
+	assert.Error(t, err)
+	assert.Equal(t, 1, primary.txCalls)
+
+	primary.down = false
+	health := failover.CheckHealth(context.Background())
+	assert.True(t, health[0].Healthy)
+	assert.True(t, health[1].Healthy)
+
+	_, err = NewFailoverRPCClient(nil, DefaultRPCFailoverConfig())
+	assert.Error(t, err)
+}
+
+func TestFailoverRPCClientDoesNotRebroadcastAfterLostConnection(t *testing.T) {
+	primary := &fakeRPCEndpoint{height: 100, connectionLost: true}
+	secondary := &fakeRPCEndpoint{height: 100, txErr: &rpctypes.RPCError{Code: -32603, Message: "tx not found"}}
+	failover, err := NewFailoverRPCClient([]RPCEndpoint{
+		{Name: "primary", Client: primary},
+		{Name: "secondary", Client: secondary},
+	}, DefaultRPCFailoverConfig())
+	assert.NoError(t, err)
+
+	// the tx may be in the primary mempool, so it is not sent to the secondary endpoint
+	_, err = failover.BroadcastTxSync(context.Background(), cmttypes.Tx("tx"))
+	assert.True(t, errors.Is(err, ErrBroadcastOutcomeUnknown))
+	assert.Equal(t, 1, primary.broadcasts)
+	assert.Equal(t, 0, secondary.broadcasts)
+	assert.Equal(t, 1, secondary.txCalls)
+
+	// the result of the tx committed meanwhile is returned
+	secondary.txErr = nil
+	res, err := failover.BroadcastTxSync(context.Background(), cmttypes.Tx("tx"))
+	assert.NoError(t, err)
+	assert.Equal(t, cmttypes.Tx("tx").Hash(), []byte(res.Hash))
+	assert.Equal(t, 0, secondary.broadcasts)
+}
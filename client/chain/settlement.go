@@ -0,0 +1,78 @@
+package chain
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// NewMsgSubmitMarketForcedSettlementProposal builds a governance proposal to settle a derivative market. A nil
+// settlementPrice settles the market at the current oracle price
+func NewMsgSubmitMarketForcedSettlementProposal(
+	proposerAddress string,
+	title string,
+	description string,
+	marketId string,
+	settlementPrice *sdk.Dec,
+	deposit sdk.Coins,
+) (*govtypes.MsgSubmitProposal, error) {
+	proposer, err := sdk.AccAddressFromBech32(proposerAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid proposer address %s", proposerAddress)
+	}
+
+	proposal := exchangetypes.NewMarketForcedSettlementProposal(title, description, marketId, settlementPrice)
+	if err := proposal.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	msg, err := govtypes.NewMsgSubmitProposal(proposal, deposit, proposer)
+	if err != nil {
+		return nil, err
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func newMsgDemolishBinaryOptionsMarket(senderAddress string, marketId string, settlementPrice sdk.Dec) (*exchangetypes.MsgAdminUpdateBinaryOptionsMarket, error) {
+	msg := &exchangetypes.MsgAdminUpdateBinaryOptionsMarket{
+		Sender:          senderAddress,
+		MarketId:        marketId,
+		SettlementPrice: &settlementPrice,
+		Status:          exchangetypes.MarketStatus_Demolished,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// NewMsgSettleBinaryOptionsMarket builds the message the market admin sends to settle a binary options market at the
+// given price (between 0 and 1)
+func NewMsgSettleBinaryOptionsMarket(senderAddress string, marketId string, settlementPrice sdk.Dec) (*exchangetypes.MsgAdminUpdateBinaryOptionsMarket, error) {
+	if settlementPrice.IsNil() || settlementPrice.IsNegative() {
+		return nil, errors.New("settlement price can not be empty or negative")
+	}
+	return newMsgDemolishBinaryOptionsMarket(senderAddress, marketId, settlementPrice)
+}
+
+// NewMsgRefundBinaryOptionsMarket builds the message the market admin sends to demolish a binary options market
+// refunding the positions margin instead of settling them
+func NewMsgRefundBinaryOptionsMarket(senderAddress string, marketId string) (*exchangetypes.MsgAdminUpdateBinaryOptionsMarket, error) {
+	return newMsgDemolishBinaryOptionsMarket(senderAddress, marketId, exchangetypes.BinaryOptionsMarketRefundFlagPrice)
+}
+
+// ExpiryFuturesSettlementPrice returns the price an expiry futures market was settled at. The second value is false
+// if the market has not been settled yet
+func ExpiryFuturesSettlementPrice(info exchangetypes.ExpiryFuturesMarketInfo) (sdk.Dec, bool) {
+	if info.SettlementPrice.IsNil() || !info.SettlementPrice.IsPositive() {
+		return sdk.Dec{}, false
+	}
+	return info.SettlementPrice, true
+}
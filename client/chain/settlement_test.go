@@ -0,0 +1,62 @@
+package chain
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+const testDerivativeMarketId = "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+
+func TestNewMsgSubmitMarketForcedSettlementProposal(t *testing.T) {
+	settlementPrice := sdk.MustNewDecFromStr("25000")
+	depositCoin, err := ChainFormattedCoin(INJToken, decimal.NewFromInt(50))
+	assert.NoError(t, err)
+	deposit := sdk.NewCoins(depositCoin)
+
+	msg, err := NewMsgSubmitMarketForcedSettlementProposal("inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku", "Settle market", "Settle the market", testDerivativeMarketId, &settlementPrice, deposit)
+	assert.NoError(t, err)
+
+	proposal, ok := msg.GetContent().(*exchangetypes.MarketForcedSettlementProposal)
+	assert.True(t, ok)
+	assert.Equal(t, testDerivativeMarketId, proposal.MarketId)
+	assert.Equal(t, settlementPrice, *proposal.SettlementPrice)
+	assert.Equal(t, deposit, msg.InitialDeposit)
+
+	_, err = NewMsgSubmitMarketForcedSettlementProposal("inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku", "Settle market", "Settle the market", "0x01", &settlementPrice, deposit)
+	assert.Error(t, err)
+}
+
+func TestNewMsgSettleBinaryOptionsMarket(t *testing.T) {
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+
+	msg, err := NewMsgSettleBinaryOptionsMarket(sender, testDerivativeMarketId, sdk.OneDec())
+	assert.NoError(t, err)
+	assert.Equal(t, exchangetypes.MarketStatus_Demolished, msg.Status)
+	assert.Equal(t, sdk.OneDec(), *msg.SettlementPrice)
+
+	_, err = NewMsgSettleBinaryOptionsMarket(sender, testDerivativeMarketId, sdk.NewDec(2))
+	assert.Error(t, err)
+	_, err = NewMsgSettleBinaryOptionsMarket(sender, testDerivativeMarketId, exchangetypes.BinaryOptionsMarketRefundFlagPrice)
+	assert.Error(t, err)
+
+	msg, err = NewMsgRefundBinaryOptionsMarket(sender, testDerivativeMarketId)
+	assert.NoError(t, err)
+	assert.Equal(t, exchangetypes.BinaryOptionsMarketRefundFlagPrice, *msg.SettlementPrice)
+}
+
+func TestExpiryFuturesSettlementPrice(t *testing.T) {
+	_, settled := ExpiryFuturesSettlementPrice(exchangetypes.ExpiryFuturesMarketInfo{MarketId: testDerivativeMarketId})
+	assert.False(t, settled)
+
+	_, settled = ExpiryFuturesSettlementPrice(exchangetypes.ExpiryFuturesMarketInfo{SettlementPrice: sdk.ZeroDec()})
+	assert.False(t, settled)
+
+	price, settled := ExpiryFuturesSettlementPrice(exchangetypes.ExpiryFuturesMarketInfo{SettlementPrice: sdk.NewDec(25000)})
+	assert.True(t, settled)
+	assert.Equal(t, sdk.NewDec(25000), price)
+}
@@ -0,0 +1,335 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// SignDocPreview is a human readable summary of a transaction pending to be signed, to be displayed in
+// confirmation screens
+type SignDocPreview struct {
+	ChainId       string
+	AccountNumber uint64
+	Sequence      uint64
+	// Messages has one description for each message in the transaction
+	Messages []string
+	Fee      string
+	Gas      uint64
+	Memo     string
+}
+
+// String renders the preview as plain text, one field per line
+func (p SignDocPreview) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Chain: %s\n", p.ChainId)
+	fmt.Fprintf(&builder, "Account: %d (sequence %d)\n", p.AccountNumber, p.Sequence)
+	for i, message := range p.Messages {
+		fmt.Fprintf(&builder, "Message %d/%d: %s\n", i+1, len(p.Messages), message)
+	}
+	fmt.Fprintf(&builder, "Fee: %s (gas %d)\n", p.Fee, p.Gas)
+	if p.Memo != "" {
+		fmt.Fprintf(&builder, "Memo: %s\n", p.Memo)
+	}
+	return builder.String()
+}
+
+// MsgTemplate describes a message for the sign doc preview
+type MsgTemplate func(renderer *SignDocRenderer, msg sdk.Msg) string
+
+// SignDocRenderer builds sign doc previews using the markets assistant tokens and markets to show amounts,
+// prices and quantities in human readable units. Messages without a template are described by their type URL
+type SignDocRenderer struct {
+	assistant  MarketsAssistant
+	templates  map[string]MsgTemplate
+	aminoCodec *codec.LegacyAmino
+}
+
+func NewSignDocRenderer(assistant MarketsAssistant) *SignDocRenderer {
+	aminoCodec := codec.NewLegacyAmino()
+	sdk.RegisterLegacyAminoCodec(aminoCodec)
+	banktypes.RegisterLegacyAminoCodec(aminoCodec)
+	exchangetypes.RegisterLegacyAminoCodec(aminoCodec)
+
+	renderer := &SignDocRenderer{
+		assistant:  assistant,
+		templates:  make(map[string]MsgTemplate),
+		aminoCodec: aminoCodec,
+	}
+
+	renderer.RegisterTemplate(&banktypes.MsgSend{}, describeMsgSend)
+	renderer.RegisterTemplate(&exchangetypes.MsgDeposit{}, describeMsgDeposit)
+	renderer.RegisterTemplate(&exchangetypes.MsgWithdraw{}, describeMsgWithdraw)
+	renderer.RegisterTemplate(&exchangetypes.MsgCreateSpotLimitOrder{}, describeMsgCreateSpotLimitOrder)
+	renderer.RegisterTemplate(&exchangetypes.MsgCreateSpotMarketOrder{}, describeMsgCreateSpotMarketOrder)
+	renderer.RegisterTemplate(&exchangetypes.MsgBatchCreateSpotLimitOrders{}, describeMsgBatchCreateSpotLimitOrders)
+	renderer.RegisterTemplate(&exchangetypes.MsgCancelSpotOrder{}, describeMsgCancelSpotOrder)
+	renderer.RegisterTemplate(&exchangetypes.MsgBatchCancelSpotOrders{}, describeMsgBatchCancelSpotOrders)
+	renderer.RegisterTemplate(&exchangetypes.MsgCreateDerivativeLimitOrder{}, describeMsgCreateDerivativeLimitOrder)
+	renderer.RegisterTemplate(&exchangetypes.MsgCreateDerivativeMarketOrder{}, describeMsgCreateDerivativeMarketOrder)
+	renderer.RegisterTemplate(&exchangetypes.MsgBatchCreateDerivativeLimitOrders{}, describeMsgBatchCreateDerivativeLimitOrders)
+	renderer.RegisterTemplate(&exchangetypes.MsgCancelDerivativeOrder{}, describeMsgCancelDerivativeOrder)
+	renderer.RegisterTemplate(&exchangetypes.MsgBatchCancelDerivativeOrders{}, describeMsgBatchCancelDerivativeOrders)
+	renderer.RegisterTemplate(&exchangetypes.MsgBatchUpdateOrders{}, describeMsgBatchUpdateOrders)
+
+	return renderer
+}
+
+// RegisterTemplate sets the template used for the messages with the same type as msg, replacing the existing one
+func (r *SignDocRenderer) RegisterTemplate(msg sdk.Msg, template MsgTemplate) {
+	r.templates[sdk.MsgTypeURL(msg)] = template
+}
+
+// DescribeMsg returns the description of a single message
+func (r *SignDocRenderer) DescribeMsg(msg sdk.Msg) string {
+	typeURL := sdk.MsgTypeURL(msg)
+	if template, found := r.templates[typeURL]; found {
+		return template(r, msg)
+	}
+	return typeURL
+}
+
+// Render builds the preview of a transaction built with the tx factory, before signing it
+func (r *SignDocRenderer) Render(signerData authsigning.SignerData, tx authsigning.Tx) SignDocPreview {
+	return r.preview(signerData.ChainID, signerData.AccountNumber, signerData.Sequence, tx.GetMsgs(), tx.GetFee(), tx.GetGas(), tx.GetMemo())
+}
+
+// aminoSignDoc mirrors legacytx.StdSignDoc with the amino JSON encoding of the numbers
+type aminoSignDoc struct {
+	AccountNumber uint64            `json:"account_number,string"`
+	ChainId       string            `json:"chain_id"`
+	Fee           json.RawMessage   `json:"fee"`
+	Memo          string            `json:"memo"`
+	Msgs          []json.RawMessage `json:"msgs"`
+	Sequence      uint64            `json:"sequence,string"`
+}
+
+// RenderAminoJSON builds the preview of the amino JSON sign doc bytes (the bytes signed with SIGN_MODE_LEGACY_AMINO_JSON,
+// as shown by hardware wallets). Only the bank and exchange messages can be decoded
+func (r *SignDocRenderer) RenderAminoJSON(signDocBytes []byte) (SignDocPreview, error) {
+	var signDoc aminoSignDoc
+	if err := json.Unmarshal(signDocBytes, &signDoc); err != nil {
+		return SignDocPreview{}, errors.Wrap(err, "failed to parse the amino JSON sign doc")
+	}
+
+	var fee legacytx.StdFee
+	if err := r.aminoCodec.UnmarshalJSON(signDoc.Fee, &fee); err != nil {
+		return SignDocPreview{}, errors.Wrap(err, "failed to parse the sign doc fee")
+	}
+
+	msgs := make([]sdk.Msg, 0, len(signDoc.Msgs))
+	for i, rawMsg := range signDoc.Msgs {
+		var msg sdk.Msg
+		if err := r.aminoCodec.UnmarshalJSON(rawMsg, &msg); err != nil {
+			return SignDocPreview{}, errors.Wrapf(err, "failed to parse the sign doc message %d", i)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return r.preview(signDoc.ChainId, signDoc.AccountNumber, signDoc.Sequence, msgs, fee.Amount, fee.Gas, signDoc.Memo), nil
+}
+
+func (r *SignDocRenderer) preview(chainId string, accountNumber uint64, sequence uint64, msgs []sdk.Msg, fee sdk.Coins, gas uint64, memo string) SignDocPreview {
+	preview := SignDocPreview{
+		ChainId:       chainId,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+		Fee:           r.FormatCoins(fee),
+		Gas:           gas,
+		Memo:          memo,
+	}
+	for _, msg := range msgs {
+		preview.Messages = append(preview.Messages, r.DescribeMsg(msg))
+	}
+	return preview
+}
+
+// FormatCoin returns the amount in human readable units with the token symbol. Unknown denoms are kept in chain format
+func (r *SignDocRenderer) FormatCoin(coin sdk.Coin) string {
+	token, found := r.assistant.tokensByDenom[coin.Denom]
+	if !found || coin.Amount.IsNil() {
+		return coin.String()
+	}
+	return fmt.Sprintf("%s %s", decimal.NewFromBigInt(coin.Amount.BigInt(), -token.Decimals).String(), token.Symbol)
+}
+
+func (r *SignDocRenderer) FormatCoins(coins sdk.Coins) string {
+	if len(coins) == 0 {
+		return "none"
+	}
+	formatted := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		formatted = append(formatted, r.FormatCoin(coin))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func (r *SignDocRenderer) describeSpotOrder(marketId string, orderType exchangetypes.OrderType, info exchangetypes.OrderInfo) string {
+	market, found := r.assistant.spotMarkets[marketId]
+	if !found {
+		return fmt.Sprintf("%s %s at %s in market %s", orderType.String(), info.Quantity.String(), info.Price.String(), marketId)
+	}
+	return fmt.Sprintf(
+		"%s %s %s at %s",
+		orderType.String(),
+		market.QuantityFromChainFormat(info.Quantity).String(),
+		market.Ticker,
+		market.PriceFromChainFormat(info.Price).String(),
+	)
+}
+
+func (r *SignDocRenderer) describeDerivativeOrder(marketId string, orderType exchangetypes.OrderType, info exchangetypes.OrderInfo, margin sdk.Dec) string {
+	market, found := r.assistant.derivativeMarkets[marketId]
+	if !found {
+		return fmt.Sprintf("%s %s at %s with %s margin in market %s", orderType.String(), info.Quantity.String(), info.Price.String(), margin.String(), marketId)
+	}
+	return fmt.Sprintf(
+		"%s %s %s at %s with %s %s margin",
+		orderType.String(),
+		market.QuantityFromChainFormat(info.Quantity).String(),
+		market.Ticker,
+		market.PriceFromChainFormat(info.Price).String(),
+		market.MarginFromChainFormat(margin).String(),
+		market.QuoteToken.Symbol,
+	)
+}
+
+func (r *SignDocRenderer) marketName(marketId string) string {
+	if market, found := r.assistant.spotMarkets[marketId]; found {
+		return market.Ticker
+	}
+	if market, found := r.assistant.derivativeMarkets[marketId]; found {
+		return market.Ticker
+	}
+	return marketId
+}
+
+func orderIdentifier(orderHash string, cid string) string {
+	if orderHash == "" {
+		return "with cid " + cid
+	}
+	return orderHash
+}
+
+func joinOrderDescriptions(descriptions []string) string {
+	if len(descriptions) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(descriptions, "; ")
+}
+
+func describeMsgSend(r *SignDocRenderer, msg sdk.Msg) string {
+	send := msg.(*banktypes.MsgSend)
+	return fmt.Sprintf("Send %s from %s to %s", r.FormatCoins(send.Amount), send.FromAddress, send.ToAddress)
+}
+
+func describeMsgDeposit(r *SignDocRenderer, msg sdk.Msg) string {
+	deposit := msg.(*exchangetypes.MsgDeposit)
+	return fmt.Sprintf("Deposit %s into subaccount %s", r.FormatCoin(deposit.Amount), deposit.SubaccountId)
+}
+
+func describeMsgWithdraw(r *SignDocRenderer, msg sdk.Msg) string {
+	withdraw := msg.(*exchangetypes.MsgWithdraw)
+	return fmt.Sprintf("Withdraw %s from subaccount %s", r.FormatCoin(withdraw.Amount), withdraw.SubaccountId)
+}
+
+func describeMsgCreateSpotLimitOrder(r *SignDocRenderer, msg sdk.Msg) string {
+	order := msg.(*exchangetypes.MsgCreateSpotLimitOrder).Order
+	return "Create spot limit order: " + r.describeSpotOrder(order.MarketId, order.OrderType, order.OrderInfo)
+}
+
+func describeMsgCreateSpotMarketOrder(r *SignDocRenderer, msg sdk.Msg) string {
+	order := msg.(*exchangetypes.MsgCreateSpotMarketOrder).Order
+	return "Create spot market order: " + r.describeSpotOrder(order.MarketId, order.OrderType, order.OrderInfo)
+}
+
+func describeMsgBatchCreateSpotLimitOrders(r *SignDocRenderer, msg sdk.Msg) string {
+	orders := msg.(*exchangetypes.MsgBatchCreateSpotLimitOrders).Orders
+	descriptions := make([]string, 0, len(orders))
+	for _, order := range orders {
+		descriptions = append(descriptions, r.describeSpotOrder(order.MarketId, order.OrderType, order.OrderInfo))
+	}
+	return fmt.Sprintf("Create %d spot limit orders%s", len(orders), joinOrderDescriptions(descriptions))
+}
+
+func describeMsgCancelSpotOrder(r *SignDocRenderer, msg sdk.Msg) string {
+	cancel := msg.(*exchangetypes.MsgCancelSpotOrder)
+	return fmt.Sprintf("Cancel spot order %s in %s", orderIdentifier(cancel.OrderHash, cancel.Cid), r.marketName(cancel.MarketId))
+}
+
+func describeMsgBatchCancelSpotOrders(r *SignDocRenderer, msg sdk.Msg) string {
+	return fmt.Sprintf("Cancel %d spot orders", len(msg.(*exchangetypes.MsgBatchCancelSpotOrders).Data))
+}
+
+func describeMsgCreateDerivativeLimitOrder(r *SignDocRenderer, msg sdk.Msg) string {
+	order := msg.(*exchangetypes.MsgCreateDerivativeLimitOrder).Order
+	return "Create derivative limit order: " + r.describeDerivativeOrder(order.MarketId, order.OrderType, order.OrderInfo, order.Margin)
+}
+
+func describeMsgCreateDerivativeMarketOrder(r *SignDocRenderer, msg sdk.Msg) string {
+	order := msg.(*exchangetypes.MsgCreateDerivativeMarketOrder).Order
+	return "Create derivative market order: " + r.describeDerivativeOrder(order.MarketId, order.OrderType, order.OrderInfo, order.Margin)
+}
+
+func describeMsgBatchCreateDerivativeLimitOrders(r *SignDocRenderer, msg sdk.Msg) string {
+	orders := msg.(*exchangetypes.MsgBatchCreateDerivativeLimitOrders).Orders
+	descriptions := make([]string, 0, len(orders))
+	for _, order := range orders {
+		descriptions = append(descriptions, r.describeDerivativeOrder(order.MarketId, order.OrderType, order.OrderInfo, order.Margin))
+	}
+	return fmt.Sprintf("Create %d derivative limit orders%s", len(orders), joinOrderDescriptions(descriptions))
+}
+
+func describeMsgCancelDerivativeOrder(r *SignDocRenderer, msg sdk.Msg) string {
+	cancel := msg.(*exchangetypes.MsgCancelDerivativeOrder)
+	return fmt.Sprintf("Cancel derivative order %s in %s", orderIdentifier(cancel.OrderHash, cancel.Cid), r.marketName(cancel.MarketId))
+}
+
+func describeMsgBatchCancelDerivativeOrders(r *SignDocRenderer, msg sdk.Msg) string {
+	return fmt.Sprintf("Cancel %d derivative orders", len(msg.(*exchangetypes.MsgBatchCancelDerivativeOrders).Data))
+}
+
+func describeMsgBatchUpdateOrders(r *SignDocRenderer, msg sdk.Msg) string {
+	update := msg.(*exchangetypes.MsgBatchUpdateOrders)
+
+	var actions []string
+	if count := len(update.SpotMarketIdsToCancelAll); count > 0 {
+		actions = append(actions, fmt.Sprintf("cancel all spot orders in %d markets", count))
+	}
+	if count := len(update.DerivativeMarketIdsToCancelAll); count > 0 {
+		actions = append(actions, fmt.Sprintf("cancel all derivative orders in %d markets", count))
+	}
+	if count := len(update.BinaryOptionsMarketIdsToCancelAll); count > 0 {
+		actions = append(actions, fmt.Sprintf("cancel all binary options orders in %d markets", count))
+	}
+	if count := len(update.SpotOrdersToCancel); count > 0 {
+		actions = append(actions, fmt.Sprintf("cancel %d spot orders", count))
+	}
+	if count := len(update.DerivativeOrdersToCancel); count > 0 {
+		actions = append(actions, fmt.Sprintf("cancel %d derivative orders", count))
+	}
+	if count := len(update.BinaryOptionsOrdersToCancel); count > 0 {
+		actions = append(actions, fmt.Sprintf("cancel %d binary options orders", count))
+	}
+	for _, order := range update.SpotOrdersToCreate {
+		actions = append(actions, "create spot order "+r.describeSpotOrder(order.MarketId, order.OrderType, order.OrderInfo))
+	}
+	for _, order := range update.DerivativeOrdersToCreate {
+		actions = append(actions, "create derivative order "+r.describeDerivativeOrder(order.MarketId, order.OrderType, order.OrderInfo, order.Margin))
+	}
+	if count := len(update.BinaryOptionsOrdersToCreate); count > 0 {
+		actions = append(actions, fmt.Sprintf("create %d binary options orders", count))
+	}
+
+	return "Batch update orders" + joinOrderDescriptions(actions)
+}
@@ -0,0 +1,84 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/exchange"
+	derivativeExchangePB "github.com/InjectiveLabs/sdk-go/exchange/derivative_exchange_rpc/pb"
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+func signDocRendererForTests(t *testing.T) *SignDocRenderer {
+	mockExchange := exchange.MockExchangeClient{}
+	mockExchange.SpotMarketsResponses = append(mockExchange.SpotMarketsResponses, &spotExchangePB.MarketsResponse{
+		Markets: []*spotExchangePB.SpotMarketInfo{createINJUSDTSpotMarketInfo()},
+	})
+	mockExchange.DerivativeMarketsResponses = append(mockExchange.DerivativeMarketsResponses, &derivativeExchangePB.MarketsResponse{})
+	assistant, err := NewMarketsAssistantInitializedFromChain(context.Background(), &mockExchange)
+	assert.NoError(t, err)
+
+	return NewSignDocRenderer(assistant)
+}
+
+func TestSignDocRendererRenderAminoJSON(t *testing.T) {
+	renderer := signDocRendererForTests(t)
+	marketId := createINJUSDTSpotMarketInfo().MarketId
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+
+	orderMsg := &exchangetypes.MsgCreateSpotLimitOrder{
+		Sender: sender,
+		Order: exchangetypes.SpotOrder{
+			MarketId: marketId,
+			OrderInfo: exchangetypes.OrderInfo{
+				SubaccountId: "0xaf79152ac5df276d9a8e1e2e22822f971345e864000000000000000000000000",
+				FeeRecipient: sender,
+				Price:        sdk.MustNewDecFromStr("0.000000000012345"),
+				Quantity:     sdk.MustNewDecFromStr("1500000000000000000"),
+			},
+			OrderType: exchangetypes.OrderType_BUY_PO,
+		},
+	}
+	sendMsg := &banktypes.MsgSend{
+		FromAddress: sender,
+		ToAddress:   "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r",
+		Amount:      sdk.NewCoins(sdk.NewCoin("inj", sdkmath.NewInt(2500000000000000000))),
+	}
+	fee := legacytx.NewStdFee(150000, sdk.NewCoins(sdk.NewCoin("inj", sdkmath.NewInt(75000000000000))))
+
+	signDocBytes := legacytx.StdSignBytes("injective-888", 12, 3, 0, fee, []sdk.Msg{orderMsg, sendMsg}, "grid bot", nil)
+	preview, err := renderer.RenderAminoJSON(signDocBytes)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "injective-888", preview.ChainId)
+	assert.Equal(t, uint64(12), preview.AccountNumber)
+	assert.Equal(t, uint64(3), preview.Sequence)
+	assert.Equal(t, []string{
+		"Create spot limit order: BUY_PO 1.5 INJ/USDT at 12.345",
+		"Send 2.5 INJ from inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku to inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r",
+	}, preview.Messages)
+	assert.Equal(t, "0.000075 INJ", preview.Fee)
+	assert.Equal(t, uint64(150000), preview.Gas)
+	assert.Equal(t, "grid bot", preview.Memo)
+	assert.Contains(t, preview.String(), "Message 2/2: Send 2.5 INJ")
+}
+
+func TestSignDocRendererDescribeMsgWithoutTemplate(t *testing.T) {
+	renderer := signDocRendererForTests(t)
+
+	msg := &exchangetypes.MsgCancelSpotOrder{MarketId: "0x01", Cid: "my-order"}
+	assert.Equal(t, "Cancel spot order with cid my-order in 0x01", renderer.DescribeMsg(msg))
+
+	unknown := &banktypes.MsgMultiSend{}
+	assert.Equal(t, "/cosmos.bank.v1beta1.MsgMultiSend", renderer.DescribeMsg(unknown))
+
+	renderer.RegisterTemplate(unknown, func(renderer *SignDocRenderer, msg sdk.Msg) string { return "Multi send" })
+	assert.Equal(t, "Multi send", renderer.DescribeMsg(unknown))
+}
@@ -0,0 +1,293 @@
+package chain
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+var ErrSignPolicyDenied = errors.New("denied by the sign policy")
+
+// SignPolicyRule is the rule of a sign policy a tx breaks
+type SignPolicyRule string
+
+const (
+	// SignRuleMsgType is a msg whose type is not allowed
+	SignRuleMsgType SignPolicyRule = "msg_type"
+	// SignRuleMarket is an order in a market not allowed
+	SignRuleMarket SignPolicyRule = "market"
+	// SignRuleMaxOrderNotional is an order with a notional (price * quantity) greater than the policy limit
+	SignRuleMaxOrderNotional SignPolicyRule = "max_order_notional"
+	// SignRuleMaxTxNotional is a tx whose orders have a total notional greater than the policy limit
+	SignRuleMaxTxNotional SignPolicyRule = "max_tx_notional"
+	// SignRuleTimeWindow is a tx signed outside of the time windows of the policy
+	SignRuleTimeWindow SignPolicyRule = "time_window"
+)
+
+// SignPolicyRequest is a tx to sign, as seen by a SignPolicy
+type SignPolicyRequest struct {
+	Signer  sdk.AccAddress
+	ChainId string
+	Msgs    []sdk.Msg
+	// Metadata is the metadata sent with the sign request (e.g. the strategy name)
+	Metadata map[string]string
+	// Time is the time of the request, used for the time windows
+	Time time.Time
+}
+
+// SignPolicy decides what a signer may sign. It is evaluated before signing each tx, and the tx is not signed when it
+// returns an error (a *SignPolicyError for the txs breaking a rule)
+type SignPolicy interface {
+	Evaluate(req SignPolicyRequest) error
+}
+
+type SignPolicyError struct {
+	Rule   SignPolicyRule
+	Detail string
+}
+
+func (e *SignPolicyError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrSignPolicyDenied.Error(), e.Rule, e.Detail)
+}
+
+func (e *SignPolicyError) Unwrap() error {
+	return ErrSignPolicyDenied
+}
+
+// PermissiveSignPolicy allows signing every tx. It is the default policy of signer.Server
+type PermissiveSignPolicy struct{}
+
+func (PermissiveSignPolicy) Evaluate(SignPolicyRequest) error {
+	return nil
+}
+
+// SignTimeWindow is a daily time range, in UTC, in which the txs can be signed
+type SignTimeWindow struct {
+	// Weekdays are the days of the window (e.g. ["monday", "friday"]). Empty means every day
+	Weekdays []string `yaml:"weekdays"`
+	// Start and End are the window times of the day, as HH:MM. A window ending before it starts ends the next day
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+// SignPolicyRules are the declarative rules of a RuleSignPolicy. Empty rules are not enforced. Notionals are in chain
+// format (the same units used in the order messages)
+type SignPolicyRules struct {
+	// AllowedMsgTypes are the type URLs of the msgs allowed (e.g. /injective.exchange.v1beta1.MsgBatchUpdateOrders).
+	// The msgs executed with authz MsgExec must be allowed too
+	AllowedMsgTypes []string `yaml:"allowed_msg_types"`
+	// AllowedMarkets are the ids of the markets the orders can be created in
+	AllowedMarkets []string `yaml:"allowed_markets"`
+	// MaxOrderNotional is the max notional of each order
+	MaxOrderNotional string `yaml:"max_order_notional"`
+	// MaxTxNotional is the max total notional of the orders of a tx
+	MaxTxNotional string           `yaml:"max_tx_notional"`
+	TimeWindows   []SignTimeWindow `yaml:"time_windows"`
+}
+
+type signTimeWindow struct {
+	weekdays map[time.Weekday]bool
+	start    time.Duration
+	end      time.Duration
+}
+
+// RuleSignPolicy is a SignPolicy enforcing SignPolicyRules
+type RuleSignPolicy struct {
+	allowedMsgTypes  map[string]bool
+	allowedMarkets   map[string]bool
+	maxOrderNotional *sdk.Dec
+	maxTxNotional    *sdk.Dec
+	timeWindows      []signTimeWindow
+}
+
+func NewRuleSignPolicy(rules SignPolicyRules) (*RuleSignPolicy, error) {
+	policy := &RuleSignPolicy{}
+	if len(rules.AllowedMsgTypes) > 0 {
+		policy.allowedMsgTypes = make(map[string]bool)
+		for _, msgType := range rules.AllowedMsgTypes {
+			policy.allowedMsgTypes[msgType] = true
+		}
+	}
+	if len(rules.AllowedMarkets) > 0 {
+		policy.allowedMarkets = make(map[string]bool)
+		for _, marketId := range rules.AllowedMarkets {
+			policy.allowedMarkets[strings.ToLower(marketId)] = true
+		}
+	}
+
+	var err error
+	if policy.maxOrderNotional, err = parseSignPolicyNotional("max_order_notional", rules.MaxOrderNotional); err != nil {
+		return nil, err
+	}
+	if policy.maxTxNotional, err = parseSignPolicyNotional("max_tx_notional", rules.MaxTxNotional); err != nil {
+		return nil, err
+	}
+
+	for i, window := range rules.TimeWindows {
+		parsed, err := parseSignTimeWindow(window)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid time window %d", i)
+		}
+		policy.timeWindows = append(policy.timeWindows, parsed)
+	}
+	return policy, nil
+}
+
+// LoadSignPolicy creates a RuleSignPolicy from the YAML encoded SignPolicyRules, e.g.
+//
+//	allowed_msg_types:
+//	  - /injective.exchange.v1beta1.MsgBatchUpdateOrders
+//	allowed_markets:
+//	  - "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+//	max_order_notional: "10000000000"
+//	time_windows:
+//	  - weekdays: [monday, tuesday, wednesday, thursday, friday]
+//	    start: "08:00"
+//	    end: "20:00"
+func LoadSignPolicy(data []byte) (*RuleSignPolicy, error) {
+	var rules SignPolicyRules
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the sign policy")
+	}
+	return NewRuleSignPolicy(rules)
+}
+
+// LoadSignPolicyFile creates a RuleSignPolicy from a YAML file (see LoadSignPolicy)
+func LoadSignPolicyFile(path string) (*RuleSignPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the sign policy %s", path)
+	}
+	return LoadSignPolicy(data)
+}
+
+// Evaluate returns a *SignPolicyError for the first rule broken by the tx
+func (p *RuleSignPolicy) Evaluate(req SignPolicyRequest) error {
+	if len(p.timeWindows) > 0 && !p.inTimeWindow(req.Time) {
+		return &SignPolicyError{Rule: SignRuleTimeWindow, Detail: fmt.Sprintf("%s is outside of the time windows", req.Time.UTC().Format(time.RFC3339))}
+	}
+	if p.allowedMsgTypes != nil {
+		if err := p.checkMsgTypes(req.Msgs); err != nil {
+			return err
+		}
+	}
+
+	txNotional := sdk.ZeroDec()
+	for _, order := range riskOrdersFromMsgs(req.Msgs) {
+		if p.allowedMarkets != nil && !p.allowedMarkets[strings.ToLower(order.marketId)] {
+			return &SignPolicyError{Rule: SignRuleMarket, Detail: fmt.Sprintf("market %s is not allowed", order.marketId)}
+		}
+		notional := order.price.Mul(order.quantity)
+		if p.maxOrderNotional != nil && notional.GT(*p.maxOrderNotional) {
+			return &SignPolicyError{Rule: SignRuleMaxOrderNotional, Detail: fmt.Sprintf("order notional %s in market %s is greater than %s", notional.String(), order.marketId, p.maxOrderNotional.String())}
+		}
+		txNotional = txNotional.Add(notional)
+	}
+	if p.maxTxNotional != nil && txNotional.GT(*p.maxTxNotional) {
+		return &SignPolicyError{Rule: SignRuleMaxTxNotional, Detail: fmt.Sprintf("tx notional %s is greater than %s", txNotional.String(), p.maxTxNotional.String())}
+	}
+	return nil
+}
+
+func (p *RuleSignPolicy) checkMsgTypes(msgs []sdk.Msg) error {
+	for _, msg := range msgs {
+		msgType := sdk.MsgTypeURL(msg)
+		if !p.allowedMsgTypes[msgType] {
+			return &SignPolicyError{Rule: SignRuleMsgType, Detail: fmt.Sprintf("msg %s is not allowed", msgType)}
+		}
+		if execMsg, ok := msg.(*authztypes.MsgExec); ok {
+			execMsgs, err := execMsg.GetMessages()
+			if err != nil {
+				return &SignPolicyError{Rule: SignRuleMsgType, Detail: "the msgs of the MsgExec can not be decoded"}
+			}
+			if err := p.checkMsgTypes(execMsgs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *RuleSignPolicy) inTimeWindow(t time.Time) bool {
+	t = t.UTC()
+	dayStart := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	timeOfDay := t.Sub(dayStart)
+	yesterday := dayStart.AddDate(0, 0, -1).Weekday()
+	for _, window := range p.timeWindows {
+		if window.start <= window.end {
+			if window.hasWeekday(t.Weekday()) && timeOfDay >= window.start && timeOfDay < window.end {
+				return true
+			}
+			continue
+		}
+		// the window ends the next day
+		if (window.hasWeekday(t.Weekday()) && timeOfDay >= window.start) || (window.hasWeekday(yesterday) && timeOfDay < window.end) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w signTimeWindow) hasWeekday(day time.Weekday) bool {
+	return w.weekdays == nil || w.weekdays[day]
+}
+
+func parseSignPolicyNotional(name string, value string) (*sdk.Dec, error) {
+	if value == "" {
+		return nil, nil
+	}
+	notional, err := sdk.NewDecFromStr(value)
+	if err != nil || !notional.IsPositive() {
+		return nil, errors.Errorf("invalid %s %q", name, value)
+	}
+	return &notional, nil
+}
+
+func parseSignTimeWindow(window SignTimeWindow) (signTimeWindow, error) {
+	parsed := signTimeWindow{}
+	var err error
+	if parsed.start, err = parseTimeOfDay(window.Start); err != nil {
+		return parsed, err
+	}
+	if parsed.end, err = parseTimeOfDay(window.End); err != nil {
+		return parsed, err
+	}
+	if parsed.start == parsed.end {
+		return parsed, errors.New("the window starts and ends at the same time")
+	}
+	if len(window.Weekdays) > 0 {
+		parsed.weekdays = make(map[time.Weekday]bool)
+		for _, name := range window.Weekdays {
+			day, found := weekdaysByName[strings.ToLower(name)]
+			if !found {
+				return parsed, errors.Errorf("invalid weekday %q", name)
+			}
+			parsed.weekdays[day] = true
+		}
+	}
+	return parsed, nil
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+var weekdaysByName = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
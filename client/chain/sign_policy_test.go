@@ -0,0 +1,86 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authztypes "github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+const testSignPolicy = `
+allowed_msg_types:
+  - /injective.exchange.v1beta1.MsgCreateDerivativeLimitOrder
+  - /injective.exchange.v1beta1.MsgCancelDerivativeOrder
+  - /cosmos.authz.v1beta1.MsgExec
+allowed_markets:
+  - "` + riskDerivativeMarketId + `"
+max_order_notional: "1000"
+max_tx_notional: "1500"
+time_windows:
+  - weekdays: [monday, tuesday, wednesday, thursday, friday]
+    start: "22:00"
+    end: "06:00"
+`
+
+func signPolicyRule(t *testing.T, err error) SignPolicyRule {
+	assert.True(t, errors.Is(err, ErrSignPolicyDenied))
+	var policyErr *SignPolicyError
+	if !assert.True(t, errors.As(err, &policyErr)) {
+		return ""
+	}
+	return policyErr.Rule
+}
+
+func TestRuleSignPolicy(t *testing.T) {
+	policy, err := LoadSignPolicy([]byte(testSignPolicy))
+	assert.NoError(t, err)
+	// a tuesday night
+	now := time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC)
+	evaluate := func(at time.Time, msgs ...sdk.Msg) error {
+		return policy.Evaluate(SignPolicyRequest{ChainId: "injective-1", Msgs: msgs, Time: at})
+	}
+	order := riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "8", "800")
+
+	assert.NoError(t, evaluate(now, order))
+	assert.NoError(t, evaluate(now, &exchangetypes.MsgCancelDerivativeOrder{MarketId: riskDerivativeMarketId}))
+
+	withdrawal := banktypes.NewMsgSend(sdk.AccAddress("from"), sdk.AccAddress("to"), sdk.NewCoins(sdk.NewInt64Coin("inj", 1)))
+	assert.Equal(t, SignRuleMsgType, signPolicyRule(t, evaluate(now, withdrawal)))
+	exec := authztypes.NewMsgExec(sdk.AccAddress("grantee"), []sdk.Msg{withdrawal})
+	assert.Equal(t, SignRuleMsgType, signPolicyRule(t, evaluate(now, &exec)))
+
+	otherMarket := riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "1", "100")
+	otherMarket.Order.MarketId = riskSpotMarketId
+	assert.Equal(t, SignRuleMarket, signPolicyRule(t, evaluate(now, otherMarket)))
+
+	assert.Equal(t, SignRuleMaxOrderNotional, signPolicyRule(t, evaluate(now, riskDerivativeOrderMsg(exchangetypes.OrderType_BUY, "100", "11", "1100"))))
+	err = evaluate(now, order, riskDerivativeOrderMsg(exchangetypes.OrderType_SELL, "100", "8", "800"))
+	assert.EqualError(t, err, "denied by the sign policy: max_tx_notional: tx notional 1600.000000000000000000 is greater than 1500.000000000000000000")
+
+	// the window of a weekday ends the next morning
+	assert.NoError(t, evaluate(time.Date(2024, 1, 6, 5, 0, 0, 0, time.UTC), order))
+	assert.Equal(t, SignRuleTimeWindow, signPolicyRule(t, evaluate(time.Date(2024, 1, 6, 23, 0, 0, 0, time.UTC), order)))
+	assert.Equal(t, SignRuleTimeWindow, signPolicyRule(t, evaluate(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), order)))
+}
+
+func TestPermissiveSignPolicy(t *testing.T) {
+	withdrawal := banktypes.NewMsgSend(sdk.AccAddress("from"), sdk.AccAddress("to"), sdk.NewCoins(sdk.NewInt64Coin("inj", 1)))
+	assert.NoError(t, PermissiveSignPolicy{}.Evaluate(SignPolicyRequest{Msgs: []sdk.Msg{withdrawal}}))
+}
+
+func TestLoadSignPolicyValidation(t *testing.T) {
+	_, err := LoadSignPolicy([]byte(`max_order_notional: "-5"`))
+	assert.EqualError(t, err, `invalid max_order_notional "-5"`)
+	_, err = LoadSignPolicy([]byte("time_windows:\n  - {start: \"9:00\", end: \"25:00\"}"))
+	assert.EqualError(t, err, `invalid time window 0: invalid time of day "25:00", expected HH:MM`)
+	_, err = LoadSignPolicy([]byte("time_windows:\n  - {weekdays: [someday], start: \"09:00\", end: \"17:00\"}"))
+	assert.EqualError(t, err, `invalid time window 0: invalid weekday "someday"`)
+	_, err = LoadSignPolicy([]byte(`allowed_msgs: []`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package chain
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/core"
+)
+
+var (
+	ErrMarketNotFound  = errors.New("market not found")
+	ErrMarketSuspended = errors.New("market is not active")
+)
+
+// SpotOrderValidator rejects dust spot orders before they are broadcast. The min notional is a human readable quote
+// token amount and can be configured per market, falling back to the default one
+type SpotOrderValidator struct {
+	marketsAssistant   MarketsAssistant
+	defaultMinNotional decimal.Decimal
+
+	mux               sync.RWMutex
+	minNotionals      map[string]decimal.Decimal
+	checkMarketStatus bool
+}
+
+func NewSpotOrderValidator(marketsAssistant MarketsAssistant, defaultMinNotional decimal.Decimal) *SpotOrderValidator {
+	return &SpotOrderValidator{
+		marketsAssistant:   marketsAssistant,
+		defaultMinNotional: defaultMinNotional,
+		minNotionals:       make(map[string]decimal.Decimal),
+	}
+}
+
+func (v *SpotOrderValidator) SetMinNotional(marketId string, minNotional decimal.Decimal) {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	v.minNotionals[marketId] = minNotional
+}
+
+func (v *SpotOrderValidator) MinNotional(marketId string) decimal.Decimal {
+	v.mux.RLock()
+	defer v.mux.RUnlock()
+
+	if minNotional, found := v.minNotionals[marketId]; found {
+		return minNotional
+	}
+	return v.defaultMinNotional
+}
+
+// SetMarketStatusCheck enables the check of the order market in the markets assistant: the market must be active, and
+// its base and quote denoms must be known. Markets loaded without status or denoms (e.g. from the ini files) are
+// rejected when the check is enabled
+func (v *SpotOrderValidator) SetMarketStatusCheck(enabled bool) {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	v.checkMarketStatus = enabled
+}
+
+// Validate checks the order data before it is converted with CreateSpotOrder
+func (v *SpotOrderValidator) Validate(d *SpotOrderData) error {
+	market, err := v.market(d.MarketId)
+	if err != nil {
+		return err
+	}
+	return market.ValidateMinNotional(d.Price, d.Quantity, v.MinNotional(d.MarketId))
+}
+
+// ValidateOrder checks an order already in chain format (e.g. the result of CreateSpotOrder)
+func (v *SpotOrderValidator) ValidateOrder(order *exchangetypes.SpotOrder) error {
+	market, err := v.market(order.MarketId)
+	if err != nil {
+		return err
+	}
+	price := market.PriceFromChainFormat(order.OrderInfo.Price)
+	quantity := market.QuantityFromChainFormat(order.OrderInfo.Quantity)
+	return market.ValidateMinNotional(price, quantity, v.MinNotional(order.MarketId))
+}
+
+func (v *SpotOrderValidator) market(marketId string) (core.SpotMarket, error) {
+	market, found := v.marketsAssistant.AllSpotMarkets()[marketId]
+	if !found {
+		return core.SpotMarket{}, errors.Wrapf(ErrMarketNotFound, "spot market %s", marketId)
+	}
+
+	v.mux.RLock()
+	checkMarketStatus := v.checkMarketStatus
+	v.mux.RUnlock()
+	if !checkMarketStatus {
+		return market, nil
+	}
+
+	if market.BaseToken.Denom == "" || market.QuoteToken.Denom == "" || market.BaseToken.Denom == market.QuoteToken.Denom {
+		return core.SpotMarket{}, errors.Wrapf(ErrMarketNotFound, "spot market %s has no valid base and quote denoms (%q and %q)", marketId, market.BaseToken.Denom, market.QuoteToken.Denom)
+	}
+	if !strings.EqualFold(market.Status, "active") {
+		return core.SpotMarket{}, errors.Wrapf(ErrMarketSuspended, "spot market %s status is %q", marketId, market.Status)
+	}
+	return market, nil
+}
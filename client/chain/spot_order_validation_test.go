@@ -0,0 +1,99 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/core"
+)
+
+func spotOrderValidationTestAssistant() MarketsAssistant {
+	assistant := arbitrageTestAssistant()
+	market := assistant.spotMarkets[arbitrageSpotMarketId]
+	market.MinPriceTickSize = decimal.RequireFromString("0.000000000000001")
+	market.MinQuantityTickSize = decimal.RequireFromString("1000000000000000")
+	assistant.spotMarkets[arbitrageSpotMarketId] = market
+	return assistant
+}
+
+func TestSpotOrderValidatorMinNotional(t *testing.T) {
+	validator := NewSpotOrderValidator(spotOrderValidationTestAssistant(), decimal.RequireFromString("1"))
+	orderData := &SpotOrderData{
+		OrderType: exchangetypes.OrderType_BUY,
+		Price:     decimal.RequireFromString("10"),
+		Quantity:  decimal.RequireFromString("0.5"),
+		MarketId:  arbitrageSpotMarketId,
+	}
+
+	assert.NoError(t, validator.Validate(orderData))
+
+	validator.SetMinNotional(arbitrageSpotMarketId, decimal.RequireFromString("10"))
+	assert.Equal(t, "10", validator.MinNotional(arbitrageSpotMarketId).String())
+	assert.True(t, errors.Is(validator.Validate(orderData), core.ErrOrderBelowMinNotional))
+
+	dustOrderData := *orderData
+	dustOrderData.Quantity = decimal.RequireFromString("0.0001")
+	assert.True(t, errors.Is(validator.Validate(&dustOrderData), core.ErrDustOrder))
+
+	dustOrderData.MarketId = "0x1"
+	assert.True(t, errors.Is(validator.Validate(&dustOrderData), ErrMarketNotFound))
+}
+
+func TestSpotOrderValidatorMarketStatusCheck(t *testing.T) {
+	assistant := spotOrderValidationTestAssistant()
+	validator := NewSpotOrderValidator(assistant, decimal.Zero)
+	orderData := &SpotOrderData{
+		OrderType: exchangetypes.OrderType_BUY,
+		Price:     decimal.RequireFromString("10"),
+		Quantity:  decimal.RequireFromString("0.5"),
+		MarketId:  arbitrageSpotMarketId,
+	}
+
+	// the status is only checked when enabled
+	assert.NoError(t, validator.Validate(orderData))
+	validator.SetMarketStatusCheck(true)
+	err := validator.Validate(orderData)
+	assert.True(t, errors.Is(err, ErrMarketSuspended))
+	assert.EqualError(t, err, `spot market 0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe status is "": market is not active`)
+
+	market := assistant.spotMarkets[arbitrageSpotMarketId]
+	market.Status = "paused"
+	assistant.spotMarkets[arbitrageSpotMarketId] = market
+	assert.True(t, errors.Is(validator.Validate(orderData), ErrMarketSuspended))
+
+	market.Status = "active"
+	assistant.spotMarkets[arbitrageSpotMarketId] = market
+	assert.NoError(t, validator.Validate(orderData))
+	order := (&chainClient{}).CreateSpotOrder(eth.Hash{}, orderData, assistant)
+	assert.NoError(t, validator.ValidateOrder(order))
+
+	market.BaseToken.Denom = ""
+	assistant.spotMarkets[arbitrageSpotMarketId] = market
+	assert.True(t, errors.Is(validator.Validate(orderData), ErrMarketNotFound))
+	assert.True(t, errors.Is(validator.ValidateOrder(order), ErrMarketNotFound))
+
+	validator.SetMarketStatusCheck(false)
+	assert.NoError(t, validator.Validate(orderData))
+}
+
+func TestSpotOrderValidatorValidateOrder(t *testing.T) {
+	assistant := spotOrderValidationTestAssistant()
+	validator := NewSpotOrderValidator(assistant, decimal.RequireFromString("5"))
+	client := &chainClient{}
+
+	order := client.CreateSpotOrder(eth.Hash{}, &SpotOrderData{
+		OrderType: exchangetypes.OrderType_SELL,
+		Price:     decimal.RequireFromString("10"),
+		Quantity:  decimal.RequireFromString("0.5"),
+		MarketId:  arbitrageSpotMarketId,
+	}, assistant)
+	assert.NoError(t, validator.ValidateOrder(order))
+
+	order.OrderInfo.Quantity = order.OrderInfo.Quantity.QuoInt64(2)
+	assert.True(t, errors.Is(validator.ValidateOrder(order), core.ErrOrderBelowMinNotional))
+}
@@ -0,0 +1,107 @@
+package chain
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/pkg/errors"
+)
+
+func parseDelegatorAndValidator(delegatorAddress string, validatorAddress string) (sdk.AccAddress, sdk.ValAddress, error) {
+	delegator, err := sdk.AccAddressFromBech32(delegatorAddress)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid delegator address %s", delegatorAddress)
+	}
+	validator, err := sdk.ValAddressFromBech32(validatorAddress)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid validator address %s", validatorAddress)
+	}
+
+	return delegator, validator, nil
+}
+
+// NewMsgDelegate builds a staking MsgDelegate, validating the addresses and the amount
+func NewMsgDelegate(delegatorAddress string, validatorAddress string, amount sdk.Coin) (*stakingtypes.MsgDelegate, error) {
+	delegator, validator, err := parseDelegatorAndValidator(delegatorAddress, validatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := stakingtypes.NewMsgDelegate(delegator, validator, amount)
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// NewMsgUndelegate builds a staking MsgUndelegate. The tokens are available after the unbonding period
+func NewMsgUndelegate(delegatorAddress string, validatorAddress string, amount sdk.Coin) (*stakingtypes.MsgUndelegate, error) {
+	delegator, validator, err := parseDelegatorAndValidator(delegatorAddress, validatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := stakingtypes.NewMsgUndelegate(delegator, validator, amount)
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// NewMsgBeginRedelegate builds a staking MsgBeginRedelegate to move the delegation between validators
+func NewMsgBeginRedelegate(delegatorAddress string, srcValidatorAddress string, dstValidatorAddress string, amount sdk.Coin) (*stakingtypes.MsgBeginRedelegate, error) {
+	delegator, srcValidator, err := parseDelegatorAndValidator(delegatorAddress, srcValidatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	dstValidator, err := sdk.ValAddressFromBech32(dstValidatorAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid validator address %s", dstValidatorAddress)
+	}
+	if srcValidator.Equals(dstValidator) {
+		return nil, errors.New("source and destination validators can not be the same")
+	}
+
+	msg := stakingtypes.NewMsgBeginRedelegate(delegator, srcValidator, dstValidator, amount)
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// NewMsgWithdrawDelegatorReward builds a distribution MsgWithdrawDelegatorReward for a single validator
+func NewMsgWithdrawDelegatorReward(delegatorAddress string, validatorAddress string) (*distributiontypes.MsgWithdrawDelegatorReward, error) {
+	delegator, validator, err := parseDelegatorAndValidator(delegatorAddress, validatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := distributiontypes.NewMsgWithdrawDelegatorReward(delegator, validator)
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// NewMsgsWithdrawAllDelegatorRewards builds one MsgWithdrawDelegatorReward for each validator with pending rewards
+// in the FetchDelegationTotalRewards response, so all of them can be broadcasted in a single transaction
+func NewMsgsWithdrawAllDelegatorRewards(delegatorAddress string, totalRewards *distributiontypes.QueryDelegationTotalRewardsResponse) ([]sdk.Msg, error) {
+	msgs := make([]sdk.Msg, 0, len(totalRewards.Rewards))
+	for _, reward := range totalRewards.Rewards {
+		if reward.Reward.IsZero() {
+			continue
+		}
+
+		msg, err := NewMsgWithdrawDelegatorReward(delegatorAddress, reward.ValidatorAddress)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,61 @@
+package chain
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testDelegatorAddress  = "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	testValidatorAddress  = "injvaloper14gy4acwjm96wd20awm9ar6j54lev5p7espy9ug"
+	testValidatorAddress2 = "injvaloper1jue5dpr9lerjn6wlwtrywxrsenrf28ru89z99z"
+)
+
+func TestNewMsgDelegateValidatesInput(t *testing.T) {
+	amount := sdk.NewInt64Coin("inj", 1000000000000000000)
+
+	msg, err := NewMsgDelegate(testDelegatorAddress, testValidatorAddress, amount)
+	assert.NoError(t, err)
+	assert.Equal(t, testValidatorAddress, msg.ValidatorAddress)
+	assert.Equal(t, amount, msg.Amount)
+
+	_, err = NewMsgDelegate(testDelegatorAddress, testDelegatorAddress, amount)
+	assert.Error(t, err)
+
+	_, err = NewMsgUndelegate(testDelegatorAddress, testValidatorAddress, sdk.NewInt64Coin("inj", 0))
+	assert.Error(t, err)
+}
+
+func TestNewMsgBeginRedelegateRejectsSameValidator(t *testing.T) {
+	amount := sdk.NewInt64Coin("inj", 1000)
+
+	_, err := NewMsgBeginRedelegate(testDelegatorAddress, testValidatorAddress, testValidatorAddress, amount)
+	assert.Error(t, err)
+
+	msg, err := NewMsgBeginRedelegate(testDelegatorAddress, testValidatorAddress, testValidatorAddress2, amount)
+	assert.NoError(t, err)
+	assert.Equal(t, testValidatorAddress2, msg.ValidatorDstAddress)
+}
+
+func TestNewMsgsWithdrawAllDelegatorRewardsSkipsEmptyRewards(t *testing.T) {
+	totalRewards := &distributiontypes.QueryDelegationTotalRewardsResponse{
+		Rewards: []distributiontypes.DelegationDelegatorReward{
+			{
+				ValidatorAddress: testValidatorAddress,
+				Reward:           sdk.NewDecCoins(sdk.NewInt64DecCoin("inj", 10)),
+			},
+			{
+				ValidatorAddress: testValidatorAddress2,
+				Reward:           sdk.DecCoins{},
+			},
+		},
+	}
+
+	msgs, err := NewMsgsWithdrawAllDelegatorRewards(testDelegatorAddress, totalRewards)
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, testValidatorAddress, msgs[0].(*distributiontypes.MsgWithdrawDelegatorReward).ValidatorAddress)
+}
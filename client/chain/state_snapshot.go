@@ -0,0 +1,179 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// StateSnapshotVersion is the version of the state snapshot schema written by StateSnapshot.Write. Snapshots with
+// another version are rejected by ReadStateSnapshot
+const StateSnapshotVersion = 1
+
+// StateSnapshot is the tracking state of a bot: the open orders of the OrderTracker with their pending cancels and
+// replacement links, and the QuoteManager targets. It is saved on shutdown (or periodically) and restored on startup
+// with RestoreState
+type StateSnapshot struct {
+	Version        int               `json:"version"`
+	TakenAt        time.Time         `json:"taken_at"`
+	Orders         []TrackedOrder    `json:"orders"`
+	PendingCancels []string          `json:"pending_cancels"`
+	ReplacedBy     map[string]string `json:"replaced_by"`
+	QuoteTargets   []QuoteTarget     `json:"quote_targets"`
+}
+
+// RestoreReport lists what RestoreState did with the orders of the snapshot
+type RestoreReport struct {
+	// Restored are the orders still resting on chain, now tracked again
+	Restored []string
+	// Closed are the orders filled or cancelled since the snapshot, not tracked anymore
+	Closed []string
+	// SkippedTargets are the quote targets of markets the markets assistant does not know
+	SkippedTargets []QuoteTarget
+}
+
+// TakeStateSnapshot copies the state of the tracker and the quote manager. The quote manager is optional
+func TakeStateSnapshot(tracker *OrderTracker, quoteManager *QuoteManager) *StateSnapshot {
+	snapshot := &StateSnapshot{
+		Version:        StateSnapshotVersion,
+		TakenAt:        tracker.clock.Now().UTC(),
+		Orders:         make([]TrackedOrder, 0),
+		PendingCancels: tracker.PendingCancels(),
+		ReplacedBy:     make(map[string]string),
+		QuoteTargets:   make([]QuoteTarget, 0),
+	}
+
+	tracker.mux.RLock()
+	for _, order := range tracker.orders {
+		snapshot.Orders = append(snapshot.Orders, order)
+	}
+	for oldOrderHash, newOrderHash := range tracker.replacedBy {
+		snapshot.ReplacedBy[oldOrderHash] = newOrderHash
+	}
+	tracker.mux.RUnlock()
+	sort.Slice(snapshot.Orders, func(i, j int) bool {
+		return snapshot.Orders[i].OrderHash < snapshot.Orders[j].OrderHash
+	})
+
+	if quoteManager != nil {
+		snapshot.QuoteTargets = quoteManager.Targets()
+	}
+	return snapshot
+}
+
+// Write encodes the snapshot as JSON
+func (s *StateSnapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(s); err != nil {
+		return errors.Wrap(err, "failed to write the state snapshot")
+	}
+	return nil
+}
+
+// ReadStateSnapshot decodes a snapshot written by Write, and checks its schema version
+func ReadStateSnapshot(r io.Reader) (*StateSnapshot, error) {
+	var snapshot StateSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, errors.Wrap(err, "failed to read the state snapshot")
+	}
+	if snapshot.Version != StateSnapshotVersion {
+		return nil, errors.Errorf("unsupported state snapshot version %d, expected %d", snapshot.Version, StateSnapshotVersion)
+	}
+	for _, order := range snapshot.Orders {
+		if order.OrderHash == "" || order.MarketId == "" || order.SubaccountId == "" {
+			return nil, errors.Errorf("the state snapshot has an order without order hash, market or subaccount: %+v", order)
+		}
+	}
+	return &snapshot, nil
+}
+
+// RestoreState loads the snapshot into the tracker and the quote manager (optional), checking the orders against the
+// chain: only the orders still resting in their market are tracked again, with their pending cancels. The replacement
+// links are all restored. Nothing is restored if an open orders query fails
+func RestoreState(ctx context.Context, chainClient ChainClient, snapshot *StateSnapshot, tracker *OrderTracker, quoteManager *QuoteManager) (*RestoreReport, error) {
+	openOrders, err := snapshotOrdersOnChain(ctx, chainClient, snapshot.Orders)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{Restored: make([]string, 0), Closed: make([]string, 0)}
+	pendingCancels := make(map[string]bool, len(snapshot.PendingCancels))
+	for _, orderHash := range snapshot.PendingCancels {
+		pendingCancels[orderHash] = true
+	}
+
+	tracker.mux.Lock()
+	for _, order := range snapshot.Orders {
+		if !openOrders[order.OrderHash] {
+			report.Closed = append(report.Closed, order.OrderHash)
+			continue
+		}
+		report.Restored = append(report.Restored, order.OrderHash)
+		tracker.orders[order.OrderHash] = order
+		if pendingCancels[order.OrderHash] {
+			tracker.pendingCancels[order.OrderHash] = true
+		}
+	}
+	for oldOrderHash, newOrderHash := range snapshot.ReplacedBy {
+		tracker.replacedBy[oldOrderHash] = newOrderHash
+	}
+	tracker.mux.Unlock()
+
+	if quoteManager != nil {
+		spotMarkets := quoteManager.marketsAssistant.AllSpotMarkets()
+		derivativeMarkets := quoteManager.marketsAssistant.AllDerivativeMarkets()
+		for _, target := range snapshot.QuoteTargets {
+			_, isSpot := spotMarkets[target.Config.MarketId]
+			_, isDerivative := derivativeMarkets[target.Config.MarketId]
+			if !isSpot && !isDerivative {
+				report.SkippedTargets = append(report.SkippedTargets, target)
+				continue
+			}
+			quoteManager.setTarget(target)
+		}
+	}
+
+	return report, nil
+}
+
+// snapshotOrdersOnChain returns the order hashes of the orders still resting on chain, with a query per market and
+// subaccount
+func snapshotOrdersOnChain(ctx context.Context, chainClient ChainClient, orders []TrackedOrder) (map[string]bool, error) {
+	type orderBook struct {
+		marketId     string
+		subaccountId string
+		isDerivative bool
+	}
+	books := make(map[orderBook]bool)
+	for _, order := range orders {
+		books[orderBook{marketId: order.MarketId, subaccountId: order.SubaccountId, isDerivative: order.IsDerivative}] = true
+	}
+
+	openOrders := make(map[string]bool)
+	for book := range books {
+		if book.isDerivative {
+			res, err := chainClient.FetchChainTraderDerivativeOrders(ctx, book.marketId, book.subaccountId)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to fetch the derivative orders in market %s", book.marketId)
+			}
+			for _, order := range res.Orders {
+				openOrders[order.OrderHash] = true
+			}
+			continue
+		}
+
+		res, err := chainClient.FetchChainTraderSpotOrders(ctx, book.marketId, book.subaccountId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the spot orders in market %s", book.marketId)
+		}
+		for _, order := range res.Orders {
+			openOrders[order.OrderHash] = true
+		}
+	}
+	return openOrders, nil
+}
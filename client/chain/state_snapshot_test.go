@@ -0,0 +1,118 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+type stateSnapshotTestChainClient struct {
+	quoteTestChainClient
+	restingOrderHashes []string
+	err                error
+}
+
+func (c *stateSnapshotTestChainClient) FetchChainTraderSpotOrders(ctx context.Context, marketId string, subaccountId string) (*exchangetypes.QueryTraderSpotOrdersResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	res := &exchangetypes.QueryTraderSpotOrdersResponse{}
+	for _, orderHash := range c.restingOrderHashes {
+		res.Orders = append(res.Orders, &exchangetypes.TrimmedSpotLimitOrder{OrderHash: orderHash})
+	}
+	return res, nil
+}
+
+func TestStateSnapshotRestoresTheOrdersStillOnChain(t *testing.T) {
+	chainClient := &stateSnapshotTestChainClient{}
+	tracker := NewOrderTracker()
+	manager := NewQuoteManager(chainClient, spotOrderValidationTestAssistant(), tracker)
+	config := quoteTestConfig()
+	_, err := manager.Refresh(context.Background(), config, decimal.RequireFromString("10"))
+	assert.NoError(t, err)
+	tracker.MarkCancelPending("0x02")
+	tracker.MarkCancelPending("0x04")
+	tracker.LinkReplacement("0x00", "0x01")
+
+	var saved bytes.Buffer
+	assert.NoError(t, TakeStateSnapshot(tracker, manager).Write(&saved))
+	snapshot, err := ReadStateSnapshot(&saved)
+	assert.NoError(t, err)
+	assert.Equal(t, StateSnapshotVersion, snapshot.Version)
+	assert.Len(t, snapshot.Orders, 4)
+	assert.Equal(t, []string{"0x02", "0x04"}, snapshot.PendingCancels)
+
+	// 0x04 was filled while the bot was stopped
+	chainClient.restingOrderHashes = []string{"0x01", "0x02", "0x03"}
+	restoredTracker := NewOrderTracker()
+	restoredManager := NewQuoteManager(chainClient, spotOrderValidationTestAssistant(), restoredTracker)
+	report, err := RestoreState(context.Background(), chainClient, snapshot, restoredTracker, restoredManager)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x01", "0x02", "0x03"}, report.Restored)
+	assert.Equal(t, []string{"0x04"}, report.Closed)
+	assert.Empty(t, report.SkippedTargets)
+
+	assert.Len(t, restoredTracker.OpenOrders(config.MarketId, config.SubaccountId), 3)
+	original, _ := tracker.Order("0x01")
+	restored, found := restoredTracker.Order("0x01")
+	assert.True(t, found)
+	assert.True(t, original.Price.Equal(restored.Price))
+	assert.True(t, original.Quantity.Equal(restored.Quantity))
+	assert.Equal(t, original.OrderType, restored.OrderType)
+	assert.True(t, original.CreatedAt.Equal(restored.CreatedAt))
+	assert.Equal(t, []string{"0x02"}, restoredTracker.PendingCancels())
+	assert.Equal(t, "0x01", restoredTracker.LatestReplacement("0x00"))
+
+	targets := restoredManager.Targets()
+	assert.Len(t, targets, 1)
+	assert.Equal(t, config.MarketId, targets[0].Config.MarketId)
+	assert.True(t, config.Spread.Equal(targets[0].Config.Spread))
+	assert.True(t, decimal.RequireFromString("10").Equal(targets[0].MidPrice))
+}
+
+func TestRestoreStateRestoresNothingIfTheChainQueryFails(t *testing.T) {
+	chainClient := &stateSnapshotTestChainClient{err: errors.New("connection refused")}
+	snapshot := &StateSnapshot{
+		Version: StateSnapshotVersion,
+		Orders:  []TrackedOrder{{OrderHash: "0x01", MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId}},
+	}
+	tracker := NewOrderTracker()
+
+	_, err := RestoreState(context.Background(), chainClient, snapshot, tracker, nil)
+
+	assert.Error(t, err)
+	_, found := tracker.Order("0x01")
+	assert.False(t, found)
+}
+
+func TestReadStateSnapshotRejectsOtherVersions(t *testing.T) {
+	_, err := ReadStateSnapshot(strings.NewReader(`{"version": 2, "orders": []}`))
+	assert.Error(t, err)
+
+	_, err = ReadStateSnapshot(strings.NewReader(`{"version": 1, "orders": [{"order_hash": "0x01"}]}`))
+	assert.Error(t, err)
+}
+
+func TestOrderTrackerPendingCancels(t *testing.T) {
+	tracker := NewOrderTracker()
+	tracker.Track(TrackedOrder{OrderHash: "0x01", MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId})
+
+	tracker.MarkCancelPending("0x01")
+	tracker.MarkCancelPending("0x02")
+	assert.True(t, tracker.IsCancelPending("0x01"))
+	assert.False(t, tracker.IsCancelPending("0x02"))
+
+	tracker.ClearCancelPending("0x01")
+	assert.Empty(t, tracker.PendingCancels())
+
+	tracker.MarkCancelPending("0x01")
+	tracker.Remove("0x01")
+	assert.Empty(t, tracker.PendingCancels())
+}
@@ -0,0 +1,76 @@
+package chain
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// TradingRewardsPoolEstimate is the share of a campaign reward pool corresponding to the account points
+type TradingRewardsPoolEstimate struct {
+	StartTimestamp   int64
+	AccountPoints    sdk.Dec
+	TotalPoints      sdk.Dec
+	EstimatedRewards sdk.Coins
+}
+
+// TradingRewardsEstimate includes the estimation for the current campaign and for the finished campaigns
+// pending to be paid out
+type TradingRewardsEstimate struct {
+	Current *TradingRewardsPoolEstimate
+	Pending []TradingRewardsPoolEstimate
+}
+
+// EstimateTradingRewards returns the rewards corresponding to the account points if the campaign ended now,
+// as the pro rata share of the campaign max rewards (amounts are truncated)
+func EstimateTradingRewards(accountPoints sdk.Dec, totalPoints sdk.Dec, maxCampaignRewards sdk.Coins) sdk.Coins {
+	rewards := sdk.NewCoins()
+	if accountPoints.IsNil() || totalPoints.IsNil() || !accountPoints.IsPositive() || !totalPoints.IsPositive() {
+		return rewards
+	}
+
+	share := sdk.MinDec(accountPoints.Quo(totalPoints), sdk.OneDec())
+	for _, reward := range maxCampaignRewards {
+		amount := share.MulInt(reward.Amount).TruncateInt()
+		if amount.IsPositive() {
+			rewards = rewards.Add(sdk.NewCoin(reward.Denom, amount))
+		}
+	}
+
+	return rewards
+}
+
+// MarketPointsMultiplier returns the points multiplier the campaign applies to the trades in the market.
+// Markets without a boost have a multiplier of 1, and disqualified markets a multiplier of 0
+func MarketPointsMultiplier(campaign *exchangetypes.TradingRewardCampaignInfo, marketId string, isDerivative bool) exchangetypes.PointsMultiplier {
+	if campaign == nil {
+		return exchangetypes.PointsMultiplier{MakerPointsMultiplier: sdk.ZeroDec(), TakerPointsMultiplier: sdk.ZeroDec()}
+	}
+
+	for _, disqualifiedMarketId := range campaign.DisqualifiedMarketIds {
+		if disqualifiedMarketId == marketId {
+			return exchangetypes.PointsMultiplier{MakerPointsMultiplier: sdk.ZeroDec(), TakerPointsMultiplier: sdk.ZeroDec()}
+		}
+	}
+
+	if boostInfo := campaign.TradingRewardBoostInfo; boostInfo != nil {
+		marketIds, multipliers := boostInfo.BoostedSpotMarketIds, boostInfo.SpotMarketMultipliers
+		if isDerivative {
+			marketIds, multipliers = boostInfo.BoostedDerivativeMarketIds, boostInfo.DerivativeMarketMultipliers
+		}
+		for i, boostedMarketId := range marketIds {
+			if boostedMarketId == marketId && i < len(multipliers) {
+				return multipliers[i]
+			}
+		}
+	}
+
+	return exchangetypes.PointsMultiplier{MakerPointsMultiplier: sdk.OneDec(), TakerPointsMultiplier: sdk.OneDec()}
+}
+
+func pointsForFirstAccount(res *exchangetypes.QueryTradeRewardPointsResponse) sdk.Dec {
+	if res == nil || len(res.AccountTradeRewardPoints) == 0 {
+		return sdk.ZeroDec()
+	}
+	return res.AccountTradeRewardPoints[0]
+}
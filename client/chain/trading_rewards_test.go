@@ -0,0 +1,47 @@
+package chain
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func TestEstimateTradingRewards(t *testing.T) {
+	maxRewards := sdk.NewCoins(sdk.NewCoin("inj", sdk.NewInt(1000)), sdk.NewCoin("peggy0x87aB3B4C8661e07D6372361211B96ed4Dc36B1B5", sdk.NewInt(10)))
+
+	rewards := EstimateTradingRewards(sdk.NewDec(25), sdk.NewDec(100), maxRewards)
+	assert.Equal(t, sdk.NewInt(250), rewards.AmountOf("inj"))
+	assert.Equal(t, sdk.NewInt(2), rewards.AmountOf("peggy0x87aB3B4C8661e07D6372361211B96ed4Dc36B1B5"))
+
+	assert.True(t, EstimateTradingRewards(sdk.ZeroDec(), sdk.NewDec(100), maxRewards).IsZero())
+	assert.True(t, EstimateTradingRewards(sdk.NewDec(25), sdk.ZeroDec(), maxRewards).IsZero())
+	assert.True(t, EstimateTradingRewards(sdk.Dec{}, sdk.NewDec(100), maxRewards).IsZero())
+}
+
+func TestMarketPointsMultiplier(t *testing.T) {
+	boostedMarketId := "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+	disqualifiedMarketId := "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	campaign := &exchangetypes.TradingRewardCampaignInfo{
+		DisqualifiedMarketIds: []string{disqualifiedMarketId},
+		TradingRewardBoostInfo: &exchangetypes.TradingRewardCampaignBoostInfo{
+			BoostedDerivativeMarketIds: []string{boostedMarketId},
+			DerivativeMarketMultipliers: []exchangetypes.PointsMultiplier{{
+				MakerPointsMultiplier: sdk.NewDec(2),
+				TakerPointsMultiplier: sdk.NewDec(3),
+			}},
+		},
+	}
+
+	multiplier := MarketPointsMultiplier(campaign, boostedMarketId, true)
+	assert.Equal(t, sdk.NewDec(2), multiplier.MakerPointsMultiplier)
+	assert.Equal(t, sdk.NewDec(3), multiplier.TakerPointsMultiplier)
+
+	multiplier = MarketPointsMultiplier(campaign, boostedMarketId, false)
+	assert.Equal(t, sdk.OneDec(), multiplier.TakerPointsMultiplier)
+
+	multiplier = MarketPointsMultiplier(campaign, disqualifiedMarketId, false)
+	assert.True(t, multiplier.MakerPointsMultiplier.IsZero())
+}
@@ -0,0 +1,225 @@
+package chain
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/pkg/errors"
+)
+
+var failedMsgIndexRegexp = regexp.MustCompile(`message index: (\d+)`)
+
+// ComposedMsgResult is the result of one of the msgs of a composed tx
+type ComposedMsgResult struct {
+	Index int
+	Msg   sdk.Msg
+	// Response is the decoded msg response (e.g. *exchangetypes.MsgCreateSpotLimitOrderResponse). It is nil if the tx
+	// failed or the response type is not registered, in which case RawResponse can still be inspected
+	Response    proto.Message
+	RawResponse *codectypes.Any
+	// Err is set on the msg that made the tx fail. The rest of the msgs were not applied either
+	Err error
+}
+
+// ComposedTxResult is the result of a tx broadcast by TxComposer
+type ComposedTxResult struct {
+	TxHash     string
+	Height     int64
+	TxResponse *sdk.TxResponse
+	Results    []ComposedMsgResult
+	// FailedMsgIndex is the index of the msg that made the tx fail, or -1 if the tx succeeded or the failing msg is
+	// not known
+	FailedMsgIndex int
+	Err            error
+}
+
+// TxComposer collects msgs of any type (e.g. cancels, creates and deposits) and broadcasts them in a single tx, so
+// they are all applied atomically in the same block or not applied at all
+type TxComposer struct {
+	chainClient ChainClient
+	memo        string
+
+	mux  sync.Mutex
+	msgs []sdk.Msg
+}
+
+func NewTxComposer(chainClient ChainClient) *TxComposer {
+	return &TxComposer{chainClient: chainClient}
+}
+
+// WithMemo sets the memo of the composed tx. The client default memo is used if it is empty
+func (c *TxComposer) WithMemo(memo string) *TxComposer {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.memo = memo
+	return c
+}
+
+// Add appends the msgs to the tx and returns the index of the first one, used to find its result
+func (c *TxComposer) Add(msgs ...sdk.Msg) int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	index := len(c.msgs)
+	c.msgs = append(c.msgs, msgs...)
+	return index
+}
+
+func (c *TxComposer) Len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return len(c.msgs)
+}
+
+func (c *TxComposer) Msgs() []sdk.Msg {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return append([]sdk.Msg{}, c.msgs...)
+}
+
+func (c *TxComposer) Reset() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.msgs = nil
+}
+
+// Broadcast sends all the msgs in one tx and waits until it is included in a block. The returned error is only set
+// when the tx could not be broadcast; if the chain rejected it, the error is in the result Err. The msgs are taken
+// from the composer when the broadcast starts, so msgs added meanwhile go in the next tx. If the tx was certainly not
+// sent (it failed to be simulated or signed, or CheckTx rejected it), the taken msgs are put back before them. If the
+// tx may have been sent (e.g. ErrTimedOut), they are not put back, so they are not executed twice, and the result has
+// the tx hash when known, to watch for the tx
+func (c *TxComposer) Broadcast() (*ComposedTxResult, error) {
+	msgs, memo := c.take()
+	if len(msgs) == 0 {
+		return nil, errors.New("there are no msgs to broadcast")
+	}
+
+	broadcast := c.chainClient.SyncBroadcastMsg
+	if memo != "" {
+		broadcast = func(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+			return c.chainClient.SyncBroadcastMsgWithMemo(memo, msgs...)
+		}
+	}
+	res, err := broadcast(msgs...)
+	var notSentErr *TxNotSentError
+	if errors.As(err, &notSentErr) {
+		c.restore(msgs)
+		return nil, errors.Wrap(err, "failed to broadcast the composed tx")
+	} else if err != nil {
+		result := &ComposedTxResult{FailedMsgIndex: -1}
+		if res != nil && res.TxResponse != nil {
+			result.TxHash = res.TxResponse.TxHash
+		}
+		return result, errors.Wrap(err, "failed to broadcast the composed tx")
+	}
+
+	if res == nil || res.TxResponse == nil {
+		return nil, errors.New("the composed tx broadcast returned no tx response")
+	}
+	if res.TxResponse.Code != 0 && res.TxResponse.Height == 0 {
+		// rejected by CheckTx, the tx will not be included in a block
+		c.restore(msgs)
+	}
+	return NewComposedTxResult(msgs, res.TxResponse)
+}
+
+// take removes the msgs from the composer, returning them with the memo
+func (c *TxComposer) take() ([]sdk.Msg, string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	msgs := c.msgs
+	c.msgs = nil
+	return msgs, c.memo
+}
+
+// restore puts back msgs taken by a broadcast of a tx that was not sent, before the msgs added since they were taken
+func (c *TxComposer) restore(msgs []sdk.Msg) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.msgs = append(msgs, c.msgs...)
+}
+
+// NewComposedTxResult maps every msg of the tx to its response, or to the tx error for the msg that failed
+func NewComposedTxResult(msgs []sdk.Msg, txResponse *sdk.TxResponse) (*ComposedTxResult, error) {
+	result := &ComposedTxResult{
+		TxHash:         txResponse.TxHash,
+		Height:         txResponse.Height,
+		TxResponse:     txResponse,
+		Results:        make([]ComposedMsgResult, len(msgs)),
+		FailedMsgIndex: -1,
+	}
+	for i, msg := range msgs {
+		result.Results[i] = ComposedMsgResult{Index: i, Msg: msg}
+	}
+
+	if txErr := NewTxError(txResponse); txErr != nil {
+		result.Err = txErr
+		if index, found := failedMsgIndex(txResponse.RawLog); found && index < len(msgs) {
+			result.FailedMsgIndex = index
+			result.Results[index].Err = txErr
+		}
+		return result, nil
+	}
+
+	msgData, err := decodeTxMsgData(txResponse.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the msg responses of tx %s", txResponse.TxHash)
+	}
+	for i, msgResponse := range msgData.MsgResponses {
+		if i >= len(msgs) {
+			break
+		}
+		result.Results[i].RawResponse = msgResponse
+		response, err := decodeMsgResponse(msgResponse)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the response of msg %d in tx %s", i, txResponse.TxHash)
+		}
+		result.Results[i].Response = response
+	}
+
+	return result, nil
+}
+
+// decodeMsgResponse decodes the response using the gogoproto registered types, returning nil for unknown types
+func decodeMsgResponse(msgResponse *codectypes.Any) (proto.Message, error) {
+	responseType := proto.MessageType(strings.TrimPrefix(msgResponse.TypeUrl, "/"))
+	if responseType == nil {
+		return nil, nil
+	}
+
+	response, ok := reflect.New(responseType.Elem()).Interface().(proto.Message)
+	if !ok {
+		return nil, nil
+	}
+	if err := proto.Unmarshal(msgResponse.Value, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// failedMsgIndex reads the index of the failed msg from the tx raw log (e.g. "failed to execute message; message index: 1: ...")
+func failedMsgIndex(rawLog string) (int, bool) {
+	match := failedMsgIndexRegexp.FindStringSubmatch(rawLog)
+	if match == nil {
+		return 0, false
+	}
+	index, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
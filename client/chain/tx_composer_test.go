@@ -0,0 +1,169 @@
+package chain
+
+import (
+	"encoding/hex"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+type composerTestChainClient struct {
+	MockChainClient
+	txResponse      *sdk.TxResponse
+	broadcastedMsgs []sdk.Msg
+	memo            string
+	broadcastErr    error
+	// errResponse is returned with broadcastErr (e.g. with the tx hash of a tx that timed out)
+	errResponse *txtypes.BroadcastTxResponse
+	onBroadcast func()
+}
+
+func (c *composerTestChainClient) SyncBroadcastMsg(msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	if c.onBroadcast != nil {
+		c.onBroadcast()
+	}
+	if c.broadcastErr != nil {
+		return c.errResponse, c.broadcastErr
+	}
+	c.broadcastedMsgs = append(c.broadcastedMsgs, msgs...)
+	return &txtypes.BroadcastTxResponse{TxResponse: c.txResponse}, nil
+}
+
+func (c *composerTestChainClient) SyncBroadcastMsgWithMemo(memo string, msgs ...sdk.Msg) (*txtypes.BroadcastTxResponse, error) {
+	c.memo = memo
+	return c.SyncBroadcastMsg(msgs...)
+}
+
+func composerTestMsgs() []sdk.Msg {
+	return []sdk.Msg{
+		&exchangetypes.MsgCancelSpotOrder{Sender: "inj1", MarketId: riskSpotMarketId, SubaccountId: riskSubaccountId, OrderHash: "0x01"},
+		&exchangetypes.MsgCreateSpotLimitOrder{Sender: "inj1"},
+		&exchangetypes.MsgDeposit{Sender: "inj1", SubaccountId: riskSubaccountId, Amount: sdk.NewInt64Coin("inj", 1)},
+	}
+}
+
+func TestTxComposerMapsMsgResponses(t *testing.T) {
+	var msgResponses []*codectypes.Any
+	for _, msgResponse := range []proto.Message{
+		&exchangetypes.MsgCancelSpotOrderResponse{},
+		&exchangetypes.MsgCreateSpotLimitOrderResponse{OrderHash: "0x02"},
+		&exchangetypes.MsgDepositResponse{},
+	} {
+		anyResponse, err := codectypes.NewAnyWithValue(msgResponse)
+		assert.NoError(t, err)
+		msgResponses = append(msgResponses, anyResponse)
+	}
+	data, err := (&sdk.TxMsgData{MsgResponses: msgResponses}).Marshal()
+	assert.NoError(t, err)
+
+	chainClient := &composerTestChainClient{txResponse: &sdk.TxResponse{TxHash: "ABCD", Height: 10, Data: hex.EncodeToString(data)}}
+	composer := NewTxComposer(chainClient).WithMemo("replace")
+	msgs := composerTestMsgs()
+	assert.Equal(t, 0, composer.Add(msgs[0]))
+	assert.Equal(t, 1, composer.Add(msgs[1:]...))
+	assert.Equal(t, 3, composer.Len())
+
+	result, err := composer.Broadcast()
+	assert.NoError(t, err)
+	assert.Equal(t, "replace", chainClient.memo)
+	assert.Equal(t, msgs, chainClient.broadcastedMsgs)
+	assert.Equal(t, 0, composer.Len())
+
+	assert.NoError(t, result.Err)
+	assert.Equal(t, -1, result.FailedMsgIndex)
+	assert.Equal(t, int64(10), result.Height)
+	assert.Len(t, result.Results, 3)
+	createResponse, isCreateResponse := result.Results[1].Response.(*exchangetypes.MsgCreateSpotLimitOrderResponse)
+	assert.True(t, isCreateResponse)
+	assert.Equal(t, "0x02", createResponse.OrderHash)
+	assert.Equal(t, msgs[2], result.Results[2].Msg)
+	assert.IsType(t, &exchangetypes.MsgDepositResponse{}, result.Results[2].Response)
+}
+
+func TestTxComposerReportsFailedMsg(t *testing.T) {
+	chainClient := &composerTestChainClient{txResponse: &sdk.TxResponse{
+		TxHash:    "ABCD",
+		Height:    10,
+		Codespace: exchangetypes.ModuleName,
+		Code:      exchangetypes.ErrOrderDoesntExist.ABCICode(),
+		RawLog:    "failed to execute message; message index: 0: order doesnt exist",
+	}}
+	composer := NewTxComposer(chainClient)
+	composer.Add(composerTestMsgs()...)
+
+	result, err := composer.Broadcast()
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(result.Err, exchangetypes.ErrOrderDoesntExist))
+	assert.Equal(t, 0, result.FailedMsgIndex)
+	assert.Error(t, result.Results[0].Err)
+	assert.NoError(t, result.Results[1].Err)
+	assert.Nil(t, result.Results[1].Response)
+
+	_, err = composer.Broadcast()
+	assert.Error(t, err)
+}
+
+func TestTxComposerKeepsMsgsAddedDuringBroadcast(t *testing.T) {
+	msgs := composerTestMsgs()
+	chainClient := &composerTestChainClient{txResponse: &sdk.TxResponse{TxHash: "ABCD"}}
+	composer := NewTxComposer(chainClient)
+	chainClient.onBroadcast = func() { composer.Add(msgs[2]) }
+	composer.Add(msgs[:2]...)
+
+	// a tx that was not sent puts the taken msgs back before the msgs added meanwhile
+	chainClient.broadcastErr = &TxNotSentError{Err: errors.New("failed to CalculateGas: connection refused")}
+	_, err := composer.Broadcast()
+	assert.Error(t, err)
+	assert.Equal(t, msgs, composer.Msgs())
+
+	// msgs added during a successful broadcast are kept for the next tx
+	chainClient.broadcastErr = nil
+	chainClient.onBroadcast = func() { composer.Add(msgs[0]) }
+	_, err = composer.Broadcast()
+	assert.NoError(t, err)
+	assert.Equal(t, msgs, chainClient.broadcastedMsgs)
+	assert.Equal(t, []sdk.Msg{msgs[0]}, composer.Msgs())
+}
+
+func TestTxComposerOnlyRestoresMsgsOfTxsNotSent(t *testing.T) {
+	msgs := composerTestMsgs()
+	chainClient := &composerTestChainClient{}
+	composer := NewTxComposer(chainClient)
+
+	// a tx rejected by CheckTx is not in a block
+	chainClient.txResponse = &sdk.TxResponse{
+		TxHash:    "ABCD",
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrInsufficientFee.ABCICode(),
+		RawLog:    "insufficient fees",
+	}
+	composer.Add(msgs...)
+	result, err := composer.Broadcast()
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(result.Err, sdkerrors.ErrInsufficientFee))
+	assert.Equal(t, msgs, composer.Msgs())
+
+	// a tx that timed out may still be included in a block, so its msgs are not put back
+	chainClient.broadcastErr = errors.Wrapf(ErrTimedOut, "%s", "ABCD")
+	chainClient.errResponse = &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: "ABCD"}}
+	result, err = composer.Broadcast()
+	assert.True(t, errors.Is(err, ErrTimedOut))
+	assert.Equal(t, "ABCD", result.TxHash)
+	assert.Equal(t, 0, composer.Len())
+
+	composer.Add(msgs...)
+	chainClient.broadcastErr = errors.Wrap(ErrBroadcastOutcomeUnknown, "tx ABCD was sent to sentry0: EOF")
+	chainClient.errResponse = nil
+	result, err = composer.Broadcast()
+	assert.True(t, errors.Is(err, ErrBroadcastOutcomeUnknown))
+	assert.Empty(t, result.TxHash)
+	assert.Equal(t, 0, composer.Len())
+}
@@ -0,0 +1,102 @@
+package chain
+
+import (
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/pkg/errors"
+)
+
+// DecodedSignerInfo is the signature information of one of the tx signers
+type DecodedSignerInfo struct {
+	Address  sdk.AccAddress
+	PubKey   cryptotypes.PubKey
+	Sequence uint64
+	// SignMode and Signature are only set for single signatures, multisig signatures are available in SignatureData
+	SignMode      signingtypes.SignMode
+	Signature     []byte
+	SignatureData signingtypes.SignatureData
+}
+
+// DecodedTx is the typed representation of a raw tx
+type DecodedTx struct {
+	Msgs          []sdk.Msg
+	Memo          string
+	TimeoutHeight uint64
+	Fee           sdk.Coins
+	GasLimit      uint64
+	FeePayer      sdk.AccAddress
+	FeeGranter    sdk.AccAddress
+	Signers       []DecodedSignerInfo
+	Tx            sdk.Tx
+}
+
+var (
+	decoderTxConfig     client.TxConfig
+	decoderTxConfigOnce sync.Once
+)
+
+func txDecoderConfig() client.TxConfig {
+	decoderTxConfigOnce.Do(func() {
+		decoderTxConfig = NewTxConfig([]signingtypes.SignMode{
+			signingtypes.SignMode_SIGN_MODE_DIRECT,
+			signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON,
+		})
+	})
+	return decoderTxConfig
+}
+
+// DecodeTx decodes protobuf encoded tx bytes (as returned by the chain node or the explorer) into its messages, fee,
+// memo and signatures. Signatures generated with both direct and amino JSON (including EIP712) sign modes are supported
+func DecodeTx(txBytes []byte) (*DecodedTx, error) {
+	decodedTx, err := txDecoderConfig().TxDecoder()(txBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode tx bytes")
+	}
+
+	sigTx, ok := decodedTx.(authsigning.Tx)
+	if !ok {
+		return nil, errors.Errorf("decoded tx of type %T does not support signatures", decodedTx)
+	}
+
+	signatures, err := sigTx.GetSignaturesV2()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode tx signatures")
+	}
+
+	signers := sigTx.GetSigners()
+	result := &DecodedTx{
+		Msgs:          sigTx.GetMsgs(),
+		Memo:          sigTx.GetMemo(),
+		TimeoutHeight: sigTx.GetTimeoutHeight(),
+		Fee:           sigTx.GetFee(),
+		GasLimit:      sigTx.GetGas(),
+		FeePayer:      sigTx.FeePayer(),
+		FeeGranter:    sigTx.FeeGranter(),
+		Signers:       make([]DecodedSignerInfo, 0, len(signatures)),
+		Tx:            decodedTx,
+	}
+
+	for i, signature := range signatures {
+		signerInfo := DecodedSignerInfo{
+			PubKey:        signature.PubKey,
+			Sequence:      signature.Sequence,
+			SignatureData: signature.Data,
+		}
+		if i < len(signers) {
+			signerInfo.Address = signers[i]
+		}
+		if singleSignature, isSingle := signature.Data.(*signingtypes.SingleSignatureData); isSingle {
+			signerInfo.SignMode = singleSignature.SignMode
+			signerInfo.Signature = singleSignature.Signature
+		}
+
+		result.Signers = append(result.Signers, signerInfo)
+	}
+
+	return result, nil
+}
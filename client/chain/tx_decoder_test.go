@@ -0,0 +1,85 @@
+package chain
+
+import (
+	"encoding/base64"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+)
+
+func TestDecodeTxWithAminoJSONSignature(t *testing.T) {
+	// MsgSend signed with EIP712 (amino JSON sign mode) and the Web3Tx extension
+	encodedTx := "CtQBCpQBChwvY29zbW9zLmJhbmsudjFiZXRhMS5Nc2dTZW5kEnQKKmluajFybGU4eXJ5bmx0cnVtNG1uN2VmbjBtNTJmNXFzajNobTM4eHdydxIqaW5qMWNwc3hldTNzczdyODNtM2EwYTM2YzJzM3hqdTR3NDJyM3NhMjh2GhoKA2luahITNTQ5OTAwMDAwMDAwMDAwMDAwMBjLvYwM+j81Ci8vaW5qZWN0aXZlLnR5cGVzLnYxYmV0YTEuRXh0ZW5zaW9uT3B0aW9uc1dlYjNUeBICCAESfgpeClQKLS9pbmplY3RpdmUuY3J5cHRvLnYxYmV0YTEuZXRoc2VjcDI1NmsxLlB1YktleRIjCiECAVMpGRBIgvdIfgYwsSJyaE3W1+j1FIcVtXdaNsj+qbISBAoCCH8YBBIcChYKA2luahIPMjAwMDAwMDAwMDAwMDAwEIC1GBpBSpQwr6FfXa7KWLz4Eousx1VrPuVMHgMAUHkRVeNK/UpFQztyTAeISB6vjl3Dx2sRKWkqy/hQ7G3lICoajar1HRw="
+	txBytes, err := base64.StdEncoding.DecodeString(encodedTx)
+	assert.NoError(t, err)
+
+	decodedTx, err := DecodeTx(txBytes)
+	assert.NoError(t, err)
+
+	assert.Len(t, decodedTx.Msgs, 1)
+	msgSend, ok := decodedTx.Msgs[0].(*banktypes.MsgSend)
+	assert.True(t, ok)
+	assert.Equal(t, "inj1rle8yrynltrum4mn7efn0m52f5qsj3hm38xwrw", msgSend.FromAddress)
+	assert.Equal(t, "inj1cpsxeu3ss7r83m3a0a36c2s3xju4w42r3sa28v", msgSend.ToAddress)
+	assert.Equal(t, sdk.NewCoins(sdk.NewCoin("inj", sdk.MustNewDecFromStr("5499000000000000000").TruncateInt())), msgSend.Amount)
+
+	assert.Equal(t, uint64(400000), decodedTx.GasLimit)
+	assert.Equal(t, "200000000000000inj", decodedTx.Fee.String())
+	assert.Equal(t, uint64(25370315), decodedTx.TimeoutHeight)
+
+	assert.Len(t, decodedTx.Signers, 1)
+	signer := decodedTx.Signers[0]
+	assert.Equal(t, msgSend.FromAddress, signer.Address.String())
+	assert.Equal(t, sdk.AccAddress(signer.PubKey.Address()), signer.Address)
+	assert.Equal(t, uint64(4), signer.Sequence)
+	assert.Equal(t, signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, signer.SignMode)
+	assert.Len(t, signer.Signature, 65)
+}
+
+func TestDecodeTxWithDirectSignature(t *testing.T) {
+	txConfig := txDecoderConfig()
+	privKey, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	sender := sdk.AccAddress(privKey.PubKey().Address()).String()
+
+	builder := txConfig.NewTxBuilder()
+	assert.NoError(t, builder.SetMsgs(&banktypes.MsgSend{
+		FromAddress: sender,
+		ToAddress:   "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r",
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin("inj", 1000)),
+	}))
+	builder.SetMemo("decoder test")
+	builder.SetGasLimit(150000)
+	builder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("inj", 500)))
+	assert.NoError(t, builder.SetSignatures(signingtypes.SignatureV2{
+		PubKey: privKey.PubKey(),
+		Data: &signingtypes.SingleSignatureData{
+			SignMode:  signingtypes.SignMode_SIGN_MODE_DIRECT,
+			Signature: []byte{1, 2, 3},
+		},
+		Sequence: 7,
+	}))
+
+	txBytes, err := txConfig.TxEncoder()(builder.GetTx())
+	assert.NoError(t, err)
+
+	decodedTx, err := DecodeTx(txBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "decoder test", decodedTx.Memo)
+	assert.Equal(t, sender, decodedTx.FeePayer.String())
+	assert.Len(t, decodedTx.Signers, 1)
+	assert.Equal(t, signingtypes.SignMode_SIGN_MODE_DIRECT, decodedTx.Signers[0].SignMode)
+	assert.Equal(t, []byte{1, 2, 3}, decodedTx.Signers[0].Signature)
+	assert.Equal(t, uint64(7), decodedTx.Signers[0].Sequence)
+	assert.Equal(t, sender, decodedTx.Signers[0].Address.String())
+}
+
+func TestDecodeTxRejectsInvalidBytes(t *testing.T) {
+	_, err := DecodeTx([]byte("not a tx"))
+	assert.Error(t, err)
+}
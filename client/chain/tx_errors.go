@@ -0,0 +1,160 @@
+package chain
+
+import (
+	"fmt"
+	"strings"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+// retryableErrors are the chain errors for which sending the same messages again (with a new account sequence, or
+// later for a full mempool) can succeed without any change from the caller. Only the sequence mismatches are retried
+// by the client broadcasts
+var retryableErrors = []*errorsmod.Error{
+	sdkerrors.ErrWrongSequence,
+	sdkerrors.ErrMempoolIsFull,
+}
+
+// knownErrors are the chain errors recognized in the messages of the errors that only have a text description
+// (for example the simulation errors returned by the gRPC endpoint)
+var knownErrors = []*errorsmod.Error{
+	sdkerrors.ErrWrongSequence,
+	sdkerrors.ErrMempoolIsFull,
+	sdkerrors.ErrInsufficientFunds,
+	sdkerrors.ErrOutOfGas,
+	sdkerrors.ErrInsufficientFee,
+	exchangetypes.ErrOrderDoesntExist,
+	exchangetypes.ErrInsufficientDeposit,
+	exchangetypes.ErrInsufficientOrderMargin,
+	exchangetypes.ErrInvalidPrice,
+	exchangetypes.ErrInvalidQuantity,
+}
+
+// TxError is the error of a transaction rejected by the chain. It wraps the chain registered error for its
+// codespace and code, so it can be checked with errors.Is (for example errors.Is(err, exchangetypes.ErrOrderDoesntExist)
+// or errors.Is(err, sdkerrors.ErrInsufficientFunds))
+type TxError struct {
+	TxHash    string
+	Codespace string
+	Code      uint32
+	Log       string
+}
+
+// NewTxError returns the error of a transaction response, or nil if the transaction was not rejected
+func NewTxError(res *sdk.TxResponse) error {
+	if res == nil || res.Code == 0 {
+		return nil
+	}
+
+	return &TxError{
+		TxHash:    res.TxHash,
+		Codespace: res.Codespace,
+		Code:      res.Code,
+		Log:       res.RawLog,
+	}
+}
+
+func (e *TxError) Error() string {
+	return fmt.Sprintf("error %d (%s): %s", e.Code, e.Codespace, e.Log)
+}
+
+func (e *TxError) Unwrap() error {
+	return errorsmod.ABCIError(e.Codespace, e.Code, e.Log)
+}
+
+// Retryable returns true if the transaction can succeed if it is sent again, possibly after a backoff
+func (e *TxError) Retryable() bool {
+	for _, retryableError := range retryableErrors {
+		if retryableError.Is(e.Unwrap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// TxNotSentError is the error of a transaction that was not sent to the node, because it could not be built, simulated,
+// signed or recorded in the broadcast journal (or it was rejected by the pre broadcast check). Its messages can be
+// broadcast again without the risk of executing them twice
+type TxNotSentError struct {
+	Err error
+}
+
+func (e *TxNotSentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TxNotSentError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable returns true if the messages that produced the error can be sent again without changes.
+// Besides TxError, it recognizes the errors returned by the chain when simulating the transaction
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var txErr *TxError
+	if errors.As(err, &txErr) {
+		return txErr.Retryable()
+	}
+
+	return isOneOf(err, retryableErrors)
+}
+
+// IsSequenceMismatch returns true if the error was caused by the account sequence used to sign the transaction
+func IsSequenceMismatch(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, sdkerrors.ErrWrongSequence) ||
+		strings.Contains(err.Error(), "account sequence mismatch") ||
+		strings.Contains(err.Error(), sdkerrors.ErrWrongSequence.Error())
+}
+
+// ChainError returns the chain registered error that caused err, or nil if it could not be identified
+func ChainError(err error) *errorsmod.Error {
+	if err == nil {
+		return nil
+	}
+
+	var txErr *TxError
+	if errors.As(err, &txErr) {
+		for _, knownError := range knownErrors {
+			if knownError.Is(txErr.Unwrap()) {
+				return knownError
+			}
+		}
+		return nil
+	}
+
+	for _, knownError := range knownErrors {
+		if errors.Is(err, knownError) || strings.Contains(err.Error(), knownError.Error()) {
+			return knownError
+		}
+	}
+	return nil
+}
+
+func isOneOf(err error, candidates []*errorsmod.Error) bool {
+	for _, candidate := range candidates {
+		if errors.Is(err, candidate) || strings.Contains(err.Error(), candidate.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// broadcastError returns the error of a broadcast, including the transaction rejection reported in the response
+func broadcastError(res *txtypes.BroadcastTxResponse, err error) error {
+	if err != nil || res == nil {
+		return err
+	}
+	return NewTxError(res.TxResponse)
+}
@@ -0,0 +1,75 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func TestNewTxErrorReturnsNilForSuccessfulTransactions(t *testing.T) {
+	assert.Nil(t, NewTxError(&sdk.TxResponse{TxHash: "ABCD"}))
+	assert.Nil(t, NewTxError(nil))
+}
+
+func TestTxErrorMatchesChainRegisteredErrors(t *testing.T) {
+	err := NewTxError(&sdk.TxResponse{
+		TxHash:    "ABCD",
+		Codespace: exchangetypes.ModuleName,
+		Code:      exchangetypes.ErrOrderDoesntExist.ABCICode(),
+		RawLog:    "failed to execute message; message index: 0: order doesnt exist",
+	})
+
+	assert.True(t, errors.Is(err, exchangetypes.ErrOrderDoesntExist))
+	assert.False(t, errors.Is(err, exchangetypes.ErrInsufficientDeposit))
+	assert.False(t, IsRetryable(err))
+	assert.Equal(t, exchangetypes.ErrOrderDoesntExist, ChainError(err))
+	assert.Equal(t, "error 19 (exchange): failed to execute message; message index: 0: order doesnt exist", err.Error())
+
+	err = NewTxError(&sdk.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrInsufficientFunds.ABCICode(),
+		RawLog:    "spendable balance 10inj is smaller than 20inj: insufficient funds",
+	})
+	assert.True(t, errors.Is(err, sdkerrors.ErrInsufficientFunds))
+	assert.False(t, IsRetryable(err))
+}
+
+func TestSequenceMismatchIsRetryable(t *testing.T) {
+	txErr := NewTxError(&sdk.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+		RawLog:    "account sequence mismatch, expected 10, got 9: incorrect account sequence",
+	})
+	assert.True(t, IsRetryable(txErr))
+	assert.True(t, IsSequenceMismatch(txErr))
+
+	// simulation errors only have the description of the chain error
+	simulationErr := errors.New("failed to CalculateGas: rpc error: code = Unknown desc = account sequence mismatch, expected 10, got 9: incorrect account sequence")
+	assert.True(t, IsRetryable(simulationErr))
+	assert.True(t, IsSequenceMismatch(simulationErr))
+	assert.Equal(t, sdkerrors.ErrWrongSequence, ChainError(simulationErr))
+
+	tickErr := errors.New("failed to CalculateGas: rpc error: code = Unknown desc = price must be a multiple of the minimum price tick size: invalid price")
+	assert.False(t, IsRetryable(tickErr))
+	assert.Equal(t, exchangetypes.ErrInvalidPrice, ChainError(tickErr))
+}
+
+func TestBroadcastErrorIncludesRejectedTransactions(t *testing.T) {
+	rejected := &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrMempoolIsFull.ABCICode(),
+		RawLog:    "mempool is full",
+	}}
+	assert.True(t, IsRetryable(broadcastError(rejected, nil)))
+	// a full mempool is not retried right away with a new sequence
+	assert.False(t, IsSequenceMismatch(broadcastError(rejected, nil)))
+
+	accepted := &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: "ABCD"}}
+	assert.NoError(t, broadcastError(accepted, nil))
+}
@@ -0,0 +1,66 @@
+package chain
+
+import (
+	"encoding/json"
+	"strings"
+
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/pkg/errors"
+)
+
+// TxMetadata is the client information attached to a transaction memo, used to attribute the transaction (and the
+// resulting fills) to a strategy in post-trade analysis
+type TxMetadata struct {
+	StrategyId string            `json:"strategy_id,omitempty"`
+	SessionId  string            `json:"session_id,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+func (m TxMetadata) IsEmpty() bool {
+	return m.StrategyId == "" && m.SessionId == "" && len(m.Tags) == 0
+}
+
+type taggedMemo struct {
+	Memo   string      `json:"memo,omitempty"`
+	Client *TxMetadata `json:"client"`
+}
+
+// EncodeTxMemo returns the memo containing both the text and the client metadata. The text is returned unchanged if
+// the metadata is empty. The result can't be longer than the default max memo characters of the chain
+func EncodeTxMemo(text string, metadata TxMetadata) (string, error) {
+	memo := text
+	if !metadata.IsEmpty() {
+		encodedMemo, err := json.Marshal(taggedMemo{Memo: text, Client: &metadata})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to encode tx memo")
+		}
+		memo = string(encodedMemo)
+	}
+
+	if uint64(len(memo)) > authtypes.DefaultMaxMemoCharacters {
+		return "", errors.Errorf("tx memo of %d characters is longer than the maximum %d", len(memo), authtypes.DefaultMaxMemoCharacters)
+	}
+
+	return memo, nil
+}
+
+// ParseTxMemo recovers the text and the client metadata from a memo created with EncodeTxMemo. Memos without metadata
+// are returned as text and found is false
+func ParseTxMemo(memo string) (text string, metadata TxMetadata, found bool) {
+	if !strings.HasPrefix(strings.TrimSpace(memo), "{") {
+		return memo, TxMetadata{}, false
+	}
+
+	var parsedMemo taggedMemo
+	if err := json.Unmarshal([]byte(memo), &parsedMemo); err != nil || parsedMemo.Client == nil {
+		return memo, TxMetadata{}, false
+	}
+
+	return parsedMemo.Memo, *parsedMemo.Client, true
+}
+
+// Metadata returns the client metadata attached to the tx memo, if any
+func (t *DecodedTx) Metadata() (TxMetadata, bool) {
+	_, metadata, found := ParseTxMemo(t.Memo)
+	return metadata, found
+}
@@ -0,0 +1,67 @@
+package chain
+
+import (
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxMemoRoundTrip(t *testing.T) {
+	metadata := TxMetadata{
+		StrategyId: "mm-inj-usdt",
+		SessionId:  "8c0f5e3a",
+		Tags:       map[string]string{"env": "prod"},
+	}
+
+	memo, err := EncodeTxMemo("rebalance", metadata)
+	assert.NoError(t, err)
+
+	text, parsedMetadata, found := ParseTxMemo(memo)
+	assert.True(t, found)
+	assert.Equal(t, "rebalance", text)
+	assert.Equal(t, metadata, parsedMetadata)
+}
+
+func TestTxMemoWithoutMetadata(t *testing.T) {
+	memo, err := EncodeTxMemo("plain memo", TxMetadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, "plain memo", memo)
+
+	text, _, found := ParseTxMemo(memo)
+	assert.False(t, found)
+	assert.Equal(t, "plain memo", text)
+
+	// JSON memos created by other clients are not client metadata
+	text, _, found = ParseTxMemo(`{"wasm":{"contract":"inj1"}}`)
+	assert.False(t, found)
+	assert.Equal(t, `{"wasm":{"contract":"inj1"}}`, text)
+}
+
+func TestEncodeTxMemoRejectsLongMemos(t *testing.T) {
+	_, err := EncodeTxMemo(strings.Repeat("a", 250), TxMetadata{StrategyId: "strategy"})
+	assert.Error(t, err)
+}
+
+func TestDecodedTxMetadata(t *testing.T) {
+	memo, err := EncodeTxMemo("", TxMetadata{StrategyId: "twap-1"})
+	assert.NoError(t, err)
+
+	builder := txDecoderConfig().NewTxBuilder()
+	assert.NoError(t, builder.SetMsgs(&banktypes.MsgSend{
+		FromAddress: "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku",
+		ToAddress:   "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r",
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin("inj", 1000)),
+	}))
+	builder.SetMemo(memo)
+	txBytes, err := txDecoderConfig().TxEncoder()(builder.GetTx())
+	assert.NoError(t, err)
+
+	decodedTx, err := DecodeTx(txBytes)
+	assert.NoError(t, err)
+	metadata, found := decodedTx.Metadata()
+	assert.True(t, found)
+	assert.Equal(t, "twap-1", metadata.StrategyId)
+}
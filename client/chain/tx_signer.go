@@ -0,0 +1,63 @@
+package chain
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+// sign sets the signature of the tx, signed with the client TxSigner if set, or else with the keyring key
+func (c *chainClient) sign(clientCtx client.Context, txf tx.Factory, txn client.TxBuilder) error {
+	if c.opts.TxSigner == nil {
+		return tx.Sign(txf, clientCtx.GetFromName(), txn, true)
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), c.broadcastTimeout())
+	defer cancelFn()
+	return signWithTxSigner(ctx, c.opts.TxSigner, clientCtx, txf, txn)
+}
+
+// signWithTxSigner signs the tx in SIGN_MODE_DIRECT for the client context FromAddress, like tx.Sign does with a
+// keyring key
+func signWithTxSigner(ctx context.Context, signer common.TxSigner, clientCtx client.Context, txf tx.Factory, txn client.TxBuilder) error {
+	address := clientCtx.GetFromAddress()
+	pubKey, err := signer.PubKey(ctx, address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the public key of %s", address.String())
+	}
+	if !sdk.AccAddress(pubKey.Address()).Equals(address) {
+		return errors.Errorf("the tx signer public key is not the key of %s", address.String())
+	}
+
+	// the signer infos are part of the sign bytes, so the signature is set empty before getting them
+	sigData := signingtypes.SingleSignatureData{SignMode: signingtypes.SignMode_SIGN_MODE_DIRECT}
+	sig := signingtypes.SignatureV2{PubKey: pubKey, Data: &sigData, Sequence: txf.Sequence()}
+	if err := txn.SetSignatures(sig); err != nil {
+		return err
+	}
+	signerData := authsigning.SignerData{
+		ChainID:       txf.ChainID(),
+		AccountNumber: txf.AccountNumber(),
+		Sequence:      txf.Sequence(),
+		PubKey:        pubKey,
+		Address:       address.String(),
+	}
+	signDoc, err := clientCtx.TxConfig.SignModeHandler().GetSignBytes(signingtypes.SignMode_SIGN_MODE_DIRECT, signerData, txn.GetTx())
+	if err != nil {
+		return errors.Wrap(err, "failed to get the sign bytes")
+	}
+
+	signature, err := signer.SignDirect(ctx, address, signDoc)
+	if err != nil {
+		return errors.Wrap(err, "the tx signer failed to sign the tx")
+	}
+	sigData.Signature = signature
+	return txn.SetSignatures(sig)
+}
@@ -0,0 +1,82 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+)
+
+// privKeyTxSigner signs with a private key, like a remote signer would
+type privKeyTxSigner struct {
+	key   *ethsecp256k1.PrivKey
+	signs int
+}
+
+func (s *privKeyTxSigner) PubKey(context.Context, sdk.AccAddress) (cryptotypes.PubKey, error) {
+	return s.key.PubKey(), nil
+}
+
+func (s *privKeyTxSigner) SignDirect(_ context.Context, _ sdk.AccAddress, signDoc []byte) ([]byte, error) {
+	s.signs++
+	return s.key.Sign(signDoc)
+}
+
+func TestTxSignerSignsTheTxs(t *testing.T) {
+	dryRunLog := NewDryRunLog(0)
+	c, _, _ := newDryRunClient(t, dryRunLog)
+	key, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	address := sdk.AccAddress(key.PubKey().Address())
+	signer := &privKeyTxSigner{key: key}
+	// the client context has no keyring, only the address of the signer account
+	clientCtx, err := NewClientContext("injective-1", "", nil)
+	assert.NoError(t, err)
+	c.ctx = clientCtx.WithFromAddress(address).WithAccountRetriever(fakeAccountRetriever{})
+	c.txFactory = NewTxFactory(c.ctx).WithAccountRetriever(fakeAccountRetriever{}).WithGasPrices(c.opts.GasPrices)
+	c.opts.TxSigner = signer
+
+	msg := banktypes.NewMsgSend(address, address, sdk.NewCoins(sdk.NewInt64Coin("inj", 1)))
+	txBytes, err := c.BuildSignedTx(c.ctx, 3, 9, 0, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, signer.signs)
+
+	decoded, err := c.ctx.TxConfig.TxDecoder()(txBytes)
+	assert.NoError(t, err)
+	sigTx := decoded.(authsigning.SigVerifiableTx)
+	signatures, err := sigTx.GetSignaturesV2()
+	assert.NoError(t, err)
+	assert.Len(t, signatures, 1)
+	assert.True(t, key.PubKey().Equals(signatures[0].PubKey))
+	assert.Equal(t, uint64(9), signatures[0].Sequence)
+	signBytes, err := c.ctx.TxConfig.SignModeHandler().GetSignBytes(signingtypes.SignMode_SIGN_MODE_DIRECT, authsigning.SignerData{
+		ChainID:       "injective-1",
+		AccountNumber: 3,
+		Sequence:      9,
+		PubKey:        key.PubKey(),
+		Address:       address.String(),
+	}, decoded)
+	assert.NoError(t, err)
+	assert.True(t, key.PubKey().VerifySignature(signBytes, signatures[0].Data.(*signingtypes.SingleSignatureData).Signature))
+
+	// the broadcasted txs are signed by the signer too
+	_, err = c.SyncBroadcastMsg(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, signer.signs)
+	assert.Len(t, dryRunLog.Txs(), 1)
+	assert.NoError(t, dryRunLog.Txs()[0].Err)
+
+	// a signer answering with the key of another account is rejected
+	other, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	c.opts.TxSigner = &privKeyTxSigner{key: other}
+	_, err = c.BuildSignedTx(c.ctx, 3, 9, 0, msg)
+	assert.EqualError(t, err, "failed to Sign Tx: the tx signer public key is not the key of "+address.String())
+}
@@ -0,0 +1,149 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const defaultUpgradePollInterval = 30 * time.Second
+
+// ErrUpgradeQuiesced is returned by UpgradeWatcher.CheckMsgs while the chain is close to an upgrade halt height
+var ErrUpgradeQuiesced = errors.New("broadcasting is paused for the scheduled chain upgrade")
+
+// UpgradeStatus is the scheduled upgrade observed in the last poll. Plan is nil if no upgrade is scheduled
+type UpgradeStatus struct {
+	Plan          *upgradetypes.Plan
+	CurrentHeight int64
+	// BlocksLeft is the number of blocks until the upgrade halt height
+	BlocksLeft int64
+	// Quiesced is true if the msgs are rejected by CheckMsgs
+	Quiesced   bool
+	ObservedAt time.Time
+}
+
+type UpgradeWatcherConfig struct {
+	PollInterval time.Duration
+	// QuiesceBlocks is the number of blocks before the halt height from which CheckMsgs rejects the msgs, until the
+	// upgrade plan is done. Zero only reports the upgrades
+	QuiesceBlocks int64
+	// OnUpgradeStatus is called when an upgrade is scheduled, changed or cancelled, and when the quiesce starts
+	OnUpgradeStatus func(status UpgradeStatus)
+	// Clock of the polls and of the observation times, the real clock by default
+	Clock clock.Clock
+}
+
+// UpgradeWatcher polls the upgrade module for the scheduled upgrade plan, warns about it and can stop the broadcasts
+// a few blocks before the halt height, so no tx is left pending (or is sent again) across the upgrade. Install it in
+// the chain client with common.OptionPreBroadcastCheck(watcher.CheckMsgs)
+type UpgradeWatcher struct {
+	queryClient upgradetypes.QueryClient
+	config      UpgradeWatcherConfig
+	logger      log.Logger
+
+	mux    sync.RWMutex
+	status UpgradeStatus
+}
+
+// NewUpgradeWatcher creates the watcher for the upgrade query client, e.g.
+// upgradetypes.NewQueryClient(chainClient.QueryClient())
+func NewUpgradeWatcher(queryClient upgradetypes.QueryClient, config UpgradeWatcherConfig) *UpgradeWatcher {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultUpgradePollInterval
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &UpgradeWatcher{
+		queryClient: queryClient,
+		config:      config,
+		logger:      log.WithField("module", "upgrade-watcher"),
+	}
+}
+
+// Watch polls the upgrade plan until the context is done. Query errors are logged and keep the previous status
+func (w *UpgradeWatcher) Watch(ctx context.Context) {
+	ticker := w.config.Clock.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Poll(ctx); err != nil {
+			w.logger.WithError(err).Warningln("failed to poll the upgrade plan")
+		}
+
+		select {
+		case <-ticker.C():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Poll queries the current upgrade plan and the chain height, and updates the status
+func (w *UpgradeWatcher) Poll(ctx context.Context) (UpgradeStatus, error) {
+	var header metadata.MD
+	res, err := w.queryClient.CurrentPlan(ctx, &upgradetypes.QueryCurrentPlanRequest{}, grpc.Header(&header))
+	if err != nil {
+		return w.Status(), errors.Wrap(err, "failed to query the current upgrade plan")
+	}
+	height, err := ResolvedHeight(header)
+	if err != nil {
+		return w.Status(), errors.Wrap(err, "failed to read the chain height")
+	}
+
+	status := UpgradeStatus{Plan: res.Plan, CurrentHeight: height, ObservedAt: w.config.Clock.Now()}
+	if res.Plan != nil {
+		status.BlocksLeft = res.Plan.Height - height
+		status.Quiesced = w.config.QuiesceBlocks > 0 && status.BlocksLeft <= w.config.QuiesceBlocks
+	}
+
+	w.mux.Lock()
+	changed := upgradeStatusChanged(w.status, status)
+	w.status = status
+	w.mux.Unlock()
+
+	if changed {
+		if status.Plan != nil {
+			w.logger.WithField("name", status.Plan.Name).WithField("height", status.Plan.Height).WithField("quiesced", status.Quiesced).Warningln("chain upgrade scheduled")
+		}
+		if w.config.OnUpgradeStatus != nil {
+			w.config.OnUpgradeStatus(status)
+		}
+	}
+	return status, nil
+}
+
+// Status returns the status of the last successful poll
+func (w *UpgradeWatcher) Status() UpgradeStatus {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+
+	return w.status
+}
+
+// CheckMsgs returns ErrUpgradeQuiesced if the chain is within QuiesceBlocks of the upgrade halt height
+func (w *UpgradeWatcher) CheckMsgs(msgs ...sdk.Msg) error {
+	status := w.Status()
+	if !status.Quiesced {
+		return nil
+	}
+	return errors.Wrapf(ErrUpgradeQuiesced, "upgrade %s at height %d, current height %d", status.Plan.Name, status.Plan.Height, status.CurrentHeight)
+}
+
+func upgradeStatusChanged(previous UpgradeStatus, current UpgradeStatus) bool {
+	if (previous.Plan == nil) != (current.Plan == nil) {
+		return true
+	}
+	if current.Plan == nil {
+		return false
+	}
+	return previous.Plan.Name != current.Plan.Name || previous.Plan.Height != current.Plan.Height || previous.Quiesced != current.Quiesced
+}
@@ -0,0 +1,71 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type fakeUpgradeQueryClient struct {
+	upgradetypes.QueryClient
+	plan   *upgradetypes.Plan
+	height int64
+}
+
+func (c *fakeUpgradeQueryClient) CurrentPlan(ctx context.Context, in *upgradetypes.QueryCurrentPlanRequest, opts ...grpc.CallOption) (*upgradetypes.QueryCurrentPlanResponse, error) {
+	for _, opt := range opts {
+		if headerOption, ok := opt.(grpc.HeaderCallOption); ok {
+			*headerOption.HeaderAddr = metadata.Pairs(grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(c.height, 10))
+		}
+	}
+	return &upgradetypes.QueryCurrentPlanResponse{Plan: c.plan}, nil
+}
+
+func TestUpgradeWatcherQuiescesBeforeHaltHeight(t *testing.T) {
+	queryClient := &fakeUpgradeQueryClient{height: 900}
+	var reported []UpgradeStatus
+	watcher := NewUpgradeWatcher(queryClient, UpgradeWatcherConfig{
+		QuiesceBlocks:   10,
+		OnUpgradeStatus: func(status UpgradeStatus) { reported = append(reported, status) },
+	})
+
+	_, err := watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, reported)
+
+	queryClient.plan = &upgradetypes.Plan{Name: "v1.13.0", Height: 1000}
+	status, err := watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), status.BlocksLeft)
+	assert.False(t, status.Quiesced)
+	assert.NoError(t, watcher.CheckMsgs())
+
+	queryClient.height = 950
+	_, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, reported, 1)
+
+	queryClient.height = 990
+	status, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, status.Quiesced)
+	assert.True(t, errors.Is(watcher.CheckMsgs(), ErrUpgradeQuiesced))
+
+	// the broadcasts resume once the plan is done
+	queryClient.plan = nil
+	queryClient.height = 1001
+	_, err = watcher.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, watcher.CheckMsgs())
+
+	assert.Len(t, reported, 3)
+	assert.True(t, reported[1].Quiesced)
+	assert.Nil(t, reported[2].Plan)
+}
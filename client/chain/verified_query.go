@@ -0,0 +1,136 @@
+package chain
+
+import (
+	"context"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+// TrustedHeaderSource provides headers verified by a light client. *light.Client from cometbft implements it
+type TrustedHeaderSource interface {
+	VerifyLightBlockAtHeight(ctx context.Context, height int64, now time.Time) (*tmtypes.LightBlock, error)
+}
+
+// VerifiedQuerier reads the chain state through ABCI queries with Merkle proofs, and verifies the proofs against
+// the app hash of the light client verified headers, so the node serving the queries does not need to be trusted
+type VerifiedQuerier struct {
+	rpcClient    rpcclient.ABCIClient
+	headers      TrustedHeaderSource
+	proofRuntime *merkle.ProofRuntime
+	clock        clock.Clock
+}
+
+func NewVerifiedQuerier(rpcClient rpcclient.ABCIClient, headers TrustedHeaderSource) *VerifiedQuerier {
+	return &VerifiedQuerier{
+		rpcClient:    rpcClient,
+		headers:      headers,
+		proofRuntime: rootmulti.DefaultProofRuntime(),
+		clock:        clock.Real(),
+	}
+}
+
+// SetClock sets the clock of the time the headers are verified at, which decides if the trusted headers expired
+func (q *VerifiedQuerier) SetClock(c clock.Clock) {
+	q.clock = clock.OrReal(c)
+}
+
+// QueryStore returns the value of the key in the module store at the height (0 for the latest height), together with
+// the height of the returned state. A nil value means the key is not present, which is also proven
+func (q *VerifiedQuerier) QueryStore(ctx context.Context, storeName string, key []byte, height int64) ([]byte, int64, error) {
+	res, err := q.rpcClient.ABCIQueryWithOptions(ctx, "/store/"+storeName+"/key", key, rpcclient.ABCIQueryOptions{
+		Height: height,
+		Prove:  true,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to query the %s store", storeName)
+	}
+
+	response := res.Response
+	if !response.IsOK() {
+		return nil, 0, errors.Errorf("the %s store query failed with code %d: %s", storeName, response.Code, response.Log)
+	}
+	if response.ProofOps == nil || len(response.ProofOps.Ops) == 0 {
+		return nil, 0, errors.Errorf("the %s store query response has no proof", storeName)
+	}
+	// a valid proof of the state at another height would pass the verification
+	if height != 0 && response.Height != height {
+		return nil, 0, errors.Errorf("the %s store query was requested at height %d but the node answered at height %d", storeName, height, response.Height)
+	}
+
+	// the app hash resulting from the state at a height is included in the header of the next block
+	lightBlock, err := q.headers.VerifyLightBlockAtHeight(ctx, response.Height+1, q.clock.Now())
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to get the trusted header at height %d", response.Height+1)
+	}
+	appHash := lightBlock.Header.AppHash
+
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL).
+		String()
+	if response.Value == nil {
+		err = q.proofRuntime.VerifyAbsence(response.ProofOps, appHash, keyPath)
+	} else {
+		err = q.proofRuntime.VerifyValue(response.ProofOps, appHash, keyPath, response.Value)
+	}
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "invalid proof for the %s store query at height %d", storeName, response.Height)
+	}
+
+	return response.Value, response.Height, nil
+}
+
+// BankBalance returns the verified bank balance of the denom for the address
+func (q *VerifiedQuerier) BankBalance(ctx context.Context, address sdk.AccAddress, denom string, height int64) (sdk.Coin, int64, error) {
+	key := append(banktypes.CreateAccountBalancesPrefix(address), []byte(denom)...)
+	value, resolvedHeight, err := q.QueryStore(ctx, banktypes.StoreKey, key, height)
+	if err != nil {
+		return sdk.Coin{}, 0, err
+	}
+
+	amount := math.ZeroInt()
+	if value != nil {
+		if err := amount.Unmarshal(value); err != nil {
+			// balances stored before v0.46 are the full coin
+			var balance sdk.Coin
+			if balance.Unmarshal(value) != nil {
+				return sdk.Coin{}, 0, errors.Wrapf(err, "invalid %s balance of %s", denom, address.String())
+			}
+			amount = balance.Amount
+		}
+	}
+
+	return sdk.NewCoin(denom, amount), resolvedHeight, nil
+}
+
+// SubaccountDeposit returns the verified exchange deposit of the denom for the subaccount
+func (q *VerifiedQuerier) SubaccountDeposit(ctx context.Context, subaccountId ethcommon.Hash, denom string, height int64) (exchangetypes.Deposit, int64, error) {
+	value, resolvedHeight, err := q.QueryStore(ctx, exchangetypes.StoreKey, exchangetypes.GetDepositKey(subaccountId, denom), height)
+	if err != nil {
+		return exchangetypes.Deposit{}, 0, err
+	}
+
+	deposit := exchangetypes.Deposit{
+		AvailableBalance: sdk.ZeroDec(),
+		TotalBalance:     sdk.ZeroDec(),
+	}
+	if value != nil {
+		if err := deposit.Unmarshal(value); err != nil {
+			return exchangetypes.Deposit{}, 0, errors.Wrapf(err, "invalid %s deposit of subaccount %s", denom, subaccountId.Hex())
+		}
+	}
+
+	return deposit, resolvedHeight, nil
+}
@@ -0,0 +1,114 @@
+package chain
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	dbm "github.com/cometbft/cometbft-db"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/libs/log"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type storeABCIClient struct {
+	rpcclient.ABCIClient
+	store *rootmulti.Store
+	// answerHeight, when set, replaces the requested height (as a node returning stale state)
+	answerHeight int64
+}
+
+func (c *storeABCIClient) ABCIQueryWithOptions(ctx context.Context, path string, data bytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	height := opts.Height
+	if c.answerHeight != 0 {
+		height = c.answerHeight
+	}
+	response := c.store.Query(abci.RequestQuery{
+		Path:   strings.TrimPrefix(path, "/store"),
+		Data:   data,
+		Height: height,
+		Prove:  opts.Prove,
+	})
+	return &ctypes.ResultABCIQuery{Response: response}, nil
+}
+
+type fixedHeaderSource struct {
+	appHash []byte
+}
+
+func (s *fixedHeaderSource) VerifyLightBlockAtHeight(ctx context.Context, height int64, now time.Time) (*tmtypes.LightBlock, error) {
+	return &tmtypes.LightBlock{
+		SignedHeader: &tmtypes.SignedHeader{Header: &tmtypes.Header{Height: height, AppHash: s.appHash}},
+	}, nil
+}
+
+func TestVerifiedQuerierBankBalance(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(banktypes.StoreKey)
+	store := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger())
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	assert.NoError(t, store.LoadLatestVersion())
+
+	address := sdk.MustAccAddressFromBech32("inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku")
+	amount, err := math.NewInt(1500).Marshal()
+	assert.NoError(t, err)
+	store.GetKVStore(storeKey).Set(append(banktypes.CreateAccountBalancesPrefix(address), []byte("inj")...), amount)
+	commitId := store.Commit()
+
+	headers := &fixedHeaderSource{appHash: commitId.Hash}
+	querier := NewVerifiedQuerier(&storeABCIClient{store: store}, headers)
+
+	balance, height, err := querier.BankBalance(context.Background(), address, "inj", commitId.Version)
+	assert.NoError(t, err)
+	assert.Equal(t, commitId.Version, height)
+	assert.Equal(t, "1500inj", balance.String())
+
+	balance, _, err = querier.BankBalance(context.Background(), address, "peggy0xdAC17F958D2ee523a2206206994597C13D831ec7", commitId.Version)
+	assert.NoError(t, err)
+	assert.True(t, balance.Amount.IsZero())
+
+	headers.appHash = []byte("untrusted app hash")
+	_, _, err = querier.BankBalance(context.Background(), address, "inj", commitId.Version)
+	assert.ErrorContains(t, err, "invalid proof")
+}
+
+func TestVerifiedQuerierRejectsAnswersAtAnotherHeight(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(banktypes.StoreKey)
+	store := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger())
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	assert.NoError(t, store.LoadLatestVersion())
+
+	address := sdk.MustAccAddressFromBech32("inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku")
+	balanceKey := append(banktypes.CreateAccountBalancesPrefix(address), []byte("inj")...)
+	for _, value := range []int64{1500, 300} {
+		amount, err := math.NewInt(value).Marshal()
+		assert.NoError(t, err)
+		store.GetKVStore(storeKey).Set(balanceKey, amount)
+		store.Commit()
+	}
+	staleCommit := store.LastCommitID()
+	staleCommit.Version--
+	staleInfo, err := store.GetCommitInfo(staleCommit.Version)
+	assert.NoError(t, err)
+
+	// the node answers with a valid proof of the state at the previous height
+	client := &storeABCIClient{store: store, answerHeight: staleCommit.Version}
+	querier := NewVerifiedQuerier(client, &fixedHeaderSource{appHash: staleInfo.Hash()})
+
+	_, _, err = querier.BankBalance(context.Background(), address, "inj", store.LastCommitID().Version)
+	assert.ErrorContains(t, err, "answered at height 1")
+
+	balance, height, err := querier.BankBalance(context.Background(), address, "inj", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, staleCommit.Version, height)
+	assert.Equal(t, "1500inj", balance.String())
+}
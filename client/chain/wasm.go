@@ -0,0 +1,171 @@
+package chain
+
+import (
+	"encoding/json"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	wasmxtypes "github.com/InjectiveLabs/sdk-go/chain/wasmx/types"
+)
+
+// ContractOrderInfo is the JSON representation of exchangetypes.OrderInfo understood by the
+// Injective CosmWasm bindings (injective-cosmwasm)
+type ContractOrderInfo struct {
+	SubaccountId string `json:"subaccount_id"`
+	FeeRecipient string `json:"fee_recipient,omitempty"`
+	Price        string `json:"price"`
+	Quantity     string `json:"quantity"`
+	Cid          string `json:"cid,omitempty"`
+}
+
+// ContractSpotOrder is the contract compatible representation of a spot order. The order type is encoded as a number,
+// as expected by the contracts
+type ContractSpotOrder struct {
+	MarketId     string                  `json:"market_id"`
+	OrderInfo    ContractOrderInfo       `json:"order_info"`
+	OrderType    exchangetypes.OrderType `json:"order_type"`
+	TriggerPrice string                  `json:"trigger_price,omitempty"`
+}
+
+// ContractDerivativeOrder is the contract compatible representation of a derivative order
+type ContractDerivativeOrder struct {
+	MarketId     string                  `json:"market_id"`
+	OrderInfo    ContractOrderInfo       `json:"order_info"`
+	OrderType    exchangetypes.OrderType `json:"order_type"`
+	Margin       string                  `json:"margin"`
+	TriggerPrice string                  `json:"trigger_price,omitempty"`
+}
+
+func newContractOrderInfo(orderInfo exchangetypes.OrderInfo) ContractOrderInfo {
+	return ContractOrderInfo{
+		SubaccountId: orderInfo.SubaccountId,
+		FeeRecipient: orderInfo.FeeRecipient,
+		Price:        orderInfo.Price.String(),
+		Quantity:     orderInfo.Quantity.String(),
+		Cid:          orderInfo.Cid,
+	}
+}
+
+func NewContractSpotOrder(order *exchangetypes.SpotOrder) ContractSpotOrder {
+	contractOrder := ContractSpotOrder{
+		MarketId:  order.MarketId,
+		OrderInfo: newContractOrderInfo(order.OrderInfo),
+		OrderType: order.OrderType,
+	}
+	if order.TriggerPrice != nil {
+		contractOrder.TriggerPrice = order.TriggerPrice.String()
+	}
+
+	return contractOrder
+}
+
+func NewContractDerivativeOrder(order *exchangetypes.DerivativeOrder) ContractDerivativeOrder {
+	contractOrder := ContractDerivativeOrder{
+		MarketId:  order.MarketId,
+		OrderInfo: newContractOrderInfo(order.OrderInfo),
+		OrderType: order.OrderType,
+		Margin:    order.Margin.String(),
+	}
+	if order.TriggerPrice != nil {
+		contractOrder.TriggerPrice = order.TriggerPrice.String()
+	}
+
+	return contractOrder
+}
+
+// EncodeContractMessage wraps the payload in an object keyed by the action name, following the CosmWasm convention
+// for execute messages (e.g. {"create_spot_order": {...}})
+func EncodeContractMessage(action string, payload interface{}) ([]byte, error) {
+	if action == "" {
+		return nil, errors.New("contract message action can not be empty")
+	}
+	if payload == nil {
+		payload = struct{}{}
+	}
+
+	return json.Marshal(map[string]interface{}{action: payload})
+}
+
+func encodeRawContractMessage(msg interface{}) ([]byte, error) {
+	switch m := msg.(type) {
+	case []byte:
+		return m, nil
+	case json.RawMessage:
+		return m, nil
+	case string:
+		return []byte(m), nil
+	default:
+		return json.Marshal(msg)
+	}
+}
+
+// NewMsgExecuteContract builds a wasm MsgExecuteContract. The message can be provided already encoded
+// (as []byte, json.RawMessage or string) or as any value that will be marshalled to JSON
+func NewMsgExecuteContract(sender string, contractAddress string, executeMsg interface{}, funds sdk.Coins) (*wasmtypes.MsgExecuteContract, error) {
+	msgBytes, err := encodeRawContractMessage(executeMsg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode contract execute message")
+	}
+
+	msg := &wasmtypes.MsgExecuteContract{
+		Sender:   sender,
+		Contract: contractAddress,
+		Msg:      msgBytes,
+		Funds:    funds,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// NewMsgInstantiateContract builds a wasm MsgInstantiateContract. An empty admin creates a contract without admin
+func NewMsgInstantiateContract(sender string, admin string, codeId uint64, label string, initMsg interface{}, funds sdk.Coins) (*wasmtypes.MsgInstantiateContract, error) {
+	msgBytes, err := encodeRawContractMessage(initMsg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode contract instantiate message")
+	}
+
+	msg := &wasmtypes.MsgInstantiateContract{
+		Sender: sender,
+		Admin:  admin,
+		CodeID: codeId,
+		Label:  label,
+		Msg:    msgBytes,
+		Funds:  funds,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// NewMsgExecuteContractCompat builds the wasmx version of MsgExecuteContract, that can be signed with EIP712
+func NewMsgExecuteContractCompat(sender string, contractAddress string, executeMsg interface{}, funds sdk.Coins) (*wasmxtypes.MsgExecuteContractCompat, error) {
+	msgBytes, err := encodeRawContractMessage(executeMsg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode contract execute message")
+	}
+
+	fundsString := "0"
+	if !funds.Empty() {
+		fundsString = funds.String()
+	}
+
+	msg := &wasmxtypes.MsgExecuteContractCompat{
+		Sender:   sender,
+		Contract: contractAddress,
+		Msg:      string(msgBytes),
+		Funds:    fundsString,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
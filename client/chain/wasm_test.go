@@ -0,0 +1,98 @@
+package chain
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+func TestContractSpotOrderEncoding(t *testing.T) {
+	triggerPrice := sdk.MustNewDecFromStr("0")
+	order := exchangetypes.SpotOrder{
+		MarketId: "0x7a57e705bb4e09c88aecfc295569481dbf2fe1d5efe364651fbe72385938e9b0",
+		OrderInfo: exchangetypes.OrderInfo{
+			SubaccountId: "0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000",
+			FeeRecipient: "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku",
+			Price:        sdk.MustNewDecFromStr("0.000000000012"),
+			Quantity:     sdk.MustNewDecFromStr("1000000000000000000"),
+			Cid:          "my-order",
+		},
+		OrderType:    exchangetypes.OrderType_BUY,
+		TriggerPrice: &triggerPrice,
+	}
+
+	msg, err := EncodeContractMessage("create_spot_order", NewContractSpotOrder(&order))
+	assert.NoError(t, err)
+
+	var decoded map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal(msg, &decoded))
+
+	contractOrder := decoded["create_spot_order"]
+	assert.Equal(t, order.MarketId, contractOrder["market_id"])
+	assert.Equal(t, float64(exchangetypes.OrderType_BUY), contractOrder["order_type"])
+	assert.Equal(t, "0.000000000000000000", contractOrder["trigger_price"])
+
+	orderInfo := contractOrder["order_info"].(map[string]interface{})
+	assert.Equal(t, order.OrderInfo.SubaccountId, orderInfo["subaccount_id"])
+	assert.Equal(t, "0.000000000012000000", orderInfo["price"])
+	assert.Equal(t, "my-order", orderInfo["cid"])
+}
+
+func TestContractDerivativeOrderIncludesMargin(t *testing.T) {
+	order := exchangetypes.DerivativeOrder{
+		MarketId: "0x4ca0f92fc28be0c9761326016b5a1a2177dd6375558365116b5bdda9abc229ce",
+		OrderInfo: exchangetypes.OrderInfo{
+			SubaccountId: "0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000",
+			Price:        sdk.MustNewDecFromStr("25000000000"),
+			Quantity:     sdk.MustNewDecFromStr("0.1"),
+		},
+		OrderType: exchangetypes.OrderType_SELL,
+		Margin:    sdk.MustNewDecFromStr("2500000000"),
+	}
+
+	contractOrder := NewContractDerivativeOrder(&order)
+
+	assert.Equal(t, "2500000000.000000000000000000", contractOrder.Margin)
+	assert.Equal(t, "", contractOrder.TriggerPrice)
+	assert.Equal(t, "", contractOrder.OrderInfo.FeeRecipient)
+}
+
+func TestEncodeContractMessageRequiresAction(t *testing.T) {
+	_, err := EncodeContractMessage("", nil)
+	assert.Error(t, err)
+
+	msg, err := EncodeContractMessage("increment", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"increment":{}}`, string(msg))
+}
+
+func TestNewMsgExecuteContractCompatFormatsFunds(t *testing.T) {
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	contract := "inj1ady3s7whq30l4fx8sj3x6muv5mx4dfdlcpv8n7"
+
+	msg, err := NewMsgExecuteContractCompat(sender, contract, `{"increment":{}}`, sdk.Coins{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0", msg.Funds)
+
+	funds := sdk.NewCoins(sdk.NewInt64Coin("inj", 10), sdk.NewInt64Coin("peggy0xdAC17F958D2ee523a2206206994597C13D831ec7", 5))
+	msg, err = NewMsgExecuteContractCompat(sender, contract, map[string]interface{}{"increment": struct{}{}}, funds)
+	assert.NoError(t, err)
+	assert.Equal(t, funds.String(), msg.Funds)
+	assert.Equal(t, `{"increment":{}}`, msg.Msg)
+}
+
+func TestNewMsgExecuteContractValidatesMessage(t *testing.T) {
+	sender := "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	contract := "inj1ady3s7whq30l4fx8sj3x6muv5mx4dfdlcpv8n7"
+
+	_, err := NewMsgExecuteContract(sender, contract, []byte("not json"), nil)
+	assert.Error(t, err)
+
+	msg, err := NewMsgExecuteContract(sender, contract, json.RawMessage(`{"increment":{}}`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, contract, msg.Contract)
+}
@@ -0,0 +1,193 @@
+package clock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when the test advances it. The timers and tickers fire during Advance, in
+// the order of their deadlines, with the time they were due. Like the real ones their channels have a buffer of one
+// tick and the ticks of a slow receiver are dropped
+type Fake struct {
+	mux     sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	// changed is closed and replaced when a waiter is added, for BlockUntil
+	changed chan struct{}
+}
+
+// NewFake creates a fake clock set at now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, changed: make(chan struct{})}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	w := &fakeWaiter{clock: f, c: make(chan time.Time, 1)}
+	w.reset(d, 0)
+	return fakeTimer{w}
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	w := &fakeWaiter{clock: f, c: make(chan time.Time, 1)}
+	w.reset(d, d)
+	return fakeTicker{w}
+}
+
+// Advance moves the time forward by d, firing the timers and tickers due until then
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the time to t, firing the timers and tickers due until then. The time never moves backward
+func (f *Fake) Set(t time.Time) {
+	for {
+		f.mux.Lock()
+		if len(f.waiters) == 0 || f.waiters[0].deadline.After(t) {
+			if t.After(f.now) {
+				f.now = t
+			}
+			f.mux.Unlock()
+			return
+		}
+
+		w := f.waiters[0]
+		if w.deadline.After(f.now) {
+			f.now = w.deadline
+		}
+		f.removeLocked(w)
+		if w.period > 0 {
+			w.deadline = w.deadline.Add(w.period)
+			f.addLocked(w)
+		}
+		fired := f.now
+		f.mux.Unlock()
+
+		select {
+		case w.c <- fired:
+		default:
+		}
+	}
+}
+
+// Waiters returns the number of active timers and tickers
+func (f *Fake) Waiters() int {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n timers and tickers are active, so a test can advance the clock once the
+// goroutine under test is waiting on it
+func (f *Fake) BlockUntil(ctx context.Context, n int) error {
+	for {
+		f.mux.Lock()
+		active, changed := len(f.waiters), f.changed
+		f.mux.Unlock()
+		if active >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (f *Fake) addLocked(w *fakeWaiter) {
+	f.waiters = append(f.waiters, w)
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+	})
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// removeLocked removes the waiter and returns whether it was active
+func (f *Fake) removeLocked(w *fakeWaiter) bool {
+	for i, waiter := range f.waiters {
+		if waiter == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeWaiter struct {
+	clock    *Fake
+	c        chan time.Time
+	deadline time.Time
+	period   time.Duration
+}
+
+func (w *fakeWaiter) stop() bool {
+	w.clock.mux.Lock()
+	defer w.clock.mux.Unlock()
+
+	return w.clock.removeLocked(w)
+}
+
+// reset schedules the waiter d after the current time, repeating every period for the tickers. A timer with a
+// non-positive d fires immediately
+func (w *fakeWaiter) reset(d time.Duration, period time.Duration) bool {
+	w.clock.mux.Lock()
+	defer w.clock.mux.Unlock()
+
+	active := w.clock.removeLocked(w)
+	w.period = period
+	w.deadline = w.clock.now.Add(d)
+	if d <= 0 && period == 0 {
+		select {
+		case w.c <- w.clock.now:
+		default:
+		}
+		return active
+	}
+	w.clock.addLocked(w)
+	return active
+}
+
+type fakeTimer struct {
+	*fakeWaiter
+}
+
+func (t fakeTimer) C() <-chan time.Time        { return t.c }
+func (t fakeTimer) Stop() bool                 { return t.stop() }
+func (t fakeTimer) Reset(d time.Duration) bool { return t.reset(d, 0) }
+
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+func (t fakeTicker) C() <-chan time.Time { return t.c }
+func (t fakeTicker) Stop()               { t.stop() }
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.reset(d, d)
+}
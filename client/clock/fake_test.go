@@ -0,0 +1,120 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeTimer(t *testing.T) {
+	clock := NewFake(start)
+	timer := clock.NewTimer(time.Minute)
+	assert.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(59 * time.Second)
+	_, fired := received(timer.C())
+	assert.False(t, fired)
+	assert.Equal(t, 59*time.Second, clock.Since(start))
+
+	clock.Advance(time.Hour)
+	firedAt, fired := received(timer.C())
+	assert.True(t, fired)
+	assert.Equal(t, start.Add(time.Minute), firedAt)
+	assert.Equal(t, start.Add(time.Hour+59*time.Second), clock.Now())
+	assert.Equal(t, 0, clock.Waiters())
+	assert.False(t, timer.Stop())
+
+	assert.False(t, timer.Reset(time.Second))
+	assert.True(t, timer.Stop())
+	clock.Advance(time.Second)
+	_, fired = received(timer.C())
+	assert.False(t, fired)
+
+	// the timers of non-positive durations fire immediately, like the real ones
+	firedAt, fired = received(clock.After(0))
+	assert.True(t, fired)
+	assert.Equal(t, clock.Now(), firedAt)
+
+	clock.Set(start)
+	assert.Equal(t, start.Add(time.Hour+time.Minute), clock.Now(), "the time never moves backward")
+}
+
+func TestFakeTickerFiresInDeadlineOrder(t *testing.T) {
+	clock := NewFake(start)
+	ticker := clock.NewTicker(10 * time.Second)
+	timer := clock.NewTimer(15 * time.Second)
+
+	clock.Advance(10 * time.Second)
+	tick, fired := received(ticker.C())
+	assert.True(t, fired)
+	assert.Equal(t, start.Add(10*time.Second), tick)
+
+	// the ticks of a slow receiver are dropped
+	clock.Advance(30 * time.Second)
+	tick, fired = received(ticker.C())
+	assert.True(t, fired)
+	assert.Equal(t, start.Add(20*time.Second), tick)
+	_, fired = received(ticker.C())
+	assert.False(t, fired)
+	firedAt, _ := received(timer.C())
+	assert.Equal(t, start.Add(15*time.Second), firedAt)
+
+	ticker.Reset(time.Minute)
+	clock.Advance(59 * time.Second)
+	_, fired = received(ticker.C())
+	assert.False(t, fired)
+	clock.Advance(time.Second)
+	tick, _ = received(ticker.C())
+	assert.Equal(t, start.Add(100*time.Second), tick)
+
+	ticker.Stop()
+	assert.Equal(t, 0, clock.Waiters())
+	assert.Panics(t, func() { clock.NewTicker(0) })
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	clock := NewFake(start)
+	done := make(chan time.Time)
+	go func() {
+		done <- <-clock.After(time.Second)
+	}()
+
+	assert.NoError(t, clock.BlockUntil(context.Background(), 1))
+	clock.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), <-done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, clock.BlockUntil(ctx, 1), context.Canceled)
+}
+
+func TestRealClock(t *testing.T) {
+	clock := OrReal(nil)
+	assert.Equal(t, Real(), clock)
+	fake := NewFake(start)
+	assert.Equal(t, Clock(fake), OrReal(fake))
+
+	before := time.Now()
+	assert.False(t, clock.Now().Before(before))
+	assert.True(t, clock.Since(before) >= 0)
+
+	timer := clock.NewTimer(time.Millisecond)
+	<-timer.C()
+	ticker := clock.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+	<-clock.After(time.Millisecond)
+}
@@ -0,0 +1,191 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+const DefaultFanOutParallelism = 16
+
+// ConnPool spreads the calls over several connections to the same endpoint. Every connection is a separate HTTP/2
+// connection, so parallel calls are not limited by the concurrent streams allowed per connection, and a large response
+// does not hold the flow control window of the others. Each call goes to the connection with the fewest calls in
+// flight.
+//
+// ConnPool implements grpc.ClientConnInterface, so the generated clients can be built on it, e.g.
+// spotExchangePB.NewInjectiveSpotExchangeRPCClient(pool)
+type ConnPool struct {
+	conns    []*grpc.ClientConn
+	inFlight []int64
+	next     uint32
+}
+
+var _ grpc.ClientConnInterface = (*ConnPool)(nil)
+
+// DialPool opens size connections to the target
+func DialPool(target string, size int, dialOptions ...grpc.DialOption) (*ConnPool, error) {
+	if size < 1 {
+		return nil, errors.Errorf("invalid connection pool size %d", size)
+	}
+
+	pool := &ConnPool{inFlight: make([]int64, size)}
+	for i := 0; i < size; i++ {
+		conn, err := grpc.Dial(target, dialOptions...)
+		if err != nil {
+			pool.Close()
+			return nil, errors.Wrapf(err, "failed to connect to the gRPC: %s", target)
+		}
+		pool.conns = append(pool.conns, conn)
+	}
+	return pool, nil
+}
+
+func (p *ConnPool) Size() int {
+	return len(p.conns)
+}
+
+// Conn returns the first connection of the pool, for the APIs taking a *grpc.ClientConn
+func (p *ConnPool) Conn() *grpc.ClientConn {
+	return p.conns[0]
+}
+
+// InFlight returns the number of calls in flight on every connection
+func (p *ConnPool) InFlight() []int64 {
+	inFlight := make([]int64, len(p.inFlight))
+	for i := range p.inFlight {
+		inFlight[i] = atomic.LoadInt64(&p.inFlight[i])
+	}
+	return inFlight
+}
+
+func (p *ConnPool) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	i := p.pick()
+	defer atomic.AddInt64(&p.inFlight[i], -1)
+
+	return p.conns[i].Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream opens the stream on the least busy connection. The stream counts as in flight until it ends (it returns
+// an error or io.EOF) or its context is done
+func (p *ConnPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	i := p.pick()
+	stream, err := p.conns[i].NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		atomic.AddInt64(&p.inFlight[i], -1)
+		return nil, err
+	}
+
+	pooled := &pooledStream{ClientStream: stream, done: make(chan struct{})}
+	go func() {
+		select {
+		case <-ctx.Done():
+			pooled.finish()
+		case <-pooled.done:
+		}
+		atomic.AddInt64(&p.inFlight[i], -1)
+	}()
+	return pooled, nil
+}
+
+// Close closes all the connections and returns the first error
+func (p *ConnPool) Close() error {
+	var firstErr error
+	for _, conn := range p.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// pick returns the connection with the fewest calls in flight, starting the search at a rotating index so the ties
+// are spread over the connections, and counts the new call
+func (p *ConnPool) pick() int {
+	start := int(atomic.AddUint32(&p.next, 1)) % len(p.conns)
+	best := start
+	bestInFlight := atomic.LoadInt64(&p.inFlight[start])
+	for offset := 1; offset < len(p.conns) && bestInFlight > 0; offset++ {
+		i := (start + offset) % len(p.conns)
+		if inFlight := atomic.LoadInt64(&p.inFlight[i]); inFlight < bestInFlight {
+			best, bestInFlight = i, inFlight
+		}
+	}
+	atomic.AddInt64(&p.inFlight[best], 1)
+	return best
+}
+
+type pooledStream struct {
+	grpc.ClientStream
+	once sync.Once
+	done chan struct{}
+}
+
+func (s *pooledStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish()
+	}
+	return err
+}
+
+func (s *pooledStream) finish() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// FanOut runs query for every input with up to parallelism queries at a time (DefaultFanOutParallelism if zero), and
+// returns the results in the order of the inputs. The first error cancels the queries still running and is returned
+func FanOut[T any, R any](ctx context.Context, inputs []T, parallelism int, query func(ctx context.Context, input T) (R, error)) ([]R, error) {
+	if parallelism <= 0 {
+		parallelism = DefaultFanOutParallelism
+	}
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	results := make([]R, len(inputs))
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	slots := make(chan struct{}, parallelism)
+	for i, input := range inputs {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, input T) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			result, err := query(ctx, input)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancelFn()
+				})
+				return
+			}
+			results[i] = result
+		}(i, input)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
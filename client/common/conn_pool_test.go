@@ -0,0 +1,148 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const poolTestMarkets = 50
+
+// startOrderbookServer serves health checks taking delay, with a limit of concurrent streams per connection like the
+// load balancers in front of the indexer
+func startOrderbookServer(tb testing.TB, delay time.Duration, maxConcurrentStreams uint32) *bufconn.Listener {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.MaxConcurrentStreams(maxConcurrentStreams))
+	healthpb.RegisterHealthServer(server, &slowHealthServer{delay: delay})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	tb.Cleanup(server.Stop)
+	return listener
+}
+
+func dialTestPool(tb testing.TB, listener *bufconn.Listener, size int) *ConnPool {
+	pool, err := DialPool("bufnet", size,
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(tb, err)
+	tb.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+func queryAllMarkets(ctx context.Context, pool *ConnPool) ([]string, error) {
+	markets := make([]string, poolTestMarkets)
+	for i := range markets {
+		markets[i] = fmt.Sprintf("market-%d", i)
+	}
+	client := healthpb.NewHealthClient(pool)
+	return FanOut(ctx, markets, poolTestMarkets, func(ctx context.Context, market string) (string, error) {
+		res, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: market})
+		if err != nil {
+			return "", err
+		}
+		return market + " " + res.Status.String(), nil
+	})
+}
+
+func TestConnPoolSpreadsTheCallsOverTheConnections(t *testing.T) {
+	pool := dialTestPool(t, startOrderbookServer(t, 50*time.Millisecond, 100), 4)
+
+	var wg sync.WaitGroup
+	client := healthpb.NewHealthClient(pool)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+			assert.NoError(t, err)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	// the picks racing for the same connection can leave it one call ahead
+	var total int64
+	for _, inFlight := range pool.InFlight() {
+		assert.GreaterOrEqual(t, inFlight, int64(1))
+		total += inFlight
+	}
+	assert.Equal(t, int64(8), total)
+	wg.Wait()
+	assert.Equal(t, []int64{0, 0, 0, 0}, pool.InFlight())
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pool.InFlight()[0]+pool.InFlight()[1]+pool.InFlight()[2]+pool.InFlight()[3])
+	cancelFn()
+	assert.Eventually(t, func() bool {
+		inFlight := pool.InFlight()
+		return inFlight[0]+inFlight[1]+inFlight[2]+inFlight[3] == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestFanOutKeepsTheInputOrder(t *testing.T) {
+	pool := dialTestPool(t, startOrderbookServer(t, time.Millisecond, 100), 2)
+
+	results, err := queryAllMarkets(context.Background(), pool)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, poolTestMarkets)
+	for i, result := range results {
+		assert.Equal(t, fmt.Sprintf("market-%d SERVING", i), result)
+	}
+}
+
+func TestFanOutStopsAtTheFirstError(t *testing.T) {
+	var calls int
+	var mux sync.Mutex
+
+	_, err := FanOut(context.Background(), []int{1, 2, 3, 4, 5, 6}, 1, func(ctx context.Context, input int) (int, error) {
+		mux.Lock()
+		calls++
+		mux.Unlock()
+		if input == 2 {
+			return 0, errors.New("market not found")
+		}
+		return input, nil
+	})
+
+	assert.EqualError(t, err, "market not found")
+	assert.LessOrEqual(t, calls, 3)
+}
+
+func TestDialPoolRejectsInvalidSizes(t *testing.T) {
+	_, err := DialPool("bufnet", 0)
+	assert.Error(t, err)
+	assert.Error(t, OptionConnectionPoolSize(0)(DefaultClientOptions()))
+}
+
+// BenchmarkFanOut queries the orderbooks of 50 markets in parallel against a server allowing 10 concurrent streams per
+// connection: a single connection queues the calls, a pool of 5 connections runs them all at once
+func BenchmarkFanOut(b *testing.B) {
+	for _, size := range []int{1, 5} {
+		b.Run(fmt.Sprintf("connections=%d", size), func(b *testing.B) {
+			pool := dialTestPool(b, startOrderbookServer(b, 5*time.Millisecond, 10), size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := queryAllMarkets(context.Background(), pool); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
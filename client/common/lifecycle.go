@@ -0,0 +1,193 @@
+package common
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrLifecycleStarted = errors.New("lifecycle already started")
+	ErrLifecycleStopped = errors.New("lifecycle stopped")
+)
+
+type lifecycleTask struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+type lifecycleHook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Lifecycle runs the goroutines of long lived components (streams, watchers, trackers) with a shared context and
+// stops them in order. Stop cancels the context, waits until all the goroutines returned and then runs the stop hooks
+// in reverse registration order. Since the goroutines already exited when the hooks run, the hooks can safely close
+// the channels the goroutines write to, flush pending cancels and close the clients
+type Lifecycle struct {
+	logger log.Logger
+
+	mux      sync.Mutex
+	ctx      context.Context
+	cancelFn context.CancelFunc
+	started  bool
+	stopping bool
+	pending  []lifecycleTask
+	hooks    []lifecycleHook
+	running  map[string]int
+	wg       sync.WaitGroup
+
+	stopOnce sync.Once
+	stopErr  error
+	doneC    chan struct{}
+}
+
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{
+		logger:  log.WithField("module", "lifecycle"),
+		running: make(map[string]int),
+		doneC:   make(chan struct{}),
+	}
+}
+
+// Go runs the function in a goroutine with the lifecycle context. Functions registered before Start are started by
+// Start. The goroutine errors are logged; a function should return when its context is done
+func (l *Lifecycle) Go(name string, run func(ctx context.Context) error) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.stopping {
+		return ErrLifecycleStopped
+	}
+	task := lifecycleTask{name: name, run: run}
+	if !l.started {
+		l.pending = append(l.pending, task)
+		return nil
+	}
+
+	l.spawn(task)
+	return nil
+}
+
+// OnStop registers a hook run by Stop after all the goroutines exited. Hooks run in reverse registration order, so
+// the hooks registered first (e.g. closing the clients) run after the hooks that need them (e.g. cancelling orders)
+func (l *Lifecycle) OnStop(name string, stop func(ctx context.Context) error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	l.hooks = append(l.hooks, lifecycleHook{name: name, stop: stop})
+}
+
+// Start starts the registered goroutines. The lifecycle context is derived from ctx, so cancelling ctx also stops
+// the goroutines, but the stop hooks only run when Stop is called
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.stopping {
+		return ErrLifecycleStopped
+	}
+	if l.started {
+		return ErrLifecycleStarted
+	}
+
+	l.ctx, l.cancelFn = context.WithCancel(ctx)
+	l.started = true
+	for _, task := range l.pending {
+		l.spawn(task)
+	}
+	l.pending = nil
+
+	return nil
+}
+
+// Stop cancels the lifecycle context, waits for the goroutines and runs the stop hooks. If ctx is done before the
+// goroutines exit, the hooks still run and the names of the goroutines still running are included in the error.
+// Stop can be called more than once and always returns the result of the first call
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.stopOnce.Do(func() {
+		l.stopErr = l.stop(ctx)
+		close(l.doneC)
+	})
+	<-l.doneC
+	return l.stopErr
+}
+
+// Done is closed when Stop completed
+func (l *Lifecycle) Done() <-chan struct{} {
+	return l.doneC
+}
+
+func (l *Lifecycle) stop(ctx context.Context) error {
+	l.mux.Lock()
+	l.stopping = true
+	if l.cancelFn != nil {
+		l.cancelFn()
+	}
+	hooks := l.hooks
+	l.mux.Unlock()
+
+	var errs []string
+
+	waitC := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(waitC)
+	}()
+	select {
+	case <-waitC:
+	case <-ctx.Done():
+		errs = append(errs, "goroutines still running: "+strings.Join(l.runningNames(), ", "))
+	}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].stop(ctx); err != nil {
+			l.logger.WithField("hook", hooks[i].name).WithError(err).Warningln("stop hook failed")
+			errs = append(errs, hooks[i].name+": "+err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Errorf("lifecycle stop failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// spawn must be called holding the mutex
+func (l *Lifecycle) spawn(task lifecycleTask) {
+	l.running[task.name]++
+	l.wg.Add(1)
+
+	go func() {
+		defer func() {
+			l.mux.Lock()
+			l.running[task.name]--
+			if l.running[task.name] == 0 {
+				delete(l.running, task.name)
+			}
+			l.mux.Unlock()
+			l.wg.Done()
+		}()
+
+		if err := task.run(l.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			l.logger.WithField("task", task.name).WithError(err).Warningln("lifecycle goroutine failed")
+		}
+	}()
+}
+
+func (l *Lifecycle) runningNames() []string {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	names := make([]string, 0, len(l.running))
+	for name := range l.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
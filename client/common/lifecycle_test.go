@@ -0,0 +1,78 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleStopsGoroutinesBeforeHooks(t *testing.T) {
+	lifecycle := NewLifecycle()
+	eventCh := make(chan int)
+	var calls []string
+
+	producer := func(ctx context.Context) error {
+		for i := 0; ; i++ {
+			select {
+			case eventCh <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	assert.NoError(t, lifecycle.Go("producer", producer))
+	lifecycle.OnStop("close client", func(ctx context.Context) error {
+		calls = append(calls, "close client")
+		return nil
+	})
+	lifecycle.OnStop("cancel orders", func(ctx context.Context) error {
+		calls = append(calls, "cancel orders")
+		// the producer already exited, so the channel can be closed
+		close(eventCh)
+		return nil
+	})
+
+	assert.NoError(t, lifecycle.Start(context.Background()))
+	assert.ErrorIs(t, lifecycle.Start(context.Background()), ErrLifecycleStarted)
+	<-eventCh
+	assert.NoError(t, lifecycle.Go("second producer", producer))
+
+	assert.NoError(t, lifecycle.Stop(context.Background()))
+	assert.Equal(t, []string{"cancel orders", "close client"}, calls)
+	_, open := <-eventCh
+	assert.False(t, open)
+
+	// stopping again doesn't run the hooks twice
+	assert.NoError(t, lifecycle.Stop(context.Background()))
+	assert.Len(t, calls, 2)
+	assert.ErrorIs(t, lifecycle.Go("late", producer), ErrLifecycleStopped)
+	<-lifecycle.Done()
+}
+
+func TestLifecycleStopTimeout(t *testing.T) {
+	lifecycle := NewLifecycle()
+	releaseC := make(chan struct{})
+	defer close(releaseC)
+
+	assert.NoError(t, lifecycle.Go("stuck", func(ctx context.Context) error {
+		<-releaseC
+		return nil
+	}))
+	hookCalled := false
+	lifecycle.OnStop("hook", func(ctx context.Context) error {
+		hookCalled = true
+		return errors.New("flush failed")
+	})
+	assert.NoError(t, lifecycle.Start(context.Background()))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelFn()
+	err := lifecycle.Stop(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "goroutines still running: stuck")
+	assert.Contains(t, err.Error(), "hook: flush failed")
+	assert.True(t, hookCalled)
+}
@@ -144,6 +144,15 @@ func (assistant *DisabledCookieAssistant) Metadata(provider MetadataProvider) (s
 	return "", nil
 }
 
+const (
+	// DefaultExchangeAddress, DefaultOrderHashChainId, DefaultOrderHashDomainName and DefaultOrderHashDomainVersion are
+	// the EIP712 domain values of the order hashes in all the Injective networks
+	DefaultExchangeAddress        = "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	DefaultOrderHashChainId       = 888
+	DefaultOrderHashDomainName    = "Injective Protocol"
+	DefaultOrderHashDomainVersion = "2.0.0"
+)
+
 type Network struct {
 	LcdEndpoint             string
 	TmEndpoint              string
@@ -155,6 +164,13 @@ type Network struct {
 	ExchangeTlsCert         credentials.TransportCredentials
 	ExplorerTlsCert         credentials.TransportCredentials
 	ChainId                 string
+	// ExchangeAddress, OrderHashChainId, OrderHashDomainName and OrderHashDomainVersion are the verifying contract,
+	// chain ID, name and version of the EIP712 domain the exchange module uses to hash the orders. The values not set
+	// are the default ones
+	ExchangeAddress         string
+	OrderHashChainId        int64
+	OrderHashDomainName     string
+	OrderHashDomainVersion  string
 	Fee_denom               string
 	Name                    string
 	chainCookieAssistant    CookieAssistant
@@ -177,6 +193,25 @@ func (network *Network) ExplorerMetadata(provider MetadataProvider) (string, err
 func LoadNetwork(name string, node string) Network {
 	switch name {
 
+	case "local":
+		return Network{
+			LcdEndpoint:             "http://localhost:10337",
+			TmEndpoint:              "http://localhost:26657",
+			ChainGrpcEndpoint:       "tcp://localhost:9900",
+			ChainStreamGrpcEndpoint: "tcp://localhost:9999",
+			ExchangeGrpcEndpoint:    "tcp://localhost:9910",
+			ExplorerGrpcEndpoint:    "tcp://localhost:9911",
+			ChainId:                 "injective-1",
+			ExchangeAddress:         DefaultExchangeAddress,
+			OrderHashChainId:        DefaultOrderHashChainId,
+			OrderHashDomainName:     DefaultOrderHashDomainName,
+			OrderHashDomainVersion:  DefaultOrderHashDomainVersion,
+			Fee_denom:               "inj",
+			Name:                    "local",
+			chainCookieAssistant:    &DisabledCookieAssistant{},
+			exchangeCookieAssistant: &DisabledCookieAssistant{},
+			explorerCookieAssistant: &DisabledCookieAssistant{},
+		}
 	case "devnet-1":
 		return Network{
 			LcdEndpoint:             "https://devnet-1.lcd.injective.dev",
@@ -186,6 +221,10 @@ func LoadNetwork(name string, node string) Network {
 			ExchangeGrpcEndpoint:    "tcp://devnet-1.api.injective.dev:9910",
 			ExplorerGrpcEndpoint:    "tcp://devnet-1.api.injective.dev:9911",
 			ChainId:                 "injective-777",
+			ExchangeAddress:         DefaultExchangeAddress,
+			OrderHashChainId:        DefaultOrderHashChainId,
+			OrderHashDomainName:     DefaultOrderHashDomainName,
+			OrderHashDomainVersion:  DefaultOrderHashDomainVersion,
 			Fee_denom:               "inj",
 			Name:                    "devnet-1",
 			chainCookieAssistant:    &DisabledCookieAssistant{},
@@ -201,6 +240,10 @@ func LoadNetwork(name string, node string) Network {
 			ExchangeGrpcEndpoint:    "tcp://devnet.injective.dev:9910",
 			ExplorerGrpcEndpoint:    "tcp://devnet.api.injective.dev:9911",
 			ChainId:                 "injective-777",
+			ExchangeAddress:         DefaultExchangeAddress,
+			OrderHashChainId:        DefaultOrderHashChainId,
+			OrderHashDomainName:     DefaultOrderHashDomainName,
+			OrderHashDomainVersion:  DefaultOrderHashDomainVersion,
 			Fee_denom:               "inj",
 			Name:                    "devnet",
 			chainCookieAssistant:    &DisabledCookieAssistant{},
@@ -255,6 +298,10 @@ func LoadNetwork(name string, node string) Network {
 			ExplorerGrpcEndpoint:    explorerGrpcEndpoint,
 			ExplorerTlsCert:         explorerTlsCert,
 			ChainId:                 "injective-888",
+			ExchangeAddress:         DefaultExchangeAddress,
+			OrderHashChainId:        DefaultOrderHashChainId,
+			OrderHashDomainName:     DefaultOrderHashDomainName,
+			OrderHashDomainVersion:  DefaultOrderHashDomainVersion,
 			Fee_denom:               "inj",
 			Name:                    "testnet",
 			chainCookieAssistant:    chainCookieAssistant,
@@ -294,6 +341,10 @@ func LoadNetwork(name string, node string) Network {
 			ExplorerGrpcEndpoint:    explorerGrpcEndpoint,
 			ExplorerTlsCert:         explorerTlsCert,
 			ChainId:                 "injective-1",
+			ExchangeAddress:         DefaultExchangeAddress,
+			OrderHashChainId:        DefaultOrderHashChainId,
+			OrderHashDomainName:     DefaultOrderHashDomainName,
+			OrderHashDomainVersion:  DefaultOrderHashDomainVersion,
 			Fee_denom:               "inj",
 			Name:                    "mainnet",
 			chainCookieAssistant:    chainCookieAssistant,
@@ -309,6 +360,10 @@ func LoadNetwork(name string, node string) Network {
 // It can be used to setup a custom environment from scratch.
 func NewNetwork() Network {
 	return Network{
+		ExchangeAddress:         DefaultExchangeAddress,
+		OrderHashChainId:        DefaultOrderHashChainId,
+		OrderHashDomainName:     DefaultOrderHashDomainName,
+		OrderHashDomainVersion:  DefaultOrderHashDomainVersion,
 		chainCookieAssistant:    &DisabledCookieAssistant{},
 		exchangeCookieAssistant: &DisabledCookieAssistant{},
 		explorerCookieAssistant: &DisabledCookieAssistant{},
@@ -1,12 +1,20 @@
 package common
 
 import (
+	"context"
+
 	ctypes "github.com/InjectiveLabs/sdk-go/chain/types"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+	"github.com/InjectiveLabs/sdk-go/client/version"
 	log "github.com/InjectiveLabs/suplog"
 	"github.com/cosmos/cosmos-sdk/client/tx"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func init() {
@@ -22,12 +30,74 @@ type ClientOptions struct {
 	GasPrices string
 	TLSCert   credentials.TransportCredentials
 	TxFactory *tx.Factory
+	Timeouts  ClientTimeouts
+	Transport ClientTransport
+	// ConnectionPoolSize is the number of connections of the indexer clients (see ConnPool)
+	ConnectionPoolSize int
+	TxMemo             string
+	// PreBroadcastCheck is called with the msgs before broadcasting them, and the msgs are not broadcasted if it
+	// returns an error
+	PreBroadcastCheck func(msgs ...sdk.Msg) error
+	BroadcastJournal  BroadcastJournal
+	GasTelemetry      GasTelemetry
+	// GasPriceStrategy, when set, provides the gas prices of every tx instead of GasPrices
+	GasPriceStrategy GasPriceStrategy
+	// DryRun, when set, makes the client simulate the txs and report them to the recorder instead of broadcasting them
+	DryRun DryRunRecorder
+	// SkipChainIDValidation allows a client context chain ID different from the network one (for testing)
+	SkipChainIDValidation bool
+	// NodeVersionCheck, when set, runs the version handshake with the node when the chain client is created
+	NodeVersionCheck *version.CheckMode
+	// Clock of the client caches and of the IBC transfer timeouts
+	Clock clock.Clock
+	// TxSigner, when set, signs the txs instead of the keyring of the client context
+	TxSigner TxSigner
+	// Referrals are the fee recipients of the orders built with a referral name, by name (see OptionReferral)
+	Referrals map[string]string
+}
+
+// BroadcastJournal records every tx signed by the client before broadcasting it, and the tx result once known
+type BroadcastJournal interface {
+	RecordBroadcast(txHash string, txBytes []byte, sequence uint64) error
+	RecordResult(txHash string, res *sdk.TxResponse) error
+}
+
+// GasTelemetry compares the simulated gas of the txs broadcasted by the client with the gas they used, and tunes the
+// gas adjustment applied to the simulated gas of the next txs
+type GasTelemetry interface {
+	GasAdjustment(msgs []sdk.Msg, defaultAdjustment float64) float64
+	RecordGas(msgs []sdk.Msg, simulatedGas uint64, gasWanted uint64, gasUsed uint64, outOfGas bool)
+}
+
+// GasPriceStrategy provides the gas prices used to calculate the fee of the txs broadcasted by the client
+type GasPriceStrategy interface {
+	GasPrices(ctx context.Context) (sdk.DecCoins, error)
+}
+
+// DryRunRecorder receives the txs simulated by a client in dry run mode. The tx bytes are signed with the simulated
+// gas, and the simulation error is set when the chain would have rejected the tx
+type DryRunRecorder interface {
+	RecordDryRun(txBytes []byte, simulation *txtypes.SimulateResponse, simulationErr error)
+}
+
+// TxSigner signs the txs of the chain client in SIGN_MODE_DIRECT with a key kept outside of the process (for example
+// signer.Client, using a remote signing service)
+type TxSigner interface {
+	// PubKey returns the public key of the account, set in the signer infos of the txs
+	PubKey(ctx context.Context, address sdk.AccAddress) (cryptotypes.PubKey, error)
+	// SignDirect returns the signature of the SignDoc bytes by the account
+	SignDirect(ctx context.Context, address sdk.AccAddress, signDoc []byte) ([]byte, error)
 }
 
 type ClientOption func(opts *ClientOptions) error
 
 func DefaultClientOptions() *ClientOptions {
-	return &ClientOptions{}
+	return &ClientOptions{
+		Timeouts:           DefaultClientTimeouts(),
+		Transport:          DefaultClientTransport(),
+		ConnectionPoolSize: 1,
+		Clock:              clock.Real(),
+	}
 }
 
 func OptionGasPrices(gasPrices string) ClientOption {
@@ -61,3 +131,159 @@ func OptionTxFactory(txFactory *tx.Factory) ClientOption {
 		return nil
 	}
 }
+
+// OptionTxMemo sets the default memo of the transactions broadcasted by the client
+func OptionTxMemo(memo string) ClientOption {
+	return func(opts *ClientOptions) error {
+		opts.TxMemo = memo
+		return nil
+	}
+}
+
+// OptionReferral registers the fee recipient address of a referral (e.g. the relayer sharing the trading fees of the
+// orders), set on the orders built with the referral name in their SpotOrderData or DerivativeOrderData
+func OptionReferral(name string, feeRecipient string) ClientOption {
+	return func(opts *ClientOptions) error {
+		if name == "" {
+			return errors.New("the referral name is empty")
+		}
+		address, err := sdk.AccAddressFromBech32(feeRecipient)
+		if err != nil {
+			return errors.Wrapf(err, "invalid fee recipient %q of referral %s", feeRecipient, name)
+		}
+		if address.Equals(sdk.AccAddress(make([]byte, len(address)))) {
+			return errors.Errorf("the fee recipient of referral %s is the zero address", name)
+		}
+		if opts.Referrals == nil {
+			opts.Referrals = make(map[string]string)
+		}
+		opts.Referrals[name] = feeRecipient
+		return nil
+	}
+}
+
+// OptionPreBroadcastCheck sets a validation run before broadcasting msgs (for example chain.RiskGuard.CheckMsgs)
+func OptionPreBroadcastCheck(check func(msgs ...sdk.Msg) error) ClientOption {
+	return func(opts *ClientOptions) error {
+		opts.PreBroadcastCheck = check
+		return nil
+	}
+}
+
+// OptionBroadcastJournal sets the journal recording the txs broadcasted by the client (for example
+// chain.FileBroadcastJournal), to recover the state of the txs pending when the process stopped
+func OptionBroadcastJournal(journal BroadcastJournal) ClientOption {
+	return func(opts *ClientOptions) error {
+		opts.BroadcastJournal = journal
+		return nil
+	}
+}
+
+// OptionGasTelemetry sets the telemetry recording the simulated and used gas of the broadcasted txs (for example
+// chain.GasTelemetry), and adapting the gas adjustment of the txs to the recorded gas
+func OptionGasTelemetry(telemetry GasTelemetry) ClientOption {
+	return func(opts *ClientOptions) error {
+		opts.GasTelemetry = telemetry
+		return nil
+	}
+}
+
+// OptionGasPriceStrategy sets the strategy choosing the gas prices of every tx (for example
+// chain.PercentileGasPriceStrategy to outbid the recent txs during congestion)
+func OptionGasPriceStrategy(strategy GasPriceStrategy) ClientOption {
+	return func(opts *ClientOptions) error {
+		opts.GasPriceStrategy = strategy
+		return nil
+	}
+}
+
+// OptionDryRun makes the client simulate the txs instead of broadcasting them, and report them to the recorder (for
+// example chain.DryRunLog). The broadcast methods return the simulation results, and the account sequence is not
+// increased, so the strategies can run against a live network without sending any tx
+func OptionDryRun(recorder DryRunRecorder) ClientOption {
+	return func(opts *ClientOptions) error {
+		if recorder == nil {
+			return errors.New("the dry run recorder is nil")
+		}
+		opts.DryRun = recorder
+		return nil
+	}
+}
+
+// OptionClock sets the clock used by the chain client instead of the system time (e.g. a clock.Fake in tests)
+func OptionClock(c clock.Clock) ClientOption {
+	return func(opts *ClientOptions) error {
+		if c == nil {
+			return errors.New("the clock is nil")
+		}
+		opts.Clock = c
+		return nil
+	}
+}
+
+// OptionTxSigner makes the chain client sign the txs with the signer instead of the client context keyring. The client
+// context needs no keyring, but its FromAddress must be set to the address of the signer account
+func OptionTxSigner(signer TxSigner) ClientOption {
+	return func(opts *ClientOptions) error {
+		if signer == nil {
+			return errors.New("the tx signer is nil")
+		}
+		opts.TxSigner = signer
+		return nil
+	}
+}
+
+// OptionSkipChainIDValidation allows creating a chain client signing txs for a chain ID different from the network
+// preset one. It is meant for tests against local chains using a network preset
+func OptionSkipChainIDValidation() ClientOption {
+	return func(opts *ClientOptions) error {
+		opts.SkipChainIDValidation = true
+		return nil
+	}
+}
+
+// OptionNodeVersionCheck checks the application version of the node when the chain client is created, and logs a
+// warning (version.CheckWarn) or fails (version.CheckFail) if the SDK does not support it
+func OptionNodeVersionCheck(mode version.CheckMode) ClientOption {
+	return func(opts *ClientOptions) error {
+		opts.NodeVersionCheck = &mode
+		return nil
+	}
+}
+
+// OptionConnectionPoolSize spreads the calls of the indexer clients over size connections, for the applications
+// running many queries in parallel (e.g. the orderbooks of every market, see FanOut)
+func OptionConnectionPoolSize(size int) ClientOption {
+	return func(opts *ClientOptions) error {
+		if size < 1 {
+			return errors.Errorf("invalid connection pool size %d", size)
+		}
+		opts.ConnectionPoolSize = size
+		return nil
+	}
+}
+
+func OptionTimeouts(timeouts ClientTimeouts) ClientOption {
+	return func(opts *ClientOptions) error {
+		if timeouts.QueryTimeout < 0 || timeouts.StreamIdleTimeout < 0 || timeouts.BroadcastTimeout < 0 || timeouts.KeepaliveTime < 0 || timeouts.KeepaliveTimeout < 0 {
+			return errors.Errorf("invalid client timeouts %+v: values can not be negative", timeouts)
+		}
+
+		opts.Timeouts = timeouts
+		return nil
+	}
+}
+
+// GrpcDialOptions returns the dial options shared by all the SDK gRPC connections, built from the client options
+func GrpcDialOptions(opts *ClientOptions) []grpc.DialOption {
+	dialOptions := []grpc.DialOption{grpc.WithContextDialer(DialerFunc)}
+	if opts.TLSCert != nil {
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(opts.TLSCert))
+	} else {
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	dialOptions = append(dialOptions, TimeoutDialOptions(opts.Timeouts)...)
+	dialOptions = append(dialOptions, TransportDialOptions(opts.Transport)...)
+
+	return dialOptions
+}
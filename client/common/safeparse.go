@@ -0,0 +1,179 @@
+package common
+
+import (
+	"encoding/json"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	chaintypes "github.com/InjectiveLabs/sdk-go/chain/types"
+)
+
+// The SafeParse functions are meant to process untrusted input (e.g. data received from third party services).
+// They never panic, and reject inputs that are too large to be valid values.
+
+const (
+	// MaxDecimalStringLength is the longest numeric string accepted, enough for any 256-bit integer with 18 decimals
+	MaxDecimalStringLength = 100
+	// MaxSafeParseInputLength limits the size of the JSON and protobuf payloads
+	MaxSafeParseInputLength = 1 << 20
+)
+
+func recoverAsError(context string, err *error) {
+	if r := recover(); r != nil {
+		*err = errors.Errorf("%s: recovered from panic: %v", context, r)
+	}
+}
+
+func checkInputLength(length int, maxLength int) error {
+	if length > maxLength {
+		return errors.Errorf("input of %d bytes exceeds the maximum length %d", length, maxLength)
+	}
+	return nil
+}
+
+// SafeParseMsgResponse decodes the data of a tx response into the messages responses
+func SafeParseMsgResponse(data []byte) (messages []*chaintypes.TxResponseGenericMessage, err error) {
+	defer recoverAsError("failed to parse tx response data", &err)
+
+	if err := checkInputLength(len(data), MaxSafeParseInputLength); err != nil {
+		return nil, err
+	}
+
+	response := chaintypes.TxResponseData{}
+	if err := response.Unmarshal(data); err != nil {
+		return nil, errors.Wrap(err, "failed to parse tx response data")
+	}
+
+	return response.Messages, nil
+}
+
+// SafeParseHexBytes decodes a hex string with or without 0x prefix
+func SafeParseHexBytes(str string) ([]byte, error) {
+	if err := checkInputLength(len(str), MaxSafeParseInputLength); err != nil {
+		return nil, err
+	}
+	return HexToBytes(str)
+}
+
+// SafeParseHash decodes a 32 bytes hex string (order hashes, market IDs, subaccount IDs). Unlike common.HexToHash,
+// strings with a wrong length are rejected instead of being silently truncated or padded
+func SafeParseHash(str string) (ethcommon.Hash, error) {
+	if len(str) != 2*ethcommon.HashLength+2 || !strings.HasPrefix(str, "0x") {
+		return ethcommon.Hash{}, errors.Errorf("invalid hash %q: expected 0x followed by %d hex characters", str, 2*ethcommon.HashLength)
+	}
+
+	data, err := HexToBytes(str)
+	if err != nil {
+		return ethcommon.Hash{}, errors.Wrapf(err, "invalid hash %q", str)
+	}
+
+	return ethcommon.BytesToHash(data), nil
+}
+
+func validateDecimalString(str string) error {
+	if err := checkInputLength(len(str), MaxDecimalStringLength); err != nil {
+		return err
+	}
+	if str == "" {
+		return errors.New("empty decimal string")
+	}
+
+	digits := strings.TrimPrefix(str, "-")
+	integerPart, fractionalPart, hasDot := strings.Cut(digits, ".")
+	if integerPart == "" || (hasDot && fractionalPart == "") {
+		return errors.Errorf("invalid decimal %q", str)
+	}
+	for _, part := range []string{integerPart, fractionalPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return errors.Errorf("invalid decimal %q: only digits and a decimal point are allowed", str)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SafeParseDecimal parses a plain notation decimal (no exponent) into a decimal.Decimal
+func SafeParseDecimal(str string) (value decimal.Decimal, err error) {
+	defer recoverAsError("failed to parse decimal", &err)
+
+	if err := validateDecimalString(str); err != nil {
+		return decimal.Zero, err
+	}
+
+	return decimal.NewFromString(str)
+}
+
+// SafeParseDec parses a plain notation decimal into a sdk.Dec (at most 18 decimals)
+func SafeParseDec(str string) (value sdk.Dec, err error) {
+	defer recoverAsError("failed to parse decimal", &err)
+
+	if err := validateDecimalString(str); err != nil {
+		return sdk.Dec{}, err
+	}
+
+	return sdk.NewDecFromStr(str)
+}
+
+func validateOrderInfo(orderInfo exchangetypes.OrderInfo) error {
+	if orderInfo.Price.IsNil() || orderInfo.Quantity.IsNil() {
+		return errors.New("order price and quantity are required")
+	}
+	if !orderInfo.Price.IsPositive() || !orderInfo.Quantity.IsPositive() {
+		return errors.New("order price and quantity must be positive")
+	}
+	return nil
+}
+
+func unmarshalOrderJSON(data []byte, order interface{}) error {
+	if err := checkInputLength(len(data), MaxSafeParseInputLength); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, order)
+}
+
+// SafeParseSpotOrderJSON decodes a spot order from its JSON representation, checking the numeric fields are set
+func SafeParseSpotOrderJSON(data []byte) (order *exchangetypes.SpotOrder, err error) {
+	defer recoverAsError("failed to parse spot order", &err)
+
+	order = &exchangetypes.SpotOrder{}
+	if err := unmarshalOrderJSON(data, order); err != nil {
+		return nil, errors.Wrap(err, "failed to parse spot order")
+	}
+	if err := validateOrderInfo(order.OrderInfo); err != nil {
+		return nil, err
+	}
+	if order.TriggerPrice != nil && order.TriggerPrice.IsNil() {
+		order.TriggerPrice = nil
+	}
+
+	return order, nil
+}
+
+// SafeParseDerivativeOrderJSON decodes a derivative order from its JSON representation, checking the numeric
+// fields are set
+func SafeParseDerivativeOrderJSON(data []byte) (order *exchangetypes.DerivativeOrder, err error) {
+	defer recoverAsError("failed to parse derivative order", &err)
+
+	order = &exchangetypes.DerivativeOrder{}
+	if err := unmarshalOrderJSON(data, order); err != nil {
+		return nil, errors.Wrap(err, "failed to parse derivative order")
+	}
+	if err := validateOrderInfo(order.OrderInfo); err != nil {
+		return nil, err
+	}
+	if order.Margin.IsNil() || order.Margin.IsNegative() {
+		return nil, errors.New("derivative order margin is required and can not be negative")
+	}
+	if order.TriggerPrice != nil && order.TriggerPrice.IsNil() {
+		order.TriggerPrice = nil
+	}
+
+	return order, nil
+}
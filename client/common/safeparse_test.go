@@ -0,0 +1,123 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	chaintypes "github.com/InjectiveLabs/sdk-go/chain/types"
+)
+
+const testSpotOrderJSON = `{"market_id":"0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe","order_info":{"subaccount_id":"0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000","fee_recipient":"inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku","price":"0.000000000012","quantity":"1000"},"order_type":"BUY"}`
+
+func TestSafeParseDecimalRejectsExponentsAndLongInputs(t *testing.T) {
+	value, err := SafeParseDecimal("-12.5")
+	assert.NoError(t, err)
+	assert.Equal(t, "-12.5", value.String())
+
+	for _, input := range []string{"", "-", ".5", "5.", "1e9999999999", "0x10", "1.2.3", strings.Repeat("9", MaxDecimalStringLength+1)} {
+		_, err := SafeParseDecimal(input)
+		assert.Error(t, err, input)
+		_, err = SafeParseDec(input)
+		assert.Error(t, err, input)
+	}
+
+	_, err = SafeParseDec("0.0000000000000000001")
+	assert.Error(t, err)
+}
+
+func TestSafeParseHashRequiresExactLength(t *testing.T) {
+	hash, err := SafeParseHash("0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe", hash.Hex())
+
+	for _, input := range []string{"", "0x", "0x0611", "0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe00", "0xzz11780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"} {
+		_, err := SafeParseHash(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestSafeParseSpotOrderJSONRequiresNumericFields(t *testing.T) {
+	order, err := SafeParseSpotOrderJSON([]byte(testSpotOrderJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, "1000.000000000000000000", order.OrderInfo.Quantity.String())
+	assert.Nil(t, order.TriggerPrice)
+
+	_, err = SafeParseSpotOrderJSON([]byte(`{"market_id":"0x01","order_info":{"price":"1"}}`))
+	assert.Error(t, err)
+
+	_, err = SafeParseDerivativeOrderJSON([]byte(testSpotOrderJSON))
+	assert.Error(t, err)
+}
+
+func TestSafeParseMsgResponse(t *testing.T) {
+	response := chaintypes.TxResponseData{
+		Messages: []*chaintypes.TxResponseGenericMessage{{Header: "/injective.exchange.v1beta1.MsgCreateSpotLimitOrder", Data: []byte{1, 2}}},
+	}
+	data, err := response.Marshal()
+	assert.NoError(t, err)
+
+	messages, err := SafeParseMsgResponse(data)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, response.Messages[0].Header, messages[0].Header)
+
+	_, err = SafeParseMsgResponse([]byte{0xff, 0xff, 0xff})
+	assert.Error(t, err)
+}
+
+func TestTlsServerDomain(t *testing.T) {
+	assert.Equal(t, "sentry.chain.grpc.injective.network", tlsServerDomain("tcp://sentry.chain.grpc.injective.network:443"))
+	assert.Equal(t, "localhost", tlsServerDomain("localhost:9900"))
+	assert.Equal(t, "localhost", tlsServerDomain("localhost"))
+	assert.Equal(t, "", tlsServerDomain(""))
+}
+
+func FuzzSafeParseDecimal(f *testing.F) {
+	for _, seed := range []string{"0", "-1.5", "0.000000000000000001", "1e10", "", "..", "99999999999999999999999999999999999999999999999999999999999999999999999999999.999999999999999999"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		if value, err := SafeParseDecimal(input); err == nil {
+			_ = value.String()
+		}
+		if value, err := SafeParseDec(input); err == nil {
+			_ = value.String()
+		}
+	})
+}
+
+func FuzzSafeParseHexBytes(f *testing.F) {
+	for _, seed := range []string{"", "0x", "0x0", "0xff", "0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		_, _ = SafeParseHexBytes(input)
+		_, _ = SafeParseHash(input)
+	})
+}
+
+func FuzzSafeParseOrderJSON(f *testing.F) {
+	f.Add([]byte(testSpotOrderJSON))
+	f.Add([]byte(`{"order_info":{"price":"1","quantity":"1"},"margin":"1","trigger_price":null}`))
+	f.Add([]byte(`{"order_type":99}`))
+	f.Add([]byte(`null`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if order, err := SafeParseSpotOrderJSON(data); err == nil {
+			_ = order.OrderInfo.Price.Mul(order.OrderInfo.Quantity)
+		}
+		if order, err := SafeParseDerivativeOrderJSON(data); err == nil {
+			_ = order.Margin.Add(order.OrderInfo.Price)
+		}
+	})
+}
+
+func FuzzSafeParseMsgResponse(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x0a, 0x02, 0x0a, 0x00})
+	f.Add([]byte{0xff, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = SafeParseMsgResponse(data)
+	})
+}
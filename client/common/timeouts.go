@@ -0,0 +1,121 @@
+package common
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultQueryTimeout     = 30 * time.Second
+	DefaultBroadcastTimeout = 40 * time.Second
+	DefaultKeepaliveTime    = 5 * time.Minute
+	DefaultKeepaliveTimeout = 20 * time.Second
+)
+
+// ClientTimeouts groups the timeout policy shared by all the SDK clients.
+// A zero QueryTimeout, StreamIdleTimeout or KeepaliveTime disables the corresponding feature, while a zero
+// BroadcastTimeout falls back to DefaultBroadcastTimeout.
+type ClientTimeouts struct {
+	// QueryTimeout is applied to unary calls when the caller context has no deadline
+	QueryTimeout time.Duration
+	// StreamIdleTimeout ends a stream with codes.DeadlineExceeded when no message is received for that long. Streams of
+	// quiet markets can be idle for long periods, so it is disabled by default
+	StreamIdleTimeout time.Duration
+	// BroadcastTimeout limits how long a sync broadcast waits for the tx to be included in a block
+	BroadcastTimeout time.Duration
+	// KeepaliveTime is the interval of the keepalive pings sent on idle connections.
+	// Servers usually reject pings sent more often than every 5 minutes, so keep it large or disabled.
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is how long to wait for a ping ack before considering the connection dead
+	KeepaliveTimeout time.Duration
+}
+
+func DefaultClientTimeouts() ClientTimeouts {
+	return ClientTimeouts{
+		QueryTimeout:     DefaultQueryTimeout,
+		BroadcastTimeout: DefaultBroadcastTimeout,
+		KeepaliveTime:    DefaultKeepaliveTime,
+		KeepaliveTimeout: DefaultKeepaliveTimeout,
+	}
+}
+
+// TimeoutUnaryInterceptor returns a client interceptor that sets the given timeout on every unary call whose context
+// does not have a deadline already. Deadlines configured by the caller are always respected.
+func TimeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, hasDeadline := ctx.Deadline(); !hasDeadline && timeout > 0 {
+			var cancelFn context.CancelFunc
+			ctx, cancelFn = context.WithTimeout(ctx, timeout)
+			defer cancelFn()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// TimeoutStreamInterceptor returns a client interceptor that ends the streams waiting more than idleTimeout for the
+// next message. The caller context deadline and cancellation are propagated to the stream as they are
+func TimeoutStreamInterceptor(idleTimeout time.Duration) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if idleTimeout <= 0 || !desc.ServerStreams {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		streamCtx, cancelFn := context.WithCancel(ctx)
+		stream, err := streamer(streamCtx, desc, cc, method, opts...)
+		if err != nil {
+			cancelFn()
+			return nil, err
+		}
+		return &idleTimeoutStream{ClientStream: stream, idleTimeout: idleTimeout, cancelFn: cancelFn}, nil
+	}
+}
+
+type idleTimeoutStream struct {
+	grpc.ClientStream
+	idleTimeout time.Duration
+	cancelFn    context.CancelFunc
+	idle        int32
+}
+
+func (s *idleTimeoutStream) RecvMsg(m interface{}) error {
+	timer := time.AfterFunc(s.idleTimeout, func() {
+		atomic.StoreInt32(&s.idle, 1)
+		s.cancelFn()
+	})
+	err := s.ClientStream.RecvMsg(m)
+	timer.Stop()
+
+	if err != nil {
+		s.cancelFn()
+		if atomic.LoadInt32(&s.idle) == 1 {
+			return status.Errorf(codes.DeadlineExceeded, "no message received in the stream for %s", s.idleTimeout)
+		}
+	}
+	return err
+}
+
+// TimeoutDialOptions translates the timeout policy into gRPC dial options.
+// Streams are not bound by QueryTimeout: they stop when the caller context is done or after StreamIdleTimeout
+// without messages, and rely on the keepalive settings to detect dead connections.
+func TimeoutDialOptions(timeouts ClientTimeouts) []grpc.DialOption {
+	dialOptions := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(TimeoutUnaryInterceptor(timeouts.QueryTimeout)),
+		grpc.WithChainStreamInterceptor(TimeoutStreamInterceptor(timeouts.StreamIdleTimeout)),
+	}
+
+	if timeouts.KeepaliveTime > 0 {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                timeouts.KeepaliveTime,
+			Timeout:             timeouts.KeepaliveTimeout,
+			PermitWithoutStream: false,
+		}))
+	}
+
+	return dialOptions
+}
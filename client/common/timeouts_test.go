@@ -0,0 +1,144 @@
+package common
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type slowHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	delay time.Duration
+}
+
+func (s *slowHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	select {
+	case <-time.After(s.delay):
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Watch sends the serving status once and then blocks until the stream is done
+func (s *slowHealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func startSlowServer(t *testing.T, delay time.Duration, timeouts ClientTimeouts) healthpb.HealthClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, &slowHealthServer{delay: delay})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	dialOptions = append(dialOptions, TimeoutDialOptions(timeouts)...)
+
+	conn, err := grpc.Dial("bufnet", dialOptions...)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestDefaultQueryTimeoutIsAppliedToSlowServer(t *testing.T) {
+	timeouts := DefaultClientTimeouts()
+	timeouts.QueryTimeout = 50 * time.Millisecond
+	client := startSlowServer(t, 2*time.Second, timeouts)
+
+	start := time.Now()
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestCallerDeadlineIsRespected(t *testing.T) {
+	timeouts := DefaultClientTimeouts()
+	timeouts.QueryTimeout = 50 * time.Millisecond
+	client := startSlowServer(t, 200*time.Millisecond, timeouts)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFn()
+	res, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+}
+
+func TestZeroQueryTimeoutDisablesDefaultDeadline(t *testing.T) {
+	timeouts := DefaultClientTimeouts()
+	timeouts.QueryTimeout = 0
+	client := startSlowServer(t, 100*time.Millisecond, timeouts)
+
+	res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+}
+
+func TestOptionTimeoutsRejectsNegativeValues(t *testing.T) {
+	opts := DefaultClientOptions()
+	timeouts := DefaultClientTimeouts()
+	timeouts.BroadcastTimeout = -time.Second
+
+	err := OptionTimeouts(timeouts)(opts)
+
+	assert.Error(t, err)
+	assert.Equal(t, DefaultBroadcastTimeout, opts.Timeouts.BroadcastTimeout)
+}
+
+func TestStreamIdleTimeoutEndsStalledStreams(t *testing.T) {
+	timeouts := DefaultClientTimeouts()
+	timeouts.StreamIdleTimeout = 100 * time.Millisecond
+	client := startSlowServer(t, 0, timeouts)
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	res, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+
+	start := time.Now()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestCallerDeadlineIsPropagatedToStreams(t *testing.T) {
+	timeouts := DefaultClientTimeouts()
+	client := startSlowServer(t, 0, timeouts)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelFn()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, DefaultKeepaliveTime, timeouts.KeepaliveTime)
+}
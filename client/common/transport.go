@@ -0,0 +1,162 @@
+package common
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+const (
+	CompressionNone = ""
+	CompressionGzip = gzip.Name
+	CompressionZstd = "zstd"
+
+	// DefaultMaxRecvMsgSize allows the orderbook and market snapshots of the exchange endpoints, which go over the
+	// 4 MiB gRPC default on mainnet
+	DefaultMaxRecvMsgSize = 64 << 20
+)
+
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// ClientTransport groups the message size, compression and flow control settings of the SDK gRPC connections.
+// Zero values keep the gRPC defaults
+type ClientTransport struct {
+	// Compression compresses the requests (CompressionGzip or CompressionZstd), and the server compresses its
+	// responses with the same algorithm. The server must support it, so it is disabled by default
+	Compression string
+	// MaxRecvMsgSize is the largest response message accepted, in bytes
+	MaxRecvMsgSize int
+	// MaxSendMsgSize is the largest request message sent, in bytes
+	MaxSendMsgSize int
+	// InitialWindowSize is the flow control window of every stream, in bytes. Zero keeps the window sized by the
+	// gRPC bandwidth estimation, which suits most connections; set it for high latency links with large streams
+	InitialWindowSize int32
+	// InitialConnWindowSize is the flow control window of the connection, shared by its streams
+	InitialConnWindowSize int32
+}
+
+func DefaultClientTransport() ClientTransport {
+	return ClientTransport{
+		MaxRecvMsgSize: DefaultMaxRecvMsgSize,
+	}
+}
+
+// OptionTransport sets the message size, compression and flow control settings of the client connections
+func OptionTransport(transport ClientTransport) ClientOption {
+	return func(opts *ClientOptions) error {
+		if transport.MaxRecvMsgSize < 0 || transport.MaxSendMsgSize < 0 {
+			return errors.Errorf("invalid client transport %+v: message sizes can not be negative", transport)
+		}
+		// gRPC ignores windows below 64 KiB
+		const minWindowSize = 64 << 10
+		if (transport.InitialWindowSize != 0 && transport.InitialWindowSize < minWindowSize) || (transport.InitialConnWindowSize != 0 && transport.InitialConnWindowSize < minWindowSize) {
+			return errors.Errorf("invalid client transport %+v: flow control windows are at least %d bytes", transport, minWindowSize)
+		}
+		if transport.Compression != CompressionNone && encoding.GetCompressor(transport.Compression) == nil {
+			return errors.Errorf("unsupported compression %q", transport.Compression)
+		}
+
+		opts.Transport = transport
+		return nil
+	}
+}
+
+// TransportDialOptions translates the transport settings into gRPC dial options
+func TransportDialOptions(transport ClientTransport) []grpc.DialOption {
+	var callOptions []grpc.CallOption
+	if transport.Compression != CompressionNone {
+		callOptions = append(callOptions, grpc.UseCompressor(transport.Compression))
+	}
+	if transport.MaxRecvMsgSize > 0 {
+		callOptions = append(callOptions, grpc.MaxCallRecvMsgSize(transport.MaxRecvMsgSize))
+	}
+	if transport.MaxSendMsgSize > 0 {
+		callOptions = append(callOptions, grpc.MaxCallSendMsgSize(transport.MaxSendMsgSize))
+	}
+
+	var dialOptions []grpc.DialOption
+	if len(callOptions) > 0 {
+		dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(callOptions...))
+	}
+	if transport.InitialWindowSize > 0 {
+		dialOptions = append(dialOptions, grpc.WithInitialWindowSize(transport.InitialWindowSize))
+	}
+	if transport.InitialConnWindowSize > 0 {
+		dialOptions = append(dialOptions, grpc.WithInitialConnWindowSize(transport.InitialConnWindowSize))
+	}
+	return dialOptions
+}
+
+// zstdCompressor is the gRPC zstd encoding. The encoders and decoders are pooled, as they are expensive to create
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+func (c *zstdCompressor) Name() string {
+	return CompressionZstd
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	encoder, ok := c.encoders.Get().(*zstd.Encoder)
+	if !ok {
+		var err error
+		if encoder, err = zstd.NewWriter(w, zstd.WithEncoderConcurrency(1)); err != nil {
+			return nil, err
+		}
+	} else {
+		encoder.Reset(w)
+	}
+	return &zstdWriter{Encoder: encoder, pool: &c.encoders}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	decoder, ok := c.decoders.Get().(*zstd.Decoder)
+	if !ok {
+		var err error
+		if decoder, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1)); err != nil {
+			return nil, err
+		}
+	} else if err := decoder.Reset(r); err != nil {
+		c.decoders.Put(decoder)
+		return nil, err
+	}
+	return &zstdReader{decoder: decoder, pool: &c.decoders}, nil
+}
+
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+	err := w.Encoder.Close()
+	w.pool.Put(w.Encoder)
+	return err
+}
+
+// zstdReader returns the decoder to the pool once the message is read
+type zstdReader struct {
+	decoder *zstd.Decoder
+	pool    *sync.Pool
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+	if r.decoder == nil {
+		return 0, io.EOF
+	}
+	n, err := r.decoder.Read(p)
+	if err == io.EOF {
+		_ = r.decoder.Reset(nil)
+		r.pool.Put(r.decoder)
+		r.decoder = nil
+	}
+	return n, err
+}
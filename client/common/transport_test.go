@@ -0,0 +1,139 @@
+package common
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const echoMethod = "/test.Echo/Echo"
+
+// payloadStats records the size of the request payloads received by the server
+type payloadStats struct {
+	mux      sync.Mutex
+	payloads []*stats.InPayload
+}
+
+func (s *payloadStats) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context   { return ctx }
+func (s *payloadStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+func (s *payloadStats) HandleConn(context.Context, stats.ConnStats)                       {}
+
+func (s *payloadStats) HandleRPC(_ context.Context, rpcStats stats.RPCStats) {
+	if payload, ok := rpcStats.(*stats.InPayload); ok {
+		s.mux.Lock()
+		s.payloads = append(s.payloads, payload)
+		s.mux.Unlock()
+	}
+}
+
+func (s *payloadStats) last() *stats.InPayload {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.payloads[len(s.payloads)-1]
+}
+
+// startEchoServer serves a method sending back the health check request it receives
+func startEchoServer(t *testing.T, transport ClientTransport) (*grpc.ClientConn, *payloadStats) {
+	listener := bufconn.Listen(1024 * 1024)
+	serverStats := &payloadStats{}
+	server := grpc.NewServer(
+		grpc.StatsHandler(serverStats),
+		grpc.MaxRecvMsgSize(16<<20),
+		grpc.MaxSendMsgSize(16<<20),
+		grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+			var req healthpb.HealthCheckRequest
+			if err := stream.RecvMsg(&req); err != nil {
+				return err
+			}
+			return stream.SendMsg(&req)
+		}),
+	)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	dialOptions = append(dialOptions, TransportDialOptions(transport)...)
+
+	conn, err := grpc.Dial("bufnet", dialOptions...)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, serverStats
+}
+
+func echo(conn *grpc.ClientConn, size int) error {
+	req := &healthpb.HealthCheckRequest{Service: strings.Repeat("orderbook ", size/10)}
+	var res healthpb.HealthCheckRequest
+	if err := conn.Invoke(context.Background(), echoMethod, req, &res); err != nil {
+		return err
+	}
+	if res.Service != req.Service {
+		return status.Error(codes.DataLoss, "the echo response differs from the request")
+	}
+	return nil
+}
+
+func TestTransportCompression(t *testing.T) {
+	for _, compression := range []string{CompressionGzip, CompressionZstd} {
+		transport := DefaultClientTransport()
+		transport.Compression = compression
+		conn, serverStats := startEchoServer(t, transport)
+
+		// the pooled encoders and decoders are reused across calls
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, echo(conn, 1<<20), compression)
+		}
+
+		payload := serverStats.last()
+		assert.Greater(t, payload.Length, 1<<19, compression)
+		assert.Less(t, payload.CompressedLength, payload.Length/10, compression)
+	}
+
+	conn, serverStats := startEchoServer(t, DefaultClientTransport())
+	assert.NoError(t, echo(conn, 1<<20))
+	assert.Equal(t, serverStats.last().Length, serverStats.last().CompressedLength)
+}
+
+func TestTransportMessageSizes(t *testing.T) {
+	conn, _ := startEchoServer(t, ClientTransport{})
+	err := echo(conn, 5<<20)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	conn, _ = startEchoServer(t, DefaultClientTransport())
+	assert.NoError(t, echo(conn, 5<<20))
+
+	conn, _ = startEchoServer(t, ClientTransport{MaxSendMsgSize: 1 << 20})
+	err = echo(conn, 2<<20)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestOptionTransportValidation(t *testing.T) {
+	opts := DefaultClientOptions()
+	assert.Equal(t, DefaultMaxRecvMsgSize, opts.Transport.MaxRecvMsgSize)
+
+	assert.Error(t, OptionTransport(ClientTransport{MaxRecvMsgSize: -1})(opts))
+	assert.Error(t, OptionTransport(ClientTransport{InitialWindowSize: 1024})(opts))
+	assert.Error(t, OptionTransport(ClientTransport{Compression: "brotli"})(opts))
+
+	transport := ClientTransport{Compression: CompressionZstd, InitialWindowSize: 1 << 20, InitialConnWindowSize: 4 << 20}
+	assert.NoError(t, OptionTransport(transport)(opts))
+	assert.Equal(t, transport, opts.Transport)
+}
@@ -41,8 +41,7 @@ func LoadTlsCert(path string, serverName string) credentials.TransportCredential
 		fmt.Println(err, "failed to add server CA's certificate")
 		return nil
 	}
-	// get domain from tcp://domain:port
-	domain := strings.Split(serverName, ":")[1][2:]
+	domain := tlsServerDomain(serverName)
 	config := &tls.Config{
 		RootCAs:    certPool,
 		ServerName: domain,
@@ -50,13 +49,25 @@ func LoadTlsCert(path string, serverName string) credentials.TransportCredential
 	return credentials.NewTLS(config)
 }
 
+// tlsServerDomain gets the domain from tcp://domain:port, also accepting domain:port and domain
+func tlsServerDomain(serverName string) string {
+	domain := serverName
+	if _, withoutScheme, found := strings.Cut(domain, "://"); found {
+		domain = withoutScheme
+	}
+	if host, _, found := strings.Cut(domain, ":"); found {
+		domain = host
+	}
+	return domain
+}
+
+// MsgResponse decodes the tx response data and panics if it is invalid. Use SafeParseMsgResponse for untrusted data
 func MsgResponse(data []byte) []*chaintypes.TxResponseGenericMessage {
-	response := chaintypes.TxResponseData{}
-	err := response.Unmarshal(data)
+	messages, err := SafeParseMsgResponse(data)
 	if err != nil {
 		panic(err)
 	}
-	return response.Messages
+	return messages
 }
 
 func RemoveExtraDecimals(value decimal.Decimal, decimalsToRemove int32) decimal.Decimal {
@@ -0,0 +1,366 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the config file values
+const EnvPrefix = "INJECTIVE_"
+
+// CustomNetwork is the network name used to configure all the endpoints explicitly
+const CustomNetwork = "custom"
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// validNetworkNodes are the nodes accepted by common.LoadNetwork for each network (nil accepts any node)
+var validNetworkNodes = map[string][]string{
+	"local":    nil,
+	"devnet":   nil,
+	"devnet-1": nil,
+	"testnet":  {"lb", "sentry"},
+	"mainnet":  {"lb"},
+}
+
+type Config struct {
+	Network  NetworkConfig  `yaml:"network" toml:"network"`
+	Keyring  KeyringConfig  `yaml:"keyring" toml:"keyring"`
+	Fees     FeesConfig     `yaml:"fees" toml:"fees"`
+	Timeouts TimeoutsConfig `yaml:"timeouts" toml:"timeouts"`
+	TxMemo   string         `yaml:"tx_memo" toml:"tx_memo"`
+}
+
+// NetworkConfig selects one of the networks known by common.LoadNetwork. The endpoints and the chain id are optional
+// overrides of the network values, and are all required for the custom network
+type NetworkConfig struct {
+	Name                    string `yaml:"name" toml:"name"`
+	Node                    string `yaml:"node" toml:"node"`
+	ChainId                 string `yaml:"chain_id" toml:"chain_id"`
+	FeeDenom                string `yaml:"fee_denom" toml:"fee_denom"`
+	LcdEndpoint             string `yaml:"lcd_endpoint" toml:"lcd_endpoint"`
+	TmEndpoint              string `yaml:"tm_endpoint" toml:"tm_endpoint"`
+	ChainGrpcEndpoint       string `yaml:"chain_grpc_endpoint" toml:"chain_grpc_endpoint"`
+	ChainStreamGrpcEndpoint string `yaml:"chain_stream_grpc_endpoint" toml:"chain_stream_grpc_endpoint"`
+	ExchangeGrpcEndpoint    string `yaml:"exchange_grpc_endpoint" toml:"exchange_grpc_endpoint"`
+	ExplorerGrpcEndpoint    string `yaml:"explorer_grpc_endpoint" toml:"explorer_grpc_endpoint"`
+}
+
+// KeyringConfig references the key used to sign txs. Secrets are not stored in the config file: the passphrase and the
+// private key are read from the environment variables named by PassphraseEnv and PrivateKeyEnv
+type KeyringConfig struct {
+	Dir           string `yaml:"dir" toml:"dir"`
+	AppName       string `yaml:"app_name" toml:"app_name"`
+	Backend       string `yaml:"backend" toml:"backend"`
+	From          string `yaml:"from" toml:"from"`
+	PassphraseEnv string `yaml:"passphrase_env" toml:"passphrase_env"`
+	PrivateKeyEnv string `yaml:"private_key_env" toml:"private_key_env"`
+	UseLedger     bool   `yaml:"use_ledger" toml:"use_ledger"`
+}
+
+type FeesConfig struct {
+	GasPrices string `yaml:"gas_prices" toml:"gas_prices"`
+	// GasAdjustment multiplies the simulated gas. Zero keeps the client default
+	GasAdjustment float64 `yaml:"gas_adjustment" toml:"gas_adjustment"`
+}
+
+// TimeoutsConfig values are durations in time.ParseDuration format (e.g. "30s"). Empty values keep the defaults
+type TimeoutsConfig struct {
+	Query            string `yaml:"query" toml:"query"`
+	Broadcast        string `yaml:"broadcast" toml:"broadcast"`
+	KeepaliveTime    string `yaml:"keepalive_time" toml:"keepalive_time"`
+	KeepaliveTimeout string `yaml:"keepalive_timeout" toml:"keepalive_timeout"`
+}
+
+// Load reads the config file (the format is selected by the .yaml, .yml or .toml extension), applies the environment
+// variable overrides and validates the result
+func Load(path string) (*Config, error) {
+	var format Format
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	case ".toml":
+		format = FormatTOML
+	default:
+		return nil, errors.Errorf("unsupported config file extension %s", filepath.Ext(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config file %s", path)
+	}
+
+	return Parse(data, format, os.LookupEnv)
+}
+
+// Parse decodes the config, applies the overrides found with lookupEnv (usually os.LookupEnv) and validates the result
+func Parse(data []byte, format Format, lookupEnv func(key string) (string, bool)) (*Config, error) {
+	cfg := &Config{}
+
+	var err error
+	switch format {
+	case FormatYAML:
+		err = yaml.UnmarshalStrict(data, cfg)
+	case FormatTOML:
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return nil, errors.Errorf("unsupported config format %s", format)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s config", format)
+	}
+
+	if lookupEnv != nil {
+		if err := cfg.applyEnv(lookupEnv); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the config values, so building the network and the client options can't fail later
+func (c *Config) Validate() error {
+	if c.Network.Name == CustomNetwork {
+		if c.Network.ChainId == "" || c.Network.ChainGrpcEndpoint == "" {
+			return errors.New("the custom network requires the chain id and the chain gRPC endpoint")
+		}
+	} else {
+		validNodes, found := validNetworkNodes[c.Network.Name]
+		if !found {
+			return errors.Errorf("unknown network %q", c.Network.Name)
+		}
+		if validNodes != nil && !containsString(validNodes, c.Network.Node) {
+			return errors.Errorf("invalid node %q for network %s", c.Network.Node, c.Network.Name)
+		}
+	}
+
+	if c.Fees.GasPrices != "" {
+		if _, err := sdk.ParseDecCoins(c.Fees.GasPrices); err != nil {
+			return errors.Wrapf(err, "invalid gas prices %s", c.Fees.GasPrices)
+		}
+	}
+	if c.Fees.GasAdjustment < 0 {
+		return errors.Errorf("invalid gas adjustment %v: it can not be negative", c.Fees.GasAdjustment)
+	}
+
+	if c.Keyring.UseLedger && c.Keyring.PrivateKeyEnv != "" {
+		return errors.New("cannot combine ledger and private key options")
+	}
+
+	if _, err := c.ClientTimeouts(); err != nil {
+		return err
+	}
+	if uint64(len(c.TxMemo)) > authtypes.DefaultMaxMemoCharacters {
+		return errors.Errorf("tx memo is longer than %d characters", authtypes.DefaultMaxMemoCharacters)
+	}
+
+	return nil
+}
+
+// LoadNetwork returns the configured network with the endpoint overrides applied
+func (c *Config) LoadNetwork() common.Network {
+	var network common.Network
+	if c.Network.Name == CustomNetwork {
+		network = common.NewNetwork()
+		network.Name = CustomNetwork
+		network.Fee_denom = "inj"
+	} else {
+		network = common.LoadNetwork(c.Network.Name, c.Network.Node)
+	}
+
+	overrides := []struct {
+		value  string
+		target *string
+	}{
+		{c.Network.ChainId, &network.ChainId},
+		{c.Network.FeeDenom, &network.Fee_denom},
+		{c.Network.LcdEndpoint, &network.LcdEndpoint},
+		{c.Network.TmEndpoint, &network.TmEndpoint},
+		{c.Network.ChainGrpcEndpoint, &network.ChainGrpcEndpoint},
+		{c.Network.ChainStreamGrpcEndpoint, &network.ChainStreamGrpcEndpoint},
+		{c.Network.ExchangeGrpcEndpoint, &network.ExchangeGrpcEndpoint},
+		{c.Network.ExplorerGrpcEndpoint, &network.ExplorerGrpcEndpoint},
+	}
+	for _, override := range overrides {
+		if override.value != "" {
+			*override.target = override.value
+		}
+	}
+
+	return network
+}
+
+// ClientTimeouts returns the default client timeouts with the configured values
+func (c *Config) ClientTimeouts() (common.ClientTimeouts, error) {
+	timeouts := common.DefaultClientTimeouts()
+
+	values := []struct {
+		name   string
+		value  string
+		target *time.Duration
+	}{
+		{"query", c.Timeouts.Query, &timeouts.QueryTimeout},
+		{"broadcast", c.Timeouts.Broadcast, &timeouts.BroadcastTimeout},
+		{"keepalive_time", c.Timeouts.KeepaliveTime, &timeouts.KeepaliveTime},
+		{"keepalive_timeout", c.Timeouts.KeepaliveTimeout, &timeouts.KeepaliveTimeout},
+	}
+	for _, value := range values {
+		if value.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value.value)
+		if err != nil {
+			return timeouts, errors.Wrapf(err, "invalid %s timeout %s", value.name, value.value)
+		}
+		if duration < 0 {
+			return timeouts, errors.Errorf("invalid %s timeout %s: it can not be negative", value.name, value.value)
+		}
+		*value.target = duration
+	}
+
+	return timeouts, nil
+}
+
+// InitKeyring initializes the configured keyring and returns the address of the signing key
+func (c *Config) InitKeyring() (sdk.AccAddress, keyring.Keyring, error) {
+	var passphrase, privateKey string
+	if c.Keyring.PassphraseEnv != "" {
+		passphrase = os.Getenv(c.Keyring.PassphraseEnv)
+	}
+	if c.Keyring.PrivateKeyEnv != "" {
+		privateKey = os.Getenv(c.Keyring.PrivateKeyEnv)
+	}
+
+	return chain.InitCosmosKeyring(
+		c.Keyring.Dir,
+		c.Keyring.AppName,
+		c.Keyring.Backend,
+		c.Keyring.From,
+		passphrase,
+		privateKey,
+		c.Keyring.UseLedger,
+	)
+}
+
+// ClientContext initializes the keyring and returns a client context for the configured network, to be used with
+// chain.NewChainClient
+func (c *Config) ClientContext() (client.Context, error) {
+	senderAddress, cosmosKeyring, err := c.InitKeyring()
+	if err != nil {
+		return client.Context{}, errors.Wrap(err, "failed to initialize the keyring")
+	}
+
+	network := c.LoadNetwork()
+	clientCtx, err := chain.NewClientContext(network.ChainId, senderAddress.String(), cosmosKeyring)
+	if err != nil {
+		return client.Context{}, errors.Wrap(err, "failed to create the client context")
+	}
+
+	return clientCtx.WithNodeURI(network.TmEndpoint), nil
+}
+
+// ClientOptions returns the options to create the clients with the configured fees, timeouts and memo. The client
+// context is only used to build the tx factory when the gas adjustment is configured
+func (c *Config) ClientOptions(clientCtx client.Context) ([]common.ClientOption, error) {
+	timeouts, err := c.ClientTimeouts()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []common.ClientOption{common.OptionTimeouts(timeouts)}
+	if c.Fees.GasPrices != "" {
+		options = append(options, common.OptionGasPrices(c.Fees.GasPrices))
+	}
+	if c.Fees.GasAdjustment > 0 {
+		txFactory := chain.NewTxFactory(clientCtx).WithGasAdjustment(c.Fees.GasAdjustment)
+		if c.Fees.GasPrices != "" {
+			txFactory = txFactory.WithGasPrices(c.Fees.GasPrices)
+		}
+		options = append(options, common.OptionTxFactory(&txFactory))
+	}
+	if c.TxMemo != "" {
+		options = append(options, common.OptionTxMemo(c.TxMemo))
+	}
+
+	return options, nil
+}
+
+func (c *Config) applyEnv(lookupEnv func(key string) (string, bool)) error {
+	stringValues := map[string]*string{
+		"NETWORK":                    &c.Network.Name,
+		"NODE":                       &c.Network.Node,
+		"CHAIN_ID":                   &c.Network.ChainId,
+		"FEE_DENOM":                  &c.Network.FeeDenom,
+		"LCD_ENDPOINT":               &c.Network.LcdEndpoint,
+		"TM_ENDPOINT":                &c.Network.TmEndpoint,
+		"CHAIN_GRPC_ENDPOINT":        &c.Network.ChainGrpcEndpoint,
+		"CHAIN_STREAM_GRPC_ENDPOINT": &c.Network.ChainStreamGrpcEndpoint,
+		"EXCHANGE_GRPC_ENDPOINT":     &c.Network.ExchangeGrpcEndpoint,
+		"EXPLORER_GRPC_ENDPOINT":     &c.Network.ExplorerGrpcEndpoint,
+		"KEYRING_DIR":                &c.Keyring.Dir,
+		"KEYRING_APP_NAME":           &c.Keyring.AppName,
+		"KEYRING_BACKEND":            &c.Keyring.Backend,
+		"KEYRING_FROM":               &c.Keyring.From,
+		"KEYRING_PASSPHRASE_ENV":     &c.Keyring.PassphraseEnv,
+		"KEYRING_PRIVATE_KEY_ENV":    &c.Keyring.PrivateKeyEnv,
+		"GAS_PRICES":                 &c.Fees.GasPrices,
+		"QUERY_TIMEOUT":              &c.Timeouts.Query,
+		"BROADCAST_TIMEOUT":          &c.Timeouts.Broadcast,
+		"KEEPALIVE_TIME":             &c.Timeouts.KeepaliveTime,
+		"KEEPALIVE_TIMEOUT":          &c.Timeouts.KeepaliveTimeout,
+		"TX_MEMO":                    &c.TxMemo,
+	}
+	for name, target := range stringValues {
+		if value, found := lookupEnv(EnvPrefix + name); found {
+			*target = value
+		}
+	}
+
+	if value, found := lookupEnv(EnvPrefix + "GAS_ADJUSTMENT"); found {
+		gasAdjustment, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %sGAS_ADJUSTMENT %s", EnvPrefix, value)
+		}
+		c.Fees.GasAdjustment = gasAdjustment
+	}
+	if value, found := lookupEnv(EnvPrefix + "KEYRING_USE_LEDGER"); found {
+		useLedger, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %sKEYRING_USE_LEDGER %s", EnvPrefix, value)
+		}
+		c.Keyring.UseLedger = useLedger
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+)
+
+const testYAMLConfig = `
+network:
+  name: testnet
+  node: lb
+  chain_grpc_endpoint: tcp://localhost:9900
+keyring:
+  backend: test
+  from: trader
+  passphrase_env: TRADER_PASSPHRASE
+fees:
+  gas_prices: 500000000inj
+timeouts:
+  query: 5s
+tx_memo: my-strategy
+`
+
+const testTOMLConfig = `
+tx_memo = "my-strategy"
+
+[network]
+name = "mainnet"
+node = "lb"
+
+[fees]
+gas_prices = "500000000inj"
+gas_adjustment = 1.2
+
+[timeouts]
+broadcast = "30s"
+`
+
+func noEnv(string) (string, bool) {
+	return "", false
+}
+
+func TestParseYAMLConfig(t *testing.T) {
+	cfg, err := Parse([]byte(testYAMLConfig), FormatYAML, noEnv)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "trader", cfg.Keyring.From)
+	assert.Equal(t, "TRADER_PASSPHRASE", cfg.Keyring.PassphraseEnv)
+
+	network := cfg.LoadNetwork()
+	assert.Equal(t, "testnet", network.Name)
+	assert.Equal(t, "injective-888", network.ChainId)
+	assert.Equal(t, "tcp://localhost:9900", network.ChainGrpcEndpoint)
+	assert.Equal(t, common.LoadNetwork("testnet", "lb").ExchangeGrpcEndpoint, network.ExchangeGrpcEndpoint)
+
+	timeouts, err := cfg.ClientTimeouts()
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, timeouts.QueryTimeout)
+	assert.Equal(t, common.DefaultClientTimeouts().BroadcastTimeout, timeouts.BroadcastTimeout)
+
+	options, err := cfg.ClientOptions(client.Context{})
+	assert.NoError(t, err)
+	clientOptions := common.DefaultClientOptions()
+	for _, option := range options {
+		assert.NoError(t, option(clientOptions))
+	}
+	assert.Equal(t, "500000000inj", clientOptions.GasPrices)
+	assert.Equal(t, "my-strategy", clientOptions.TxMemo)
+	assert.Nil(t, clientOptions.TxFactory)
+}
+
+func TestLoadTOMLConfigWithEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(path, []byte(testTOMLConfig), 0o600))
+
+	t.Setenv("INJECTIVE_NETWORK", "testnet")
+	t.Setenv("INJECTIVE_NODE", "sentry")
+	t.Setenv("INJECTIVE_GAS_PRICES", "160000000inj")
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "testnet", cfg.LoadNetwork().Name)
+	assert.Equal(t, "160000000inj", cfg.Fees.GasPrices)
+	assert.Equal(t, 1.2, cfg.Fees.GasAdjustment)
+
+	options, err := cfg.ClientOptions(client.Context{})
+	assert.NoError(t, err)
+	clientOptions := common.DefaultClientOptions()
+	for _, option := range options {
+		assert.NoError(t, option(clientOptions))
+	}
+	assert.Equal(t, 30*time.Second, clientOptions.Timeouts.BroadcastTimeout)
+	assert.NotNil(t, clientOptions.TxFactory)
+	assert.Equal(t, 1.2, clientOptions.TxFactory.GasAdjustment())
+}
+
+func TestConfigValidation(t *testing.T) {
+	testCases := map[string]string{
+		"unknown network":  "network:\n  name: moon\n",
+		"invalid node":     "network:\n  name: mainnet\n  node: sentry\n",
+		"custom network":   "network:\n  name: custom\n  chain_id: injective-1\n",
+		"gas prices":       "network:\n  name: local\nfees:\n  gas_prices: inj\n",
+		"timeout":          "network:\n  name: local\ntimeouts:\n  query: soon\n",
+		"ledger with keys": "network:\n  name: local\nkeyring:\n  use_ledger: true\n  private_key_env: KEY\n",
+		"unknown field":    "network:\n  name: local\n  endpoint: tcp://localhost:9900\n",
+	}
+
+	for name, config := range testCases {
+		_, err := Parse([]byte(config), FormatYAML, noEnv)
+		assert.Error(t, err, name)
+	}
+
+	cfg, err := Parse([]byte("network:\n  name: custom\n  chain_id: injective-777\n  chain_grpc_endpoint: tcp://localhost:9900\n"), FormatYAML, noEnv)
+	assert.NoError(t, err)
+	network := cfg.LoadNetwork()
+	assert.Equal(t, "injective-777", network.ChainId)
+	assert.Equal(t, "inj", network.Fee_denom)
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+// DefaultIndexPriceMaxAge is the oldest index price accepted by NewIndexPriceGuard when no max age is configured
+const DefaultIndexPriceMaxAge = time.Minute
+
+var (
+	ErrIndexPriceNotPositive = errors.New("index price must be positive")
+	ErrIndexPriceStale       = errors.New("index price is stale")
+	ErrIndexPriceFromFuture  = errors.New("index price timestamp is in the future")
+	ErrIndexPriceDeviation   = errors.New("index price deviates too much from the last trade price")
+)
+
+// IndexPrice is an oracle price observation in human readable format, with the time it was published by the oracle
+type IndexPrice struct {
+	Price     decimal.Decimal
+	Timestamp time.Time
+}
+
+// ValidateIndexPricePositive checks the index price is greater than zero
+func ValidateIndexPricePositive(indexPrice IndexPrice) error {
+	if !indexPrice.Price.IsPositive() {
+		return errors.Wrapf(ErrIndexPriceNotPositive, "got %s", indexPrice.Price.String())
+	}
+	return nil
+}
+
+// ValidateIndexPriceFreshness checks the index price was published at most maxAge before now. Timestamps later than
+// now by more than DefaultExpirationClockSkew are rejected too, since they point to a wrong clock or a bad feed
+func ValidateIndexPriceFreshness(indexPrice IndexPrice, now time.Time, maxAge time.Duration) error {
+	if indexPrice.Timestamp.IsZero() {
+		return errors.Wrap(ErrIndexPriceStale, "the index price has no timestamp")
+	}
+	if indexPrice.Timestamp.After(now.Add(DefaultExpirationClockSkew)) {
+		return errors.Wrapf(ErrIndexPriceFromFuture, "published at %s, local time is %s", indexPrice.Timestamp.UTC().Format(time.RFC3339), now.UTC().Format(time.RFC3339))
+	}
+	if age := now.Sub(indexPrice.Timestamp); age > maxAge {
+		return errors.Wrapf(ErrIndexPriceStale, "published %s ago, max age is %s", age.Truncate(time.Second).String(), maxAge.String())
+	}
+	return nil
+}
+
+// ValidateIndexPriceDeviation checks the relative difference between the index price and the last trade price is not
+// greater than maxDeviation (e.g. 0.05 for 5%). The check is skipped when there is no last trade price
+func ValidateIndexPriceDeviation(indexPrice IndexPrice, lastTradePrice decimal.Decimal, maxDeviation decimal.Decimal) error {
+	if !lastTradePrice.IsPositive() {
+		return nil
+	}
+	deviation := indexPrice.Price.Sub(lastTradePrice).Abs().Div(lastTradePrice)
+	if deviation.GreaterThan(maxDeviation) {
+		return errors.Wrapf(ErrIndexPriceDeviation, "index price %s, last trade price %s, deviation %s, max deviation %s", indexPrice.Price.String(), lastTradePrice.String(), deviation.String(), maxDeviation.String())
+	}
+	return nil
+}
+
+// IndexPriceGuard refuses to use index prices that are not positive, stale or too far from the last trade price.
+// Use it to size orders from oracle data, so a frozen or broken feed does not translate into wrongly sized orders
+type IndexPriceGuard struct {
+	// MaxAge is the oldest index price accepted
+	MaxAge time.Duration
+	// MaxDeviation is the max relative difference with the last trade price. Zero disables the check
+	MaxDeviation decimal.Decimal
+
+	clock clock.Clock
+}
+
+func NewIndexPriceGuard(maxAge time.Duration, maxDeviation decimal.Decimal) *IndexPriceGuard {
+	if maxAge <= 0 {
+		maxAge = DefaultIndexPriceMaxAge
+	}
+	return &IndexPriceGuard{
+		MaxAge:       maxAge,
+		MaxDeviation: maxDeviation,
+		clock:        clock.Real(),
+	}
+}
+
+// SetClock sets the clock of the index price freshness checks
+func (g *IndexPriceGuard) SetClock(c clock.Clock) {
+	g.clock = clock.OrReal(c)
+}
+
+// Validate runs all the index price checks configured in the guard
+func (g *IndexPriceGuard) Validate(indexPrice IndexPrice, lastTradePrice decimal.Decimal) error {
+	if err := ValidateIndexPricePositive(indexPrice); err != nil {
+		return err
+	}
+	if err := ValidateIndexPriceFreshness(indexPrice, g.clock.Now(), g.MaxAge); err != nil {
+		return err
+	}
+	if g.MaxDeviation.IsPositive() {
+		return ValidateIndexPriceDeviation(indexPrice, lastTradePrice, g.MaxDeviation)
+	}
+	return nil
+}
+
+// QuantityForNotional returns the derivative market quantity worth the notional at the index price, rounded down to
+// the market quantity tick size. It fails if the index price does not pass the guard validations
+func (g *IndexPriceGuard) QuantityForNotional(market DerivativeMarket, indexPrice IndexPrice, lastTradePrice decimal.Decimal, notional decimal.Decimal) (decimal.Decimal, error) {
+	if err := g.Validate(indexPrice, lastTradePrice); err != nil {
+		return decimal.Decimal{}, errors.Wrapf(err, "can not size the order in market %s", market.Id)
+	}
+
+	quantity := notional.Div(indexPrice.Price)
+	return quantity.Div(market.MinQuantityTickSize).Floor().Mul(market.MinQuantityTickSize), nil
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+func TestValidateIndexPrice(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	indexPrice := IndexPrice{Price: decimal.RequireFromString("30000"), Timestamp: now.Add(-10 * time.Second)}
+
+	assert.Equal(t, nil, ValidateIndexPricePositive(indexPrice))
+	assert.Assert(t, errors.Is(ValidateIndexPricePositive(IndexPrice{Price: decimal.Zero}), ErrIndexPriceNotPositive))
+	assert.Assert(t, errors.Is(ValidateIndexPricePositive(IndexPrice{Price: decimal.NewFromInt(-1)}), ErrIndexPriceNotPositive))
+
+	assert.Equal(t, nil, ValidateIndexPriceFreshness(indexPrice, now, time.Minute))
+	assert.Assert(t, errors.Is(ValidateIndexPriceFreshness(indexPrice, now, 5*time.Second), ErrIndexPriceStale))
+	assert.Assert(t, errors.Is(ValidateIndexPriceFreshness(IndexPrice{Price: indexPrice.Price}, now, time.Minute), ErrIndexPriceStale))
+	futurePrice := IndexPrice{Price: indexPrice.Price, Timestamp: now.Add(time.Minute)}
+	assert.Assert(t, errors.Is(ValidateIndexPriceFreshness(futurePrice, now, time.Minute), ErrIndexPriceFromFuture))
+
+	maxDeviation := decimal.RequireFromString("0.05")
+	assert.Equal(t, nil, ValidateIndexPriceDeviation(indexPrice, decimal.RequireFromString("29000"), maxDeviation))
+	assert.Equal(t, nil, ValidateIndexPriceDeviation(indexPrice, decimal.Zero, maxDeviation))
+	assert.Assert(t, errors.Is(ValidateIndexPriceDeviation(indexPrice, decimal.RequireFromString("25000"), maxDeviation), ErrIndexPriceDeviation))
+}
+
+func TestIndexPriceGuardQuantityForNotional(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+	now := time.Unix(1700000000, 0)
+	guard := NewIndexPriceGuard(30*time.Second, decimal.RequireFromString("0.05"))
+	guard.SetClock(clock.NewFake(now))
+
+	indexPrice := IndexPrice{Price: decimal.RequireFromString("30000"), Timestamp: now.Add(-10 * time.Second)}
+	quantity, err := guard.QuantityForNotional(derivativeMarket, indexPrice, decimal.RequireFromString("30100"), decimal.RequireFromString("1000"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0.0333", quantity.String())
+
+	staleIndexPrice := IndexPrice{Price: indexPrice.Price, Timestamp: now.Add(-time.Minute)}
+	_, err = guard.QuantityForNotional(derivativeMarket, staleIndexPrice, decimal.Zero, decimal.RequireFromString("1000"))
+	assert.Assert(t, errors.Is(err, ErrIndexPriceStale))
+
+	_, err = guard.QuantityForNotional(derivativeMarket, indexPrice, decimal.RequireFromString("20000"), decimal.RequireFromString("1000"))
+	assert.Assert(t, errors.Is(err, ErrIndexPriceDeviation))
+
+	assert.Equal(t, DefaultIndexPriceMaxAge, NewIndexPriceGuard(0, decimal.Zero).MaxAge)
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	permyriadMultiplier  = decimal.NewFromInt(10000)
+	percentageMultiplier = decimal.NewFromInt(100)
+
+	ErrInvalidMarginRatio = errors.New("margin ratio must be greater than 0 and less than 1")
+)
+
+// MarginRatio is a fraction of the position notional (e.g. the market initial or maintenance margin ratio). Valid
+// ratios are greater than 0 and less than 1
+type MarginRatio struct {
+	value decimal.Decimal
+}
+
+func NewMarginRatio(value decimal.Decimal) (MarginRatio, error) {
+	ratio := MarginRatio{value: value}
+	if err := ratio.Validate(); err != nil {
+		return MarginRatio{}, err
+	}
+	return ratio, nil
+}
+
+// ParseMarginRatio parses a decimal ratio such as "0.05"
+func ParseMarginRatio(value string) (MarginRatio, error) {
+	ratio, err := decimal.NewFromString(value)
+	if err != nil {
+		return MarginRatio{}, errors.Wrapf(err, "invalid margin ratio %s", value)
+	}
+	return NewMarginRatio(ratio)
+}
+
+// MarginRatioFromPermyriad creates the ratio from parts per ten thousand (500 is 0.05)
+func MarginRatioFromPermyriad(permyriad int64) (MarginRatio, error) {
+	return NewMarginRatio(decimal.NewFromInt(permyriad).Div(permyriadMultiplier))
+}
+
+// MarginRatioFromBasisPoints creates the ratio from basis points. A basis point is a permyriad, so fractional values
+// allow finer ratios than MarginRatioFromPermyriad
+func MarginRatioFromBasisPoints(basisPoints decimal.Decimal) (MarginRatio, error) {
+	return NewMarginRatio(basisPoints.Div(permyriadMultiplier))
+}
+
+func MarginRatioFromPercentage(percentage decimal.Decimal) (MarginRatio, error) {
+	return NewMarginRatio(percentage.Div(percentageMultiplier))
+}
+
+func (r MarginRatio) Validate() error {
+	if !r.value.IsPositive() || r.value.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return errors.Wrapf(ErrInvalidMarginRatio, "got %s", r.value.String())
+	}
+	return nil
+}
+
+func (r MarginRatio) Decimal() decimal.Decimal {
+	return r.value
+}
+
+func (r MarginRatio) Permyriad() decimal.Decimal {
+	return r.value.Mul(permyriadMultiplier)
+}
+
+func (r MarginRatio) Percentage() decimal.Decimal {
+	return r.value.Mul(percentageMultiplier)
+}
+
+func (r MarginRatio) String() string {
+	return r.value.String()
+}
+
+// Margin returns the margin required by the ratio for the notional
+func (r MarginRatio) Margin(notional decimal.Decimal) decimal.Decimal {
+	return notional.Mul(r.value)
+}
+
+// MaxLeverage is the highest leverage allowed by the ratio (1 / ratio)
+func (r MarginRatio) MaxLeverage() decimal.Decimal {
+	return decimal.NewFromInt(1).Div(r.value)
+}
+
+func (r MarginRatio) Add(other MarginRatio) (MarginRatio, error) {
+	return NewMarginRatio(r.value.Add(other.value))
+}
+
+func (r MarginRatio) Sub(other MarginRatio) (MarginRatio, error) {
+	return NewMarginRatio(r.value.Sub(other.value))
+}
+
+func (r MarginRatio) Mul(factor decimal.Decimal) (MarginRatio, error) {
+	return NewMarginRatio(r.value.Mul(factor))
+}
+
+func (r MarginRatio) Equal(other MarginRatio) bool {
+	return r.value.Equal(other.value)
+}
+
+func (r MarginRatio) GreaterThan(other MarginRatio) bool {
+	return r.value.GreaterThan(other.value)
+}
+
+func (r MarginRatio) LessThan(other MarginRatio) bool {
+	return r.value.LessThan(other.value)
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/huandu/go-assert"
+	"github.com/shopspring/decimal"
+)
+
+func TestMarginRatioConversions(t *testing.T) {
+	fromPermyriad, err := MarginRatioFromPermyriad(500)
+	assert.Equal(t, nil, err)
+	fromBasisPoints, err := MarginRatioFromBasisPoints(decimal.RequireFromString("500"))
+	assert.Equal(t, nil, err)
+	fromPercentage, err := MarginRatioFromPercentage(decimal.RequireFromString("5"))
+	assert.Equal(t, nil, err)
+	parsed, err := ParseMarginRatio("0.05")
+	assert.Equal(t, nil, err)
+
+	assert.Assert(t, fromPermyriad.Equal(parsed))
+	assert.Assert(t, fromBasisPoints.Equal(parsed))
+	assert.Assert(t, fromPercentage.Equal(parsed))
+	assert.Equal(t, "500", parsed.Permyriad().String())
+	assert.Equal(t, "5", parsed.Percentage().String())
+	assert.Equal(t, "20", parsed.MaxLeverage().String())
+	assert.Equal(t, "50", parsed.Margin(decimal.RequireFromString("1000")).String())
+}
+
+func TestMarginRatioBounds(t *testing.T) {
+	for _, value := range []string{"0", "-0.1", "1", "1.5"} {
+		_, err := ParseMarginRatio(value)
+		assert.Assert(t, errors.Is(err, ErrInvalidMarginRatio))
+	}
+	_, err := ParseMarginRatio("five percent")
+	assert.Assert(t, err != nil)
+
+	initial, _ := ParseMarginRatio("0.6")
+	maintenance, _ := ParseMarginRatio("0.5")
+	difference, err := initial.Sub(maintenance)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0.1", difference.String())
+	_, err = initial.Add(maintenance)
+	assert.Assert(t, errors.Is(err, ErrInvalidMarginRatio))
+	assert.Assert(t, initial.GreaterThan(maintenance))
+	assert.Assert(t, maintenance.LessThan(initial))
+}
@@ -1,13 +1,25 @@
 package core
 
 import (
+	"time"
+
 	"github.com/InjectiveLabs/sdk-go/client/common"
 	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 )
 
 const AdditionalChainFormatDecimals = 18
 
+// DefaultExpirationClockSkew is the tolerance used to consider a market expired before the chain does, to account for
+// differences between the local clock and the block time
+const DefaultExpirationClockSkew = 5 * time.Second
+
+var (
+	ErrDustOrder             = errors.New("order rounds to zero with the market tick sizes")
+	ErrOrderBelowMinNotional = errors.New("order notional is lower than the min notional")
+)
+
 type SpotMarket struct {
 	Id                  string
 	Status              string
@@ -55,6 +67,39 @@ func (spotMarket SpotMarket) PriceFromExtendedChainFormat(chainValue cosmtypes.D
 	return common.RemoveExtraDecimals(spotMarket.PriceFromChainFormat(chainValue), AdditionalChainFormatDecimals)
 }
 
+// PriceTickSize is the min price tick size in human readable format
+func (spotMarket SpotMarket) PriceTickSize() decimal.Decimal {
+	return spotMarket.MinPriceTickSize.Shift(spotMarket.BaseToken.Decimals - spotMarket.QuoteToken.Decimals)
+}
+
+// QuantityTickSize is the min quantity tick size in human readable format
+func (spotMarket SpotMarket) QuantityTickSize() decimal.Decimal {
+	return spotMarket.MinQuantityTickSize.Shift(-spotMarket.BaseToken.Decimals)
+}
+
+// NotionalInChainFormat returns the quote amount (in the quote token chain units) of an order with the price and
+// quantity, calculated from the values quantized to the market tick sizes, as the chain does
+func (spotMarket SpotMarket) NotionalInChainFormat(humanReadablePrice decimal.Decimal, humanReadableQuantity decimal.Decimal) cosmtypes.Dec {
+	return spotMarket.PriceToChainFormat(humanReadablePrice).Mul(spotMarket.QuantityToChainFormat(humanReadableQuantity))
+}
+
+// ValidateMinNotional rejects orders whose quantity or price round to zero with the market tick sizes, and orders
+// whose notional is lower than minNotional (a human readable quote token amount). A zero minNotional only rejects the
+// orders rounding to zero
+func (spotMarket SpotMarket) ValidateMinNotional(humanReadablePrice decimal.Decimal, humanReadableQuantity decimal.Decimal, minNotional decimal.Decimal) error {
+	chainQuantity := spotMarket.QuantityToChainFormat(humanReadableQuantity)
+	chainPrice := spotMarket.PriceToChainFormat(humanReadablePrice)
+	if !chainQuantity.IsPositive() || !chainPrice.IsPositive() {
+		return errors.Wrapf(ErrDustOrder, "quantity %s at price %s rounds to zero in market %s", humanReadableQuantity.String(), humanReadablePrice.String(), spotMarket.Ticker)
+	}
+
+	notional := decimal.RequireFromString(chainPrice.Mul(chainQuantity).String()).Div(decimal.New(1, spotMarket.QuoteToken.Decimals))
+	if notional.LessThan(minNotional) {
+		return errors.Wrapf(ErrOrderBelowMinNotional, "order notional %s %s is lower than the min notional %s %s", notional.String(), spotMarket.QuoteToken.Symbol, minNotional.String(), spotMarket.QuoteToken.Symbol)
+	}
+	return nil
+}
+
 type DerivativeMarket struct {
 	Id                     string
 	Status                 string
@@ -71,25 +116,47 @@ type DerivativeMarket struct {
 	ServiceProviderFee     decimal.Decimal
 	MinPriceTickSize       decimal.Decimal
 	MinQuantityTickSize    decimal.Decimal
+	// ExpirationTimestamp is the expiry futures market expiration time in seconds (zero for perpetual markets)
+	ExpirationTimestamp int64
 }
 
 func (derivativeMarket DerivativeMarket) QuantityToChainFormat(humanReadableValue decimal.Decimal) cosmtypes.Dec {
-	chainFormattedValue := humanReadableValue
-	quantizedValue := chainFormattedValue.DivRound(derivativeMarket.MinQuantityTickSize, 0).Mul(derivativeMarket.MinQuantityTickSize)
+	quantizedValue := derivativeMarket.quantizedChainQuantity(humanReadableValue)
 	valueInChainFormat, _ := cosmtypes.NewDecFromStr(quantizedValue.String())
 
 	return valueInChainFormat
 }
 
 func (derivativeMarket DerivativeMarket) PriceToChainFormat(humanReadableValue decimal.Decimal) cosmtypes.Dec {
-	decimals := derivativeMarket.QuoteToken.Decimals
-	chainFormattedValue := humanReadableValue.Mul(decimal.New(1, decimals))
-	quantizedValue := chainFormattedValue.DivRound(derivativeMarket.MinPriceTickSize, 0).Mul(derivativeMarket.MinPriceTickSize)
+	quantizedValue := derivativeMarket.quantizedChainPrice(humanReadableValue)
 	valueInChainFormat, _ := cosmtypes.NewDecFromStr(quantizedValue.String())
 
 	return valueInChainFormat
 }
 
+// quantizedChainQuantity is the quantity sent in the orders, rounded to the quantity tick size
+func (derivativeMarket DerivativeMarket) quantizedChainQuantity(humanReadableValue decimal.Decimal) decimal.Decimal {
+	chainFormattedValue := humanReadableValue
+	return chainFormattedValue.DivRound(derivativeMarket.MinQuantityTickSize, 0).Mul(derivativeMarket.MinQuantityTickSize)
+}
+
+// quantizedChainPrice is the price sent in the orders, in chain format and rounded to the price tick size
+func (derivativeMarket DerivativeMarket) quantizedChainPrice(humanReadableValue decimal.Decimal) decimal.Decimal {
+	decimals := derivativeMarket.QuoteToken.Decimals
+	chainFormattedValue := humanReadableValue.Mul(decimal.New(1, decimals))
+	return chainFormattedValue.DivRound(derivativeMarket.MinPriceTickSize, 0).Mul(derivativeMarket.MinPriceTickSize)
+}
+
+// PriceTickSize is the min price tick size in human readable format
+func (derivativeMarket DerivativeMarket) PriceTickSize() decimal.Decimal {
+	return derivativeMarket.MinPriceTickSize.Shift(-derivativeMarket.QuoteToken.Decimals)
+}
+
+// QuantityTickSize is the min quantity tick size in human readable format
+func (derivativeMarket DerivativeMarket) QuantityTickSize() decimal.Decimal {
+	return derivativeMarket.MinQuantityTickSize
+}
+
 func (derivativeMarket DerivativeMarket) MarginToChainFormat(humanReadableValue decimal.Decimal) cosmtypes.Dec {
 	decimals := derivativeMarket.QuoteToken.Decimals
 	chainFormattedValue := humanReadableValue.Mul(decimal.New(1, decimals))
@@ -112,6 +179,49 @@ func (derivativeMarket DerivativeMarket) CalculateMarginInChainFormat(humanReada
 	return valueInChainFormat
 }
 
+// InitialMarginRatioValue returns the market initial margin ratio, validating it is between 0 and 1
+func (derivativeMarket DerivativeMarket) InitialMarginRatioValue() (MarginRatio, error) {
+	return NewMarginRatio(derivativeMarket.InitialMarginRatio)
+}
+
+// MaintenanceMarginRatioValue returns the market maintenance margin ratio, validating it is between 0 and 1
+func (derivativeMarket DerivativeMarket) MaintenanceMarginRatioValue() (MarginRatio, error) {
+	return NewMarginRatio(derivativeMarket.MaintenanceMarginRatio)
+}
+
+// ValidateLeverage checks the leverage is allowed by the market initial margin ratio
+func (derivativeMarket DerivativeMarket) ValidateLeverage(leverage decimal.Decimal) error {
+	initialMarginRatio, err := derivativeMarket.InitialMarginRatioValue()
+	if err != nil {
+		return err
+	}
+	if !leverage.IsPositive() {
+		return errors.Errorf("leverage must be positive, got %s", leverage.String())
+	}
+	if maxLeverage := initialMarginRatio.MaxLeverage(); leverage.GreaterThan(maxLeverage) {
+		return errors.Errorf("leverage %s is greater than the market max leverage %s", leverage.String(), maxLeverage.String())
+	}
+	return nil
+}
+
+// CalculateMinimumMarginInChainFormat returns the lowest margin accepted for an order of the quantity at the price:
+// the notional of the quantized quantity and price (as sent by QuantityToChainFormat and PriceToChainFormat)
+// multiplied by the initial margin ratio, rounded up to the quantity tick size
+func (derivativeMarket DerivativeMarket) CalculateMinimumMarginInChainFormat(humanReadableQuantity decimal.Decimal, humanReadablePrice decimal.Decimal) (cosmtypes.Dec, error) {
+	initialMarginRatio, err := derivativeMarket.InitialMarginRatioValue()
+	if err != nil {
+		return cosmtypes.Dec{}, err
+	}
+
+	quantity := derivativeMarket.quantizedChainQuantity(humanReadableQuantity)
+	price := derivativeMarket.quantizedChainPrice(humanReadablePrice)
+	margin := initialMarginRatio.Margin(quantity.Mul(price))
+	quantizedMargin := margin.Div(derivativeMarket.MinQuantityTickSize).Ceil().Mul(derivativeMarket.MinQuantityTickSize)
+	valueInChainFormat, _ := cosmtypes.NewDecFromStr(quantizedMargin.String())
+
+	return valueInChainFormat, nil
+}
+
 func (derivativeMarket DerivativeMarket) QuantityFromChainFormat(chainValue cosmtypes.Dec) decimal.Decimal {
 	return decimal.RequireFromString(chainValue.String())
 }
@@ -137,3 +247,13 @@ func (derivativeMarket DerivativeMarket) PriceFromExtendedChainFormat(chainValue
 func (derivativeMarket DerivativeMarket) MarginFromExtendedChainFormat(chainValue cosmtypes.Dec) decimal.Decimal {
 	return common.RemoveExtraDecimals(derivativeMarket.MarginFromChainFormat(chainValue), AdditionalChainFormatDecimals)
 }
+
+// IsExpired returns true if the market expiration is within skew of the block time. The chain rejects orders in expiry
+// futures markets once the block time reaches the expiration, so the skew allows stopping before that happens.
+// Perpetual markets never expire
+func (derivativeMarket DerivativeMarket) IsExpired(blockTime time.Time, skew time.Duration) bool {
+	if derivativeMarket.ExpirationTimestamp == 0 {
+		return false
+	}
+	return !blockTime.Add(skew).Before(time.Unix(derivativeMarket.ExpirationTimestamp, 0))
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// MarketSnapshot is an immutable view of a derivative market and its last index price. Updates create a new snapshot,
+// so a snapshot can be read by any number of goroutines (e.g. order validation) while the market is being updated
+type MarketSnapshot struct {
+	market     DerivativeMarket
+	indexPrice IndexPrice
+}
+
+func NewMarketSnapshot(market DerivativeMarket, indexPrice IndexPrice) *MarketSnapshot {
+	return &MarketSnapshot{
+		market:     market,
+		indexPrice: indexPrice,
+	}
+}
+
+// Market returns a copy of the market
+func (s *MarketSnapshot) Market() DerivativeMarket {
+	return s.market
+}
+
+func (s *MarketSnapshot) IndexPrice() IndexPrice {
+	return s.indexPrice
+}
+
+// WithIndexPrice returns a new snapshot of the market with the index price
+func (s *MarketSnapshot) WithIndexPrice(indexPrice IndexPrice) *MarketSnapshot {
+	return NewMarketSnapshot(s.market, indexPrice)
+}
+
+// WithMarket returns a new snapshot with the market definition, keeping the index price
+func (s *MarketSnapshot) WithMarket(market DerivativeMarket) *MarketSnapshot {
+	return NewMarketSnapshot(market, s.indexPrice)
+}
+
+// MarketSnapshotHolder holds the latest snapshot of a market. Readers always get a complete snapshot, and writers
+// swap it atomically
+type MarketSnapshotHolder struct {
+	snapshot atomic.Pointer[MarketSnapshot]
+}
+
+func NewMarketSnapshotHolder(snapshot *MarketSnapshot) *MarketSnapshotHolder {
+	holder := &MarketSnapshotHolder{}
+	holder.snapshot.Store(snapshot)
+	return holder
+}
+
+func (h *MarketSnapshotHolder) Load() *MarketSnapshot {
+	return h.snapshot.Load()
+}
+
+// Swap stores the snapshot and returns the previous one
+func (h *MarketSnapshotHolder) Swap(snapshot *MarketSnapshot) *MarketSnapshot {
+	return h.snapshot.Swap(snapshot)
+}
+
+// Update replaces the snapshot with update(current snapshot). update can be called more than once if other goroutines
+// update the snapshot at the same time, so it must not have side effects
+func (h *MarketSnapshotHolder) Update(update func(snapshot *MarketSnapshot) *MarketSnapshot) *MarketSnapshot {
+	for {
+		current := h.snapshot.Load()
+		updated := update(current)
+		if h.snapshot.CompareAndSwap(current, updated) {
+			return updated
+		}
+	}
+}
+
+// MarketSnapshotCache has the snapshot holders of the derivative markets by market id
+type MarketSnapshotCache struct {
+	mux     sync.RWMutex
+	holders map[string]*MarketSnapshotHolder
+}
+
+func NewMarketSnapshotCache(markets map[string]DerivativeMarket) *MarketSnapshotCache {
+	holders := make(map[string]*MarketSnapshotHolder, len(markets))
+	for marketId, market := range markets {
+		holders[marketId] = NewMarketSnapshotHolder(NewMarketSnapshot(market, IndexPrice{}))
+	}
+	return &MarketSnapshotCache{holders: holders}
+}
+
+// Snapshot returns the latest snapshot of the market
+func (c *MarketSnapshotCache) Snapshot(marketId string) (*MarketSnapshot, bool) {
+	holder, found := c.holder(marketId)
+	if !found {
+		return nil, false
+	}
+	return holder.Load(), true
+}
+
+// StoreMarket adds the market, or replaces the definition of a known market keeping its index price
+func (c *MarketSnapshotCache) StoreMarket(market DerivativeMarket) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if holder, found := c.holders[market.Id]; found {
+		holder.Update(func(snapshot *MarketSnapshot) *MarketSnapshot { return snapshot.WithMarket(market) })
+		return
+	}
+	c.holders[market.Id] = NewMarketSnapshotHolder(NewMarketSnapshot(market, IndexPrice{}))
+}
+
+// UpdateIndexPrice stores the index price of the market, unless it is older than the one in the snapshot
+func (c *MarketSnapshotCache) UpdateIndexPrice(marketId string, indexPrice IndexPrice) error {
+	holder, found := c.holder(marketId)
+	if !found {
+		return errors.Errorf("market %s is not in the cache", marketId)
+	}
+
+	holder.Update(func(snapshot *MarketSnapshot) *MarketSnapshot {
+		if indexPrice.Timestamp.Before(snapshot.IndexPrice().Timestamp) {
+			return snapshot
+		}
+		return snapshot.WithIndexPrice(indexPrice)
+	})
+	return nil
+}
+
+func (c *MarketSnapshotCache) holder(marketId string) (*MarketSnapshotHolder, bool) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	holder, found := c.holders[marketId]
+	return holder, found
+}
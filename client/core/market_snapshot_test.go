@@ -0,0 +1,70 @@
+package core
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+	"github.com/shopspring/decimal"
+)
+
+func TestMarketSnapshotCacheUpdates(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+	cache := NewMarketSnapshotCache(map[string]DerivativeMarket{derivativeMarket.Id: derivativeMarket})
+
+	before, found := cache.Snapshot(derivativeMarket.Id)
+	assert.Assert(t, found)
+	assert.Assert(t, before.IndexPrice().Timestamp.IsZero())
+
+	now := time.Unix(1700000000, 0)
+	indexPrice := IndexPrice{Price: decimal.RequireFromString("30000"), Timestamp: now}
+	assert.Equal(t, nil, cache.UpdateIndexPrice(derivativeMarket.Id, indexPrice))
+	// older prices are ignored
+	assert.Equal(t, nil, cache.UpdateIndexPrice(derivativeMarket.Id, IndexPrice{Price: decimal.RequireFromString("1"), Timestamp: now.Add(-time.Second)}))
+
+	after, _ := cache.Snapshot(derivativeMarket.Id)
+	assert.Equal(t, "30000", after.IndexPrice().Price.String())
+	// the previous snapshot is not modified
+	assert.Assert(t, before.IndexPrice().Timestamp.IsZero())
+
+	updatedMarket := derivativeMarket
+	updatedMarket.MinPriceTickSize = decimal.RequireFromString("0.01")
+	cache.StoreMarket(updatedMarket)
+	after, _ = cache.Snapshot(derivativeMarket.Id)
+	assert.Equal(t, "0.01", after.Market().MinPriceTickSize.String())
+	assert.Equal(t, "30000", after.IndexPrice().Price.String())
+
+	assert.Assert(t, cache.UpdateIndexPrice("0x00", indexPrice) != nil)
+	_, found = cache.Snapshot("0x00")
+	assert.Assert(t, !found)
+}
+
+func TestMarketSnapshotCacheConcurrentReadsAndUpdates(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+	cache := NewMarketSnapshotCache(map[string]DerivativeMarket{derivativeMarket.Id: derivativeMarket})
+	start := time.Unix(1700000000, 0)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				indexPrice := IndexPrice{Price: decimal.NewFromInt(int64(30000 + j)), Timestamp: start.Add(time.Duration(j) * time.Second)}
+				_ = cache.UpdateIndexPrice(derivativeMarket.Id, indexPrice)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				snapshot, _ := cache.Snapshot(derivativeMarket.Id)
+				snapshot.Market().QuantityToChainFormat(decimal.RequireFromString("0.1"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	snapshot, _ := cache.Snapshot(derivativeMarket.Id)
+	assert.Equal(t, "30099", snapshot.IndexPrice().Price.String())
+}
@@ -1,7 +1,9 @@
 package core
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/huandu/go-assert"
@@ -187,6 +189,48 @@ func TestCalculateMarginInChainFormatForDerivativeMarket(t *testing.T) {
 	assert.Assert(t, chainValue.Equal(legacyDecimalQuantizedValue))
 }
 
+func TestCalculateMarginInChainFormatWithoutLeverage(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+
+	chainValue := derivativeMarket.CalculateMarginInChainFormat(decimal.RequireFromString("2"), decimal.RequireFromString("100"), decimal.NewFromInt(1))
+	assert.Equal(t, "200000000.000000000000000000", chainValue.String())
+}
+
+func TestCalculateMarginInChainFormatDividesByTheLeverage(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+
+	// 14 * 43210.123 / 3 is 201647.2406666..., quantized to 201647.2406667, while multiplying by a rounded 1/3 would
+	// give 201647.2406666
+	chainValue := derivativeMarket.CalculateMarginInChainFormat(decimal.RequireFromString("14"), decimal.RequireFromString("43210.123"), decimal.RequireFromString("3"))
+	assert.Equal(t, "201647240666.666700000000000000", chainValue.String())
+}
+
+func TestCalculateMinimumMarginInChainFormatForDerivativeMarket(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+
+	chainValue, err := derivativeMarket.CalculateMinimumMarginInChainFormat(decimal.RequireFromString("10.00004"), decimal.RequireFromString("123.456789"))
+	assert.Equal(t, nil, err)
+	// the order quantity and price are quantized to 10 and 123000000: 10 * 123000000 * 0.095 = 116850000
+	assert.Equal(t, "116850000.000000000000000000", chainValue.String())
+
+	quantity := derivativeMarket.QuantityToChainFormat(decimal.RequireFromString("10.00004"))
+	price := derivativeMarket.PriceToChainFormat(decimal.RequireFromString("123.456789"))
+	assert.Assert(t, chainValue.Equal(quantity.Mul(price).Mul(types.MustNewDecFromStr("0.095"))))
+}
+
+func TestValidateLeverageForDerivativeMarket(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+
+	assert.Equal(t, nil, derivativeMarket.ValidateLeverage(decimal.RequireFromString("10")))
+	// the max leverage is 1 / 0.095
+	assert.Assert(t, derivativeMarket.ValidateLeverage(decimal.RequireFromString("10.6")) != nil)
+	assert.Assert(t, derivativeMarket.ValidateLeverage(decimal.Zero) != nil)
+
+	derivativeMarket.InitialMarginRatio = decimal.Zero
+	_, err := derivativeMarket.InitialMarginRatioValue()
+	assert.Assert(t, errors.Is(err, ErrInvalidMarginRatio))
+}
+
 func TestConvertQuantityFromChainFormatForDerivativeMarket(t *testing.T) {
 	derivativeMarket := createBTCUSDTPerpMarket()
 	expectedQuantity := decimal.RequireFromString("123.456")
@@ -250,3 +294,59 @@ func TestConvertMarginFromExtendedChainFormatForDerivativeMarket(t *testing.T) {
 
 	assert.Assert(t, expectedMargin.Equal(humanReadablePrice))
 }
+
+func TestDerivativeMarketIsExpired(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+	blockTime := time.Unix(1700000000, 0)
+
+	assert.Assert(t, !derivativeMarket.IsExpired(blockTime, DefaultExpirationClockSkew))
+
+	derivativeMarket.ExpirationTimestamp = blockTime.Unix() + 10
+	assert.Assert(t, !derivativeMarket.IsExpired(blockTime, DefaultExpirationClockSkew))
+	assert.Assert(t, derivativeMarket.IsExpired(blockTime, 10*time.Second))
+	assert.Assert(t, derivativeMarket.IsExpired(blockTime.Add(10*time.Second), 0))
+	assert.Assert(t, !derivativeMarket.IsExpired(blockTime.Add(9*time.Second), 0))
+}
+
+func TestSpotMarketValidateMinNotional(t *testing.T) {
+	spotMarket := createINJUSDTSpotMarket()
+	price := decimal.RequireFromString("10")
+
+	notional := spotMarket.NotionalInChainFormat(price, decimal.RequireFromString("0.5"))
+	assert.Equal(t, "5000000.000000000000000000", notional.String())
+
+	assert.Equal(t, nil, spotMarket.ValidateMinNotional(price, decimal.RequireFromString("0.5"), decimal.RequireFromString("1")))
+	assert.Equal(t, nil, spotMarket.ValidateMinNotional(price, decimal.RequireFromString("0.5"), decimal.Zero))
+
+	err := spotMarket.ValidateMinNotional(price, decimal.RequireFromString("0.5"), decimal.RequireFromString("10"))
+	assert.Assert(t, errors.Is(err, ErrOrderBelowMinNotional))
+
+	err = spotMarket.ValidateMinNotional(price, decimal.RequireFromString("0.0004"), decimal.Zero)
+	assert.Assert(t, errors.Is(err, ErrDustOrder))
+	err = spotMarket.ValidateMinNotional(decimal.RequireFromString("0.0001"), decimal.RequireFromString("1"), decimal.Zero)
+	assert.Assert(t, errors.Is(err, ErrDustOrder))
+}
+
+func BenchmarkCalculateMarginInChainFormat(b *testing.B) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+	quantity := decimal.RequireFromString("10.00004")
+	price := decimal.RequireFromString("123.456789")
+	leverage := decimal.RequireFromString("2.5")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		derivativeMarket.CalculateMarginInChainFormat(quantity, price, leverage)
+	}
+}
+
+func BenchmarkQuantityAndPriceToChainFormat(b *testing.B) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+	quantity := decimal.RequireFromString("10.00004")
+	price := decimal.RequireFromString("123.456789")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		derivativeMarket.QuantityToChainFormat(quantity)
+		derivativeMarket.PriceToChainFormat(price)
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Rounding is the direction a value is rounded to a multiple of a tick size
+type Rounding int
+
+const (
+	// RoundNearest rounds to the nearest tick, half away from zero, like the chain format conversions of the markets
+	RoundNearest Rounding = iota
+	RoundDown
+	RoundUp
+)
+
+// TickSizeMarket is a market with price and quantity tick sizes, like SpotMarket and DerivativeMarket
+type TickSizeMarket interface {
+	PriceTickSize() decimal.Decimal
+	QuantityTickSize() decimal.Decimal
+}
+
+// OrderRounding has the rounding directions NormalizeOrder uses for the buy and sell prices and for the quantities
+type OrderRounding struct {
+	BuyPrice  Rounding
+	SellPrice Rounding
+	Quantity  Rounding
+}
+
+// DefaultOrderRounding rounds the bid prices down and the ask prices up, so the orders are never more aggressive than
+// requested, and the quantities down, so they are never bigger than requested
+func DefaultOrderRounding() OrderRounding {
+	return OrderRounding{
+		BuyPrice:  RoundDown,
+		SellPrice: RoundUp,
+		Quantity:  RoundDown,
+	}
+}
+
+// NormalizedOrder has the price and quantity of an order rounded to the market tick sizes (in human readable format),
+// and the deltas added to the requested values by the rounding
+type NormalizedOrder struct {
+	Price         decimal.Decimal
+	Quantity      decimal.Decimal
+	PriceDelta    decimal.Decimal
+	QuantityDelta decimal.Decimal
+}
+
+// NormalizeOrder rounds the human readable price and quantity of an order to the market tick sizes with the
+// DefaultOrderRounding. Orders whose price or quantity round to zero are rejected with ErrDustOrder
+func NormalizeOrder(price decimal.Decimal, quantity decimal.Decimal, market TickSizeMarket, isBuy bool) (NormalizedOrder, error) {
+	return DefaultOrderRounding().NormalizeOrder(price, quantity, market, isBuy)
+}
+
+// NormalizeOrder rounds the human readable price and quantity of an order to the market tick sizes. Orders whose price
+// or quantity round to zero are rejected with ErrDustOrder
+func (r OrderRounding) NormalizeOrder(price decimal.Decimal, quantity decimal.Decimal, market TickSizeMarket, isBuy bool) (NormalizedOrder, error) {
+	if !price.IsPositive() {
+		return NormalizedOrder{}, errors.Errorf("the order price must be positive, got %s", price.String())
+	}
+	if !quantity.IsPositive() {
+		return NormalizedOrder{}, errors.Errorf("the order quantity must be positive, got %s", quantity.String())
+	}
+
+	priceTickSize, quantityTickSize := market.PriceTickSize(), market.QuantityTickSize()
+	if !priceTickSize.IsPositive() || !quantityTickSize.IsPositive() {
+		return NormalizedOrder{}, errors.Errorf("the market tick sizes must be positive, got price tick %s and quantity tick %s", priceTickSize.String(), quantityTickSize.String())
+	}
+
+	priceRounding := r.SellPrice
+	if isBuy {
+		priceRounding = r.BuyPrice
+	}
+	normalizedPrice, err := roundToTick(price, priceTickSize, priceRounding)
+	if err != nil {
+		return NormalizedOrder{}, err
+	}
+	normalizedQuantity, err := roundToTick(quantity, quantityTickSize, r.Quantity)
+	if err != nil {
+		return NormalizedOrder{}, err
+	}
+	if !normalizedPrice.IsPositive() || !normalizedQuantity.IsPositive() {
+		return NormalizedOrder{}, errors.Wrapf(ErrDustOrder, "quantity %s at price %s rounds to zero", quantity.String(), price.String())
+	}
+
+	return NormalizedOrder{
+		Price:         normalizedPrice,
+		Quantity:      normalizedQuantity,
+		PriceDelta:    normalizedPrice.Sub(price),
+		QuantityDelta: normalizedQuantity.Sub(quantity),
+	}, nil
+}
+
+// roundToTick rounds a positive value to a multiple of the tick size. The quotient is calculated exactly, so values
+// already quantized are never moved by a division rounding
+func roundToTick(value decimal.Decimal, tickSize decimal.Decimal, rounding Rounding) (decimal.Decimal, error) {
+	switch rounding {
+	case RoundNearest:
+		return value.DivRound(tickSize, 0).Mul(tickSize), nil
+	case RoundDown, RoundUp:
+		ticks, remainder := value.QuoRem(tickSize, 0)
+		if rounding == RoundUp && !remainder.IsZero() {
+			ticks = ticks.Add(decimal.New(1, 0))
+		}
+		return ticks.Mul(tickSize), nil
+	default:
+		return decimal.Zero, errors.Errorf("unknown rounding %d", rounding)
+	}
+}
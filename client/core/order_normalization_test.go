@@ -0,0 +1,84 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/huandu/go-assert"
+	"github.com/shopspring/decimal"
+)
+
+func TestMarketTickSizes(t *testing.T) {
+	spotMarket := createINJUSDTSpotMarket()
+	assert.Equal(t, "0.001", spotMarket.PriceTickSize().String())
+	assert.Equal(t, "0.001", spotMarket.QuantityTickSize().String())
+
+	derivativeMarket := createBTCUSDTPerpMarket()
+	assert.Equal(t, "1", derivativeMarket.PriceTickSize().String())
+	assert.Equal(t, "0.0001", derivativeMarket.QuantityTickSize().String())
+}
+
+func TestNormalizeOrderRoundsPassively(t *testing.T) {
+	spotMarket := createINJUSDTSpotMarket()
+	price := decimal.RequireFromString("12.34567")
+	quantity := decimal.RequireFromString("3.14159")
+
+	bid, err := NormalizeOrder(price, quantity, spotMarket, true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "12.345", bid.Price.String())
+	assert.Equal(t, "-0.00067", bid.PriceDelta.String())
+	assert.Equal(t, "3.141", bid.Quantity.String())
+	assert.Equal(t, "-0.00059", bid.QuantityDelta.String())
+
+	ask, err := NormalizeOrder(price, quantity, spotMarket, false)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "12.346", ask.Price.String())
+	assert.Equal(t, "0.00033", ask.PriceDelta.String())
+	assert.Equal(t, "3.141", ask.Quantity.String())
+
+	// the values already quantized are not changed
+	quantized, err := NormalizeOrder(bid.Price, bid.Quantity, spotMarket, false)
+	assert.Equal(t, nil, err)
+	assert.Assert(t, quantized.Price.Equal(bid.Price))
+	assert.Assert(t, quantized.PriceDelta.IsZero())
+	assert.Assert(t, quantized.QuantityDelta.IsZero())
+}
+
+func TestNormalizeOrderWithRounding(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+	rounding := OrderRounding{BuyPrice: RoundUp, SellPrice: RoundNearest, Quantity: RoundNearest}
+
+	bid, err := rounding.NormalizeOrder(decimal.RequireFromString("30000.2"), decimal.RequireFromString("0.00015"), derivativeMarket, true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "30001", bid.Price.String())
+	assert.Equal(t, "0.0002", bid.Quantity.String())
+
+	ask, err := rounding.NormalizeOrder(decimal.RequireFromString("30000.5"), decimal.RequireFromString("0.00014"), derivativeMarket, false)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "30001", ask.Price.String())
+	assert.Equal(t, "0.0001", ask.Quantity.String())
+
+	_, err = OrderRounding{BuyPrice: Rounding(7)}.NormalizeOrder(decimal.RequireFromString("30000"), decimal.RequireFromString("1"), derivativeMarket, true)
+	assert.Equal(t, "unknown rounding 7", err.Error())
+}
+
+func TestNormalizeOrderRejectsInvalidOrders(t *testing.T) {
+	derivativeMarket := createBTCUSDTPerpMarket()
+
+	_, err := NormalizeOrder(decimal.RequireFromString("30000"), decimal.RequireFromString("0.00009"), derivativeMarket, true)
+	assert.Assert(t, errors.Is(err, ErrDustOrder))
+	_, err = NormalizeOrder(decimal.RequireFromString("0.5"), decimal.RequireFromString("1"), derivativeMarket, true)
+	assert.Assert(t, errors.Is(err, ErrDustOrder))
+	// a sell price rounded up is never zero
+	_, err = NormalizeOrder(decimal.RequireFromString("0.5"), decimal.RequireFromString("1"), derivativeMarket, false)
+	assert.Equal(t, nil, err)
+
+	_, err = NormalizeOrder(decimal.Zero, decimal.RequireFromString("1"), derivativeMarket, true)
+	assert.Equal(t, "the order price must be positive, got 0", err.Error())
+	_, err = NormalizeOrder(decimal.RequireFromString("30000"), decimal.RequireFromString("-1"), derivativeMarket, true)
+	assert.Equal(t, "the order quantity must be positive, got -1", err.Error())
+
+	derivativeMarket.MinQuantityTickSize = decimal.Zero
+	_, err = NormalizeOrder(decimal.RequireFromString("30000"), decimal.RequireFromString("1"), derivativeMarket, true)
+	assert.Equal(t, "the market tick sizes must be positive, got price tick 1 and quantity tick 0", err.Error())
+}
@@ -0,0 +1,338 @@
+package dispatch
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"sync"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultQueueSize = 256
+	DefaultWorkers   = 8
+)
+
+// ErrClosed is returned by Dispatch after Close
+var ErrClosed = errors.New("the dispatcher is closed")
+
+// Policy is what Dispatch does when the queue of the key is full
+type Policy int
+
+const (
+	// Block waits until the handler takes a message of the key, so a slow handler slows down the stream
+	Block Policy = iota
+	// DropNewest drops the message being dispatched
+	DropNewest
+	// DropOldest drops the oldest queued message of the key
+	DropOldest
+	// LatestWins keeps only the latest message of every key, for snapshots where an older message is useless once a
+	// newer one arrives (e.g. orderbook snapshots or oracle prices). The queue size is ignored
+	LatestWins
+)
+
+type Config struct {
+	// QueueSize is the number of messages queued per key
+	QueueSize int
+	// Workers is the number of keys handled at the same time
+	Workers int
+	Policy  Policy
+	// Priority orders the keys with queued messages waiting for a worker, higher first. Keys of the same priority are
+	// served in the order they became ready
+	Priority func(key string) int
+}
+
+// KeyStats are the queue metrics of a key
+type KeyStats struct {
+	Depth     int
+	MaxDepth  int
+	Delivered uint64
+	Dropped   uint64
+}
+
+// Stats are the queue metrics of all the keys
+type Stats struct {
+	Depth     int
+	Delivered uint64
+	Dropped   uint64
+	Keys      map[string]KeyStats
+}
+
+// Handler handles the messages of a key, one at a time and in the order they were dispatched
+type Handler[T any] func(ctx context.Context, key string, msg T)
+
+// Dispatcher delivers the messages of every key (e.g. a market id) to the handler in dispatch order, while messages
+// of different keys are handled in parallel. Every key has its own bounded queue, so a busy market does not delay the
+// others
+type Dispatcher[T any] struct {
+	config  Config
+	handler Handler[T]
+	logger  log.Logger
+
+	mux     sync.Mutex
+	changed *sync.Cond
+	queues  map[string]*keyQueue[T]
+	ready   readyKeys
+	seq     uint64
+	closed  bool
+}
+
+type keyQueue[T any] struct {
+	key      string
+	messages []T
+	running  bool
+	queued   bool
+	stats    KeyStats
+}
+
+func NewDispatcher[T any](config Config, handler Handler[T]) *Dispatcher[T] {
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	if config.Policy == LatestWins {
+		config.QueueSize = 1
+	}
+
+	d := &Dispatcher[T]{
+		config:  config,
+		handler: handler,
+		logger:  log.WithField("module", "dispatcher"),
+		queues:  make(map[string]*keyQueue[T]),
+	}
+	d.changed = sync.NewCond(&d.mux)
+	return d
+}
+
+// Dispatch queues the message for the key. With the Block policy it waits for room in the queue until the context is
+// done
+func (d *Dispatcher[T]) Dispatch(ctx context.Context, key string, msg T) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+	queue, found := d.queues[key]
+	if !found {
+		queue = &keyQueue[T]{key: key}
+		d.queues[key] = queue
+	}
+
+	if len(queue.messages) >= d.config.QueueSize {
+		switch d.config.Policy {
+		case DropNewest:
+			queue.stats.Dropped++
+			return nil
+		case DropOldest, LatestWins:
+			var zero T
+			queue.messages[0] = zero
+			queue.messages = queue.messages[1:]
+			queue.stats.Dropped++
+		default:
+			if err := d.waitForRoom(ctx, queue); err != nil {
+				return err
+			}
+		}
+	}
+
+	queue.messages = append(queue.messages, msg)
+	if len(queue.messages) > queue.stats.MaxDepth {
+		queue.stats.MaxDepth = len(queue.messages)
+	}
+	d.markReady(queue)
+	return nil
+}
+
+// waitForRoom waits, with the lock held, until the queue has room, the dispatcher is closed or the context is done
+func (d *Dispatcher[T]) waitForRoom(ctx context.Context, queue *keyQueue[T]) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			d.mux.Lock()
+			d.changed.Broadcast()
+			d.mux.Unlock()
+		case <-stop:
+		}
+	}()
+
+	for len(queue.messages) >= d.config.QueueSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.closed {
+			return ErrClosed
+		}
+		d.changed.Wait()
+	}
+	return nil
+}
+
+func (d *Dispatcher[T]) markReady(queue *keyQueue[T]) {
+	if queue.running || queue.queued || len(queue.messages) == 0 {
+		return
+	}
+	priority := 0
+	if d.config.Priority != nil {
+		priority = d.config.Priority(queue.key)
+	}
+	d.seq++
+	heap.Push(&d.ready, readyKey{key: queue.key, priority: priority, seq: d.seq})
+	queue.queued = true
+	d.changed.Broadcast()
+}
+
+// Run handles the messages with the configured number of workers until the context is done, or until the dispatcher
+// is closed and all the queued messages are handled
+func (d *Dispatcher[T]) Run(ctx context.Context) {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			d.mux.Lock()
+			d.changed.Broadcast()
+			d.mux.Unlock()
+		case <-stop:
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < d.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work takes one message at a time from the ready key with the highest priority. The key is given back to the ready
+// keys after every message, so the keys with many messages do not starve the others
+func (d *Dispatcher[T]) work(ctx context.Context) {
+	for {
+		d.mux.Lock()
+		for d.ready.Len() == 0 && ctx.Err() == nil && !(d.closed && d.idle()) {
+			d.changed.Wait()
+		}
+		if ctx.Err() != nil || d.ready.Len() == 0 {
+			d.mux.Unlock()
+			return
+		}
+
+		queue := d.queues[heap.Pop(&d.ready).(readyKey).key]
+		queue.queued = false
+		queue.running = true
+		msg := queue.messages[0]
+		var zero T
+		queue.messages[0] = zero
+		queue.messages = queue.messages[1:]
+		// a waiting Dispatch can queue its message
+		d.changed.Broadcast()
+		d.mux.Unlock()
+
+		d.handle(ctx, queue.key, msg)
+
+		d.mux.Lock()
+		queue.running = false
+		queue.stats.Delivered++
+		d.markReady(queue)
+		if d.closed && d.idle() {
+			d.changed.Broadcast()
+		}
+		d.mux.Unlock()
+	}
+}
+
+func (d *Dispatcher[T]) handle(ctx context.Context, key string, msg T) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.logger.WithField("key", key).Errorln("the message handler panicked:", r)
+		}
+	}()
+	d.handler(ctx, key, msg)
+}
+
+// idle is true if no message is queued or being handled
+func (d *Dispatcher[T]) idle() bool {
+	for _, queue := range d.queues {
+		if queue.running || len(queue.messages) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Close stops accepting messages. Run returns once the queued messages are handled
+func (d *Dispatcher[T]) Close() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.closed = true
+	d.changed.Broadcast()
+}
+
+func (d *Dispatcher[T]) Stats() Stats {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	stats := Stats{Keys: make(map[string]KeyStats, len(d.queues))}
+	for key, queue := range d.queues {
+		keyStats := queue.stats
+		keyStats.Depth = len(queue.messages)
+		stats.Keys[key] = keyStats
+		stats.Depth += keyStats.Depth
+		stats.Delivered += keyStats.Delivered
+		stats.Dropped += keyStats.Dropped
+	}
+	return stats
+}
+
+// Keys returns the keys dispatched so far, sorted
+func (d *Dispatcher[T]) Keys() []string {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	keys := make([]string, 0, len(d.queues))
+	for key := range d.queues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type readyKey struct {
+	key      string
+	priority int
+	seq      uint64
+}
+
+// readyKeys is a heap of the keys with queued messages and no worker, by priority and then readiness order
+type readyKeys []readyKey
+
+func (r readyKeys) Len() int { return len(r) }
+
+func (r readyKeys) Less(i, j int) bool {
+	if r[i].priority != r[j].priority {
+		return r[i].priority > r[j].priority
+	}
+	return r[i].seq < r[j].seq
+}
+
+func (r readyKeys) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+
+func (r *readyKeys) Push(x interface{}) { *r = append(*r, x.(readyKey)) }
+
+func (r *readyKeys) Pop() interface{} {
+	old := *r
+	item := old[len(old)-1]
+	*r = old[:len(old)-1]
+	return item
+}
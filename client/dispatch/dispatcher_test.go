@@ -0,0 +1,212 @@
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records the messages handled per key
+type recorder struct {
+	mux      sync.Mutex
+	keys     []string
+	messages map[string][]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{messages: make(map[string][]int)}
+}
+
+func (r *recorder) handle(_ context.Context, key string, msg int) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.keys = append(r.keys, key)
+	r.messages[key] = append(r.messages[key], msg)
+}
+
+// dispatchAll queues the messages of the key
+func dispatchAll(t *testing.T, d *Dispatcher[int], key string, messages ...int) {
+	for _, msg := range messages {
+		assert.NoError(t, d.Dispatch(context.Background(), key, msg))
+	}
+}
+
+// closeAndRun handles the queued messages
+func closeAndRun(d *Dispatcher[int]) {
+	d.Close()
+	d.Run(context.Background())
+}
+
+func TestDispatcherKeepsTheOrderPerKey(t *testing.T) {
+	const (
+		markets  = 20
+		messages = 200
+	)
+	var running, maxRunning int64
+	runningPerKey := make([]int64, markets)
+	handled := make([][]int, markets)
+
+	d := NewDispatcher(Config{QueueSize: 16, Workers: 4}, func(_ context.Context, key string, msg int) {
+		var market int
+		_, _ = fmt.Sscanf(key, "market-%d", &market)
+		assert.Equal(t, int64(1), atomic.AddInt64(&runningPerKey[market], 1), "a key is handled by one worker at a time")
+		if n := atomic.AddInt64(&running, 1); n > atomic.LoadInt64(&maxRunning) {
+			atomic.StoreInt64(&maxRunning, n)
+		}
+		time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+		handled[market] = append(handled[market], msg)
+		atomic.AddInt64(&running, -1)
+		atomic.AddInt64(&runningPerKey[market], -1)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		d.Run(context.Background())
+		close(done)
+	}()
+
+	var wg sync.WaitGroup
+	for market := 0; market < markets; market++ {
+		wg.Add(1)
+		go func(market int) {
+			defer wg.Done()
+			for msg := 0; msg < messages; msg++ {
+				assert.NoError(t, d.Dispatch(context.Background(), fmt.Sprintf("market-%d", market), msg))
+			}
+		}(market)
+	}
+	wg.Wait()
+	d.Close()
+	<-done
+
+	for market := range handled {
+		assert.Len(t, handled[market], messages)
+		for i, msg := range handled[market] {
+			if !assert.Equal(t, i, msg, "market-%d", market) {
+				break
+			}
+		}
+	}
+	assert.Greater(t, atomic.LoadInt64(&maxRunning), int64(1), "the markets are handled in parallel")
+
+	stats := d.Stats()
+	assert.Equal(t, uint64(markets*messages), stats.Delivered)
+	assert.Equal(t, uint64(0), stats.Dropped)
+	assert.Equal(t, 0, stats.Depth)
+	assert.LessOrEqual(t, stats.Keys["market-0"].MaxDepth, 16)
+	assert.Len(t, d.Keys(), markets)
+}
+
+func TestDispatcherPolicies(t *testing.T) {
+	tests := []struct {
+		policy    Policy
+		delivered []int
+		maxDepth  int
+	}{
+		{policy: DropNewest, delivered: []int{1, 2}, maxDepth: 2},
+		{policy: DropOldest, delivered: []int{4, 5}, maxDepth: 2},
+		{policy: LatestWins, delivered: []int{5}, maxDepth: 1},
+	}
+
+	for _, test := range tests {
+		rec := newRecorder()
+		d := NewDispatcher(Config{QueueSize: 2, Workers: 1, Policy: test.policy}, rec.handle)
+
+		dispatchAll(t, d, "orderbook", 1, 2, 3, 4, 5)
+		stats := d.Stats()
+		assert.Equal(t, len(test.delivered), stats.Depth)
+		assert.Equal(t, uint64(5-len(test.delivered)), stats.Dropped)
+		assert.Equal(t, test.maxDepth, stats.Keys["orderbook"].MaxDepth)
+
+		closeAndRun(d)
+		assert.Equal(t, test.delivered, rec.messages["orderbook"])
+		assert.Equal(t, uint64(len(test.delivered)), d.Stats().Keys["orderbook"].Delivered)
+	}
+}
+
+func TestDispatcherBlockPolicyWaitsForRoom(t *testing.T) {
+	rec := newRecorder()
+	d := NewDispatcher(Config{QueueSize: 1, Workers: 1}, rec.handle)
+	dispatchAll(t, d, "market", 1)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelFn()
+	assert.ErrorIs(t, d.Dispatch(ctx, "market", 2), context.DeadlineExceeded)
+	dispatchAll(t, d, "other-market", 1)
+
+	dispatched := make(chan error)
+	go func() {
+		dispatched <- d.Dispatch(context.Background(), "market", 3)
+	}()
+	select {
+	case <-dispatched:
+		t.Fatal("the dispatch did not wait for room in the queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	runCtx, stopRun := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(runCtx)
+		close(done)
+	}()
+	assert.NoError(t, <-dispatched)
+	d.Close()
+	<-done
+	stopRun()
+
+	assert.Equal(t, []int{1, 3}, rec.messages["market"])
+	assert.Equal(t, uint64(0), d.Stats().Dropped)
+}
+
+func TestDispatcherPriority(t *testing.T) {
+	rec := newRecorder()
+	d := NewDispatcher(Config{Workers: 1, Priority: func(key string) int {
+		if key == "btc" {
+			return 1
+		}
+		return 0
+	}}, rec.handle)
+
+	dispatchAll(t, d, "atom", 1, 2)
+	dispatchAll(t, d, "btc", 1, 2)
+	dispatchAll(t, d, "eth", 1)
+	closeAndRun(d)
+
+	assert.Equal(t, []string{"btc", "btc", "atom", "eth", "atom"}, rec.keys)
+}
+
+func TestDispatcherCloseAndCancel(t *testing.T) {
+	var handled int64
+	d := NewDispatcher(Config{Workers: 2}, func(_ context.Context, key string, msg int) {
+		atomic.AddInt64(&handled, 1)
+		if msg == 1 {
+			panic("invalid market")
+		}
+	})
+	dispatchAll(t, d, "market", 1, 2)
+	closeAndRun(d)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&handled), "a panicking handler does not stop the key")
+	assert.ErrorIs(t, d.Dispatch(context.Background(), "market", 3), ErrClosed)
+
+	d = NewDispatcher(Config{}, func(context.Context, string, int) {})
+	ctx, cancelFn := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	cancelFn()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return when the context was cancelled")
+	}
+}
@@ -0,0 +1,112 @@
+package dispatch
+
+import (
+	"context"
+	"io"
+
+	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
+)
+
+// AccountKey is the key of the stream updates not bound to a market: bank balances, subaccount deposits and oracle
+// prices
+const AccountKey = ""
+
+// Keyed is a message with the key it is delivered in order for
+type Keyed[T any] struct {
+	Key string
+	Msg T
+}
+
+// Pump receives the stream messages until recv fails, splits every message by key and dispatches the parts. It returns
+// nil at the end of the stream (io.EOF), and the first recv or dispatch error otherwise
+func Pump[M any, T any](ctx context.Context, recv func() (M, error), split func(M) []Keyed[T], d *Dispatcher[T]) error {
+	for {
+		msg, err := recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, keyed := range split(msg) {
+			if err := d.Dispatch(ctx, keyed.Key, keyed.Msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SplitByMarket splits a chain stream response in one response per market, with the block height and time of the
+// original. The updates not bound to a market go in a response with the AccountKey. The responses are in the order
+// their keys first appear
+func SplitByMarket(res *chainstreamtypes.StreamResponse) []Keyed[*chainstreamtypes.StreamResponse] {
+	var keys []string
+	parts := make(map[string]*chainstreamtypes.StreamResponse)
+	part := func(key string) *chainstreamtypes.StreamResponse {
+		if p, found := parts[key]; found {
+			return p
+		}
+		p := &chainstreamtypes.StreamResponse{BlockHeight: res.BlockHeight, BlockTime: res.BlockTime}
+		parts[key] = p
+		keys = append(keys, key)
+		return p
+	}
+
+	if len(res.BankBalances) > 0 || len(res.SubaccountDeposits) > 0 || len(res.OraclePrices) > 0 {
+		p := part(AccountKey)
+		p.BankBalances = res.BankBalances
+		p.SubaccountDeposits = res.SubaccountDeposits
+		p.OraclePrices = res.OraclePrices
+	}
+	for _, trade := range res.SpotTrades {
+		p := part(trade.MarketId)
+		p.SpotTrades = append(p.SpotTrades, trade)
+	}
+	for _, trade := range res.DerivativeTrades {
+		p := part(trade.MarketId)
+		p.DerivativeTrades = append(p.DerivativeTrades, trade)
+	}
+	for _, update := range res.SpotOrders {
+		var marketID string
+		if update.Order != nil {
+			marketID = update.Order.MarketId
+		}
+		p := part(marketID)
+		p.SpotOrders = append(p.SpotOrders, update)
+	}
+	for _, update := range res.DerivativeOrders {
+		var marketID string
+		if update.Order != nil {
+			marketID = update.Order.MarketId
+		}
+		p := part(marketID)
+		p.DerivativeOrders = append(p.DerivativeOrders, update)
+	}
+	for _, update := range res.SpotOrderbookUpdates {
+		var marketID string
+		if update.Orderbook != nil {
+			marketID = update.Orderbook.MarketId
+		}
+		p := part(marketID)
+		p.SpotOrderbookUpdates = append(p.SpotOrderbookUpdates, update)
+	}
+	for _, update := range res.DerivativeOrderbookUpdates {
+		var marketID string
+		if update.Orderbook != nil {
+			marketID = update.Orderbook.MarketId
+		}
+		p := part(marketID)
+		p.DerivativeOrderbookUpdates = append(p.DerivativeOrderbookUpdates, update)
+	}
+	for _, position := range res.Positions {
+		p := part(position.MarketId)
+		p.Positions = append(p.Positions, position)
+	}
+
+	split := make([]Keyed[*chainstreamtypes.StreamResponse], len(keys))
+	for i, key := range keys {
+		split[i] = Keyed[*chainstreamtypes.StreamResponse]{Key: key, Msg: parts[key]}
+	}
+	return split
+}
@@ -0,0 +1,97 @@
+package dispatch
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
+)
+
+func testStreamResponse(height uint64) *chainstreamtypes.StreamResponse {
+	return &chainstreamtypes.StreamResponse{
+		BlockHeight: height,
+		BlockTime:   int64(height) * 1000,
+		OraclePrices: []*chainstreamtypes.OraclePrice{
+			{Symbol: "INJ"},
+		},
+		SpotTrades: []*chainstreamtypes.SpotTrade{
+			{MarketId: "inj-usdt"},
+			{MarketId: "atom-usdt"},
+		},
+		SpotOrders: []*chainstreamtypes.SpotOrderUpdate{
+			{Order: &chainstreamtypes.SpotOrder{MarketId: "inj-usdt"}},
+		},
+		DerivativeOrderbookUpdates: []*chainstreamtypes.OrderbookUpdate{
+			{Seq: height, Orderbook: &chainstreamtypes.Orderbook{MarketId: "inj-perp"}},
+		},
+		Positions: []*chainstreamtypes.Position{
+			{MarketId: "inj-perp"},
+		},
+	}
+}
+
+func TestSplitByMarket(t *testing.T) {
+	split := SplitByMarket(testStreamResponse(10))
+
+	var keys []string
+	for _, keyed := range split {
+		keys = append(keys, keyed.Key)
+		assert.Equal(t, uint64(10), keyed.Msg.BlockHeight)
+		assert.Equal(t, int64(10000), keyed.Msg.BlockTime)
+	}
+	assert.Equal(t, []string{AccountKey, "inj-usdt", "atom-usdt", "inj-perp"}, keys)
+
+	assert.Len(t, split[0].Msg.OraclePrices, 1)
+	assert.Empty(t, split[0].Msg.SpotTrades)
+	assert.Len(t, split[1].Msg.SpotTrades, 1)
+	assert.Len(t, split[1].Msg.SpotOrders, 1)
+	assert.Len(t, split[2].Msg.SpotTrades, 1)
+	assert.Len(t, split[3].Msg.DerivativeOrderbookUpdates, 1)
+	assert.Len(t, split[3].Msg.Positions, 1)
+
+	assert.Empty(t, SplitByMarket(&chainstreamtypes.StreamResponse{BlockHeight: 11}))
+}
+
+func TestPumpDeliversTheBlocksInOrderPerMarket(t *testing.T) {
+	var mux sync.Mutex
+	heights := make(map[string][]uint64)
+	d := NewDispatcher(Config{Workers: 4}, func(_ context.Context, key string, res *chainstreamtypes.StreamResponse) {
+		mux.Lock()
+		defer mux.Unlock()
+		heights[key] = append(heights[key], res.BlockHeight)
+	})
+	done := make(chan struct{})
+	go func() {
+		d.Run(context.Background())
+		close(done)
+	}()
+
+	var height uint64
+	recv := func() (*chainstreamtypes.StreamResponse, error) {
+		if height == 50 {
+			return nil, io.EOF
+		}
+		height++
+		return testStreamResponse(height), nil
+	}
+	assert.NoError(t, Pump(context.Background(), recv, SplitByMarket, d))
+	d.Close()
+	<-done
+
+	for _, key := range []string{AccountKey, "inj-usdt", "atom-usdt", "inj-perp"} {
+		assert.Len(t, heights[key], 50, key)
+		for i, h := range heights[key] {
+			assert.Equal(t, uint64(i+1), h, key)
+		}
+	}
+
+	failing := func() (*chainstreamtypes.StreamResponse, error) {
+		return nil, errors.New("stream reset")
+	}
+	assert.EqualError(t, Pump(context.Background(), failing, SplitByMarket, d), "stream reset")
+}
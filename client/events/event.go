@@ -0,0 +1,173 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+)
+
+type EventType string
+
+const (
+	EventFill        EventType = "fill"
+	EventCancel      EventType = "cancel"
+	EventLiquidation EventType = "liquidation"
+	EventDeposit     EventType = "deposit"
+)
+
+// Event is the JSON document published to the sinks. Id is the same every time the event is published, so consumers
+// can drop the duplicates of the at-least-once delivery. Amounts are decimal strings in chain format
+type Event struct {
+	Id           string    `json:"id"`
+	Type         EventType `json:"type"`
+	SubaccountId string    `json:"subaccount_id"`
+	MarketId     string    `json:"market_id,omitempty"`
+	OrderHash    string    `json:"order_hash,omitempty"`
+	Cid          string    `json:"cid,omitempty"`
+	IsBuy        bool      `json:"is_buy,omitempty"`
+	Price        string    `json:"price,omitempty"`
+	Quantity     string    `json:"quantity,omitempty"`
+	Fee          string    `json:"fee,omitempty"`
+	Denom        string    `json:"denom,omitempty"`
+	// Amount is the change of the deposit total balance
+	Amount string    `json:"amount,omitempty"`
+	Height uint64    `json:"height,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// EventsFromStreamResponse returns the fill, liquidation and cancel events of a chain stream response. Derivative
+// trades with a liquidation execution type are reported as liquidations instead of fills
+func EventsFromStreamResponse(response *chainstreamtypes.StreamResponse) []Event {
+	blockTime := time.UnixMilli(response.BlockTime).UTC()
+	var events []Event
+
+	for _, trade := range response.SpotTrades {
+		orderHash := ethcommon.BytesToHash(trade.OrderHash).Hex()
+		events = append(events, Event{
+			Id:           tradeEventId(EventFill, trade.SubaccountId, trade.TradeId, response.BlockHeight, orderHash),
+			Type:         EventFill,
+			SubaccountId: trade.SubaccountId,
+			MarketId:     trade.MarketId,
+			OrderHash:    orderHash,
+			Cid:          trade.Cid,
+			IsBuy:        trade.IsBuy,
+			Price:        decString(trade.Price),
+			Quantity:     decString(trade.Quantity),
+			Fee:          decString(trade.Fee),
+			Height:       response.BlockHeight,
+			Time:         blockTime,
+		})
+	}
+
+	for _, trade := range response.DerivativeTrades {
+		eventType := EventFill
+		if trade.ExecutionType == exchangetypes.ExecutionType_MarketLiquidation.String() {
+			eventType = EventLiquidation
+		}
+		event := Event{
+			Id:           tradeEventId(eventType, trade.SubaccountId, trade.TradeId, response.BlockHeight, trade.OrderHash),
+			Type:         eventType,
+			SubaccountId: trade.SubaccountId,
+			MarketId:     trade.MarketId,
+			OrderHash:    trade.OrderHash,
+			Cid:          trade.Cid,
+			IsBuy:        trade.IsBuy,
+			Fee:          decString(trade.Fee),
+			Height:       response.BlockHeight,
+			Time:         blockTime,
+		}
+		if trade.PositionDelta != nil {
+			event.Price = decString(trade.PositionDelta.ExecutionPrice)
+			event.Quantity = decString(trade.PositionDelta.ExecutionQuantity)
+		}
+		events = append(events, event)
+	}
+
+	for _, update := range response.SpotOrders {
+		if update.Status != chainstreamtypes.OrderUpdateStatus_Cancelled || update.Order == nil {
+			continue
+		}
+		events = append(events, cancelEvent(update.Order.MarketId, update.OrderHash, update.Cid, update.Order.Order.OrderInfo, update.Order.Order.IsBuy(), response.BlockHeight, blockTime))
+	}
+	for _, update := range response.DerivativeOrders {
+		if update.Status != chainstreamtypes.OrderUpdateStatus_Cancelled || update.Order == nil {
+			continue
+		}
+		events = append(events, cancelEvent(update.Order.MarketId, update.OrderHash, update.Cid, update.Order.Order.OrderInfo, update.Order.Order.IsBuy(), response.BlockHeight, blockTime))
+	}
+
+	return events
+}
+
+// EventFromAccountDelta converts the deposit changes of the account watcher to deposit events, and its position
+// liquidations to liquidation events. The other changes have no event
+func EventFromAccountDelta(delta chain.AccountDeltaEvent) (Event, bool) {
+	idSuffix := fmt.Sprintf("%s/%d/%d", delta.SubaccountId, delta.Height, delta.ObservedAt.UnixNano())
+
+	switch delta.Type {
+	case chain.DepositCredited, chain.DepositDebited:
+		return Event{
+			Id:           fmt.Sprintf("%s/%s/%s", EventDeposit, delta.Denom, idSuffix),
+			Type:         EventDeposit,
+			SubaccountId: delta.SubaccountId,
+			Denom:        delta.Denom,
+			Amount:       decString(delta.TotalBalanceDelta),
+			Height:       delta.Height,
+			Time:         delta.ObservedAt.UTC(),
+		}, true
+	case chain.PositionLiquidated:
+		event := Event{
+			Id:           fmt.Sprintf("%s/%s/%s", EventLiquidation, delta.MarketId, idSuffix),
+			Type:         EventLiquidation,
+			SubaccountId: delta.SubaccountId,
+			MarketId:     delta.MarketId,
+			Quantity:     decString(delta.QuantityDelta.Abs()),
+			Height:       delta.Height,
+			Time:         delta.ObservedAt.UTC(),
+		}
+		if delta.PreviousPosition != nil {
+			// the liquidation closes the position, so it trades on the other side
+			event.IsBuy = !delta.PreviousPosition.IsLong
+		}
+		return event, true
+	default:
+		return Event{}, false
+	}
+}
+
+func cancelEvent(marketId string, orderHashBytes []byte, cid string, orderInfo exchangetypes.OrderInfo, isBuy bool, height uint64, blockTime time.Time) Event {
+	orderHash := ethcommon.BytesToHash(orderHashBytes).Hex()
+	return Event{
+		Id:           fmt.Sprintf("%s/%s", EventCancel, orderHash),
+		Type:         EventCancel,
+		SubaccountId: orderInfo.SubaccountId,
+		MarketId:     marketId,
+		OrderHash:    orderHash,
+		Cid:          cid,
+		IsBuy:        isBuy,
+		Price:        decString(orderInfo.Price),
+		Quantity:     decString(orderInfo.Quantity),
+		Height:       height,
+		Time:         blockTime,
+	}
+}
+
+func tradeEventId(eventType EventType, subaccountId string, tradeId string, height uint64, orderHash string) string {
+	if tradeId != "" {
+		return fmt.Sprintf("%s/%s/%s", eventType, subaccountId, tradeId)
+	}
+	return fmt.Sprintf("%s/%s/%d/%s", eventType, subaccountId, height, orderHash)
+}
+
+func decString(value sdk.Dec) string {
+	if value.IsNil() {
+		return ""
+	}
+	return value.String()
+}
@@ -0,0 +1,131 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	chainstreamtypes "github.com/InjectiveLabs/sdk-go/chain/stream/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+)
+
+const (
+	testSubaccountId = "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000"
+	testMarketId     = "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+)
+
+func TestEventsFromStreamResponse(t *testing.T) {
+	orderHash := ethcommon.HexToHash("0x01")
+	response := &chainstreamtypes.StreamResponse{
+		BlockHeight: 100,
+		BlockTime:   1700000000000,
+		SpotTrades: []*chainstreamtypes.SpotTrade{{
+			MarketId:     testMarketId,
+			IsBuy:        true,
+			SubaccountId: testSubaccountId,
+			Price:        sdk.MustNewDecFromStr("1.5"),
+			Quantity:     sdk.MustNewDecFromStr("10"),
+			Fee:          sdk.MustNewDecFromStr("0.01"),
+			OrderHash:    orderHash.Bytes(),
+			TradeId:      "100_0_0",
+		}},
+		DerivativeTrades: []*chainstreamtypes.DerivativeTrade{{
+			MarketId:      testMarketId,
+			ExecutionType: exchangetypes.ExecutionType_MarketLiquidation.String(),
+			SubaccountId:  testSubaccountId,
+			PositionDelta: &exchangetypes.PositionDelta{
+				ExecutionPrice:    sdk.MustNewDecFromStr("20"),
+				ExecutionQuantity: sdk.MustNewDecFromStr("2"),
+				ExecutionMargin:   sdk.ZeroDec(),
+			},
+			Fee:       sdk.ZeroDec(),
+			Payout:    sdk.ZeroDec(),
+			OrderHash: orderHash.Hex(),
+		}},
+		SpotOrders: []*chainstreamtypes.SpotOrderUpdate{
+			{Status: chainstreamtypes.OrderUpdateStatus_Booked, OrderHash: orderHash.Bytes()},
+			{
+				Status:    chainstreamtypes.OrderUpdateStatus_Cancelled,
+				OrderHash: orderHash.Bytes(),
+				Cid:       "my-order",
+				Order: &chainstreamtypes.SpotOrder{
+					MarketId: testMarketId,
+					Order: exchangetypes.SpotLimitOrder{
+						OrderInfo: exchangetypes.OrderInfo{
+							SubaccountId: testSubaccountId,
+							Price:        sdk.MustNewDecFromStr("1.4"),
+							Quantity:     sdk.MustNewDecFromStr("5"),
+						},
+						OrderType: exchangetypes.OrderType_SELL,
+					},
+				},
+			},
+		},
+	}
+
+	events := EventsFromStreamResponse(response)
+
+	assert.Len(t, events, 3)
+	blockTime := time.UnixMilli(1700000000000).UTC()
+	assert.Equal(t, Event{
+		Id:           "fill/" + testSubaccountId + "/100_0_0",
+		Type:         EventFill,
+		SubaccountId: testSubaccountId,
+		MarketId:     testMarketId,
+		OrderHash:    orderHash.Hex(),
+		IsBuy:        true,
+		Price:        "1.500000000000000000",
+		Quantity:     "10.000000000000000000",
+		Fee:          "0.010000000000000000",
+		Height:       100,
+		Time:         blockTime,
+	}, events[0])
+
+	assert.Equal(t, EventLiquidation, events[1].Type)
+	assert.Equal(t, "liquidation/"+testSubaccountId+"/100/"+orderHash.Hex(), events[1].Id)
+	assert.Equal(t, "20.000000000000000000", events[1].Price)
+	assert.Equal(t, "2.000000000000000000", events[1].Quantity)
+
+	assert.Equal(t, EventCancel, events[2].Type)
+	assert.Equal(t, "cancel/"+orderHash.Hex(), events[2].Id)
+	assert.Equal(t, "my-order", events[2].Cid)
+	assert.False(t, events[2].IsBuy)
+	assert.Equal(t, "5.000000000000000000", events[2].Quantity)
+}
+
+func TestEventFromAccountDelta(t *testing.T) {
+	observedAt := time.Unix(1700000000, 0)
+
+	deposit, found := EventFromAccountDelta(chain.AccountDeltaEvent{
+		Type:              chain.DepositCredited,
+		SubaccountId:      testSubaccountId,
+		Denom:             "inj",
+		TotalBalanceDelta: sdk.MustNewDecFromStr("3"),
+		Height:            10,
+		ObservedAt:        observedAt,
+	})
+	assert.True(t, found)
+	assert.Equal(t, EventDeposit, deposit.Type)
+	assert.Equal(t, "inj", deposit.Denom)
+	assert.Equal(t, "3.000000000000000000", deposit.Amount)
+
+	liquidation, found := EventFromAccountDelta(chain.AccountDeltaEvent{
+		Type:             chain.PositionLiquidated,
+		SubaccountId:     testSubaccountId,
+		MarketId:         testMarketId,
+		QuantityDelta:    sdk.MustNewDecFromStr("-2"),
+		PreviousPosition: &exchangetypes.Position{IsLong: true},
+		ObservedAt:       observedAt,
+	})
+	assert.True(t, found)
+	assert.Equal(t, EventLiquidation, liquidation.Type)
+	assert.Equal(t, "2.000000000000000000", liquidation.Quantity)
+	assert.False(t, liquidation.IsBuy)
+
+	_, found = EventFromAccountDelta(chain.AccountDeltaEvent{Type: chain.PositionOpened})
+	assert.False(t, found)
+}
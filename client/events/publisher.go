@@ -0,0 +1,257 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const (
+	defaultQueueSize        = 1024
+	defaultBatchSize        = 100
+	defaultRetryInterval    = time.Second
+	defaultMaxRetryInterval = 30 * time.Second
+)
+
+// Sink receives the published events. Send must return an error if the events may not have been delivered, the
+// events are then sent again. Errors created with NewPermanentError drop the events instead
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, events []Event) error
+}
+
+type sinkFunc struct {
+	name string
+	send func(ctx context.Context, events []Event) error
+}
+
+// NewSinkFunc creates a sink from a function, e.g. to publish the events with the Kafka or NATS producer of the
+// application
+func NewSinkFunc(name string, send func(ctx context.Context, events []Event) error) Sink {
+	return &sinkFunc{name: name, send: send}
+}
+
+func (s *sinkFunc) Name() string {
+	return s.name
+}
+
+func (s *sinkFunc) Send(ctx context.Context, events []Event) error {
+	return s.send(ctx, events)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// NewPermanentError marks a sink error that sending the events again would not fix (e.g. a rejected payload)
+func NewPermanentError(err error) error {
+	return &permanentError{err: err}
+}
+
+func IsPermanentError(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+type PublisherConfig struct {
+	// QueueSize is the number of events queued for each sink (1024 by default). Publish blocks while a sink queue is
+	// full
+	QueueSize int
+	// BatchSize is the maximum number of events sent to a sink at once (100 by default)
+	BatchSize int
+	// RetryInterval is the wait before sending failed events again (1 second by default). It doubles after every
+	// failure, up to MaxRetryInterval (30 seconds by default)
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+	// Clock of the retry waits, the real clock by default
+	Clock clock.Clock
+}
+
+type sinkQueue struct {
+	sink   Sink
+	events chan Event
+}
+
+// Publisher delivers the events to every sink at least once: events are sent in order, and a batch is sent again
+// until the sink accepts it. Each sink has its own queue, so a failing sink does not delay the others until its queue
+// is full; Publish then blocks, applying backpressure to the event source. Delivery is in memory: events still queued
+// when Run returns are not delivered
+type Publisher struct {
+	config PublisherConfig
+	queues []*sinkQueue
+	logger log.Logger
+
+	mux       sync.Mutex
+	delivered map[string]uint64
+	dropped   map[string]uint64
+}
+
+func NewPublisher(config PublisherConfig, sinks ...Sink) *Publisher {
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaultRetryInterval
+	}
+	if config.MaxRetryInterval < config.RetryInterval {
+		config.MaxRetryInterval = defaultMaxRetryInterval
+		if config.MaxRetryInterval < config.RetryInterval {
+			config.MaxRetryInterval = config.RetryInterval
+		}
+	}
+
+	config.Clock = clock.OrReal(config.Clock)
+
+	queues := make([]*sinkQueue, 0, len(sinks))
+	for _, sink := range sinks {
+		queues = append(queues, &sinkQueue{sink: sink, events: make(chan Event, config.QueueSize)})
+	}
+
+	return &Publisher{
+		config:    config,
+		queues:    queues,
+		logger:    log.WithField("module", "event-publisher"),
+		delivered: make(map[string]uint64),
+		dropped:   make(map[string]uint64),
+	}
+}
+
+// Publish queues the events for all the sinks, waiting while a queue is full. If the context is done first, the
+// events already queued for some sinks are still delivered to them
+func (p *Publisher) Publish(ctx context.Context, events ...Event) error {
+	for _, event := range events {
+		for _, queue := range p.queues {
+			select {
+			case queue.events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// Pending returns the number of events queued for each sink, by sink name
+func (p *Publisher) Pending() map[string]int {
+	pending := make(map[string]int, len(p.queues))
+	for _, queue := range p.queues {
+		pending[queue.sink.Name()] += len(queue.events)
+	}
+	return pending
+}
+
+// Delivered returns the number of events accepted by each sink, by sink name
+func (p *Publisher) Delivered() map[string]uint64 {
+	return p.counters(p.delivered)
+}
+
+// Dropped returns the number of events dropped after a permanent error of each sink, by sink name
+func (p *Publisher) Dropped() map[string]uint64 {
+	return p.counters(p.dropped)
+}
+
+// Run delivers the queued events to the sinks until the context is done
+func (p *Publisher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, queue := range p.queues {
+		wg.Add(1)
+		go func(queue *sinkQueue) {
+			defer wg.Done()
+			p.runSink(ctx, queue)
+		}(queue)
+	}
+	wg.Wait()
+}
+
+func (p *Publisher) runSink(ctx context.Context, queue *sinkQueue) {
+	logger := p.logger.WithField("sink", queue.sink.Name())
+	batch := make([]Event, 0, p.config.BatchSize)
+
+	for {
+		select {
+		case event := <-queue.events:
+			batch = append(batch[:0], event)
+		case <-ctx.Done():
+			return
+		}
+	fill:
+		for len(batch) < p.config.BatchSize {
+			select {
+			case event := <-queue.events:
+				batch = append(batch, event)
+			default:
+				break fill
+			}
+		}
+
+		if !p.send(ctx, logger, queue.sink, batch) {
+			return
+		}
+	}
+}
+
+// send sends the batch until the sink accepts it or returns a permanent error, and returns false if the context is
+// done first
+func (p *Publisher) send(ctx context.Context, logger log.Logger, sink Sink, batch []Event) bool {
+	retryInterval := p.config.RetryInterval
+	for {
+		err := sink.Send(ctx, batch)
+		if err == nil {
+			p.count(p.delivered, sink.Name(), len(batch))
+			return true
+		}
+		if IsPermanentError(err) {
+			logger.WithError(err).WithField("events", len(batch)).Errorln("dropping the events rejected by the sink")
+			p.count(p.dropped, sink.Name(), len(batch))
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		logger.WithError(err).WithField("retry_in", retryInterval).Warningln("failed to send the events")
+		select {
+		case <-p.config.Clock.After(retryInterval):
+		case <-ctx.Done():
+			return false
+		}
+		retryInterval *= 2
+		if retryInterval > p.config.MaxRetryInterval {
+			retryInterval = p.config.MaxRetryInterval
+		}
+	}
+}
+
+func (p *Publisher) count(counters map[string]uint64, name string, events int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	counters[name] += uint64(events)
+}
+
+func (p *Publisher) counters(counters map[string]uint64) map[string]uint64 {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	result := make(map[string]uint64, len(counters))
+	for name, count := range counters {
+		result[name] = count
+	}
+	return result
+}
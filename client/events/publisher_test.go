@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	name string
+
+	mux      sync.Mutex
+	failures int
+	err      error
+	batches  [][]Event
+}
+
+func (s *recordingSink) Name() string {
+	return s.name
+}
+
+func (s *recordingSink) Send(_ context.Context, events []Event) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return s.err
+	}
+	s.batches = append(s.batches, append([]Event(nil), events...))
+	return nil
+}
+
+func (s *recordingSink) eventIds() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var ids []string
+	for _, batch := range s.batches {
+		for _, event := range batch {
+			ids = append(ids, event.Id)
+		}
+	}
+	return ids
+}
+
+func testEvents(ids ...string) []Event {
+	events := make([]Event, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, Event{Id: id, Type: EventFill})
+	}
+	return events
+}
+
+func runPublisher(t *testing.T, publisher *Publisher) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		publisher.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestPublisherDeliversTheEventsToEverySinkInOrder(t *testing.T) {
+	first := &recordingSink{name: "first"}
+	second := &recordingSink{name: "second"}
+	publisher := NewPublisher(PublisherConfig{BatchSize: 2}, first, second)
+
+	assert.NoError(t, publisher.Publish(context.Background(), testEvents("a", "b", "c")...))
+	runPublisher(t, publisher)
+
+	assert.Eventually(t, func() bool { return len(second.eventIds()) == 3 && len(first.eventIds()) == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"a", "b", "c"}, first.eventIds())
+	assert.Equal(t, []string{"a", "b", "c"}, second.eventIds())
+	for _, batch := range first.batches {
+		assert.LessOrEqual(t, len(batch), 2)
+	}
+	assert.Equal(t, map[string]uint64{"first": 3, "second": 3}, publisher.Delivered())
+}
+
+func TestPublisherSendsTheFailedBatchesAgain(t *testing.T) {
+	sink := &recordingSink{name: "flaky", failures: 2, err: errors.New("connection refused")}
+	publisher := NewPublisher(PublisherConfig{RetryInterval: time.Millisecond}, sink)
+
+	assert.NoError(t, publisher.Publish(context.Background(), testEvents("a", "b")...))
+	runPublisher(t, publisher)
+
+	assert.Eventually(t, func() bool { return len(sink.eventIds()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"a", "b"}, sink.eventIds())
+	assert.Empty(t, publisher.Dropped())
+}
+
+func TestPublisherDropsTheEventsAfterAPermanentError(t *testing.T) {
+	sink := &recordingSink{name: "strict", failures: 1, err: NewPermanentError(errors.New("bad payload"))}
+	publisher := NewPublisher(PublisherConfig{BatchSize: 1, RetryInterval: time.Millisecond}, sink)
+
+	assert.NoError(t, publisher.Publish(context.Background(), testEvents("a", "b")...))
+	runPublisher(t, publisher)
+
+	assert.Eventually(t, func() bool { return len(sink.eventIds()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"b"}, sink.eventIds())
+	assert.Equal(t, map[string]uint64{"strict": 1}, publisher.Dropped())
+}
+
+func TestPublishBlocksWhileASinkQueueIsFull(t *testing.T) {
+	sink := &recordingSink{name: "slow"}
+	publisher := NewPublisher(PublisherConfig{QueueSize: 2}, sink)
+
+	assert.NoError(t, publisher.Publish(context.Background(), testEvents("a", "b")...))
+	assert.Equal(t, map[string]int{"slow": 2}, publisher.Pending())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := publisher.Publish(ctx, testEvents("c")...)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	runPublisher(t, publisher)
+	assert.NoError(t, publisher.Publish(context.Background(), testEvents("c")...))
+	assert.Eventually(t, func() bool { return len(sink.eventIds()) == 3 }, time.Second, time.Millisecond)
+}
+
+func TestIsPermanentErrorFindsWrappedErrors(t *testing.T) {
+	err := errors.Wrap(NewPermanentError(errors.New("rejected")), "sink failed")
+
+	assert.True(t, IsPermanentError(err))
+	assert.False(t, IsPermanentError(errors.New("timeout")))
+	assert.Equal(t, "sink failed: rejected", err.Error())
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWebhookTimeout = 10 * time.Second
+	// SignatureHeader has the hex HMAC-SHA256 of the request body when the webhook has a secret
+	SignatureHeader = "X-Injective-Signature"
+)
+
+type WebhookConfig struct {
+	URL string
+	// Headers are added to every request, e.g. for authentication
+	Headers http.Header
+	// Secret signs the request bodies (see SignatureHeader) if set
+	Secret []byte
+	// Timeout of the requests (10 seconds by default)
+	Timeout time.Duration
+}
+
+// WebhookSink posts the events to an HTTP endpoint, as a JSON array. 2xx responses accept the events; 4xx responses
+// other than 408 and 429 are permanent errors, and the other failures are retried
+type WebhookSink struct {
+	config WebhookConfig
+	client *http.Client
+}
+
+func NewWebhookSink(config WebhookConfig) *WebhookSink {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultWebhookTimeout
+	}
+
+	return &WebhookSink{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook " + s.config.URL
+}
+
+func (s *WebhookSink) Send(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return NewPermanentError(errors.Wrap(err, "failed to encode the events"))
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return NewPermanentError(errors.Wrap(err, "failed to create the webhook request"))
+	}
+	for name, values := range s.config.Headers {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if len(s.config.Secret) > 0 {
+		mac := hmac.New(sha256.New, s.config.Secret)
+		mac.Write(body)
+		request.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post the events to %s", s.config.URL)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	err = errors.Errorf("the webhook %s answered %s", s.config.URL, response.Status)
+	if response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusRequestTimeout && response.StatusCode != http.StatusTooManyRequests {
+		return NewPermanentError(err)
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSinkPostsSignedEvents(t *testing.T) {
+	var body []byte
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	secret := []byte("secret")
+	sink := NewWebhookSink(WebhookConfig{URL: server.URL, Secret: secret, Headers: http.Header{"Authorization": {"Bearer token"}}})
+
+	err := sink.Send(context.Background(), testEvents("a", "b"))
+
+	assert.NoError(t, err)
+	var received []Event
+	assert.NoError(t, json.Unmarshal(body, &received))
+	assert.Equal(t, []string{"a", "b"}, []string{received[0].Id, received[1].Id})
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), headers.Get(SignatureHeader))
+}
+
+func TestWebhookSinkErrors(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	sink := NewWebhookSink(WebhookConfig{URL: server.URL})
+
+	err := sink.Send(context.Background(), testEvents("a"))
+	assert.Error(t, err)
+	assert.False(t, IsPermanentError(err))
+
+	status = http.StatusTooManyRequests
+	err = sink.Send(context.Background(), testEvents("a"))
+	assert.Error(t, err)
+	assert.False(t, IsPermanentError(err))
+
+	status = http.StatusBadRequest
+	err = sink.Send(context.Background(), testEvents("a"))
+	assert.Error(t, err)
+	assert.True(t, IsPermanentError(err))
+}
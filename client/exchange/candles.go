@@ -0,0 +1,204 @@
+package exchange
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/sdk-go/client/core"
+	derivativeExchangePB "github.com/InjectiveLabs/sdk-go/exchange/derivative_exchange_rpc/pb"
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+// tradesPageLimit is the number of trades requested in each page when building candles
+const tradesPageLimit = 100
+
+// TradeTick is a trade execution in human readable values
+type TradeTick struct {
+	ExecutedAt time.Time
+	Price      decimal.Decimal
+	Quantity   decimal.Decimal
+}
+
+// Candle is an OHLCV bar. BaseVolume is the traded quantity and QuoteVolume the traded notional
+type Candle struct {
+	OpenTime    time.Time
+	Interval    time.Duration
+	Open        decimal.Decimal
+	High        decimal.Decimal
+	Low         decimal.Decimal
+	Close       decimal.Decimal
+	BaseVolume  decimal.Decimal
+	QuoteVolume decimal.Decimal
+	TradesCount int
+}
+
+// CloseTime returns the end (exclusive) of the candle interval
+func (c Candle) CloseTime() time.Time {
+	return c.OpenTime.Add(c.Interval)
+}
+
+// ResampleTrades aggregates the trades into candles of the given interval, aligned to the Unix epoch.
+// Intervals without trades do not produce a candle. The candles are sorted by open time
+func ResampleTrades(trades []TradeTick, interval time.Duration) ([]Candle, error) {
+	if interval <= 0 {
+		return nil, errors.Errorf("invalid candle interval %s", interval)
+	}
+
+	sortedTrades := make([]TradeTick, len(trades))
+	copy(sortedTrades, trades)
+	sort.SliceStable(sortedTrades, func(i, j int) bool {
+		return sortedTrades[i].ExecutedAt.Before(sortedTrades[j].ExecutedAt)
+	})
+
+	var candles []Candle
+	for _, trade := range sortedTrades {
+		openTime := trade.ExecutedAt.Truncate(interval)
+		notional := trade.Price.Mul(trade.Quantity)
+
+		if len(candles) == 0 || !candles[len(candles)-1].OpenTime.Equal(openTime) {
+			candles = append(candles, Candle{
+				OpenTime:    openTime,
+				Interval:    interval,
+				Open:        trade.Price,
+				High:        trade.Price,
+				Low:         trade.Price,
+				Close:       trade.Price,
+				BaseVolume:  trade.Quantity,
+				QuoteVolume: notional,
+				TradesCount: 1,
+			})
+			continue
+		}
+
+		candle := &candles[len(candles)-1]
+		if trade.Price.GreaterThan(candle.High) {
+			candle.High = trade.Price
+		}
+		if trade.Price.LessThan(candle.Low) {
+			candle.Low = trade.Price
+		}
+		candle.Close = trade.Price
+		candle.BaseVolume = candle.BaseVolume.Add(trade.Quantity)
+		candle.QuoteVolume = candle.QuoteVolume.Add(notional)
+		candle.TradesCount++
+	}
+
+	return candles, nil
+}
+
+func parseChainValue(value string, fieldName string) (cosmtypes.Dec, error) {
+	dec, err := cosmtypes.NewDecFromStr(value)
+	if err != nil {
+		return cosmtypes.Dec{}, errors.Wrapf(err, "invalid trade %s %q", fieldName, value)
+	}
+	return dec, nil
+}
+
+// SpotTradeTick converts a spot trade from the exchange API into human readable values
+func SpotTradeTick(trade *spotExchangePB.SpotTrade, market core.SpotMarket) (TradeTick, error) {
+	if trade.GetPrice() == nil {
+		return TradeTick{}, errors.Errorf("trade %s has no price", trade.GetTradeId())
+	}
+	price, err := parseChainValue(trade.Price.Price, "price")
+	if err != nil {
+		return TradeTick{}, err
+	}
+	quantity, err := parseChainValue(trade.Price.Quantity, "quantity")
+	if err != nil {
+		return TradeTick{}, err
+	}
+
+	return TradeTick{
+		ExecutedAt: time.UnixMilli(trade.ExecutedAt),
+		Price:      market.PriceFromChainFormat(price),
+		Quantity:   market.QuantityFromChainFormat(quantity),
+	}, nil
+}
+
+// DerivativeTradeTick converts a derivative trade from the exchange API into human readable values
+func DerivativeTradeTick(trade *derivativeExchangePB.DerivativeTrade, market core.DerivativeMarket) (TradeTick, error) {
+	if trade.GetPositionDelta() == nil {
+		return TradeTick{}, errors.Errorf("trade %s has no position delta", trade.GetTradeId())
+	}
+	price, err := parseChainValue(trade.PositionDelta.ExecutionPrice, "price")
+	if err != nil {
+		return TradeTick{}, err
+	}
+	quantity, err := parseChainValue(trade.PositionDelta.ExecutionQuantity, "quantity")
+	if err != nil {
+		return TradeTick{}, err
+	}
+
+	return TradeTick{
+		ExecutedAt: time.UnixMilli(trade.ExecutedAt),
+		Price:      market.PriceFromChainFormat(price),
+		Quantity:   market.QuantityFromChainFormat(quantity),
+	}, nil
+}
+
+func (c *exchangeClient) FetchSpotCandles(ctx context.Context, market core.SpotMarket, interval time.Duration, startTime time.Time, endTime time.Time) ([]Candle, error) {
+	var ticks []TradeTick
+	for skip := uint64(0); ; skip += tradesPageLimit {
+		// each fill is reported once for each side, only the buy side is requested to count it once
+		res, err := c.GetSpotTrades(ctx, &spotExchangePB.TradesRequest{
+			MarketId:  market.Id,
+			Direction: "buy",
+			Skip:      skip,
+			Limit:     tradesPageLimit,
+			StartTime: startTime.UnixMilli(),
+			EndTime:   endTime.UnixMilli(),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the spot trades of market %s", market.Id)
+		}
+
+		for _, trade := range res.Trades {
+			tick, err := SpotTradeTick(trade, market)
+			if err != nil {
+				return nil, err
+			}
+			ticks = append(ticks, tick)
+		}
+		if len(res.Trades) < tradesPageLimit {
+			break
+		}
+	}
+
+	return ResampleTrades(ticks, interval)
+}
+
+func (c *exchangeClient) FetchDerivativeCandles(ctx context.Context, market core.DerivativeMarket, interval time.Duration, startTime time.Time, endTime time.Time) ([]Candle, error) {
+	var ticks []TradeTick
+	for skip := uint64(0); ; skip += tradesPageLimit {
+		// each fill is reported once for each side, only the buy side is requested to count it once
+		res, err := c.GetDerivativeTrades(ctx, &derivativeExchangePB.TradesRequest{
+			MarketId:  market.Id,
+			Direction: "buy",
+			Skip:      skip,
+			Limit:     tradesPageLimit,
+			StartTime: startTime.UnixMilli(),
+			EndTime:   endTime.UnixMilli(),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the derivative trades of market %s", market.Id)
+		}
+
+		for _, trade := range res.Trades {
+			tick, err := DerivativeTradeTick(trade, market)
+			if err != nil {
+				return nil, err
+			}
+			ticks = append(ticks, tick)
+		}
+		if len(res.Trades) < tradesPageLimit {
+			break
+		}
+	}
+
+	return ResampleTrades(ticks, interval)
+}
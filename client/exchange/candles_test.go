@@ -0,0 +1,76 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/core"
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+func tick(executedAt time.Time, price string, quantity string) TradeTick {
+	return TradeTick{
+		ExecutedAt: executedAt,
+		Price:      decimal.RequireFromString(price),
+		Quantity:   decimal.RequireFromString(quantity),
+	}
+}
+
+func TestResampleTradesBuildsAlignedCandles(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	trades := []TradeTick{
+		tick(start.Add(70*time.Second), "12", "1"),
+		tick(start.Add(5*time.Second), "10", "2"),
+		tick(start.Add(10*time.Second), "11", "1"),
+		tick(start.Add(15*time.Second), "9", "1"),
+	}
+
+	candles, err := ResampleTrades(trades, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, candles, 2)
+
+	first := candles[0]
+	assert.Equal(t, start.Truncate(time.Minute), first.OpenTime)
+	assert.Equal(t, "10", first.Open.String())
+	assert.Equal(t, "11", first.High.String())
+	assert.Equal(t, "9", first.Low.String())
+	assert.Equal(t, "9", first.Close.String())
+	assert.Equal(t, "4", first.BaseVolume.String())
+	assert.Equal(t, "40", first.QuoteVolume.String())
+	assert.Equal(t, 3, first.TradesCount)
+	assert.Equal(t, first.OpenTime.Add(time.Minute), first.CloseTime())
+
+	assert.Equal(t, "12", candles[1].Open.String())
+	assert.Equal(t, 1, candles[1].TradesCount)
+
+	candles, err = ResampleTrades(trades, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, candles, 4)
+
+	_, err = ResampleTrades(trades, 0)
+	assert.Error(t, err)
+}
+
+func TestSpotTradeTickUsesHumanReadableValues(t *testing.T) {
+	market := core.SpotMarket{
+		Id:         "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe",
+		BaseToken:  core.Token{Symbol: "INJ", Decimals: 18},
+		QuoteToken: core.Token{Symbol: "USDT", Decimals: 6},
+	}
+	trade := &spotExchangePB.SpotTrade{
+		Price:      &spotExchangePB.PriceLevel{Price: "0.000000000012", Quantity: "2000000000000000000"},
+		ExecutedAt: 1700000000123,
+	}
+
+	tradeTick, err := SpotTradeTick(trade, market)
+	assert.NoError(t, err)
+	assert.Equal(t, "12", tradeTick.Price.String())
+	assert.Equal(t, "2", tradeTick.Quantity.String())
+	assert.Equal(t, int64(1700000000123), tradeTick.ExecutedAt.UnixMilli())
+
+	_, err = SpotTradeTick(&spotExchangePB.SpotTrade{Price: &spotExchangePB.PriceLevel{Price: "abc", Quantity: "1"}}, market)
+	assert.Error(t, err)
+}
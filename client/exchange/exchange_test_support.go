@@ -3,7 +3,9 @@ package exchange
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/InjectiveLabs/sdk-go/client/core"
 	accountPB "github.com/InjectiveLabs/sdk-go/exchange/accounts_rpc/pb"
 	auctionPB "github.com/InjectiveLabs/sdk-go/exchange/auction_rpc/pb"
 	derivativeExchangePB "github.com/InjectiveLabs/sdk-go/exchange/derivative_exchange_rpc/pb"
@@ -239,6 +241,14 @@ func (e *MockExchangeClient) GetSpotTrades(ctx context.Context, req *spotExchang
 	return &spotExchangePB.TradesResponse{}, nil
 }
 
+func (e *MockExchangeClient) FetchSpotCandles(ctx context.Context, market core.SpotMarket, interval time.Duration, startTime time.Time, endTime time.Time) ([]Candle, error) {
+	return []Candle{}, nil
+}
+
+func (e *MockExchangeClient) FetchDerivativeCandles(ctx context.Context, market core.DerivativeMarket, interval time.Duration, startTime time.Time, endTime time.Time) ([]Candle, error) {
+	return []Candle{}, nil
+}
+
 func (e *MockExchangeClient) GetSpotTradesV2(ctx context.Context, req *spotExchangePB.TradesV2Request) (*spotExchangePB.TradesV2Response, error) {
 	return &spotExchangePB.TradesV2Response{}, nil
 }
@@ -0,0 +1,339 @@
+package execution
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+// ParentOrder is the order split by an algo into child orders. Prices and quantities are human readable
+type ParentOrder struct {
+	MarketId string
+	IsBuy    bool
+	Quantity decimal.Decimal
+	// LimitPrice is the worst price accepted for the child orders, zero means no limit. Iceberg child orders rest at
+	// the limit price, so they require it
+	LimitPrice decimal.Decimal
+	// QuantityTickSize rounds down the child order quantities. Zero disables the rounding
+	QuantityTickSize decimal.Decimal
+}
+
+// ChildOrder is a limit order placed by an algo
+type ChildOrder struct {
+	MarketId string
+	IsBuy    bool
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// OrderPlacer places and cancels the child orders (see ChainOrderPlacer for the chain implementation)
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, order ChildOrder) (orderHash string, err error)
+	CancelOrder(ctx context.Context, marketId string, orderHash string) error
+}
+
+// OrderbookMirror provides the top of book of the markets, kept up to date from the orderbook streams
+type OrderbookMirror interface {
+	BestBidAndAsk(marketId string) (bestBid decimal.Decimal, bestAsk decimal.Decimal, found bool)
+}
+
+// FillTracker provides the quantity filled of the child orders, kept up to date from the trades streams
+type FillTracker interface {
+	FilledQuantity(orderHash string) decimal.Decimal
+}
+
+// VolumeTracker provides the cumulative traded volume of the markets, used by POV algos
+type VolumeTracker interface {
+	TradedVolume(marketId string) decimal.Decimal
+}
+
+// Progress is the execution state of an algo
+type Progress struct {
+	Filled      decimal.Decimal
+	Remaining   decimal.Decimal
+	ChildOrders int
+	Paused      bool
+	Done        bool
+}
+
+// Callbacks are optional functions notified of the algo execution
+type Callbacks struct {
+	OnChildOrder func(order ChildOrder, orderHash string)
+	// OnComplete is called once, when the parent order is filled or Run returns with an error
+	OnComplete func(progress Progress, err error)
+}
+
+type childOrder struct {
+	orderHash string
+	quantity  decimal.Decimal
+	active    bool
+}
+
+// childCancelTimeout bounds the cancel of the active child order when Run stops
+const childCancelTimeout = 10 * time.Second
+
+// sizer returns the quantity of the next child order, given the parent quantity not filled yet
+type sizer func(remaining decimal.Decimal) decimal.Decimal
+
+// Algo executes a parent order placing child orders on every tick. TWAP and POV algos cancel the unfilled part of
+// the previous child order before placing the next one; iceberg algos wait until the displayed child order is filled
+type Algo struct {
+	name      string
+	parent    ParentOrder
+	placer    OrderPlacer
+	orderbook OrderbookMirror
+	fills     FillTracker
+	callbacks Callbacks
+	interval  time.Duration
+	// replaceEachTick cancels the unfilled previous child order on every tick
+	replaceEachTick bool
+	// usesTouchPrice takes the child order price from the top of book instead of the limit price
+	usesTouchPrice bool
+	nextSize       sizer
+	clock          clock.Clock
+	logger         log.Logger
+
+	// stepMux serializes the steps, so mux can be released while the child orders are placed and cancelled
+	stepMux      sync.Mutex
+	mux          sync.Mutex
+	paused       bool
+	children     []childOrder
+	completeOnce sync.Once
+}
+
+func newAlgo(name string, parent ParentOrder, placer OrderPlacer, orderbook OrderbookMirror, fills FillTracker, interval time.Duration, callbacks Callbacks) (*Algo, error) {
+	if !parent.Quantity.IsPositive() {
+		return nil, errors.Errorf("the parent order quantity must be positive, got %s", parent.Quantity.String())
+	}
+	if interval <= 0 {
+		return nil, errors.Errorf("the %s interval must be positive", name)
+	}
+	return &Algo{
+		name:           name,
+		parent:         parent,
+		placer:         placer,
+		orderbook:      orderbook,
+		fills:          fills,
+		callbacks:      callbacks,
+		interval:       interval,
+		usesTouchPrice: true,
+		clock:          clock.Real(),
+		logger:         log.WithField("module", "execution").WithField("algo", name),
+	}, nil
+}
+
+// SetClock sets the clock of the algo interval, before calling Run
+func (a *Algo) SetClock(c clock.Clock) {
+	a.clock = clock.OrReal(c)
+}
+
+// Run executes the algo until the parent order is filled or ctx is done. The active child order is cancelled when ctx
+// is done, so no order of the algo is left in the book
+func (a *Algo) Run(ctx context.Context) error {
+	ticker := a.clock.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Step(ctx); err != nil {
+			a.logger.WithError(err).Warningln("failed to place child order")
+		}
+		if a.Progress().Done {
+			a.complete(nil)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err := a.cancelActiveChild(); err != nil {
+				a.logger.WithError(err).Warningln("failed to cancel the active child order")
+			}
+			a.complete(ctx.Err())
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// Pause stops placing child orders. The active child order is left in the book
+func (a *Algo) Pause() {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.paused = true
+}
+
+func (a *Algo) Resume() {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.paused = false
+}
+
+func (a *Algo) Progress() Progress {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	return a.progress()
+}
+
+// Step runs one tick of the algo: it cancels or waits for the previous child order and places the next one. Run
+// calls it on every interval, it is exported to drive the algo from an external scheduler
+func (a *Algo) Step(ctx context.Context) error {
+	a.stepMux.Lock()
+	defer a.stepMux.Unlock()
+
+	a.mux.Lock()
+	progress := a.progress()
+	if progress.Done || a.paused {
+		a.mux.Unlock()
+		return nil
+	}
+
+	if active := a.activeChild(); active != nil {
+		unfilled := active.quantity.Sub(a.fills.FilledQuantity(active.orderHash))
+		if unfilled.IsPositive() {
+			if !a.replaceEachTick {
+				a.mux.Unlock()
+				return nil
+			}
+			orderHash := active.orderHash
+			a.mux.Unlock()
+			if err := a.placer.CancelOrder(ctx, a.parent.MarketId, orderHash); err != nil {
+				return errors.Wrapf(err, "failed to cancel child order %s", orderHash)
+			}
+			a.mux.Lock()
+		}
+		// the children are only changed by the steps, so active is still the last child
+		active.active = false
+		progress = a.progress()
+	}
+
+	size := a.roundQuantity(decimal.Min(a.nextSize(progress.Remaining), progress.Remaining))
+	if !size.IsPositive() {
+		a.mux.Unlock()
+		return nil
+	}
+	price, err := a.childPrice()
+	a.mux.Unlock()
+	if err != nil {
+		return err
+	}
+
+	order := ChildOrder{MarketId: a.parent.MarketId, IsBuy: a.parent.IsBuy, Price: price, Quantity: size}
+	orderHash, err := a.placer.PlaceOrder(ctx, order)
+	if err != nil {
+		return errors.Wrap(err, "failed to place child order")
+	}
+
+	a.mux.Lock()
+	a.children = append(a.children, childOrder{orderHash: orderHash, quantity: size, active: true})
+	a.mux.Unlock()
+	if a.callbacks.OnChildOrder != nil {
+		a.callbacks.OnChildOrder(order, orderHash)
+	}
+	return nil
+}
+
+// cancelActiveChild cancels the unfilled part of the active child order. It uses its own timeout because it is
+// called when the Run ctx is already done
+func (a *Algo) cancelActiveChild() error {
+	a.stepMux.Lock()
+	defer a.stepMux.Unlock()
+
+	a.mux.Lock()
+	active := a.activeChild()
+	if active == nil || !active.quantity.Sub(a.fills.FilledQuantity(active.orderHash)).IsPositive() {
+		a.mux.Unlock()
+		return nil
+	}
+	orderHash := active.orderHash
+	a.mux.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), childCancelTimeout)
+	defer cancel()
+	if err := a.placer.CancelOrder(ctx, a.parent.MarketId, orderHash); err != nil {
+		return errors.Wrapf(err, "failed to cancel child order %s", orderHash)
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	active.active = false
+	return nil
+}
+
+// progress must be called holding the mutex
+func (a *Algo) progress() Progress {
+	filled := decimal.Zero
+	for _, child := range a.children {
+		filled = filled.Add(a.fills.FilledQuantity(child.orderHash))
+	}
+	remaining := a.parent.Quantity.Sub(filled)
+	if remaining.IsNegative() {
+		remaining = decimal.Zero
+	}
+	// the remaining quantity can be lower than the tick size after the rounding of the child orders
+	done := !remaining.IsPositive() || !a.roundQuantity(remaining).IsPositive()
+
+	return Progress{
+		Filled:      filled,
+		Remaining:   remaining,
+		ChildOrders: len(a.children),
+		Paused:      a.paused,
+		Done:        done,
+	}
+}
+
+func (a *Algo) activeChild() *childOrder {
+	if len(a.children) == 0 || !a.children[len(a.children)-1].active {
+		return nil
+	}
+	return &a.children[len(a.children)-1]
+}
+
+func (a *Algo) childPrice() (decimal.Decimal, error) {
+	if !a.usesTouchPrice {
+		if !a.parent.LimitPrice.IsPositive() {
+			return decimal.Zero, errors.Errorf("the %s algo requires a limit price", a.name)
+		}
+		return a.parent.LimitPrice, nil
+	}
+
+	bestBid, bestAsk, found := a.orderbook.BestBidAndAsk(a.parent.MarketId)
+	price := bestBid
+	if a.parent.IsBuy {
+		price = bestAsk
+	}
+	if !found || !price.IsPositive() {
+		return decimal.Zero, errors.Errorf("there is no top of book for market %s", a.parent.MarketId)
+	}
+
+	if a.parent.LimitPrice.IsPositive() {
+		if a.parent.IsBuy {
+			price = decimal.Min(price, a.parent.LimitPrice)
+		} else {
+			price = decimal.Max(price, a.parent.LimitPrice)
+		}
+	}
+	return price, nil
+}
+
+func (a *Algo) roundQuantity(quantity decimal.Decimal) decimal.Decimal {
+	if !a.parent.QuantityTickSize.IsPositive() {
+		return quantity
+	}
+	return quantity.Div(a.parent.QuantityTickSize).Floor().Mul(a.parent.QuantityTickSize)
+}
+
+func (a *Algo) complete(err error) {
+	a.completeOnce.Do(func() {
+		if a.callbacks.OnComplete != nil {
+			a.callbacks.OnComplete(a.Progress(), err)
+		}
+	})
+}
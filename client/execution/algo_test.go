@@ -0,0 +1,260 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMarket struct {
+	mux        sync.Mutex
+	placed     []ChildOrder
+	hashes     []string
+	cancelled  []string
+	filled     map[string]decimal.Decimal
+	volume     decimal.Decimal
+	fillOnPost bool
+	// onPlace is called before placing every order, without holding the market mutex
+	onPlace func()
+}
+
+func newTestMarket() *testMarket {
+	return &testMarket{filled: make(map[string]decimal.Decimal)}
+}
+
+func (m *testMarket) PlaceOrder(ctx context.Context, order ChildOrder) (string, error) {
+	if m.onPlace != nil {
+		m.onPlace()
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	orderHash := fmt.Sprintf("0x%02d", len(m.placed)+1)
+	m.placed = append(m.placed, order)
+	m.hashes = append(m.hashes, orderHash)
+	if m.fillOnPost {
+		m.filled[orderHash] = order.Quantity
+	}
+	return orderHash, nil
+}
+
+func (m *testMarket) CancelOrder(ctx context.Context, marketId string, orderHash string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.cancelled = append(m.cancelled, orderHash)
+	return nil
+}
+
+func (m *testMarket) BestBidAndAsk(marketId string) (decimal.Decimal, decimal.Decimal, bool) {
+	return decimal.RequireFromString("9.9"), decimal.RequireFromString("10.1"), true
+}
+
+func (m *testMarket) FilledQuantity(orderHash string) decimal.Decimal {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.filled[orderHash]
+}
+
+func (m *testMarket) TradedVolume(marketId string) decimal.Decimal {
+	return m.volume
+}
+
+func (m *testMarket) fill(orderHash string, quantity string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.filled[orderHash] = decimal.RequireFromString(quantity)
+}
+
+func testParentOrder(quantity string) ParentOrder {
+	return ParentOrder{
+		MarketId:         "0x01",
+		IsBuy:            true,
+		Quantity:         decimal.RequireFromString(quantity),
+		QuantityTickSize: decimal.RequireFromString("0.1"),
+	}
+}
+
+func TestTWAPSplitsParentOrderInSlices(t *testing.T) {
+	market := newTestMarket()
+	var childOrders []string
+	parent := testParentOrder("9")
+	parent.LimitPrice = decimal.RequireFromString("10")
+	algo, err := NewTWAP(parent, TWAPConfig{Duration: 3 * time.Minute, Slices: 3}, market, market, market, Callbacks{
+		OnChildOrder: func(order ChildOrder, orderHash string) { childOrders = append(childOrders, orderHash) },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, algo.interval)
+
+	ctx := context.Background()
+	assert.NoError(t, algo.Step(ctx))
+	assert.Equal(t, "3", market.placed[0].Quantity.String())
+	assert.Equal(t, "10", market.placed[0].Price.String())
+
+	market.fill("0x01", "1")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Equal(t, []string{"0x01"}, market.cancelled)
+	assert.Equal(t, "4", market.placed[1].Quantity.String())
+
+	market.fill("0x02", "4")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Equal(t, "4", market.placed[2].Quantity.String())
+	assert.Len(t, market.cancelled, 1)
+
+	market.fill("0x03", "4")
+	progress := algo.Progress()
+	assert.True(t, progress.Done)
+	assert.Equal(t, "9", progress.Filled.String())
+	assert.Equal(t, []string{"0x01", "0x02", "0x03"}, childOrders)
+
+	_, err = NewTWAP(parent, TWAPConfig{Duration: time.Minute}, market, market, market, Callbacks{})
+	assert.Error(t, err)
+}
+
+func TestPOVFollowsMarketVolume(t *testing.T) {
+	market := newTestMarket()
+	parent := testParentOrder("5")
+	parent.IsBuy = false
+	algo, err := NewPOV(parent, POVConfig{
+		Interval:          time.Second,
+		ParticipationRate: decimal.RequireFromString("0.1"),
+		MinChildQuantity:  decimal.RequireFromString("0.5"),
+	}, market, market, market, market, Callbacks{})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	market.volume = decimal.RequireFromString("100")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Empty(t, market.placed)
+
+	market.volume = decimal.RequireFromString("103")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Empty(t, market.placed)
+
+	market.volume = decimal.RequireFromString("123")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Len(t, market.placed, 1)
+	assert.Equal(t, "2", market.placed[0].Quantity.String())
+	assert.Equal(t, "9.9", market.placed[0].Price.String())
+
+	market.fill("0x01", "2")
+	market.volume = decimal.RequireFromString("223")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Equal(t, "3", market.placed[1].Quantity.String())
+	assert.Empty(t, market.cancelled)
+
+	_, err = NewPOV(parent, POVConfig{Interval: time.Second, ParticipationRate: decimal.RequireFromString("2")}, market, market, market, market, Callbacks{})
+	assert.Error(t, err)
+}
+
+func TestIcebergShowsDisplayQuantity(t *testing.T) {
+	market := newTestMarket()
+	parent := testParentOrder("5")
+	parent.LimitPrice = decimal.RequireFromString("9.5")
+	algo, err := NewIceberg(parent, IcebergConfig{DisplayQuantity: decimal.RequireFromString("2"), PollInterval: time.Second}, market, market, Callbacks{})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, algo.Step(ctx))
+	assert.NoError(t, algo.Step(ctx))
+	assert.Len(t, market.placed, 1)
+	assert.Equal(t, "9.5", market.placed[0].Price.String())
+
+	market.fill("0x01", "1")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Len(t, market.placed, 1)
+
+	algo.Pause()
+	market.fill("0x01", "2")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Len(t, market.placed, 1)
+	assert.True(t, algo.Progress().Paused)
+
+	algo.Resume()
+	assert.NoError(t, algo.Step(ctx))
+	assert.Len(t, market.placed, 2)
+	market.fill("0x02", "2")
+	assert.NoError(t, algo.Step(ctx))
+	assert.Equal(t, "1", market.placed[2].Quantity.String())
+	assert.Empty(t, market.cancelled)
+
+	parent.LimitPrice = decimal.Zero
+	_, err = NewIceberg(parent, IcebergConfig{DisplayQuantity: decimal.RequireFromString("2"), PollInterval: time.Second}, market, market, Callbacks{})
+	assert.Error(t, err)
+}
+
+func TestAlgoRunCallsOnComplete(t *testing.T) {
+	market := newTestMarket()
+	market.fillOnPost = true
+	parent := testParentOrder("3")
+	parent.LimitPrice = decimal.RequireFromString("9.5")
+
+	completed := make(chan Progress, 1)
+	algo, err := NewIceberg(parent, IcebergConfig{DisplayQuantity: decimal.RequireFromString("1"), PollInterval: time.Millisecond}, market, market, Callbacks{
+		OnComplete: func(progress Progress, err error) {
+			assert.NoError(t, err)
+			completed <- progress
+		},
+	})
+	assert.NoError(t, err)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+	assert.NoError(t, algo.Run(ctx))
+
+	progress := <-completed
+	assert.True(t, progress.Done)
+	assert.Equal(t, 3, progress.ChildOrders)
+}
+
+func TestAlgoIsNotLockedWhilePlacingOrders(t *testing.T) {
+	market := newTestMarket()
+	parent := testParentOrder("3")
+	parent.LimitPrice = decimal.RequireFromString("9.5")
+	algo, err := NewIceberg(parent, IcebergConfig{DisplayQuantity: decimal.RequireFromString("1"), PollInterval: time.Second}, market, market, Callbacks{})
+	assert.NoError(t, err)
+
+	// the placer can take long, the algo state is still available meanwhile
+	market.onPlace = func() {
+		algo.Pause()
+		assert.Equal(t, 0, algo.Progress().ChildOrders)
+	}
+	assert.NoError(t, algo.Step(context.Background()))
+	assert.Len(t, market.placed, 1)
+	assert.True(t, algo.Progress().Paused)
+}
+
+func TestAlgoRunCancelsActiveChildWhenStopped(t *testing.T) {
+	market := newTestMarket()
+	parent := testParentOrder("3")
+	parent.LimitPrice = decimal.RequireFromString("9.5")
+
+	var completeErr error
+	algo, err := NewIceberg(parent, IcebergConfig{DisplayQuantity: decimal.RequireFromString("1"), PollInterval: time.Millisecond}, market, market, Callbacks{
+		OnComplete: func(progress Progress, err error) { completeErr = err },
+	})
+	assert.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	market.onPlace = cancelFn
+	assert.ErrorIs(t, algo.Run(ctx), context.Canceled)
+	assert.ErrorIs(t, completeErr, context.Canceled)
+	assert.Equal(t, []string{"0x01"}, market.cancelled)
+
+	// filled child orders are not cancelled
+	market.onPlace = nil
+	market.fillOnPost = true
+	algo, err = NewIceberg(parent, IcebergConfig{DisplayQuantity: decimal.RequireFromString("1"), PollInterval: time.Hour}, market, market, Callbacks{})
+	assert.NoError(t, err)
+	ctx, cancelFn = context.WithCancel(context.Background())
+	cancelFn()
+	assert.ErrorIs(t, algo.Run(ctx), context.Canceled)
+	assert.Equal(t, []string{"0x01"}, market.cancelled)
+}
@@ -0,0 +1,102 @@
+package execution
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// TWAPConfig splits the parent order in Slices child orders of the same size, one every Duration / Slices
+type TWAPConfig struct {
+	Duration time.Duration
+	Slices   int
+}
+
+func NewTWAP(parent ParentOrder, config TWAPConfig, placer OrderPlacer, orderbook OrderbookMirror, fills FillTracker, callbacks Callbacks) (*Algo, error) {
+	if config.Slices <= 0 {
+		return nil, errors.Errorf("the number of TWAP slices must be positive, got %d", config.Slices)
+	}
+	algo, err := newAlgo("twap", parent, placer, orderbook, fills, config.Duration/time.Duration(config.Slices), callbacks)
+	if err != nil {
+		return nil, err
+	}
+
+	algo.replaceEachTick = true
+	algo.nextSize = func(remaining decimal.Decimal) decimal.Decimal {
+		slicesLeft := config.Slices - len(algo.children)
+		if slicesLeft <= 1 {
+			return remaining
+		}
+		return remaining.Div(decimal.NewFromInt(int64(slicesLeft)))
+	}
+	return algo, nil
+}
+
+// POVConfig sizes every child order as ParticipationRate (e.g. 0.1 for 10%) of the market volume traded since the
+// previous tick. No child order is placed on the ticks where that size is below MinChildQuantity, and the volume of
+// those ticks is not carried over to the next ones
+type POVConfig struct {
+	Interval          time.Duration
+	ParticipationRate decimal.Decimal
+	MinChildQuantity  decimal.Decimal
+}
+
+func NewPOV(parent ParentOrder, config POVConfig, placer OrderPlacer, orderbook OrderbookMirror, fills FillTracker, volumes VolumeTracker, callbacks Callbacks) (*Algo, error) {
+	if !config.ParticipationRate.IsPositive() || config.ParticipationRate.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, errors.Errorf("the POV participation rate must be greater than 0 and at most 1, got %s", config.ParticipationRate.String())
+	}
+	algo, err := newAlgo("pov", parent, placer, orderbook, fills, config.Interval, callbacks)
+	if err != nil {
+		return nil, err
+	}
+
+	var mux sync.Mutex
+	var lastVolume *decimal.Decimal
+	algo.replaceEachTick = true
+	algo.nextSize = func(remaining decimal.Decimal) decimal.Decimal {
+		mux.Lock()
+		defer mux.Unlock()
+
+		volume := volumes.TradedVolume(parent.MarketId)
+		if lastVolume == nil {
+			// the first tick only takes the volume reference
+			lastVolume = &volume
+			return decimal.Zero
+		}
+		size := volume.Sub(*lastVolume).Mul(config.ParticipationRate)
+		lastVolume = &volume
+		if size.LessThan(config.MinChildQuantity) {
+			return decimal.Zero
+		}
+		return size
+	}
+	return algo, nil
+}
+
+// IcebergConfig shows at most DisplayQuantity of the parent order at the limit price, placing the next child order
+// when the displayed one is filled. PollInterval is how often the fills are checked
+type IcebergConfig struct {
+	DisplayQuantity decimal.Decimal
+	PollInterval    time.Duration
+}
+
+func NewIceberg(parent ParentOrder, config IcebergConfig, placer OrderPlacer, fills FillTracker, callbacks Callbacks) (*Algo, error) {
+	if !config.DisplayQuantity.IsPositive() {
+		return nil, errors.Errorf("the iceberg display quantity must be positive, got %s", config.DisplayQuantity.String())
+	}
+	if !parent.LimitPrice.IsPositive() {
+		return nil, errors.New("iceberg orders require a limit price")
+	}
+	algo, err := newAlgo("iceberg", parent, placer, nil, fills, config.PollInterval, callbacks)
+	if err != nil {
+		return nil, err
+	}
+
+	algo.usesTouchPrice = false
+	algo.nextSize = func(remaining decimal.Decimal) decimal.Decimal {
+		return config.DisplayQuantity
+	}
+	return algo, nil
+}
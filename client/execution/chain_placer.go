@@ -0,0 +1,122 @@
+package execution
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+)
+
+// ChainOrderPlacer places the child orders as limit orders with the chain client, from the default subaccount of the
+// client sender. Orders in derivative markets are created with Leverage
+type ChainOrderPlacer struct {
+	chainClient      chain.ChainClient
+	marketsAssistant chain.MarketsAssistant
+	tracker          *chain.OrderTracker
+	Leverage         decimal.Decimal
+	FeeRecipient     string
+}
+
+// NewChainOrderPlacer creates the placer. The tracker is optional; if set, the child orders are tracked in it
+func NewChainOrderPlacer(chainClient chain.ChainClient, marketsAssistant chain.MarketsAssistant, tracker *chain.OrderTracker) *ChainOrderPlacer {
+	return &ChainOrderPlacer{
+		chainClient:      chainClient,
+		marketsAssistant: marketsAssistant,
+		tracker:          tracker,
+		Leverage:         decimal.NewFromInt(1),
+		FeeRecipient:     chainClient.FromAddress().String(),
+	}
+}
+
+func (p *ChainOrderPlacer) PlaceOrder(ctx context.Context, order ChildOrder) (string, error) {
+	subaccountId := p.chainClient.DefaultSubaccount(p.chainClient.FromAddress())
+	sender := p.chainClient.FromAddress().String()
+	orderType := exchangetypes.OrderType_SELL
+	if order.IsBuy {
+		orderType = exchangetypes.OrderType_BUY
+	}
+
+	composer := chain.NewTxComposer(p.chainClient)
+	var spotOrder *exchangetypes.SpotOrder
+	var derivativeOrder *exchangetypes.DerivativeOrder
+	if _, isSpot := p.marketsAssistant.AllSpotMarkets()[order.MarketId]; isSpot {
+		spotOrder = p.chainClient.CreateSpotOrder(subaccountId, &chain.SpotOrderData{
+			OrderType:    orderType,
+			Price:        order.Price,
+			Quantity:     order.Quantity,
+			FeeRecipient: p.FeeRecipient,
+			MarketId:     order.MarketId,
+		}, p.marketsAssistant)
+		composer.Add(&exchangetypes.MsgCreateSpotLimitOrder{Sender: sender, Order: *spotOrder})
+	} else if _, isDerivative := p.marketsAssistant.AllDerivativeMarkets()[order.MarketId]; isDerivative {
+		derivativeOrder = p.chainClient.CreateDerivativeOrder(subaccountId, &chain.DerivativeOrderData{
+			OrderType:    orderType,
+			Price:        order.Price,
+			Quantity:     order.Quantity,
+			Leverage:     p.Leverage,
+			FeeRecipient: p.FeeRecipient,
+			MarketId:     order.MarketId,
+		}, p.marketsAssistant)
+		composer.Add(&exchangetypes.MsgCreateDerivativeLimitOrder{Sender: sender, Order: *derivativeOrder})
+	} else {
+		return "", errors.Errorf("market %s not found", order.MarketId)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	result, err := composer.Broadcast()
+	if err != nil {
+		return "", err
+	}
+	if result.Err != nil {
+		return "", result.Err
+	}
+
+	switch response := result.Results[0].Response.(type) {
+	case *exchangetypes.MsgCreateSpotLimitOrderResponse:
+		if p.tracker != nil {
+			p.tracker.TrackSpotOrder(response.OrderHash, spotOrder)
+		}
+		return response.OrderHash, nil
+	case *exchangetypes.MsgCreateDerivativeLimitOrderResponse:
+		if p.tracker != nil {
+			p.tracker.TrackDerivativeOrder(response.OrderHash, derivativeOrder)
+		}
+		return response.OrderHash, nil
+	default:
+		return "", errors.Errorf("the order creation tx %s has no order hash", result.TxHash)
+	}
+}
+
+func (p *ChainOrderPlacer) CancelOrder(ctx context.Context, marketId string, orderHash string) error {
+	subaccountId := p.chainClient.DefaultSubaccount(p.chainClient.FromAddress()).Hex()
+	sender := p.chainClient.FromAddress().String()
+
+	var msg sdk.Msg = &exchangetypes.MsgCancelDerivativeOrder{Sender: sender, MarketId: marketId, SubaccountId: subaccountId, OrderHash: orderHash}
+	if _, isSpot := p.marketsAssistant.AllSpotMarkets()[marketId]; isSpot {
+		msg = &exchangetypes.MsgCancelSpotOrder{Sender: sender, MarketId: marketId, SubaccountId: subaccountId, OrderHash: orderHash}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	composer := chain.NewTxComposer(p.chainClient)
+	composer.Add(msg)
+	result, err := composer.Broadcast()
+	if err != nil {
+		return err
+	}
+	// the order may have been filled since the last check, which is not a failure for the algos
+	if result.Err != nil && !errors.Is(result.Err, exchangetypes.ErrOrderDoesntExist) {
+		return result.Err
+	}
+	if p.tracker != nil {
+		p.tracker.Remove(orderHash)
+	}
+	return nil
+}
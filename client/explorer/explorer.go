@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"google.golang.org/grpc/credentials/insecure"
-
 	"github.com/InjectiveLabs/sdk-go/client/common"
 	explorerPB "github.com/InjectiveLabs/sdk-go/exchange/explorer_rpc/pb"
 	"google.golang.org/grpc/metadata"
@@ -22,6 +20,10 @@ type ExplorerClient interface {
 	GetBlock(ctx context.Context, blockHeight string) (*explorerPB.GetBlockResponse, error)
 	GetBlocks(ctx context.Context) (*explorerPB.GetBlocksResponse, error)
 	GetAccountTxs(ctx context.Context, req *explorerPB.GetAccountTxsRequest) (*explorerPB.GetAccountTxsResponse, error)
+	GetContractTxs(ctx context.Context, req *explorerPB.GetContractTxsRequest) (*explorerPB.GetContractTxsResponse, error)
+	GetValidators(ctx context.Context) (*explorerPB.GetValidatorsResponse, error)
+	GetValidator(ctx context.Context, address string) (*explorerPB.GetValidatorResponse, error)
+	GetValidatorUptime(ctx context.Context, address string) (*explorerPB.GetValidatorUptimeResponse, error)
 	GetPeggyDeposits(ctx context.Context, req *explorerPB.GetPeggyDepositTxsRequest) (*explorerPB.GetPeggyDepositTxsResponse, error)
 	GetPeggyWithdrawals(ctx context.Context, req *explorerPB.GetPeggyWithdrawalTxsRequest) (*explorerPB.GetPeggyWithdrawalTxsResponse, error)
 	GetIBCTransfers(ctx context.Context, req *explorerPB.GetIBCTransferTxsRequest) (*explorerPB.GetIBCTransferTxsResponse, error)
@@ -32,6 +34,8 @@ type ExplorerClient interface {
 	GetWasmContracts(ctx context.Context, req *explorerPB.GetWasmContractsRequest) (*explorerPB.GetWasmContractsResponse, error)
 	GetWasmContractByAddress(ctx context.Context, req *explorerPB.GetWasmContractByAddressRequest) (*explorerPB.GetWasmContractByAddressResponse, error)
 	GetCW20Balance(ctx context.Context, req *explorerPB.GetCw20BalanceRequest) (*explorerPB.GetCw20BalanceResponse, error)
+	GetRelayers(ctx context.Context, req *explorerPB.RelayersRequest) (*explorerPB.RelayersResponse, error)
+	GetBankTransfers(ctx context.Context, req *explorerPB.GetBankTransfersRequest) (*explorerPB.GetBankTransfersResponse, error)
 	Close()
 }
 
@@ -51,11 +55,7 @@ func NewExplorerClient(network common.Network, options ...common.ClientOption) (
 	// create grpc client
 	var conn *grpc.ClientConn
 	var err error
-	if opts.TLSCert != nil {
-		conn, err = grpc.Dial(network.ExplorerGrpcEndpoint, grpc.WithTransportCredentials(opts.TLSCert), grpc.WithContextDialer(common.DialerFunc))
-	} else {
-		conn, err = grpc.Dial(network.ExplorerGrpcEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(common.DialerFunc))
-	}
+	conn, err = grpc.Dial(network.ExplorerGrpcEndpoint, common.GrpcDialOptions(opts)...)
 	if err != nil {
 		err := errors.Wrapf(err, "failed to connect to the gRPC: %s", network.ExplorerGrpcEndpoint)
 		return nil, err
@@ -132,6 +132,17 @@ func (c *explorerClient) GetAccountTxs(ctx context.Context, req *explorerPB.GetA
 	return res, nil
 }
 
+func (c *explorerClient) GetContractTxs(ctx context.Context, req *explorerPB.GetContractTxsRequest) (*explorerPB.GetContractTxsResponse, error) {
+	ctx = c.getCookie(ctx)
+	res, err := c.explorerClient.GetContractTxs(ctx, req)
+	if err != nil {
+		fmt.Println(err)
+		return &explorerPB.GetContractTxsResponse{}, err
+	}
+
+	return res, nil
+}
+
 func (c *explorerClient) GetBlocks(ctx context.Context) (*explorerPB.GetBlocksResponse, error) {
 	req := explorerPB.GetBlocksRequest{}
 
@@ -160,6 +171,49 @@ func (c *explorerClient) GetBlock(ctx context.Context, blockHeight string) (*exp
 	return res, nil
 }
 
+func (c *explorerClient) GetValidators(ctx context.Context) (*explorerPB.GetValidatorsResponse, error) {
+	req := explorerPB.GetValidatorsRequest{}
+
+	ctx = c.getCookie(ctx)
+	res, err := c.explorerClient.GetValidators(ctx, &req)
+	if err != nil {
+		fmt.Println(err)
+		return &explorerPB.GetValidatorsResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (c *explorerClient) GetValidator(ctx context.Context, address string) (*explorerPB.GetValidatorResponse, error) {
+	req := explorerPB.GetValidatorRequest{
+		Address: address,
+	}
+
+	ctx = c.getCookie(ctx)
+	res, err := c.explorerClient.GetValidator(ctx, &req)
+	if err != nil {
+		fmt.Println(err)
+		return &explorerPB.GetValidatorResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (c *explorerClient) GetValidatorUptime(ctx context.Context, address string) (*explorerPB.GetValidatorUptimeResponse, error) {
+	req := explorerPB.GetValidatorUptimeRequest{
+		Address: address,
+	}
+
+	ctx = c.getCookie(ctx)
+	res, err := c.explorerClient.GetValidatorUptime(ctx, &req)
+	if err != nil {
+		fmt.Println(err)
+		return &explorerPB.GetValidatorUptimeResponse{}, err
+	}
+
+	return res, nil
+}
+
 func (c *explorerClient) GetTxs(ctx context.Context, req *explorerPB.GetTxsRequest) (*explorerPB.GetTxsResponse, error) {
 	ctx = c.getCookie(ctx)
 	res, err := c.explorerClient.GetTxs(ctx, req)
@@ -285,6 +339,28 @@ func (c *explorerClient) GetCW20Balance(ctx context.Context, req *explorerPB.Get
 	return res, nil
 }
 
+func (c *explorerClient) GetRelayers(ctx context.Context, req *explorerPB.RelayersRequest) (*explorerPB.RelayersResponse, error) {
+	ctx = c.getCookie(ctx)
+	res, err := c.explorerClient.Relayers(ctx, req)
+	if err != nil {
+		fmt.Println(err)
+		return &explorerPB.RelayersResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (c *explorerClient) GetBankTransfers(ctx context.Context, req *explorerPB.GetBankTransfersRequest) (*explorerPB.GetBankTransfersResponse, error) {
+	ctx = c.getCookie(ctx)
+	res, err := c.explorerClient.GetBankTransfers(ctx, req)
+	if err != nil {
+		fmt.Println(err)
+		return &explorerPB.GetBankTransfersResponse{}, err
+	}
+
+	return res, nil
+}
+
 func (c *explorerClient) Close() {
 	c.conn.Close()
 }
@@ -0,0 +1,105 @@
+package dashboard
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+)
+
+// ProtocolVersion is the version of the messages sent by the server, included in the snapshot message
+const ProtocolVersion = 1
+
+// MessageType is the type of a message pushed to the dashboards
+type MessageType string
+
+const (
+	// MessageSnapshot is the first message of a connection, with the orders and the portfolio
+	MessageSnapshot MessageType = "snapshot"
+	// MessageOrders is sent when the tracked orders change
+	MessageOrders MessageType = "orders"
+	// MessagePortfolio is sent when the deposits or positions change
+	MessagePortfolio MessageType = "portfolio"
+	// MessageAccountDelta is sent for every account delta event published to the server
+	MessageAccountDelta MessageType = "account_delta"
+)
+
+// Message is the JSON message pushed to the dashboards. Seq increases with every message of the server, so a client
+// can tell the order of the messages and detect the ones dropped on reconnection
+type Message struct {
+	Type         MessageType     `json:"type"`
+	Seq          uint64          `json:"seq"`
+	Time         time.Time       `json:"time"`
+	Version      int             `json:"version,omitempty"`
+	Orders       *OrdersState    `json:"orders,omitempty"`
+	Portfolio    *PortfolioState `json:"portfolio,omitempty"`
+	AccountDelta *AccountDelta   `json:"account_delta,omitempty"`
+}
+
+// OrdersState is the state of the order tracker
+type OrdersState struct {
+	Open           []chain.TrackedOrder `json:"open"`
+	PendingCancels []string             `json:"pending_cancels"`
+	ReplacedBy     map[string]string    `json:"replaced_by"`
+}
+
+// PortfolioState has the deposits and positions of the subaccounts
+type PortfolioState struct {
+	Subaccounts []SubaccountPortfolio `json:"subaccounts"`
+}
+
+type SubaccountPortfolio struct {
+	SubaccountId string                            `json:"subaccount_id"`
+	Deposits     map[string]exchangetypes.Deposit  `json:"deposits"`
+	Positions    map[string]exchangetypes.Position `json:"positions"`
+}
+
+// AccountDelta is a chain.AccountDeltaEvent. Deposit events have Denom and Deposit set, position events have
+// MarketId set and Position set unless the position was closed
+type AccountDelta struct {
+	Type         chain.AccountDeltaType `json:"type"`
+	SubaccountId string                 `json:"subaccount_id"`
+
+	Denom                 string                 `json:"denom,omitempty"`
+	TotalBalanceDelta     *sdk.Dec               `json:"total_balance_delta,omitempty"`
+	AvailableBalanceDelta *sdk.Dec               `json:"available_balance_delta,omitempty"`
+	Deposit               *exchangetypes.Deposit `json:"deposit,omitempty"`
+
+	MarketId      string                  `json:"market_id,omitempty"`
+	QuantityDelta *sdk.Dec                `json:"quantity_delta,omitempty"`
+	MarginDelta   *sdk.Dec                `json:"margin_delta,omitempty"`
+	Position      *exchangetypes.Position `json:"position,omitempty"`
+
+	Height     uint64    `json:"height,omitempty"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+func newAccountDelta(event chain.AccountDeltaEvent) *AccountDelta {
+	delta := &AccountDelta{
+		Type:                  event.Type,
+		SubaccountId:          event.SubaccountId,
+		Denom:                 event.Denom,
+		TotalBalanceDelta:     decOrNil(event.TotalBalanceDelta),
+		AvailableBalanceDelta: decOrNil(event.AvailableBalanceDelta),
+		MarketId:              event.MarketId,
+		QuantityDelta:         decOrNil(event.QuantityDelta),
+		MarginDelta:           decOrNil(event.MarginDelta),
+		Position:              event.CurrentPosition,
+		Height:                event.Height,
+		ObservedAt:            event.ObservedAt,
+	}
+	if event.Denom != "" {
+		deposit := event.CurrentDeposit
+		delta.Deposit = &deposit
+	}
+	return delta
+}
+
+func decOrNil(value sdk.Dec) *sdk.Dec {
+	if value.IsNil() {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,287 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/gorilla/websocket"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const (
+	defaultRefreshInterval = time.Second
+	defaultSendQueueSize   = 64
+	pingInterval           = 30 * time.Second
+	writeTimeout           = 10 * time.Second
+)
+
+type ServerConfig struct {
+	// Interval is the time between the checks for changes of the orders and the portfolio (1 second by default)
+	Interval time.Duration
+	// AllowedOrigins are the browser origins allowed to connect ("*" allows any). When empty, only the pages served
+	// from the same host, and the clients that are not browsers, are allowed
+	AllowedOrigins []string
+	// SendQueueSize is the number of messages queued for a connection (64 by default). Connections that don't read
+	// their messages are closed when the queue is full
+	SendQueueSize int
+	// Clock of the refresh interval and of the message times, the real clock by default
+	Clock clock.Clock
+}
+
+// PortfolioSource provides the deposits and positions pushed to the dashboards. chain.AccountWatcher implements it
+type PortfolioSource interface {
+	SubaccountIds() []string
+	Deposits(subaccountId string) map[string]exchangetypes.Deposit
+	Positions(subaccountId string) map[string]exchangetypes.Position
+}
+
+// Server is an HTTP handler upgrading the requests to WebSocket connections, and pushing to them the state of an
+// OrderTracker and of a PortfolioSource as JSON messages (see Message). Every connection starts with a snapshot
+// message, then gets the orders and portfolio messages when the state changes (checked every interval by Run), and
+// the account delta events published with PublishAccountDelta. Messages sent by the clients are ignored
+type Server struct {
+	config    ServerConfig
+	tracker   *chain.OrderTracker
+	portfolio PortfolioSource
+	upgrader  websocket.Upgrader
+	logger    log.Logger
+
+	mux           sync.Mutex
+	connections   map[*connection]struct{}
+	seq           uint64
+	lastOrders    []byte
+	lastPortfolio []byte
+}
+
+type connection struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// NewServer creates the server. The tracker and the portfolio are optional, the messages only include the state of
+// the ones set
+func NewServer(tracker *chain.OrderTracker, portfolio PortfolioSource, config ServerConfig) *Server {
+	if config.Interval <= 0 {
+		config.Interval = defaultRefreshInterval
+	}
+	if config.SendQueueSize <= 0 {
+		config.SendQueueSize = defaultSendQueueSize
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	s := &Server{
+		config:      config,
+		tracker:     tracker,
+		portfolio:   portfolio,
+		logger:      log.WithField("module", "dashboard-server"),
+		connections: make(map[*connection]struct{}),
+	}
+	if len(config.AllowedOrigins) > 0 {
+		s.upgrader.CheckOrigin = s.checkOrigin
+	}
+	return s
+}
+
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	for _, allowed := range s.config.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and pushes the messages until the connection is closed
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already answered the request with the error
+		s.logger.WithError(err).Debugln("failed to upgrade the dashboard connection")
+		return
+	}
+
+	c := &connection{conn: conn, send: make(chan []byte, s.config.SendQueueSize)}
+	s.mux.Lock()
+	s.connections[c] = struct{}{}
+	snapshot := s.newMessage(MessageSnapshot)
+	snapshot.Version = ProtocolVersion
+	snapshot.Orders, _ = s.ordersState()
+	snapshot.Portfolio, _ = s.portfolioState()
+	s.enqueue(c, snapshot)
+	s.mux.Unlock()
+
+	go s.write(c)
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	s.remove(c)
+}
+
+// Run pushes the changes of the orders and the portfolio every interval until the context is done, then closes the
+// connections
+func (s *Server) Run(ctx context.Context) {
+	ticker := s.config.Clock.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			s.Refresh()
+		case <-ctx.Done():
+			s.closeAll()
+			return
+		}
+	}
+}
+
+// Refresh pushes the orders and the portfolio if they changed since they were last pushed
+func (s *Server) Refresh() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if orders, encoded := s.ordersState(); orders != nil && string(encoded) != string(s.lastOrders) {
+		s.lastOrders = encoded
+		message := s.newMessage(MessageOrders)
+		message.Orders = orders
+		s.broadcast(message)
+	}
+	if portfolio, encoded := s.portfolioState(); portfolio != nil && string(encoded) != string(s.lastPortfolio) {
+		s.lastPortfolio = encoded
+		message := s.newMessage(MessagePortfolio)
+		message.Portfolio = portfolio
+		s.broadcast(message)
+	}
+}
+
+// PublishAccountDelta pushes an account delta event (e.g. from AccountWatcher.Watch) to the dashboards
+func (s *Server) PublishAccountDelta(event chain.AccountDeltaEvent) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	message := s.newMessage(MessageAccountDelta)
+	message.AccountDelta = newAccountDelta(event)
+	s.broadcast(message)
+}
+
+// Connections returns the number of connected dashboards
+func (s *Server) Connections() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return len(s.connections)
+}
+
+func (s *Server) newMessage(messageType MessageType) *Message {
+	s.seq++
+	return &Message{Type: messageType, Seq: s.seq, Time: s.config.Clock.Now().UTC()}
+}
+
+func (s *Server) ordersState() (*OrdersState, []byte) {
+	if s.tracker == nil {
+		return nil, nil
+	}
+	snapshot := chain.TakeStateSnapshot(s.tracker, nil)
+	state := &OrdersState{Open: snapshot.Orders, PendingCancels: snapshot.PendingCancels, ReplacedBy: snapshot.ReplacedBy}
+	if state.PendingCancels == nil {
+		state.PendingCancels = []string{}
+	}
+	encoded, _ := json.Marshal(state)
+	return state, encoded
+}
+
+func (s *Server) portfolioState() (*PortfolioState, []byte) {
+	if s.portfolio == nil {
+		return nil, nil
+	}
+	state := &PortfolioState{Subaccounts: make([]SubaccountPortfolio, 0)}
+	for _, subaccountId := range s.portfolio.SubaccountIds() {
+		state.Subaccounts = append(state.Subaccounts, SubaccountPortfolio{
+			SubaccountId: subaccountId,
+			Deposits:     s.portfolio.Deposits(subaccountId),
+			Positions:    s.portfolio.Positions(subaccountId),
+		})
+	}
+	encoded, _ := json.Marshal(state)
+	return state, encoded
+}
+
+// broadcast queues the message for every connection. It is called with mux locked
+func (s *Server) broadcast(message *Message) {
+	for c := range s.connections {
+		s.enqueue(c, message)
+	}
+}
+
+// enqueue queues the message for the connection, closing the connection if its queue is full. It is called with mux
+// locked
+func (s *Server) enqueue(c *connection, message *Message) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		s.logger.WithError(err).Errorln("failed to encode the dashboard message")
+		return
+	}
+
+	select {
+	case c.send <- data:
+	default:
+		s.logger.WithField("remote", c.conn.RemoteAddr().String()).Warningln("closing the dashboard connection not reading its messages")
+		delete(s.connections, c)
+		close(c.send)
+	}
+}
+
+// write sends the queued messages to the connection, and closes it once the queue is closed
+func (s *Server) write(c *connection) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	defer c.conn.Close()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) remove(c *connection) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, found := s.connections[c]; found {
+		delete(s.connections, c)
+		close(c.send)
+	}
+}
+
+func (s *Server) closeAll() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for c := range s.connections {
+		delete(s.connections, c)
+		close(c.send)
+	}
+}
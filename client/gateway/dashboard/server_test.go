@@ -0,0 +1,167 @@
+package dashboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const (
+	testMarketId     = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	testSubaccountId = "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000"
+)
+
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type testPortfolio struct{}
+
+func (testPortfolio) SubaccountIds() []string { return []string{testSubaccountId} }
+
+func (testPortfolio) Deposits(string) map[string]exchangetypes.Deposit {
+	return map[string]exchangetypes.Deposit{"inj": {AvailableBalance: sdk.NewDec(5), TotalBalance: sdk.NewDec(10)}}
+}
+
+func (testPortfolio) Positions(string) map[string]exchangetypes.Position {
+	return map[string]exchangetypes.Position{}
+}
+
+func testOrder(orderHash string) chain.TrackedOrder {
+	return chain.TrackedOrder{
+		OrderHash:    orderHash,
+		MarketId:     testMarketId,
+		SubaccountId: testSubaccountId,
+		OrderType:    exchangetypes.OrderType_BUY,
+		Price:        sdk.NewDec(2),
+		Quantity:     sdk.NewDec(1),
+	}
+}
+
+func dial(t *testing.T, server *httptest.Server, header http.Header) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), header)
+	assert.NoError(t, err)
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) Message {
+	var message Message
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	assert.NoError(t, conn.ReadJSON(&message))
+	return message
+}
+
+func TestServerPushesSnapshotAndChanges(t *testing.T) {
+	fakeClock := clock.NewFake(testStart)
+	tracker := chain.NewOrderTracker()
+	tracker.SetClock(fakeClock)
+	tracker.Track(testOrder("0x01"))
+	server := NewServer(tracker, testPortfolio{}, ServerConfig{Clock: fakeClock})
+	server.Refresh()
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	conn := dial(t, httpServer, nil)
+	defer conn.Close()
+	snapshot := readMessage(t, conn)
+	assert.Equal(t, MessageSnapshot, snapshot.Type)
+	assert.Equal(t, ProtocolVersion, snapshot.Version)
+	assert.Equal(t, uint64(3), snapshot.Seq)
+	assert.Equal(t, testStart, snapshot.Time)
+	assert.Len(t, snapshot.Orders.Open, 1)
+	assert.Equal(t, "0x01", snapshot.Orders.Open[0].OrderHash)
+	assert.Len(t, snapshot.Portfolio.Subaccounts, 1)
+	assert.Equal(t, "10.000000000000000000", snapshot.Portfolio.Subaccounts[0].Deposits["inj"].TotalBalance.String())
+	assert.Equal(t, 1, server.Connections())
+
+	// only the changed state is pushed
+	tracker.Track(testOrder("0x02"))
+	tracker.MarkCancelPending("0x01")
+	server.Refresh()
+	orders := readMessage(t, conn)
+	assert.Equal(t, MessageOrders, orders.Type)
+	assert.Equal(t, uint64(4), orders.Seq)
+	assert.Len(t, orders.Orders.Open, 2)
+	assert.Equal(t, []string{"0x01"}, orders.Orders.PendingCancels)
+	assert.Nil(t, orders.Portfolio)
+
+	server.PublishAccountDelta(chain.AccountDeltaEvent{
+		Type:                  chain.DepositCredited,
+		SubaccountId:          testSubaccountId,
+		Denom:                 "inj",
+		TotalBalanceDelta:     sdk.NewDec(3),
+		AvailableBalanceDelta: sdk.NewDec(3),
+		CurrentDeposit:        exchangetypes.Deposit{AvailableBalance: sdk.NewDec(8), TotalBalance: sdk.NewDec(13)},
+		ObservedAt:            testStart,
+	})
+	delta := readMessage(t, conn)
+	assert.Equal(t, MessageAccountDelta, delta.Type)
+	assert.Equal(t, uint64(5), delta.Seq)
+	assert.Equal(t, chain.DepositCredited, delta.AccountDelta.Type)
+	assert.Equal(t, "3.000000000000000000", delta.AccountDelta.TotalBalanceDelta.String())
+	assert.Equal(t, "13.000000000000000000", delta.AccountDelta.Deposit.TotalBalance.String())
+	assert.Nil(t, delta.AccountDelta.QuantityDelta)
+
+	assert.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool { return server.Connections() == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestServerRunRefreshesEveryInterval(t *testing.T) {
+	fakeClock := clock.NewFake(testStart)
+	tracker := chain.NewOrderTracker()
+	server := NewServer(tracker, nil, ServerConfig{Interval: time.Minute, Clock: fakeClock})
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	conn := dial(t, httpServer, nil)
+	defer conn.Close()
+	snapshot := readMessage(t, conn)
+	assert.Empty(t, snapshot.Orders.Open)
+	assert.Nil(t, snapshot.Portfolio)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		server.Run(ctx)
+		close(done)
+	}()
+	assert.NoError(t, fakeClock.BlockUntil(ctx, 1))
+
+	tracker.Track(testOrder("0x01"))
+	fakeClock.Advance(time.Minute)
+	orders := readMessage(t, conn)
+	assert.Equal(t, MessageOrders, orders.Type)
+	assert.Equal(t, testStart.Add(time.Minute), orders.Time)
+	assert.Len(t, orders.Orders.Open, 1)
+
+	// the connections are closed when Run returns
+	cancel()
+	<-done
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+	assert.Equal(t, 0, server.Connections())
+}
+
+func TestServerAllowedOrigins(t *testing.T) {
+	server := NewServer(chain.NewOrderTracker(), nil, ServerConfig{AllowedOrigins: []string{"https://dashboard.example"}})
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	_, res, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://other.example"}})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	conn := dial(t, httpServer, http.Header{"Origin": []string{"https://dashboard.example"}})
+	defer conn.Close()
+	assert.Equal(t, MessageSnapshot, readMessage(t, conn).Type)
+}
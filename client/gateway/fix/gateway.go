@@ -0,0 +1,326 @@
+package fix
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+	"github.com/InjectiveLabs/sdk-go/client/execution"
+)
+
+const (
+	defaultHeartbeatInterval = 30 * time.Second
+	sendingTimeFormat        = "20060102-15:04:05.000"
+
+	sideBuy  = "1"
+	sideSell = "2"
+
+	ordTypeLimit = "2"
+
+	execTypeNew      = "0"
+	execTypeCanceled = "4"
+	execTypeRejected = "8"
+
+	ordStatusNew      = "0"
+	ordStatusCanceled = "4"
+	ordStatusRejected = "8"
+)
+
+type GatewayConfig struct {
+	// SenderCompID is the CompID of the gateway, the clients must use it as TargetCompID
+	SenderCompID string
+	// HeartbeatInterval is used when the client Logon has no HeartBtInt (30 seconds by default)
+	HeartbeatInterval time.Duration
+	// Markets maps the FIX symbols to market ids. Symbols not in the map are used as market ids
+	Markets map[string]string
+	// Clock of the heartbeats and of the message times, the real clock by default
+	Clock clock.Clock
+}
+
+// gatewayOrder is an order created from a NewOrderSingle, by session and ClOrdID
+type gatewayOrder struct {
+	targetCompID string
+	clOrdID      string
+	orderHash    string
+	symbol       string
+	marketId     string
+	side         string
+	price        decimal.Decimal
+	quantity     decimal.Decimal
+}
+
+// Gateway accepts FIX 4.4 order entry sessions: NewOrderSingle limit orders are placed with the OrderPlacer (for
+// example execution.ChainOrderPlacer, which builds the orders with the chain client and tracks them), and
+// OrderCancelRequest cancels them. Both are answered with ExecutionReport (or OrderCancelReject) messages, where
+// OrderID is the order hash. Fills are not reported. Sequence numbers start at 1 on every connection and gaps are not
+// resent
+type Gateway struct {
+	config  GatewayConfig
+	placer  execution.OrderPlacer
+	tracker *chain.OrderTracker
+	logger  log.Logger
+
+	mux          sync.Mutex
+	orders       map[string]*gatewayOrder
+	ordersByHash map[string]*gatewayOrder
+	execId       uint64
+
+	clock clock.Clock
+}
+
+// NewGateway creates the gateway. The tracker is optional: if set, cancel requests for orders not tracked anymore
+// (e.g. filled orders removed from the tracker) are rejected without broadcasting them, and OrderCancelRequest can
+// also reference orders by OrderID
+func NewGateway(config GatewayConfig, placer execution.OrderPlacer, tracker *chain.OrderTracker) *Gateway {
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = defaultHeartbeatInterval
+	}
+
+	return &Gateway{
+		config:       config,
+		placer:       placer,
+		tracker:      tracker,
+		logger:       log.WithField("module", "fix-gateway"),
+		orders:       make(map[string]*gatewayOrder),
+		ordersByHash: make(map[string]*gatewayOrder),
+		clock:        clock.OrReal(config.Clock),
+	}
+}
+
+// Serve accepts connections until the context is done or the listener fails, and runs a session for each one
+func (g *Gateway) Serve(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "failed to accept a FIX connection")
+		}
+		go func() {
+			if err := g.ServeConn(ctx, conn); err != nil {
+				g.logger.WithError(err).Warningln("FIX session ended")
+			}
+		}()
+	}
+}
+
+// ServeConn runs a session on the connection until the client logs out, the connection is closed or the context is
+// done. The connection is closed when it returns
+func (g *Gateway) ServeConn(ctx context.Context, conn io.ReadWriteCloser) error {
+	defer conn.Close()
+
+	s := &session{
+		gateway:   g,
+		conn:      conn,
+		inSeqNum:  1,
+		heartbeat: g.config.HeartbeatInterval,
+	}
+	return s.run(ctx)
+}
+
+func (g *Gateway) marketId(symbol string) string {
+	if marketId, found := g.config.Markets[symbol]; found {
+		return marketId
+	}
+	return symbol
+}
+
+func (g *Gateway) nextExecId() string {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	g.execId++
+	return strconv.FormatInt(g.clock.Now().UnixNano(), 10) + "-" + strconv.FormatUint(g.execId, 10)
+}
+
+func (g *Gateway) newOrder(s *session, msg *Message) *Message {
+	clOrdID, _ := msg.Get(TagClOrdID)
+	symbol, _ := msg.Get(TagSymbol)
+	side, _ := msg.Get(TagSide)
+	order := &gatewayOrder{targetCompID: s.targetCompID, clOrdID: clOrdID, symbol: symbol, side: side}
+
+	if err := g.parseNewOrder(msg, order); err != nil {
+		return g.executionReport(order, execTypeRejected, ordStatusRejected, err.Error())
+	}
+
+	g.mux.Lock()
+	_, duplicate := g.orders[orderKey(s.targetCompID, clOrdID)]
+	g.mux.Unlock()
+	if duplicate {
+		return g.executionReport(order, execTypeRejected, ordStatusRejected, "duplicate ClOrdID")
+	}
+
+	orderHash, err := g.placer.PlaceOrder(s.ctx, execution.ChildOrder{
+		MarketId: order.marketId,
+		IsBuy:    side == sideBuy,
+		Price:    order.price,
+		Quantity: order.quantity,
+	})
+	if err != nil {
+		return g.executionReport(order, execTypeRejected, ordStatusRejected, err.Error())
+	}
+	order.orderHash = orderHash
+
+	g.mux.Lock()
+	g.orders[orderKey(s.targetCompID, clOrdID)] = order
+	g.ordersByHash[orderHash] = order
+	g.mux.Unlock()
+
+	return g.executionReport(order, execTypeNew, ordStatusNew, "")
+}
+
+func (g *Gateway) parseNewOrder(msg *Message, order *gatewayOrder) error {
+	if order.clOrdID == "" {
+		return errors.New("ClOrdID is required")
+	}
+	if order.symbol == "" {
+		return errors.New("Symbol is required")
+	}
+	if order.side != sideBuy && order.side != sideSell {
+		return errors.Errorf("unsupported Side %q", order.side)
+	}
+	if ordType, _ := msg.Get(TagOrdType); ordType != ordTypeLimit {
+		return errors.Errorf("unsupported OrdType %q, only limit orders are supported", ordType)
+	}
+
+	var err error
+	quantity, _ := msg.Get(TagOrderQty)
+	if order.quantity, err = decimal.NewFromString(quantity); err != nil || !order.quantity.IsPositive() {
+		return errors.Errorf("invalid OrderQty %q", quantity)
+	}
+	price, _ := msg.Get(TagPrice)
+	if order.price, err = decimal.NewFromString(price); err != nil || !order.price.IsPositive() {
+		return errors.Errorf("invalid Price %q", price)
+	}
+	order.marketId = g.marketId(order.symbol)
+	return nil
+}
+
+func (g *Gateway) cancelOrder(s *session, msg *Message) *Message {
+	clOrdID, _ := msg.Get(TagClOrdID)
+	origClOrdID, _ := msg.Get(TagOrigClOrdID)
+	orderID, _ := msg.Get(TagOrderID)
+
+	order, err := g.orderToCancel(s, origClOrdID, orderID)
+	if err == nil && clOrdID == "" {
+		err = errors.New("ClOrdID is required")
+	}
+	if err == nil && g.tracker != nil {
+		if _, found := g.tracker.Order(order.orderHash); !found {
+			err = errors.Errorf("order %s is not open", order.orderHash)
+		}
+	}
+	if err == nil {
+		err = g.placer.CancelOrder(s.ctx, order.marketId, order.orderHash)
+	}
+	if err != nil {
+		return cancelReject(order, clOrdID, origClOrdID, orderID, err)
+	}
+
+	g.mux.Lock()
+	delete(g.orders, orderKey(order.targetCompID, order.clOrdID))
+	delete(g.ordersByHash, order.orderHash)
+	g.mux.Unlock()
+
+	report := g.executionReport(order, execTypeCanceled, ordStatusCanceled, "").Set(TagClOrdID, clOrdID)
+	if order.clOrdID != "" {
+		report.Set(TagOrigClOrdID, order.clOrdID)
+	}
+	return report
+}
+
+func (g *Gateway) orderToCancel(s *session, origClOrdID string, orderID string) (*gatewayOrder, error) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	if origClOrdID != "" {
+		if order, found := g.orders[orderKey(s.targetCompID, origClOrdID)]; found {
+			return order, nil
+		}
+		return nil, errors.Errorf("unknown OrigClOrdID %s", origClOrdID)
+	}
+	if orderID == "" {
+		return nil, errors.New("OrigClOrdID or OrderID is required")
+	}
+	if order, found := g.ordersByHash[orderID]; found && order.targetCompID == s.targetCompID {
+		return order, nil
+	}
+	if g.tracker != nil {
+		if tracked, found := g.tracker.Order(orderID); found {
+			return &gatewayOrder{targetCompID: s.targetCompID, orderHash: orderID, marketId: tracked.MarketId}, nil
+		}
+	}
+	return nil, errors.Errorf("unknown OrderID %s", orderID)
+}
+
+func (g *Gateway) executionReport(order *gatewayOrder, execType string, ordStatus string, text string) *Message {
+	leavesQty := order.quantity
+	if ordStatus != ordStatusNew {
+		leavesQty = decimal.Zero
+	}
+
+	report := NewMessage(MsgTypeExecutionReport).
+		Set(TagOrderID, valueOrNone(order.orderHash)).
+		Set(TagClOrdID, valueOrNone(order.clOrdID)).
+		Set(TagExecID, g.nextExecId()).
+		Set(TagExecType, execType).
+		Set(TagOrdStatus, ordStatus).
+		Set(TagSymbol, valueOrNone(order.symbol)).
+		Set(TagSide, valueOrNone(order.side)).
+		Set(TagOrderQty, order.quantity.String()).
+		Set(TagPrice, order.price.String()).
+		Set(TagLeavesQty, leavesQty.String()).
+		Set(TagCumQty, "0").
+		Set(TagAvgPx, "0").
+		Set(TagTransactTime, g.clock.Now().UTC().Format(sendingTimeFormat))
+	if text != "" {
+		report.Set(TagText, text)
+	}
+	return report
+}
+
+func cancelReject(order *gatewayOrder, clOrdID string, origClOrdID string, orderID string, err error) *Message {
+	ordStatus := ordStatusRejected
+	if order != nil {
+		orderID = order.orderHash
+		ordStatus = ordStatusNew
+	}
+
+	reject := NewMessage(MsgTypeOrderCancelReject).
+		Set(TagOrderID, valueOrNone(orderID)).
+		Set(TagClOrdID, valueOrNone(clOrdID)).
+		Set(TagOrdStatus, ordStatus).
+		Set(TagCxlRejResponseTo, "1").
+		Set(TagText, err.Error())
+	if origClOrdID != "" {
+		reject.Set(TagOrigClOrdID, origClOrdID)
+	}
+	return reject
+}
+
+// valueOrNone returns NONE for the required fields without a value
+func valueOrNone(value string) string {
+	if value == "" {
+		return "NONE"
+	}
+	return value
+}
+
+func orderKey(targetCompID string, clOrdID string) string {
+	return targetCompID + "/" + clOrdID
+}
@@ -0,0 +1,147 @@
+package fix
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/execution"
+)
+
+const testMarketId = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+
+type testPlacer struct {
+	tracker   *chain.OrderTracker
+	placed    []execution.ChildOrder
+	cancelled []string
+}
+
+func (p *testPlacer) PlaceOrder(ctx context.Context, order execution.ChildOrder) (string, error) {
+	p.placed = append(p.placed, order)
+	orderHash := "0x" + strconv.Itoa(len(p.placed))
+	p.tracker.Track(chain.TrackedOrder{OrderHash: orderHash, MarketId: order.MarketId, OrderType: exchangetypes.OrderType_BUY, Price: sdk.ZeroDec(), Quantity: sdk.ZeroDec()})
+	return orderHash, nil
+}
+
+func (p *testPlacer) CancelOrder(ctx context.Context, marketId string, orderHash string) error {
+	p.cancelled = append(p.cancelled, marketId+"/"+orderHash)
+	p.tracker.Remove(orderHash)
+	return nil
+}
+
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	seqNum int
+}
+
+func (c *testClient) send(msg *Message) {
+	c.seqNum++
+	fields := []Field{msg.Fields[0], {Tag: TagSenderCompID, Value: "OMS"}, {Tag: TagTargetCompID, Value: "INJ"}, {Tag: TagMsgSeqNum, Value: strconv.Itoa(c.seqNum)}}
+	_, err := c.conn.Write((&Message{Fields: append(fields, msg.Fields[1:]...)}).Bytes())
+	assert.NoError(c.t, err)
+}
+
+func (c *testClient) receive() *Message {
+	data, err := ReadMessage(c.reader)
+	assert.NoError(c.t, err)
+	msg, err := ParseMessage(data)
+	assert.NoError(c.t, err)
+	return msg
+}
+
+func TestGatewayOrderEntrySession(t *testing.T) {
+	tracker := chain.NewOrderTracker()
+	placer := &testPlacer{tracker: tracker}
+	gateway := NewGateway(GatewayConfig{SenderCompID: "INJ", Markets: map[string]string{"INJ/USDT": testMarketId}}, placer, tracker)
+
+	serverConn, clientConn := net.Pipe()
+	done := make(chan error)
+	go func() { done <- gateway.ServeConn(context.Background(), serverConn) }()
+	client := &testClient{t: t, conn: clientConn, reader: bufio.NewReader(clientConn)}
+
+	client.send(NewMessage(MsgTypeLogon).Set(TagEncryptMethod, "0").Set(TagHeartBtInt, "10"))
+	logon := client.receive()
+	assert.Equal(t, MsgTypeLogon, logon.MsgType())
+	assert.Equal(t, "10", firstValue(logon, TagHeartBtInt))
+	assert.Equal(t, "OMS", firstValue(logon, TagTargetCompID))
+
+	newOrder := func(clOrdID string, ordType string) *Message {
+		return NewMessage(MsgTypeNewOrderSingle).
+			Set(TagClOrdID, clOrdID).
+			Set(TagSymbol, "INJ/USDT").
+			Set(TagSide, sideBuy).
+			Set(TagOrderQty, "1.5").
+			Set(TagOrdType, ordType).
+			Set(TagPrice, "20.1")
+	}
+	client.send(newOrder("order-1", ordTypeLimit))
+	report := client.receive()
+	assert.Equal(t, MsgTypeExecutionReport, report.MsgType())
+	assert.Equal(t, execTypeNew, firstValue(report, TagExecType))
+	assert.Equal(t, "0x1", firstValue(report, TagOrderID))
+	assert.Equal(t, "1.5", firstValue(report, TagLeavesQty))
+	assert.Len(t, placer.placed, 1)
+	assert.Equal(t, testMarketId, placer.placed[0].MarketId)
+	assert.True(t, placer.placed[0].IsBuy)
+	assert.Equal(t, "20.1", placer.placed[0].Price.String())
+
+	client.send(newOrder("order-1", ordTypeLimit))
+	assert.Equal(t, execTypeRejected, firstValue(client.receive(), TagExecType), "duplicate ClOrdID")
+	client.send(newOrder("order-2", "1"))
+	report = client.receive()
+	assert.Equal(t, execTypeRejected, firstValue(report, TagExecType), "market orders are not supported")
+	assert.Equal(t, "NONE", firstValue(report, TagOrderID))
+	assert.Len(t, placer.placed, 1)
+
+	client.send(NewMessage(MsgTypeOrderCancelRequest).Set(TagOrigClOrdID, "order-1").Set(TagClOrdID, "cancel-1").Set(TagSymbol, "INJ/USDT").Set(TagSide, sideBuy))
+	report = client.receive()
+	assert.Equal(t, MsgTypeExecutionReport, report.MsgType())
+	assert.Equal(t, execTypeCanceled, firstValue(report, TagExecType))
+	assert.Equal(t, "order-1", firstValue(report, TagOrigClOrdID))
+	assert.Equal(t, []string{testMarketId + "/0x1"}, placer.cancelled)
+
+	client.send(NewMessage(MsgTypeOrderCancelRequest).Set(TagOrigClOrdID, "order-1").Set(TagClOrdID, "cancel-2"))
+	reject := client.receive()
+	assert.Equal(t, MsgTypeOrderCancelReject, reject.MsgType())
+	assert.Equal(t, "cancel-2", firstValue(reject, TagClOrdID))
+
+	client.send(NewMessage(MsgTypeTestRequest).Set(TagTestReqID, "ping"))
+	assert.Equal(t, "ping", firstValue(client.receive(), TagTestReqID))
+
+	client.send(NewMessage(MsgTypeLogout))
+	assert.Equal(t, MsgTypeLogout, client.receive().MsgType())
+	assert.NoError(t, <-done)
+}
+
+func TestGatewayRequiresLogonAndSequenceNumbers(t *testing.T) {
+	gateway := NewGateway(GatewayConfig{SenderCompID: "INJ"}, &testPlacer{tracker: chain.NewOrderTracker()}, nil)
+
+	serverConn, clientConn := net.Pipe()
+	done := make(chan error)
+	go func() { done <- gateway.ServeConn(context.Background(), serverConn) }()
+	client := &testClient{t: t, conn: clientConn, reader: bufio.NewReader(clientConn)}
+
+	client.send(NewMessage(MsgTypeHeartbeat))
+	assert.Equal(t, MsgTypeLogout, client.receive().MsgType())
+	assert.Error(t, <-done)
+
+	serverConn, clientConn = net.Pipe()
+	go func() { done <- gateway.ServeConn(context.Background(), serverConn) }()
+	client = &testClient{t: t, conn: clientConn, reader: bufio.NewReader(clientConn)}
+
+	client.send(NewMessage(MsgTypeLogon).Set(TagEncryptMethod, "0"))
+	assert.Equal(t, MsgTypeLogon, client.receive().MsgType())
+	client.seqNum = 0
+	client.send(NewMessage(MsgTypeHeartbeat))
+	assert.Equal(t, MsgTypeLogout, client.receive().MsgType())
+	assert.Error(t, <-done)
+}
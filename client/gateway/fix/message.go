@@ -0,0 +1,199 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	BeginString = "FIX.4.4"
+	soh         = '\x01'
+	// maxMessageSize bounds the messages read from a session, the order entry messages are much smaller
+	maxMessageSize = 64 * 1024
+)
+
+// Tags used by the order entry session
+const (
+	TagAvgPx            = 6
+	TagBeginString      = 8
+	TagBodyLength       = 9
+	TagCheckSum         = 10
+	TagClOrdID          = 11
+	TagCumQty           = 14
+	TagExecID           = 17
+	TagMsgSeqNum        = 34
+	TagMsgType          = 35
+	TagOrderID          = 37
+	TagOrderQty         = 38
+	TagOrdStatus        = 39
+	TagOrdType          = 40
+	TagOrigClOrdID      = 41
+	TagPrice            = 44
+	TagRefSeqNum        = 45
+	TagSenderCompID     = 49
+	TagSendingTime      = 52
+	TagSide             = 54
+	TagSymbol           = 55
+	TagTargetCompID     = 56
+	TagText             = 58
+	TagTransactTime     = 60
+	TagEncryptMethod    = 98
+	TagHeartBtInt       = 108
+	TagTestReqID        = 112
+	TagExecType         = 150
+	TagLeavesQty        = 151
+	TagCxlRejResponseTo = 434
+)
+
+// Message types used by the order entry session
+const (
+	MsgTypeHeartbeat          = "0"
+	MsgTypeTestRequest        = "1"
+	MsgTypeReject             = "3"
+	MsgTypeLogout             = "5"
+	MsgTypeExecutionReport    = "8"
+	MsgTypeOrderCancelReject  = "9"
+	MsgTypeLogon              = "A"
+	MsgTypeNewOrderSingle     = "D"
+	MsgTypeOrderCancelRequest = "F"
+)
+
+type Field struct {
+	Tag   int
+	Value string
+}
+
+// Message is a FIX message with its fields in order. BeginString, BodyLength and CheckSum are not stored in the
+// fields, they are added by Bytes and checked by ParseMessage
+type Message struct {
+	Fields []Field
+}
+
+func NewMessage(msgType string) *Message {
+	return &Message{Fields: []Field{{Tag: TagMsgType, Value: msgType}}}
+}
+
+func (m *Message) MsgType() string {
+	msgType, _ := m.Get(TagMsgType)
+	return msgType
+}
+
+// Get returns the value of the first field with the tag
+func (m *Message) Get(tag int) (string, bool) {
+	for _, field := range m.Fields {
+		if field.Tag == tag {
+			return field.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set replaces the value of the field with the tag, or appends the field
+func (m *Message) Set(tag int, value string) *Message {
+	for i, field := range m.Fields {
+		if field.Tag == tag {
+			m.Fields[i].Value = value
+			return m
+		}
+	}
+	m.Fields = append(m.Fields, Field{Tag: tag, Value: value})
+	return m
+}
+
+// Bytes encodes the message with the BeginString, BodyLength and CheckSum fields
+func (m *Message) Bytes() []byte {
+	var body bytes.Buffer
+	for _, field := range m.Fields {
+		body.WriteString(strconv.Itoa(field.Tag))
+		body.WriteByte('=')
+		body.WriteString(field.Value)
+		body.WriteByte(soh)
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "%d=%s%c%d=%d%c", TagBeginString, BeginString, soh, TagBodyLength, body.Len(), soh)
+	message.Write(body.Bytes())
+	fmt.Fprintf(&message, "%d=%03d%c", TagCheckSum, checksum(message.Bytes()), soh)
+	return message.Bytes()
+}
+
+// ParseMessage decodes a message, checking its BeginString, BodyLength and CheckSum
+func ParseMessage(data []byte) (*Message, error) {
+	fields, err := splitFields(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 4 || fields[0].Tag != TagBeginString || fields[1].Tag != TagBodyLength || fields[len(fields)-1].Tag != TagCheckSum {
+		return nil, errors.New("the message must start with BeginString and BodyLength and end with CheckSum")
+	}
+	if fields[0].Value != BeginString {
+		return nil, errors.Errorf("unsupported BeginString %s", fields[0].Value)
+	}
+	if fields[2].Tag != TagMsgType {
+		return nil, errors.New("MsgType must be the third field")
+	}
+
+	bodyStart := bytes.IndexByte(data, soh) + 1
+	bodyStart += bytes.IndexByte(data[bodyStart:], soh) + 1
+	checksumStart := bytes.LastIndex(data[:len(data)-1], []byte{soh}) + 1
+	bodyLength, err := strconv.Atoi(fields[1].Value)
+	if err != nil || bodyLength != checksumStart-bodyStart {
+		return nil, errors.Errorf("wrong BodyLength %s, the body has %d bytes", fields[1].Value, checksumStart-bodyStart)
+	}
+	expectedChecksum, err := strconv.Atoi(fields[len(fields)-1].Value)
+	if err != nil || expectedChecksum != checksum(data[:checksumStart]) {
+		return nil, errors.Errorf("wrong CheckSum %s, expected %03d", fields[len(fields)-1].Value, checksum(data[:checksumStart]))
+	}
+
+	return &Message{Fields: fields[2 : len(fields)-1]}, nil
+}
+
+// ReadMessage reads the next message, up to and including its CheckSum field
+func ReadMessage(reader *bufio.Reader) ([]byte, error) {
+	var message []byte
+	for {
+		field, err := reader.ReadBytes(soh)
+		if err != nil {
+			return nil, err
+		}
+		message = append(message, field...)
+		if len(message) > maxMessageSize {
+			return nil, errors.Errorf("the message is longer than %d bytes", maxMessageSize)
+		}
+		if bytes.HasPrefix(field, []byte(strconv.Itoa(TagCheckSum)+"=")) {
+			return message, nil
+		}
+	}
+}
+
+func splitFields(data []byte) ([]Field, error) {
+	if len(data) == 0 || data[len(data)-1] != soh {
+		return nil, errors.New("the message must end with the field delimiter")
+	}
+
+	var fields []Field
+	for _, rawField := range bytes.Split(data[:len(data)-1], []byte{soh}) {
+		separator := bytes.IndexByte(rawField, '=')
+		if separator <= 0 {
+			return nil, errors.Errorf("malformed field %q", rawField)
+		}
+		tag, err := strconv.Atoi(string(rawField[:separator]))
+		if err != nil || tag <= 0 {
+			return nil, errors.Errorf("malformed tag in field %q", rawField)
+		}
+		fields = append(fields, Field{Tag: tag, Value: string(rawField[separator+1:])})
+	}
+	return fields, nil
+}
+
+func checksum(data []byte) int {
+	sum := 0
+	for _, b := range data {
+		sum += int(b)
+	}
+	return sum % 256
+}
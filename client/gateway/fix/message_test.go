@@ -0,0 +1,37 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageEncodingRoundTrip(t *testing.T) {
+	msg := NewMessage(MsgTypeHeartbeat).Set(TagSenderCompID, "GATEWAY").Set(TagTestReqID, "1")
+	data := msg.Bytes()
+	assert.Equal(t, "8=FIX.4.4\x019=22\x0135=0\x0149=GATEWAY\x01112=1\x0110=", string(data[:len(data)-4]))
+
+	parsed, err := ParseMessage(data)
+	assert.NoError(t, err)
+	assert.Equal(t, msg.Fields, parsed.Fields)
+
+	read, err := ReadMessage(bufio.NewReader(bytes.NewReader(append(data, data...))))
+	assert.NoError(t, err)
+	assert.Equal(t, data, read)
+}
+
+func TestParseMessageChecksTheFraming(t *testing.T) {
+	data := string(NewMessage(MsgTypeHeartbeat).Set(TagSenderCompID, "GATEWAY").Bytes())
+
+	_, err := ParseMessage([]byte(strings.Replace(data, "GATEWAY", "GATEWAX", 1)))
+	assert.Error(t, err, "wrong checksum")
+	_, err = ParseMessage([]byte(strings.Replace(data, "9=16", "9=15", 1)))
+	assert.Error(t, err, "wrong body length")
+	_, err = ParseMessage([]byte(strings.Replace(data, "FIX.4.4", "FIX.4.2", 1)))
+	assert.Error(t, err, "wrong begin string")
+	_, err = ParseMessage([]byte(data[:len(data)-1]))
+	assert.Error(t, err, "no trailing delimiter")
+}
@@ -0,0 +1,203 @@
+package fix
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type incomingMessage struct {
+	msg *Message
+	err error
+}
+
+// session is a FIX connection. Messages are handled one at a time, in the order they are received
+type session struct {
+	gateway *Gateway
+	conn    io.ReadWriteCloser
+	ctx     context.Context
+
+	targetCompID string
+	loggedOn     bool
+	inSeqNum     int
+	heartbeat    time.Duration
+
+	writeMux  sync.Mutex
+	outSeqNum int
+	lastSent  time.Time
+}
+
+func (s *session) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.ctx = ctx
+
+	incoming := make(chan incomingMessage)
+	go s.read(ctx, incoming)
+
+	heartbeatTicker := s.gateway.clock.NewTicker(time.Second)
+	defer heartbeatTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if s.loggedOn {
+				_ = s.send(NewMessage(MsgTypeLogout).Set(TagText, "gateway stopping"))
+			}
+			return nil
+		case <-heartbeatTicker.C():
+			if s.loggedOn && s.idleFor() >= s.heartbeat {
+				if err := s.send(NewMessage(MsgTypeHeartbeat)); err != nil {
+					return err
+				}
+			}
+		case in := <-incoming:
+			if in.err != nil {
+				if errors.Is(in.err, io.EOF) {
+					return nil
+				}
+				return in.err
+			}
+			done, err := s.handle(in.msg)
+			if err != nil || done {
+				return err
+			}
+		}
+	}
+}
+
+func (s *session) read(ctx context.Context, incoming chan<- incomingMessage) {
+	reader := bufio.NewReader(s.conn)
+	for {
+		data, err := ReadMessage(reader)
+		var msg *Message
+		if err == nil {
+			msg, err = ParseMessage(data)
+			if err != nil {
+				// garbled messages are ignored, as required by the session protocol
+				s.gateway.logger.WithError(err).Warningln("ignoring an invalid FIX message")
+				continue
+			}
+		}
+
+		select {
+		case incoming <- incomingMessage{msg: msg, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// handle processes a message and returns true if the session is over
+func (s *session) handle(msg *Message) (bool, error) {
+	senderCompID, _ := msg.Get(TagSenderCompID)
+	targetCompID, _ := msg.Get(TagTargetCompID)
+	seqNum, err := strconv.Atoi(firstValue(msg, TagMsgSeqNum))
+	if err != nil {
+		return true, s.logout(errors.New("MsgSeqNum is required"))
+	}
+
+	if !s.loggedOn {
+		if msg.MsgType() != MsgTypeLogon {
+			return true, s.logout(errors.Errorf("the first message must be a Logon, got MsgType %s", msg.MsgType()))
+		}
+		if senderCompID == "" || targetCompID != s.gateway.config.SenderCompID {
+			return true, s.logout(errors.Errorf("unknown TargetCompID %s", targetCompID))
+		}
+		s.targetCompID = senderCompID
+	} else if senderCompID != s.targetCompID || targetCompID != s.gateway.config.SenderCompID {
+		return true, s.logout(errors.Errorf("wrong CompIDs %s -> %s", senderCompID, targetCompID))
+	}
+
+	if seqNum < s.inSeqNum {
+		return true, s.logout(errors.Errorf("MsgSeqNum too low, expected %d but received %d", s.inSeqNum, seqNum))
+	}
+	if seqNum > s.inSeqNum {
+		s.gateway.logger.WithField("expected", s.inSeqNum).WithField("received", seqNum).Warningln("FIX sequence gap, the missing messages are not requested")
+	}
+	s.inSeqNum = seqNum + 1
+
+	switch msg.MsgType() {
+	case MsgTypeLogon:
+		if s.loggedOn {
+			return false, s.reject(seqNum, "already logged on")
+		}
+		if heartBtInt, err := strconv.Atoi(firstValue(msg, TagHeartBtInt)); err == nil && heartBtInt > 0 {
+			s.heartbeat = time.Duration(heartBtInt) * time.Second
+		}
+		s.loggedOn = true
+		return false, s.send(NewMessage(MsgTypeLogon).
+			Set(TagEncryptMethod, "0").
+			Set(TagHeartBtInt, strconv.Itoa(int(s.heartbeat/time.Second))))
+	case MsgTypeHeartbeat:
+		return false, nil
+	case MsgTypeTestRequest:
+		heartbeat := NewMessage(MsgTypeHeartbeat)
+		if testReqID, found := msg.Get(TagTestReqID); found {
+			heartbeat.Set(TagTestReqID, testReqID)
+		}
+		return false, s.send(heartbeat)
+	case MsgTypeLogout:
+		return true, s.send(NewMessage(MsgTypeLogout))
+	case MsgTypeNewOrderSingle:
+		return false, s.send(s.gateway.newOrder(s, msg))
+	case MsgTypeOrderCancelRequest:
+		return false, s.send(s.gateway.cancelOrder(s, msg))
+	default:
+		return false, s.reject(seqNum, "unsupported MsgType "+msg.MsgType())
+	}
+}
+
+func (s *session) reject(refSeqNum int, text string) error {
+	return s.send(NewMessage(MsgTypeReject).Set(TagRefSeqNum, strconv.Itoa(refSeqNum)).Set(TagText, text))
+}
+
+func (s *session) logout(reason error) error {
+	if err := s.send(NewMessage(MsgTypeLogout).Set(TagText, reason.Error())); err != nil {
+		return err
+	}
+	return reason
+}
+
+// send adds the header to the message (MsgType must be its first field) and writes it
+func (s *session) send(msg *Message) error {
+	s.writeMux.Lock()
+	defer s.writeMux.Unlock()
+
+	s.outSeqNum++
+	now := s.gateway.clock.Now()
+	fields := []Field{
+		msg.Fields[0],
+		{Tag: TagSenderCompID, Value: s.gateway.config.SenderCompID},
+		{Tag: TagTargetCompID, Value: valueOrNone(s.targetCompID)},
+		{Tag: TagMsgSeqNum, Value: strconv.Itoa(s.outSeqNum)},
+		{Tag: TagSendingTime, Value: now.UTC().Format(sendingTimeFormat)},
+	}
+	fields = append(fields, msg.Fields[1:]...)
+
+	if _, err := s.conn.Write((&Message{Fields: fields}).Bytes()); err != nil {
+		return errors.Wrapf(err, "failed to send FIX message %s", msg.MsgType())
+	}
+	s.lastSent = now
+	return nil
+}
+
+func (s *session) idleFor() time.Duration {
+	s.writeMux.Lock()
+	defer s.writeMux.Unlock()
+
+	return s.gateway.clock.Now().Sub(s.lastSent)
+}
+
+func firstValue(msg *Message, tag int) string {
+	value, _ := msg.Get(tag)
+	return value
+}
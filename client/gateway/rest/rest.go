@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	accountPB "github.com/InjectiveLabs/sdk-go/exchange/accounts_rpc/pb"
+	auctionPB "github.com/InjectiveLabs/sdk-go/exchange/auction_rpc/pb"
+	campaignPB "github.com/InjectiveLabs/sdk-go/exchange/campaign_rpc/pb"
+	derivativeExchangePB "github.com/InjectiveLabs/sdk-go/exchange/derivative_exchange_rpc/pb"
+	exchangePB "github.com/InjectiveLabs/sdk-go/exchange/exchange_rpc/pb"
+	explorerPB "github.com/InjectiveLabs/sdk-go/exchange/explorer_rpc/pb"
+	insurancePB "github.com/InjectiveLabs/sdk-go/exchange/insurance_rpc/pb"
+	metaPB "github.com/InjectiveLabs/sdk-go/exchange/meta_rpc/pb"
+	oraclePB "github.com/InjectiveLabs/sdk-go/exchange/oracle_rpc/pb"
+	portfolioPB "github.com/InjectiveLabs/sdk-go/exchange/portfolio_rpc/pb"
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+	tradingPB "github.com/InjectiveLabs/sdk-go/exchange/trading_rpc/pb"
+)
+
+const eventStreamContentType = "text/event-stream"
+
+type registerFunc func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error
+
+// Service is an indexer service exposed by the REST handler. The routes are the gRPC method paths
+// (e.g. POST /injective_spot_exchange_rpc.InjectiveSpotExchangeRPC/Markets), with the request message as JSON body
+type Service struct {
+	Name     string
+	explorer bool
+	register registerFunc
+}
+
+var (
+	AccountsService           = Service{Name: "accounts", register: accountPB.RegisterInjectiveAccountsRPCHandlerFromEndpoint}
+	AuctionService            = Service{Name: "auction", register: auctionPB.RegisterInjectiveAuctionRPCHandlerFromEndpoint}
+	CampaignService           = Service{Name: "campaign", register: campaignPB.RegisterInjectiveCampaignRPCHandlerFromEndpoint}
+	DerivativeExchangeService = Service{Name: "derivative_exchange", register: derivativeExchangePB.RegisterInjectiveDerivativeExchangeRPCHandlerFromEndpoint}
+	ExchangeService           = Service{Name: "exchange", register: exchangePB.RegisterInjectiveExchangeRPCHandlerFromEndpoint}
+	InsuranceService          = Service{Name: "insurance", register: insurancePB.RegisterInjectiveInsuranceRPCHandlerFromEndpoint}
+	MetaService               = Service{Name: "meta", register: metaPB.RegisterInjectiveMetaRPCHandlerFromEndpoint}
+	OracleService             = Service{Name: "oracle", register: oraclePB.RegisterInjectiveOracleRPCHandlerFromEndpoint}
+	PortfolioService          = Service{Name: "portfolio", register: portfolioPB.RegisterInjectivePortfolioRPCHandlerFromEndpoint}
+	SpotExchangeService       = Service{Name: "spot_exchange", register: spotExchangePB.RegisterInjectiveSpotExchangeRPCHandlerFromEndpoint}
+	TradingService            = Service{Name: "trading", register: tradingPB.RegisterInjectiveTradingRPCHandlerFromEndpoint}
+	// ExplorerService is served by the network explorer endpoint, the other services by the exchange endpoint
+	ExplorerService = Service{Name: "explorer", explorer: true, register: explorerPB.RegisterInjectiveExplorerRPCHandlerFromEndpoint}
+)
+
+func AllServices() []Service {
+	return []Service{
+		AccountsService,
+		AuctionService,
+		CampaignService,
+		DerivativeExchangeService,
+		ExchangeService,
+		ExplorerService,
+		InsuranceService,
+		MetaService,
+		OracleService,
+		PortfolioService,
+		SpotExchangeService,
+		TradingService,
+	}
+}
+
+// NewHandler returns an HTTP handler proxying JSON requests to the gRPC services of the network (all the services if
+// none is given), for environments that can't use gRPC. Streams are sent as newline delimited JSON, or as server-sent
+// events if the request accepts text/event-stream. The client options configure the gRPC connections like in the
+// exchange and explorer clients. The connections are closed when the context is done
+func NewHandler(ctx context.Context, network common.Network, services []Service, options ...common.ClientOption) (http.Handler, error) {
+	if len(services) == 0 {
+		services = AllServices()
+	}
+
+	exchangeDialOptions, err := dialOptions(network.ExchangeTlsCert, options)
+	if err != nil {
+		return nil, err
+	}
+	explorerDialOptions, err := dialOptions(network.ExplorerTlsCert, options)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := runtime.NewServeMux()
+	for _, service := range services {
+		endpoint, serviceDialOptions := network.ExchangeGrpcEndpoint, exchangeDialOptions
+		if service.explorer {
+			endpoint, serviceDialOptions = network.ExplorerGrpcEndpoint, explorerDialOptions
+		}
+		if err := service.register(ctx, mux, endpoint, serviceDialOptions); err != nil {
+			return nil, errors.Wrapf(err, "failed to register the %s service", service.Name)
+		}
+	}
+
+	return ServerSentEvents(mux), nil
+}
+
+func dialOptions(tlsCert credentials.TransportCredentials, options []common.ClientOption) ([]grpc.DialOption, error) {
+	opts := common.DefaultClientOptions()
+	if tlsCert != nil {
+		options = append([]common.ClientOption{common.OptionTLSCert(tlsCert)}, options...)
+	}
+	for _, opt := range options {
+		if err := opt(opts); err != nil {
+			return nil, errors.Wrap(err, "error in client option")
+		}
+	}
+	return common.GrpcDialOptions(opts), nil
+}
+
+// ServerSentEvents sends every line written by the handler as a server-sent event when the request accepts
+// text/event-stream, and calls the handler unchanged otherwise
+func ServerSentEvents(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept"), eventStreamContentType) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		writer := &eventWriter{ResponseWriter: w}
+		handler.ServeHTTP(writer, r)
+		writer.flushEvent()
+	})
+}
+
+// eventWriter buffers the response until a full line is written, and sends it as an event
+type eventWriter struct {
+	http.ResponseWriter
+	pending       []byte
+	headerWritten bool
+}
+
+func (w *eventWriter) WriteHeader(statusCode int) {
+	if w.headerWritten {
+		return
+	}
+	w.headerWritten = true
+	w.Header().Set("Content-Type", eventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *eventWriter) Write(data []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.pending = append(w.pending, data...)
+	for {
+		end := bytes.IndexByte(w.pending, '\n')
+		if end < 0 {
+			return len(data), nil
+		}
+		line := w.pending[:end]
+		w.pending = w.pending[end+1:]
+		if err := w.writeEvent(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (w *eventWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *eventWriter) flushEvent() {
+	if len(w.pending) > 0 {
+		_ = w.writeEvent(w.pending)
+		w.pending = nil
+	}
+}
+
+func (w *eventWriter) writeEvent(line []byte) error {
+	line = bytes.TrimRight(line, "\r")
+	if len(line) == 0 {
+		return nil
+	}
+	if _, err := w.ResponseWriter.Write(append(append([]byte("data: "), line...), '\n', '\n')); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
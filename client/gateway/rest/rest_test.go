@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	metaPB "github.com/InjectiveLabs/sdk-go/exchange/meta_rpc/pb"
+)
+
+type testMetaServer struct {
+	metaPB.UnimplementedInjectiveMetaRPCServer
+}
+
+func (s *testMetaServer) Version(context.Context, *metaPB.VersionRequest) (*metaPB.VersionResponse, error) {
+	return &metaPB.VersionResponse{Version: "v1.0.0"}, nil
+}
+
+func (s *testMetaServer) StreamKeepalive(_ *metaPB.StreamKeepaliveRequest, stream metaPB.InjectiveMetaRPC_StreamKeepaliveServer) error {
+	for _, event := range []string{"ping", "shutdown"} {
+		if err := stream.Send(&metaPB.StreamKeepaliveResponse{Event: event}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startTestMetaServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	metaPB.RegisterInjectiveMetaRPCServer(server, &testMetaServer{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func newTestHandler(t *testing.T) http.Handler {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	network := common.Network{ExchangeGrpcEndpoint: startTestMetaServer(t)}
+	handler, err := NewHandler(ctx, network, []Service{MetaService})
+	assert.NoError(t, err)
+	return handler
+}
+
+func post(handler http.Handler, path string, accept string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestHandlerProxiesUnaryCalls(t *testing.T) {
+	handler := newTestHandler(t)
+
+	response := post(handler, "/injective_meta_rpc.InjectiveMetaRPC/Version", "")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), `"version":"v1.0.0"`)
+}
+
+func TestHandlerSendsStreamsAsNewlineDelimitedJSON(t *testing.T) {
+	handler := newTestHandler(t)
+
+	response := post(handler, "/injective_meta_rpc.InjectiveMetaRPC/StreamKeepalive", "")
+
+	lines := strings.Split(strings.TrimSpace(response.Body.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"event":"ping"`)
+	assert.Contains(t, lines[1], `"event":"shutdown"`)
+}
+
+func TestHandlerSendsStreamsAsServerSentEvents(t *testing.T) {
+	handler := newTestHandler(t)
+
+	response := post(handler, "/injective_meta_rpc.InjectiveMetaRPC/StreamKeepalive", "text/event-stream")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "text/event-stream", response.Header().Get("Content-Type"))
+	events := strings.Split(strings.TrimSuffix(response.Body.String(), "\n\n"), "\n\n")
+	assert.Len(t, events, 2)
+	assert.True(t, strings.HasPrefix(events[0], "data: {"))
+	assert.Contains(t, events[0], `"event":"ping"`)
+	assert.Contains(t, events[1], `"event":"shutdown"`)
+}
+
+func TestServerSentEventsBuffersPartialLines(t *testing.T) {
+	handler := ServerSentEvents(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"a":`)
+		_, _ = io.WriteString(w, "1}\n{\"b\"")
+		_, _ = io.WriteString(w, ":2}")
+	}))
+
+	response := post(handler, "/", "text/event-stream")
+
+	assert.Equal(t, "data: {\"a\":1}\n\ndata: {\"b\":2}\n\n", response.Body.String())
+	assert.True(t, response.Flushed)
+}
+
+func TestServerSentEventsKeepsOtherResponsesUnchanged(t *testing.T) {
+	handler := ServerSentEvents(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, "{\"a\":1}\n")
+	}))
+
+	response := post(handler, "/", "application/json")
+
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Equal(t, "{\"a\":1}\n", response.Body.String())
+}
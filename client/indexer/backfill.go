@@ -0,0 +1,232 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const (
+	defaultBackfillMaxRetries   = 3
+	defaultBackfillRetryBackoff = time.Second
+	resumeTokenPrefix           = "backfill:v1"
+)
+
+var ErrInvalidResumeToken = errors.New("invalid backfill resume token")
+
+// ArchiveBlock is a historical block with the results of its txs and of its begin and end block
+type ArchiveBlock struct {
+	Height  int64
+	Block   *ctypes.ResultBlock
+	Results *ctypes.ResultBlockResults
+}
+
+// BlockHandler processes the blocks fetched by a Backfiller, in height order. Indexer implements it
+type BlockHandler interface {
+	HandleBlock(ctx context.Context, block ArchiveBlock) error
+}
+
+// BlockHandlerFunc is a function used as BlockHandler
+type BlockHandlerFunc func(ctx context.Context, block ArchiveBlock) error
+
+func (f BlockHandlerFunc) HandleBlock(ctx context.Context, block ArchiveBlock) error {
+	return f(ctx, block)
+}
+
+// ResumeToken is the position of a backfill: the next block to fetch and the last block of the range. Its String
+// form can be stored and passed to Backfiller.Run to resume an interrupted backfill
+type ResumeToken struct {
+	NextHeight int64
+	EndHeight  int64
+}
+
+func (t ResumeToken) String() string {
+	return fmt.Sprintf("%s:%d:%d", resumeTokenPrefix, t.NextHeight, t.EndHeight)
+}
+
+// Done returns true when all the blocks of the range were handled
+func (t ResumeToken) Done() bool {
+	return t.NextHeight > t.EndHeight
+}
+
+// ParseResumeToken parses the String form of a resume token
+func ParseResumeToken(token string) (ResumeToken, error) {
+	heights := strings.Split(strings.TrimPrefix(token, resumeTokenPrefix+":"), ":")
+	if !strings.HasPrefix(token, resumeTokenPrefix+":") || len(heights) != 2 {
+		return ResumeToken{}, errors.Wrapf(ErrInvalidResumeToken, "%q", token)
+	}
+	next, nextErr := strconv.ParseInt(heights[0], 10, 64)
+	end, endErr := strconv.ParseInt(heights[1], 10, 64)
+	if nextErr != nil || endErr != nil || next <= 0 || end <= 0 {
+		return ResumeToken{}, errors.Wrapf(ErrInvalidResumeToken, "%q has no valid heights", token)
+	}
+	return ResumeToken{NextHeight: next, EndHeight: end}, nil
+}
+
+type BackfillConfig struct {
+	// StartHeight is the first block fetched. It must be set, usually to the earliest height kept by the node
+	StartHeight int64
+	// EndHeight is the last block fetched. Zero ends at the latest block when the backfill starts
+	EndHeight int64
+	// BlocksPerSecond limits the rate of the blocks fetched from the node. Zero doesn't limit it
+	BlocksPerSecond float64
+	// MaxRetries is the number of times the fetch of a block is retried when the node fails (3 by default), waiting
+	// RetryBackoff (1 second by default) doubled after every attempt
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// OnCheckpoint is called with the resume token after every handled block, so it can be stored
+	OnCheckpoint func(token ResumeToken)
+	// Clock of the rate limit and of the retries, the real clock by default
+	Clock clock.Clock
+}
+
+// Backfiller walks the historical blocks of a node and passes them to the handlers, e.g. an Indexer for the initial
+// sync without a chain snapshot. The backfill can be interrupted and resumed with the last resume token
+type Backfiller struct {
+	source   BlockSource
+	handlers []BlockHandler
+	config   BackfillConfig
+	logger   log.Logger
+	interval time.Duration
+}
+
+func NewBackfiller(source BlockSource, config BackfillConfig, handlers ...BlockHandler) (*Backfiller, error) {
+	if config.StartHeight <= 0 {
+		return nil, errors.Errorf("invalid start height %d", config.StartHeight)
+	}
+	if config.EndHeight != 0 && config.EndHeight < config.StartHeight {
+		return nil, errors.Errorf("the end height %d is lower than the start height %d", config.EndHeight, config.StartHeight)
+	}
+	if config.BlocksPerSecond < 0 {
+		return nil, errors.Errorf("invalid rate of %v blocks per second", config.BlocksPerSecond)
+	}
+	if len(handlers) == 0 {
+		return nil, errors.New("the backfill needs at least one block handler")
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = defaultBackfillMaxRetries
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaultBackfillRetryBackoff
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	b := &Backfiller{
+		source:   source,
+		handlers: handlers,
+		config:   config,
+		logger:   log.WithField("module", "indexer-backfill"),
+	}
+	if config.BlocksPerSecond > 0 {
+		b.interval = time.Duration(float64(time.Second) / config.BlocksPerSecond)
+	}
+	return b, nil
+}
+
+// Run fetches the blocks and passes them to the handlers until the end height, an error, or the context is done.
+// An empty resumeToken starts at the configured start height, otherwise the backfill resumes from the token. The
+// returned token is the position reached, which is done once the whole range was handled
+func (b *Backfiller) Run(ctx context.Context, resumeToken string) (ResumeToken, error) {
+	token, err := b.startToken(ctx, resumeToken)
+	if err != nil {
+		return ResumeToken{}, err
+	}
+
+	var nextFetch time.Time
+	for !token.Done() {
+		if b.interval > 0 {
+			if err := b.waitUntil(ctx, nextFetch); err != nil {
+				return token, err
+			}
+			nextFetch = b.config.Clock.Now().Add(b.interval)
+		}
+
+		block, err := b.fetch(ctx, token.NextHeight)
+		if err != nil {
+			return token, err
+		}
+		for _, handler := range b.handlers {
+			if err := handler.HandleBlock(ctx, block); err != nil {
+				return token, errors.Wrapf(err, "failed to handle block %d", block.Height)
+			}
+		}
+
+		token.NextHeight++
+		if b.config.OnCheckpoint != nil {
+			b.config.OnCheckpoint(token)
+		}
+	}
+
+	b.logger.WithField("end", token.EndHeight).Infoln("backfill done")
+	return token, nil
+}
+
+func (b *Backfiller) startToken(ctx context.Context, resumeToken string) (ResumeToken, error) {
+	if resumeToken != "" {
+		return ParseResumeToken(resumeToken)
+	}
+
+	token := ResumeToken{NextHeight: b.config.StartHeight, EndHeight: b.config.EndHeight}
+	if token.EndHeight == 0 {
+		latest, err := b.source.GetLatestBlockHeight(ctx)
+		if err != nil {
+			return ResumeToken{}, errors.Wrap(err, "failed to get the latest block height")
+		}
+		if latest < token.NextHeight {
+			return ResumeToken{}, errors.Errorf("the start height %d is after the latest block %d", token.NextHeight, latest)
+		}
+		token.EndHeight = latest
+	}
+	return token, nil
+}
+
+func (b *Backfiller) fetch(ctx context.Context, height int64) (ArchiveBlock, error) {
+	backoff := b.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		block, err := b.fetchOnce(ctx, height)
+		if err == nil || attempt == b.config.MaxRetries || ctx.Err() != nil {
+			return block, err
+		}
+
+		b.logger.WithError(err).WithField("height", height).Warningln("failed to fetch the block, retrying")
+		select {
+		case <-b.config.Clock.After(backoff):
+		case <-ctx.Done():
+			return ArchiveBlock{}, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+func (b *Backfiller) fetchOnce(ctx context.Context, height int64) (ArchiveBlock, error) {
+	block, err := b.source.GetBlock(ctx, height)
+	if err != nil {
+		return ArchiveBlock{}, errors.Wrapf(err, "failed to get block %d", height)
+	}
+	results, err := b.source.GetBlockResults(ctx, height)
+	if err != nil {
+		return ArchiveBlock{}, errors.Wrapf(err, "failed to get the results of block %d", height)
+	}
+	return ArchiveBlock{Height: height, Block: block, Results: results}, nil
+}
+
+func (b *Backfiller) waitUntil(ctx context.Context, t time.Time) error {
+	wait := t.Sub(b.config.Clock.Now())
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-b.config.Clock.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
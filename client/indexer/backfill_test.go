@@ -0,0 +1,127 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+func TestBackfillFeedsTheIndexer(t *testing.T) {
+	source := &testBlockSource{t: t, latest: 5}
+	store := NewMemoryStore()
+	indexer, err := NewIndexer(source, store, Config{})
+	assert.NoError(t, err)
+
+	var checkpoints []string
+	backfiller, err := NewBackfiller(source, BackfillConfig{StartHeight: 2, OnCheckpoint: func(token ResumeToken) {
+		checkpoints = append(checkpoints, token.String())
+	}}, indexer)
+	assert.NoError(t, err)
+
+	token, err := backfiller.Run(context.Background(), "")
+	assert.NoError(t, err)
+	assert.True(t, token.Done())
+	assert.Equal(t, []string{"backfill:v1:3:5", "backfill:v1:4:5", "backfill:v1:5:5", "backfill:v1:6:5"}, checkpoints)
+	height, err := store.LastHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), height)
+
+	// the indexer continues after the backfilled blocks
+	source.latest = 6
+	last, err := indexer.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), last)
+	assert.Equal(t, []int64{2, 3, 4, 5, 6}, source.fetched)
+}
+
+func TestBackfillRetriesAndResumes(t *testing.T) {
+	fakeClock := clock.NewFake(testBlockTime)
+	source := &testBlockSource{t: t, failAt: 4}
+	var handled []int64
+	handler := BlockHandlerFunc(func(_ context.Context, block ArchiveBlock) error {
+		handled = append(handled, block.Height)
+		return nil
+	})
+	backfiller, err := NewBackfiller(source, BackfillConfig{StartHeight: 3, EndHeight: 5, MaxRetries: 2, RetryBackoff: time.Second, Clock: fakeClock}, handler)
+	assert.NoError(t, err)
+
+	type result struct {
+		token ResumeToken
+		err   error
+	}
+	done := make(chan result)
+	go func() {
+		token, err := backfiller.Run(context.Background(), "")
+		done <- result{token: token, err: err}
+	}()
+	// the backoff doubles after every failed attempt
+	assert.NoError(t, fakeClock.BlockUntil(context.Background(), 1))
+	fakeClock.Advance(time.Second)
+	assert.NoError(t, fakeClock.BlockUntil(context.Background(), 1))
+	fakeClock.Advance(2 * time.Second)
+	res := <-done
+	assert.EqualError(t, res.err, "failed to get the results of block 4: connection reset")
+	assert.Equal(t, ResumeToken{NextHeight: 4, EndHeight: 5}, res.token)
+	assert.Equal(t, []int64{3}, handled)
+
+	source.failAt = 0
+	token, err := backfiller.Run(context.Background(), res.token.String())
+	assert.NoError(t, err)
+	assert.True(t, token.Done())
+	assert.Equal(t, []int64{3, 4, 5}, handled)
+
+	// the handler errors stop the backfill at the block
+	failing := BlockHandlerFunc(func(context.Context, ArchiveBlock) error { return errors.New("disk full") })
+	backfiller, err = NewBackfiller(source, BackfillConfig{StartHeight: 3, EndHeight: 5}, failing)
+	assert.NoError(t, err)
+	token, err = backfiller.Run(context.Background(), "")
+	assert.EqualError(t, err, "failed to handle block 3: disk full")
+	assert.Equal(t, int64(3), token.NextHeight)
+}
+
+func TestBackfillRateLimit(t *testing.T) {
+	fakeClock := clock.NewFake(testBlockTime)
+	source := &testBlockSource{t: t}
+	handled := make(chan int64, 3)
+	handler := BlockHandlerFunc(func(_ context.Context, block ArchiveBlock) error {
+		handled <- block.Height
+		return nil
+	})
+	backfiller, err := NewBackfiller(source, BackfillConfig{StartHeight: 1, EndHeight: 3, BlocksPerSecond: 2, Clock: fakeClock}, handler)
+	assert.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := backfiller.Run(context.Background(), "")
+		done <- err
+	}()
+	assert.Equal(t, int64(1), <-handled)
+	for _, height := range []int64{2, 3} {
+		assert.NoError(t, fakeClock.BlockUntil(context.Background(), 1))
+		assert.Empty(t, handled)
+		fakeClock.Advance(500 * time.Millisecond)
+		assert.Equal(t, height, <-handled)
+	}
+	assert.NoError(t, <-done)
+}
+
+func TestParseResumeToken(t *testing.T) {
+	token, err := ParseResumeToken(ResumeToken{NextHeight: 10, EndHeight: 20}.String())
+	assert.NoError(t, err)
+	assert.Equal(t, ResumeToken{NextHeight: 10, EndHeight: 20}, token)
+
+	for _, invalid := range []string{"", "backfill:v2:10:20", "backfill:v1:10", "backfill:v1:a:20", "backfill:v1:0:20"} {
+		_, err := ParseResumeToken(invalid)
+		assert.True(t, errors.Is(err, ErrInvalidResumeToken), invalid)
+	}
+
+	_, err = NewBackfiller(&testBlockSource{t: t}, BackfillConfig{}, BlockHandlerFunc(nil))
+	assert.EqualError(t, err, "invalid start height 0")
+	_, err = NewBackfiller(&testBlockSource{t: t}, BackfillConfig{StartHeight: 1})
+	assert.EqualError(t, err, "the backfill needs at least one block handler")
+}
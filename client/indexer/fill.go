@@ -0,0 +1,141 @@
+// Package indexer indexes the exchange fills of the chain blocks into a pluggable store (in memory, SQLite or
+// Postgres), and answers the fills, volume and realized PnL queries from it.
+package indexer
+
+import (
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	chaintypes "github.com/InjectiveLabs/sdk-go/chain/types"
+)
+
+// Fill is a trade of a subaccount, decoded from the exchange execution events of a block
+type Fill struct {
+	Height int64
+	// Index is the position of the fill in the block, Height and Index identify the fill
+	Index         int
+	Time          time.Time
+	MarketId      string
+	SubaccountId  string
+	OrderHash     string
+	Cid           string
+	IsBuy         bool
+	IsDerivative  bool
+	IsLiquidation bool
+	ExecutionType string
+	Price         sdk.Dec
+	Quantity      sdk.Dec
+	Fee           sdk.Dec
+	// Margin and Payout are zero for the spot fills
+	Margin sdk.Dec
+	Payout sdk.Dec
+	// CumulativeFunding is the cumulative funding of the perpetual market at the fill, zero for the other markets
+	CumulativeFunding sdk.Dec
+	FeeRecipient      string
+}
+
+// Notional is the quote amount of the fill
+func (f Fill) Notional() sdk.Dec {
+	return f.Price.Mul(f.Quantity)
+}
+
+var (
+	spotExecutionEventType       = proto.MessageName(&exchangetypes.EventBatchSpotExecution{})
+	derivativeExecutionEventType = proto.MessageName(&exchangetypes.EventBatchDerivativeExecution{})
+)
+
+// DecodeFills returns the fills of the block results in execution order: the begin block events, the events of the
+// successful transactions, then the end block events
+func DecodeFills(blockTime time.Time, results *ctypes.ResultBlockResults) ([]Fill, error) {
+	events := append([]abci.Event{}, results.BeginBlockEvents...)
+	for _, txResult := range results.TxsResults {
+		if txResult.Code == 0 {
+			events = append(events, txResult.Events...)
+		}
+	}
+	events = append(events, results.EndBlockEvents...)
+
+	var fills []Fill
+	for _, event := range events {
+		if event.Type != spotExecutionEventType && event.Type != derivativeExecutionEventType {
+			continue
+		}
+		typedEvent, err := sdk.ParseTypedEvent(event)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the %s event of block %d", event.Type, results.Height)
+		}
+
+		switch e := typedEvent.(type) {
+		case *exchangetypes.EventBatchSpotExecution:
+			for _, trade := range e.Trades {
+				fills = append(fills, Fill{
+					MarketId:          e.MarketId,
+					SubaccountId:      ethcommon.BytesToHash(trade.SubaccountId).Hex(),
+					OrderHash:         ethcommon.BytesToHash(trade.OrderHash).Hex(),
+					Cid:               trade.Cid,
+					IsBuy:             e.IsBuy,
+					ExecutionType:     e.ExecutionType.String(),
+					Price:             trade.Price,
+					Quantity:          trade.Quantity,
+					Fee:               trade.Fee,
+					Margin:            sdk.ZeroDec(),
+					Payout:            sdk.ZeroDec(),
+					CumulativeFunding: sdk.ZeroDec(),
+					FeeRecipient:      feeRecipient(trade.FeeRecipientAddress),
+				})
+			}
+		case *exchangetypes.EventBatchDerivativeExecution:
+			cumulativeFunding := sdk.ZeroDec()
+			if e.CumulativeFunding != nil {
+				cumulativeFunding = *e.CumulativeFunding
+			}
+			for _, trade := range e.Trades {
+				if trade.PositionDelta == nil {
+					continue
+				}
+				fills = append(fills, Fill{
+					MarketId:          e.MarketId,
+					SubaccountId:      ethcommon.BytesToHash(trade.SubaccountId).Hex(),
+					OrderHash:         ethcommon.BytesToHash(trade.OrderHash).Hex(),
+					Cid:               trade.Cid,
+					IsBuy:             trade.PositionDelta.IsLong,
+					IsDerivative:      true,
+					IsLiquidation:     e.IsLiquidation,
+					ExecutionType:     e.ExecutionType.String(),
+					Price:             trade.PositionDelta.ExecutionPrice,
+					Quantity:          trade.PositionDelta.ExecutionQuantity,
+					Fee:               trade.Fee,
+					Margin:            trade.PositionDelta.ExecutionMargin,
+					Payout:            trade.Payout,
+					CumulativeFunding: cumulativeFunding,
+					FeeRecipient:      feeRecipient(trade.FeeRecipientAddress),
+				})
+			}
+		}
+	}
+
+	for i := range fills {
+		fills[i].Height = results.Height
+		fills[i].Index = i
+		fills[i].Time = blockTime.UTC()
+	}
+	return fills, nil
+}
+
+func feeRecipient(address []byte) string {
+	if len(address) == 0 {
+		return ""
+	}
+	bech32Address, err := sdk.Bech32ifyAddressBytes(chaintypes.Bech32PrefixAccAddr, address)
+	if err != nil {
+		return ""
+	}
+	return bech32Address
+}
@@ -0,0 +1,142 @@
+package indexer
+
+import (
+	"context"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+const defaultPollInterval = time.Second
+
+// BlockSource provides the blocks to index, tm.TendermintClient implements it
+type BlockSource interface {
+	GetBlock(ctx context.Context, height int64) (*ctypes.ResultBlock, error)
+	GetBlockResults(ctx context.Context, height int64) (*ctypes.ResultBlockResults, error)
+	GetLatestBlockHeight(ctx context.Context) (int64, error)
+}
+
+type Config struct {
+	// StartHeight is the first block indexed when the store is empty. Zero starts at the latest block
+	StartHeight int64
+	// PollInterval is the time between the checks for new blocks in Run
+	PollInterval time.Duration
+	// OnBlock is called after every indexed block
+	OnBlock func(height int64, fills []Fill)
+	// Clock of the poll interval, the real clock by default
+	Clock clock.Clock
+}
+
+// Indexer indexes the fills of the blocks into the store, resuming after the last indexed block
+type Indexer struct {
+	source BlockSource
+	store  Store
+	config Config
+	logger log.Logger
+}
+
+func NewIndexer(source BlockSource, store Store, config Config) (*Indexer, error) {
+	if config.StartHeight < 0 {
+		return nil, errors.Errorf("invalid start height %d", config.StartHeight)
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	config.Clock = clock.OrReal(config.Clock)
+
+	return &Indexer{
+		source: source,
+		store:  store,
+		config: config,
+		logger: log.WithField("module", "indexer"),
+	}, nil
+}
+
+// Run indexes the new blocks every poll interval until the context is done. Errors are logged and the indexing is
+// retried from the last saved block
+func (i *Indexer) Run(ctx context.Context) {
+	ticker := i.config.Clock.NewTicker(i.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := i.Sync(ctx); err != nil && ctx.Err() == nil {
+			i.logger.WithError(err).Warningln("failed to index the new blocks")
+		}
+
+		select {
+		case <-ticker.C():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Sync indexes the blocks from the last indexed one up to the latest one, and returns the last indexed height
+func (i *Indexer) Sync(ctx context.Context) (int64, error) {
+	last, err := i.store.LastHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	latest, err := i.source.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return last, errors.Wrap(err, "failed to get the latest block height")
+	}
+
+	next := last + 1
+	if last == 0 {
+		next = i.config.StartHeight
+		if next == 0 {
+			next = latest
+		}
+	}
+	for height := next; height <= latest; height++ {
+		if err := ctx.Err(); err != nil {
+			return last, err
+		}
+		if _, err := i.IndexBlock(ctx, height); err != nil {
+			return last, err
+		}
+		last = height
+	}
+	return last, nil
+}
+
+// IndexBlock decodes and saves the fills of a block. Indexing a block again does not duplicate its fills
+func (i *Indexer) IndexBlock(ctx context.Context, height int64) ([]Fill, error) {
+	block, err := i.source.GetBlock(ctx, height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block %d", height)
+	}
+	results, err := i.source.GetBlockResults(ctx, height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the results of block %d", height)
+	}
+	return i.indexBlock(ctx, ArchiveBlock{Height: height, Block: block, Results: results})
+}
+
+// HandleBlock indexes a block fetched by a Backfiller. Once the backfill is done, Run and Sync continue after the
+// last backfilled block
+func (i *Indexer) HandleBlock(ctx context.Context, block ArchiveBlock) error {
+	_, err := i.indexBlock(ctx, block)
+	return err
+}
+
+func (i *Indexer) indexBlock(ctx context.Context, block ArchiveBlock) ([]Fill, error) {
+	fills, err := DecodeFills(block.Block.Block.Time, block.Results)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.store.SaveBlock(ctx, block.Height, fills); err != nil {
+		return nil, errors.Wrapf(err, "failed to save block %d", block.Height)
+	}
+
+	i.logger.WithField("height", block.Height).Debugf("indexed %d fills", len(fills))
+	if i.config.OnBlock != nil {
+		i.config.OnBlock(block.Height, fills)
+	}
+	return fills, nil
+}
@@ -0,0 +1,194 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+const (
+	testSubaccountId  = "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000"
+	otherSubaccountId = "0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000"
+	spotMarketId      = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	perpMarketId      = "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+)
+
+var testBlockTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func typedEvent(t *testing.T, msg proto.Message) abci.Event {
+	event, err := sdk.TypedEventToEvent(msg)
+	assert.NoError(t, err)
+	return abci.Event(event)
+}
+
+func spotExecution(t *testing.T, isBuy bool, subaccountId string, price, quantity string) abci.Event {
+	return typedEvent(t, &exchangetypes.EventBatchSpotExecution{
+		MarketId:      spotMarketId,
+		IsBuy:         isBuy,
+		ExecutionType: exchangetypes.ExecutionType_LimitMatchNewOrder,
+		Trades: []*exchangetypes.TradeLog{{
+			Quantity:            sdk.MustNewDecFromStr(quantity),
+			Price:               sdk.MustNewDecFromStr(price),
+			SubaccountId:        ethcommon.HexToHash(subaccountId).Bytes(),
+			Fee:                 sdk.MustNewDecFromStr("0.1"),
+			OrderHash:           ethcommon.HexToHash("0x01").Bytes(),
+			FeeRecipientAddress: sdk.AccAddress(ethcommon.HexToAddress("0xbdaedec95d563fb05240d6e01821008454c24c36").Bytes()),
+			Cid:                 "spot-order",
+		}},
+	})
+}
+
+func testBlockResults(t *testing.T, height int64) *ctypes.ResultBlockResults {
+	cumulativeFunding := sdk.MustNewDecFromStr("-0.25")
+	return &ctypes.ResultBlockResults{
+		Height: height,
+		TxsResults: []*abci.ResponseDeliverTx{
+			{Code: 0, Events: []abci.Event{{Type: "message"}, spotExecution(t, true, testSubaccountId, "1.5", "10")}},
+			{Code: 5, Events: []abci.Event{spotExecution(t, true, otherSubaccountId, "1.5", "10")}},
+		},
+		EndBlockEvents: []abci.Event{
+			typedEvent(t, &exchangetypes.EventBatchDerivativeExecution{
+				MarketId:          perpMarketId,
+				IsLiquidation:     true,
+				CumulativeFunding: &cumulativeFunding,
+				ExecutionType:     exchangetypes.ExecutionType_MarketLiquidation,
+				Trades: []*exchangetypes.DerivativeTradeLog{
+					{
+						SubaccountId: ethcommon.HexToHash(otherSubaccountId).Bytes(),
+						PositionDelta: &exchangetypes.PositionDelta{
+							IsLong:            false,
+							ExecutionQuantity: sdk.MustNewDecFromStr("2"),
+							ExecutionMargin:   sdk.MustNewDecFromStr("40"),
+							ExecutionPrice:    sdk.MustNewDecFromStr("20"),
+						},
+						Payout:    sdk.MustNewDecFromStr("3"),
+						Fee:       sdk.ZeroDec(),
+						OrderHash: ethcommon.HexToHash("0x02").Bytes(),
+					},
+					{SubaccountId: ethcommon.HexToHash(otherSubaccountId).Bytes(), Payout: sdk.ZeroDec(), Fee: sdk.ZeroDec()},
+				},
+			}),
+		},
+	}
+}
+
+func TestDecodeFills(t *testing.T) {
+	fills, err := DecodeFills(testBlockTime.In(time.FixedZone("CET", 3600)), testBlockResults(t, 7))
+	assert.NoError(t, err)
+	assert.Len(t, fills, 2, "the fills of failed transactions and the trades without position delta are skipped")
+
+	spot := fills[0]
+	assert.Equal(t, int64(7), spot.Height)
+	assert.Equal(t, 0, spot.Index)
+	assert.Equal(t, testBlockTime, spot.Time)
+	assert.Equal(t, spotMarketId, spot.MarketId)
+	assert.Equal(t, testSubaccountId, spot.SubaccountId)
+	assert.Equal(t, ethcommon.HexToHash("0x01").Hex(), spot.OrderHash)
+	assert.Equal(t, "spot-order", spot.Cid)
+	assert.True(t, spot.IsBuy)
+	assert.False(t, spot.IsDerivative)
+	assert.Equal(t, exchangetypes.ExecutionType_LimitMatchNewOrder.String(), spot.ExecutionType)
+	assert.Equal(t, "15.000000000000000000", spot.Notional().String())
+	assert.Equal(t, "inj1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r", spot.FeeRecipient)
+
+	derivative := fills[1]
+	assert.Equal(t, 1, derivative.Index)
+	assert.Equal(t, otherSubaccountId, derivative.SubaccountId)
+	assert.False(t, derivative.IsBuy)
+	assert.True(t, derivative.IsDerivative)
+	assert.True(t, derivative.IsLiquidation)
+	assert.Equal(t, "20.000000000000000000", derivative.Price.String())
+	assert.Equal(t, "40.000000000000000000", derivative.Margin.String())
+	assert.Equal(t, "3.000000000000000000", derivative.Payout.String())
+	assert.Equal(t, "-0.250000000000000000", derivative.CumulativeFunding.String())
+	assert.True(t, spot.CumulativeFunding.IsZero())
+	assert.Empty(t, derivative.FeeRecipient)
+
+	_, err = DecodeFills(testBlockTime, &ctypes.ResultBlockResults{
+		EndBlockEvents: []abci.Event{{Type: spotExecutionEventType, Attributes: []abci.EventAttribute{{Key: "trades", Value: "{"}}}},
+	})
+	assert.Error(t, err)
+}
+
+// testBlockSource serves testBlockResults for every height up to latest
+type testBlockSource struct {
+	t       *testing.T
+	latest  int64
+	failAt  int64
+	fetched []int64
+}
+
+func (s *testBlockSource) GetBlock(_ context.Context, height int64) (*ctypes.ResultBlock, error) {
+	return &ctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: height, Time: testBlockTime.Add(time.Duration(height) * time.Second)}}}, nil
+}
+
+func (s *testBlockSource) GetBlockResults(_ context.Context, height int64) (*ctypes.ResultBlockResults, error) {
+	if height == s.failAt {
+		return nil, errors.New("connection reset")
+	}
+	s.fetched = append(s.fetched, height)
+	return testBlockResults(s.t, height), nil
+}
+
+func (s *testBlockSource) GetLatestBlockHeight(_ context.Context) (int64, error) {
+	return s.latest, nil
+}
+
+func TestIndexerSyncResumesAfterTheLastSavedBlock(t *testing.T) {
+	source := &testBlockSource{t: t, latest: 3, failAt: 5}
+	store := NewMemoryStore()
+	var indexed []int64
+	indexer, err := NewIndexer(source, store, Config{StartHeight: 2, OnBlock: func(height int64, fills []Fill) {
+		indexed = append(indexed, height)
+	}})
+	assert.NoError(t, err)
+
+	last, err := indexer.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), last)
+
+	source.latest = 6
+	last, err = indexer.Sync(context.Background())
+	assert.EqualError(t, err, "failed to get the results of block 5: connection reset")
+	assert.Equal(t, int64(4), last)
+
+	source.failAt = 0
+	last, err = indexer.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), last)
+	assert.Equal(t, []int64{2, 3, 4, 5, 6}, indexed)
+
+	_, err = indexer.IndexBlock(context.Background(), 3)
+	assert.NoError(t, err)
+	fills, err := store.Fills(context.Background(), FillFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, fills, 10, "indexing a block again does not duplicate its fills")
+	height, err := store.LastHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), height)
+}
+
+func TestIndexerStartsAtTheLatestBlock(t *testing.T) {
+	source := &testBlockSource{t: t, latest: 100}
+	indexer, err := NewIndexer(source, NewMemoryStore(), Config{})
+	assert.NoError(t, err)
+
+	last, err := indexer.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), last)
+	assert.Equal(t, []int64{100}, source.fetched)
+
+	_, err = NewIndexer(source, NewMemoryStore(), Config{StartHeight: -1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package indexer
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MarketVolume is the traded volume of a market. Every trade is counted once per side, like the fills
+type MarketVolume struct {
+	MarketId string
+	Fills    int
+	Quantity sdk.Dec
+	Notional sdk.Dec
+	Fees     sdk.Dec
+}
+
+// PnL is the realized profit and loss of a subaccount in a market, in the quote denom. The entry price of a position
+// is the average price of the fills that opened it
+type PnL struct {
+	SubaccountId string
+	MarketId     string
+	Fills        int
+	// Realized excludes the fees
+	Realized sdk.Dec
+	Fees     sdk.Dec
+	// Net is Realized minus Fees
+	Net sdk.Dec
+	// Position is the open quantity after the fills, negative when short
+	Position          sdk.Dec
+	AverageEntryPrice sdk.Dec
+}
+
+// FillsBySubaccount returns the most recent fills of the subaccount, at most limit if positive
+func FillsBySubaccount(ctx context.Context, store Store, subaccountId string, limit int) ([]Fill, error) {
+	return store.Fills(ctx, FillFilter{SubaccountId: subaccountId, Limit: limit, NewestFirst: true})
+}
+
+// VolumeByMarket returns the volume of every market between from (inclusive) and to (exclusive), sorted by market id.
+// Zero times leave the range open
+func VolumeByMarket(ctx context.Context, store Store, from, to time.Time) ([]MarketVolume, error) {
+	fills, err := store.Fills(ctx, FillFilter{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[string]*MarketVolume)
+	for _, fill := range fills {
+		volume, found := volumes[fill.MarketId]
+		if !found {
+			volume = &MarketVolume{MarketId: fill.MarketId, Quantity: sdk.ZeroDec(), Notional: sdk.ZeroDec(), Fees: sdk.ZeroDec()}
+			volumes[fill.MarketId] = volume
+		}
+		volume.Fills++
+		volume.Quantity = volume.Quantity.Add(fill.Quantity)
+		volume.Notional = volume.Notional.Add(fill.Notional())
+		volume.Fees = volume.Fees.Add(fill.Fee)
+	}
+
+	result := make([]MarketVolume, 0, len(volumes))
+	for _, volume := range volumes {
+		result = append(result, *volume)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].MarketId < result[j].MarketId })
+	return result, nil
+}
+
+// RealizedPnL returns the realized PnL of the subaccount in the market from all its indexed fills
+func RealizedPnL(ctx context.Context, store Store, subaccountId, marketId string) (PnL, error) {
+	fills, err := store.Fills(ctx, FillFilter{SubaccountId: subaccountId, MarketId: marketId})
+	if err != nil {
+		return PnL{}, err
+	}
+
+	pnl := ComputeRealizedPnL(fills)
+	pnl.SubaccountId = subaccountId
+	pnl.MarketId = marketId
+	return pnl, nil
+}
+
+// ComputeRealizedPnL returns the realized PnL of fills of one subaccount in one market, in execution order. A fill
+// reducing the position realizes the difference between its price and the average entry price, a fill flipping the
+// position opens the remaining quantity at its price
+func ComputeRealizedPnL(fills []Fill) PnL {
+	pnl := PnL{
+		Realized:          sdk.ZeroDec(),
+		Fees:              sdk.ZeroDec(),
+		Position:          sdk.ZeroDec(),
+		AverageEntryPrice: sdk.ZeroDec(),
+	}
+
+	for _, fill := range fills {
+		pnl.Fills++
+		pnl.Fees = pnl.Fees.Add(fill.Fee)
+		if fill.Quantity.IsZero() {
+			continue
+		}
+
+		quantity := fill.Quantity
+		if !fill.IsBuy {
+			quantity = quantity.Neg()
+		}
+
+		if pnl.Position.IsZero() || pnl.Position.IsPositive() == quantity.IsPositive() {
+			size := pnl.Position.Abs()
+			pnl.AverageEntryPrice = size.Mul(pnl.AverageEntryPrice).Add(fill.Quantity.Mul(fill.Price)).Quo(size.Add(fill.Quantity))
+			pnl.Position = pnl.Position.Add(quantity)
+			continue
+		}
+
+		closed := sdk.MinDec(fill.Quantity, pnl.Position.Abs())
+		realized := closed.Mul(fill.Price.Sub(pnl.AverageEntryPrice))
+		if pnl.Position.IsNegative() {
+			realized = realized.Neg()
+		}
+		pnl.Realized = pnl.Realized.Add(realized)
+
+		pnl.Position = pnl.Position.Add(quantity)
+		switch {
+		case pnl.Position.IsZero():
+			pnl.AverageEntryPrice = sdk.ZeroDec()
+		case fill.Quantity.GT(closed):
+			pnl.AverageEntryPrice = fill.Price
+		}
+	}
+
+	pnl.Net = pnl.Realized.Sub(pnl.Fees)
+	return pnl
+}
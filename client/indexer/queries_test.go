@@ -0,0 +1,96 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFill(height int64, subaccountId, marketId string, isBuy bool, price, quantity, fee string) Fill {
+	return Fill{
+		Height:            height,
+		Time:              testBlockTime.Add(time.Duration(height) * time.Hour),
+		MarketId:          marketId,
+		SubaccountId:      subaccountId,
+		IsBuy:             isBuy,
+		Price:             sdk.MustNewDecFromStr(price),
+		Quantity:          sdk.MustNewDecFromStr(quantity),
+		Fee:               sdk.MustNewDecFromStr(fee),
+		Margin:            sdk.ZeroDec(),
+		Payout:            sdk.ZeroDec(),
+		CumulativeFunding: sdk.ZeroDec(),
+	}
+}
+
+func TestComputeRealizedPnL(t *testing.T) {
+	pnl := ComputeRealizedPnL([]Fill{
+		testFill(1, testSubaccountId, perpMarketId, true, "10", "2", "0.1"),
+		testFill(2, testSubaccountId, perpMarketId, true, "13", "1", "0.1"),
+		// closes 2 of 3 at an average entry of 11
+		testFill(3, testSubaccountId, perpMarketId, false, "12", "2", "0.1"),
+		// closes the last one and opens a short of 2 at 8
+		testFill(4, testSubaccountId, perpMarketId, false, "8", "3", "0.1"),
+		testFill(5, testSubaccountId, perpMarketId, true, "6", "1", "0"),
+	})
+
+	assert.Equal(t, 5, pnl.Fills)
+	// 2 * (12 - 11) + 1 * (8 - 11) + 1 * (8 - 6)
+	assert.Equal(t, "1.000000000000000000", pnl.Realized.String())
+	assert.Equal(t, "0.400000000000000000", pnl.Fees.String())
+	assert.Equal(t, "0.600000000000000000", pnl.Net.String())
+	assert.Equal(t, "-1.000000000000000000", pnl.Position.String())
+	assert.Equal(t, "8.000000000000000000", pnl.AverageEntryPrice.String())
+
+	flat := ComputeRealizedPnL([]Fill{
+		testFill(1, testSubaccountId, spotMarketId, false, "5", "4", "0"),
+		testFill(2, testSubaccountId, spotMarketId, true, "4", "4", "0"),
+		testFill(3, testSubaccountId, spotMarketId, true, "4", "0", "0.5"),
+	})
+	assert.Equal(t, "4.000000000000000000", flat.Realized.String())
+	assert.True(t, flat.Position.IsZero())
+	assert.True(t, flat.AverageEntryPrice.IsZero())
+	assert.Equal(t, "3.500000000000000000", flat.Net.String())
+}
+
+func TestQueries(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	buy := testFill(1, testSubaccountId, spotMarketId, true, "2", "10", "0.2")
+	sell := testFill(1, otherSubaccountId, spotMarketId, false, "2", "10", "0.1")
+	sell.Index = 1
+	assert.NoError(t, store.SaveBlock(ctx, 1, []Fill{buy, sell}))
+	fill := testFill(2, testSubaccountId, spotMarketId, false, "3", "4", "0.1")
+	perpFill := testFill(2, testSubaccountId, perpMarketId, true, "20", "1", "0.5")
+	perpFill.Index = 1
+	assert.NoError(t, store.SaveBlock(ctx, 2, []Fill{fill, perpFill}))
+	// indexing the first block again
+	assert.NoError(t, store.SaveBlock(ctx, 1, []Fill{sell}))
+
+	fills, err := FillsBySubaccount(ctx, store, testSubaccountId, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{perpMarketId, spotMarketId}, []string{fills[0].MarketId, fills[1].MarketId})
+	assert.Equal(t, []int64{2, 2}, []int64{fills[0].Height, fills[1].Height})
+
+	volumes, err := VolumeByMarket(ctx, store, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 2)
+	assert.Equal(t, spotMarketId, volumes[0].MarketId)
+	assert.Equal(t, 3, volumes[0].Fills)
+	assert.Equal(t, "24.000000000000000000", volumes[0].Quantity.String())
+	assert.Equal(t, "52.000000000000000000", volumes[0].Notional.String())
+	assert.Equal(t, "0.400000000000000000", volumes[0].Fees.String())
+
+	volumes, err = VolumeByMarket(ctx, store, testBlockTime.Add(2*time.Hour), time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, []int{volumes[0].Fills, volumes[1].Fills})
+
+	pnl, err := RealizedPnL(ctx, store, testSubaccountId, spotMarketId)
+	assert.NoError(t, err)
+	assert.Equal(t, testSubaccountId, pnl.SubaccountId)
+	assert.Equal(t, 2, pnl.Fills)
+	assert.Equal(t, "4.000000000000000000", pnl.Realized.String())
+	assert.Equal(t, "6.000000000000000000", pnl.Position.String())
+}
@@ -0,0 +1,237 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// Dialect is the SQL flavour of the database of a SQLStore
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+)
+
+// The decimals are stored as text to keep their precision, and the block times as Unix milliseconds
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS indexer_fills (
+		height BIGINT NOT NULL,
+		idx INTEGER NOT NULL,
+		block_time BIGINT NOT NULL,
+		market_id TEXT NOT NULL,
+		subaccount_id TEXT NOT NULL,
+		order_hash TEXT NOT NULL,
+		cid TEXT NOT NULL,
+		is_buy BOOLEAN NOT NULL,
+		is_derivative BOOLEAN NOT NULL,
+		is_liquidation BOOLEAN NOT NULL,
+		execution_type TEXT NOT NULL,
+		price TEXT NOT NULL,
+		quantity TEXT NOT NULL,
+		fee TEXT NOT NULL,
+		margin TEXT NOT NULL,
+		payout TEXT NOT NULL,
+		cumulative_funding TEXT NOT NULL,
+		fee_recipient TEXT NOT NULL,
+		PRIMARY KEY (height, idx)
+	)`,
+	`CREATE INDEX IF NOT EXISTS indexer_fills_subaccount ON indexer_fills (subaccount_id, height, idx)`,
+	`CREATE INDEX IF NOT EXISTS indexer_fills_market ON indexer_fills (market_id, height, idx)`,
+	`CREATE TABLE IF NOT EXISTS indexer_state (
+		id INTEGER PRIMARY KEY,
+		height BIGINT NOT NULL
+	)`,
+}
+
+const (
+	fillColumns = "height, idx, block_time, market_id, subaccount_id, order_hash, cid, is_buy, is_derivative, " +
+		"is_liquidation, execution_type, price, quantity, fee, margin, payout, cumulative_funding, fee_recipient"
+	insertFillQuery = "INSERT INTO indexer_fills (" + fillColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) " +
+		"ON CONFLICT (height, idx) DO NOTHING"
+	saveHeightQuery = "INSERT INTO indexer_state (id, height) VALUES (1, ?) " +
+		"ON CONFLICT (id) DO UPDATE SET height = excluded.height WHERE excluded.height > indexer_state.height"
+	lastHeightQuery = "SELECT height FROM indexer_state WHERE id = 1"
+)
+
+// SQLStore keeps the fills in a SQLite or Postgres database. The caller opens the database with the driver of its
+// choice (e.g. github.com/mattn/go-sqlite3 or github.com/lib/pq), so the SDK does not depend on the drivers
+type SQLStore struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+var _ Store = (*SQLStore)(nil)
+
+// NewSQLStore creates the indexer tables if they do not exist
+func NewSQLStore(ctx context.Context, db *sql.DB, dialect Dialect) (*SQLStore, error) {
+	if dialect != DialectSQLite && dialect != DialectPostgres {
+		return nil, errors.Errorf("unsupported SQL dialect %q", dialect)
+	}
+
+	store := &SQLStore{db: db, dialect: dialect}
+	for _, statement := range sqlSchema {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return nil, errors.Wrap(err, "failed to create the indexer tables")
+		}
+	}
+	return store, nil
+}
+
+func (s *SQLStore) LastHeight(ctx context.Context) (int64, error) {
+	var height int64
+	err := s.db.QueryRowContext(ctx, s.rebind(lastHeightQuery)).Scan(&height)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read the indexed height")
+	}
+	return height, nil
+}
+
+func (s *SQLStore) SaveBlock(ctx context.Context, height int64, fills []Fill) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin the transaction")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if len(fills) > 0 {
+		statement, err := tx.PrepareContext(ctx, s.rebind(insertFillQuery))
+		if err != nil {
+			return errors.Wrap(err, "failed to prepare the fill insert")
+		}
+		defer statement.Close()
+
+		for _, fill := range fills {
+			_, err := statement.ExecContext(ctx,
+				fill.Height, fill.Index, fill.Time.UnixMilli(), fill.MarketId, fill.SubaccountId, fill.OrderHash,
+				fill.Cid, fill.IsBuy, fill.IsDerivative, fill.IsLiquidation, fill.ExecutionType, fill.Price.String(),
+				fill.Quantity.String(), fill.Fee.String(), fill.Margin.String(), fill.Payout.String(),
+				fill.CumulativeFunding.String(), fill.FeeRecipient,
+			)
+			if err != nil {
+				return errors.Wrapf(err, "failed to insert the fill %d of block %d", fill.Index, fill.Height)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, s.rebind(saveHeightQuery), height); err != nil {
+		return errors.Wrapf(err, "failed to save the indexed height %d", height)
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to commit block %d", height)
+	}
+	return nil
+}
+
+func (s *SQLStore) Fills(ctx context.Context, filter FillFilter) ([]Fill, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.SubaccountId != "" {
+		conditions = append(conditions, "subaccount_id = ?")
+		args = append(args, filter.SubaccountId)
+	}
+	if filter.MarketId != "" {
+		conditions = append(conditions, "market_id = ?")
+		args = append(args, filter.MarketId)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "block_time >= ?")
+		args = append(args, filter.From.UnixMilli())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "block_time < ?")
+		args = append(args, filter.To.UnixMilli())
+	}
+
+	query := "SELECT " + fillColumns + " FROM indexer_fills"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if filter.NewestFirst {
+		query += " ORDER BY height DESC, idx DESC"
+	} else {
+		query += " ORDER BY height, idx"
+	}
+	if filter.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query the fills")
+	}
+	defer rows.Close()
+
+	var fills []Fill
+	for rows.Next() {
+		var (
+			fill                                                    Fill
+			blockTime                                               int64
+			price, quantity, fee, margin, payout, cumulativeFunding string
+		)
+		err := rows.Scan(
+			&fill.Height, &fill.Index, &blockTime, &fill.MarketId, &fill.SubaccountId, &fill.OrderHash, &fill.Cid,
+			&fill.IsBuy, &fill.IsDerivative, &fill.IsLiquidation, &fill.ExecutionType, &price, &quantity, &fee,
+			&margin, &payout, &cumulativeFunding, &fill.FeeRecipient,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read a fill")
+		}
+		fill.Time = time.UnixMilli(blockTime).UTC()
+		decimals := []struct {
+			value  string
+			target *sdk.Dec
+		}{
+			{price, &fill.Price},
+			{quantity, &fill.Quantity},
+			{fee, &fill.Fee},
+			{margin, &fill.Margin},
+			{payout, &fill.Payout},
+			{cumulativeFunding, &fill.CumulativeFunding},
+		}
+		for _, dec := range decimals {
+			if *dec.target, err = sdk.NewDecFromStr(dec.value); err != nil {
+				return nil, errors.Wrapf(err, "invalid decimal in the fill %d of block %d", fill.Index, fill.Height)
+			}
+		}
+		fills = append(fills, fill)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the fills")
+	}
+	return fills, nil
+}
+
+// rebind replaces the ? placeholders with the numbered placeholders of Postgres
+func (s *SQLStore) rebind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+
+	var rebound strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			rebound.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		rebound.WriteRune(r)
+	}
+	return rebound.String()
+}
@@ -0,0 +1,219 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedStatement struct {
+	query string
+	args  []driver.Value
+}
+
+// recordingConn is a database/sql driver connection recording the statements run, and answering the queries with
+// the rows set by the test
+type recordingConn struct {
+	mux         sync.Mutex
+	statements  []recordedStatement
+	rows        [][]driver.Value
+	failInserts bool
+}
+
+func (c *recordingConn) record(query string, args []driver.Value) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.statements = append(c.statements, recordedStatement{query: query, args: args})
+}
+
+func (c *recordingConn) queries() []string {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	queries := make([]string, len(c.statements))
+	for i, statement := range c.statements {
+		queries[i] = statement.query
+	}
+	return queries
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{conn: c, query: query}, nil
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.record("BEGIN", nil)
+	return recordingTx{conn: c}, nil
+}
+
+type recordingTx struct {
+	conn *recordingConn
+}
+
+func (tx recordingTx) Commit() error {
+	tx.conn.record("COMMIT", nil)
+	return nil
+}
+
+func (tx recordingTx) Rollback() error {
+	tx.conn.record("ROLLBACK", nil)
+	return nil
+}
+
+type recordingStmt struct {
+	conn  *recordingConn
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.conn.failInserts && strings.HasPrefix(s.query, "INSERT INTO indexer_fills") {
+		return nil, errors.New("disk full")
+	}
+	s.conn.record(s.query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.record(s.query, args)
+	return &recordingRows{values: s.conn.rows}, nil
+}
+
+type recordingRows struct {
+	values [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string {
+	if len(r.values) == 0 {
+		return []string{"height"}
+	}
+	columns := make([]string, len(r.values[0]))
+	for i := range columns {
+		columns[i] = fmt.Sprintf("column_%d", i)
+	}
+	return columns
+}
+
+func (r *recordingRows) Close() error { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+type recordingConnector struct {
+	conn *recordingConn
+}
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c recordingConnector) Driver() driver.Driver                        { return nil }
+
+func newRecordingStore(t *testing.T, dialect Dialect) (*SQLStore, *recordingConn) {
+	conn := &recordingConn{}
+	db := sql.OpenDB(recordingConnector{conn: conn})
+	t.Cleanup(func() { db.Close() })
+
+	store, err := NewSQLStore(context.Background(), db, dialect)
+	assert.NoError(t, err)
+	return store, conn
+}
+
+func TestSQLStoreCreatesTheTables(t *testing.T) {
+	_, conn := newRecordingStore(t, DialectSQLite)
+	assert.Equal(t, sqlSchema, conn.queries())
+
+	_, err := NewSQLStore(context.Background(), sql.OpenDB(recordingConnector{conn: conn}), "mysql")
+	assert.EqualError(t, err, `unsupported SQL dialect "mysql"`)
+}
+
+func TestSQLStoreSaveBlock(t *testing.T) {
+	store, conn := newRecordingStore(t, DialectPostgres)
+	conn.statements = nil
+
+	fill := testFill(12, testSubaccountId, spotMarketId, true, "1.5", "10", "0.01")
+	fill.Index = 3
+	assert.NoError(t, store.SaveBlock(context.Background(), 12, []Fill{fill}))
+
+	statements := conn.statements
+	assert.Len(t, statements, 4)
+	assert.Equal(t, "BEGIN", statements[0].query)
+	assert.Contains(t, statements[1].query, "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)")
+	assert.Equal(t, []driver.Value{
+		int64(12), int64(3), fill.Time.UnixMilli(), spotMarketId, testSubaccountId, "", "", true, false, false, "",
+		"1.500000000000000000", "10.000000000000000000", "0.010000000000000000", "0.000000000000000000",
+		"0.000000000000000000", "0.000000000000000000", "",
+	}, statements[1].args)
+	assert.Equal(t, "INSERT INTO indexer_state (id, height) VALUES (1, $1) ON CONFLICT (id) DO UPDATE SET height = excluded.height WHERE excluded.height > indexer_state.height", statements[2].query)
+	assert.Equal(t, []driver.Value{int64(12)}, statements[2].args)
+	assert.Equal(t, "COMMIT", statements[3].query)
+
+	conn.statements = nil
+	conn.failInserts = true
+	err := store.SaveBlock(context.Background(), 13, []Fill{fill})
+	assert.EqualError(t, err, "failed to insert the fill 3 of block 12: disk full")
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, conn.queries())
+}
+
+func TestSQLStoreQueries(t *testing.T) {
+	store, conn := newRecordingStore(t, DialectSQLite)
+
+	height, err := store.LastHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), height)
+	conn.rows = [][]driver.Value{{int64(42)}}
+	height, err = store.LastHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), height)
+
+	expected := testFill(12, testSubaccountId, spotMarketId, true, "1.5", "10", "0.01")
+	expected.Index = 3
+	expected.IsDerivative = true
+	expected.ExecutionType = "limitFill"
+	// SQLite returns the booleans as integers
+	conn.rows = [][]driver.Value{{
+		int64(12), int64(3), expected.Time.UnixMilli(), spotMarketId, testSubaccountId, "", "", int64(1), true,
+		int64(0), "limitFill", "1.5", "10", "0.01", "0", "0", "0", "",
+	}}
+	conn.statements = nil
+	fills, err := store.Fills(context.Background(), FillFilter{
+		SubaccountId: testSubaccountId,
+		From:         testBlockTime,
+		Limit:        10,
+		NewestFirst:  true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Fill{expected}, fills)
+	assert.Equal(t, "SELECT "+fillColumns+" FROM indexer_fills WHERE subaccount_id = ? AND block_time >= ? ORDER BY height DESC, idx DESC LIMIT 10", conn.statements[0].query)
+	assert.Equal(t, []driver.Value{testSubaccountId, testBlockTime.UnixMilli()}, conn.statements[0].args)
+
+	conn.rows = [][]driver.Value{{
+		int64(12), int64(3), int64(0), spotMarketId, testSubaccountId, "", "", true, false, false, "", "1.5", "ten",
+		"0", "0", "0", "0", "",
+	}}
+	_, err = store.Fills(context.Background(), FillFilter{})
+	assert.Error(t, err)
+}
+
+func TestRebind(t *testing.T) {
+	postgres := &SQLStore{dialect: DialectPostgres}
+	assert.Equal(t, "a = $1 AND b IN ($2, $3)", postgres.rebind("a = ? AND b IN (?, ?)"))
+	sqlite := &SQLStore{dialect: DialectSQLite}
+	assert.Equal(t, "a = ?", sqlite.rebind("a = ?"))
+}
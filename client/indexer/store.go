@@ -0,0 +1,107 @@
+package indexer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store persists the indexed fills. SaveBlock must write the fills and the indexed height atomically, so the indexer
+// resumes after the last block fully saved, and must ignore the fills already saved when a block is indexed again
+type Store interface {
+	// LastHeight returns the last indexed height, or 0 if no block was indexed
+	LastHeight(ctx context.Context) (int64, error)
+	SaveBlock(ctx context.Context, height int64, fills []Fill) error
+	Fills(ctx context.Context, filter FillFilter) ([]Fill, error)
+}
+
+// FillFilter selects fills. Empty fields match all the fills. The fills are returned in execution order, or the most
+// recent first with NewestFirst
+type FillFilter struct {
+	SubaccountId string
+	MarketId     string
+	// From is inclusive and To exclusive
+	From        time.Time
+	To          time.Time
+	Limit       int
+	NewestFirst bool
+}
+
+func (f FillFilter) matches(fill Fill) bool {
+	if f.SubaccountId != "" && fill.SubaccountId != f.SubaccountId {
+		return false
+	}
+	if f.MarketId != "" && fill.MarketId != f.MarketId {
+		return false
+	}
+	if !f.From.IsZero() && fill.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !fill.Time.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// MemoryStore keeps the fills in memory, for tests and short lived processes
+type MemoryStore struct {
+	mux    sync.RWMutex
+	fills  []Fill
+	height int64
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) LastHeight(_ context.Context) (int64, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return s.height, nil
+}
+
+func (s *MemoryStore) SaveBlock(_ context.Context, height int64, fills []Fill) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, fill := range fills {
+		i := sort.Search(len(s.fills), func(i int) bool {
+			return s.fills[i].Height > fill.Height || (s.fills[i].Height == fill.Height && s.fills[i].Index >= fill.Index)
+		})
+		if i < len(s.fills) && s.fills[i].Height == fill.Height && s.fills[i].Index == fill.Index {
+			continue
+		}
+		s.fills = append(s.fills, Fill{})
+		copy(s.fills[i+1:], s.fills[i:])
+		s.fills[i] = fill
+	}
+	if height > s.height {
+		s.height = height
+	}
+	return nil
+}
+
+func (s *MemoryStore) Fills(_ context.Context, filter FillFilter) ([]Fill, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	var fills []Fill
+	for i := range s.fills {
+		fill := s.fills[i]
+		if filter.NewestFirst {
+			fill = s.fills[len(s.fills)-1-i]
+		}
+		if !filter.matches(fill) {
+			continue
+		}
+		fills = append(fills, fill)
+		if filter.Limit > 0 && len(fills) == filter.Limit {
+			break
+		}
+	}
+	return fills, nil
+}
@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"reflect"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+)
+
+const (
+	JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+	OpenAPIVersion    = "3.1.0"
+)
+
+// JSONSchemaDocument is a JSON Schema with the definitions of a set of types and no root type
+type JSONSchemaDocument struct {
+	Schema      string             `json:"$schema"`
+	Title       string             `json:"title,omitempty"`
+	Definitions map[string]*Schema `json:"$defs"`
+}
+
+// OpenAPIDocument is an OpenAPI 3.1 document with only components, to be referenced from the specs of the services
+// built with the SDK
+type OpenAPIDocument struct {
+	OpenAPI    string            `json:"openapi"`
+	Info       OpenAPIInfo       `json:"info"`
+	Components OpenAPIComponents `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// NewJSONSchemaDocument returns the JSON Schema definitions of values and of the types they use
+func NewJSONSchemaDocument(title string, values ...interface{}) *JSONSchemaDocument {
+	generator := NewGenerator(JSONSchemaRefPrefix)
+	for _, value := range values {
+		generator.Add(value)
+	}
+	return &JSONSchemaDocument{
+		Schema:      JSONSchemaDialect,
+		Title:       title,
+		Definitions: generator.Definitions(),
+	}
+}
+
+// NewOpenAPIDocument returns the OpenAPI components of values and of the types they use
+func NewOpenAPIDocument(info OpenAPIInfo, values ...interface{}) *OpenAPIDocument {
+	generator := NewGenerator(OpenAPIRefPrefix)
+	for _, value := range values {
+		generator.Add(value)
+	}
+	return &OpenAPIDocument{
+		OpenAPI:    OpenAPIVersion,
+		Info:       info,
+		Components: OpenAPIComponents{Schemas: generator.Definitions()},
+	}
+}
+
+// MsgTypes returns a value of every Msg type known by the SDK (see chain.RegisterInterfaces) and of its
+// response, sorted by type URL
+func MsgTypes() []interface{} {
+	registry := chain.NewInterfaceRegistry()
+	typeURLs := registry.ListImplementations(sdk.MsgInterfaceProtoName)
+	sort.Strings(typeURLs)
+
+	values := make([]interface{}, 0, 2*len(typeURLs))
+	for _, typeURL := range typeURLs {
+		msg, err := registry.Resolve(typeURL)
+		if err != nil {
+			continue
+		}
+		values = append(values, msg)
+		if responseType := proto.MessageType(proto.MessageName(msg) + "Response"); responseType != nil {
+			values = append(values, reflect.New(responseType.Elem()).Interface())
+		}
+	}
+	return values
+}
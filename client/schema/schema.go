@@ -0,0 +1,364 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/gogoproto/jsonpb"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	JSONSchemaRefPrefix = "#/$defs/"
+	OpenAPIRefPrefix    = "#/components/schemas/"
+)
+
+// Schema is the subset of JSON Schema (draft 2020-12) used to describe the SDK types. OpenAPI 3.1 components are
+// JSON Schema objects too
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	// AdditionalProperties is a *Schema for maps, or true for objects with arbitrary fields (e.g. a packed Any)
+	AdditionalProperties interface{}   `json:"additionalProperties,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+}
+
+var (
+	decimalPattern = `^-?[0-9]+(\.[0-9]+)?$`
+	integerPattern = `^-?[0-9]+$`
+
+	// well known types with a custom JSON encoding
+	knownSchemas = map[reflect.Type]Schema{
+		reflect.TypeOf(time.Time{}):         {Type: "string", Format: "date-time"},
+		reflect.TypeOf(sdkmath.LegacyDec{}): {Type: "string", Pattern: decimalPattern, Description: "decimal with 18 digits of precision"},
+		reflect.TypeOf(sdkmath.Int{}):       {Type: "string", Pattern: integerPattern},
+		reflect.TypeOf(sdkmath.Uint{}):      {Type: "string", Pattern: `^[0-9]+$`},
+		reflect.TypeOf(decimal.Decimal{}):   {Type: "string", Pattern: decimalPattern},
+		reflect.TypeOf(codectypes.Any{}): {
+			Type:                 "object",
+			Description:          "packed proto message, with its fields next to @type",
+			Properties:           map[string]*Schema{"@type": {Type: "string", Description: "type URL of the message"}},
+			Required:             []string{"@type"},
+			AdditionalProperties: true,
+		},
+	}
+
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	protoMessageType  = reflect.TypeOf((*proto.Message)(nil)).Elem()
+	durationType      = reflect.TypeOf(time.Duration(0))
+)
+
+// Generator builds the schemas of Go types from their struct tags. Every named struct gets a definition, referenced
+// from the schemas using it.
+//
+// Proto messages are described as encoded by the SDK proto JSON codec (ProtoCodec().MarshalJSON, also used by the
+// gRPC gateways): the proto field names, 64 bit integers as strings, enums by name and oneof fields inlined. Any
+// other type is described as encoded by encoding/json
+type Generator struct {
+	refPrefix   string
+	definitions map[string]*Schema
+	names       map[reflect.Type]string
+}
+
+// NewGenerator returns a generator whose references start with refPrefix (JSONSchemaRefPrefix or OpenAPIRefPrefix)
+func NewGenerator(refPrefix string) *Generator {
+	return &Generator{
+		refPrefix:   refPrefix,
+		definitions: make(map[string]*Schema),
+		names:       make(map[reflect.Type]string),
+	}
+}
+
+// Add adds the definitions of the type of value and of the types it uses, and returns the schema of the type
+// (a reference for structs)
+func (g *Generator) Add(value interface{}) *Schema {
+	return g.schemaOf(reflect.TypeOf(value), false)
+}
+
+// Definitions returns the definitions added so far, by name. Proto messages are named after their full proto name
+// (e.g. injective.exchange.v1beta1.MsgCreateSpotLimitOrder), other structs after their package and type names
+func (g *Generator) Definitions() map[string]*Schema {
+	return g.definitions
+}
+
+// DefinitionName returns the name of the definition of the type of value
+func (g *Generator) DefinitionName(value interface{}) string {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return g.names[t]
+}
+
+func (g *Generator) schemaOf(t reflect.Type, protoEncoding bool) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if known, found := knownSchemas[t]; found {
+		return &known
+	}
+	if t == durationType && protoEncoding {
+		return &Schema{Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?s$`, Description: "duration in seconds, e.g. 1.5s"}
+	}
+	if implements(t, jsonMarshalerType) {
+		return &Schema{Description: "custom JSON encoding of " + t.String()}
+	}
+	if t.Kind() != reflect.String && implements(t, textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint:
+		return &Schema{Type: "integer"}
+	case reflect.Int64:
+		if protoEncoding {
+			return &Schema{Type: "string", Format: "int64", Pattern: integerPattern}
+		}
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint64:
+		if protoEncoding {
+			return &Schema{Type: "string", Format: "uint64", Pattern: `^[0-9]+$`}
+		}
+		return &Schema{Type: "integer", Format: "uint64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Description: "base64 encoded bytes"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem(), protoEncoding)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem(), protoEncoding)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.reference(t)
+	default:
+		// interfaces can hold any value
+		return &Schema{}
+	}
+}
+
+func (g *Generator) reference(t reflect.Type) *Schema {
+	name, found := g.names[t]
+	if !found {
+		name = g.definitionName(t)
+		g.names[t] = name
+		// registered before the fields are walked, so recursive types end in a reference
+		g.definitions[name] = &Schema{}
+		*g.definitions[name] = *g.structSchema(t)
+	}
+	return &Schema{Ref: g.refPrefix + name}
+}
+
+func (g *Generator) definitionName(t reflect.Type) string {
+	name := path.Base(t.PkgPath()) + "." + t.Name()
+	if isProtoMessage(t) {
+		if protoName := proto.MessageName(reflect.New(t).Interface().(proto.Message)); protoName != "" {
+			name = protoName
+		}
+	}
+
+	unique := name
+	for i := 2; g.definitions[unique] != nil; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	return unique
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	if isProtoMessage(t) {
+		return g.protoMessageSchema(t)
+	}
+
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options := parseTag(field.Tag.Get("json"))
+		if name == "-" && options == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			embedded := g.structSchema(indirect(field.Type))
+			for embeddedName, property := range embedded.Properties {
+				schema.Properties[embeddedName] = property
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schemaOf(field.Type, false)
+		if strings.Contains(options, "string") && property.Type != "" && property.Type != "object" && property.Type != "array" {
+			property = &Schema{Type: "string"}
+		}
+		schema.Properties[name] = property
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// protoMessageSchema describes the proto JSON encoding of a message. Proto3 fields are all optional
+func (g *Generator) protoMessageSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	var oneofWrappers []interface{}
+	if withOneofs, ok := reflect.New(t).Interface().(interface{ XXX_OneofWrappers() []interface{} }); ok {
+		oneofWrappers = withOneofs.XXX_OneofWrappers()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || strings.HasPrefix(field.Name, "XXX_") {
+			continue
+		}
+
+		if field.Tag.Get("protobuf_oneof") != "" {
+			// the set field of the oneof is encoded as a field of the message
+			for _, wrapper := range oneofWrappers {
+				wrapperType := indirect(reflect.TypeOf(wrapper))
+				if !reflect.PtrTo(wrapperType).Implements(field.Type) || wrapperType.NumField() == 0 {
+					continue
+				}
+				if name, property := g.protoField(wrapperType.Field(0)); name != "" {
+					schema.Properties[name] = property
+				}
+			}
+			continue
+		}
+
+		if name, property := g.protoField(field); name != "" {
+			schema.Properties[name] = property
+		}
+	}
+	return schema
+}
+
+func (g *Generator) protoField(field reflect.StructField) (string, *Schema) {
+	tag := field.Tag.Get("protobuf")
+	if tag == "" {
+		name, _ := parseTag(field.Tag.Get("json"))
+		if name == "-" {
+			return "", nil
+		}
+		if name == "" {
+			name = field.Name
+		}
+		return name, g.schemaOf(field.Type, true)
+	}
+
+	var name, enumName string
+	for _, option := range strings.Split(tag, ",") {
+		switch {
+		case strings.HasPrefix(option, "name="):
+			name = strings.TrimPrefix(option, "name=")
+		case strings.HasPrefix(option, "enum="):
+			enumName = strings.TrimPrefix(option, "enum=")
+		}
+	}
+
+	if enumName != "" {
+		if field.Type.Kind() == reflect.Slice {
+			return name, &Schema{Type: "array", Items: g.enumSchema(enumName, indirect(field.Type.Elem()))}
+		}
+		return name, g.enumSchema(enumName, indirect(field.Type))
+	}
+	return name, g.schemaOf(field.Type, true)
+}
+
+// enumSchema lists the names of the enum values, or their custom JSON encoding when the enum type has one
+func (g *Generator) enumSchema(enumName string, enumType reflect.Type) *Schema {
+	values := proto.EnumValueMap(enumName)
+	if len(values) == 0 {
+		return &Schema{Type: "string", Description: enumName}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if values[names[i]] != values[names[j]] {
+			return values[names[i]] < values[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	schema := &Schema{Type: "string", Description: enumName}
+	for _, name := range names {
+		value := reflect.New(enumType)
+		value.Elem().SetInt(int64(values[name]))
+		if marshaler, ok := value.Interface().(jsonpb.JSONPBMarshaler); ok {
+			encoded, err := marshaler.MarshalJSONPB(&jsonpb.Marshaler{})
+			var customName string
+			if err != nil || json.Unmarshal(encoded, &customName) != nil {
+				return &Schema{Description: "custom JSON encoding of " + enumName}
+			}
+			name = customName
+		}
+		if !containsName(schema.Enum, name) {
+			schema.Enum = append(schema.Enum, name)
+		}
+	}
+	return schema
+}
+
+func containsName(names []interface{}, name string) bool {
+	for _, existing := range names {
+		if existing == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isProtoMessage(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(protoMessageType)
+}
+
+func implements(t reflect.Type, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func parseTag(tag string) (string, string) {
+	name, options, _ := strings.Cut(tag, ",")
+	return name, options
+}
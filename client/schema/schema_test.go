@@ -0,0 +1,168 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/events"
+)
+
+// matches checks the JSON value against the schema: the types, that the objects only have known properties and
+// that the required properties are set
+func matches(t *testing.T, definitions map[string]*Schema, schema *Schema, value interface{}, at string) {
+	if schema.Ref != "" {
+		definition := definitions[strings.TrimPrefix(schema.Ref, JSONSchemaRefPrefix)]
+		if !assert.NotNil(t, definition, "%s: missing definition %s", at, schema.Ref) {
+			return
+		}
+		matches(t, definitions, definition, value, at)
+		return
+	}
+	if value == nil || schema.Type == "" {
+		return
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if !assert.Equal(t, "object", schema.Type, at) {
+			return
+		}
+		for _, required := range schema.Required {
+			assert.Contains(t, typed, required, at)
+		}
+		for name, property := range typed {
+			propertySchema, found := schema.Properties[name]
+			if !found {
+				if additional, ok := schema.AdditionalProperties.(*Schema); ok {
+					propertySchema, found = additional, true
+				} else {
+					found = schema.AdditionalProperties == true
+				}
+			}
+			if assert.True(t, found, "%s: unknown property %s", at, name) && propertySchema != nil {
+				matches(t, definitions, propertySchema, property, at+"."+name)
+			}
+		}
+	case []interface{}:
+		if !assert.Equal(t, "array", schema.Type, at) {
+			return
+		}
+		for _, item := range typed {
+			matches(t, definitions, schema.Items, item, at+"[]")
+		}
+	case string:
+		assert.Equal(t, "string", schema.Type, at)
+		if len(schema.Enum) > 0 {
+			assert.Contains(t, schema.Enum, typed, at)
+		}
+	case float64:
+		assert.Contains(t, []string{"integer", "number"}, schema.Type, at)
+	case bool:
+		assert.Equal(t, "boolean", schema.Type, at)
+	}
+}
+
+func TestMsgSchemasMatchTheProtoJSONEncoding(t *testing.T) {
+	msgs := MsgTypes()
+	assert.Greater(t, len(msgs), 100)
+	document := NewJSONSchemaDocument("injective msgs", msgs...)
+	generator := NewGenerator(JSONSchemaRefPrefix)
+
+	order := exchangetypes.SpotOrder{
+		MarketId:  "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe",
+		OrderInfo: exchangetypes.OrderInfo{SubaccountId: "0x01", Price: math.LegacyNewDec(10), Quantity: math.LegacyNewDec(2), Cid: "1"},
+		OrderType: exchangetypes.OrderType_SELL_PO,
+	}
+	msgs = append(msgs, &exchangetypes.MsgBatchUpdateOrders{
+		Sender:                   "inj1sender",
+		SpotOrdersToCreate:       []*exchangetypes.SpotOrder{&order},
+		SpotOrdersToCancel:       []*exchangetypes.OrderData{{MarketId: order.MarketId, OrderHash: "0x02", OrderMask: 1}},
+		SpotMarketIdsToCancelAll: []string{order.MarketId},
+	})
+
+	for _, msg := range msgs {
+		encoded, err := chain.ProtoCodec().MarshalJSON(msg.(proto.Message))
+		assert.NoError(t, err)
+		var value interface{}
+		assert.NoError(t, json.Unmarshal(encoded, &value))
+
+		matches(t, document.Definitions, generator.Add(msg), value, proto.MessageName(msg.(proto.Message)))
+	}
+}
+
+func TestSpotLimitOrderSchema(t *testing.T) {
+	generator := NewGenerator(OpenAPIRefPrefix)
+
+	ref := generator.Add(&exchangetypes.MsgCreateSpotLimitOrder{})
+
+	assert.Equal(t, OpenAPIRefPrefix+"injective.exchange.v1beta1.MsgCreateSpotLimitOrder", ref.Ref)
+	definitions := generator.Definitions()
+	msg := definitions["injective.exchange.v1beta1.MsgCreateSpotLimitOrder"]
+	assert.Equal(t, OpenAPIRefPrefix+"injective.exchange.v1beta1.SpotOrder", msg.Properties["order"].Ref)
+
+	order := definitions["injective.exchange.v1beta1.SpotOrder"]
+	assert.Equal(t, "string", order.Properties["order_type"].Type)
+	assert.Contains(t, order.Properties["order_type"].Enum, "BUY_PO")
+	assert.Equal(t, decimalPattern, order.Properties["trigger_price"].Pattern)
+
+	info := definitions["injective.exchange.v1beta1.OrderInfo"]
+	assert.Equal(t, "string", info.Properties["price"].Type)
+	assert.Contains(t, info.Properties, "cid")
+	assert.Empty(t, info.Required)
+}
+
+func TestOneofFieldsAreInlined(t *testing.T) {
+	generator := NewGenerator(JSONSchemaRefPrefix)
+
+	generator.Add(&exchangetypes.FullDerivativeMarket{})
+
+	market := generator.Definitions()["injective.exchange.v1beta1.FullDerivativeMarket"]
+	assert.Contains(t, market.Properties, "perpetual_info")
+	assert.Contains(t, market.Properties, "futures_info")
+	assert.NotContains(t, market.Properties, "info")
+}
+
+func TestGoStructSchemasFollowEncodingJSON(t *testing.T) {
+	generator := NewGenerator(JSONSchemaRefPrefix)
+	event := events.Event{
+		Id:       "fill/0x01/1",
+		Type:     events.EventFill,
+		Price:    "10",
+		Quantity: "2",
+		Height:   100,
+		Time:     time.Unix(1700000000, 0).UTC(),
+	}
+
+	ref := generator.Add(event)
+
+	encoded, err := json.Marshal(event)
+	assert.NoError(t, err)
+	var value interface{}
+	assert.NoError(t, json.Unmarshal(encoded, &value))
+	matches(t, generator.Definitions(), ref, value, "event")
+
+	definition := generator.Definitions()[generator.DefinitionName(event)]
+	assert.Equal(t, "events.Event", generator.DefinitionName(event))
+	assert.Equal(t, "integer", definition.Properties["height"].Type)
+	assert.Equal(t, "date-time", definition.Properties["time"].Format)
+	assert.Contains(t, definition.Required, "id")
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	document := NewOpenAPIDocument(OpenAPIInfo{Title: "orders", Version: "v1"}, &exchangetypes.MsgCreateSpotLimitOrder{})
+
+	encoded, err := json.Marshal(document)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"openapi":"3.1.0"`)
+	assert.Contains(t, string(encoded), `"$ref":"#/components/schemas/injective.exchange.v1beta1.SpotOrder"`)
+	assert.Contains(t, document.Components.Schemas, "injective.exchange.v1beta1.OrderInfo")
+}
@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"context"
+	"sync"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+)
+
+// Client is a common.TxSigner signing with the keys of a Signer service. Use it with common.OptionTxSigner to create
+// a chain client with no local keys
+type Client struct {
+	client   SignerClient
+	metadata map[string]string
+
+	mux     sync.Mutex
+	pubKeys map[string]cryptotypes.PubKey
+}
+
+// NewClient creates a client of the Signer service on the connection. The metadata is sent with every sign request,
+// for the signer policies and audit logs (e.g. {"strategy": "market-maker", "node": "mm-1"})
+func NewClient(conn grpc.ClientConnInterface, metadata map[string]string) *Client {
+	return &Client{
+		client:   NewSignerClient(conn),
+		metadata: metadata,
+		pubKeys:  make(map[string]cryptotypes.PubKey),
+	}
+}
+
+// PubKey returns the public key of the account, cached after the first call
+func (c *Client) PubKey(ctx context.Context, address sdk.AccAddress) (cryptotypes.PubKey, error) {
+	c.mux.Lock()
+	pubKey, found := c.pubKeys[address.String()]
+	c.mux.Unlock()
+	if found {
+		return pubKey, nil
+	}
+
+	res, err := c.client.GetPubKey(ctx, &GetPubKeyRequest{SignerAddress: address.String()})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.PubKey) != ethsecp256k1.PubKeySize {
+		return nil, errors.Errorf("invalid public key length %d", len(res.PubKey))
+	}
+	pubKey = &ethsecp256k1.PubKey{Key: res.PubKey}
+	if !sdk.AccAddress(pubKey.Address()).Equals(address) {
+		return nil, errors.Errorf("the signer returned the public key of %s instead of %s", sdk.AccAddress(pubKey.Address()).String(), address.String())
+	}
+
+	c.mux.Lock()
+	c.pubKeys[address.String()] = pubKey
+	c.mux.Unlock()
+	return pubKey, nil
+}
+
+// SignDirect returns the signature of the SignDoc by the account, after verifying it with the account public key
+func (c *Client) SignDirect(ctx context.Context, address sdk.AccAddress, signDoc []byte) ([]byte, error) {
+	pubKey, err := c.PubKey(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.Sign(ctx, &SignRequest{
+		SignerAddress: address.String(),
+		SignDoc:       signDoc,
+		Metadata:      c.metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !pubKey.VerifySignature(signDoc, res.Signature) {
+		return nil, errors.Errorf("the signature returned by the signer is not a valid signature of %s", address.String())
+	}
+	return res.Signature, nil
+}
@@ -0,0 +1,153 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	log "github.com/InjectiveLabs/suplog"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/clock"
+)
+
+// Server is the reference Signer service, signing with the eth_secp256k1 keys of a keyring the txs allowed by its
+// sign policy. Register it on a gRPC server with RegisterSignerServer
+type Server struct {
+	UnimplementedSignerServer
+
+	keyring  keyring.Keyring
+	registry codectypes.InterfaceRegistry
+	logger   log.Logger
+
+	mux    sync.RWMutex
+	policy chain.SignPolicy
+	clock  clock.Clock
+}
+
+var _ SignerServer = (*Server)(nil)
+
+func NewServer(kb keyring.Keyring) *Server {
+	return &Server{
+		keyring:  kb,
+		registry: chain.NewInterfaceRegistry(),
+		logger:   log.WithField("module", "signer-server"),
+		policy:   chain.PermissiveSignPolicy{},
+		clock:    clock.Real(),
+	}
+}
+
+// SetPolicy sets the policy evaluated before signing each tx (e.g. a chain.RuleSignPolicy). Nil allows every tx
+func (s *Server) SetPolicy(policy chain.SignPolicy) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if policy == nil {
+		policy = chain.PermissiveSignPolicy{}
+	}
+	s.policy = policy
+}
+
+// SetClock sets the clock of the sign policy time windows
+func (s *Server) SetClock(c clock.Clock) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.clock = clock.OrReal(c)
+}
+
+func (s *Server) GetPubKey(_ context.Context, req *GetPubKeyRequest) (*GetPubKeyResponse, error) {
+	_, pubKey, err := s.key(req.SignerAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &GetPubKeyResponse{PubKey: pubKey.Key}, nil
+}
+
+// Sign signs the SignDoc after checking that it is a valid SignDoc with the account key as signer, and that its msgs
+// are allowed by the sign policy. Txs denied by the policy fail with codes.PermissionDenied
+func (s *Server) Sign(_ context.Context, req *SignRequest) (*SignResponse, error) {
+	address, pubKey, err := s.key(req.SignerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var signDoc txtypes.SignDoc
+	if err := signDoc.Unmarshal(req.SignDoc); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sign doc: %s", err.Error())
+	}
+	var authInfo txtypes.AuthInfo
+	if err := authInfo.Unmarshal(signDoc.AuthInfoBytes); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sign doc auth info: %s", err.Error())
+	}
+	pubKeyBytes, err := pubKey.Marshal()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if len(authInfo.SignerInfos) != 1 || authInfo.SignerInfos[0].PublicKey == nil || !bytes.Equal(authInfo.SignerInfos[0].PublicKey.Value, pubKeyBytes) {
+		return nil, status.Errorf(codes.InvalidArgument, "the sign doc signer is not %s", req.SignerAddress)
+	}
+
+	var body txtypes.TxBody
+	if err := body.Unmarshal(signDoc.BodyBytes); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sign doc body: %s", err.Error())
+	}
+	if err := body.UnpackInterfaces(s.registry); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sign doc msgs: %s", err.Error())
+	}
+	msgs, err := txtypes.GetMsgs(body.Messages, "sign doc")
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sign doc msgs: %s", err.Error())
+	}
+	s.mux.RLock()
+	policy, now := s.policy, s.clock.Now()
+	s.mux.RUnlock()
+	if err := policy.Evaluate(chain.SignPolicyRequest{
+		Signer:   address,
+		ChainId:  signDoc.ChainId,
+		Msgs:     msgs,
+		Metadata: req.Metadata,
+		Time:     now,
+	}); err != nil {
+		s.logger.WithError(err).WithField("address", req.SignerAddress).WithField("metadata", req.Metadata).Warningln("sign policy denied the tx")
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	signature, _, err := s.keyring.SignByAddress(address, req.SignDoc)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign: %s", err.Error())
+	}
+
+	s.logger.WithFields(log.Fields{
+		"address":       req.SignerAddress,
+		"chainId":       signDoc.ChainId,
+		"accountNumber": signDoc.AccountNumber,
+		"metadata":      req.Metadata,
+	}).Infoln("signed tx")
+	return &SignResponse{Signature: signature}, nil
+}
+
+func (s *Server) key(signerAddress string) (sdk.AccAddress, *ethsecp256k1.PubKey, error) {
+	address, err := sdk.AccAddressFromBech32(signerAddress)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid signer address %q", signerAddress)
+	}
+	record, err := s.keyring.KeyByAddress(address)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.NotFound, "no key for %s", signerAddress)
+	}
+	pubKey, err := record.GetPubKey()
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to get the public key of %s: %s", signerAddress, err.Error())
+	}
+	ethPubKey, ok := pubKey.(*ethsecp256k1.PubKey)
+	if !ok {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "the key of %s is not an eth_secp256k1 key", signerAddress)
+	}
+	return address, ethPubKey, nil
+}
@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+)
+
+func startSigner(t *testing.T, srv SignerServer) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	RegisterSignerServer(server, srv)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func testSignDoc(t *testing.T, pubKey cryptotypes.PubKey, msgs ...sdk.Msg) []byte {
+	pubKeyAny, err := codectypes.NewAnyWithValue(pubKey)
+	assert.NoError(t, err)
+	body := txtypes.TxBody{}
+	for _, msg := range msgs {
+		msgAny, err := codectypes.NewAnyWithValue(msg)
+		assert.NoError(t, err)
+		body.Messages = append(body.Messages, msgAny)
+	}
+	bodyBytes, err := body.Marshal()
+	assert.NoError(t, err)
+	authInfo := txtypes.AuthInfo{SignerInfos: []*txtypes.SignerInfo{{PublicKey: pubKeyAny, Sequence: 9}}}
+	authInfoBytes, err := authInfo.Marshal()
+	assert.NoError(t, err)
+	signDoc := txtypes.SignDoc{BodyBytes: bodyBytes, AuthInfoBytes: authInfoBytes, ChainId: "injective-1", AccountNumber: 3}
+	signDocBytes, err := signDoc.Marshal()
+	assert.NoError(t, err)
+	return signDocBytes
+}
+
+func TestSigner(t *testing.T) {
+	key, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	address := sdk.AccAddress(key.PubKey().Address())
+	kb, err := chain.KeyringForPrivKey("custody", key)
+	assert.NoError(t, err)
+	server := NewServer(kb)
+	client := NewClient(startSigner(t, server), map[string]string{"strategy": "test"})
+	ctx := context.Background()
+
+	pubKey, err := client.PubKey(ctx, address)
+	assert.NoError(t, err)
+	assert.True(t, key.PubKey().Equals(pubKey))
+
+	signDoc := testSignDoc(t, key.PubKey())
+	signature, err := client.SignDirect(ctx, address, signDoc)
+	assert.NoError(t, err)
+	assert.True(t, key.PubKey().VerifySignature(signDoc, signature))
+
+	// the server only signs the sign docs of its keys
+	other, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	_, err = client.SignDirect(ctx, address, testSignDoc(t, other.PubKey()))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.SignDirect(ctx, address, []byte("not a sign doc"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.PubKey(ctx, sdk.AccAddress(other.PubKey().Address()))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// the txs denied by the sign policy are not signed
+	policy, err := chain.LoadSignPolicy([]byte("allowed_msg_types: [/injective.exchange.v1beta1.MsgBatchUpdateOrders]"))
+	assert.NoError(t, err)
+	server.SetPolicy(policy)
+	withdrawal := banktypes.NewMsgSend(address, sdk.AccAddress(other.PubKey().Address()), sdk.NewCoins(sdk.NewInt64Coin("inj", 1)))
+	_, err = client.SignDirect(ctx, address, testSignDoc(t, key.PubKey(), withdrawal))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, "denied by the sign policy: msg_type: msg /cosmos.bank.v1beta1.MsgSend is not allowed", status.Convert(err).Message())
+	_, err = client.SignDirect(ctx, address, testSignDoc(t, key.PubKey(), &exchangetypes.MsgBatchUpdateOrders{Sender: address.String()}))
+	assert.NoError(t, err)
+}
+
+// lyingSigner returns the signatures of another key
+type lyingSigner struct {
+	*Server
+	key      *ethsecp256k1.PrivKey
+	metadata map[string]string
+}
+
+func (s *lyingSigner) Sign(_ context.Context, req *SignRequest) (*SignResponse, error) {
+	s.metadata = req.Metadata
+	signature, err := s.key.Sign(req.SignDoc)
+	return &SignResponse{Signature: signature}, err
+}
+
+func TestClientVerifiesTheSignatures(t *testing.T) {
+	key, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	address := sdk.AccAddress(key.PubKey().Address())
+	kb, err := chain.KeyringForPrivKey("custody", key)
+	assert.NoError(t, err)
+	other, err := ethsecp256k1.GenerateKey()
+	assert.NoError(t, err)
+	signer := &lyingSigner{Server: NewServer(kb), key: other}
+	client := NewClient(startSigner(t, signer), map[string]string{"strategy": "test", "node": "mm-1"})
+
+	_, err = client.SignDirect(context.Background(), address, testSignDoc(t, key.PubKey()))
+	assert.EqualError(t, err, "the signature returned by the signer is not a valid signature of "+address.String())
+	assert.Equal(t, map[string]string{"strategy": "test", "node": "mm-1"}, signer.metadata)
+}
@@ -0,0 +1,308 @@
+// Package testvectors exports canonical exchange orders together with their EIP712 order hashes and signatures,
+// so other implementations can be cross-validated against the Go SDK.
+package testvectors
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client/chain"
+)
+
+const (
+	SpotOrderKind       = "spot"
+	DerivativeOrderKind = "derivative"
+
+	// SignatureTypeEIP712 is the secp256k1 signature (r || s || v, v in {27, 28}) of the EIP712 order hash
+	SignatureTypeEIP712 = "EIP712"
+	// SignatureTypeEthSign is the signature of the order hash with the "\x19Ethereum Signed Message:\n32" prefix
+	SignatureTypeEthSign = "EthSign"
+
+	// signerPrivateKey is a well known test key, never use it to hold funds
+	signerPrivateKey = "5d386fbdbf11f1141010f81a46b40f94887367562bd33b452bbaa6ce1cd1381e"
+)
+
+//go:embed testdata/order_vectors.json
+var goldenVectors []byte
+
+type SignatureVector struct {
+	SignatureType string `json:"signature_type"`
+	Signature     string `json:"signature"`
+}
+
+type OrderVector struct {
+	Name      string `json:"name"`
+	OrderKind string `json:"order_kind"`
+	// Nonce is the subaccount trade nonce used as the order salt
+	Nonce           uint32                         `json:"nonce"`
+	SpotOrder       *exchangetypes.SpotOrder       `json:"spot_order,omitempty"`
+	DerivativeOrder *exchangetypes.DerivativeOrder `json:"derivative_order,omitempty"`
+	OrderHash       string                         `json:"order_hash"`
+	SignerAddress   string                         `json:"signer_address"`
+	Signatures      []SignatureVector              `json:"signatures"`
+}
+
+const (
+	canonicalSubaccountId = "0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000"
+	canonicalFeeRecipient = "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+	canonicalSpotMarket   = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	canonicalPerpMarket   = "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+)
+
+func decPtr(value string) *sdk.Dec {
+	dec := sdk.MustNewDecFromStr(value)
+	return &dec
+}
+
+func orderInfo(price string, quantity string) exchangetypes.OrderInfo {
+	return exchangetypes.OrderInfo{
+		SubaccountId: canonicalSubaccountId,
+		FeeRecipient: canonicalFeeRecipient,
+		Price:        sdk.MustNewDecFromStr(price),
+		Quantity:     sdk.MustNewDecFromStr(quantity),
+	}
+}
+
+// CanonicalOrders returns the orders used to generate the vectors, without hashes and signatures
+func CanonicalOrders() []OrderVector {
+	return []OrderVector{
+		{
+			Name:      "spot_limit_buy",
+			OrderKind: SpotOrderKind,
+			Nonce:     1,
+			SpotOrder: &exchangetypes.SpotOrder{
+				MarketId:  canonicalSpotMarket,
+				OrderInfo: orderInfo("0.000000000012", "1000000000000000000"),
+				OrderType: exchangetypes.OrderType_BUY,
+			},
+		},
+		{
+			Name:      "spot_limit_sell_post_only",
+			OrderKind: SpotOrderKind,
+			Nonce:     42,
+			SpotOrder: &exchangetypes.SpotOrder{
+				MarketId:     canonicalSpotMarket,
+				OrderInfo:    orderInfo("0.000000000015", "2500000000000000000"),
+				OrderType:    exchangetypes.OrderType_SELL_PO,
+				TriggerPrice: decPtr("0"),
+			},
+		},
+		{
+			Name:      "derivative_limit_buy",
+			OrderKind: DerivativeOrderKind,
+			Nonce:     7,
+			DerivativeOrder: &exchangetypes.DerivativeOrder{
+				MarketId:  canonicalPerpMarket,
+				OrderInfo: orderInfo("25000000000", "0.1"),
+				OrderType: exchangetypes.OrderType_BUY,
+				Margin:    sdk.MustNewDecFromStr("2500000000"),
+			},
+		},
+		{
+			Name:      "derivative_reduce_only_sell",
+			OrderKind: DerivativeOrderKind,
+			Nonce:     8,
+			DerivativeOrder: &exchangetypes.DerivativeOrder{
+				MarketId:  canonicalPerpMarket,
+				OrderInfo: orderInfo("26000000000", "0.05"),
+				OrderType: exchangetypes.OrderType_SELL,
+				Margin:    sdk.ZeroDec(),
+			},
+		},
+		{
+			Name:      "derivative_stop_loss",
+			OrderKind: DerivativeOrderKind,
+			Nonce:     4294967295,
+			DerivativeOrder: &exchangetypes.DerivativeOrder{
+				MarketId:     canonicalPerpMarket,
+				OrderInfo:    orderInfo("24000000000", "0.1"),
+				OrderType:    exchangetypes.OrderType_STOP_SELL,
+				Margin:       sdk.MustNewDecFromStr("2400000000"),
+				TriggerPrice: decPtr("24500000000"),
+			},
+		},
+	}
+}
+
+func computeOrderHash(vector OrderVector) (common.Hash, error) {
+	switch vector.OrderKind {
+	case SpotOrderKind:
+		if vector.SpotOrder == nil {
+			return common.Hash{}, errors.Errorf("vector %s has no spot order", vector.Name)
+		}
+		return chain.ComputeSpotOrderHash(*vector.SpotOrder, vector.Nonce)
+	case DerivativeOrderKind:
+		if vector.DerivativeOrder == nil {
+			return common.Hash{}, errors.Errorf("vector %s has no derivative order", vector.Name)
+		}
+		return chain.ComputeDerivativeOrderHash(*vector.DerivativeOrder, vector.Nonce)
+	default:
+		return common.Hash{}, errors.Errorf("vector %s has unknown order kind %s", vector.Name, vector.OrderKind)
+	}
+}
+
+func signatureDigest(signatureType string, orderHash common.Hash) ([]byte, error) {
+	switch signatureType {
+	case SignatureTypeEIP712:
+		return orderHash.Bytes(), nil
+	case SignatureTypeEthSign:
+		return accounts.TextHash(orderHash.Bytes()), nil
+	default:
+		return nil, errors.Errorf("unknown signature type %s", signatureType)
+	}
+}
+
+// Generate computes the hashes and signatures of the canonical orders
+func Generate() ([]OrderVector, error) {
+	privKey, err := ethcrypto.HexToECDSA(signerPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	signerAddress := ethcrypto.PubkeyToAddress(privKey.PublicKey)
+
+	vectors := CanonicalOrders()
+	for i := range vectors {
+		orderHash, err := computeOrderHash(vectors[i])
+		if err != nil {
+			return nil, err
+		}
+		vectors[i].OrderHash = orderHash.Hex()
+		vectors[i].SignerAddress = signerAddress.Hex()
+
+		for _, signatureType := range []string{SignatureTypeEIP712, SignatureTypeEthSign} {
+			digest, err := signatureDigest(signatureType, orderHash)
+			if err != nil {
+				return nil, err
+			}
+			signature, err := ethcrypto.Sign(digest, privKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to sign vector %s", vectors[i].Name)
+			}
+			// use the Ethereum convention for the recovery id
+			signature[ethcrypto.RecoveryIDOffset] += 27
+
+			vectors[i].Signatures = append(vectors[i].Signatures, SignatureVector{
+				SignatureType: signatureType,
+				Signature:     hexutil.Encode(signature),
+			})
+		}
+	}
+
+	return vectors, nil
+}
+
+// Golden returns the vectors stored in testdata/order_vectors.json
+func Golden() ([]OrderVector, error) {
+	var vectors []OrderVector
+	if err := json.Unmarshal(goldenVectors, &vectors); err != nil {
+		return nil, errors.Wrap(err, "failed to parse golden vectors")
+	}
+	return vectors, nil
+}
+
+// MarshalVectors encodes the vectors in the golden file format
+func MarshalVectors(vectors []OrderVector) ([]byte, error) {
+	encoded, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(encoded, '\n'), nil
+}
+
+// VerifyVector checks the order hash of the vector matches the order, and that each signature was generated
+// by the signer address over the order hash
+func VerifyVector(vector OrderVector) error {
+	orderHash, err := computeOrderHash(vector)
+	if err != nil {
+		return err
+	}
+	if orderHash.Hex() != common.HexToHash(vector.OrderHash).Hex() {
+		return errors.Errorf("vector %s: order hash %s does not match the computed hash %s", vector.Name, vector.OrderHash, orderHash.Hex())
+	}
+
+	for _, signatureVector := range vector.Signatures {
+		signature, err := hexutil.Decode(signatureVector.Signature)
+		if err != nil || len(signature) != ethcrypto.SignatureLength {
+			return errors.Errorf("vector %s: invalid %s signature %s", vector.Name, signatureVector.SignatureType, signatureVector.Signature)
+		}
+		digest, err := signatureDigest(signatureVector.SignatureType, orderHash)
+		if err != nil {
+			return errors.Wrapf(err, "vector %s", vector.Name)
+		}
+
+		recoverable := append([]byte(nil), signature...)
+		if recoverable[ethcrypto.RecoveryIDOffset] >= 27 {
+			recoverable[ethcrypto.RecoveryIDOffset] -= 27
+		}
+		pubKey, err := ethcrypto.SigToPub(digest, recoverable)
+		if err != nil {
+			return errors.Wrapf(err, "vector %s: failed to recover %s signer", vector.Name, signatureVector.SignatureType)
+		}
+		if signer := ethcrypto.PubkeyToAddress(*pubKey); signer != common.HexToAddress(vector.SignerAddress) {
+			return errors.Errorf("vector %s: %s signature recovers %s instead of %s", vector.Name, signatureVector.SignatureType, signer.Hex(), vector.SignerAddress)
+		}
+	}
+
+	return nil
+}
+
+// VerifyAgainstGolden compares the vectors (e.g. produced by another implementation) with the golden vectors by name.
+// All golden vectors must be present, and hashes and signatures must match exactly
+func VerifyAgainstGolden(vectors []OrderVector) error {
+	golden, err := Golden()
+	if err != nil {
+		return err
+	}
+
+	vectorsByName := make(map[string]OrderVector, len(vectors))
+	for _, vector := range vectors {
+		vectorsByName[vector.Name] = vector
+	}
+
+	for _, expected := range golden {
+		actual, found := vectorsByName[expected.Name]
+		if !found {
+			return errors.Errorf("vector %s is missing", expected.Name)
+		}
+		if common.HexToHash(actual.OrderHash) != common.HexToHash(expected.OrderHash) {
+			return errors.Errorf("vector %s: order hash %s, expected %s", expected.Name, actual.OrderHash, expected.OrderHash)
+		}
+		if err := compareSignatures(expected, actual); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func compareSignatures(expected OrderVector, actual OrderVector) error {
+	actualSignatures := make(map[string]string, len(actual.Signatures))
+	for _, signature := range actual.Signatures {
+		actualSignatures[signature.SignatureType] = signature.Signature
+	}
+
+	for _, signature := range expected.Signatures {
+		actualSignature, found := actualSignatures[signature.SignatureType]
+		if !found {
+			return errors.Errorf("vector %s: %s signature is missing", expected.Name, signature.SignatureType)
+		}
+		actualBytes, err := hexutil.Decode(actualSignature)
+		if err != nil {
+			return errors.Wrapf(err, "vector %s: invalid %s signature", expected.Name, signature.SignatureType)
+		}
+		if !bytes.Equal(actualBytes, hexutil.MustDecode(signature.Signature)) {
+			return errors.Errorf("vector %s: %s signature %s, expected %s", expected.Name, signature.SignatureType, actualSignature, signature.Signature)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package testvectors
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "regenerate testdata/order_vectors.json")
+
+func TestGeneratedVectorsMatchGolden(t *testing.T) {
+	vectors, err := Generate()
+	assert.NoError(t, err)
+
+	if *update {
+		encoded, err := MarshalVectors(vectors)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile("testdata/order_vectors.json", encoded, 0o600))
+		return
+	}
+
+	golden, err := Golden()
+	assert.NoError(t, err)
+	assert.Equal(t, golden, vectors)
+	assert.NoError(t, VerifyAgainstGolden(vectors))
+}
+
+func TestGoldenVectorsAreSelfConsistent(t *testing.T) {
+	golden, err := Golden()
+	assert.NoError(t, err)
+	assert.Len(t, golden, len(CanonicalOrders()))
+
+	for _, vector := range golden {
+		assert.NoError(t, VerifyVector(vector), vector.Name)
+		assert.Len(t, vector.Signatures, 2)
+	}
+}
+
+func TestVerifyAgainstGoldenDetectsMismatches(t *testing.T) {
+	golden, err := Golden()
+	assert.NoError(t, err)
+
+	wrongHash, _ := Golden()
+	wrongHash[0].OrderHash = golden[1].OrderHash
+	assert.Error(t, VerifyAgainstGolden(wrongHash))
+	assert.Error(t, VerifyVector(wrongHash[0]))
+
+	wrongSignature, _ := Golden()
+	wrongSignature[2].Signatures[0].Signature = golden[3].Signatures[0].Signature
+	assert.Error(t, VerifyAgainstGolden(wrongSignature))
+	assert.Error(t, VerifyVector(wrongSignature[2]))
+
+	assert.Error(t, VerifyAgainstGolden(golden[1:]))
+}
@@ -0,0 +1,197 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	log "github.com/InjectiveLabs/suplog"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/pkg/errors"
+)
+
+const modulePath = "github.com/InjectiveLabs/sdk-go"
+
+// Version and GitCommit can be set at build time, e.g.
+// -ldflags "-X github.com/InjectiveLabs/sdk-go/client/version.Version=v1.50.0". When they are not set, the version
+// is read from the module build info of the binary
+var (
+	Version   = ""
+	GitCommit = ""
+)
+
+// SupportedChainVersions is the injective-core release line the chain types of the SDK are copied from. Nodes
+// outside of it can encode messages and state the SDK does not know
+var SupportedChainVersions = Range{Min: SemVer{Major: 1, Minor: 12}, Max: SemVer{Major: 1, Minor: 13}}
+
+// Info describes the SDK build. It has no build time, so the same sources always report the same info
+type Info struct {
+	Version                string
+	GitCommit              string
+	GoVersion              string
+	SupportedChainVersions Range
+}
+
+func BuildInfo() Info {
+	info := Info{
+		Version:                Version,
+		GitCommit:              GitCommit,
+		GoVersion:              runtime.Version(),
+		SupportedChainVersions: SupportedChainVersions,
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" {
+			info.Version = moduleVersion(buildInfo)
+		}
+		if info.GitCommit == "" {
+			for _, setting := range buildInfo.Settings {
+				if setting.Key == "vcs.revision" {
+					info.GitCommit = setting.Value
+				}
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "(devel)"
+	}
+	return info
+}
+
+func (i Info) String() string {
+	version := "injective sdk-go " + i.Version
+	if i.GitCommit != "" {
+		version += " (" + i.GitCommit + ")"
+	}
+	return fmt.Sprintf("%s, %s, supports injective-core %s", version, i.GoVersion, i.SupportedChainVersions)
+}
+
+func moduleVersion(buildInfo *debug.BuildInfo) string {
+	if buildInfo.Main.Path == modulePath {
+		return buildInfo.Main.Version
+	}
+	for _, dependency := range buildInfo.Deps {
+		if dependency.Path == modulePath {
+			if dependency.Replace != nil {
+				return dependency.Replace.Version
+			}
+			return dependency.Version
+		}
+	}
+	return ""
+}
+
+// SemVer is a major.minor.patch version. Pre-release and build metadata are ignored
+type SemVer struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseSemVer parses versions like v1.12.1, 1.12.1-rc1 or v1.12
+func ParseSemVer(version string) (SemVer, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if end := strings.IndexAny(trimmed, "-+ "); end >= 0 {
+		trimmed = trimmed[:end]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return SemVer{}, errors.Errorf("invalid version %q", version)
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return SemVer{}, errors.Errorf("invalid version %q", version)
+		}
+		numbers[i] = number
+	}
+	return SemVer{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// Compare returns -1, 0 or 1 if v is lower, equal or greater than other
+func (v SemVer) Compare(other SemVer) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if diff < 0 {
+			return -1
+		}
+		if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v SemVer) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Range has the versions from Min (included) to Max (excluded)
+type Range struct {
+	Min SemVer
+	Max SemVer
+}
+
+func (r Range) Contains(v SemVer) bool {
+	return v.Compare(r.Min) >= 0 && v.Compare(r.Max) < 0
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf(">= %s, < %s", r.Min, r.Max)
+}
+
+type CheckMode int
+
+const (
+	// CheckWarn logs a warning when the node version is not supported
+	CheckWarn CheckMode = iota
+	// CheckFail returns an error when the node version is not supported
+	CheckFail
+)
+
+// ABCIInfoClient is the part of the CometBFT RPC client used by the handshake (client.Context.Client implements it)
+type ABCIInfoClient interface {
+	ABCIInfo(ctx context.Context) (*coretypes.ResultABCIInfo, error)
+}
+
+// NodeVersion is the application version reported by a node
+type NodeVersion struct {
+	AppName    string
+	AppVersion string
+	// Protocol is the ABCI app protocol version
+	Protocol  uint64
+	Height    int64
+	Supported bool
+}
+
+// Handshake queries the application version of the node and checks it against SupportedChainVersions. A version
+// that can not be parsed is reported as not supported
+func Handshake(ctx context.Context, client ABCIInfoClient, mode CheckMode) (*NodeVersion, error) {
+	res, err := client.ABCIInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query the node ABCI info")
+	}
+
+	nodeVersion := &NodeVersion{
+		AppName:    res.Response.Data,
+		AppVersion: res.Response.Version,
+		Protocol:   res.Response.AppVersion,
+		Height:     res.Response.LastBlockHeight,
+	}
+	version, parseErr := ParseSemVer(res.Response.Version)
+	nodeVersion.Supported = parseErr == nil && SupportedChainVersions.Contains(version)
+	if nodeVersion.Supported {
+		return nodeVersion, nil
+	}
+
+	err = errors.Errorf("node version %q is not supported by sdk-go %s, which supports injective-core %s", res.Response.Version, BuildInfo().Version, SupportedChainVersions)
+	if mode == CheckFail {
+		return nodeVersion, err
+	}
+	log.WithError(err).Warningln("the node may encode messages the SDK can not decode")
+	return nodeVersion, nil
+}
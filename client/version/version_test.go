@@ -0,0 +1,83 @@
+package version
+
+import (
+	"context"
+	"testing"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testABCIInfoClient struct {
+	version string
+	err     error
+}
+
+func (c *testABCIInfoClient) ABCIInfo(context.Context) (*coretypes.ResultABCIInfo, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &coretypes.ResultABCIInfo{Response: abcitypes.ResponseInfo{Data: "injective", Version: c.version, LastBlockHeight: 100}}, nil
+}
+
+func TestParseSemVer(t *testing.T) {
+	for input, expected := range map[string]SemVer{
+		"v1.12.1":         {Major: 1, Minor: 12, Patch: 1},
+		"1.12.0-rc1":      {Major: 1, Minor: 12},
+		"v1.13":           {Major: 1, Minor: 13},
+		"v1.12.2+abcdef0": {Major: 1, Minor: 12, Patch: 2},
+	} {
+		version, err := ParseSemVer(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, version, input)
+	}
+
+	for _, input := range []string{"", "v1", "main", "v1.x.0", "1.2.3.4"} {
+		_, err := ParseSemVer(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	supported := Range{Min: SemVer{Major: 1, Minor: 12}, Max: SemVer{Major: 1, Minor: 13}}
+
+	assert.True(t, supported.Contains(SemVer{Major: 1, Minor: 12}))
+	assert.True(t, supported.Contains(SemVer{Major: 1, Minor: 12, Patch: 9}))
+	assert.False(t, supported.Contains(SemVer{Major: 1, Minor: 11, Patch: 9}))
+	assert.False(t, supported.Contains(SemVer{Major: 1, Minor: 13}))
+	assert.Equal(t, ">= v1.12.0, < v1.13.0", supported.String())
+}
+
+func TestHandshake(t *testing.T) {
+	nodeVersion, err := Handshake(context.Background(), &testABCIInfoClient{version: "v1.12.1"}, CheckFail)
+	assert.NoError(t, err)
+	assert.True(t, nodeVersion.Supported)
+	assert.Equal(t, "injective", nodeVersion.AppName)
+	assert.Equal(t, int64(100), nodeVersion.Height)
+
+	nodeVersion, err = Handshake(context.Background(), &testABCIInfoClient{version: "v1.13.0"}, CheckFail)
+	assert.Error(t, err)
+	assert.False(t, nodeVersion.Supported)
+
+	nodeVersion, err = Handshake(context.Background(), &testABCIInfoClient{version: "unknown"}, CheckWarn)
+	assert.NoError(t, err)
+	assert.False(t, nodeVersion.Supported)
+
+	_, err = Handshake(context.Background(), &testABCIInfoClient{err: errors.New("connection refused")}, CheckWarn)
+	assert.Error(t, err)
+}
+
+func TestBuildInfoIsDeterministic(t *testing.T) {
+	Version, GitCommit = "v1.50.0", "abcdef0"
+	defer func() { Version, GitCommit = "", "" }()
+
+	info := BuildInfo()
+
+	assert.Equal(t, "v1.50.0", info.Version)
+	assert.Equal(t, "abcdef0", info.GitCommit)
+	assert.Equal(t, BuildInfo(), info)
+	assert.Contains(t, info.String(), "injective sdk-go v1.50.0 (abcdef0)")
+	assert.Contains(t, info.String(), "supports injective-core >= v1.12.0, < v1.13.0")
+}
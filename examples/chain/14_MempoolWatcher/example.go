@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+)
+
+func main() {
+	network := common.LoadNetwork("testnet", "lb")
+	tmClient, err := rpchttp.New(network.TmEndpoint, "/websocket")
+	if err != nil {
+		panic(err)
+	}
+
+	watcher := chainclient.NewMempoolWatcher(tmClient, 200*time.Millisecond)
+	eventCh := make(chan chainclient.PendingOrderEvent, 1000)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelFn()
+	go watcher.Watch(ctx, eventCh)
+
+	for {
+		select {
+		case event := <-eventCh:
+			switch {
+			case event.SpotOrder != nil:
+				fmt.Println("pending spot order", event.TxHash, event.SpotOrder.MarketId, event.SpotOrder.OrderType, event.SpotOrder.OrderInfo.Price, event.SpotOrder.OrderInfo.Quantity)
+			case event.DerivativeOrder != nil:
+				fmt.Println("pending derivative order", event.TxHash, event.DerivativeOrder.MarketId, event.DerivativeOrder.OrderType, event.DerivativeOrder.OrderInfo.Price, event.DerivativeOrder.OrderInfo.Quantity)
+			case event.Cancellation != nil:
+				fmt.Println("pending cancellation", event.TxHash, event.Cancellation.MarketId, event.Cancellation.OrderHash)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/InjectiveLabs/sdk-go/client/events"
+	"github.com/InjectiveLabs/sdk-go/client/schema"
+)
+
+func main() {
+	// the OpenAPI components of all the Msg types and their responses, plus the SDK event payloads
+	values := append(schema.MsgTypes(), events.Event{})
+	document := schema.NewOpenAPIDocument(schema.OpenAPIInfo{Title: "Injective msgs", Version: "v1"}, values...)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		panic(err)
+	}
+}
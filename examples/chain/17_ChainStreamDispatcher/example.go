@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chainStreamModule "github.com/InjectiveLabs/sdk-go/chain/stream/types"
+	"github.com/InjectiveLabs/sdk-go/client"
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/dispatch"
+)
+
+func main() {
+	network := common.LoadNetwork("testnet", "lb")
+
+	clientCtx, err := chainclient.NewClientContext(
+		network.ChainId,
+		"",
+		nil,
+	)
+	if err != nil {
+		panic(err)
+	}
+	clientCtx = clientCtx.WithNodeURI(network.TmEndpoint)
+
+	chainClient, err := chainclient.NewChainClient(
+		clientCtx,
+		network,
+		common.OptionGasPrices(client.DefaultGasPriceWithDenom),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	injUsdtMarket := "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	injUsdtPerpMarket := "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+
+	req := chainStreamModule.StreamRequest{
+		SpotOrderbooksFilter: &chainStreamModule.OrderbookFilter{
+			MarketIds: []string{injUsdtMarket},
+		},
+		DerivativeOrderbooksFilter: &chainStreamModule.OrderbookFilter{
+			MarketIds: []string{injUsdtPerpMarket},
+		},
+		OraclePriceFilter: &chainStreamModule.OraclePriceFilter{
+			Symbol: []string{"INJ", "USDT"},
+		},
+	}
+
+	ctx := context.Background()
+
+	stream, err := chainClient.ChainStream(ctx, req)
+	if err != nil {
+		panic(err)
+	}
+
+	// the orderbook snapshots of every market are handled in block order, the markets in parallel, and a slow market
+	// only keeps its latest snapshot
+	dispatcher := dispatch.NewDispatcher(dispatch.Config{Policy: dispatch.LatestWins}, func(ctx context.Context, marketId string, res *chainStreamModule.StreamResponse) {
+		fmt.Printf("market %q at height %d: %d spot and %d derivative orderbook updates\n", marketId, res.BlockHeight, len(res.SpotOrderbookUpdates), len(res.DerivativeOrderbookUpdates))
+	})
+	go dispatcher.Run(ctx)
+
+	go func() {
+		for range time.Tick(10 * time.Second) {
+			stats := dispatcher.Stats()
+			fmt.Printf("delivered %d, dropped %d, queued %d\n", stats.Delivered, stats.Dropped, stats.Depth)
+		}
+	}()
+
+	if err := dispatch.Pump(ctx, stream.Recv, dispatch.SplitByMarket, dispatcher); err != nil {
+		panic(err)
+	}
+	dispatcher.Close()
+}
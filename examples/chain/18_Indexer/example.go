@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/indexer"
+	"github.com/InjectiveLabs/sdk-go/client/tm"
+)
+
+func main() {
+	network := common.LoadNetwork("testnet", "lb")
+	tmClient := tm.NewRPCClient(network.TmEndpoint)
+
+	// a SQLStore keeps the fills across restarts, e.g.
+	// db, _ := sql.Open("postgres", "postgres://localhost/indexer")
+	// store, _ := indexer.NewSQLStore(ctx, db, indexer.DialectPostgres)
+	store := indexer.NewMemoryStore()
+
+	idx, err := indexer.NewIndexer(tmClient, store, indexer.Config{
+		OnBlock: func(height int64, fills []indexer.Fill) {
+			fmt.Printf("block %d: %d fills\n", height, len(fills))
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelFn()
+	idx.Run(ctx)
+
+	volumes, err := indexer.VolumeByMarket(context.Background(), store, time.Time{}, time.Time{})
+	if err != nil {
+		panic(err)
+	}
+	for _, volume := range volumes {
+		fmt.Printf("market %s: %d fills, notional %s\n", volume.MarketId, volume.Fills, volume.Notional)
+	}
+
+	subaccountId := "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000"
+	injUsdtMarket := "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	pnl, err := indexer.RealizedPnL(context.Background(), store, subaccountId, injUsdtMarket)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("realized %s, fees %s, net %s\n", pnl.Realized, pnl.Fees, pnl.Net)
+}
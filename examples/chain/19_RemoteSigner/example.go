@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/InjectiveLabs/sdk-go/client"
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/signer"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+)
+
+func main() {
+	network := common.LoadNetwork("testnet", "lb")
+
+	// the signing service holds the keys. It would run on its own host, serving every trading node
+	senderAddress, cosmosKeyring, err := chainclient.InitCosmosKeyring(
+		os.Getenv("HOME")+"/.injectived",
+		"injectived",
+		"file",
+		"inj-user",
+		"12345678",
+		"f9db9bf330e23cb7839039e944adef6e9df447b90b503d5b4464c90bea9022f3", // keyring will be used if pk not provided
+		false,
+	)
+	if err != nil {
+		panic(err)
+	}
+	listener, err := net.Listen("tcp", "localhost:9950")
+	if err != nil {
+		panic(err)
+	}
+	server := grpc.NewServer()
+	signer.RegisterSignerServer(server, signer.NewServer(cosmosKeyring))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	// the trading node has no keyring, only the address of the account signed by the signing service
+	signerConn, err := grpc.Dial("localhost:9950", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+	defer signerConn.Close()
+
+	tmClient, err := rpchttp.New(network.TmEndpoint, "/websocket")
+	if err != nil {
+		panic(err)
+	}
+	clientCtx, err := chainclient.NewClientContext(network.ChainId, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	clientCtx = clientCtx.WithNodeURI(network.TmEndpoint).WithClient(tmClient).WithFromAddress(senderAddress)
+
+	chainClient, err := chainclient.NewChainClient(
+		clientCtx,
+		network,
+		common.OptionGasPrices(client.DefaultGasPriceWithDenom),
+		common.OptionTxSigner(signer.NewClient(signerConn, map[string]string{"strategy": "example"})),
+	)
+	if err != nil {
+		panic(err)
+	}
+	defer chainClient.Close()
+
+	msg := banktypes.NewMsgSend(senderAddress, senderAddress, sdktypes.NewCoins(sdktypes.NewInt64Coin("inj", 1)))
+	res, err := chainClient.SyncBroadcastMsg(msg)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("tx hash", res.TxResponse.TxHash)
+}
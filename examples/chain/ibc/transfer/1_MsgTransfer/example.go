@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/InjectiveLabs/sdk-go/client"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+func main() {
+	network := common.LoadNetwork("testnet", "lb")
+	tmClient, err := rpchttp.New(network.TmEndpoint, "/websocket")
+	if err != nil {
+		panic(err)
+	}
+
+	senderAddress, cosmosKeyring, err := chainclient.InitCosmosKeyring(
+		os.Getenv("HOME")+"/.injectived",
+		"injectived",
+		"file",
+		"inj-user",
+		"12345678",
+		"5d386fbdbf11f1141010f81a46b40f94887367562bd33b452bbaa6ce1cd1381e", // keyring will be used if pk not provided
+		false,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	// initialize grpc client
+	clientCtx, err := chainclient.NewClientContext(
+		network.ChainId,
+		senderAddress.String(),
+		cosmosKeyring,
+	)
+	if err != nil {
+		panic(err)
+	}
+	clientCtx = clientCtx.WithNodeURI(network.TmEndpoint).WithClient(tmClient)
+
+	chainClient, err := chainclient.NewChainClient(
+		clientCtx,
+		network,
+		common.OptionGasPrices(client.DefaultGasPriceWithDenom),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	sourcePort := "transfer"
+	sourceChannel := "channel-126"
+	receiver := "cosmos1hkhdaj2a2clmq5jq6mspsggqs32vynpk228q3r"
+	token := sdktypes.NewInt64Coin("inj", 1000000000000000000) // 1 INJ
+
+	timeoutHeight, timeoutTimestamp, err := chainClient.ComputeIBCTransferTimeout(
+		context.Background(),
+		sourcePort,
+		sourceChannel,
+		chainclient.DefaultIBCTransferTimeoutBlocks,
+		chainclient.DefaultIBCTransferTimeoutDuration,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	// prepare tx msg
+	msg, err := chainclient.NewMsgIBCTransfer(
+		sourcePort,
+		sourceChannel,
+		token,
+		senderAddress.String(),
+		receiver,
+		timeoutHeight,
+		timeoutTimestamp,
+		"",
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	//AsyncBroadcastMsg, SyncBroadcastMsg, QueueBroadcastMsg
+	err = chainClient.QueueBroadcastMsg(msg)
+
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	time.Sleep(time.Second * 5)
+
+	gasFee, err := chainClient.GetGasFee()
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("gas fee:", gasFee, "INJ")
+}
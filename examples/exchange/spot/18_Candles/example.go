@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	exchangeclient "github.com/InjectiveLabs/sdk-go/client/exchange"
+)
+
+func main() {
+	network := common.LoadNetwork("testnet", "lb")
+	exchangeClient, err := exchangeclient.NewExchangeClient(network)
+	if err != nil {
+		panic(err)
+	}
+
+	ctx := context.Background()
+	marketsAssistant, err := chainclient.NewMarketsAssistantInitializedFromChain(ctx, exchangeClient)
+	if err != nil {
+		panic(err)
+	}
+	market := marketsAssistant.AllSpotMarkets()["0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"]
+
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Hour)
+	candles, err := exchangeClient.FetchSpotCandles(ctx, market, 5*time.Minute, startTime, endTime)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, candle := range candles {
+		fmt.Printf("%s open: %s high: %s low: %s close: %s volume: %s (%s %s)\n",
+			candle.OpenTime.Format(time.RFC3339),
+			candle.Open.String(),
+			candle.High.String(),
+			candle.Low.String(),
+			candle.Close.String(),
+			candle.BaseVolume.String(),
+			candle.QuoteVolume.String(),
+			market.QuoteToken.Symbol,
+		)
+	}
+}
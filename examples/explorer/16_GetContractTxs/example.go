@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	explorerPB "github.com/InjectiveLabs/sdk-go/exchange/explorer_rpc/pb"
+
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	explorerclient "github.com/InjectiveLabs/sdk-go/client/explorer"
+)
+
+func main() {
+	network := common.LoadNetwork("testnet", "lb")
+	explorerClient, err := explorerclient.NewExplorerClient(network)
+	if err != nil {
+		panic(err)
+	}
+
+	req := explorerPB.GetContractTxsRequest{
+		Address: "inj1ady3s7whq30l4fx8sj3x6muv5mx4dfdlcpv8n7",
+		Limit:   10,
+	}
+
+	ctx := context.Background()
+	res, err := explorerClient.GetContractTxs(ctx, &req)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	str, _ := json.MarshalIndent(res, "", " ")
+	fmt.Print(string(str))
+}
@@ -0,0 +1,369 @@
+// Package localnet starts a single validator Injective node in a Docker container for integration tests, with
+// prefunded accounts and test markets, and creates chain and exchange clients connected to it.
+package localnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	"github.com/InjectiveLabs/sdk-go/client"
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/InjectiveLabs/sdk-go/client/common"
+	"github.com/InjectiveLabs/sdk-go/client/exchange"
+)
+
+const (
+	DefaultImage   = "public.ecr.aws/l9h3g6c6/injective-core:v1.12.1"
+	DefaultChainId = "injective-777"
+	// USDTDenom is the quote denom funded in the default accounts and used by the default test market
+	USDTDenom = "peggy0xdAC17F958D2ee523a2206206994597C13D831ec7"
+
+	defaultStartTimeout = 2 * time.Minute
+	pollInterval        = 500 * time.Millisecond
+
+	tendermintPort  = "26657"
+	grpcPort        = "9900"
+	chainStreamPort = "9999"
+)
+
+// Account is an account funded in the genesis of the node
+type Account struct {
+	Name string
+	// PrivateKey is the hex encoded eth_secp256k1 private key
+	PrivateKey string
+	Balance    sdk.Coins
+}
+
+// Address returns the account address derived from the private key
+func (a Account) Address() (sdk.AccAddress, error) {
+	keyBytes, err := common.HexToBytes(a.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid private key for account %s", a.Name)
+	}
+	privateKey := &ethsecp256k1.PrivKey{Key: keyBytes}
+	return sdk.AccAddress(privateKey.PubKey().Address().Bytes()), nil
+}
+
+type Config struct {
+	Image   string
+	ChainId string
+	// ContainerName defaults to a unique name based on the current time
+	ContainerName string
+	Accounts      []Account
+	// SpotMarkets are launched after the node starts, sent by the first account (the sender field is ignored)
+	SpotMarkets  []exchangetypes.MsgInstantSpotMarketLaunch
+	StartTimeout time.Duration
+	// ExchangeGrpcEndpoint is the endpoint of an exchange API (indexer) connected to the node. The node image does not
+	// include it, so ExchangeClient is only available when it is configured
+	ExchangeGrpcEndpoint string
+}
+
+// DefaultConfig returns a configuration with two funded accounts ("user1" and "user2") and an INJ/USDT spot market
+func DefaultConfig() Config {
+	balance := sdk.NewCoins(
+		sdk.NewCoin("inj", sdkmath.NewIntWithDecimal(1_000_000, 18)),
+		sdk.NewCoin(USDTDenom, sdkmath.NewIntWithDecimal(1_000_000, 6)),
+	)
+
+	return Config{
+		Image:   DefaultImage,
+		ChainId: DefaultChainId,
+		Accounts: []Account{
+			{Name: "user1", PrivateKey: "5d386fbdbf11f1141010f81a46b40f94887367562bd33b452bbaa6ce1cd1381e", Balance: balance},
+			{Name: "user2", PrivateKey: "b1bab9a8a9c0f8a2f7f9b8f6a3f1e9d7c5b3a19f8e7d6c5b4a39281706f5e4d3", Balance: balance},
+		},
+		SpotMarkets: []exchangetypes.MsgInstantSpotMarketLaunch{
+			{
+				Ticker:              "INJ/USDT",
+				BaseDenom:           "inj",
+				QuoteDenom:          USDTDenom,
+				MinPriceTickSize:    sdk.MustNewDecFromStr("0.000000000000001"),
+				MinQuantityTickSize: sdk.MustNewDecFromStr("1000000000000000"),
+			},
+		},
+		StartTimeout: defaultStartTimeout,
+	}
+}
+
+// Localnet is a running node. Network has the endpoints of the node in the host
+type Localnet struct {
+	Network  common.Network
+	Accounts []Account
+	config   Config
+}
+
+// Start runs the node container and waits until it produces blocks and the configured markets are launched
+func Start(ctx context.Context, config Config) (*Localnet, error) {
+	if config.Image == "" {
+		config.Image = DefaultImage
+	}
+	if config.ChainId == "" {
+		config.ChainId = DefaultChainId
+	}
+	if config.ContainerName == "" {
+		config.ContainerName = fmt.Sprintf("injective-localnet-%d", time.Now().UnixNano())
+	}
+	if config.StartTimeout <= 0 {
+		config.StartTimeout = defaultStartTimeout
+	}
+	if len(config.SpotMarkets) > 0 && len(config.Accounts) == 0 {
+		return nil, errors.New("an account is required to launch the markets")
+	}
+
+	script, err := startScript(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := docker(ctx,
+		"run", "-d",
+		"--name", config.ContainerName,
+		"-p", "127.0.0.1::"+tendermintPort,
+		"-p", "127.0.0.1::"+grpcPort,
+		"-p", "127.0.0.1::"+chainStreamPort,
+		"--entrypoint", "sh",
+		config.Image,
+		"-c", script,
+	); err != nil {
+		return nil, err
+	}
+
+	localnet := &Localnet{Accounts: config.Accounts, config: config}
+	if err := localnet.initialize(ctx); err != nil {
+		_ = localnet.Stop(context.Background())
+		return nil, err
+	}
+
+	return localnet, nil
+}
+
+// StartForTest starts the node for a test and stops it when the test finishes. The test is skipped if Docker is
+// not available
+func StartForTest(t testing.TB, config Config) *Localnet {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker is required to run the localnet")
+	}
+
+	localnet, err := Start(context.Background(), config)
+	if err != nil {
+		t.Fatalf("failed to start the localnet: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := localnet.Stop(context.Background()); err != nil {
+			t.Logf("failed to stop the localnet: %v", err)
+		}
+	})
+
+	return localnet
+}
+
+// Stop removes the node container
+func (l *Localnet) Stop(ctx context.Context) error {
+	_, err := docker(ctx, "rm", "-f", "-v", l.config.ContainerName)
+	return err
+}
+
+// ChainClient returns a client connected to the node, signing with the account
+func (l *Localnet) ChainClient(accountName string) (chainclient.ChainClient, error) {
+	var account *Account
+	for i := range l.Accounts {
+		if l.Accounts[i].Name == accountName {
+			account = &l.Accounts[i]
+		}
+	}
+	if account == nil {
+		return nil, errors.Errorf("unknown localnet account %s", accountName)
+	}
+
+	senderAddress, cosmosKeyring, err := chainclient.InitCosmosKeyring("", "", "", account.Name, "", account.PrivateKey, false)
+	if err != nil {
+		return nil, err
+	}
+	clientCtx, err := chainclient.NewClientContext(l.Network.ChainId, senderAddress.String(), cosmosKeyring)
+	if err != nil {
+		return nil, err
+	}
+	tmClient, err := rpchttp.New(l.Network.TmEndpoint, "/websocket")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the tendermint client")
+	}
+	clientCtx = clientCtx.WithNodeURI(l.Network.TmEndpoint).WithClient(tmClient)
+
+	return chainclient.NewChainClient(clientCtx, l.Network, common.OptionGasPrices(client.DefaultGasPriceWithDenom))
+}
+
+// ExchangeClient returns a client for the exchange API configured in Config.ExchangeGrpcEndpoint
+func (l *Localnet) ExchangeClient() (exchange.ExchangeClient, error) {
+	if l.Network.ExchangeGrpcEndpoint == "" {
+		return nil, errors.New("the localnet has no exchange API endpoint configured")
+	}
+	return exchange.NewExchangeClient(l.Network)
+}
+
+func (l *Localnet) initialize(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, l.config.StartTimeout)
+	defer cancel()
+
+	network := common.LoadNetwork("local", "")
+	network.ChainId = l.config.ChainId
+	network.ExchangeGrpcEndpoint = l.config.ExchangeGrpcEndpoint
+	network.LcdEndpoint = ""
+	network.ExplorerGrpcEndpoint = ""
+
+	tmAddress, err := l.hostAddress(ctx, tendermintPort)
+	if err != nil {
+		return err
+	}
+	grpcAddress, err := l.hostAddress(ctx, grpcPort)
+	if err != nil {
+		return err
+	}
+	chainStreamAddress, err := l.hostAddress(ctx, chainStreamPort)
+	if err != nil {
+		return err
+	}
+	network.TmEndpoint = "http://" + tmAddress
+	network.ChainGrpcEndpoint = "tcp://" + grpcAddress
+	network.ChainStreamGrpcEndpoint = "tcp://" + chainStreamAddress
+	l.Network = network
+
+	if err := l.waitForBlocks(ctx); err != nil {
+		return err
+	}
+	return l.launchMarkets(ctx)
+}
+
+func (l *Localnet) hostAddress(ctx context.Context, containerPort string) (string, error) {
+	output, err := docker(ctx, "port", l.config.ContainerName, containerPort+"/tcp")
+	if err != nil {
+		return "", err
+	}
+	// one line per mapping, like 127.0.0.1:49153
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return "", errors.Errorf("port %s of the localnet is not published", containerPort)
+	}
+	return fields[0], nil
+}
+
+func (l *Localnet) waitForBlocks(ctx context.Context) error {
+	tmClient, err := rpchttp.New(l.Network.TmEndpoint, "/websocket")
+	if err != nil {
+		return errors.Wrap(err, "failed to create the tendermint client")
+	}
+
+	for {
+		status, err := tmClient.Status(ctx)
+		if err == nil && status.SyncInfo.LatestBlockHeight > 1 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			logs, _ := docker(context.Background(), "logs", "--tail", "20", l.config.ContainerName)
+			return errors.Errorf("the localnet did not produce blocks in time, last logs:\n%s", logs)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (l *Localnet) launchMarkets(ctx context.Context) error {
+	if len(l.config.SpotMarkets) == 0 {
+		return nil
+	}
+
+	chainClient, err := l.ChainClient(l.Accounts[0].Name)
+	if err != nil {
+		return err
+	}
+	defer chainClient.Close()
+
+	sender := chainClient.FromAddress().String()
+	msgs := make([]sdk.Msg, 0, len(l.config.SpotMarkets))
+	for i := range l.config.SpotMarkets {
+		msg := l.config.SpotMarkets[i]
+		msg.Sender = sender
+		msgs = append(msgs, &msg)
+	}
+
+	res, err := chainClient.SyncBroadcastMsg(msgs...)
+	if err != nil {
+		return errors.Wrap(err, "failed to launch the localnet markets")
+	}
+	if res.TxResponse.Code != 0 {
+		return errors.Errorf("failed to launch the localnet markets: %s", res.TxResponse.RawLog)
+	}
+
+	for {
+		markets, err := chainClient.FetchChainSpotMarkets(ctx, "", nil)
+		if err == nil && len(markets.Markets) >= len(l.config.SpotMarkets) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.New("the localnet markets were not launched in time")
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// startScript returns the shell script initializing the genesis and starting the node inside the container
+func startScript(config Config) (string, error) {
+	var script strings.Builder
+	script.WriteString("set -e\n")
+	script.WriteString("HOME_DIR=/root/.injectived\n")
+	fmt.Fprintf(&script, "CHAIN_ID=%s\n", config.ChainId)
+	script.WriteString("injectived init localnet --chain-id=$CHAIN_ID --home=$HOME_DIR > /dev/null 2>&1\n")
+	script.WriteString("injectived keys add validator --keyring-backend=test --home=$HOME_DIR > /dev/null 2>&1\n")
+	script.WriteString("injectived add-genesis-account --keyring-backend=test --home=$HOME_DIR validator 1000000000000000000000000inj\n")
+
+	for _, account := range config.Accounts {
+		address, err := account.Address()
+		if err != nil {
+			return "", err
+		}
+		if !account.Balance.IsValid() || account.Balance.IsZero() {
+			return "", errors.Errorf("invalid balance %q for account %s", account.Balance.String(), account.Name)
+		}
+		fmt.Fprintf(&script, "injectived add-genesis-account --home=$HOME_DIR %s %s\n", address.String(), account.Balance.String())
+	}
+
+	script.WriteString("injectived gentx validator 100000000000000000000000inj --chain-id=$CHAIN_ID --keyring-backend=test --home=$HOME_DIR > /dev/null 2>&1\n")
+	script.WriteString("injectived collect-gentxs --home=$HOME_DIR > /dev/null 2>&1\n")
+	// faster blocks to keep the tests short
+	script.WriteString("sed -i 's/timeout_commit = \"5s\"/timeout_commit = \"1s\"/' $HOME_DIR/config/config.toml\n")
+	fmt.Fprintf(
+		&script,
+		"exec injectived start --home=$HOME_DIR --rpc.laddr=tcp://0.0.0.0:%s --grpc.address=0.0.0.0:%s --chainstream-server=0.0.0.0:%s\n",
+		tendermintPort,
+		grpcPort,
+		chainStreamPort,
+	)
+
+	return script.String(), nil
+}
+
+func docker(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "docker %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
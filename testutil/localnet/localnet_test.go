@@ -0,0 +1,32 @@
+package localnet
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountAddress(t *testing.T) {
+	address, err := DefaultConfig().Accounts[0].Address()
+	assert.NoError(t, err)
+	assert.Equal(t, "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku", address.String())
+}
+
+func TestStartScriptFundsTheAccounts(t *testing.T) {
+	config := DefaultConfig()
+	script, err := startScript(config)
+	assert.NoError(t, err)
+
+	for _, account := range config.Accounts {
+		address, err := account.Address()
+		assert.NoError(t, err)
+		assert.Contains(t, script, "add-genesis-account --home=$HOME_DIR "+address.String()+" "+account.Balance.String())
+	}
+	assert.Contains(t, script, "CHAIN_ID=injective-777")
+	assert.Contains(t, script, "exec injectived start")
+
+	config.Accounts[0].Balance = sdk.Coins{}
+	_, err = startScript(config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,387 @@
+// Package matching is a deterministic reference matching engine for tests. Orders are matched with price-time
+// priority at the resting order price, using the chain order types and validations (tick sizes, post-only and
+// margin checks), so the fills produced in unit tests follow the chain semantics without running a node.
+package matching
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+const (
+	ExecutionTypeLimitMatchRestingOrder = "limitMatchRestingOrder"
+	ExecutionTypeLimitMatchNewOrder     = "limitMatchNewOrder"
+	ExecutionTypeMarket                 = "market"
+)
+
+// MarketConfig has the market parameters used by the engine, in chain format.
+// InitialMarginRatio is only used for derivative markets
+type MarketConfig struct {
+	MarketId            string
+	MinPriceTickSize    sdk.Dec
+	MinQuantityTickSize sdk.Dec
+	MakerFeeRate        sdk.Dec
+	TakerFeeRate        sdk.Dec
+	InitialMarginRatio  sdk.Dec
+}
+
+// Fill is the execution of one side of a trade. Each trade produces a maker and a taker fill
+type Fill struct {
+	TradeId       string
+	MarketId      string
+	OrderHash     string
+	SubaccountId  string
+	Cid           string
+	IsBuy         bool
+	IsMaker       bool
+	ExecutionType string
+	Price         sdk.Dec
+	Quantity      sdk.Dec
+	// Margin is the part of the derivative order margin used by the fill (zero for spot fills)
+	Margin sdk.Dec
+	// Fee is negative for maker rebates
+	Fee sdk.Dec
+}
+
+// OrderResult is the outcome of placing an order. RestingQuantity is the quantity added to the orderbook
+// (always zero for market orders)
+type OrderResult struct {
+	OrderHash       string
+	Fills           []Fill
+	RestingQuantity sdk.Dec
+}
+
+// RestingOrder is a limit order in the orderbook
+type RestingOrder struct {
+	OrderHash    string
+	SubaccountId string
+	Cid          string
+	IsBuy        bool
+	Price        sdk.Dec
+	Quantity     sdk.Dec
+	Fillable     sdk.Dec
+	Margin       sdk.Dec
+	// Sequence is the placement order, used to prioritize orders at the same price
+	Sequence uint64
+}
+
+type orderbook struct {
+	config MarketConfig
+	// buys and sells are sorted by priority (best price first, then lower sequence)
+	buys  []*RestingOrder
+	sells []*RestingOrder
+}
+
+// Engine keeps the orderbooks of the markets. It is not safe for concurrent use
+type Engine struct {
+	markets  map[string]*orderbook
+	nonces   map[string]uint32
+	sequence uint64
+	trades   uint64
+}
+
+func NewEngine() *Engine {
+	return &Engine{
+		markets: make(map[string]*orderbook),
+		nonces:  make(map[string]uint32),
+	}
+}
+
+// AddMarket registers a market, replacing its orderbook if it already exists
+func (e *Engine) AddMarket(config MarketConfig) {
+	e.markets[config.MarketId] = &orderbook{config: config}
+}
+
+// Orderbook returns a copy of the resting orders of the market, sorted by priority
+func (e *Engine) Orderbook(marketId string) (buys []RestingOrder, sells []RestingOrder, err error) {
+	book, found := e.markets[marketId]
+	if !found {
+		return nil, nil, errors.Errorf("market %s is not registered", marketId)
+	}
+	for _, order := range book.buys {
+		buys = append(buys, *order)
+	}
+	for _, order := range book.sells {
+		sells = append(sells, *order)
+	}
+	return buys, sells, nil
+}
+
+// CancelOrder removes a resting order
+func (e *Engine) CancelOrder(marketId string, orderHash string) error {
+	book, found := e.markets[marketId]
+	if !found {
+		return errors.Errorf("market %s is not registered", marketId)
+	}
+	for _, side := range []*[]*RestingOrder{&book.buys, &book.sells} {
+		for i, order := range *side {
+			if order.OrderHash == orderHash {
+				*side = append((*side)[:i], (*side)[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return errors.Wrapf(exchangetypes.ErrOrderDoesntExist, "order %s", orderHash)
+}
+
+func (e *Engine) PlaceSpotLimitOrder(order exchangetypes.SpotOrder) (*OrderResult, error) {
+	book, err := e.spotOrderbook(order)
+	if err != nil {
+		return nil, err
+	}
+	if order.OrderType.IsAtomic() {
+		return nil, errors.Wrapf(exchangetypes.ErrUnrecognizedOrderType, "%s is a market order type", order.OrderType.String())
+	}
+
+	return e.placeLimitOrder(book, order.OrderType, func(nonce uint32) (string, error) {
+		hash, err := order.ComputeOrderHash(nonce)
+		return hash.Hex(), err
+	}, order.OrderInfo, sdk.ZeroDec())
+}
+
+func (e *Engine) PlaceSpotMarketOrder(order exchangetypes.SpotOrder) (*OrderResult, error) {
+	book, err := e.spotOrderbook(order)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.placeMarketOrder(book, order.OrderType, func(nonce uint32) (string, error) {
+		hash, err := order.ComputeOrderHash(nonce)
+		return hash.Hex(), err
+	}, order.OrderInfo, sdk.ZeroDec())
+}
+
+// PlaceDerivativeLimitOrder adds a limit order checking its margin against the market initial margin ratio and the
+// mark price
+func (e *Engine) PlaceDerivativeLimitOrder(order exchangetypes.DerivativeOrder, markPrice sdk.Dec) (*OrderResult, error) {
+	book, err := e.derivativeOrderbook(order, markPrice)
+	if err != nil {
+		return nil, err
+	}
+	if order.OrderType.IsAtomic() {
+		return nil, errors.Wrapf(exchangetypes.ErrUnrecognizedOrderType, "%s is a market order type", order.OrderType.String())
+	}
+
+	return e.placeLimitOrder(book, order.OrderType, func(nonce uint32) (string, error) {
+		hash, err := order.ComputeOrderHash(nonce)
+		return hash.Hex(), err
+	}, order.OrderInfo, order.Margin)
+}
+
+// PlaceDerivativeMarketOrder executes a market order checking its margin against the market initial margin ratio and
+// the mark price
+func (e *Engine) PlaceDerivativeMarketOrder(order exchangetypes.DerivativeOrder, markPrice sdk.Dec) (*OrderResult, error) {
+	book, err := e.derivativeOrderbook(order, markPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.placeMarketOrder(book, order.OrderType, func(nonce uint32) (string, error) {
+		hash, err := order.ComputeOrderHash(nonce)
+		return hash.Hex(), err
+	}, order.OrderInfo, order.Margin)
+}
+
+func (e *Engine) spotOrderbook(order exchangetypes.SpotOrder) (*orderbook, error) {
+	book, found := e.markets[order.MarketId]
+	if !found {
+		return nil, errors.Wrapf(exchangetypes.ErrSpotMarketNotFound, "market %s", order.MarketId)
+	}
+	if order.IsConditional() {
+		return nil, errors.Wrapf(exchangetypes.ErrUnrecognizedOrderType, "conditional order type %s is not supported", order.OrderType.String())
+	}
+	if err := order.CheckTickSize(book.config.MinPriceTickSize, book.config.MinQuantityTickSize); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+func (e *Engine) derivativeOrderbook(order exchangetypes.DerivativeOrder, markPrice sdk.Dec) (*orderbook, error) {
+	book, found := e.markets[order.MarketId]
+	if !found {
+		return nil, errors.Wrapf(exchangetypes.ErrDerivativeMarketNotFound, "market %s", order.MarketId)
+	}
+	if order.IsConditional() {
+		return nil, errors.Wrapf(exchangetypes.ErrUnrecognizedOrderType, "conditional order type %s is not supported", order.OrderType.String())
+	}
+	if order.IsReduceOnly() {
+		// reduce-only orders depend on the subaccount position, which the engine does not track
+		return nil, errors.Wrap(exchangetypes.ErrNoMarginLocked, "reduce-only orders are not supported")
+	}
+	if err := order.CheckTickSize(book.config.MinPriceTickSize, book.config.MinQuantityTickSize); err != nil {
+		return nil, err
+	}
+	if _, err := order.CheckMarginAndGetMarginHold(
+		book.config.InitialMarginRatio,
+		markPrice,
+		book.config.TakerFeeRate,
+		exchangetypes.MarketType_Perpetual,
+		0,
+	); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+func (e *Engine) newOrder(orderType exchangetypes.OrderType, computeHash func(nonce uint32) (string, error), info exchangetypes.OrderInfo, margin sdk.Dec) (*RestingOrder, error) {
+	// the chain increments the subaccount nonce before computing the hash of each new order
+	nonce := e.nonces[info.SubaccountId] + 1
+	hash, err := computeHash(nonce)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute the order hash")
+	}
+	e.nonces[info.SubaccountId] = nonce
+	e.sequence++
+
+	return &RestingOrder{
+		OrderHash:    hash,
+		SubaccountId: info.SubaccountId,
+		Cid:          info.Cid,
+		IsBuy:        orderType.IsBuy(),
+		Price:        info.Price,
+		Quantity:     info.Quantity,
+		Fillable:     info.Quantity,
+		Margin:       margin,
+		Sequence:     e.sequence,
+	}, nil
+}
+
+func (e *Engine) placeLimitOrder(book *orderbook, orderType exchangetypes.OrderType, computeHash func(nonce uint32) (string, error), info exchangetypes.OrderInfo, margin sdk.Dec) (*OrderResult, error) {
+	if orderType.IsPostOnly() && book.crosses(orderType.IsBuy(), info.Price) {
+		return nil, errors.Wrapf(exchangetypes.ErrExceedsTopOfBookPrice, "post-only order at %s crosses the orderbook", info.Price.String())
+	}
+
+	order, err := e.newOrder(orderType, computeHash, info, margin)
+	if err != nil {
+		return nil, err
+	}
+
+	fills := e.match(book, order, ExecutionTypeLimitMatchNewOrder)
+	if order.Fillable.IsPositive() {
+		book.insert(order)
+	}
+
+	return &OrderResult{OrderHash: order.OrderHash, Fills: fills, RestingQuantity: order.Fillable}, nil
+}
+
+func (e *Engine) placeMarketOrder(book *orderbook, orderType exchangetypes.OrderType, computeHash func(nonce uint32) (string, error), info exchangetypes.OrderInfo, margin sdk.Dec) (*OrderResult, error) {
+	opposite := book.sells
+	if !orderType.IsBuy() {
+		opposite = book.buys
+	}
+	if len(opposite) == 0 {
+		return nil, exchangetypes.ErrNoLiquidity
+	}
+	// for market orders the price is the worst accepted price
+	if !book.crosses(orderType.IsBuy(), info.Price) {
+		return nil, errors.Wrapf(exchangetypes.ErrSlippageExceedsWorstPrice, "best price %s, worst price %s", opposite[0].Price.String(), info.Price.String())
+	}
+
+	order, err := e.newOrder(orderType, computeHash, info, margin)
+	if err != nil {
+		return nil, err
+	}
+
+	// the quantity not filled up to the worst price is cancelled
+	fills := e.match(book, order, ExecutionTypeMarket)
+	return &OrderResult{OrderHash: order.OrderHash, Fills: fills, RestingQuantity: sdk.ZeroDec()}, nil
+}
+
+func (e *Engine) match(book *orderbook, taker *RestingOrder, takerExecutionType string) []Fill {
+	opposite := &book.sells
+	if !taker.IsBuy {
+		opposite = &book.buys
+	}
+
+	var fills []Fill
+	for taker.Fillable.IsPositive() && len(*opposite) > 0 {
+		maker := (*opposite)[0]
+		if !book.crosses(taker.IsBuy, taker.Price) {
+			break
+		}
+
+		quantity := sdk.MinDec(taker.Fillable, maker.Fillable)
+		price := maker.Price
+		notional := price.Mul(quantity)
+		e.trades++
+		tradeId := fmt.Sprintf("%d", e.trades)
+
+		fills = append(fills,
+			Fill{
+				TradeId:       tradeId,
+				MarketId:      book.config.MarketId,
+				OrderHash:     maker.OrderHash,
+				SubaccountId:  maker.SubaccountId,
+				Cid:           maker.Cid,
+				IsBuy:         maker.IsBuy,
+				IsMaker:       true,
+				ExecutionType: ExecutionTypeLimitMatchRestingOrder,
+				Price:         price,
+				Quantity:      quantity,
+				Margin:        proportionalMargin(maker, quantity),
+				Fee:           notional.Mul(book.config.MakerFeeRate),
+			},
+			Fill{
+				TradeId:       tradeId,
+				MarketId:      book.config.MarketId,
+				OrderHash:     taker.OrderHash,
+				SubaccountId:  taker.SubaccountId,
+				Cid:           taker.Cid,
+				IsBuy:         taker.IsBuy,
+				IsMaker:       false,
+				ExecutionType: takerExecutionType,
+				Price:         price,
+				Quantity:      quantity,
+				Margin:        proportionalMargin(taker, quantity),
+				Fee:           notional.Mul(book.config.TakerFeeRate),
+			},
+		)
+
+		maker.Fillable = maker.Fillable.Sub(quantity)
+		taker.Fillable = taker.Fillable.Sub(quantity)
+		if !maker.Fillable.IsPositive() {
+			*opposite = (*opposite)[1:]
+		}
+	}
+
+	return fills
+}
+
+// proportionalMargin is the part of the order margin corresponding to the filled quantity
+func proportionalMargin(order *RestingOrder, quantity sdk.Dec) sdk.Dec {
+	if order.Margin.IsZero() {
+		return sdk.ZeroDec()
+	}
+	return order.Margin.Mul(quantity).Quo(order.Quantity)
+}
+
+// crosses returns true if an order at the price matches the best order of the opposite side
+func (b *orderbook) crosses(isBuy bool, price sdk.Dec) bool {
+	if isBuy {
+		return len(b.sells) > 0 && price.GTE(b.sells[0].Price)
+	}
+	return len(b.buys) > 0 && price.LTE(b.buys[0].Price)
+}
+
+func (b *orderbook) insert(order *RestingOrder) {
+	side := &b.sells
+	if order.IsBuy {
+		side = &b.buys
+	}
+
+	// orders at the same price keep the placement order
+	index := sort.Search(len(*side), func(i int) bool {
+		if order.IsBuy {
+			return (*side)[i].Price.LT(order.Price)
+		}
+		return (*side)[i].Price.GT(order.Price)
+	})
+	*side = append(*side, nil)
+	copy((*side)[index+1:], (*side)[index:])
+	(*side)[index] = order
+}
@@ -0,0 +1,175 @@
+package matching
+
+import (
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+)
+
+const (
+	spotMarketId       = "0x0611780ba69656949525013d947713300f56c37b6175e02f26bffa495c3208fe"
+	derivativeMarketId = "0x17ef48032cb24375ba7c2e39f384e56433bcab20cbee9a7357e4cba2eb00abe6"
+	makerSubaccountId  = "0xaf79152ac5df276d9a8e1e2e22822f9713474902000000000000000000000000"
+	takerSubaccountId  = "0xbdaedec95d563fb05240d6e01821008454c24c36000000000000000000000000"
+	feeRecipient       = "inj14au322k9munkmx5wrchz9q30juf5wjgz2cfqku"
+)
+
+func engineForTests() *Engine {
+	engine := NewEngine()
+	engine.AddMarket(MarketConfig{
+		MarketId:            spotMarketId,
+		MinPriceTickSize:    sdk.MustNewDecFromStr("0.01"),
+		MinQuantityTickSize: sdk.MustNewDecFromStr("0.1"),
+		MakerFeeRate:        sdk.MustNewDecFromStr("-0.0001"),
+		TakerFeeRate:        sdk.MustNewDecFromStr("0.001"),
+	})
+	engine.AddMarket(MarketConfig{
+		MarketId:            derivativeMarketId,
+		MinPriceTickSize:    sdk.MustNewDecFromStr("0.01"),
+		MinQuantityTickSize: sdk.MustNewDecFromStr("0.1"),
+		MakerFeeRate:        sdk.ZeroDec(),
+		TakerFeeRate:        sdk.MustNewDecFromStr("0.001"),
+		InitialMarginRatio:  sdk.MustNewDecFromStr("0.05"),
+	})
+	return engine
+}
+
+func spotOrder(subaccountId string, orderType exchangetypes.OrderType, price string, quantity string) exchangetypes.SpotOrder {
+	return exchangetypes.SpotOrder{
+		MarketId: spotMarketId,
+		OrderInfo: exchangetypes.OrderInfo{
+			SubaccountId: subaccountId,
+			FeeRecipient: feeRecipient,
+			Price:        sdk.MustNewDecFromStr(price),
+			Quantity:     sdk.MustNewDecFromStr(quantity),
+		},
+		OrderType:    orderType,
+		TriggerPrice: nil,
+	}
+}
+
+func TestSpotOrdersMatchWithPriceTimePriority(t *testing.T) {
+	engine := engineForTests()
+
+	first, err := engine.PlaceSpotLimitOrder(spotOrder(makerSubaccountId, exchangetypes.OrderType_SELL, "10.5", "1"))
+	assert.NoError(t, err)
+	second, err := engine.PlaceSpotLimitOrder(spotOrder(makerSubaccountId, exchangetypes.OrderType_SELL, "10.5", "1"))
+	assert.NoError(t, err)
+	cheaper, err := engine.PlaceSpotLimitOrder(spotOrder(makerSubaccountId, exchangetypes.OrderType_SELL_PO, "10.2", "0.5"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.OrderHash, second.OrderHash)
+
+	result, err := engine.PlaceSpotLimitOrder(spotOrder(takerSubaccountId, exchangetypes.OrderType_BUY, "10.5", "2"))
+	assert.NoError(t, err)
+	// each trade has a fill for the maker and a fill for the taker
+	assert.Len(t, result.Fills, 6)
+
+	// the best price is filled first, and then the oldest order at the same price
+	assert.Equal(t, cheaper.OrderHash, result.Fills[0].OrderHash)
+	assert.True(t, result.Fills[0].IsMaker)
+	assert.Equal(t, "10.200000000000000000", result.Fills[0].Price.String())
+	assert.Equal(t, "-0.000510000000000000", result.Fills[0].Fee.String())
+	assert.Equal(t, result.OrderHash, result.Fills[1].OrderHash)
+	assert.Equal(t, ExecutionTypeLimitMatchNewOrder, result.Fills[1].ExecutionType)
+	assert.Equal(t, "0.005100000000000000", result.Fills[1].Fee.String())
+	assert.Equal(t, first.OrderHash, result.Fills[2].OrderHash)
+	assert.Equal(t, "1.000000000000000000", result.Fills[2].Quantity.String())
+	assert.Equal(t, second.OrderHash, result.Fills[4].OrderHash)
+	assert.Equal(t, "0.500000000000000000", result.Fills[4].Quantity.String())
+	assert.True(t, result.RestingQuantity.IsZero())
+
+	buys, sells, err := engine.Orderbook(spotMarketId)
+	assert.NoError(t, err)
+	assert.Empty(t, buys)
+	assert.Len(t, sells, 1)
+	assert.Equal(t, second.OrderHash, sells[0].OrderHash)
+	assert.Equal(t, "0.500000000000000000", sells[0].Fillable.String())
+}
+
+func TestSpotOrderValidations(t *testing.T) {
+	engine := engineForTests()
+
+	_, err := engine.PlaceSpotLimitOrder(spotOrder(makerSubaccountId, exchangetypes.OrderType_SELL, "10.555", "1"))
+	assert.True(t, errors.Is(err, exchangetypes.ErrInvalidPrice))
+
+	_, err = engine.PlaceSpotMarketOrder(spotOrder(takerSubaccountId, exchangetypes.OrderType_BUY_ATOMIC, "11", "1"))
+	assert.True(t, errors.Is(err, exchangetypes.ErrNoLiquidity))
+
+	_, err = engine.PlaceSpotLimitOrder(spotOrder(makerSubaccountId, exchangetypes.OrderType_SELL, "10.5", "1"))
+	assert.NoError(t, err)
+
+	_, err = engine.PlaceSpotLimitOrder(spotOrder(takerSubaccountId, exchangetypes.OrderType_BUY_PO, "10.5", "1"))
+	assert.True(t, errors.Is(err, exchangetypes.ErrExceedsTopOfBookPrice))
+
+	_, err = engine.PlaceSpotMarketOrder(spotOrder(takerSubaccountId, exchangetypes.OrderType_BUY, "10", "1"))
+	assert.True(t, errors.Is(err, exchangetypes.ErrSlippageExceedsWorstPrice))
+
+	// the part of a market order not filled up to the worst price is not added to the orderbook
+	result, err := engine.PlaceSpotMarketOrder(spotOrder(takerSubaccountId, exchangetypes.OrderType_BUY, "11", "3"))
+	assert.NoError(t, err)
+	assert.Len(t, result.Fills, 2)
+	assert.Equal(t, ExecutionTypeMarket, result.Fills[1].ExecutionType)
+	buys, sells, err := engine.Orderbook(spotMarketId)
+	assert.NoError(t, err)
+	assert.Empty(t, buys)
+	assert.Empty(t, sells)
+}
+
+func TestCancelOrder(t *testing.T) {
+	engine := engineForTests()
+	result, err := engine.PlaceSpotLimitOrder(spotOrder(makerSubaccountId, exchangetypes.OrderType_BUY, "9", "1"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, engine.CancelOrder(spotMarketId, result.OrderHash))
+	assert.True(t, errors.Is(engine.CancelOrder(spotMarketId, result.OrderHash), exchangetypes.ErrOrderDoesntExist))
+}
+
+func TestDerivativeOrdersMarginChecks(t *testing.T) {
+	engine := engineForTests()
+	markPrice := sdk.MustNewDecFromStr("100")
+
+	order := exchangetypes.DerivativeOrder{
+		MarketId: derivativeMarketId,
+		OrderInfo: exchangetypes.OrderInfo{
+			SubaccountId: makerSubaccountId,
+			FeeRecipient: feeRecipient,
+			Price:        sdk.MustNewDecFromStr("100"),
+			Quantity:     sdk.MustNewDecFromStr("2"),
+		},
+		OrderType: exchangetypes.OrderType_SELL,
+		Margin:    sdk.MustNewDecFromStr("5"),
+	}
+	// the minimum margin is 100 * 2 * 0.05 = 10
+	_, err := engine.PlaceDerivativeLimitOrder(order, markPrice)
+	assert.True(t, errors.Is(err, exchangetypes.ErrInsufficientOrderMargin))
+
+	order.Margin = sdk.MustNewDecFromStr("20")
+	maker, err := engine.PlaceDerivativeLimitOrder(order, markPrice)
+	assert.NoError(t, err)
+
+	takerOrder := order
+	takerOrder.OrderInfo.SubaccountId = takerSubaccountId
+	takerOrder.OrderInfo.Quantity = sdk.MustNewDecFromStr("0.5")
+	takerOrder.OrderInfo.Price = sdk.MustNewDecFromStr("101")
+	takerOrder.OrderType = exchangetypes.OrderType_BUY
+	takerOrder.Margin = sdk.MustNewDecFromStr("10")
+	result, err := engine.PlaceDerivativeMarketOrder(takerOrder, markPrice)
+	assert.NoError(t, err)
+	assert.Len(t, result.Fills, 2)
+	assert.Equal(t, maker.OrderHash, result.Fills[0].OrderHash)
+	assert.Equal(t, "5.000000000000000000", result.Fills[0].Margin.String())
+	assert.Equal(t, "10.000000000000000000", result.Fills[1].Margin.String())
+
+	trade := result.Fills[1].DerivativeTrade(1700000000000)
+	assert.Equal(t, "buy", trade.PositionDelta.TradeDirection)
+	assert.Equal(t, "taker", trade.ExecutionSide)
+	assert.Equal(t, "100.000000000000000000", trade.PositionDelta.ExecutionPrice)
+
+	takerOrder.Margin = sdk.ZeroDec()
+	_, err = engine.PlaceDerivativeMarketOrder(takerOrder, markPrice)
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package matching
+
+import (
+	"strings"
+
+	derivativeExchangePB "github.com/InjectiveLabs/sdk-go/exchange/derivative_exchange_rpc/pb"
+	spotExchangePB "github.com/InjectiveLabs/sdk-go/exchange/spot_exchange_rpc/pb"
+)
+
+func (f Fill) direction() string {
+	if f.IsBuy {
+		return "buy"
+	}
+	return "sell"
+}
+
+func (f Fill) executionSide() string {
+	if f.IsMaker {
+		return "maker"
+	}
+	return "taker"
+}
+
+// tradeId follows the exchange API format, where both sides of a trade have a different id
+func (f Fill) tradeId() string {
+	return strings.Join([]string{f.TradeId, f.OrderHash}, "_")
+}
+
+// SpotTrade returns the fill as reported by the exchange API, to configure the responses of
+// exchange.MockExchangeClient. executedAt is the timestamp in milliseconds
+func (f Fill) SpotTrade(executedAt int64) *spotExchangePB.SpotTrade {
+	return &spotExchangePB.SpotTrade{
+		OrderHash:          f.OrderHash,
+		SubaccountId:       f.SubaccountId,
+		MarketId:           f.MarketId,
+		TradeExecutionType: f.ExecutionType,
+		TradeDirection:     f.direction(),
+		Price: &spotExchangePB.PriceLevel{
+			Price:     f.Price.String(),
+			Quantity:  f.Quantity.String(),
+			Timestamp: executedAt,
+		},
+		Fee:           f.Fee.String(),
+		ExecutedAt:    executedAt,
+		TradeId:       f.tradeId(),
+		ExecutionSide: f.executionSide(),
+		Cid:           f.Cid,
+	}
+}
+
+// DerivativeTrade returns the fill as reported by the exchange API, to configure the responses of
+// exchange.MockExchangeClient. executedAt is the timestamp in milliseconds
+func (f Fill) DerivativeTrade(executedAt int64) *derivativeExchangePB.DerivativeTrade {
+	return &derivativeExchangePB.DerivativeTrade{
+		OrderHash:          f.OrderHash,
+		SubaccountId:       f.SubaccountId,
+		MarketId:           f.MarketId,
+		TradeExecutionType: f.ExecutionType,
+		PositionDelta: &derivativeExchangePB.PositionDelta{
+			TradeDirection:    f.direction(),
+			ExecutionPrice:    f.Price.String(),
+			ExecutionQuantity: f.Quantity.String(),
+			ExecutionMargin:   f.Margin.String(),
+		},
+		Fee:           f.Fee.String(),
+		ExecutedAt:    executedAt,
+		TradeId:       f.tradeId(),
+		ExecutionSide: f.executionSide(),
+		Cid:           f.Cid,
+	}
+}